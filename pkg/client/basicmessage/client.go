@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/service/basic"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	// namespace of the basic message protocol message types
+	namespace = "https://didcomm.org/basicmessage/1.0/"
+
+	defaultLocale      = "en"
+	stateNameCompleted = "completed"
+)
+
+var (
+	// ErrConnectionNotFound is returned when connection not found
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrConnectionNotCompleted is returned when connection is not completed yet
+	ErrConnectionNotCompleted = errors.New("connection not completed")
+)
+
+// Provider contains dependencies for the basic message client and is typically created by using aries.Context()
+type Provider interface {
+	Messenger() service.Messenger
+	MessageHandlerRegistry() *service.Registry
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+}
+
+// Client enable access to basic message api
+type Client struct {
+	messenger        service.Messenger
+	registry         *service.Registry
+	connectionLookup *connection.Lookup
+	clock            clock.Clock
+	handlers         []func(BasicMessage)
+	lock             sync.RWMutex
+}
+
+// New return new instance of basic message client.
+// The client handles inbound basic messages until it is closed.
+func New(ctx Provider) (*Client, error) {
+	registry := ctx.MessageHandlerRegistry()
+	if registry == nil {
+		return nil, errors.New("message handler registry is not configured")
+	}
+
+	connectionLookup, err := connection.NewLookup(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create connection lookup: %w", err)
+	}
+
+	c := &Client{
+		messenger:        ctx.Messenger(),
+		registry:         registry,
+		connectionLookup: connectionLookup,
+		clock:            clock.Real(),
+	}
+
+	if err = registry.Register(namespace, c); err != nil {
+		return nil, fmt.Errorf("register basic message handler: %w", err)
+	}
+
+	return c, nil
+}
+
+// Send sends the free-text message to the other party of the given connection.
+func (c *Client) Send(connectionID, text string) error {
+	conn, err := c.connectionLookup.GetConnectionRecord(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return ErrConnectionNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	if conn.State != stateNameCompleted {
+		return ErrConnectionNotCompleted
+	}
+
+	msg := basic.Message{
+		Type:     basic.MessageRequestType,
+		SentTime: c.clock.Now().UTC(),
+		Content:  text,
+	}
+	msg.I10n.Locale = defaultLocale
+
+	if err = c.messenger.Send(service.NewDIDCommMsgMap(msg), conn.MyDID, conn.TheirDID); err != nil {
+		return fmt.Errorf("send basic message: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterMessageHandler registers the handler for inbound basic messages.
+func (c *Client) RegisterMessageHandler(handler func(BasicMessage)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.handlers = append(c.handlers, handler)
+}
+
+// HandleInbound delivers the inbound basic message to the registered handlers.
+func (c *Client) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	basicMsg := basic.Message{}
+
+	if err := msg.Decode(&basicMsg); err != nil {
+		return "", fmt.Errorf("decode basic message: %w", err)
+	}
+
+	c.lock.RLock()
+	handlers := make([]func(BasicMessage), len(c.handlers))
+	copy(handlers, c.handlers)
+	c.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(BasicMessage{
+			ID:       basicMsg.ID,
+			Content:  basicMsg.Content,
+			Locale:   basicMsg.I10n.Locale,
+			SentTime: basicMsg.SentTime,
+			MyDID:    myDID,
+			TheirDID: theirDID,
+		})
+	}
+
+	return "", nil
+}
+
+// Close stops handling inbound basic messages.
+func (c *Client) Close() error {
+	if err := c.registry.Unregister(namespace); err != nil {
+		return fmt.Errorf("unregister basic message handler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/service/basic"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	myDID    = "did:example:myDID"
+	theirDID = "did:example:theirDID"
+)
+
+func newProvider(messenger service.Messenger) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		StorageProviderValue:          mockstore.NewMockStoreProvider(),
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		MessengerValue:                messenger,
+		MessageHandlerRegistryValue:   service.NewRegistry(),
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		prov := newProvider(nil)
+
+		client, err := New(prov)
+		require.NoError(t, err)
+		require.NotNil(t, client)
+
+		h, ok := prov.MessageHandlerRegistryValue.Handler(basic.MessageRequestType)
+		require.True(t, ok)
+		require.Equal(t, client, h)
+
+		// only one client can handle inbound basic messages
+		_, err = New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "register basic message handler")
+
+		require.NoError(t, client.Close())
+
+		_, ok = prov.MessageHandlerRegistryValue.Handler(basic.MessageRequestType)
+		require.False(t, ok)
+
+		err = client.Close()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unregister basic message handler")
+
+		_, err = New(prov)
+		require.NoError(t, err)
+	})
+
+	t.Run("test no message handler registry", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.MessageHandlerRegistryValue = nil
+
+		_, err := New(prov)
+		require.EqualError(t, err, "message handler registry is not configured")
+	})
+
+	t.Run("test connection lookup error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.StorageProviderValue = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create connection lookup")
+	})
+}
+
+func TestClient_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2020, 4, 10, 12, 30, 0, 0, time.UTC)
+
+	saveConnection := func(t *testing.T, prov *mockprovider.Provider, state string) {
+		recorder, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "conn1",
+			State:        state,
+			MyDID:        myDID,
+			TheirDID:     theirDID,
+		}))
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), myDID, theirDID).
+			Do(func(msg service.DIDCommMsgMap, _, _ string) {
+				require.Equal(t, basic.MessageRequestType, msg.Type())
+
+				sent := basic.Message{}
+				require.NoError(t, msg.Decode(&sent))
+				require.Equal(t, "Your hovercraft is full of eels.", sent.Content)
+				require.Equal(t, "en", sent.I10n.Locale)
+				require.Equal(t, now, sent.SentTime)
+			}).Return(nil)
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov, "completed")
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		client.clock = clock.Fixed(now)
+
+		require.NoError(t, client.Send("conn1", "Your hovercraft is full of eels."))
+	})
+
+	t.Run("test messenger error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), myDID, theirDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov, "completed")
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		err = client.Send("conn1", "hello")
+		require.EqualError(t, err, "send basic message: send error")
+	})
+
+	t.Run("test connection not found", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		err = client.Send("conn1", "hello")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test connection not completed", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov, "requested")
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		err = client.Send("conn1", "hello")
+		require.True(t, errors.Is(err, ErrConnectionNotCompleted))
+	})
+}
+
+func TestClient_HandleInbound(t *testing.T) {
+	sentTime := time.Date(2020, 4, 10, 12, 30, 0, 0, time.UTC)
+
+	t.Run("test message is delivered to registered handlers", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		var received []BasicMessage
+
+		client.RegisterMessageHandler(func(msg BasicMessage) { received = append(received, msg) })
+		client.RegisterMessageHandler(func(msg BasicMessage) { received = append(received, msg) })
+
+		msg := basic.Message{
+			ID:       "msg1",
+			Type:     basic.MessageRequestType,
+			SentTime: sentTime,
+			Content:  "Your hovercraft is full of eels.",
+		}
+		msg.I10n.Locale = "en"
+
+		_, err = client.HandleInbound(service.NewDIDCommMsgMap(msg), myDID, theirDID)
+		require.NoError(t, err)
+
+		expected := BasicMessage{
+			ID:       "msg1",
+			Content:  "Your hovercraft is full of eels.",
+			Locale:   "en",
+			SentTime: sentTime,
+			MyDID:    myDID,
+			TheirDID: theirDID,
+		}
+		require.Equal(t, []BasicMessage{expected, expected}, received)
+	})
+
+	t.Run("test decode error", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = client.HandleInbound(service.DIDCommMsgMap{"sent_time": "invalid"}, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode basic message")
+	})
+}
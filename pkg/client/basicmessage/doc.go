@@ -0,0 +1,20 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package basicmessage provides the basic message protocol (chat) over an established connection.
+// Free-text messages are sent to the other party of the connection with Send,
+// inbound messages are delivered to the handlers registered with RegisterMessageHandler:
+// 	client, err := basicmessage.New(ctx)
+// 	client.RegisterMessageHandler(func(msg basicmessage.BasicMessage) {
+// 	  fmt.Println(msg.Content)
+// 	})
+// 	err = client.Send(connectionID, "Your hovercraft is full of eels.")
+//
+// RFC Reference:
+//
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0095-basic-message
+//
+package basicmessage
@@ -0,0 +1,25 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package basicmessage
+
+import "time"
+
+// BasicMessage is an inbound basic message
+type BasicMessage struct {
+	// ID is the message ID
+	ID string
+	// Content is the message text
+	Content string
+	// Locale is the locale of the message content
+	Locale string
+	// SentTime is the time the message was sent by the other party
+	SentTime time.Time
+	// MyDID is the receiving agent's DID
+	MyDID string
+	// TheirDID is the sender agent's DID
+	TheirDID string
+}
@@ -10,10 +10,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
@@ -26,10 +29,23 @@ const (
 	InvitationMsgType = didexchange.InvitationMsgType
 	// RequestMsgType defines the did-exchange request message type.
 	RequestMsgType = didexchange.RequestMsgType
+	// OOBInvitationMsgType defines the out-of-band invitation message type.
+	OOBInvitationMsgType = didexchange.OOBInvitationMsgType
+
+	// handshakeProtocol is the handshake protocol of out-of-band invitations handled by this client.
+	handshakeProtocol = "https://didcomm.org/didexchange/1.0"
+	// oobServiceType is the type of out-of-band invitation inline service block.
+	oobServiceType = "did-communication"
+	// stateCompleted is the state of the established connection.
+	stateCompleted = "completed"
 )
 
-// ErrConnectionNotFound is returned when connection not found
-var ErrConnectionNotFound = errors.New("connection not found")
+var (
+	// ErrConnectionNotFound is returned when connection not found
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrInvitationExpired is returned when the invitation to be handled has expired
+	ErrInvitationExpired = didexchange.ErrInvitationExpired
+)
 
 // provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context()
 type provider interface {
@@ -48,6 +64,7 @@ type Client struct {
 	legacyKMS       legacykms.KeyManager
 	serviceEndpoint string
 	connectionStore *connection.Recorder
+	clock           clock.Clock
 }
 
 // protocolService defines DID Exchange service.
@@ -64,10 +81,30 @@ type protocolService interface {
 	// CreateImplicitInvitation creates implicit invitation. Inviter DID is required, invitee DID is optional.
 	// If invitee DID is not provided new peer DID will be created for implicit invitation exchange request.
 	CreateImplicitInvitation(inviterLabel, inviterDID, inviteeLabel, inviteeDID string) (string, error)
+
+	// RotateDID rotates my DID of the completed connection and notifies the other party
+	RotateDID(connectionID, newDID string) error
+
+	// ReuseConnection notifies the inviter of out-of-band invitation that the existing connection is reused
+	ReuseConnection(connectionID, invitationID string) error
+
+	// HandleInvitation handles the received invitation, the connection is assigned the given metadata
+	HandleInvitation(msg service.DIDCommMsg, metadata map[string]interface{}) (string, error)
+}
+
+// Opt is the didexchange client option
+type Opt func(c *Client)
+
+// WithClock sets the clock of the client which is used to set and check the expiry of the invitations.
+// The real-time clock is used by default.
+func WithClock(c clock.Clock) Opt {
+	return func(client *Client) {
+		client.clock = c
+	}
 }
 
 // New return new instance of didexchange client
-func New(ctx provider) (*Client, error) {
+func New(ctx provider, opts ...Opt) (*Client, error) {
 	svc, err := ctx.Service(didexchange.DIDExchange)
 	if err != nil {
 		return nil, err
@@ -93,20 +130,33 @@ func New(ctx provider) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{
+	c := &Client{
 		Event:           didexchangeSvc,
 		didexchangeSvc:  didexchangeSvc,
 		routeSvc:        routeSvc,
 		legacyKMS:       ctx.LegacyKMS(),
 		serviceEndpoint: ctx.ServiceEndpoint(),
 		connectionStore: connectionStore,
-	}, nil
+		clock:           clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 // CreateInvitation creates an invitation. New key pair will be generated and base58 encoded public key will be
 // used as basis for invitation. This invitation will be stored so client can cross reference this invitation during
-// did exchange protocol
-func (c *Client) CreateInvitation(label string) (*Invitation, error) {
+// did exchange protocol. The invitation does not expire unless WithInvitationExpiry option is passed.
+func (c *Client) CreateInvitation(label string, opts ...InvitationOption) (*Invitation, error) {
+	invOpts := &invitationOpts{}
+
+	for _, opt := range opts {
+		opt(invOpts)
+	}
+
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 	//  with connection record
 	_, sigPubKey, err := c.legacyKMS.CreateKeySet()
@@ -115,7 +165,7 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 	}
 
 	// get the route configs
-	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, c.serviceEndpoint)
+	serviceEndpoint, routingKeys, err := c.invitationRouterConfig(invOpts)
 	if err != nil {
 		return nil, fmt.Errorf("create invitation - fetch router config : %w", err)
 	}
@@ -129,6 +179,10 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 		RoutingKeys:     routingKeys,
 	}
 
+	if invOpts.expiry > 0 {
+		invitation.Timing = &decorator.Timing{ExpiresTime: c.clock.Now().Add(invOpts.expiry).UTC()}
+	}
+
 	if err = route.AddKeyToRouter(c.routeSvc, sigPubKey); err != nil {
 		return nil, fmt.Errorf("create invitation - add key to the router : %w", err)
 	}
@@ -138,9 +192,27 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 		return nil, fmt.Errorf("failed to save invitation: %w", err)
 	}
 
+	if err = c.saveInvitationMetadata(invitation.ID, invOpts); err != nil {
+		return nil, err
+	}
+
 	return &Invitation{invitation}, nil
 }
 
+// invitationRouterConfig returns the endpoint and routing keys of the invitation router
+func (c *Client) invitationRouterConfig(invOpts *invitationOpts) (string, []string, error) {
+	if invOpts.routerConnectionID == "" {
+		return route.GetRouterConfig(c.routeSvc, c.serviceEndpoint)
+	}
+
+	routeConf, err := c.routeSvc.ConnectionConfig(invOpts.routerConnectionID)
+	if err != nil {
+		return "", nil, fmt.Errorf("router of connection %s: %w", invOpts.routerConnectionID, err)
+	}
+
+	return routeConf.Endpoint(), routeConf.Keys(), nil
+}
+
 // CreateInvitationWithDID creates an invitation with specified public DID. This invitation will be stored
 // so client can cross reference this invitation during did exchange protocol
 func (c *Client) CreateInvitationWithDID(label, did string) (*Invitation, error) {
@@ -159,10 +231,226 @@ func (c *Client) CreateInvitationWithDID(label, did string) (*Invitation, error)
 	return &Invitation{invitation}, nil
 }
 
+// CreateOOBInvitation creates an out-of-band invitation (RFC 0434). New key pair will be generated and
+// base58 encoded public key will be used in the inline service block of the invitation. The equivalent
+// DID Exchange invitation is stored under the same ID, so the exchange request which follows the out-of-band
+// invitation is handled as for the DID Exchange invitation.
+func (c *Client) CreateOOBInvitation(opts ...OOBOption) (*OOBInvitation, error) {
+	oobOpts := &oobOpts{}
+
+	for _, opt := range opts {
+		opt(oobOpts)
+	}
+
+	_, sigPubKey, err := c.legacyKMS.CreateKeySet()
+	if err != nil {
+		return nil, fmt.Errorf("failed CreateSigningKey: %w", err)
+	}
+
+	// get the route configs
+	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, c.serviceEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("create out-of-band invitation - fetch router config : %w", err)
+	}
+
+	inlineService := &didexchange.OOBService{
+		ID:              "#inline",
+		Type:            oobServiceType,
+		RecipientKeys:   []string{sigPubKey},
+		RoutingKeys:     routingKeys,
+		ServiceEndpoint: serviceEndpoint,
+	}
+
+	invitation := &didexchange.OOBInvitation{
+		ID:                 uuid.New().String(),
+		Type:               didexchange.OOBInvitationMsgType,
+		Label:              oobOpts.label,
+		Goal:               oobOpts.goal,
+		GoalCode:           oobOpts.goalCode,
+		HandshakeProtocols: []string{handshakeProtocol},
+		Requests:           oobOpts.requests,
+		Service:            append([]interface{}{inlineService}, oobOpts.services...),
+	}
+
+	if err = route.AddKeyToRouter(c.routeSvc, sigPubKey); err != nil {
+		return nil, fmt.Errorf("create out-of-band invitation - add key to the router : %w", err)
+	}
+
+	err = c.connectionStore.SaveInvitation(invitation.ID, &didexchange.Invitation{
+		ID:              invitation.ID,
+		Label:           invitation.Label,
+		RecipientKeys:   inlineService.RecipientKeys,
+		ServiceEndpoint: inlineService.ServiceEndpoint,
+		RoutingKeys:     inlineService.RoutingKeys,
+		Type:            didexchange.InvitationMsgType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save out-of-band invitation: %w", err)
+	}
+
+	return &OOBInvitation{invitation}, nil
+}
+
+// HandleOOBInvitation handles incoming out-of-band invitation (RFC 0434) and returns the connectionID that can be
+// used to query the state of did exchange protocol. The first supported service of the invitation is used to
+// connect to the inviter.
+// If there is a completed connection with the inviter (i.e. with the public DID or the recipient keys of
+// the invitation services), the connection is reused: the inviter is notified by the handshake reuse message
+// and the connectionID of the existing connection is returned instead of starting a new exchange.
+func (c *Client) HandleOOBInvitation(invitation *OOBInvitation) (string, error) {
+	if invitation == nil || invitation.OOBInvitation == nil {
+		return "", errors.New("out-of-band invitation is not defined")
+	}
+
+	didexInvitation, err := toDIDExchangeInvitation(invitation.OOBInvitation)
+	if err != nil {
+		return "", fmt.Errorf("handle out-of-band invitation: %w", err)
+	}
+
+	connectionID, err := c.reusableConnection(invitation.OOBInvitation)
+	if err != nil {
+		return "", fmt.Errorf("handle out-of-band invitation: %w", err)
+	}
+
+	if connectionID != "" {
+		if err = c.didexchangeSvc.ReuseConnection(connectionID, invitation.ID); err != nil {
+			return "", fmt.Errorf("handle out-of-band invitation: %w", err)
+		}
+
+		return connectionID, nil
+	}
+
+	return c.HandleInvitation(&Invitation{didexInvitation})
+}
+
+// reusableConnection returns the ID of the completed connection with the inviter of out-of-band invitation,
+// i.e. the connection with the public DID of the invitation or the one created with the recipient keys
+// of its inline service block. Empty ID is returned if there is no such connection.
+func (c *Client) reusableConnection(oobInvitation *didexchange.OOBInvitation) (string, error) {
+	records, err := c.connectionStore.QueryConnectionRecords()
+	if err != nil {
+		return "", fmt.Errorf("query connections to reuse: %w", err)
+	}
+
+	for _, svc := range oobInvitation.Service {
+		for _, record := range records {
+			if record.State != stateCompleted {
+				continue
+			}
+
+			if matchOOBService(svc, record) {
+				return record.ConnectionID, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// matchOOBService checks whether the connection was established with the service of out-of-band invitation.
+func matchOOBService(svc interface{}, record *connection.Record) bool {
+	if did, ok := svc.(string); ok {
+		return did != "" && (did == record.TheirDID || did == record.InvitationDID)
+	}
+
+	inlineService, err := toOOBService(svc)
+	if err != nil {
+		return false
+	}
+
+	for _, key := range inlineService.RecipientKeys {
+		if contains(record.RecipientKeys, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toDIDExchangeInvitation converts out-of-band invitation to DID Exchange invitation with the same ID.
+func toDIDExchangeInvitation(oobInvitation *didexchange.OOBInvitation) (*didexchange.Invitation, error) {
+	if oobInvitation.Type != didexchange.OOBInvitationMsgType {
+		return nil, fmt.Errorf("unsupported invitation type: %s", oobInvitation.Type)
+	}
+
+	if len(oobInvitation.HandshakeProtocols) > 0 && !contains(oobInvitation.HandshakeProtocols, handshakeProtocol) {
+		return nil, fmt.Errorf("unsupported handshake protocols: %v", oobInvitation.HandshakeProtocols)
+	}
+
+	for _, svc := range oobInvitation.Service {
+		if did, ok := svc.(string); ok {
+			if did == "" {
+				continue
+			}
+
+			return &didexchange.Invitation{
+				ID:    oobInvitation.ID,
+				Label: oobInvitation.Label,
+				DID:   did,
+				Type:  didexchange.InvitationMsgType,
+			}, nil
+		}
+
+		inlineService, err := toOOBService(svc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(inlineService.RecipientKeys) == 0 || inlineService.ServiceEndpoint == "" {
+			continue
+		}
+
+		return &didexchange.Invitation{
+			ID:              oobInvitation.ID,
+			Label:           oobInvitation.Label,
+			RecipientKeys:   inlineService.RecipientKeys,
+			ServiceEndpoint: inlineService.ServiceEndpoint,
+			RoutingKeys:     inlineService.RoutingKeys,
+			Type:            didexchange.InvitationMsgType,
+		}, nil
+	}
+
+	return nil, errors.New("no supported service found in out-of-band invitation")
+}
+
+func toOOBService(svc interface{}) (*didexchange.OOBService, error) {
+	bytes, err := json.Marshal(svc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal out-of-band service: %w", err)
+	}
+
+	inlineService := &didexchange.OOBService{}
+
+	err = json.Unmarshal(bytes, inlineService)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal out-of-band service: %w", err)
+	}
+
+	return inlineService, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HandleInvitation handle incoming invitation and returns the connectionID that can be used to query the state
 // of did exchange protocol. Upon successful completion of did exchange protocol connection details will be used
 // for securing communication between agents.
-func (c *Client) HandleInvitation(invitation *Invitation) (string, error) {
+// ErrInvitationExpired is returned if the invitation has expired (see WithInvitationExpiry).
+// Only WithConnectionMetadata option is applicable, the other options are ignored.
+func (c *Client) HandleInvitation(invitation *Invitation, opts ...InvitationOption) (string, error) {
+	invOpts := &invitationOpts{}
+
+	for _, opt := range opts {
+		opt(invOpts)
+	}
+
 	payload, err := json.Marshal(invitation)
 	if err != nil {
 		return "", fmt.Errorf("failed marshal invitation: %w", err)
@@ -173,7 +461,11 @@ func (c *Client) HandleInvitation(invitation *Invitation) (string, error) {
 		return "", fmt.Errorf("failed to create DIDCommMsg: %w", err)
 	}
 
-	connectionID, err := c.didexchangeSvc.HandleInbound(msg, "", "")
+	if msg.Expired(c.clock.Now()) {
+		return "", fmt.Errorf("handle invitation %s: %w", msg.ID(), ErrInvitationExpired)
+	}
+
+	connectionID, err := c.didexchangeSvc.HandleInvitation(msg, invOpts.metadata)
 	if err != nil {
 		return "", fmt.Errorf("failed from didexchange service handle: %w", err)
 	}
@@ -181,6 +473,20 @@ func (c *Client) HandleInvitation(invitation *Invitation) (string, error) {
 	return connectionID, nil
 }
 
+// saveInvitationMetadata saves the metadata to be assigned to the connections created for the invitation
+// (if any) when the invitee requests the connection.
+func (c *Client) saveInvitationMetadata(invitationID string, invOpts *invitationOpts) error {
+	if len(invOpts.metadata) == 0 {
+		return nil
+	}
+
+	if err := c.connectionStore.SaveInvitationMetadata(invitationID, invOpts.metadata); err != nil {
+		return fmt.Errorf("failed to save invitation metadata: %w", err)
+	}
+
+	return nil
+}
+
 // TODO https://github.com/hyperledger/aries-framework-go/issues/754 - e.Continue v Explicit API call for action events
 
 // AcceptInvitation accepts/approves exchange invitation. This call is not used if auto execute is setup
@@ -217,6 +523,53 @@ func (c *Client) CreateImplicitInvitationWithDID(inviter, invitee *DIDInfo) (str
 	return c.didexchangeSvc.CreateImplicitInvitation(inviter.Label, inviter.DID, invitee.Label, invitee.DID)
 }
 
+// CreateConnectionWithDID creates a connection with the party identified by its resolvable public DID
+// without exchanging an invitation (implicit invitation). The exchange request is sent to the DID service
+// endpoint and the connection proceeds through the regular did exchange states.
+// returns:
+// 		string: connectionID that can be used to query the state of did exchange protocol
+//		error: in case of errors
+func (c *Client) CreateConnectionWithDID(theirPublicDID string, opts ...Option) (string, error) {
+	if theirPublicDID == "" {
+		return "", errors.New("create connection with DID: their public DID is not defined")
+	}
+
+	connOpts := &connectionOpts{}
+
+	for _, opt := range opts {
+		opt(connOpts)
+	}
+
+	connectionID, err := c.didexchangeSvc.CreateImplicitInvitation(connOpts.theirLabel, theirPublicDID,
+		connOpts.myLabel, connOpts.myPublicDID)
+	if err != nil {
+		return "", fmt.Errorf("create connection with DID: %w", err)
+	}
+
+	return connectionID, nil
+}
+
+// RotateDID rotates my DID of the completed connection to the new one (DID rotation, RFC 0794).
+// The other party is notified with the rotate message sent on the connection thread, then MyDID
+// of the connection record is updated. The new DID must be resolvable.
+// When the other party rotates its DID, TheirDID of the connection record is updated on receipt
+// of its rotate message. Replies sent through the messenger use the rotated DIDs.
+func (c *Client) RotateDID(connectionID, newDID string) error {
+	if newDID == "" {
+		return errors.New("did exchange client - rotate DID: new DID is not defined")
+	}
+
+	if _, err := c.GetConnection(connectionID); err != nil {
+		return fmt.Errorf("did exchange client - rotate DID: %w", err)
+	}
+
+	if err := c.didexchangeSvc.RotateDID(connectionID, newDID); err != nil {
+		return fmt.Errorf("did exchange client - rotate DID: %w", err)
+	}
+
+	return nil
+}
+
 // QueryConnections queries connections matching given criteria(parameters)
 func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connection, error) {
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 - query all connections from all criteria and
@@ -226,6 +579,11 @@ func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connectio
 		return nil, fmt.Errorf("failed query connections: %w", err)
 	}
 
+	metadata, err := normalizeMetadata(request.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed query connections: %w", err)
+	}
+
 	var result []*Connection
 
 	for _, record := range records {
@@ -233,12 +591,48 @@ func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connectio
 			continue
 		}
 
+		if !matchMetadata(record.Metadata, metadata) {
+			continue
+		}
+
 		result = append(result, &Connection{Record: record})
 	}
 
 	return result, nil
 }
 
+// normalizeMetadata converts the metadata filter to the form the metadata is read from the store in
+// (e.g. the numbers are float64), so it can be compared with the metadata of the connection records.
+func normalizeMetadata(metadata map[string]interface{}) (map[string]interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata filter: %w", err)
+	}
+
+	var normalized map[string]interface{}
+
+	if err = json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("unmarshal metadata filter: %w", err)
+	}
+
+	return normalized, nil
+}
+
+// matchMetadata checks whether the metadata of the connection contains all the entries of the filter
+func matchMetadata(metadata, filter map[string]interface{}) bool {
+	for k, v := range filter {
+		if !reflect.DeepEqual(metadata[k], v) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetConnection fetches single connection record for given id
 func (c *Client) GetConnection(connectionID string) (*Connection, error) {
 	conn, err := c.connectionStore.GetConnectionRecord(connectionID)
@@ -255,6 +649,73 @@ func (c *Client) GetConnection(connectionID string) (*Connection, error) {
 	}, nil
 }
 
+// ConnectionRecord fetches the complete connection record for given id, e.g. to diagnose mismatched keys
+// and endpoints. The record is a copy, changes are not persisted.
+func (c *Client) ConnectionRecord(connectionID string) (*ConnectionRecord, error) {
+	conn, err := c.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("cannot fetch connection record: connectionid=%s err=%w", connectionID, err)
+	}
+
+	record := &ConnectionRecord{Record: *conn}
+	record.RecipientKeys = append([]string(nil), conn.RecipientKeys...)
+	record.RoutingKeys = append([]string(nil), conn.RoutingKeys...)
+
+	if conn.Namespace != "" && conn.ThreadID != "" {
+		record.NSThreadID, err = connection.CreateNamespaceKey(conn.Namespace, conn.ThreadID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute namespaced thread ID: connectionid=%s err=%w", connectionID, err)
+		}
+	}
+
+	return record, nil
+}
+
+// RequireEncryption sets the encryption policy of the connection, the messages of the connection which requires
+// encryption are not sent unencrypted by the messenger (see messenger.WithEncryptionPolicy).
+func (c *Client) RequireEncryption(connectionID string, require bool) error {
+	record, err := c.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return ErrConnectionNotFound
+		}
+
+		return fmt.Errorf("cannot fetch connection record: connectionid=%s err=%w", connectionID, err)
+	}
+
+	record.RequireEncryption = require
+
+	if err = c.connectionStore.SaveConnectionRecord(record); err != nil {
+		return fmt.Errorf("cannot save connection record: connectionid=%s err=%w", connectionID, err)
+	}
+
+	return nil
+}
+
+// ConnectionHistory returns the state transitions of the connection in chronological order.
+// The history is persisted only if DID exchange service is created with didexchange.WithConnectionHistory option.
+func (c *Client) ConnectionHistory(connectionID string) ([]StateTransition, error) {
+	history, err := c.connectionStore.GetConnectionHistory(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("cannot fetch connection history: connectionid=%s err=%w", connectionID, err)
+	}
+
+	transitions := make([]StateTransition, len(history))
+	for i := range history {
+		transitions[i] = StateTransition{&history[i]}
+	}
+
+	return transitions, nil
+}
+
 // GetConnectionAtState fetches connection record for connection id at particular state.
 func (c *Client) GetConnectionAtState(connectionID, stateID string) (*Connection, error) {
 	conn, err := c.connectionStore.GetConnectionRecordAtState(connectionID, stateID)
@@ -18,6 +18,7 @@ import (
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
@@ -29,6 +30,7 @@ import (
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -151,6 +153,33 @@ func TestClient_CreateInvitation(t *testing.T) {
 		require.NotEmpty(t, inviteReq.ID)
 		require.Nil(t, inviteReq.RoutingKeys)
 		require.Equal(t, "endpoint", inviteReq.ServiceEndpoint)
+		require.Nil(t, inviteReq.Timing)
+	})
+
+	t.Run("test success with expiry", func(t *testing.T) {
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"}, WithClock(clock.Fixed(now)))
+		require.NoError(t, err)
+
+		inviteReq, err := c.CreateInvitation("agent", WithInvitationExpiry(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, inviteReq.Timing)
+		require.Equal(t, now.Add(time.Hour), inviteReq.Timing.ExpiresTime)
+
+		// the stored invitation keeps the expiry
+		var stored didexchange.Invitation
+		require.NoError(t, c.connectionStore.GetInvitation(inviteReq.ID, &stored))
+		require.NotNil(t, stored.Timing)
+		require.True(t, inviteReq.Timing.ExpiresTime.Equal(stored.Timing.ExpiresTime))
 	})
 
 	t.Run("test error from createSigningKey", func(t *testing.T) {
@@ -238,6 +267,38 @@ func TestClient_CreateInvitation(t *testing.T) {
 		require.Equal(t, routingKeys, inviteReq.RoutingKeys)
 	})
 
+	t.Run("test success with routing through mediator", func(t *testing.T) {
+		endpoint := "http://router.example.com"
+		routingKeys := []string{"abc", "xyz"}
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination: &mockroute.MockRouteSvc{
+					RoutingKeys:        routingKeys,
+					RouterEndpoint:     endpoint,
+					RouterConnectionID: "mediator-conn",
+				},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint",
+		})
+		require.NoError(t, err)
+
+		inviteReq, err := c.CreateInvitation("agent", WithRouting("mediator-conn"))
+		require.NoError(t, err)
+		require.Equal(t, endpoint, inviteReq.ServiceEndpoint)
+		require.Equal(t, routingKeys, inviteReq.RoutingKeys)
+
+		inviteReq, err = c.CreateInvitation("agent", WithRouting("other-conn"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "router of connection other-conn")
+		require.True(t, errors.Is(err, route.ErrRouterNotRegistered))
+		require.Nil(t, inviteReq)
+	})
+
 	t.Run("test create invitation with router config error", func(t *testing.T) {
 		svc, err := didexchange.New(&mockprotocol.MockProvider{
 			ServiceMap: map[string]interface{}{
@@ -492,6 +553,183 @@ func TestClient_GetConnection(t *testing.T) {
 	})
 }
 
+func TestClient_ConnectionHistory(t *testing.T) {
+	svc, err := didexchange.New(&mockprotocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	storageProvider := mockstore.NewMockStoreProvider()
+
+	c, err := New(&mockprovider.Provider{
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		StorageProviderValue:          storageProvider,
+		ServiceMap: map[string]interface{}{
+			didexchange.DIDExchange: svc,
+			route.Coordination:      &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		transition := connection.StateTransition{FromState: "invited", ToState: "requested",
+			MsgType: RequestMsgType, Time: time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)}
+		require.NoError(t, c.connectionStore.SaveStateTransition("id1", transition))
+
+		history, err := c.ConnectionHistory("id1")
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, transition, *history[0].StateTransition)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		history, err := c.ConnectionHistory("id2")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+		require.Nil(t, history)
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		storageProvider.Store.ErrGet = errors.New("get error")
+		defer func() { storageProvider.Store.ErrGet = nil }()
+
+		history, err := c.ConnectionHistory("id1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot fetch connection history")
+		require.Nil(t, history)
+	})
+}
+
+func TestClient_ConnectionRecord(t *testing.T) {
+	svc, err := didexchange.New(&mockprotocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	storageProvider := mockstore.NewMockStoreProvider()
+
+	c, err := New(&mockprovider.Provider{
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		StorageProviderValue:          storageProvider,
+		ServiceMap: map[string]interface{}{
+			didexchange.DIDExchange: svc,
+			route.Coordination:      &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	connRec := &connection.Record{
+		ConnectionID:    "id1",
+		ThreadID:        "thid1",
+		State:           "completed",
+		TheirLabel:      "alice",
+		ServiceEndPoint: "http://alice.example.com",
+		RecipientKeys:   []string{"recKey"},
+		RoutingKeys:     []string{"routingKey"},
+		InvitationID:    "invitationID",
+		Namespace:       "my",
+	}
+	require.NoError(t, c.connectionStore.SaveConnectionRecord(connRec))
+
+	t.Run("test success", func(t *testing.T) {
+		record, err := c.ConnectionRecord("id1")
+		require.NoError(t, err)
+		require.Equal(t, *connRec, record.Record)
+
+		nsThID, err := connection.CreateNamespaceKey("my", "thid1")
+		require.NoError(t, err)
+		require.Equal(t, nsThID, record.NSThreadID)
+
+		// changes of the record are not persisted
+		record.RoutingKeys[0] = "changed"
+
+		record, err = c.ConnectionRecord("id1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"routingKey"}, record.RoutingKeys)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		record, err := c.ConnectionRecord("id2")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+		require.Nil(t, record)
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		storageProvider.Store.ErrGet = errors.New("get error")
+		defer func() { storageProvider.Store.ErrGet = nil }()
+
+		record, err := c.ConnectionRecord("id1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot fetch connection record")
+		require.Nil(t, record)
+	})
+}
+
+func TestClient_RequireEncryption(t *testing.T) {
+	svc, err := didexchange.New(&mockprotocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	storageProvider := mockstore.NewMockStoreProvider()
+	transientStorageProvider := mockstore.NewMockStoreProvider()
+
+	c, err := New(&mockprovider.Provider{
+		TransientStorageProviderValue: transientStorageProvider,
+		StorageProviderValue:          storageProvider,
+		ServiceMap: map[string]interface{}{
+			didexchange.DIDExchange: svc,
+			route.Coordination:      &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	connRec := &connection.Record{ConnectionID: "id1", ThreadID: "thid1", State: "completed"}
+	require.NoError(t, c.connectionStore.SaveConnectionRecord(connRec))
+
+	t.Run("test success", func(t *testing.T) {
+		require.NoError(t, c.RequireEncryption("id1", true))
+
+		record, err := c.ConnectionRecord("id1")
+		require.NoError(t, err)
+		require.True(t, record.RequireEncryption)
+
+		require.NoError(t, c.RequireEncryption("id1", false))
+
+		record, err = c.ConnectionRecord("id1")
+		require.NoError(t, err)
+		require.False(t, record.RequireEncryption)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		err := c.RequireEncryption("id2", true)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		storageProvider.Store.ErrGet = errors.New("get error")
+		defer func() { storageProvider.Store.ErrGet = nil }()
+
+		err := c.RequireEncryption("id1", true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot fetch connection record")
+	})
+
+	t.Run("test save error", func(t *testing.T) {
+		transientStorageProvider.Store.ErrPut = errors.New("put error")
+		defer func() { transientStorageProvider.Store.ErrPut = nil }()
+
+		err := c.RequireEncryption("id1", true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot save connection record")
+	})
+}
+
 func TestClientGetConnectionAtState(t *testing.T) {
 	// create service
 	svc, err := didexchange.New(&mockprotocol.MockProvider{
@@ -563,6 +801,85 @@ func TestClient_HandleInvitation(t *testing.T) {
 		require.NotEmpty(t, connectionID)
 	})
 
+	t.Run("test success with connection metadata", func(t *testing.T) {
+		store, transientStore := mockstore.NewMockStoreProvider(), mockstore.NewMockStoreProvider()
+
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			StoreProvider:          store,
+			TransientStoreProvider: transientStore,
+			ServiceMap: map[string]interface{}{
+				route.Coordination: &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: transientStore,
+			StorageProviderValue:          store,
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"})
+		require.NoError(t, err)
+
+		inviteReq, err := c.CreateInvitation("agent")
+		require.NoError(t, err)
+
+		metadata := map[string]interface{}{"user": "alice"}
+
+		connectionID, err := c.HandleInvitation(inviteReq, WithConnectionMetadata(metadata))
+		require.NoError(t, err)
+
+		conn, err := c.GetConnection(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, metadata, conn.Metadata)
+
+		// the metadata of the received invitation is not saved for the invitation ID
+		_, err = c.connectionStore.GetInvitationMetadata(inviteReq.ID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// the metadata of the created invitation is not assigned to the connection of the received one
+		inviteReq, err = c.CreateInvitation("agent", WithConnectionMetadata(map[string]interface{}{"user": "bob"}))
+		require.NoError(t, err)
+
+		connectionID, err = c.HandleInvitation(inviteReq)
+		require.NoError(t, err)
+
+		conn, err = c.GetConnection(connectionID)
+		require.NoError(t, err)
+		require.Empty(t, conn.Metadata)
+	})
+
+	t.Run("test invitation expiry", func(t *testing.T) {
+		now := time.Now()
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"}, WithClock(clock.Func(func() time.Time { return now })))
+		require.NoError(t, err)
+
+		inviteReq, err := c.CreateInvitation("agent", WithInvitationExpiry(time.Hour))
+		require.NoError(t, err)
+
+		connectionID, err := c.HandleInvitation(inviteReq)
+		require.NoError(t, err)
+		require.NotEmpty(t, connectionID)
+
+		now = now.Add(time.Hour + time.Minute)
+
+		connectionID, err = c.HandleInvitation(inviteReq)
+		require.True(t, errors.Is(err, ErrInvitationExpired))
+		require.Empty(t, connectionID)
+	})
+
 	t.Run("test error from handle msg", func(t *testing.T) {
 		c, err := New(&mockprovider.Provider{
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
@@ -585,6 +902,206 @@ func TestClient_HandleInvitation(t *testing.T) {
 	})
 }
 
+func TestClient_CreateOOBInvitation(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"})
+		require.NoError(t, err)
+
+		request := &decorator.Attachment{ID: "request-0", MimeType: "application/json"}
+
+		invitation, err := c.CreateOOBInvitation(WithOOBLabel("agent"), WithOOBGoal("connect", "p2p-messaging"),
+			WithOOBRequests(request), WithOOBServices("did:example:123"))
+		require.NoError(t, err)
+		require.NotNil(t, invitation)
+		require.NotEmpty(t, invitation.ID)
+		require.Equal(t, OOBInvitationMsgType, invitation.Type)
+		require.Equal(t, "agent", invitation.Label)
+		require.Equal(t, "connect", invitation.Goal)
+		require.Equal(t, "p2p-messaging", invitation.GoalCode)
+		require.Equal(t, []string{handshakeProtocol}, invitation.HandshakeProtocols)
+		require.Equal(t, []*decorator.Attachment{request}, invitation.Requests)
+		require.Len(t, invitation.Service, 2)
+		require.Equal(t, "did:example:123", invitation.Service[1])
+
+		inlineService, ok := invitation.Service[0].(*didexchange.OOBService)
+		require.True(t, ok)
+		require.Equal(t, []string{"sample-key"}, inlineService.RecipientKeys)
+		require.Equal(t, "endpoint", inlineService.ServiceEndpoint)
+
+		// the equivalent DID Exchange invitation is saved under the same ID
+		savedInvitation := &didexchange.Invitation{}
+		err = c.connectionStore.GetInvitation(invitation.ID, savedInvitation)
+		require.NoError(t, err)
+		require.Equal(t, InvitationMsgType, savedInvitation.Type)
+		require.Equal(t, []string{"sample-key"}, savedInvitation.RecipientKeys)
+	})
+
+	t.Run("test error from createSigningKey", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue: &mockkms.CloseableKMS{CreateKeyErr: fmt.Errorf("createKeyErr")}})
+		require.NoError(t, err)
+
+		_, err = c.CreateOOBInvitation()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "createKeyErr")
+	})
+
+	t.Run("test create out-of-band invitation with router config error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination: &mockroute.MockRouteSvc{
+					ConfigErr: errors.New("router config error"),
+				},
+			},
+			KMSValue: &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"}})
+		require.NoError(t, err)
+
+		_, err = c.CreateOOBInvitation()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create out-of-band invitation - fetch router config")
+	})
+}
+
+func TestClient_HandleOOBInvitation(t *testing.T) {
+	newClient := func(t *testing.T, handleFunc func(service.DIDCommMsg) (string, error)) *Client {
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{HandleFunc: handleFunc},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		var invitation *didexchange.Invitation
+
+		c := newClient(t, func(msg service.DIDCommMsg) (string, error) {
+			invitation = &didexchange.Invitation{}
+			require.NoError(t, msg.Decode(invitation))
+
+			return "connection-id", nil
+		})
+
+		oobInvitation, err := c.CreateOOBInvitation(WithOOBLabel("agent"))
+		require.NoError(t, err)
+
+		// round trip the invitation to get an inline service block as it is received by the invitee
+		bytes, err := json.Marshal(oobInvitation)
+		require.NoError(t, err)
+
+		received := &OOBInvitation{&didexchange.OOBInvitation{}}
+		require.NoError(t, json.Unmarshal(bytes, received.OOBInvitation))
+
+		connectionID, err := c.HandleOOBInvitation(received)
+		require.NoError(t, err)
+		require.Equal(t, "connection-id", connectionID)
+		require.Equal(t, oobInvitation.ID, invitation.ID)
+		require.Equal(t, InvitationMsgType, invitation.Type)
+		require.Equal(t, "agent", invitation.Label)
+		require.Equal(t, []string{"sample-key"}, invitation.RecipientKeys)
+		require.Equal(t, "endpoint", invitation.ServiceEndpoint)
+	})
+
+	t.Run("test success with DID service", func(t *testing.T) {
+		var invitation *didexchange.Invitation
+
+		c := newClient(t, func(msg service.DIDCommMsg) (string, error) {
+			invitation = &didexchange.Invitation{}
+			require.NoError(t, msg.Decode(invitation))
+
+			return "connection-id", nil
+		})
+
+		connectionID, err := c.HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			ID:      "invitation-id",
+			Type:    OOBInvitationMsgType,
+			Service: []interface{}{"", "did:example:123"},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, "connection-id", connectionID)
+		require.Equal(t, "invitation-id", invitation.ID)
+		require.Equal(t, "did:example:123", invitation.DID)
+	})
+
+	t.Run("test invitation is not defined", func(t *testing.T) {
+		_, err := newClient(t, nil).HandleOOBInvitation(nil)
+		require.EqualError(t, err, "out-of-band invitation is not defined")
+	})
+
+	t.Run("test unsupported invitation type", func(t *testing.T) {
+		_, err := newClient(t, nil).HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			Type:    InvitationMsgType,
+			Service: []interface{}{"did:example:123"},
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported invitation type")
+	})
+
+	t.Run("test unsupported handshake protocols", func(t *testing.T) {
+		_, err := newClient(t, nil).HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			Type:               OOBInvitationMsgType,
+			HandshakeProtocols: []string{"https://didcomm.org/connections/1.0"},
+			Service:            []interface{}{"did:example:123"},
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported handshake protocols")
+	})
+
+	t.Run("test no supported service", func(t *testing.T) {
+		_, err := newClient(t, nil).HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			Type:    OOBInvitationMsgType,
+			Service: []interface{}{map[string]interface{}{"id": "#inline", "serviceEndpoint": "endpoint"}},
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no supported service found in out-of-band invitation")
+	})
+
+	t.Run("test invalid inline service", func(t *testing.T) {
+		_, err := newClient(t, nil).HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			Type:    OOBInvitationMsgType,
+			Service: []interface{}{map[string]interface{}{"recipientKeys": "not an array"}},
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal out-of-band service")
+	})
+
+	t.Run("test error from handle msg", func(t *testing.T) {
+		c := newClient(t, func(msg service.DIDCommMsg) (string, error) {
+			return "", fmt.Errorf("handle error")
+		})
+
+		_, err := c.HandleOOBInvitation(&OOBInvitation{&didexchange.OOBInvitation{
+			Type:    OOBInvitationMsgType,
+			Service: []interface{}{"did:example:123"},
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handle error")
+	})
+}
+
 func TestClient_CreateImplicitInvitation(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
 		c, err := New(&mockprovider.Provider{
@@ -687,6 +1204,232 @@ func TestClient_CreateImplicitInvitationWithDID(t *testing.T) {
 	})
 }
 
+func TestClient_CreateConnectionWithDID(t *testing.T) {
+	newClient := func(t *testing.T, svc *mocksvc.MockDIDExchangeSvc) *Client {
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			ServiceEndpointValue: "endpoint"})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		var args []string
+
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			ImplicitInvitationFunc: func(inviterLabel, inviterDID, inviteeLabel, inviteeDID string) (string, error) {
+				args = []string{inviterLabel, inviterDID, inviteeLabel, inviteeDID}
+
+				return "connection-id", nil
+			}})
+
+		connectionID, err := c.CreateConnectionWithDID("did:example:alice")
+		require.NoError(t, err)
+		require.Equal(t, "connection-id", connectionID)
+		require.Equal(t, []string{"", "did:example:alice", "", ""}, args)
+
+		connectionID, err = c.CreateConnectionWithDID("did:example:alice",
+			WithTheirLabel("alice"), WithMyLabel("bob"), WithMyPublicDID("did:example:bob"))
+		require.NoError(t, err)
+		require.Equal(t, "connection-id", connectionID)
+		require.Equal(t, []string{"alice", "did:example:alice", "bob", "did:example:bob"}, args)
+	})
+
+	t.Run("test error from service", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{ImplicitInvitationErr: errors.New("implicit error")})
+
+		connectionID, err := c.CreateConnectionWithDID("did:example:alice")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "implicit error")
+		require.Empty(t, connectionID)
+	})
+
+	t.Run("test missing their public DID", func(t *testing.T) {
+		connectionID, err := newClient(t, &mocksvc.MockDIDExchangeSvc{}).CreateConnectionWithDID("")
+		require.EqualError(t, err, "create connection with DID: their public DID is not defined")
+		require.Empty(t, connectionID)
+	})
+}
+
+func TestClient_HandleOOBInvitationReuse(t *testing.T) {
+	const connID = "connection-id"
+
+	newClient := func(t *testing.T, svc *mocksvc.MockDIDExchangeSvc, record *connection.Record) *Client {
+		transientStore := mockstore.NewMockStoreProvider()
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: transientStore,
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		connBytes, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, transientStore.Store.Put("conn_"+record.ConnectionID, connBytes))
+
+		return c
+	}
+
+	inlineService := map[string]interface{}{"recipientKeys": []string{"key"}, "serviceEndpoint": "endpoint"}
+
+	newInvitation := func(services ...interface{}) *OOBInvitation {
+		return &OOBInvitation{&didexchange.OOBInvitation{
+			ID:      "invitation-id",
+			Type:    OOBInvitationMsgType,
+			Service: services,
+		}}
+	}
+
+	t.Run("test reuse connection with public DID", func(t *testing.T) {
+		var args []string
+
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			HandleFunc: func(service.DIDCommMsg) (string, error) {
+				return "", errors.New("new exchange must not be started")
+			},
+			ReuseConnectionFunc: func(connectionID, invitationID string) error {
+				args = []string{connectionID, invitationID}
+
+				return nil
+			},
+		}, &connection.Record{ConnectionID: connID, State: "completed", TheirDID: "did:example:inviter"})
+
+		connectionID, err := c.HandleOOBInvitation(newInvitation(inlineService, "did:example:inviter"))
+		require.NoError(t, err)
+		require.Equal(t, connID, connectionID)
+		require.Equal(t, []string{connID, "invitation-id"}, args)
+	})
+
+	t.Run("test reuse connection with inline service", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{}, &connection.Record{
+			ConnectionID:  connID,
+			State:         "completed",
+			RecipientKeys: []string{"key"},
+		})
+
+		connectionID, err := c.HandleOOBInvitation(newInvitation(inlineService))
+		require.NoError(t, err)
+		require.Equal(t, connID, connectionID)
+	})
+
+	t.Run("test connection is not completed", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			HandleFunc: func(service.DIDCommMsg) (string, error) {
+				return "new-connection-id", nil
+			},
+			ReuseConnectionFunc: func(string, string) error {
+				return errors.New("connection must not be reused")
+			},
+		}, &connection.Record{ConnectionID: connID, State: "requested", TheirDID: "did:example:inviter"})
+
+		connectionID, err := c.HandleOOBInvitation(newInvitation("did:example:inviter"))
+		require.NoError(t, err)
+		require.Equal(t, "new-connection-id", connectionID)
+	})
+
+	t.Run("test reuse connection error", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			ReuseConnectionFunc: func(string, string) error {
+				return errors.New("reuse error")
+			},
+		}, &connection.Record{ConnectionID: connID, State: "completed", InvitationDID: "did:example:inviter"})
+
+		_, err := c.HandleOOBInvitation(newInvitation("did:example:inviter"))
+		require.EqualError(t, err, "handle out-of-band invitation: reuse error")
+	})
+
+	t.Run("test query connections error", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{}, &connection.Record{ConnectionID: connID})
+
+		transientStore := mockstore.NewMockStoreProvider()
+		require.NoError(t, transientStore.Store.Put("conn_invalid", []byte("invalid")))
+
+		connectionStore, err := connection.NewRecorder(&mockprovider.Provider{
+			TransientStorageProviderValue: transientStore,
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		c.connectionStore = connectionStore
+
+		_, err = c.HandleOOBInvitation(newInvitation("did:example:inviter"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query connections to reuse")
+	})
+
+	require.False(t, matchOOBService(map[string]interface{}{"recipientKeys": "invalid"}, &connection.Record{}))
+}
+
+func TestClient_RotateDID(t *testing.T) {
+	const connID = "connection-id"
+
+	newClient := func(t *testing.T, svc *mocksvc.MockDIDExchangeSvc) *Client {
+		transientStore := mockstore.NewMockStoreProvider()
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: transientStore,
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		connBytes, err := json.Marshal(&connection.Record{ConnectionID: connID, State: "completed"})
+		require.NoError(t, err)
+		require.NoError(t, transientStore.Store.Put("conn_"+connID, connBytes))
+
+		return c
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		var args []string
+
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			RotateDIDFunc: func(connectionID, newDID string) error {
+				args = []string{connectionID, newDID}
+
+				return nil
+			}})
+
+		require.NoError(t, c.RotateDID(connID, "did:example:new"))
+		require.Equal(t, []string{connID, "did:example:new"}, args)
+	})
+
+	t.Run("test connection not found", func(t *testing.T) {
+		err := newClient(t, &mocksvc.MockDIDExchangeSvc{}).RotateDID("unknown", "did:example:new")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test error from service", func(t *testing.T) {
+		c := newClient(t, &mocksvc.MockDIDExchangeSvc{
+			RotateDIDFunc: func(connectionID, newDID string) error {
+				return errors.New("rotate error")
+			}})
+
+		err := c.RotateDID(connID, "did:example:new")
+		require.EqualError(t, err, "did exchange client - rotate DID: rotate error")
+	})
+
+	t.Run("test missing new DID", func(t *testing.T) {
+		err := newClient(t, &mocksvc.MockDIDExchangeSvc{}).RotateDID(connID, "")
+		require.EqualError(t, err, "did exchange client - rotate DID: new DID is not defined")
+	})
+}
+
 func TestClient_QueryConnectionsByParams(t *testing.T) {
 	t.Run("test get all connections", func(t *testing.T) {
 		svc, err := didexchange.New(&mockprotocol.MockProvider{
@@ -782,6 +1525,56 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		}
 	})
 
+	t.Run("test get connections by metadata param", func(t *testing.T) {
+		storageProvider := mockstore.NewMockStoreProvider()
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          storageProvider,
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		for i, metadata := range []map[string]interface{}{
+			nil,
+			{"user": "alice", "age": 30},
+			{"user": "alice", "age": 31},
+			{"user": "bob", "age": 30},
+		} {
+			val, e := json.Marshal(&connection.Record{
+				ConnectionID: fmt.Sprintf("conn%d", i),
+				Metadata:     metadata,
+			})
+			require.NoError(t, e)
+			require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("conn_abc%d", i), val))
+		}
+
+		results, err := c.QueryConnections(&QueryConnectionsParams{})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		results, err = c.QueryConnections(&QueryConnectionsParams{
+			Metadata: map[string]interface{}{"user": "alice"},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		results, err = c.QueryConnections(&QueryConnectionsParams{
+			Metadata: map[string]interface{}{"user": "alice", "age": 30},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "conn1", results[0].ConnectionID)
+
+		_, err = c.QueryConnections(&QueryConnectionsParams{
+			Metadata: map[string]interface{}{"user": make(chan int)},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal metadata filter")
+	})
+
 	t.Run("test get connections error", func(t *testing.T) {
 		svc, err := didexchange.New(&mockprotocol.MockProvider{
 			ServiceMap: map[string]interface{}{
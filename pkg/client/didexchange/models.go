@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package didexchange
 
 import (
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -37,6 +40,9 @@ type QueryConnectionsParams struct {
 
 	// TheirRole is other party's role
 	TheirRole string `json:"their_role,omitempty"`
+
+	// Metadata of the connection, the connections whose metadata contains all the given entries are matched
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Connection model
@@ -47,11 +53,146 @@ type Connection struct {
 	*connection.Record
 }
 
+// ConnectionRecord model
+//
+// This is used to represent the complete connection record as stored by DID exchange service
+//
+type ConnectionRecord struct {
+	connection.Record
+
+	// NSThreadID is the namespaced thread ID the connection is looked up by for inbound messages
+	NSThreadID string
+}
+
+// StateTransition model
+//
+// This is used to represent the transition of connection from one state to another
+//
+type StateTransition struct {
+	*connection.StateTransition
+}
+
 // Invitation model for DID Exchange invitation.
 type Invitation struct {
 	*didexchange.Invitation
 }
 
+// InvitationOption is an option for creating invitation
+type InvitationOption func(opts *invitationOpts)
+
+// invitationOpts holds options for creating invitation
+type invitationOpts struct {
+	expiry             time.Duration
+	routerConnectionID string
+	metadata           map[string]interface{}
+}
+
+// WithInvitationExpiry sets the expiry of invitation, the invitation expires after the given duration
+// since its creation (~timing.expires_time decorator is set). Expired invitation is rejected by HandleInvitation.
+func WithInvitationExpiry(d time.Duration) InvitationOption {
+	return func(opts *invitationOpts) {
+		opts.expiry = d
+	}
+}
+
+// WithRouting sets the mediator (router) of the invitation, the service endpoint and routing keys of the invitation
+// are the ones of the router registered for the given connection (see route.Service Register).
+// An error is returned if the agent is not registered with the router of the connection.
+// Without the option, the endpoint and routing keys of the registered router (if any) are used.
+func WithRouting(mediatorConnectionID string) InvitationOption {
+	return func(opts *invitationOpts) {
+		opts.routerConnectionID = mediatorConnectionID
+	}
+}
+
+// WithConnectionMetadata sets the application metadata (e.g. the ID of the user) of the connections created for
+// the invitation (see CreateInvitation and HandleInvitation). The metadata is stored on the connection record
+// and is matched by QueryConnections.
+func WithConnectionMetadata(metadata map[string]interface{}) InvitationOption {
+	return func(opts *invitationOpts) {
+		opts.metadata = metadata
+	}
+}
+
+// OOBInvitation model for out-of-band invitation.
+type OOBInvitation struct {
+	*didexchange.OOBInvitation
+}
+
+// OOBOption is an option for creating out-of-band invitation
+type OOBOption func(opts *oobOpts)
+
+// oobOpts holds options for creating out-of-band invitation
+type oobOpts struct {
+	label    string
+	goal     string
+	goalCode string
+	requests []*decorator.Attachment
+	services []interface{}
+}
+
+// WithOOBLabel sets the label of out-of-band invitation
+func WithOOBLabel(label string) OOBOption {
+	return func(opts *oobOpts) {
+		opts.label = label
+	}
+}
+
+// WithOOBGoal sets the goal and the goal code of out-of-band invitation
+func WithOOBGoal(goal, goalCode string) OOBOption {
+	return func(opts *oobOpts) {
+		opts.goal = goal
+		opts.goalCode = goalCode
+	}
+}
+
+// WithOOBRequests attaches the requests (e.g. messages of other protocols) to out-of-band invitation
+func WithOOBRequests(requests ...*decorator.Attachment) OOBOption {
+	return func(opts *oobOpts) {
+		opts.requests = append(opts.requests, requests...)
+	}
+}
+
+// WithOOBServices adds services to out-of-band invitation (in addition to the inline service block which is
+// always created). Each service is either a DID (string) or an inline service block (*didexchange.OOBService).
+func WithOOBServices(services ...interface{}) OOBOption {
+	return func(opts *oobOpts) {
+		opts.services = append(opts.services, services...)
+	}
+}
+
+// Option is an option for creating connection with the public DID
+type Option func(opts *connectionOpts)
+
+// connectionOpts holds options for creating connection with the public DID
+type connectionOpts struct {
+	theirLabel  string
+	myLabel     string
+	myPublicDID string
+}
+
+// WithTheirLabel sets the label of the other party of the connection
+func WithTheirLabel(label string) Option {
+	return func(opts *connectionOpts) {
+		opts.theirLabel = label
+	}
+}
+
+// WithMyLabel sets the label sent to the other party in the exchange request
+func WithMyLabel(label string) Option {
+	return func(opts *connectionOpts) {
+		opts.myLabel = label
+	}
+}
+
+// WithMyPublicDID sets the public DID sent to the other party in the exchange request
+// (new peer DID is created if not set)
+func WithMyPublicDID(did string) Option {
+	return func(opts *connectionOpts) {
+		opts.myPublicDID = did
+	}
+}
+
 // DIDInfo model for specifying public DID and associated label
 type DIDInfo struct {
 
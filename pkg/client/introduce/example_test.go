@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package introduce
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -103,8 +104,8 @@ func provider(agent string, transport map[string]chan transportMsg) (Provider, c
 	}
 
 	dispatcherOutbound.EXPECT().
-		SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-		Do(func(msg service.DIDCommMsg, myDID string, theirDID string) error {
+		SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, msg service.DIDCommMsg, myDID string, theirDID string) error {
 			// sends a message
 			transport[theirDID] <- transportMsg{
 				msg:      msg.(service.DIDCommMsgMap),
@@ -0,0 +1,464 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	// namespace of the present proof protocol message types
+	namespace = "https://didcomm.org/present-proof/1.0/"
+
+	// RequestPresentationMsgType is the message type of the presentation request
+	RequestPresentationMsgType = namespace + "request-presentation"
+	// PresentationMsgType is the message type of the presentation
+	PresentationMsgType = namespace + "presentation"
+
+	presentProofStore = "presentproof"
+	threadKeyPrefix   = "thread_"
+
+	stateNameCompleted            = "completed"
+	stateNameRequestSent          = "request-sent"
+	stateNameRequestReceived      = "request-received"
+	stateNamePresentationSent     = "presentation-sent"
+	stateNamePresentationReceived = "presentation-received"
+
+	requestMimeType      = "application/json"
+	presentationMimeType = "application/ld+json"
+)
+
+var (
+	// ErrConnectionNotFound is returned when connection not found
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrConnectionNotCompleted is returned when connection is not completed yet
+	ErrConnectionNotCompleted = errors.New("connection not completed")
+	// ErrProtocolInstanceNotFound is returned when there is no protocol instance with the given ID
+	ErrProtocolInstanceNotFound = errors.New("protocol instance not found")
+	// ErrInvalidState is returned when the protocol instance is not in the state the operation expects
+	ErrInvalidState = errors.New("invalid state of protocol instance")
+)
+
+// Provider contains dependencies for the present proof client and is typically created by using aries.Context()
+type Provider interface {
+	Messenger() service.Messenger
+	MessageHandlerRegistry() *service.Registry
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+}
+
+// Opt is the present proof client option
+type Opt func(c *Client)
+
+// WithPresentationOpts sets the options used to decode and verify inbound presentations
+// (e.g. verifiable.WithPresPublicKeyFetcher). The presentation must have an embedded proof,
+// the challenge and domain of the request (if defined) are checked against it.
+func WithPresentationOpts(opts ...verifiable.PresentationOpt) Opt {
+	return func(c *Client) {
+		c.presentationOpts = opts
+	}
+}
+
+// Client enables access to present proof api
+type Client struct {
+	messenger            service.Messenger
+	registry             *service.Registry
+	connectionLookup     *connection.Lookup
+	store                storage.Store
+	presentationOpts     []verifiable.PresentationOpt
+	requestHandlers      []func(RequestEvent)
+	presentationHandlers []func(PresentationEvent)
+	lock                 sync.RWMutex
+}
+
+// New returns new instance of present proof client.
+// The client handles inbound present proof messages until it is closed.
+func New(ctx Provider, opts ...Opt) (*Client, error) {
+	registry := ctx.MessageHandlerRegistry()
+	if registry == nil {
+		return nil, errors.New("message handler registry is not configured")
+	}
+
+	connectionLookup, err := connection.NewLookup(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create connection lookup: %w", err)
+	}
+
+	store, err := ctx.StorageProvider().OpenStore(presentProofStore)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	c := &Client{
+		messenger:        ctx.Messenger(),
+		registry:         registry,
+		connectionLookup: connectionLookup,
+		store:            store,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err = registry.Register(namespace, c); err != nil {
+		return nil, fmt.Errorf("register present proof handler: %w", err)
+	}
+
+	return c, nil
+}
+
+// SendRequestPresentation requests the verifiable presentation from the other party of the given connection.
+// The returned protocol instance ID (thread ID of the request) is the PIID of the PresentationEvent
+// of the received presentation.
+func (c *Client) SendRequestPresentation(connectionID string, req *PresentationRequest) (string, error) {
+	if req == nil {
+		return "", errors.New("presentation request is not defined")
+	}
+
+	conn, err := c.completedConnection(connectionID)
+	if err != nil {
+		return "", err
+	}
+
+	var options requestOptions
+	options.Options.Challenge = req.Challenge
+	options.Options.Domain = req.Domain
+
+	msg := &requestPresentation{
+		ID:      uuid.New().String(),
+		Type:    RequestPresentationMsgType,
+		Comment: req.Comment,
+		RequestPresentationsAttach: []decorator.Attachment{{
+			ID:       uuid.New().String(),
+			MimeType: requestMimeType,
+			Data:     decorator.AttachmentData{JSON: options},
+		}},
+	}
+
+	// a new thread is started, its ID is the ID of the request
+	piid := msg.ID
+
+	err = c.saveRecord(piid, &record{
+		State:     stateNameRequestSent,
+		MyDID:     conn.MyDID,
+		TheirDID:  conn.TheirDID,
+		Challenge: req.Challenge,
+		Domain:    req.Domain,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err = c.messenger.Send(service.NewDIDCommMsgMap(msg), conn.MyDID, conn.TheirDID); err != nil {
+		return "", fmt.Errorf("send presentation request: %w", err)
+	}
+
+	return piid, nil
+}
+
+// AcceptRequest replies to the presentation request of the given protocol instance with the presentation.
+// The presentation should be signed with the challenge and domain of the request (see RequestEvent).
+func (c *Client) AcceptRequest(piid string, vp *verifiable.Presentation) error {
+	if vp == nil {
+		return errors.New("presentation is not defined")
+	}
+
+	rec, err := c.getRecord(piid)
+	if err != nil {
+		return err
+	}
+
+	if rec.State != stateNameRequestReceived {
+		return fmt.Errorf("accept request: %w: %s", ErrInvalidState, rec.State)
+	}
+
+	vpBytes, err := vp.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal presentation: %w", err)
+	}
+
+	msg := &presentation{
+		ID:   uuid.New().String(),
+		Type: PresentationMsgType,
+		PresentationsAttach: []decorator.Attachment{{
+			ID:       uuid.New().String(),
+			MimeType: presentationMimeType,
+			Data:     decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString(vpBytes)},
+		}},
+	}
+
+	if err = c.messenger.ReplyTo(rec.RequestMsgID, service.NewDIDCommMsgMap(msg)); err != nil {
+		return fmt.Errorf("send presentation: %w", err)
+	}
+
+	rec.State = stateNamePresentationSent
+
+	return c.saveRecord(piid, rec)
+}
+
+// RegisterRequestHandler registers the handler for inbound presentation requests.
+func (c *Client) RegisterRequestHandler(handler func(RequestEvent)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.requestHandlers = append(c.requestHandlers, handler)
+}
+
+// RegisterPresentationHandler registers the handler for inbound presentations (verified ones only).
+func (c *Client) RegisterPresentationHandler(handler func(PresentationEvent)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.presentationHandlers = append(c.presentationHandlers, handler)
+}
+
+// HandleInbound handles inbound present proof messages.
+func (c *Client) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	thid, err := msg.ThreadID()
+	if err != nil {
+		return "", fmt.Errorf("threadID: %w", err)
+	}
+
+	switch msg.Type() {
+	case RequestPresentationMsgType:
+		return "", c.handleRequest(msg, thid, myDID, theirDID)
+	case PresentationMsgType:
+		return "", c.handlePresentation(msg, thid, myDID, theirDID)
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+}
+
+// Close stops handling inbound present proof messages.
+func (c *Client) Close() error {
+	if err := c.registry.Unregister(namespace); err != nil {
+		return fmt.Errorf("unregister present proof handler: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) handleRequest(msg service.DIDCommMsg, thid, myDID, theirDID string) error {
+	var req requestPresentation
+
+	if err := msg.Decode(&req); err != nil {
+		return fmt.Errorf("decode presentation request: %w", err)
+	}
+
+	var options requestOptions
+
+	if err := fetchAttachment(req.RequestPresentationsAttach, func(data []byte) error {
+		return json.Unmarshal(data, &options)
+	}); err != nil {
+		return fmt.Errorf("presentation request: %w", err)
+	}
+
+	// the thread ID is chosen by the other party, the protocol instance gets its own ID
+	// so that the request can't overwrite the protocol instances of this or other connections
+	piid := uuid.New().String()
+
+	if err := c.bindThread(myDID, theirDID, thid, piid); err != nil {
+		return err
+	}
+
+	err := c.saveRecord(piid, &record{
+		State:        stateNameRequestReceived,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+		RequestMsgID: msg.ID(),
+		Challenge:    options.Options.Challenge,
+		Domain:       options.Options.Domain,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.lock.RLock()
+	handlers := make([]func(RequestEvent), len(c.requestHandlers))
+	copy(handlers, c.requestHandlers)
+	c.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(RequestEvent{
+			PIID: piid,
+			Request: &PresentationRequest{
+				Comment:   req.Comment,
+				Challenge: options.Options.Challenge,
+				Domain:    options.Options.Domain,
+			},
+			MyDID:    myDID,
+			TheirDID: theirDID,
+		})
+	}
+
+	return nil
+}
+
+func (c *Client) handlePresentation(msg service.DIDCommMsg, piid, myDID, theirDID string) error {
+	rec, err := c.getRecord(piid)
+	if err != nil {
+		return err
+	}
+
+	// the presentation is accepted only from the connection the request was sent over
+	if rec.MyDID != myDID || rec.TheirDID != theirDID {
+		return fmt.Errorf("presentation: %w: %s", ErrProtocolInstanceNotFound, piid)
+	}
+
+	if rec.State != stateNameRequestSent {
+		return fmt.Errorf("presentation: %w: %s", ErrInvalidState, rec.State)
+	}
+
+	var pres presentation
+
+	if err = msg.Decode(&pres); err != nil {
+		return fmt.Errorf("decode presentation: %w", err)
+	}
+
+	vp, err := c.verifyPresentation(&pres, rec)
+	if err != nil {
+		return fmt.Errorf("presentation: %w", err)
+	}
+
+	rec.State = stateNamePresentationReceived
+
+	if err = c.saveRecord(piid, rec); err != nil {
+		return err
+	}
+
+	c.lock.RLock()
+	handlers := make([]func(PresentationEvent), len(c.presentationHandlers))
+	copy(handlers, c.presentationHandlers)
+	c.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(PresentationEvent{PIID: piid, Presentation: vp, MyDID: myDID, TheirDID: theirDID})
+	}
+
+	return nil
+}
+
+// verifyPresentation decodes the presentation and checks its proof against the request
+func (c *Client) verifyPresentation(pres *presentation, rec *record) (*verifiable.Presentation, error) {
+	opts := append([]verifiable.PresentationOpt{}, c.presentationOpts...)
+	opts = append(opts, verifiable.WithPresProofChallenge(rec.Challenge), verifiable.WithPresProofDomain(rec.Domain))
+
+	var vp *verifiable.Presentation
+
+	err := fetchAttachment(pres.PresentationsAttach, func(data []byte) error {
+		var vpErr error
+		vp, vpErr = verifiable.NewPresentation(data, opts...)
+
+		return vpErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vp.Proofs) == 0 {
+		return nil, errors.New("presentation has no embedded proof")
+	}
+
+	return vp, nil
+}
+
+func (c *Client) completedConnection(connectionID string) (*connection.Record, error) {
+	conn, err := c.connectionLookup.GetConnectionRecord(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrConnectionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get connection record: %w", err)
+	}
+
+	if conn.State != stateNameCompleted {
+		return nil, ErrConnectionNotCompleted
+	}
+
+	return conn, nil
+}
+
+// getRecord returns the state of the protocol instance
+func (c *Client) getRecord(piid string) (*record, error) {
+	src, err := c.store.Get(piid)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrProtocolInstanceNotFound, piid)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get protocol instance: %w", err)
+	}
+
+	var rec record
+	if err = json.Unmarshal(src, &rec); err != nil {
+		return nil, fmt.Errorf("unmarshal protocol instance: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// saveRecord saves the state of the protocol instance
+func (c *Client) saveRecord(piid string, rec *record) error {
+	src, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal protocol instance: %w", err)
+	}
+
+	if err = c.store.Put(piid, src); err != nil {
+		return fmt.Errorf("save protocol instance: %w", err)
+	}
+
+	return nil
+}
+
+// bindThread binds the thread of the inbound request to the protocol instance,
+// the thread can be bound once per connection
+func (c *Client) bindThread(myDID, theirDID, thid, piid string) error {
+	key := threadKeyPrefix + myDID + "_" + theirDID + "_" + thid
+
+	_, err := c.store.Get(key)
+	if err == nil {
+		return fmt.Errorf("presentation request: %w: thread %s is already bound", ErrInvalidState, thid)
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("get thread: %w", err)
+	}
+
+	if err = c.store.Put(key, []byte(piid)); err != nil {
+		return fmt.Errorf("save thread: %w", err)
+	}
+
+	return nil
+}
+
+// fetchAttachment passes the payload of the first attachment to the handle function
+func fetchAttachment(attachments []decorator.Attachment, handle func(data []byte) error) error {
+	if len(attachments) == 0 {
+		return errors.New("attachment is absent")
+	}
+
+	data, err := attachments[0].Data.Fetch()
+	if err != nil {
+		return fmt.Errorf("fetch attachment: %w", err)
+	}
+
+	return handle(data)
+}
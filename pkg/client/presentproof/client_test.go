@@ -0,0 +1,448 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	verifierDID = "did:example:verifier"
+	proverDID   = "did:example:prover"
+)
+
+const vpJSON = `
+{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    "https://www.w3.org/2018/credentials/examples/v1"
+  ],
+  "id": "urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5",
+  "type": "VerifiablePresentation",
+  "verifiableCredential": [
+    {
+      "@context": [
+        "https://www.w3.org/2018/credentials/v1",
+        "https://www.w3.org/2018/credentials/examples/v1"
+      ],
+      "id": "http://example.edu/credentials/1872",
+      "type": [
+        "VerifiableCredential",
+        "AlumniCredential"
+      ],
+      "issuer": "https://example.edu/issuers/565049",
+      "issuanceDate": "2010-01-01T19:03:24Z",
+      "credentialSubject": {
+        "id": "did:example:ebfeb1f712ebc6f1c276e12ec21"
+      }
+    }
+  ],
+  "holder": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+  "proof": {
+    "type": "Ed25519Signature2018",
+    "created": "2020-01-21T16:44:53+02:00",
+    "challenge": "challenge",
+    "domain": "example.com",
+    "proofValue": "eyJhbGciOiJSUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..kTCYt5XsITJX1CxPCT8yAV-TVIw5WEuts01mq-pQy7UJiN5mgREEMGlv50aqzpqh4Qq_PbChOMqsLfRoPsnsgxD-WUcX16dUOqV0G_zS245-kronKb78cPktb3rk-BuQy72IFLN25DYuNzVBAh4vGHSrQyHUGlcTwLtjPAnKb78"
+  }
+}
+`
+
+func newProvider(messenger service.Messenger) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		StorageProviderValue:          mockstore.NewMockStoreProvider(),
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		MessengerValue:                messenger,
+		MessageHandlerRegistryValue:   service.NewRegistry(),
+	}
+}
+
+func saveConnection(t *testing.T, prov *mockprovider.Provider, state string) {
+	recorder, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "conn1",
+		State:        state,
+		MyDID:        verifierDID,
+		TheirDID:     proverDID,
+	}))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		prov := newProvider(nil)
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		h, ok := prov.MessageHandlerRegistryValue.Handler(RequestPresentationMsgType)
+		require.True(t, ok)
+		require.Equal(t, client, h)
+
+		// only one client can handle inbound present proof messages
+		_, err = New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "register present proof handler")
+
+		require.NoError(t, client.Close())
+
+		err = client.Close()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unregister present proof handler")
+	})
+
+	t.Run("test no message handler registry", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.MessageHandlerRegistryValue = nil
+
+		_, err := New(prov)
+		require.EqualError(t, err, "message handler registry is not configured")
+	})
+
+	t.Run("test open store error", func(t *testing.T) {
+		prov := newProvider(nil)
+		prov.StorageProviderValue = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open error")
+	})
+}
+
+func TestClient_PresentProof(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	verifierMessenger := serviceMocks.NewMockMessenger(ctrl)
+	proverMessenger := serviceMocks.NewMockMessenger(ctrl)
+
+	verifierProv := newProvider(verifierMessenger)
+	saveConnection(t, verifierProv, "completed")
+
+	verifier, err := New(verifierProv)
+	require.NoError(t, err)
+
+	prover, err := New(newProvider(proverMessenger))
+	require.NoError(t, err)
+
+	var requestMsg service.DIDCommMsgMap
+
+	verifierMessenger.EXPECT().Send(gomock.Any(), verifierDID, proverDID).
+		Do(func(msg service.DIDCommMsgMap, _, _ string) {
+			requestMsg = msg
+		}).Return(nil)
+
+	piid, err := verifier.SendRequestPresentation("conn1", &PresentationRequest{
+		Comment:   "alumni",
+		Challenge: "challenge",
+		Domain:    "example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, RequestPresentationMsgType, requestMsg.Type())
+	require.Equal(t, piid, requestMsg.ID())
+
+	var requestEvent RequestEvent
+
+	prover.RegisterRequestHandler(func(e RequestEvent) {
+		requestEvent = e
+	})
+
+	_, err = prover.HandleInbound(requestMsg, proverDID, verifierDID)
+	require.NoError(t, err)
+	require.NotEmpty(t, requestEvent.PIID)
+	require.Equal(t, RequestEvent{
+		PIID:     requestEvent.PIID,
+		Request:  &PresentationRequest{Comment: "alumni", Challenge: "challenge", Domain: "example.com"},
+		MyDID:    proverDID,
+		TheirDID: verifierDID,
+	}, requestEvent)
+
+	vp, err := verifiable.NewPresentation([]byte(vpJSON))
+	require.NoError(t, err)
+
+	var presentationMsg service.DIDCommMsgMap
+
+	proverMessenger.EXPECT().ReplyTo(piid, gomock.Any()).
+		Do(func(_ string, msg service.DIDCommMsgMap) {
+			// the messenger threads the reply
+			msg["~thread"] = map[string]interface{}{"thid": piid}
+			presentationMsg = msg
+		}).Return(nil)
+
+	require.NoError(t, prover.AcceptRequest(requestEvent.PIID, vp))
+	require.Equal(t, PresentationMsgType, presentationMsg.Type())
+
+	// the request is accepted once
+	err = prover.AcceptRequest(requestEvent.PIID, vp)
+	require.True(t, errors.Is(err, ErrInvalidState))
+
+	var presentationEvent PresentationEvent
+
+	verifier.RegisterPresentationHandler(func(e PresentationEvent) {
+		presentationEvent = e
+	})
+
+	_, err = verifier.HandleInbound(presentationMsg, verifierDID, proverDID)
+	require.NoError(t, err)
+	require.Equal(t, piid, presentationEvent.PIID)
+	require.Equal(t, vp.ID, presentationEvent.Presentation.ID)
+	require.Equal(t, vp.Holder, presentationEvent.Presentation.Holder)
+	require.Equal(t, verifierDID, presentationEvent.MyDID)
+	require.Equal(t, proverDID, presentationEvent.TheirDID)
+
+	// the presentation is received once
+	_, err = verifier.HandleInbound(presentationMsg, verifierDID, proverDID)
+	require.True(t, errors.Is(err, ErrInvalidState))
+}
+
+func TestClient_SendRequestPresentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("test connection not found", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = client.SendRequestPresentation("conn1", &PresentationRequest{})
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test connection not completed", func(t *testing.T) {
+		prov := newProvider(nil)
+		saveConnection(t, prov, "requested")
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = client.SendRequestPresentation("conn1", &PresentationRequest{})
+		require.True(t, errors.Is(err, ErrConnectionNotCompleted))
+	})
+
+	t.Run("test request is not defined", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		_, err = client.SendRequestPresentation("conn1", nil)
+		require.EqualError(t, err, "presentation request is not defined")
+	})
+
+	t.Run("test send error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), verifierDID, proverDID).Return(errors.New("send error"))
+
+		prov := newProvider(messenger)
+		saveConnection(t, prov, "completed")
+
+		client, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = client.SendRequestPresentation("conn1", &PresentationRequest{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send presentation request: send error")
+	})
+}
+
+func TestClient_AcceptRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vp, err := verifiable.NewPresentation([]byte(vpJSON))
+	require.NoError(t, err)
+
+	t.Run("test protocol instance not found", func(t *testing.T) {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		err = client.AcceptRequest("piid", vp)
+		require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+
+		err = client.AcceptRequest("piid", nil)
+		require.EqualError(t, err, "presentation is not defined")
+	})
+
+	t.Run("test reply error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyTo("msgID", gomock.Any()).Return(errors.New("reply error"))
+
+		client, err := New(newProvider(messenger))
+		require.NoError(t, err)
+
+		require.NoError(t, client.saveRecord("piid", &record{State: stateNameRequestReceived, RequestMsgID: "msgID"}))
+
+		err = client.AcceptRequest("piid", vp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send presentation: reply error")
+	})
+}
+
+func TestClient_HandleInbound(t *testing.T) {
+	newClient := func(t *testing.T) *Client {
+		client, err := New(newProvider(nil))
+		require.NoError(t, err)
+
+		return client
+	}
+
+	t.Run("test unsupported message type", func(t *testing.T) {
+		_, err := newClient(t).HandleInbound(service.DIDCommMsgMap{
+			"@id":   "ID",
+			"@type": namespace + "propose-presentation",
+		}, proverDID, verifierDID)
+		require.EqualError(t, err, "unsupported message type "+namespace+"propose-presentation")
+	})
+
+	t.Run("test invalid thread", func(t *testing.T) {
+		_, err := newClient(t).HandleInbound(service.DIDCommMsgMap{
+			"@type":   RequestPresentationMsgType,
+			"~thread": map[string]interface{}{"thid": "thID"},
+		}, proverDID, verifierDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "threadID")
+	})
+
+	t.Run("test request without attachment", func(t *testing.T) {
+		_, err := newClient(t).HandleInbound(service.NewDIDCommMsgMap(&requestPresentation{
+			ID:   "ID",
+			Type: RequestPresentationMsgType,
+		}), proverDID, verifierDID)
+		require.EqualError(t, err, "presentation request: attachment is absent")
+	})
+
+	t.Run("test presentation of unknown protocol instance", func(t *testing.T) {
+		_, err := newClient(t).HandleInbound(service.DIDCommMsgMap{
+			"@id":   "ID",
+			"@type": PresentationMsgType,
+		}, verifierDID, proverDID)
+		require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+	})
+
+	t.Run("test invalid presentation", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.saveRecord("ID", &record{
+			State:    stateNameRequestSent,
+			MyDID:    verifierDID,
+			TheirDID: proverDID,
+		}))
+
+		_, err := client.HandleInbound(service.NewDIDCommMsgMap(&presentation{
+			ID:   "ID",
+			Type: PresentationMsgType,
+			PresentationsAttach: []decorator.Attachment{{
+				Data: decorator.AttachmentData{JSON: map[string]interface{}{"id": "invalid"}},
+			}},
+		}), verifierDID, proverDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "presentation: ")
+	})
+
+	t.Run("test presentation without embedded payload", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.saveRecord("ID", &record{
+			State:    stateNameRequestSent,
+			MyDID:    verifierDID,
+			TheirDID: proverDID,
+		}))
+
+		_, err := client.HandleInbound(service.NewDIDCommMsgMap(&presentation{
+			ID:   "ID",
+			Type: PresentationMsgType,
+			PresentationsAttach: []decorator.Attachment{{
+				Data: decorator.AttachmentData{Links: []string{"https://example.com/vp"}},
+			}},
+		}), verifierDID, proverDID)
+		require.True(t, errors.Is(err, decorator.ErrAttachmentDataNotEmbedded))
+	})
+
+	t.Run("test presentation from another connection", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.saveRecord("ID", &record{
+			State:    stateNameRequestSent,
+			MyDID:    verifierDID,
+			TheirDID: proverDID,
+		}))
+
+		_, err := client.HandleInbound(service.DIDCommMsgMap{
+			"@id":   "ID",
+			"@type": PresentationMsgType,
+		}, verifierDID, "did:example:other")
+		require.True(t, errors.Is(err, ErrProtocolInstanceNotFound))
+	})
+
+	t.Run("test presentation without embedded proof", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.saveRecord("ID", &record{
+			State:    stateNameRequestSent,
+			MyDID:    verifierDID,
+			TheirDID: proverDID,
+		}))
+
+		vp, err := verifiable.NewPresentation([]byte(vpJSON))
+		require.NoError(t, err)
+
+		vp.Proofs = nil
+
+		claims, err := vp.JWTClaims(nil, false)
+		require.NoError(t, err)
+
+		unsecuredJWT, err := claims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		_, err = client.HandleInbound(service.NewDIDCommMsgMap(&presentation{
+			ID:   "ID",
+			Type: PresentationMsgType,
+			PresentationsAttach: []decorator.Attachment{{
+				Data: decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString([]byte(unsecuredJWT))},
+			}},
+		}), verifierDID, proverDID)
+		require.EqualError(t, err, "presentation: presentation has no embedded proof")
+	})
+
+	t.Run("test request thread is bound once per connection", func(t *testing.T) {
+		client := newClient(t)
+
+		request := service.NewDIDCommMsgMap(&requestPresentation{
+			ID:   "ID",
+			Type: RequestPresentationMsgType,
+			RequestPresentationsAttach: []decorator.Attachment{{
+				Data: decorator.AttachmentData{JSON: map[string]interface{}{}},
+			}},
+		})
+
+		var piids []string
+
+		client.RegisterRequestHandler(func(e RequestEvent) {
+			piids = append(piids, e.PIID)
+		})
+
+		_, err := client.HandleInbound(request, proverDID, verifierDID)
+		require.NoError(t, err)
+
+		_, err = client.HandleInbound(request, proverDID, verifierDID)
+		require.True(t, errors.Is(err, ErrInvalidState))
+
+		// the same thread of another connection is another protocol instance
+		_, err = client.HandleInbound(request, proverDID, "did:example:other")
+		require.NoError(t, err)
+
+		require.Len(t, piids, 2)
+		require.NotEqual(t, piids[0], piids[1])
+	})
+}
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package presentproof provides the present proof protocol over an established connection.
+// The verifier requests the verifiable presentation with SendRequestPresentation,
+// the prover receives the request in the handler registered with RegisterRequestHandler
+// and replies with the presentation by AcceptRequest. The verified presentation is delivered
+// to the handlers registered with RegisterPresentationHandler. The messages of the protocol instance
+// are on the same thread. The verifier's protocol instance ID (PIID) is the thread ID,
+// the prover's one is assigned to the inbound request and is passed with RequestEvent:
+// 	// verifier
+// 	client, err := presentproof.New(ctx, presentproof.WithPresentationOpts(
+// 	  verifiable.WithPresPublicKeyFetcher(fetcher)))
+// 	client.RegisterPresentationHandler(func(e presentproof.PresentationEvent) {
+// 	  fmt.Println(e.PIID, e.Presentation.Holder)
+// 	})
+// 	piid, err := client.SendRequestPresentation(connectionID, &presentproof.PresentationRequest{
+// 	  Challenge: "c0ae1c8e-c7e7-469f-b252-86e6a0e7387e",
+// 	})
+//
+// 	// prover
+// 	client.RegisterRequestHandler(func(e presentproof.RequestEvent) {
+// 	  // sign the presentation with e.Request.Challenge and e.Request.Domain
+// 	  err := client.AcceptRequest(e.PIID, vp)
+// 	})
+//
+// RFC Reference:
+//
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0037-present-proof
+//
+package presentproof
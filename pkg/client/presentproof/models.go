@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+// PresentationRequest is the request of the verifiable presentation
+type PresentationRequest struct {
+	// Comment is a human readable comment of the request
+	Comment string
+	// Challenge is the challenge the proof of the presentation must have (prevents replay of the presentation)
+	Challenge string
+	// Domain is the domain the proof of the presentation must have
+	Domain string
+}
+
+// RequestEvent is an inbound request of the verifiable presentation
+type RequestEvent struct {
+	// PIID is the protocol instance ID, it is used to accept the request
+	PIID string
+	// Request is the presentation request
+	Request *PresentationRequest
+	// MyDID is the receiving agent's DID
+	MyDID string
+	// TheirDID is the requesting agent's DID
+	TheirDID string
+}
+
+// PresentationEvent is an inbound verifiable presentation which was requested by the agent
+type PresentationEvent struct {
+	// PIID is the protocol instance ID returned by SendRequestPresentation
+	PIID string
+	// Presentation is the verified presentation
+	Presentation *verifiable.Presentation
+	// MyDID is the receiving agent's DID
+	MyDID string
+	// TheirDID is the presenting agent's DID
+	TheirDID string
+}
+
+// requestPresentation is the request-presentation message
+type requestPresentation struct {
+	ID                         string                 `json:"@id,omitempty"`
+	Type                       string                 `json:"@type,omitempty"`
+	Comment                    string                 `json:"comment,omitempty"`
+	RequestPresentationsAttach []decorator.Attachment `json:"request_presentations~attach"`
+}
+
+// presentation is the presentation message
+type presentation struct {
+	ID                  string                 `json:"@id,omitempty"`
+	Type                string                 `json:"@type,omitempty"`
+	PresentationsAttach []decorator.Attachment `json:"presentations~attach"`
+}
+
+// requestOptions is the payload of the request-presentation attachment
+type requestOptions struct {
+	Options struct {
+		Challenge string `json:"challenge,omitempty"`
+		Domain    string `json:"domain,omitempty"`
+	} `json:"options"`
+}
+
+// record is the state of the protocol instance
+type record struct {
+	State        string `json:"state"`
+	MyDID        string `json:"my_did"`
+	TheirDID     string `json:"their_did"`
+	RequestMsgID string `json:"request_msg_id,omitempty"`
+	Challenge    string `json:"challenge,omitempty"`
+	Domain       string `json:"domain,omitempty"`
+}
@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package clock provides a time source which can be replaced to control time-dependent behavior
+// (e.g. message expiry, credential validity) in a deterministic way.
+package clock
+
+import "time"
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Func is an adapter to allow the use of ordinary functions as Clock.
+type Func func() time.Time
+
+// Now returns f().
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// Real returns Clock which provides the current local time (time.Now).
+func Real() Clock {
+	return Func(time.Now)
+}
+
+// Fixed returns Clock which always provides the given time.
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time {
+		return t
+	})
+}
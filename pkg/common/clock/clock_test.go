@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	now := Real().Now()
+
+	require.False(t, now.Before(before))
+	require.False(t, now.After(time.Now()))
+}
+
+func TestFixed(t *testing.T) {
+	fixed := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := Fixed(fixed)
+
+	require.Equal(t, fixed, c.Now())
+	require.Equal(t, fixed, c.Now())
+}
+
+func TestFunc(t *testing.T) {
+	calls := 0
+
+	var c Clock = Func(func() time.Time {
+		calls++
+
+		return time.Unix(int64(calls), 0)
+	})
+
+	require.Equal(t, time.Unix(1, 0), c.Now())
+	require.Equal(t, time.Unix(2, 0), c.Now())
+}
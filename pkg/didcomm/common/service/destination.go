@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package service
 
 import (
+	"crypto/ed25519"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
@@ -36,19 +39,45 @@ func GetDestination(did string, vdr vdri.Registry) (*Destination, error) {
 		return nil, err
 	}
 
-	return CreateDestination(didDoc)
+	return NewDestinationFromDID(didDoc)
 }
 
-// CreateDestination makes a DIDComm Destination object from a DID Doc
+// CreateDestination makes a DIDComm Destination object from a DID Doc.
+//
+// Deprecated: use NewDestinationFromDID.
 func CreateDestination(didDoc *diddoc.Doc) (*Destination, error) {
+	dest, err := NewDestinationFromDID(didDoc)
+	if err != nil {
+		return nil, fmt.Errorf("create destination: %w", err)
+	}
+
+	return dest, nil
+}
+
+// NewDestinationFromDID makes a DIDComm Destination from the DIDComm service of the DID Doc (the one with
+// the highest priority if there are several of them). The recipient keys of the service are either references
+// to Ed25519 public keys of the DID Doc or base58 encoded keys (e.g. did:peer:2 DID Doc).
+// ErrNoDIDCommService or ErrNoRecipientKeys is returned if the DID Doc can't be used to reach its subject.
+func NewDestinationFromDID(didDoc *diddoc.Doc) (*Destination, error) {
+	if didDoc == nil {
+		return nil, fmt.Errorf("new destination: %w", ErrNoDIDCommService)
+	}
+
 	didCommService, ok := diddoc.LookupService(didDoc, didCommServiceType)
 	if !ok {
-		return nil, fmt.Errorf("create destination: missing DID doc service")
+		return nil, fmt.Errorf("new destination from %s: %w", didDoc.ID, ErrNoDIDCommService)
 	}
 
-	recipientKeys, ok := diddoc.LookupRecipientKeys(didDoc, didCommServiceType, ed25519KeyType)
-	if !ok {
-		return nil, fmt.Errorf("create destination: missing keys")
+	var recipientKeys []string
+
+	for _, key := range didCommService.RecipientKeys {
+		if k, found := recipientKey(didDoc, key); found {
+			recipientKeys = append(recipientKeys, k)
+		}
+	}
+
+	if len(recipientKeys) == 0 {
+		return nil, fmt.Errorf("new destination from %s: %w", didDoc.ID, ErrNoRecipientKeys)
 	}
 
 	return &Destination{
@@ -57,3 +86,22 @@ func CreateDestination(didDoc *diddoc.Doc) (*Destination, error) {
 		RoutingKeys:     didCommService.RoutingKeys,
 	}, nil
 }
+
+// recipientKey returns base58 encoded Ed25519 key the recipient key of the service refers to
+// or the recipient key itself if it is base58 encoded key.
+func recipientKey(didDoc *diddoc.Doc, key string) (string, bool) {
+	if pubKey, ok := diddoc.LookupPublicKey(key, didDoc); ok {
+		if pubKey.Type != ed25519KeyType {
+			return "", false
+		}
+
+		// TODO fix hardcode base58 https://github.com/hyperledger/aries-framework-go/issues/1207
+		return base58.Encode(pubKey.Value), true
+	}
+
+	if len(base58.Decode(key)) != ed25519.PublicKeySize {
+		return "", false
+	}
+
+	return key, true
+}
@@ -91,6 +91,52 @@ func TestPrepareDestination(t *testing.T) {
 	})
 }
 
+func TestNewDestinationFromDID(t *testing.T) {
+	t.Run("recipient keys referring to public keys", func(t *testing.T) {
+		didDoc := mockdiddoc.GetMockDIDDoc()
+
+		dest, err := NewDestinationFromDID(didDoc)
+		require.NoError(t, err)
+		require.Equal(t, "https://localhost:8090", dest.ServiceEndpoint)
+		require.Equal(t, didDoc.Service[0].RoutingKeys, dest.RoutingKeys)
+
+		recipientKeys, ok := did.LookupRecipientKeys(didDoc, "did-communication", "Ed25519VerificationKey2018")
+		require.True(t, ok)
+		require.Equal(t, recipientKeys, dest.RecipientKeys)
+	})
+
+	t.Run("base58 encoded recipient keys", func(t *testing.T) {
+		pubKey, _ := generateKeyPair()
+
+		didDoc := createDIDDoc()
+		didDoc.Service[0].RecipientKeys = []string{pubKey, "did:example:123#unknown"}
+
+		dest, err := NewDestinationFromDID(didDoc)
+		require.NoError(t, err)
+		require.Equal(t, []string{pubKey}, dest.RecipientKeys)
+		require.Equal(t, "http://localhost:58416", dest.ServiceEndpoint)
+	})
+
+	t.Run("recipient key of unsupported type", func(t *testing.T) {
+		didDoc := createDIDDoc()
+		didDoc.PublicKey[0].Type = "X25519KeyAgreementKey2019"
+
+		_, err := NewDestinationFromDID(didDoc)
+		require.True(t, errors.Is(err, ErrNoRecipientKeys))
+	})
+
+	t.Run("no DIDComm service", func(t *testing.T) {
+		didDoc := createDIDDoc()
+		didDoc.Service[0].Type = "unknown"
+
+		_, err := NewDestinationFromDID(didDoc)
+		require.True(t, errors.Is(err, ErrNoDIDCommService))
+
+		_, err = NewDestinationFromDID(nil)
+		require.True(t, errors.Is(err, ErrNoDIDCommService))
+	})
+}
+
 func createDIDDoc() *did.Doc {
 	pubKey, _ := generateKeyPair()
 	return createDIDDocWithKey(pubKey)
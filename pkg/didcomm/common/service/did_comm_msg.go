@@ -24,6 +24,17 @@ const (
 	jsonThread   = "~thread"
 	jsonThreadID = "thid"
 	jsonMetadata = "_internal_metadata"
+
+	jsonTiming      = "~timing"
+	jsonExpiresTime = "expires_time"
+
+	jsonAttach      = "~attach"
+	jsonAttachments = "attachments"
+
+	jsonL10n = "~l10n"
+
+	jsonTransport   = "~transport"
+	jsonReturnRoute = "~return_route"
 )
 
 // Metadata may contain additional payload for the protocol. It might be populated by the client/protocol
@@ -146,6 +157,198 @@ func (m DIDCommMsgMap) ID() string {
 	return res
 }
 
+// Timing returns the message ~timing decorator.
+// Zero value is returned if the decorator is absent or has invalid format.
+func (m DIDCommMsgMap) Timing() decorator.Timing {
+	if m == nil || m[jsonTiming] == nil {
+		return decorator.Timing{}
+	}
+
+	timing, ok := m[jsonTiming].(map[string]interface{})
+	if !ok {
+		return decorator.Timing{}
+	}
+
+	var res decorator.Timing
+
+	switch expiresTime := timing[jsonExpiresTime].(type) {
+	case time.Time:
+		res.ExpiresTime = expiresTime
+	case string:
+		if t, err := time.Parse(time.RFC3339, expiresTime); err == nil {
+			res.ExpiresTime = t
+		}
+	}
+
+	return res
+}
+
+// SetTiming sets the message ~timing decorator
+func (m DIDCommMsgMap) SetTiming(timing decorator.Timing) {
+	if m == nil {
+		return
+	}
+
+	m[jsonTiming] = toMap(timing)
+}
+
+// Expired checks whether the message ~timing.expires_time has passed at the given time
+func (m DIDCommMsgMap) Expired(now time.Time) bool {
+	expiresTime := m.Timing().ExpiresTime
+
+	return !expiresTime.IsZero() && now.After(expiresTime)
+}
+
+// L10n returns the message ~l10n decorator.
+// Zero value is returned if the decorator is absent or has invalid format.
+func (m DIDCommMsgMap) L10n() decorator.L10n {
+	if m == nil || m[jsonL10n] == nil {
+		return decorator.L10n{}
+	}
+
+	raw, err := json.Marshal(m[jsonL10n])
+	if err != nil {
+		return decorator.L10n{}
+	}
+
+	var res decorator.L10n
+	if err = json.Unmarshal(raw, &res); err != nil {
+		return decorator.L10n{}
+	}
+
+	return res
+}
+
+// SetL10n sets the message ~l10n decorator
+func (m DIDCommMsgMap) SetL10n(l10n decorator.L10n) {
+	if m == nil {
+		return
+	}
+
+	m[jsonL10n] = toMap(l10n)
+}
+
+// Attachments returns the message attachments of ~attach decorator (and legacy attachments field).
+// Use decorator.AttachmentData Fetch function to get the raw bytes of the attachment payload.
+func (m DIDCommMsgMap) Attachments() ([]decorator.Attachment, error) {
+	var res []decorator.Attachment
+
+	for _, key := range []string{jsonAttach, jsonAttachments} {
+		if m[key] == nil {
+			continue
+		}
+
+		raw, err := json.Marshal(m[key])
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", key, err)
+		}
+
+		var attachments []decorator.Attachment
+		if err = json.Unmarshal(raw, &attachments); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", key, err)
+		}
+
+		res = append(res, attachments...)
+	}
+
+	return res, nil
+}
+
+// AddAttachment adds the attachment to the message ~attach decorator.
+// The decorator is rewritten if it has invalid format.
+func (m DIDCommMsgMap) AddAttachment(attachment decorator.Attachment) {
+	if m == nil {
+		return
+	}
+
+	attachments, _ := m[jsonAttach].([]interface{}) //nolint:errcheck
+
+	m[jsonAttach] = append(attachments, toMap(attachment))
+}
+
+// ReturnRoute returns the message ~transport decorator return route option ("none", "all" or "thread").
+// Empty string is returned if the decorator is absent or has invalid format.
+func (m DIDCommMsgMap) ReturnRoute() string {
+	if m == nil || m[jsonTransport] == nil {
+		return ""
+	}
+
+	transport, ok := m[jsonTransport].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	res, ok := transport[jsonReturnRoute].(string)
+	if !ok {
+		return ""
+	}
+
+	return res
+}
+
+// SetReturnRoute sets the message ~transport decorator return route option ("none", "all" or "thread").
+// The outbound dispatcher keeps the option of the message instead of the one configured in the framework.
+func (m DIDCommMsgMap) SetReturnRoute(value string) {
+	if m == nil {
+		return
+	}
+
+	m[jsonTransport] = toMap(decorator.ReturnRoute{Value: value})
+}
+
+// Clone returns a deep copy of the message, i.e. nested maps and slices are copied as well,
+// so the copy can be modified without affecting the original message.
+func (m DIDCommMsgMap) Clone() DIDCommMsgMap {
+	if m == nil {
+		return nil
+	}
+
+	return DIDCommMsgMap(cloneValue(map[string]interface{}(m)).(map[string]interface{}))
+}
+
+// cloneValue deep copies maps and slices, other values are returned as is
+func cloneValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+
+		res := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+
+		for _, k := range rv.MapKeys() {
+			res.SetMapIndex(k, clonedValueOf(rv.MapIndex(k), rv.Type().Elem()))
+		}
+
+		return res.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+
+		res := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+
+		for i := 0; i < rv.Len(); i++ {
+			res.Index(i).Set(clonedValueOf(rv.Index(i), rv.Type().Elem()))
+		}
+
+		return res.Interface()
+	default:
+		return v
+	}
+}
+
+// clonedValueOf returns the clone of the element of the map or slice
+func clonedValueOf(v reflect.Value, elemType reflect.Type) reflect.Value {
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) && v.IsNil() {
+		return reflect.Zero(elemType)
+	}
+
+	return reflect.ValueOf(cloneValue(v.Interface())).Convert(elemType)
+}
+
 // Decode converts message to  struct
 func (m DIDCommMsgMap) Decode(v interface{}) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -8,10 +8,13 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 )
 
 func TestDIDCommMsgMap_ID(t *testing.T) {
@@ -46,6 +49,45 @@ func TestDIDCommMsgMap_ID(t *testing.T) {
 	}
 }
 
+func TestDIDCommMsgMap_Clone(t *testing.T) {
+	t.Run("nil message", func(t *testing.T) {
+		require.Nil(t, DIDCommMsgMap(nil).Clone())
+	})
+
+	t.Run("nested values are copied", func(t *testing.T) {
+		created := time.Now()
+
+		msg := DIDCommMsgMap{
+			jsonID:     "ID",
+			jsonThread: map[string]interface{}{jsonThreadID: "thID"},
+			"list":     []interface{}{map[string]interface{}{"k": "v"}, nil, "item"},
+			"strings":  []string{"a", "b"},
+			"nested":   DIDCommMsgMap{"k": "v"},
+			"created":  created,
+			"empty":    nil,
+		}
+
+		clone := msg.Clone()
+		require.Equal(t, msg, clone)
+
+		clone[jsonID] = "other"
+		clone[jsonThread].(map[string]interface{})[jsonThreadID] = "other"
+		clone["list"].([]interface{})[0].(map[string]interface{})["k"] = "other"
+		clone["strings"].([]string)[0] = "other"
+		clone["nested"].(DIDCommMsgMap)["k"] = "other"
+
+		require.Equal(t, DIDCommMsgMap{
+			jsonID:     "ID",
+			jsonThread: map[string]interface{}{jsonThreadID: "thID"},
+			"list":     []interface{}{map[string]interface{}{"k": "v"}, nil, "item"},
+			"strings":  []string{"a", "b"},
+			"nested":   DIDCommMsgMap{"k": "v"},
+			"created":  created,
+			"empty":    nil,
+		}, msg)
+	})
+}
+
 func TestDIDCommMsgMap_MetaData(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -104,6 +146,280 @@ func TestDIDCommMsgMap_Type(t *testing.T) {
 	}
 }
 
+func TestDIDCommMsgMap_Timing(t *testing.T) {
+	expiresTime := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expected decorator.Timing
+		msg      DIDCommMsgMap
+	}{
+		{
+			name: "Empty (nil msg)",
+		},
+		{
+			name: "Empty",
+			msg:  DIDCommMsgMap{},
+		},
+		{
+			name: "Bad type Timing",
+			msg:  DIDCommMsgMap{jsonTiming: map[int]int{}},
+		},
+		{
+			name: "Bad expires time",
+			msg:  DIDCommMsgMap{jsonTiming: map[string]interface{}{jsonExpiresTime: "yesterday"}},
+		},
+		{
+			name:     "Success (string)",
+			msg:      DIDCommMsgMap{jsonTiming: map[string]interface{}{jsonExpiresTime: "2020-03-01T10:00:00Z"}},
+			expected: decorator.Timing{ExpiresTime: expiresTime},
+		},
+		{
+			name:     "Success (time)",
+			msg:      DIDCommMsgMap{jsonTiming: map[string]interface{}{jsonExpiresTime: expiresTime}},
+			expected: decorator.Timing{ExpiresTime: expiresTime},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.msg.Timing())
+		})
+	}
+}
+
+func TestDIDCommMsgMap_SetTiming(t *testing.T) {
+	expiresTime := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	msg := DIDCommMsgMap{jsonID: "ID"}
+	msg.SetTiming(decorator.Timing{ExpiresTime: expiresTime})
+	require.Equal(t, expiresTime, msg.Timing().ExpiresTime)
+
+	// timing survives JSON serialization
+	payload, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	parsed, err := ParseDIDCommMsgMap(payload)
+	require.NoError(t, err)
+	require.Equal(t, expiresTime, parsed.Timing().ExpiresTime)
+
+	require.False(t, parsed.Expired(expiresTime.Add(-time.Second)))
+	require.True(t, parsed.Expired(expiresTime.Add(time.Second)))
+
+	// message without ~timing decorator never expires
+	require.False(t, DIDCommMsgMap{}.Expired(time.Now()))
+
+	// nil message is ignored
+	var nilMsg DIDCommMsgMap
+	nilMsg.SetTiming(decorator.Timing{ExpiresTime: expiresTime})
+	require.Nil(t, nilMsg)
+}
+
+func TestDIDCommMsgMap_L10n(t *testing.T) {
+	t.Run("absent or invalid decorator", func(t *testing.T) {
+		require.Equal(t, decorator.L10n{}, DIDCommMsgMap(nil).L10n())
+		require.Equal(t, decorator.L10n{}, DIDCommMsgMap{}.L10n())
+		require.Equal(t, decorator.L10n{}, DIDCommMsgMap{jsonL10n: "en"}.L10n())
+		require.Equal(t, decorator.L10n{}, DIDCommMsgMap{jsonL10n: map[string]interface{}{"locale": 1}}.L10n())
+	})
+
+	t.Run("parsed message", func(t *testing.T) {
+		msg, err := ParseDIDCommMsgMap([]byte(`{
+			"@id": "ID",
+			"content": "Bonjour",
+			"~l10n": {"locale": "fr", "localizable": ["content"], "catalog": "https://example.com/catalog"}
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, decorator.L10n{
+			Locale:      "fr",
+			Localizable: []string{"content"},
+			Catalog:     "https://example.com/catalog",
+		}, msg.L10n())
+	})
+
+	t.Run("set decorator", func(t *testing.T) {
+		l10n := decorator.L10n{Locale: "en", Localizable: []string{"content"}}
+
+		msg := DIDCommMsgMap{jsonID: "ID"}
+		msg.SetL10n(l10n)
+		require.Equal(t, l10n, msg.L10n())
+
+		// decorator survives JSON serialization
+		payload, err := json.Marshal(msg)
+		require.NoError(t, err)
+		require.NotContains(t, string(payload), "catalog")
+
+		parsed, err := ParseDIDCommMsgMap(payload)
+		require.NoError(t, err)
+		require.Equal(t, l10n, parsed.L10n())
+
+		// nil message is ignored
+		var nilMsg DIDCommMsgMap
+		nilMsg.SetL10n(l10n)
+		require.Nil(t, nilMsg)
+	})
+}
+
+func TestDIDCommMsgMap_ReturnRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		msg      DIDCommMsgMap
+	}{
+		{
+			name: "Empty (nil msg)",
+		},
+		{
+			name: "Empty",
+			msg:  DIDCommMsgMap{},
+		},
+		{
+			name: "Bad type Transport",
+			msg:  DIDCommMsgMap{jsonTransport: "all"},
+		},
+		{
+			name: "Bad type ReturnRoute",
+			msg:  DIDCommMsgMap{jsonTransport: map[string]interface{}{jsonReturnRoute: true}},
+		},
+		{
+			name:     "Success",
+			msg:      DIDCommMsgMap{jsonTransport: map[string]interface{}{jsonReturnRoute: "thread"}},
+			expected: decorator.TransportReturnRouteThread,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.msg.ReturnRoute())
+		})
+	}
+}
+
+func TestDIDCommMsgMap_SetReturnRoute(t *testing.T) {
+	msg := DIDCommMsgMap{jsonID: "ID"}
+	msg.SetReturnRoute(decorator.TransportReturnRouteAll)
+	require.Equal(t, decorator.TransportReturnRouteAll, msg.ReturnRoute())
+
+	// decorator has the same format as decorator.Transport
+	payload, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	trans := &decorator.Transport{}
+	require.NoError(t, json.Unmarshal(payload, trans))
+	require.Equal(t, &decorator.ReturnRoute{Value: decorator.TransportReturnRouteAll}, trans.ReturnRoute)
+
+	parsed, err := ParseDIDCommMsgMap(payload)
+	require.NoError(t, err)
+	require.Equal(t, decorator.TransportReturnRouteAll, parsed.ReturnRoute())
+
+	// nil message is ignored
+	var nilMsg DIDCommMsgMap
+	nilMsg.SetReturnRoute(decorator.TransportReturnRouteAll)
+	require.Nil(t, nilMsg)
+}
+
+func TestDIDCommMsgMap_Attachments(t *testing.T) {
+	t.Run("no attachments", func(t *testing.T) {
+		attachments, err := DIDCommMsgMap{}.Attachments()
+		require.NoError(t, err)
+		require.Empty(t, attachments)
+	})
+
+	t.Run("parsed message", func(t *testing.T) {
+		msg, err := ParseDIDCommMsgMap([]byte(`{
+			"@id": "ID",
+			"~attach": [
+				{"@id": "attach-1", "mime-type": "application/json", "data": {"json": {"key": "value"}}},
+				{"@id": "attach-2", "mime-type": "text/plain", "data": {"base64": "aGVsbG8="}}
+			],
+			"attachments": [
+				{"@id": "attach-3", "data": {"links": ["https://example.com/file"]}}
+			]
+		}`))
+		require.NoError(t, err)
+
+		attachments, err := msg.Attachments()
+		require.NoError(t, err)
+		require.Len(t, attachments, 3)
+
+		require.Equal(t, "attach-1", attachments[0].ID)
+		require.Equal(t, "application/json", attachments[0].MimeType)
+		data, err := attachments[0].Data.Fetch()
+		require.NoError(t, err)
+		require.Equal(t, `{"key":"value"}`, string(data))
+
+		require.Equal(t, "attach-2", attachments[1].ID)
+		data, err = attachments[1].Data.Fetch()
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+
+		require.Equal(t, "attach-3", attachments[2].ID)
+		require.Equal(t, []string{"https://example.com/file"}, attachments[2].Data.Links)
+		_, err = attachments[2].Data.Fetch()
+		require.True(t, errors.Is(err, decorator.ErrAttachmentDataNotEmbedded))
+	})
+
+	t.Run("add attachment", func(t *testing.T) {
+		msg := DIDCommMsgMap{jsonID: "ID"}
+		msg.AddAttachment(decorator.Attachment{
+			ID:       "attach-1",
+			MimeType: "application/json",
+			Data:     decorator.AttachmentData{JSON: map[string]interface{}{"key": "value"}},
+		})
+		msg.AddAttachment(decorator.Attachment{
+			ID:   "attach-2",
+			Data: decorator.AttachmentData{Base64: "aGVsbG8"},
+		})
+
+		// attachments survive JSON serialization
+		payload, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		parsed, err := ParseDIDCommMsgMap(payload)
+		require.NoError(t, err)
+
+		for _, m := range []DIDCommMsgMap{msg, parsed} {
+			attachments, err := m.Attachments()
+			require.NoError(t, err)
+			require.Len(t, attachments, 2)
+			require.Equal(t, "attach-1", attachments[0].ID)
+			require.Equal(t, "application/json", attachments[0].MimeType)
+			require.Equal(t, "attach-2", attachments[1].ID)
+
+			data, err := attachments[1].Data.Fetch()
+			require.NoError(t, err)
+			require.Equal(t, "hello", string(data))
+		}
+	})
+
+	t.Run("add attachment rewrites invalid decorator", func(t *testing.T) {
+		msg := DIDCommMsgMap{jsonAttach: "invalid"}
+
+		_, err := msg.Attachments()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal ~attach")
+
+		msg.AddAttachment(decorator.Attachment{ID: "attach-1"})
+
+		attachments, err := msg.Attachments()
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+
+		// nil message is ignored
+		var nilMsg DIDCommMsgMap
+		nilMsg.AddAttachment(decorator.Attachment{ID: "attach-1"})
+		require.Nil(t, nilMsg)
+	})
+
+	t.Run("invalid attachments", func(t *testing.T) {
+		_, err := DIDCommMsgMap{jsonAttachments: make(chan int)}.Attachments()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal attachments")
+	})
+}
+
 func TestDIDCommMsgMap_ToStruct(t *testing.T) {
 	type Test struct {
 		Time  time.Time
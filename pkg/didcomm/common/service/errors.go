@@ -13,6 +13,10 @@ const (
 	ErrInvalidChannel    = serviceError("invalid channel passed to unregister the action event")
 	ErrThreadIDNotFound  = serviceError("threadID not found")
 	ErrInvalidMessage    = serviceError("invalid message")
+	ErrMessageExpired    = serviceError("message expired")
+	ErrNotProblemReport  = serviceError("message is not a problem report")
+	ErrNoDIDCommService  = serviceError("missing DID doc service")
+	ErrNoRecipientKeys   = serviceError("missing keys")
 )
 
 // serviceError defines service error
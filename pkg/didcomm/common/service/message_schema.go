@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// InvalidMessageError is returned when the message does not conform to the JSON schema registered for its type.
+// It wraps ErrInvalidMessage, so it can be checked with errors.Is.
+type InvalidMessageError struct {
+	// Type is the @type of the invalid message
+	Type string
+	// Violations are the descriptions of the schema violations
+	Violations []string
+}
+
+// Error satisfies build-in error interface
+func (e *InvalidMessageError) Error() string {
+	return fmt.Sprintf("%s of type '%s': %s", ErrInvalidMessage, e.Type, strings.Join(e.Violations, "; "))
+}
+
+// Unwrap returns ErrInvalidMessage
+func (e *InvalidMessageError) Unwrap() error {
+	return ErrInvalidMessage
+}
+
+// MessageSchemas keeps the JSON schemas of the message types and validates the messages against them.
+// The messages of the types without registered schema are not validated.
+type MessageSchemas struct {
+	schemas map[string]*gojsonschema.Schema
+	lock    sync.RWMutex
+}
+
+// NewMessageSchemas returns a new instance of MessageSchemas
+func NewMessageSchemas() *MessageSchemas {
+	return &MessageSchemas{schemas: make(map[string]*gojsonschema.Schema)}
+}
+
+// Register registers the JSON schema of the message type, the previous schema of the type is replaced
+func (s *MessageSchemas) Register(msgType string, schema []byte) error {
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return fmt.Errorf("register schema of '%s': %w", msgType, err)
+	}
+
+	s.lock.Lock()
+	s.schemas[msgType] = compiled
+	s.lock.Unlock()
+
+	return nil
+}
+
+// Validate validates the message against the JSON schema registered for its type.
+// InvalidMessageError is returned if the message doesn't conform to the schema.
+func (s *MessageSchemas) Validate(msg DIDCommMsgMap) error {
+	s.lock.RLock()
+	schema, ok := s.schemas[msg.Type()]
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}(msg)))
+	if err != nil {
+		return fmt.Errorf("validate message of type '%s': %w", msg.Type(), err)
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	violations := make([]string, len(result.Errors()))
+	for i, desc := range result.Errors() {
+		violations[i] = desc.String()
+	}
+
+	return &InvalidMessageError{Type: msg.Type(), Violations: violations}
+}
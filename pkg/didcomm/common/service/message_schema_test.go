@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMsgType   = "https://didcomm.org/test/1.0/message"
+	testMsgSchema = `{
+  "type": "object",
+  "required": ["@id", "comment"],
+  "properties": {
+    "comment": {"type": "string"}
+  }
+}`
+)
+
+func TestMessageSchemas(t *testing.T) {
+	schemas := NewMessageSchemas()
+	require.NoError(t, schemas.Register(testMsgType, []byte(testMsgSchema)))
+
+	t.Run("valid message", func(t *testing.T) {
+		require.NoError(t, schemas.Validate(DIDCommMsgMap{
+			jsonID:    "ID",
+			jsonType:  testMsgType,
+			"comment": "hello",
+		}))
+	})
+
+	t.Run("unregistered type is not validated", func(t *testing.T) {
+		require.NoError(t, schemas.Validate(DIDCommMsgMap{jsonType: "https://didcomm.org/test/1.0/other"}))
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		err := schemas.Validate(DIDCommMsgMap{
+			jsonType:  testMsgType,
+			"comment": 42,
+		})
+		require.True(t, errors.Is(err, ErrInvalidMessage))
+
+		var invalidErr *InvalidMessageError
+
+		require.True(t, errors.As(err, &invalidErr))
+		require.Equal(t, testMsgType, invalidErr.Type)
+		require.Len(t, invalidErr.Violations, 2)
+		require.Contains(t, err.Error(), "invalid message of type '"+testMsgType+"'")
+		require.Contains(t, err.Error(), "@id is required")
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		err := schemas.Register(testMsgType, []byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "register schema of '"+testMsgType+"'")
+	})
+}
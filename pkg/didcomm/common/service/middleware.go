@@ -0,0 +1,25 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+// InboundFunc handles the inbound message received by myDID from theirDID (see MessengerHandler HandleInbound).
+type InboundFunc func(msg DIDCommMsgMap, myDID, theirDID string) error
+
+// Middleware wraps the handling of the inbound messages to apply cross-cutting concerns (e.g. logging,
+// authorization, metrics or rate limiting). The middleware may inspect or modify the message and pass it
+// to next, or short-circuit returning an error without calling next.
+type Middleware func(next InboundFunc) InboundFunc
+
+// Chain wraps the handler by the middlewares, the first middleware is the outermost one, i.e. it is the first
+// to see the inbound message.
+func Chain(handler InboundFunc, middlewares ...Middleware) InboundFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+
+	record := func(name string) Middleware {
+		return func(next InboundFunc) InboundFunc {
+			return func(msg DIDCommMsgMap, myDID, theirDID string) error {
+				calls = append(calls, name)
+				msg[name] = true
+
+				return next(msg, myDID, theirDID)
+			}
+		}
+	}
+
+	handler := func(msg DIDCommMsgMap, myDID, theirDID string) error {
+		calls = append(calls, "handler")
+
+		require.Equal(t, "myDID", myDID)
+		require.Equal(t, "theirDID", theirDID)
+
+		return nil
+	}
+
+	t.Run("middlewares are called in order", func(t *testing.T) {
+		calls = nil
+		msg := DIDCommMsgMap{}
+
+		require.NoError(t, Chain(handler, record("first"), record("second"))(msg, "myDID", "theirDID"))
+		require.Equal(t, []string{"first", "second", "handler"}, calls)
+		require.Equal(t, DIDCommMsgMap{"first": true, "second": true}, msg)
+	})
+
+	t.Run("no middlewares", func(t *testing.T) {
+		calls = nil
+
+		require.NoError(t, Chain(handler)(DIDCommMsgMap{}, "myDID", "theirDID"))
+		require.Equal(t, []string{"handler"}, calls)
+	})
+
+	t.Run("short-circuit", func(t *testing.T) {
+		calls = nil
+		errUnauthorized := errors.New("unauthorized")
+
+		deny := func(next InboundFunc) InboundFunc {
+			return func(msg DIDCommMsgMap, myDID, theirDID string) error {
+				return errUnauthorized
+			}
+		}
+
+		err := Chain(handler, record("first"), deny, record("second"))(DIDCommMsgMap{}, "myDID", "theirDID")
+		require.True(t, errors.Is(err, errUnauthorized))
+		require.Equal(t, []string{"first"}, calls)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ProblemReportMsgType is the message type of the problem report
+	// https://github.com/hyperledger/aries-rfcs/tree/master/features/0035-report-problem
+	ProblemReportMsgType = "https://didcomm.org/notification/1.0/problem-report"
+
+	// problemReportSuffix is the suffix of the problem report message types (including protocol specific ones)
+	problemReportSuffix = "/problem-report"
+
+	jsonDescription = "description"
+	jsonCode        = "code"
+	jsonExplainEN   = "en"
+	jsonExplainLtxt = "explain_ltxt"
+)
+
+// ProblemReport is the parsed problem report message. It implements error interface,
+// so the problem reported by the other party can be returned as error.
+type ProblemReport struct {
+	// ID is the ID of the problem report message
+	ID string
+	// ThreadID is the thread of the failed protocol
+	ThreadID string
+	// Code is the problem code, e.g. "request-not-accepted"
+	Code string
+	// Explain is the human readable explanation of the problem
+	Explain string
+}
+
+// Error returns the problem description
+func (p *ProblemReport) Error() string {
+	if p.Explain == "" {
+		return fmt.Sprintf("problem report: %s", p.Code)
+	}
+
+	return fmt.Sprintf("problem report: %s: %s", p.Code, p.Explain)
+}
+
+// NewProblemReport creates the problem report message about the failure of the protocol of the given thread.
+func NewProblemReport(thID, code, explain string) DIDCommMsgMap {
+	msg := DIDCommMsgMap{
+		jsonID:   uuid.New().String(),
+		jsonType: ProblemReportMsgType,
+		jsonDescription: map[string]interface{}{
+			jsonCode: code,
+		},
+	}
+
+	if thID != "" {
+		msg[jsonThread] = map[string]interface{}{jsonThreadID: thID}
+	}
+
+	if explain != "" {
+		msg[jsonDescription].(map[string]interface{})[jsonExplainEN] = explain
+		msg[jsonExplainLtxt] = explain
+	}
+
+	return msg
+}
+
+// IsProblemReport checks if the message is a problem report,
+// both generic and protocol specific (e.g. introduce problem-report) ones are recognized.
+func (m DIDCommMsgMap) IsProblemReport() bool {
+	return strings.HasSuffix(m.Type(), problemReportSuffix)
+}
+
+// ProblemReport parses the problem report message, ErrNotProblemReport is returned for other messages.
+func (m DIDCommMsgMap) ProblemReport() (*ProblemReport, error) {
+	if !m.IsProblemReport() {
+		return nil, ErrNotProblemReport
+	}
+
+	thID, err := m.ThreadID()
+	if err != nil {
+		return nil, fmt.Errorf("problem report: %w", err)
+	}
+
+	report := &ProblemReport{ID: m.ID(), ThreadID: thID}
+
+	if description, ok := m[jsonDescription].(map[string]interface{}); ok {
+		report.Code, _ = description[jsonCode].(string)         //nolint:errcheck
+		report.Explain, _ = description[jsonExplainEN].(string) //nolint:errcheck
+	}
+
+	if report.Explain == "" {
+		report.Explain, _ = m[jsonExplainLtxt].(string) //nolint:errcheck
+	}
+
+	return report, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProblemReport(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		msg := NewProblemReport("thID", "request-not-accepted", "request is not accepted")
+		require.NotEmpty(t, msg.ID())
+		require.Equal(t, ProblemReportMsgType, msg.Type())
+		require.True(t, msg.IsProblemReport())
+
+		report, err := msg.ProblemReport()
+		require.NoError(t, err)
+		require.Equal(t, &ProblemReport{
+			ID:       msg.ID(),
+			ThreadID: "thID",
+			Code:     "request-not-accepted",
+			Explain:  "request is not accepted",
+		}, report)
+		require.EqualError(t, report, "problem report: request-not-accepted: request is not accepted")
+	})
+
+	t.Run("without thread and explanation", func(t *testing.T) {
+		msg := NewProblemReport("", "request-not-accepted", "")
+
+		report, err := msg.ProblemReport()
+		require.NoError(t, err)
+		// the message starts the new thread
+		require.Equal(t, msg.ID(), report.ThreadID)
+		require.Empty(t, report.Explain)
+		require.EqualError(t, report, "problem report: request-not-accepted")
+	})
+}
+
+func TestDIDCommMsgMap_ProblemReport(t *testing.T) {
+	t.Run("protocol specific problem report", func(t *testing.T) {
+		msg := DIDCommMsgMap{
+			jsonID:          "ID",
+			jsonType:        "https://didcomm.org/introduce/1.0/problem-report",
+			jsonExplainLtxt: "introducee is not available",
+		}
+		require.True(t, msg.IsProblemReport())
+
+		report, err := msg.ProblemReport()
+		require.NoError(t, err)
+		require.Equal(t, "ID", report.ID)
+		require.Empty(t, report.Code)
+		require.Equal(t, "introducee is not available", report.Explain)
+	})
+
+	t.Run("not a problem report", func(t *testing.T) {
+		msg := DIDCommMsgMap{jsonID: "ID", jsonType: "https://didcomm.org/introduce/1.0/proposal"}
+		require.False(t, msg.IsProblemReport())
+
+		report, err := msg.ProblemReport()
+		require.True(t, errors.Is(err, ErrNotProblemReport))
+		require.Nil(t, report)
+	})
+
+	t.Run("invalid thread", func(t *testing.T) {
+		msg := NewProblemReport("thID", "request-not-accepted", "")
+		delete(msg, jsonID)
+
+		report, err := msg.ProblemReport()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "problem report")
+		require.Nil(t, report)
+	})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MessageHandler handles inbound messages of the message type namespace it is registered for.
+type MessageHandler interface {
+	InboundHandler
+}
+
+// Registry maintains message handlers keyed by message type namespace
+// (e.g. "https://didcomm.org/basicmessage/1.0/"). It allows applications to handle custom protocols
+// without implementing a protocol service. Messages of unrecognized types fall through to the default handler.
+type Registry struct {
+	handlers       map[string]MessageHandler
+	defaultHandler MessageHandler
+	lock           sync.RWMutex
+}
+
+// NewRegistry returns new message handler registry.
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]MessageHandler)}
+}
+
+// Register registers the message handler for the given message type namespace,
+// returns error in case the namespace is already registered.
+func (r *Registry) Register(namespace string, handler MessageHandler) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.handlers[namespace]; ok {
+		return fmt.Errorf("message handler for namespace `%s` already registered", namespace)
+	}
+
+	r.handlers[namespace] = handler
+
+	return nil
+}
+
+// Unregister unregisters the message handler of the given message type namespace,
+// returns error if no handler is registered for the namespace.
+func (r *Registry) Unregister(namespace string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.handlers[namespace]; !ok {
+		return fmt.Errorf("message handler for namespace `%s` is not registered", namespace)
+	}
+
+	delete(r.handlers, namespace)
+
+	return nil
+}
+
+// SetDefault sets the handler for messages of unrecognized types (nil removes it).
+func (r *Registry) SetDefault(handler MessageHandler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.defaultHandler = handler
+}
+
+// Handler returns the message handler registered for the namespace of the given message type.
+// If several namespaces match, the longest one wins.
+func (r *Registry) Handler(msgType string) (MessageHandler, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var (
+		handler MessageHandler
+		matched string
+	)
+
+	for namespace, h := range r.handlers {
+		if strings.HasPrefix(msgType, namespace) && len(namespace) > len(matched) {
+			handler, matched = h, namespace
+		}
+	}
+
+	return handler, handler != nil
+}
+
+// DefaultHandler returns the handler for messages of unrecognized types (if any).
+func (r *Registry) DefaultHandler() (MessageHandler, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.defaultHandler, r.defaultHandler != nil
+}
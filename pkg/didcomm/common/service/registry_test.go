@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockMessageHandler struct {
+	name string
+}
+
+func (m *mockMessageHandler) HandleInbound(DIDCommMsg, string, string) (string, error) {
+	return m.name, nil
+}
+
+func TestRegistry(t *testing.T) {
+	const (
+		basicMsgNamespace = "https://didcomm.org/basicmessage/"
+		basicMsgType      = "https://didcomm.org/basicmessage/1.0/message"
+	)
+
+	t.Run("register and unregister", func(t *testing.T) {
+		r := NewRegistry()
+
+		h, ok := r.Handler(basicMsgType)
+		require.False(t, ok)
+		require.Nil(t, h)
+
+		basic := &mockMessageHandler{name: "basic"}
+		require.NoError(t, r.Register(basicMsgNamespace, basic))
+
+		err := r.Register(basicMsgNamespace, &mockMessageHandler{})
+		require.EqualError(t, err, "message handler for namespace `https://didcomm.org/basicmessage/` already registered")
+
+		h, ok = r.Handler(basicMsgType)
+		require.True(t, ok)
+		require.Equal(t, basic, h)
+
+		_, ok = r.Handler("https://didcomm.org/trust_ping/1.0/ping")
+		require.False(t, ok)
+
+		require.NoError(t, r.Unregister(basicMsgNamespace))
+
+		_, ok = r.Handler(basicMsgType)
+		require.False(t, ok)
+
+		err = r.Unregister(basicMsgNamespace)
+		require.EqualError(t, err, "message handler for namespace `https://didcomm.org/basicmessage/` is not registered")
+	})
+
+	t.Run("longest namespace wins", func(t *testing.T) {
+		r := NewRegistry()
+
+		generic := &mockMessageHandler{name: "generic"}
+		basic := &mockMessageHandler{name: "basic"}
+
+		require.NoError(t, r.Register("https://didcomm.org/", generic))
+		require.NoError(t, r.Register(basicMsgNamespace, basic))
+
+		h, ok := r.Handler(basicMsgType)
+		require.True(t, ok)
+		require.Equal(t, basic, h)
+
+		h, ok = r.Handler("https://didcomm.org/trust_ping/1.0/ping")
+		require.True(t, ok)
+		require.Equal(t, generic, h)
+	})
+
+	t.Run("default handler", func(t *testing.T) {
+		r := NewRegistry()
+
+		h, ok := r.DefaultHandler()
+		require.False(t, ok)
+		require.Nil(t, h)
+
+		fallback := &mockMessageHandler{name: "default"}
+		r.SetDefault(fallback)
+
+		h, ok = r.DefaultHandler()
+		require.True(t, ok)
+		require.Equal(t, fallback, h)
+
+		// the default handler is not returned for namespace lookups
+		_, ok = r.Handler(basicMsgType)
+		require.False(t, ok)
+
+		r.SetDefault(nil)
+
+		_, ok = r.DefaultHandler()
+		require.False(t, ok)
+	})
+}
@@ -6,6 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 
 package transport
 
+const (
+	// MediaTypeV1Envelope is the media type of DIDComm v1 (legacy) encrypted envelope
+	MediaTypeV1Envelope = "JWM/1.0"
+
+	// MediaTypeV2Envelope is the media type of JWE (authcrypt) encrypted envelope,
+	// it is the `typ` of the envelope header which other Aries agents expect
+	MediaTypeV2Envelope = "prs.hyperledger.aries-auth-message"
+)
+
 // Envelope holds message data and metadata for inbound and outbound messaging
 type Envelope struct {
 	Message    []byte
@@ -16,4 +25,7 @@ type Envelope struct {
 	ToVerKey []byte
 	FromDID  string
 	ToDID    string
+	// MediaType selects the envelope format of an outbound message (primary packer's one if empty)
+	// and holds the detected envelope format of an inbound message
+	MediaType string
 }
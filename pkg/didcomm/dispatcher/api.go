@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"context"
+
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 )
 
@@ -31,9 +33,15 @@ type Outbound interface {
 	// Sends the message after packing with the sender key and recipient keys.
 	Send(interface{}, string, *service.Destination) error
 
+	// Sends the message after packing with the sender key and recipient keys, the sending is aborted once ctx is done.
+	SendWithContext(ctx context.Context, msg interface{}, senderVerKey string, des *service.Destination) error
+
 	// Sends the message after packing with the keys derived from DIDs.
 	SendToDID(msg interface{}, myDID, theirDID string) error
 
+	// Sends the message after packing with the keys derived from DIDs, the sending is aborted once ctx is done.
+	SendToDIDWithContext(ctx context.Context, msg interface{}, myDID, theirDID string) error
+
 	// Forward forwards the message without packing to the destination.
 	Forward(interface{}, *service.Destination) error
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -54,6 +55,12 @@ func NewOutbound(prov provider) *OutboundDispatcher {
 
 // SendToDID sends a message from myDID to the agent who owns theirDID
 func (o *OutboundDispatcher) SendToDID(msg interface{}, myDID, theirDID string) error {
+	return o.SendToDIDWithContext(context.Background(), msg, myDID, theirDID)
+}
+
+// SendToDIDWithContext sends a message from myDID to the agent who owns theirDID,
+// the sending is aborted once ctx is done
+func (o *OutboundDispatcher) SendToDIDWithContext(ctx context.Context, msg interface{}, myDID, theirDID string) error {
 	dest, err := service.GetDestination(theirDID, o.vdRegistry)
 	if err != nil {
 		return err
@@ -69,11 +76,18 @@ func (o *OutboundDispatcher) SendToDID(msg interface{}, myDID, theirDID string)
 	// TODO: relies on hardcoded key type
 	key := src.RecipientKeys[0]
 
-	return o.Send(msg, key, dest)
+	return o.SendWithContext(ctx, msg, key, dest)
 }
 
 // Send sends the message after packing with the sender key and recipient keys.
 func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *service.Destination) error {
+	return o.SendWithContext(context.Background(), msg, senderVerKey, des)
+}
+
+// SendWithContext sends the message after packing with the sender key and recipient keys,
+// the sending is aborted once ctx is done.
+func (o *OutboundDispatcher) SendWithContext(ctx context.Context, msg interface{}, senderVerKey string,
+	des *service.Destination) error {
 	for _, v := range o.outboundTransports {
 		// check if outbound accepts routing keys, else use recipient keys
 		keys := des.RecipientKeys
@@ -104,15 +118,18 @@ func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *ser
 			return fmt.Errorf("failed to pack msg: %w", err)
 		}
 
-		// set the return route option
+		// set the return route option, the option of the message takes precedence over the framework one
 		des.TransportReturnRoute = o.transportReturnRoute
+		if returnRoute, ok := messageReturnRoute(req); ok {
+			des.TransportReturnRoute = returnRoute
+		}
 
 		packedMsg, err = o.createForwardMessage(packedMsg, des)
 		if err != nil {
 			return fmt.Errorf("create forward msg : %w", err)
 		}
 
-		_, err = v.Send(packedMsg, des)
+		_, err = v.SendWithContext(ctx, packedMsg, des)
 		if err != nil {
 			return fmt.Errorf("failed to send msg using outbound transport: %w", err)
 		}
@@ -197,6 +214,11 @@ func (o *OutboundDispatcher) addTransportRouteOptions(req []byte, des *service.D
 		return req, nil
 	}
 
+	// dont override transport route options set on the message (e.g. via DIDCommMsgMap.SetReturnRoute)
+	if _, ok := messageReturnRoute(req); ok {
+		return req, nil
+	}
+
 	if o.transportReturnRoute == decorator.TransportReturnRouteAll ||
 		o.transportReturnRoute == decorator.TransportReturnRouteThread {
 		// create the decorator with the option set in the framework
@@ -217,3 +239,14 @@ func (o *OutboundDispatcher) addTransportRouteOptions(req []byte, des *service.D
 
 	return req, nil
 }
+
+// messageReturnRoute returns the return route option of the message ~transport decorator.
+func messageReturnRoute(req []byte) (string, bool) {
+	trans := &decorator.Transport{}
+
+	if err := json.Unmarshal(req, trans); err != nil || trans.ReturnRoute == nil {
+		return "", false
+	}
+
+	return trans.ReturnRoute.Value, true
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,6 +38,19 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 		require.NoError(t, o.Send("data", "", &service.Destination{ServiceEndpoint: "url"}))
 	})
 
+	t.Run("test send with canceled context", func(t *testing.T) {
+		o := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{}},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := o.SendWithContext(ctx, "data", "", &service.Destination{ServiceEndpoint: "url"})
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
 	t.Run("test no outbound transport found", func(t *testing.T) {
 		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: false}}})
@@ -141,6 +155,22 @@ func TestOutboundDispatcher_SendToDID(t *testing.T) {
 		require.NoError(t, o.SendToDID("data", "", ""))
 	})
 
+	t.Run("canceled context", func(t *testing.T) {
+		o := NewOutbound(&mockProvider{
+			packagerValue: &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
+			vdriRegistry: &mockvdri.MockVDRIRegistry{
+				ResolveValue: mockDoc,
+			},
+			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{}},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := o.SendToDIDWithContext(ctx, "data", "", "")
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
 	t.Run("resolve err", func(t *testing.T) {
 		o := NewOutbound(&mockProvider{
 			packagerValue: &mockpackager.Packager{},
@@ -235,6 +265,26 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 		require.NoError(t, o.Send(req, "", &service.Destination{ServiceEndpoint: "url"}))
 	})
 
+	t.Run("transport route option - message value takes precedence", func(t *testing.T) {
+		req := service.DIDCommMsgMap{"@id": uuid.New().String()}
+		req.SetReturnRoute(decorator.TransportReturnRouteAll)
+
+		expectedRequest, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		o := NewOutbound(&mockProvider{
+			packagerValue: &mockPackager{},
+			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{
+				expectedRequest: string(expectedRequest)},
+			},
+			transportReturnRoute: decorator.TransportReturnRouteNone,
+		})
+
+		des := &service.Destination{ServiceEndpoint: "url"}
+		require.NoError(t, o.Send(req, "", des))
+		require.Equal(t, decorator.TransportReturnRouteAll, des.TransportReturnRoute)
+	})
+
 	t.Run("transport route option - forward message", func(t *testing.T) {
 		transportReturnRoute := "thread"
 		o := NewOutbound(&mockProvider{
@@ -337,6 +387,15 @@ func (o *mockOutboundTransport) Send(data []byte, destination *service.Destinati
 	return "", nil
 }
 
+func (o *mockOutboundTransport) SendWithContext(ctx context.Context, data []byte,
+	destination *service.Destination) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return o.Send(data, destination)
+}
+
 func (o *mockOutboundTransport) AcceptRecipient([]string) bool {
 	return o.acceptRecipient
 }
@@ -372,14 +431,26 @@ func (m *mockKMS) CreateKeySet() (string, string, error) {
 	return m.CreateEncryptionKeyValue, m.CreateSigningKeyValue, m.CreateKeyErr
 }
 
+func (m *mockKMS) CreateKeyAgreementKey() (string, []byte, error) {
+	return m.CreateEncryptionKeyValue, nil, m.CreateKeyErr
+}
+
 func (m *mockKMS) FindVerKey(candidateKeys []string) (int, error) {
 	return 0, nil
 }
 
+func (m *mockKMS) FindEncKey(candidateKeys []string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockKMS) SignMessage(message []byte, fromVerKey string) ([]byte, error) {
 	return nil, nil
 }
 
+func (m *mockKMS) VerifyMessage(message, signature []byte, fromVerKey string) error {
+	return nil
+}
+
 func (m *mockKMS) DeriveKEK(alg, apu, fromKey, toPubKey []byte) ([]byte, error) {
 	return nil, nil
 }
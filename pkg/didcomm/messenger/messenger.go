@@ -7,22 +7,43 @@ SPDX-License-Identifier: Apache-2.0
 package messenger
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
 	messengerStore = "messenger_store"
-
-	metadataKey = "metadata_%s"
+	// pendingStore keeps the messages queued for re-delivery apart from the records of the inbound messages,
+	// which are keyed by the message IDs chosen by the senders
+	pendingStore = "messenger_pending_store"
+
+	metadataKey = metadataPrefix + "%s"
+	// metadataPrefix is a key prefix of the metadata entries
+	metadataPrefix = "metadata_"
+	// pendingRootPrefix is a key prefix of all queued messages
+	pendingRootPrefix = "pending|"
+	// pendingPrefix is a key prefix of the messages queued for re-delivery to the given connection (myDID, theirDID)
+	pendingPrefix = "pending|%s|%s|"
+	// pendingKey is a key of the queued message, the timestamp keeps the order of messages
+	pendingKey = "%s%020d|%s"
+	// limitPattern is a limit of the key range for the given prefix
+	limitPattern = "%s~"
 
 	jsonID             = "@id"
 	jsonThread         = "~thread"
@@ -33,6 +54,20 @@ const (
 
 var logger = log.New("aries-framework/didcomm/messenger")
 
+var (
+	// ErrMissingMessageID is returned when an inbound message does not have an ID
+	ErrMissingMessageID = errors.New("message-id is absent")
+	// ErrThreadNotFound is returned when threadID can't be determined for the message
+	ErrThreadNotFound = errors.New("thread not found")
+	// ErrRecordNotFound is returned when there is no record of the message (e.g. the message was not received yet)
+	ErrRecordNotFound = errors.New("record not found")
+	// ErrEncryptionRequired is returned when the message of the connection which requires encryption
+	// would be sent unencrypted (see WithEncryptionPolicy)
+	ErrEncryptionRequired = errors.New("encryption is required for the connection")
+	// ErrDIDResolverNotSet is returned when the message is sent to DID by the Messenger without DID resolver
+	ErrDIDResolverNotSet = errors.New("DID resolver is not set")
+)
+
 // record is an internal structure and keeps payload about inbound message
 type record struct {
 	MyDID          string                 `json:"my_did,omitempty"`
@@ -42,36 +77,207 @@ type record struct {
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// pendingMessage is an internal structure and keeps outbound message which is queued for re-delivery
+type pendingMessage struct {
+	Message service.DIDCommMsgMap `json:"message"`
+}
+
 // Provider contains dependencies for the Messenger
 type Provider interface {
 	OutboundDispatcher() dispatcher.Outbound
 	StorageProvider() storage.Provider
 }
 
+// didLookup resolves the current DIDs of a connection which parties might have rotated their DIDs
+type didLookup interface {
+	GetCurrentDIDs(myDID, theirDID string) (string, string, error)
+}
+
+// connectionLookup finds the connection record of the connection parties
+type connectionLookup interface {
+	GetConnectionRecordByDIDs(myDID, theirDID string) (*connection.Record, error)
+}
+
+// didResolver resolves DIDs to DID Docs (e.g. vdri.Registry)
+type didResolver interface {
+	Resolve(did string, opts ...vdriapi.ResolveOpts) (*did.Doc, error)
+}
+
+// EncryptionCheck reports if the outbound messages from myDID to theirDID are encrypted
+type EncryptionCheck func(myDID, theirDID string) bool
+
 // Messenger describes the messenger structure
 type Messenger struct {
-	store      storage.Store
-	dispatcher dispatcher.Outbound
+	store       storage.Store
+	pending     storage.Store
+	dispatcher  dispatcher.Outbound
+	dropExpired bool
+	retryQueue  bool
+	clock       clock.Clock
+	didLookup   didLookup
+	codec       RecordCodec
+	waiters     map[string][]*ReplyWaiter
+	waitersLock sync.Mutex
+	pendingLock sync.Mutex
+	connections connectionLookup
+	encrypted   EncryptionCheck
+	schemas     *service.MessageSchemas
+	resolver    didResolver
+	middlewares []service.Middleware
+	inbound     service.InboundFunc
+}
+
+// RecordCodec serializes the records the Messenger keeps in its store (message metadata and queued messages)
+type RecordCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// jsonCodec is the default RecordCodec
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Opt is a Messenger option
+type Opt func(m *Messenger)
+
+// WithDropExpiredMessages drops inbound messages whose ~timing.expires_time has passed.
+// HandleInbound returns service.ErrMessageExpired for such messages.
+func WithDropExpiredMessages() Opt {
+	return func(m *Messenger) {
+		m.dropExpired = true
+	}
+}
+
+// WithRetryQueue keeps outbound messages which failed to be sent in the store of the Messenger (the separate
+// one from the records of the inbound messages), so they can be re-delivered later by RetryPending.
+// The sending error is still returned to the caller.
+func WithRetryQueue() Opt {
+	return func(m *Messenger) {
+		m.retryQueue = true
+	}
+}
+
+// WithClock sets the time source of the Messenger (e.g. to check message expiry).
+// The real-time clock is used by default.
+func WithClock(c clock.Clock) Opt {
+	return func(m *Messenger) {
+		m.clock = c
+	}
+}
+
+// WithDIDLookup makes the Messenger reply using the current DIDs of the connection parties, i.e. the DIDs
+// are looked up in case they were rotated after the message was received (e.g. connection.Lookup).
+func WithDIDLookup(l didLookup) Opt {
+	return func(m *Messenger) {
+		m.didLookup = l
+	}
+}
+
+// WithEncryptionPolicy enforces the encryption policy of the connections (see connection.Record RequireEncryption).
+// The connection records are found by connections (e.g. connection.Lookup), the message of the connection
+// which requires encryption is not sent if encrypted reports that the outbound path would send it unencrypted,
+// ErrEncryptionRequired is returned instead. The messages of the parties without connection record are not checked.
+func WithEncryptionPolicy(connections connectionLookup, encrypted EncryptionCheck) Opt {
+	return func(m *Messenger) {
+		m.connections = connections
+		m.encrypted = encrypted
+	}
+}
+
+// WithMessageSchemas validates the inbound messages against the JSON schemas registered for their types,
+// HandleInbound returns service.InvalidMessageError listing the schema violations of the invalid message.
+func WithMessageSchemas(schemas *service.MessageSchemas) Opt {
+	return func(m *Messenger) {
+		m.schemas = schemas
+	}
+}
+
+// WithDIDResolver sets the resolver of DIDs the messages are sent to by SendToDID (e.g. vdri.Registry).
+func WithDIDResolver(r didResolver) Opt {
+	return func(m *Messenger) {
+		m.resolver = r
+	}
+}
+
+// WithMiddleware wraps the handling of the inbound messages by the middlewares (e.g. logging, authorization),
+// the first middleware is the first to see the message. The option can be used several times,
+// the middlewares are appended to the chain in order.
+func WithMiddleware(middlewares ...service.Middleware) Opt {
+	return func(m *Messenger) {
+		m.middlewares = append(m.middlewares, middlewares...)
+	}
+}
+
+// WithRecordCodec sets the codec of the records the Messenger keeps in its store, e.g. a more compact one
+// than JSON which is used by default. The codec must not be changed for the existing store.
+func WithRecordCodec(codec RecordCodec) Opt {
+	return func(m *Messenger) {
+		m.codec = codec
+	}
 }
 
 // NewMessenger returns a new instance of the Messenger
-func NewMessenger(ctx Provider) (*Messenger, error) {
-	store, err := ctx.StorageProvider().OpenStore(messengerStore)
+func NewMessenger(ctx Provider, opts ...Opt) (*Messenger, error) {
+	storageProvider := ctx.StorageProvider()
+
+	store, err := storageProvider.OpenStore(messengerStore)
 	if err != nil {
 		return nil, fmt.Errorf("open store: %w", err)
 	}
 
-	return &Messenger{
+	m := &Messenger{
 		store:      store,
 		dispatcher: ctx.OutboundDispatcher(),
-	}, nil
+		clock:      clock.Real(),
+		codec:      jsonCodec{},
+		waiters:    make(map[string][]*ReplyWaiter),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.retryQueue {
+		m.pending, err = storageProvider.OpenStore(pendingStore)
+		if err != nil {
+			return nil, fmt.Errorf("open pending store: %w", err)
+		}
+	}
+
+	m.inbound = service.Chain(m.handleInbound, m.middlewares...)
+
+	return m, nil
 }
 
-// HandleInbound handles all inbound messages
+// HandleInbound handles all inbound messages, the message is passed through the middlewares first
+// (see WithMiddleware).
 func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.inbound(msg, myDID, theirDID)
+}
+
+func (m *Messenger) handleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
 	// an incoming message cannot be without id
 	if msg.ID() == "" {
-		return errors.New("message-id is absent and can't be processed")
+		return fmt.Errorf("%w and can't be processed", ErrMissingMessageID)
+	}
+
+	if m.dropExpired && msg.Expired(m.clock.Now()) {
+		logger.Warnf("message %s expired at %s and will be dropped", msg.ID(), msg.Timing().ExpiresTime)
+
+		return service.ErrMessageExpired
+	}
+
+	if m.schemas != nil {
+		if err := m.schemas.Validate(msg); err != nil {
+			return err
+		}
 	}
 
 	// get message threadID
@@ -79,7 +285,7 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID str
 	if err != nil {
 		// since we are checking ID above this should never happen
 		// even if ~thread decorator is absent the message ID should be returned as a threadID
-		return fmt.Errorf("threadID: %w", err)
+		return fmt.Errorf("threadID: %w: %v", ErrThreadNotFound, err)
 	}
 
 	var parentThreadID string
@@ -95,12 +301,19 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID str
 	}
 
 	// saves message payload
-	return m.saveRecord(msg.ID(), record{
+	err = m.saveRecord(msg.ID(), record{
 		ParentThreadID: parentThreadID,
 		MyDID:          myDID,
 		TheirDID:       theirDID,
 		ThreadID:       thID,
 	})
+	if err != nil {
+		return err
+	}
+
+	m.notifyWaiters(thID, myDID, theirDID, msg)
+
+	return nil
 }
 
 func (m *Messenger) saveMetadata(msg service.DIDCommMsgMap) error {
@@ -111,7 +324,7 @@ func (m *Messenger) saveMetadata(msg service.DIDCommMsgMap) error {
 
 	thID, err := msg.ThreadID()
 	if err != nil {
-		return fmt.Errorf("threadID: %w", err)
+		return fmt.Errorf("threadID: %w: %v", ErrThreadNotFound, err)
 	}
 
 	delete(msg, jsonMetadata)
@@ -121,7 +334,7 @@ func (m *Messenger) saveMetadata(msg service.DIDCommMsgMap) error {
 
 func (m *Messenger) populateMetadata(thID string, msg service.DIDCommMsgMap) error {
 	rec, err := m.getRecord(fmt.Sprintf(metadataKey, thID))
-	if errors.Is(err, storage.ErrDataNotFound) {
+	if errors.Is(err, ErrRecordNotFound) {
 		return nil
 	}
 
@@ -141,7 +354,17 @@ func (m *Messenger) populateMetadata(thID string, msg service.DIDCommMsgMap) err
 // Send sends the message by starting a new thread.
 // Do not provide a message with ~thread decorator. It will be removed.
 // Use ReplyTo function instead. It will keep ~thread decorator automatically.
+// The given message is not modified, the copy of it is sent.
 func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.SendWithContext(context.Background(), msg, myDID, theirDID)
+}
+
+// SendWithContext sends the message by starting a new thread, the sending is aborted once ctx is done.
+// See Send for the details.
+func (m *Messenger) SendWithContext(ctx context.Context, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	// the message of the caller is not modified
+	msg = msg.Clone()
+
 	// fills missing fields
 	fillIfMissing(msg)
 
@@ -153,13 +376,73 @@ func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) erro
 		logger.Warnf("do not pass message with %s decorator, it will be ignored with the next change", jsonThread)
 	}
 
-	return m.dispatcher.SendToDID(msg, myDID, theirDID)
+	return m.send(ctx, msg, myDID, theirDID)
+}
+
+// SendToDID sends the message by starting a new thread to the DIDComm service of theirDID resolved
+// on the fly by the resolver the Messenger is created with (see WithDIDResolver), e.g. to reply
+// to an out-of-band request before the connection exists. The message is sent once, i.e. neither
+// the connection record nor the re-delivery queue is involved.
+// The given message is not modified, the copy of it is sent.
+func (m *Messenger) SendToDID(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.SendToDIDWithContext(context.Background(), msg, myDID, theirDID)
+}
+
+// SendToDIDWithContext sends the message to theirDID resolved on the fly, the sending is aborted once ctx is done.
+// See SendToDID for the details.
+func (m *Messenger) SendToDIDWithContext(ctx context.Context, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if m.resolver == nil {
+		return fmt.Errorf("send to %s: %w", theirDID, ErrDIDResolverNotSet)
+	}
+
+	dest, err := m.resolveDestination(theirDID)
+	if err != nil {
+		return fmt.Errorf("send to %s: %w", theirDID, err)
+	}
+
+	src, err := m.resolveDestination(myDID)
+	if err != nil {
+		return fmt.Errorf("send to %s: sender: %w", theirDID, err)
+	}
+
+	// the message of the caller is not modified
+	msg = msg.Clone()
+
+	fillIfMissing(msg)
+
+	if err = m.saveMetadata(msg); err != nil {
+		return fmt.Errorf("save metadata: %w", err)
+	}
+
+	// the first recipient key of the sender DIDComm service is used as the sender key
+	return m.dispatcher.SendWithContext(ctx, msg, src.RecipientKeys[0], dest)
+}
+
+// resolveDestination resolves the DID and makes the destination from its DIDComm service
+func (m *Messenger) resolveDestination(didID string) (*service.Destination, error) {
+	doc, err := m.resolver.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", didID, err)
+	}
+
+	return service.NewDestinationFromDID(doc)
 }
 
 // ReplyTo replies to the message by given msgID.
 // The function adds ~thread decorator to the message according to the given msgID.
+// The reply is sent using the current DIDs of the connection if the Messenger is created WithDIDLookup.
+// The given message is not modified, the copy of it is sent.
 // Do not provide a message with ~thread decorator. It will be rewritten.
 func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
+	return m.ReplyToWithContext(context.Background(), msgID, msg)
+}
+
+// ReplyToWithContext replies to the message by given msgID, the sending is aborted once ctx is done.
+// See ReplyTo for the details.
+func (m *Messenger) ReplyToWithContext(ctx context.Context, msgID string, msg service.DIDCommMsgMap) error {
+	// the message of the caller is not modified
+	msg = msg.Clone()
+
 	// fills missing fields
 	fillIfMissing(msg)
 
@@ -188,14 +471,39 @@ func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
 
 	msg[jsonThread] = thread
 
-	return m.dispatcher.SendToDID(msg, rec.MyDID, rec.TheirDID)
+	myDID, theirDID, err := m.currentDIDs(rec.MyDID, rec.TheirDID)
+	if err != nil {
+		return fmt.Errorf("current DIDs: %w", err)
+	}
+
+	return m.send(ctx, msg, myDID, theirDID)
+}
+
+// currentDIDs returns the current DIDs of the connection parties (if DID lookup is defined)
+func (m *Messenger) currentDIDs(myDID, theirDID string) (string, string, error) {
+	if m.didLookup == nil {
+		return myDID, theirDID, nil
+	}
+
+	return m.didLookup.GetCurrentDIDs(myDID, theirDID)
 }
 
 // ReplyToNested sends the message by starting a new thread.
 // Do not provide a message with ~thread decorator. It will be rewritten.
 // The function adds ~thread decorator to the message according to the given threadID.
 // NOTE: Given threadID becomes parent threadID.
+// The given message is not modified, the copy of it is sent.
 func (m *Messenger) ReplyToNested(threadID string, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.ReplyToNestedWithContext(context.Background(), threadID, msg, myDID, theirDID)
+}
+
+// ReplyToNestedWithContext sends the message by starting a new thread, the sending is aborted once ctx is done.
+// See ReplyToNested for the details.
+func (m *Messenger) ReplyToNestedWithContext(ctx context.Context, threadID string, msg service.DIDCommMsgMap,
+	myDID, theirDID string) error {
+	// the message of the caller is not modified
+	msg = msg.Clone()
+
 	// fills missing fields
 	fillIfMissing(msg)
 
@@ -210,7 +518,197 @@ func (m *Messenger) ReplyToNested(threadID string, msg service.DIDCommMsgMap, my
 	// sets parent threadID
 	msg[jsonThread] = map[string]interface{}{jsonParentThreadID: threadID}
 
-	return m.dispatcher.SendToDID(msg, myDID, theirDID)
+	return m.send(ctx, msg, myDID, theirDID)
+}
+
+// RetryPending re-attempts delivery of the messages queued for the given connection (see WithRetryQueue).
+// The messages are sent in the order they were queued. Successfully sent messages are removed from the queue,
+// the re-delivery stops on the first failure to keep the order, the rest of the messages stay queued.
+// It can be called periodically or when the connection is re-established.
+func (m *Messenger) RetryPending(myDID, theirDID string) error {
+	// the queue is processed by one caller at a time, so the message is not re-delivered twice
+	m.pendingLock.Lock()
+	defer m.pendingLock.Unlock()
+
+	prefix := fmt.Sprintf(pendingPrefix, myDID, theirDID)
+
+	records, err := m.pendingRecords(prefix)
+	if err != nil {
+		return fmt.Errorf("retry pending: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	// the policy might have changed since the messages were queued
+	if err = m.checkEncryptionPolicy(myDID, theirDID); err != nil {
+		return fmt.Errorf("retry pending: %w", err)
+	}
+
+	for _, rec := range records {
+		var pending pendingMessage
+		if err = m.codec.Unmarshal(rec.value, &pending); err != nil {
+			return fmt.Errorf("retry pending: unmarshal message: %w", err)
+		}
+
+		err = m.dispatcher.SendToDIDWithContext(context.Background(), pending.Message, myDID, theirDID)
+		if err != nil {
+			return fmt.Errorf("retry pending: send message %s: %w", pending.Message.ID(), err)
+		}
+
+		if err = m.pending.Delete(rec.key); err != nil {
+			return fmt.Errorf("retry pending: delete message %s: %w", pending.Message.ID(), err)
+		}
+	}
+
+	return nil
+}
+
+// OnConnectionActive re-delivers the messages queued for all connections with the given party
+// (see RetryPending). It is supposed to be called by the transport when the party is reachable again,
+// e.g. an intermittently connected mobile agent is back online. The call is idempotent, the delivered messages
+// are removed from the queue, as well as the messages which were sent successfully by the caller again.
+func (m *Messenger) OnConnectionActive(theirDID string) error {
+	records, err := m.pendingRecords(pendingRootPrefix)
+	if err != nil {
+		return fmt.Errorf("on connection active: %w", err)
+	}
+
+	var myDIDs []string
+
+	seen := make(map[string]bool)
+
+	for _, rec := range records {
+		// the key is pending|myDID|theirDID|timestamp|msgID
+		parts := strings.SplitN(strings.TrimPrefix(rec.key, pendingRootPrefix), "|", 3)
+		if len(parts) < 3 || parts[1] != theirDID || seen[parts[0]] {
+			continue
+		}
+
+		seen[parts[0]] = true
+		myDIDs = append(myDIDs, parts[0])
+	}
+
+	for _, myDID := range myDIDs {
+		if err = m.RetryPending(myDID, theirDID); err != nil {
+			return fmt.Errorf("on connection active: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeDelivered removes the queued copies of the message which was delivered successfully
+func (m *Messenger) removeDelivered(msgID, myDID, theirDID string) error {
+	m.pendingLock.Lock()
+	defer m.pendingLock.Unlock()
+
+	records, err := m.pendingRecords(fmt.Sprintf(pendingPrefix, myDID, theirDID))
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		if !strings.HasSuffix(rec.key, "|"+msgID) {
+			continue
+		}
+
+		if err = m.pending.Delete(rec.key); err != nil {
+			return fmt.Errorf("delete message %s: %w", msgID, err)
+		}
+	}
+
+	return nil
+}
+
+type storeRecord struct {
+	key   string
+	value []byte
+}
+
+// pendingRecords returns the queued messages with the given key prefix sorted by key (i.e. in the queue order),
+// nothing is queued if the retry queue is not enabled
+func (m *Messenger) pendingRecords(prefix string) ([]storeRecord, error) {
+	if m.pending == nil {
+		return nil, nil
+	}
+
+	itr := m.pending.Iterator(prefix, fmt.Sprintf(limitPattern, prefix))
+	defer itr.Release()
+
+	var records []storeRecord
+
+	for itr.Next() {
+		records = append(records, storeRecord{
+			key:   string(itr.Key()),
+			value: append([]byte(nil), itr.Value()...),
+		})
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("iterate store: %w", err)
+	}
+
+	// not every store iterates keys in order
+	sort.Slice(records, func(i, j int) bool { return records[i].key < records[j].key })
+
+	return records, nil
+}
+
+// send sends the message and queues it for re-delivery in case of failure (if enabled)
+func (m *Messenger) send(ctx context.Context, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if err := m.checkEncryptionPolicy(myDID, theirDID); err != nil {
+		return err
+	}
+
+	err := m.dispatcher.SendToDIDWithContext(ctx, msg, myDID, theirDID)
+	if !m.retryQueue {
+		return err
+	}
+
+	if err == nil {
+		// the message might be queued after the previous attempt, it must not be re-delivered
+		if rErr := m.removeDelivered(msg.ID(), myDID, theirDID); rErr != nil {
+			logger.Warnf("remove delivered message %s from re-delivery queue: %v", msg.ID(), rErr)
+		}
+
+		return nil
+	}
+
+	src, qErr := m.codec.Marshal(pendingMessage{Message: msg})
+	if qErr == nil {
+		key := fmt.Sprintf(pendingKey, fmt.Sprintf(pendingPrefix, myDID, theirDID), m.clock.Now().UnixNano(), msg.ID())
+		qErr = m.pending.Put(key, src)
+	}
+
+	if qErr != nil {
+		return fmt.Errorf("%w (queue for re-delivery: %v)", err, qErr)
+	}
+
+	return err
+}
+
+// checkEncryptionPolicy checks that the message of the connection which requires encryption is encrypted
+func (m *Messenger) checkEncryptionPolicy(myDID, theirDID string) error {
+	if m.connections == nil || m.encrypted == nil {
+		return nil
+	}
+
+	record, err := m.connections.GetConnectionRecordByDIDs(myDID, theirDID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("check encryption policy: %w", err)
+	}
+
+	if record.RequireEncryption && !m.encrypted(myDID, theirDID) {
+		return fmt.Errorf("send to %s: %w", theirDID, ErrEncryptionRequired)
+	}
+
+	return nil
 }
 
 // fillIfMissing populates message with common fields such as ID
@@ -224,12 +722,16 @@ func fillIfMissing(msg service.DIDCommMsgMap) {
 // getRecord returns message payload by msgID
 func (m *Messenger) getRecord(msgID string) (*record, error) {
 	src, err := m.store.Get(msgID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrRecordNotFound, msgID)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("store get: %w", err)
 	}
 
 	var r *record
-	if err = json.Unmarshal(src, &r); err != nil {
+	if err = m.codec.Unmarshal(src, &r); err != nil {
 		return nil, fmt.Errorf("unmarshal record: %w", err)
 	}
 
@@ -238,7 +740,7 @@ func (m *Messenger) getRecord(msgID string) (*record, error) {
 
 // saveRecord saves incoming message payload
 func (m *Messenger) saveRecord(msgID string, rec record) error {
-	src, err := json.Marshal(rec)
+	src, err := m.codec.Marshal(rec)
 	if err != nil {
 		return fmt.Errorf("marshal record: %w", err)
 	}
@@ -7,19 +7,31 @@ SPDX-License-Identifier: Apache-2.0
 package messenger
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
 	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -104,6 +116,94 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
 	})
 
+	t.Run("expired message is dropped", func(t *testing.T) {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+		msgr, err := NewMessenger(provider, WithDropExpiredMessages(), WithClock(clock.Fixed(now)))
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		msg := service.DIDCommMsgMap{jsonID: ID}
+		msg.SetTiming(decorator.Timing{ExpiresTime: now.Add(-time.Minute)})
+
+		err = msgr.HandleInbound(msg, myDID, theirDID)
+		require.True(t, errors.Is(err, service.ErrMessageExpired))
+	})
+
+	t.Run("message not matching the schema is rejected", func(t *testing.T) {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		schemas := service.NewMessageSchemas()
+		require.NoError(t, schemas.Register("test", []byte(`{"required": ["comment"]}`)))
+
+		msgr, err := NewMessenger(provider, WithMessageSchemas(schemas))
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		err = msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID, "@type": "test"}, myDID, theirDID)
+		require.True(t, errors.Is(err, service.ErrInvalidMessage))
+		require.Contains(t, err.Error(), "comment is required")
+	})
+
+	t.Run("message matching the schema is handled", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		schemas := service.NewMessageSchemas()
+		require.NoError(t, schemas.Register("test", []byte(`{"required": ["comment"]}`)))
+
+		msgr, err := NewMessenger(provider, WithMessageSchemas(schemas))
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		msg := service.DIDCommMsgMap{jsonID: ID, "@type": "test", "comment": "hello"}
+		require.NoError(t, msgr.HandleInbound(msg, myDID, theirDID))
+	})
+
+	t.Run("not expired message is handled", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+		msgr, err := NewMessenger(provider, WithDropExpiredMessages(), WithClock(clock.Fixed(now)))
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		msg := service.DIDCommMsgMap{jsonID: ID}
+		msg.SetTiming(decorator.Timing{ExpiresTime: now.Add(time.Minute)})
+
+		require.NoError(t, msgr.HandleInbound(msg, myDID, theirDID))
+	})
+
 	t.Run("absent ID", func(t *testing.T) {
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
@@ -118,6 +218,7 @@ func TestMessenger_HandleInbound(t *testing.T) {
 
 		err = msgr.HandleInbound(service.DIDCommMsgMap{}, myDID, theirDID)
 		require.Contains(t, fmt.Sprintf("%v", err), "message-id is absent")
+		require.True(t, errors.Is(err, ErrMissingMessageID))
 	})
 
 	t.Run("metadata with error", func(t *testing.T) {
@@ -229,6 +330,15 @@ func sendToDIDCheck(t *testing.T, checks ...string) func(msg service.DIDCommMsgM
 	}
 }
 
+func sendToDIDWithContextCheck(t *testing.T,
+	checks ...string) func(ctx context.Context, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	check := sendToDIDCheck(t, checks...)
+
+	return func(_ context.Context, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+		return check(msg, myDID, theirDID)
+	}
+}
+
 func TestMessenger_Send(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -238,8 +348,8 @@ func TestMessenger_Send(t *testing.T) {
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), myDID, theirDID).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -252,13 +362,37 @@ func TestMessenger_Send(t *testing.T) {
 		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
 	})
 
+	t.Run("success with context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(ctx, gomock.Any(), myDID, theirDID).Return(context.Canceled)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		cancel()
+
+		err = msgr.SendWithContext(ctx, service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
 	t.Run("success msg without id", func(t *testing.T) {
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), myDID, theirDID).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -272,6 +406,49 @@ func TestMessenger_Send(t *testing.T) {
 		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonThread: map[string]interface{}{}}, myDID, theirDID))
 	})
 
+	t.Run("the message of the caller is not modified", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		var sent []service.DIDCommMsgMap
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(func(_ context.Context, msg interface{}, _, _ string) {
+				sent = append(sent, msg.(service.DIDCommMsgMap))
+			}).Times(2)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		newMsg := func() service.DIDCommMsgMap {
+			return service.DIDCommMsgMap{
+				jsonThread:   map[string]interface{}{jsonThreadID: "thID"},
+				jsonMetadata: map[string]interface{}{"key": "val"},
+			}
+		}
+
+		msg := newMsg()
+
+		require.NoError(t, msgr.Send(msg, myDID, theirDID))
+		require.NoError(t, msgr.Send(msg, myDID, theirDID))
+
+		require.Equal(t, newMsg(), msg)
+
+		require.Len(t, sent, 2)
+		require.NotEmpty(t, sent[0].ID())
+		require.NotEqual(t, sent[0].ID(), sent[1].ID())
+		require.Nil(t, sent[0].Metadata())
+	})
+
 	t.Run("save metadata error", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
 		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New(errMsg))
@@ -295,6 +472,87 @@ func TestMessenger_Send(t *testing.T) {
 	})
 }
 
+func TestMessenger_SendToDID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resolver := &mockvdri.MockVDRIRegistry{ResolveFunc: func(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+		switch didID {
+		case myDID, theirDID:
+			return mockdiddoc.GetMockDIDDoc(), nil
+		case "did:example:no-service":
+			return &did.Doc{ID: didID}, nil
+		default:
+			return nil, vdriapi.ErrNotFound
+		}
+	}}
+
+	newMessenger := func(t *testing.T, outbound dispatcher.Outbound, opts ...Opt) *Messenger {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(mem.NewProvider().OpenStore(messengerStore))
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, opts...)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	t.Run("success", func(t *testing.T) {
+		dest, err := service.NewDestinationFromDID(mockdiddoc.GetMockDIDDoc())
+		require.NoError(t, err)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendWithContext(gomock.Any(), gomock.Any(), dest.RecipientKeys[0], dest).
+			Do(func(_ context.Context, msg interface{}, _ string, _ *service.Destination) error {
+				require.NotEmpty(t, msg.(service.DIDCommMsgMap).ID())
+
+				return nil
+			})
+
+		msg := service.DIDCommMsgMap{jsonMetadata: map[string]interface{}{"key": "value"}}
+
+		msgr := newMessenger(t, outbound, WithDIDResolver(resolver))
+		require.NoError(t, msgr.SendToDID(msg, myDID, theirDID))
+
+		// the message of the caller is not modified
+		require.Empty(t, msg.ID())
+	})
+
+	t.Run("resolver is not set", func(t *testing.T) {
+		msgr := newMessenger(t, dispatcherMocks.NewMockOutbound(ctrl))
+
+		err := msgr.SendToDID(service.DIDCommMsgMap{}, myDID, theirDID)
+		require.True(t, errors.Is(err, ErrDIDResolverNotSet))
+	})
+
+	t.Run("unresolvable DID", func(t *testing.T) {
+		msgr := newMessenger(t, dispatcherMocks.NewMockOutbound(ctrl), WithDIDResolver(resolver))
+
+		err := msgr.SendToDID(service.DIDCommMsgMap{}, myDID, "did:example:unknown")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+
+		err = msgr.SendToDID(service.DIDCommMsgMap{}, "did:example:unknown", theirDID)
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Contains(t, err.Error(), "sender")
+
+		err = msgr.SendToDID(service.DIDCommMsgMap{}, myDID, "did:example:no-service")
+		require.True(t, errors.Is(err, service.ErrNoDIDCommService))
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New(errMsg))
+
+		msgr := newMessenger(t, outbound, WithDIDResolver(resolver))
+		require.EqualError(t, msgr.SendToDID(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID), errMsg)
+	})
+}
+
 func TestMessenger_ReplyTo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -307,8 +565,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata, jsonThreadID, jsonParentThreadID))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata, jsonThreadID, jsonParentThreadID))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -320,6 +578,71 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{jsonID: ID}))
 	})
 
+	t.Run("success with context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(ID).Return([]byte(`{"thread_id":"thID","my_did":"myDID","their_did":"theirDID"}`), nil)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(ctx, gomock.Any(), myDID, theirDID).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonThreadID))
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+		require.NoError(t, msgr.ReplyToWithContext(ctx, ID, service.DIDCommMsgMap{jsonID: ID}))
+	})
+
+	t.Run("success with rotated DIDs", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(ID).Return([]byte(`{"thread_id":"thID","my_did":"myDID","their_did":"theirDID"}`), nil)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), "myRotatedDID", theirDID).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonThreadID))
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, WithDIDLookup(&didLookupStub{
+			rotations: map[string]string{myDID: "myRotatedDID"},
+		}))
+		require.NoError(t, err)
+		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{jsonID: ID}))
+	})
+
+	t.Run("DID lookup error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(ID).Return([]byte(`{"thread_id":"thID","my_did":"myDID","their_did":"theirDID"}`), nil)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider, WithDIDLookup(&didLookupStub{err: errors.New(errMsg)}))
+		require.NoError(t, err)
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{jsonID: ID})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "current DIDs: "+errMsg)
+	})
+
 	t.Run("the message was not received", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
 		store.EXPECT().Get(ID).Return(nil, errors.New(errMsg))
@@ -337,6 +660,26 @@ func TestMessenger_ReplyTo(t *testing.T) {
 
 		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{})
 		require.Contains(t, fmt.Sprintf("%v", err), errMsg)
+		require.False(t, errors.Is(err, ErrRecordNotFound))
+	})
+
+	t.Run("the record of the message is not persisted yet", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(ID).Return(nil, storage.ErrDataNotFound)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{})
+		require.True(t, errors.Is(err, ErrRecordNotFound))
 	})
 
 	t.Run("success msg without id", func(t *testing.T) {
@@ -347,8 +690,8 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata, jsonThreadID))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata, jsonThreadID))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -396,8 +739,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata, jsonParentThreadID))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata, jsonParentThreadID))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -414,8 +757,8 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
 
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
-		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-			Do(sendToDIDCheck(t, jsonID, jsonMetadata, jsonParentThreadID))
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(sendToDIDWithContextCheck(t, jsonID, jsonMetadata, jsonParentThreadID))
 
 		provider := messengerMocks.NewMockProvider(ctrl)
 		provider.EXPECT().StorageProvider().Return(storageProvider)
@@ -452,3 +795,537 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		require.Contains(t, fmt.Sprintf("%v", err), errMsg)
 	})
 }
+
+func TestMessenger_RetryPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T, store storage.Store, outbound *dispatcherMocks.MockOutbound,
+		opts ...Opt) *Messenger {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, opts...)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	t.Run("inbound message IDs do not reach the queue", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, WithRetryQueue())
+		require.NoError(t, err)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		require.EqualError(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID), errMsg)
+
+		queued, err := msgr.PendingMessages(myDID, theirDID, 0)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+
+		// the ID of the inbound message looks like the key of the queued one
+		injectedID := fmt.Sprintf(pendingKey, fmt.Sprintf(pendingPrefix, myDID, theirDID), 0, "injected")
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: injectedID}, myDID, theirDID))
+
+		overwrittenID := fmt.Sprintf(pendingKey, fmt.Sprintf(pendingPrefix, myDID, theirDID), 0, "1")
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: overwrittenID}, myDID, theirDID))
+
+		pending, err := msgr.PendingMessages(myDID, theirDID, 0)
+		require.NoError(t, err)
+		require.Equal(t, queued, pending)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(func(_ context.Context, msg service.DIDCommMsgMap, _, _ string) {
+				require.Equal(t, "1", msg.ID())
+			})
+
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+
+		count, err := msgr.PendingCount(myDID, theirDID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("failed messages are re-delivered in order", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, newMemStore(t), outbound, WithRetryQueue())
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg)).Times(2)
+
+		require.EqualError(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID), errMsg)
+		require.EqualError(t, msgr.Send(service.DIDCommMsgMap{jsonID: "2"}, myDID, theirDID), errMsg)
+
+		// the first message fails again, the second one is not sent to keep the order
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		err := msgr.RetryPending(myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "retry pending: send message 1")
+
+		var sent []string
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(func(_ context.Context, msg service.DIDCommMsgMap, _, _ string) {
+				sent = append(sent, msg.ID())
+			}).Times(2)
+
+		// messages of other connections are not re-delivered
+		require.NoError(t, msgr.RetryPending(myDID, "other"))
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+		require.Equal(t, []string{"1", "2"}, sent)
+
+		// the queue is empty now
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+	})
+
+	t.Run("failed messages are not queued by default", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, newMemStore(t), outbound)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		require.EqualError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID), errMsg)
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+	})
+
+	t.Run("queue error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New("put error"))
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		msgr := newMessenger(t, store, outbound, WithRetryQueue())
+
+		err := msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+		require.Contains(t, err.Error(), "put error")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Iterator(gomock.Any(), gomock.Any()).Return(newIterator(t, "key", `{"message":{"@id":"ID"}}`))
+		store.EXPECT().Delete("key").Return(errors.New("delete error"))
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).Return(nil)
+
+		err := newMessenger(t, store, outbound, WithRetryQueue()).RetryPending(myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete error")
+	})
+
+	t.Run("invalid queued message", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Iterator(gomock.Any(), gomock.Any()).Return(newIterator(t, "key", "{"))
+
+		err := newMessenger(t, store, nil, WithRetryQueue()).RetryPending(myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal message")
+	})
+}
+
+func TestMessenger_OnConnectionActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T, store storage.Store, outbound *dispatcherMocks.MockOutbound) *Messenger {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).Times(2)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, WithRetryQueue())
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	t.Run("queued messages of the party are re-delivered once", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, newMemStore(t), outbound)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New(errMsg)).Times(3)
+
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID))
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "2"}, "myDID2", theirDID))
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "3"}, myDID, "other"))
+
+		var sent []string
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), theirDID).
+			Do(func(_ context.Context, msg service.DIDCommMsgMap, _, _ string) {
+				sent = append(sent, msg.ID())
+			}).Times(2)
+
+		require.NoError(t, msgr.OnConnectionActive(theirDID))
+		require.ElementsMatch(t, []string{"1", "2"}, sent)
+
+		// the messages are not re-delivered again
+		require.NoError(t, msgr.OnConnectionActive(theirDID))
+		require.Len(t, sent, 2)
+	})
+
+	t.Run("message delivered by another path is not re-delivered", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, newMemStore(t), outbound)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg)).Times(2)
+
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID))
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "2"}, myDID, theirDID))
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).Return(nil)
+
+		// the caller sends the first message again
+		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID))
+
+		var sent []string
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(func(_ context.Context, msg service.DIDCommMsgMap, _, _ string) {
+				sent = append(sent, msg.ID())
+			})
+
+		require.NoError(t, msgr.OnConnectionActive(theirDID))
+		require.Equal(t, []string{"2"}, sent)
+	})
+
+	t.Run("re-delivery error", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, newMemStore(t), outbound)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg)).Times(2)
+
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID))
+
+		err := msgr.OnConnectionActive(theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "on connection active: retry pending: send message 1")
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Iterator(gomock.Any(), gomock.Any()).Return(&errIterator{})
+
+		err := newMessenger(t, store, nil).OnConnectionActive(theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "on connection active: iterate store")
+	})
+}
+
+type connectionLookupStub struct {
+	records map[string]*connection.Record
+	err     error
+}
+
+func (l *connectionLookupStub) GetConnectionRecordByDIDs(myDID, theirDID string) (*connection.Record, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	record, ok := l.records[myDID+theirDID]
+	if !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return record, nil
+}
+
+func TestMessenger_EncryptionPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T, outbound *dispatcherMocks.MockOutbound, opts ...Opt) *Messenger {
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, opts...)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	lookup := &connectionLookupStub{records: map[string]*connection.Record{
+		myDID + theirDID: {MyDID: myDID, TheirDID: theirDID, RequireEncryption: true},
+		myDID + "other":  {MyDID: myDID, TheirDID: "other"},
+	}}
+
+	unencrypted := func(myDID, theirDID string) bool { return false }
+
+	t.Run("unencrypted message of the connection which requires encryption", func(t *testing.T) {
+		msgr := newMessenger(t, dispatcherMocks.NewMockOutbound(ctrl),
+			WithEncryptionPolicy(lookup, unencrypted), WithRetryQueue())
+
+		err := msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID)
+		require.True(t, errors.Is(err, ErrEncryptionRequired))
+
+		// the message is not queued for re-delivery
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+	})
+
+	t.Run("messages are sent", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, gomock.Any()).Times(3)
+
+		// the connection does not require encryption or the connection is unknown
+		msgr := newMessenger(t, outbound, WithEncryptionPolicy(lookup, unencrypted))
+		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, "other"))
+		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, "unknown"))
+
+		// the message is encrypted
+		msgr = newMessenger(t, outbound, WithEncryptionPolicy(lookup, func(string, string) bool { return true }))
+		require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
+	})
+
+	t.Run("queued messages are not re-delivered unencrypted", func(t *testing.T) {
+		policy := &connectionLookupStub{records: map[string]*connection.Record{
+			myDID + theirDID: {MyDID: myDID, TheirDID: theirDID},
+		}}
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		msgr := newMessenger(t, outbound, WithEncryptionPolicy(policy, unencrypted), WithRetryQueue())
+		require.EqualError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID), errMsg)
+
+		// the connection requires encryption after the message was queued
+		policy.records[myDID+theirDID].RequireEncryption = true
+
+		err := msgr.RetryPending(myDID, theirDID)
+		require.True(t, errors.Is(err, ErrEncryptionRequired))
+		require.Contains(t, err.Error(), "retry pending")
+	})
+
+	t.Run("policy is not enforced by default", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID)
+
+		require.NoError(t, newMessenger(t, outbound).Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
+	})
+
+	t.Run("connection lookup error", func(t *testing.T) {
+		msgr := newMessenger(t, dispatcherMocks.NewMockOutbound(ctrl),
+			WithEncryptionPolicy(&connectionLookupStub{err: errors.New(errMsg)}, unencrypted))
+
+		err := msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check encryption policy: "+errMsg)
+	})
+}
+
+type errIterator struct{}
+
+func (i *errIterator) Next() bool    { return false }
+func (i *errIterator) Release()      {}
+func (i *errIterator) Error() error  { return errors.New("iterator error") }
+func (i *errIterator) Key() []byte   { return nil }
+func (i *errIterator) Value() []byte { return nil }
+
+func TestMessenger_RecordCodec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T, store storage.Store, outbound *dispatcherMocks.MockOutbound,
+		codec RecordCodec) *Messenger {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).Times(2)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, WithRecordCodec(codec), WithRetryQueue())
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	t.Run("records are serialized by codec", func(t *testing.T) {
+		store := newMemStore(t)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		msgr := newMessenger(t, store, outbound, prefixCodec{})
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{
+			jsonID:     ID,
+			jsonThread: map[string]interface{}{jsonThreadID: "thID"},
+		}, myDID, theirDID))
+
+		src, err := store.Get(ID)
+		require.NoError(t, err)
+		require.Contains(t, string(src), codecPrefix)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		require.EqualError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{jsonID: "reply"}), errMsg)
+
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Do(func(_ context.Context, msg service.DIDCommMsgMap, _, _ string) {
+				require.Equal(t, "reply", msg.ID())
+
+				thID, err := msg.ThreadID()
+				require.NoError(t, err)
+				require.Equal(t, "thID", thID)
+			})
+
+		require.NoError(t, msgr.RetryPending(myDID, theirDID))
+	})
+
+	t.Run("codec error", func(t *testing.T) {
+		store := newMemStore(t)
+		require.NoError(t, store.Put(ID, []byte(`{}`)))
+
+		msgr := newMessenger(t, store, nil, prefixCodec{})
+
+		err := msgr.ReplyTo(ID, service.DIDCommMsgMap{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal record")
+	})
+}
+
+const codecPrefix = "codec:"
+
+// prefixCodec is JSON codec which prefixes the serialized records
+type prefixCodec struct{}
+
+func (prefixCodec) Marshal(v interface{}) ([]byte, error) {
+	src, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(codecPrefix), src...), nil
+}
+
+func (prefixCodec) Unmarshal(data []byte, v interface{}) error {
+	if !strings.HasPrefix(string(data), codecPrefix) {
+		return errors.New("no codec prefix")
+	}
+
+	return json.Unmarshal(data[len(codecPrefix):], v)
+}
+
+type didLookupStub struct {
+	rotations map[string]string
+	err       error
+}
+
+func (s *didLookupStub) GetCurrentDIDs(myDID, theirDID string) (string, string, error) {
+	if s.err != nil {
+		return "", "", s.err
+	}
+
+	current := func(did string) string {
+		if rotated, ok := s.rotations[did]; ok {
+			return rotated
+		}
+
+		return did
+	}
+
+	return current(myDID), current(theirDID), nil
+}
+
+func newIterator(t *testing.T, key, value string) storage.StoreIterator {
+	store := newMemStore(t)
+	require.NoError(t, store.Put(key, []byte(value)))
+
+	return store.Iterator(key, key+"~")
+}
+
+func newMemStore(t *testing.T) storage.Store {
+	store, err := mem.NewProvider().OpenStore("test")
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestMessenger_Middleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T, store storage.Store, opts ...Opt) *Messenger {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider, opts...)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	t.Run("message is passed through the middlewares", func(t *testing.T) {
+		var calls []string
+
+		middleware := func(name string) service.Middleware {
+			return func(next service.InboundFunc) service.InboundFunc {
+				return func(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+					calls = append(calls, name)
+
+					return next(msg, myDID, theirDID)
+				}
+			}
+		}
+
+		// the middleware may modify the message, e.g. assign the thread
+		assignThread := func(next service.InboundFunc) service.InboundFunc {
+			return func(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+				msg[jsonThread] = map[string]interface{}{jsonThreadID: "thID"}
+
+				return next(msg, myDID, theirDID)
+			}
+		}
+
+		store := newMemStore(t)
+		msgr := newMessenger(t, store, WithMiddleware(middleware("first"), middleware("second")),
+			WithMiddleware(assignThread))
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
+		require.Equal(t, []string{"first", "second"}, calls)
+
+		rec, err := msgr.getRecord(ID)
+		require.NoError(t, err)
+		require.Equal(t, "thID", rec.ThreadID)
+	})
+
+	t.Run("middleware short-circuits", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+
+		msgr := newMessenger(t, store, WithMiddleware(func(service.InboundFunc) service.InboundFunc {
+			return func(service.DIDCommMsgMap, string, string) error {
+				return errors.New(errMsg)
+			}
+		}))
+
+		require.EqualError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID), errMsg)
+	})
+}
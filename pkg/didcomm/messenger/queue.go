@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+// PendingCount returns the number of messages queued for re-delivery to the given connection (see WithRetryQueue).
+func (m *Messenger) PendingCount(myDID, theirDID string) (int, error) {
+	m.pendingLock.Lock()
+	defer m.pendingLock.Unlock()
+
+	records, err := m.pendingRecords(fmt.Sprintf(pendingPrefix, myDID, theirDID))
+	if err != nil {
+		return 0, fmt.Errorf("pending count: %w", err)
+	}
+
+	return len(records), nil
+}
+
+// PendingMessages returns up to limit messages queued for re-delivery to the given connection in the order
+// they were queued (all of them if limit is not positive). The messages stay queued until they are removed
+// by RemovePending or re-delivered by RetryPending, e.g. a mediator delivers them to the recipient
+// on its request and removes them once the recipient acknowledges the receipt.
+func (m *Messenger) PendingMessages(myDID, theirDID string, limit int) ([]service.DIDCommMsgMap, error) {
+	// the queue is not read while the messages are re-delivered or removed
+	m.pendingLock.Lock()
+	defer m.pendingLock.Unlock()
+
+	records, err := m.pendingRecords(fmt.Sprintf(pendingPrefix, myDID, theirDID))
+	if err != nil {
+		return nil, fmt.Errorf("pending messages: %w", err)
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	msgs := make([]service.DIDCommMsgMap, 0, len(records))
+
+	for _, rec := range records {
+		var pending pendingMessage
+		if err = m.codec.Unmarshal(rec.value, &pending); err != nil {
+			return nil, fmt.Errorf("pending messages: unmarshal message: %w", err)
+		}
+
+		msgs = append(msgs, pending.Message)
+	}
+
+	return msgs, nil
+}
+
+// RemovePending removes the messages with the given IDs from the queue of the given connection.
+// The IDs which are not queued are ignored.
+func (m *Messenger) RemovePending(myDID, theirDID string, msgIDs ...string) error {
+	m.pendingLock.Lock()
+	defer m.pendingLock.Unlock()
+
+	records, err := m.pendingRecords(fmt.Sprintf(pendingPrefix, myDID, theirDID))
+	if err != nil {
+		return fmt.Errorf("remove pending: %w", err)
+	}
+
+	ids := make(map[string]bool, len(msgIDs))
+	for _, id := range msgIDs {
+		ids[id] = true
+	}
+
+	for _, rec := range records {
+		// the key ends with |msgID
+		msgID := rec.key[strings.LastIndex(rec.key, "|")+1:]
+		if !ids[msgID] {
+			continue
+		}
+
+		if err = m.pending.Delete(rec.key); err != nil {
+			return fmt.Errorf("remove pending: delete message %s: %w", msgID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
+	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+)
+
+func TestMessenger_PendingMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storageProvider := storageMocks.NewMockProvider(ctrl)
+	storageProvider.EXPECT().OpenStore(gomock.Any()).Return(newMemStore(t), nil).Times(2)
+
+	outbound := dispatcherMocks.NewMockOutbound(ctrl)
+	outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+		Return(errors.New(errMsg)).Times(3)
+
+	provider := messengerMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(storageProvider)
+	provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+	msgr, err := NewMessenger(provider, WithRetryQueue())
+	require.NoError(t, err)
+
+	count, err := msgr.PendingCount(myDID, theirDID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{jsonID: id}, myDID, theirDID))
+	}
+
+	t.Run("count", func(t *testing.T) {
+		count, err = msgr.PendingCount(myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		count, err = msgr.PendingCount(myDID, "other")
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("messages in queue order", func(t *testing.T) {
+		msgs, err := msgr.PendingMessages(myDID, theirDID, 2)
+		require.NoError(t, err)
+		require.Len(t, msgs, 2)
+		require.Equal(t, "1", msgs[0].ID())
+		require.Equal(t, "2", msgs[1].ID())
+
+		msgs, err = msgr.PendingMessages(myDID, theirDID, 0)
+		require.NoError(t, err)
+		require.Len(t, msgs, 3)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, msgr.RemovePending(myDID, theirDID, "1", "3", "unknown"))
+
+		msgs, err := msgr.PendingMessages(myDID, theirDID, 0)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		require.Equal(t, "2", msgs[0].ID())
+	})
+}
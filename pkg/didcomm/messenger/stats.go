@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stats are the statistics of the Messenger store
+type Stats struct {
+	// Records is the number of records of the inbound messages
+	Records int
+	// Threads is the number of distinct threads of the records
+	Threads int
+	// MetadataEntries is the number of threads with metadata
+	MetadataEntries int
+	// PendingMessages is the number of outbound messages queued for re-delivery (see WithRetryQueue)
+	PendingMessages int
+}
+
+// Stats returns the statistics of the Messenger store, e.g. for health checks. The growing number of threads
+// may indicate that a protocol leaks them. All the records of the store are read, so it shouldn't be called often.
+func (m *Messenger) Stats() (Stats, error) {
+	var stats Stats
+
+	threads := make(map[string]struct{})
+
+	itr := m.store.Iterator("", fmt.Sprintf(limitPattern, ""))
+	defer itr.Release()
+
+	for itr.Next() {
+		key := string(itr.Key())
+
+		if strings.HasPrefix(key, metadataPrefix) {
+			stats.MetadataEntries++
+			continue
+		}
+
+		stats.Records++
+
+		var rec *record
+		if err := m.codec.Unmarshal(itr.Value(), &rec); err != nil {
+			return Stats{}, fmt.Errorf("messenger stats: unmarshal record %s: %w", key, err)
+		}
+
+		if rec != nil && rec.ThreadID != "" {
+			threads[rec.ThreadID] = struct{}{}
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return Stats{}, fmt.Errorf("messenger stats: iterate store: %w", err)
+	}
+
+	stats.Threads = len(threads)
+
+	pending, err := m.pendingRecords(pendingRootPrefix)
+	if err != nil {
+		return Stats{}, fmt.Errorf("messenger stats: %w", err)
+	}
+
+	stats.PendingMessages = len(pending)
+
+	return stats, nil
+}
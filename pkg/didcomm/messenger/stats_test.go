@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
+	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestMessenger_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		store, err := mem.NewProvider().OpenStore(messengerStore)
+		require.NoError(t, err)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(messengerStore).Return(store, nil)
+		storageProvider.EXPECT().OpenStore(pendingStore).Return(newMemStore(t), nil)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDIDWithContext(gomock.Any(), gomock.Any(), myDID, theirDID).
+			Return(errors.New(errMsg))
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider, WithRetryQueue())
+		require.NoError(t, err)
+
+		stats, err := msgr.Stats()
+		require.NoError(t, err)
+		require.Equal(t, Stats{}, stats)
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: "1"}, myDID, theirDID))
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{
+			jsonID:     "2",
+			jsonThread: map[string]interface{}{jsonThreadID: "1"},
+		}, myDID, theirDID))
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: "3"}, myDID, theirDID))
+
+		// the message is queued for re-delivery and its metadata is saved
+		require.Error(t, msgr.Send(service.DIDCommMsgMap{
+			jsonID:       "4",
+			jsonMetadata: map[string]interface{}{"key": "value"},
+		}, myDID, theirDID))
+
+		stats, err = msgr.Stats()
+		require.NoError(t, err)
+		require.Equal(t, Stats{Records: 3, Threads: 2, MetadataEntries: 1, PendingMessages: 1}, stats)
+	})
+
+	t.Run("invalid record", func(t *testing.T) {
+		store, err := mem.NewProvider().OpenStore(messengerStore)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ID, []byte("{")))
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		_, err = msgr.Stats()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "messenger stats: unmarshal record ID")
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Iterator(gomock.Any(), gomock.Any()).Return(&errIterator{})
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		_, err = msgr.Stats()
+		require.EqualError(t, err, "messenger stats: iterate store: iterator error")
+	})
+}
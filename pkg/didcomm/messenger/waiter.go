@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+// ReplyWaiter waits for the inbound reply on the thread of the connection (see NewReplyWaiter)
+type ReplyWaiter struct {
+	messenger *Messenger
+	threadID  string
+	myDID     string
+	theirDID  string
+	replies   chan service.DIDCommMsgMap
+}
+
+// NewReplyWaiter starts waiting for the next inbound message on the given thread of the connection
+// (myDID, theirDID), the messages of the thread received from the other connections are not delivered.
+// The waiter should be created before the message the reply is expected for is sent, so the reply is not missed.
+// Wait must be called to release the waiter.
+func (m *Messenger) NewReplyWaiter(threadID, myDID, theirDID string) *ReplyWaiter {
+	w := &ReplyWaiter{
+		messenger: m,
+		threadID:  threadID,
+		myDID:     myDID,
+		theirDID:  theirDID,
+		replies:   make(chan service.DIDCommMsgMap, 1),
+	}
+
+	m.waitersLock.Lock()
+	m.waiters[threadID] = append(m.waiters[threadID], w)
+	m.waitersLock.Unlock()
+
+	return w
+}
+
+// Wait returns the inbound reply on the thread or the error of ctx if it is done first.
+// If the other party replied with a problem report, it is returned as *service.ProblemReport error.
+// The waiter is released once Wait returns.
+func (w *ReplyWaiter) Wait(ctx context.Context) (service.DIDCommMsgMap, error) {
+	defer w.messenger.releaseWaiter(w)
+
+	select {
+	case msg := <-w.replies:
+		if !msg.IsProblemReport() {
+			return msg, nil
+		}
+
+		report, err := msg.ProblemReport()
+		if err != nil {
+			return nil, fmt.Errorf("wait reply: %w", err)
+		}
+
+		return nil, report
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait reply: %w", ctx.Err())
+	}
+}
+
+// releaseWaiter stops delivering replies to the waiter
+func (m *Messenger) releaseWaiter(w *ReplyWaiter) {
+	m.waitersLock.Lock()
+	defer m.waitersLock.Unlock()
+
+	waiters := m.waiters[w.threadID]
+
+	for i, waiter := range waiters {
+		if waiter == w {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+
+	if len(waiters) == 0 {
+		delete(m.waiters, w.threadID)
+	} else {
+		m.waiters[w.threadID] = waiters
+	}
+}
+
+// notifyWaiters delivers the inbound message to the waiters of its thread on the connection the message
+// is received from, a waiter gets the first reply only
+func (m *Messenger) notifyWaiters(threadID, myDID, theirDID string, msg service.DIDCommMsgMap) {
+	m.waitersLock.Lock()
+	defer m.waitersLock.Unlock()
+
+	for _, w := range m.waiters[threadID] {
+		if w.myDID != myDID || w.theirDID != theirDID {
+			continue
+		}
+
+		select {
+		case w.replies <- msg.Clone():
+		default:
+		}
+	}
+}
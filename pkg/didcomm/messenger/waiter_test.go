@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestMessenger_NewReplyWaiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessenger := func(t *testing.T) *Messenger {
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+		provider.EXPECT().OutboundDispatcher().Return(nil)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	reply := func(id, thID, msgType string) service.DIDCommMsgMap {
+		return service.DIDCommMsgMap{
+			jsonID:     id,
+			"@type":    msgType,
+			jsonThread: map[string]interface{}{jsonThreadID: thID},
+		}
+	}
+
+	t.Run("reply is delivered", func(t *testing.T) {
+		msgr := newMessenger(t)
+		waiter := msgr.NewReplyWaiter("thID", myDID, theirDID)
+
+		require.NoError(t, msgr.HandleInbound(reply("ID1", "thID", "https://didcomm.org/test/1.0/reply"), myDID, theirDID))
+		require.NoError(t, msgr.HandleInbound(reply("ID2", "thID", "https://didcomm.org/test/1.0/reply"), myDID, theirDID))
+
+		msg, err := waiter.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "ID1", msg.ID())
+		require.Empty(t, msgr.waiters)
+	})
+
+	t.Run("message of another thread is not delivered", func(t *testing.T) {
+		msgr := newMessenger(t)
+		waiter := msgr.NewReplyWaiter("thID", myDID, theirDID)
+
+		require.NoError(t, msgr.HandleInbound(reply("ID1", "thID2", "https://didcomm.org/test/1.0/reply"), myDID, theirDID))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		msg, err := waiter.Wait(ctx)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Nil(t, msg)
+		require.Empty(t, msgr.waiters)
+	})
+
+	t.Run("message of another connection is not delivered", func(t *testing.T) {
+		msgr := newMessenger(t)
+		waiter := msgr.NewReplyWaiter("thID", myDID, theirDID)
+
+		report := service.NewProblemReport("thID", "request-not-accepted", "request is not accepted")
+		require.NoError(t, msgr.HandleInbound(report, myDID, "did:example:other"))
+		require.NoError(t, msgr.HandleInbound(reply("ID1", "thID", "https://didcomm.org/test/1.0/reply"),
+			"did:example:other", theirDID))
+		require.NoError(t, msgr.HandleInbound(reply("ID2", "thID", "https://didcomm.org/test/1.0/reply"),
+			myDID, theirDID))
+
+		msg, err := waiter.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "ID2", msg.ID())
+	})
+
+	t.Run("problem report is returned as error", func(t *testing.T) {
+		msgr := newMessenger(t)
+		waiter := msgr.NewReplyWaiter("thID", myDID, theirDID)
+		other := msgr.NewReplyWaiter("thID", myDID, theirDID)
+
+		report := service.NewProblemReport("thID", "request-not-accepted", "request is not accepted")
+		require.NoError(t, msgr.HandleInbound(report, myDID, theirDID))
+
+		msg, err := waiter.Wait(context.Background())
+		require.Nil(t, msg)
+		require.EqualError(t, err, "problem report: request-not-accepted: request is not accepted")
+
+		var problem *service.ProblemReport
+
+		require.True(t, errors.As(err, &problem))
+		require.Equal(t, "thID", problem.ThreadID)
+		require.Equal(t, report.ID(), problem.ID)
+
+		require.Len(t, msgr.waiters["thID"], 1)
+
+		_, err = other.Wait(context.Background())
+		require.Error(t, err)
+		require.Empty(t, msgr.waiters)
+	})
+}
@@ -253,6 +253,58 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		require.Equal(t, unpackedMsg.Message, []byte("msg2"))
 	})
 
+	t.Run("test Pack/Unpack with media type", func(t *testing.T) {
+		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     w,
+		}
+
+		jwePacker, err := jwe.New(mockedProviders, jwe.XC20P)
+		require.NoError(t, err)
+
+		// legacy is the primary packer, JWE is additional one
+		mockedProviders.primaryPacker = legacy.New(mockedProviders)
+		mockedProviders.packers = []packer.Packer{jwePacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		_, base58FromVerKey, err := w.CreateKeySet()
+		require.NoError(t, err)
+
+		_, base58ToVerKey, err := w.CreateKeySet()
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			mediaType string
+			expected  string
+		}{
+			{mediaType: "", expected: transport.MediaTypeV1Envelope},
+			{mediaType: transport.MediaTypeV1Envelope, expected: transport.MediaTypeV1Envelope},
+			{mediaType: transport.MediaTypeV2Envelope, expected: transport.MediaTypeV2Envelope},
+		} {
+			packMsg, err := packager.PackMessage(&transport.Envelope{Message: []byte("msg"),
+				FromVerKey: base58.Decode(base58FromVerKey),
+				ToVerKeys:  []string{base58ToVerKey},
+				MediaType:  tc.mediaType})
+			require.NoError(t, err)
+
+			unpackedMsg, err := packager.UnpackMessage(packMsg)
+			require.NoError(t, err)
+			require.Equal(t, []byte("msg"), unpackedMsg.Message)
+			require.Equal(t, tc.expected, unpackedMsg.MediaType)
+		}
+
+		_, err = packager.PackMessage(&transport.Envelope{Message: []byte("msg"),
+			FromVerKey: base58.Decode(base58FromVerKey),
+			ToVerKeys:  []string{base58ToVerKey},
+			MediaType:  "application/unknown"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "pack: media type not supported: application/unknown")
+	})
+
 	t.Run("test success - dids not found", func(t *testing.T) {
 		// create a mock LegacyKMS with storage as a map
 		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
@@ -340,6 +392,105 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 }
 
+func TestBaseKMSInPackager_MultipleRecipients(t *testing.T) {
+	newWalletPackager := func(t *testing.T, newPacker func(*mockProvider) packer.Packer) (
+		*Packager, legacykms.KeyManager) {
+		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     w,
+		}
+
+		testPacker := newPacker(mockedProviders)
+		mockedProviders.primaryPacker = testPacker
+		mockedProviders.packers = []packer.Packer{testPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		return packager, w
+	}
+
+	// JWE packer reports the encryption keys of the unpacked envelope while legacy packer reports
+	// the verification keys
+	tests := []struct {
+		name          string
+		newPacker     func(*mockProvider) packer.Packer
+		reportsEncKey bool
+	}{
+		{
+			name:          "JWE packer",
+			reportsEncKey: true,
+			newPacker: func(p *mockProvider) packer.Packer {
+				jwePacker, err := jwe.New(p, jwe.XC20P)
+				require.NoError(t, err)
+
+				return jwePacker
+			},
+		},
+		{
+			name: "legacy packer",
+			newPacker: func(p *mockProvider) packer.Packer {
+				return legacy.New(p)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run("pack to three recipients and unpack by each of them using "+tc.name, func(t *testing.T) {
+			senderPackager, senderWallet := newWalletPackager(t, tc.newPacker)
+
+			base58FromEncKey, base58FromVerKey, err := senderWallet.CreateKeySet()
+			require.NoError(t, err)
+
+			var (
+				recipientPackagers []*Packager
+				toEncKeys          []string
+				toVerKeys          []string
+			)
+
+			for i := 0; i < 3; i++ {
+				recipientPackager, recipientWallet := newWalletPackager(t, tc.newPacker)
+
+				base58ToEncKey, base58ToVerKey, e := recipientWallet.CreateKeySet()
+				require.NoError(t, e)
+
+				recipientPackagers = append(recipientPackagers, recipientPackager)
+				toEncKeys = append(toEncKeys, base58ToEncKey)
+				toVerKeys = append(toVerKeys, base58ToVerKey)
+			}
+
+			packMsg, err := senderPackager.PackMessage(&transport.Envelope{Message: []byte("msg"),
+				FromVerKey: base58.Decode(base58FromVerKey),
+				ToVerKeys:  toVerKeys})
+			require.NoError(t, err)
+
+			for i, recipientPackager := range recipientPackagers {
+				unpackedMsg, e := recipientPackager.UnpackMessage(packMsg)
+				require.NoError(t, e)
+				require.Equal(t, []byte("msg"), unpackedMsg.Message)
+
+				if tc.reportsEncKey {
+					require.Equal(t, base58FromEncKey, base58.Encode(unpackedMsg.FromVerKey))
+					require.Equal(t, toEncKeys[i], base58.Encode(unpackedMsg.ToVerKey))
+				} else {
+					require.Equal(t, base58FromVerKey, base58.Encode(unpackedMsg.FromVerKey))
+					require.Equal(t, toVerKeys[i], base58.Encode(unpackedMsg.ToVerKey))
+				}
+			}
+
+			// a wallet which is not among the recipients can't unpack the message
+			otherPackager, _ := newWalletPackager(t, tc.newPacker)
+
+			_, err = otherPackager.UnpackMessage(packMsg)
+			require.Error(t, err)
+		})
+	}
+}
+
 func newMockKMSProvider(storagePvdr *mockstorage.MockStoreProvider) *mockProvider {
 	return &mockProvider{storagePvdr, nil, nil, nil, nil}
 }
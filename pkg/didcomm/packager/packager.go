@@ -79,11 +79,26 @@ func (bp *Packager) addPacker(pack packer.Packer) {
 }
 
 // PackMessage Pack a message for one or more recipients.
+// A single envelope is produced with a recipient entry for each of messageEnvelope.ToVerKeys,
+// so that any of the recipients can unpack it.
+// The envelope format is selected by messageEnvelope.MediaType (e.g. transport.MediaTypeV2Envelope),
+// the primary packer is used if it is empty.
 func (bp *Packager) PackMessage(messageEnvelope *transport.Envelope) ([]byte, error) {
 	if messageEnvelope == nil {
 		return nil, errors.New("envelope argument is nil")
 	}
 
+	p := bp.primaryPacker
+
+	if messageEnvelope.MediaType != "" {
+		var ok bool
+
+		p, ok = bp.packers[messageEnvelope.MediaType]
+		if !ok {
+			return nil, fmt.Errorf("pack: media type not supported: %s", messageEnvelope.MediaType)
+		}
+	}
+
 	var recipients [][]byte
 
 	for _, verKey := range messageEnvelope.ToVerKeys {
@@ -98,7 +113,7 @@ func (bp *Packager) PackMessage(messageEnvelope *transport.Envelope) ([]byte, er
 		recipients = append(recipients, verKeyBytes)
 	}
 	// pack message
-	bytes, err := bp.primaryPacker.Pack(messageEnvelope.Message, messageEnvelope.FromVerKey, recipients)
+	bytes, err := p.Pack(messageEnvelope.Message, messageEnvelope.FromVerKey, recipients)
 	if err != nil {
 		return nil, fmt.Errorf("pack: %w", err)
 	}
@@ -147,6 +162,7 @@ func getEncodingType(encMessage []byte) (string, error) {
 }
 
 // UnpackMessage Unpack a message.
+// The envelope format is detected from the envelope header and returned as MediaType of the unpacked envelope.
 func (bp *Packager) UnpackMessage(encMessage []byte) (*transport.Envelope, error) {
 	encType, err := getEncodingType(encMessage)
 	if err != nil {
@@ -179,6 +195,7 @@ func (bp *Packager) UnpackMessage(encMessage []byte) (*transport.Envelope, error
 
 	envelope.ToDID = myDID
 	envelope.FromDID = theirDID
+	envelope.MediaType = encType
 
 	return envelope, nil
 }
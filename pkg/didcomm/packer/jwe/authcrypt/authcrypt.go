@@ -13,6 +13,7 @@ import (
 
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 )
@@ -29,7 +30,7 @@ const (
 	// XC20P XChacha20Poly1305 algorithm
 	XC20P = ContentEncryption("XC20P") // XChacha20 encryption + Poly1305 authenticator cipher (192 bits nonce)
 	// encodingType is the `typ` string identifier in a message that identifies the format as being JWE
-	encodingType string = "prs.hyperledger.aries-auth-message"
+	encodingType = transport.MediaTypeV2Envelope
 )
 
 // errUnsupportedAlg is used when a bad encryption algorithm is used
@@ -102,7 +102,7 @@ func (p *Packer) findRecipient(jweRecipients []Recipient) (*[chacha.KeySize]byte
 		recipientsKeys = append(recipientsKeys, recipient.Header.KID)
 	}
 
-	i, err := p.legacyKMS.FindVerKey(recipientsKeys)
+	i, err := p.legacyKMS.FindEncKey(recipientsKeys)
 	if err != nil {
 		return nil, nil, err
 	}
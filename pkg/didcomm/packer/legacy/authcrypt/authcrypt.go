@@ -10,6 +10,7 @@ import (
 	"crypto/rand"
 	"io"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 )
@@ -21,7 +22,7 @@ type Packer struct {
 }
 
 // encodingType is the `typ` string identifier in a message that identifies the format as being legacy
-const encodingType string = "JWM/1.0"
+const encodingType = transport.MediaTypeV1Envelope
 
 // New will create a Packer that encrypts messages using the legacy Aries format
 // Note: legacy Packer does not support XChacha20Poly1035 (XC20P), only Chacha20Poly1035 (C20P)
@@ -702,6 +702,16 @@ func Test_getCEK(t *testing.T) {
 
 	_, err := getCEK(recs, &k)
 	require.EqualError(t, err, "mock error")
+
+	t.Run("recipient key is key agreement key", func(t *testing.T) {
+		testKMS, _ := newKMS(t)
+
+		kid, _, err := testKMS.CreateKeyAgreementKey()
+		require.NoError(t, err)
+
+		_, err = getCEK([]recipient{{Header: recipientHeader{KID: kid}}}, testKMS)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+	})
 }
 
 func getB58Key(pub, priv string) *cryptoutil.KeyPair {
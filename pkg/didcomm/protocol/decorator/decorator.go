@@ -6,7 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 
 package decorator
 
-import "time"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
 
 const (
 	// TransportReturnRouteNone return route option none
@@ -32,6 +38,14 @@ type Timing struct {
 	ExpiresTime time.Time `json:"expires_time,omitempty"`
 }
 
+// L10n localization decorator, it defines the locale of the message and its localizable fields
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0043-l10n
+type L10n struct {
+	Locale      string   `json:"locale,omitempty"`
+	Localizable []string `json:"localizable,omitempty"`
+	Catalog     string   `json:"catalog,omitempty"`
+}
+
 // Transport transport decorator
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0092-transport-return-route
 type Transport struct {
@@ -42,3 +56,69 @@ type Transport struct {
 type ReturnRoute struct {
 	Value string `json:"~return_route,omitempty"`
 }
+
+// Attachment is intended to provide the possibility to include files, links or even JSON payload to the message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/concepts/0017-attachments
+type Attachment struct {
+	ID          string         `json:"@id,omitempty"`
+	Description string         `json:"description,omitempty"`
+	FileName    string         `json:"filename,omitempty"`
+	MimeType    string         `json:"mime-type,omitempty"`
+	LastModTime *time.Time     `json:"lastmod_time,omitempty"`
+	ByteCount   int64          `json:"byte_count,omitempty"`
+	Data        AttachmentData `json:"data,omitempty"`
+}
+
+// AttachmentData contains attachment payload
+type AttachmentData struct {
+	Sha256 string      `json:"sha256,omitempty"`
+	Links  []string    `json:"links,omitempty"`
+	Base64 string      `json:"base64,omitempty"`
+	JSON   interface{} `json:"json,omitempty"`
+	// JWS is the detached signature of the payload (e.g. legacykms.AttachmentSig)
+	JWS json.RawMessage `json:"jws,omitempty"`
+}
+
+// ErrAttachmentDataNotEmbedded is returned when the attachment payload is not embedded into the message
+// (e.g. only links to the payload are provided)
+var ErrAttachmentDataNotEmbedded = errors.New("attachment data is not embedded")
+
+// Fetch returns the raw bytes of the embedded attachment payload. Base64 payload is decoded
+// (both standard and URL-safe alphabets are supported), JSON payload is marshaled.
+// ErrAttachmentDataNotEmbedded is returned if there is no embedded payload (e.g. links only).
+func (d *AttachmentData) Fetch() ([]byte, error) {
+	if d.Base64 != "" {
+		return decodeBase64(d.Base64)
+	}
+
+	if d.JSON != nil {
+		bytes, err := json.Marshal(d.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("marshal attachment JSON data: %w", err)
+		}
+
+		return bytes, nil
+	}
+
+	return nil, ErrAttachmentDataNotEmbedded
+}
+
+// decodeBase64 decodes standard or URL-safe base64 with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	encodings := []*base64.Encoding{
+		base64.URLEncoding, base64.RawURLEncoding, base64.StdEncoding, base64.RawStdEncoding,
+	}
+
+	var err error
+
+	for _, enc := range encodings {
+		var bytes []byte
+
+		bytes, err = enc.DecodeString(s)
+		if err == nil {
+			return bytes, nil
+		}
+	}
+
+	return nil, fmt.Errorf("decode attachment base64 data: %w", err)
+}
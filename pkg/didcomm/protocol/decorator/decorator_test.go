@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package decorator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentData_Fetch(t *testing.T) {
+	t.Run("base64", func(t *testing.T) {
+		for _, data := range []string{"aGk_Pz8-", "aGk/Pz8+", "aGk_Pz8-Pw==", "aGk_Pz8-Pw"} {
+			bytes, err := (&AttachmentData{Base64: data}).Fetch()
+			require.NoError(t, err)
+			require.Contains(t, string(bytes), "hi???")
+		}
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		_, err := (&AttachmentData{Base64: "!!!"}).Fetch()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode attachment base64 data")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bytes, err := (&AttachmentData{JSON: map[string]interface{}{"key": "value"}}).Fetch()
+		require.NoError(t, err)
+		require.Equal(t, `{"key":"value"}`, string(bytes))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := (&AttachmentData{JSON: make(chan int)}).Fetch()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal attachment JSON data")
+	})
+
+	t.Run("links only", func(t *testing.T) {
+		_, err := (&AttachmentData{Links: []string{"https://example.com/file"}}).Fetch()
+		require.True(t, errors.Is(err, ErrAttachmentDataNotEmbedded))
+	})
+}
@@ -41,6 +41,50 @@ type Invitation struct {
 	// the Type of the connection invitation
 	Type   string            `json:"@type,omitempty"`
 	Thread *decorator.Thread `json:"~thread,omitempty"`
+
+	// the Timing decorator keeps the expiration time of the connection invitation
+	Timing *decorator.Timing `json:"~timing,omitempty"`
+}
+
+// OOBInvitation model
+//
+// OOBInvitation defines out-of-band invitation message
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#message-type-httpsdidcommorgout-of-bandverinvitation
+//
+type OOBInvitation struct {
+	// the ID of the out-of-band invitation
+	ID string `json:"@id,omitempty"`
+
+	// the Type of the out-of-band invitation
+	Type string `json:"@type,omitempty"`
+
+	// the Label of the out-of-band invitation
+	Label string `json:"label,omitempty"`
+
+	// the Goal of the out-of-band invitation
+	Goal string `json:"goal,omitempty"`
+
+	// the GoalCode of the out-of-band invitation
+	GoalCode string `json:"goal_code,omitempty"`
+
+	// the HandshakeProtocols which can be used to establish the connection
+	HandshakeProtocols []string `json:"handshake_protocols,omitempty"`
+
+	// the Requests attached to the out-of-band invitation
+	Requests []*decorator.Attachment `json:"request~attach,omitempty"`
+
+	// the Service entries of the out-of-band invitation, each one is either a DID (string) or
+	// an inline service block (OOBService)
+	Service []interface{} `json:"service,omitempty"`
+}
+
+// OOBService defines an inline service block of the out-of-band invitation
+type OOBService struct {
+	ID              string   `json:"id,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	RecipientKeys   []string `json:"recipientKeys,omitempty"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint,omitempty"`
 }
 
 // Request defines a2a DID exchange request
@@ -75,3 +119,31 @@ type Connection struct {
 	DID    string   `json:"did,omitempty"`
 	DIDDoc *did.Doc `json:"did_doc,omitempty"`
 }
+
+// Rotate defines DID rotate message, the sender notifies the other party of the completed connection
+// that it rotated its DID to the one in the message
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0794-did-rotate#rotate
+type Rotate struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	ToDID  string            `json:"to_did,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// HandshakeReuse defines out-of-band handshake reuse message, the receiver of out-of-band invitation notifies
+// the inviter that the existing connection is used instead of a new one
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#reuse-messages
+type HandshakeReuse struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// HandshakeReuseAccepted defines out-of-band handshake reuse accepted message, the inviter acknowledges
+// the reuse of the existing connection
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#reuse-messages
+type HandshakeReuseAccepted struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// ReuseConnection notifies the inviter of the out-of-band invitation that the existing completed connection
+// is used instead of a new one. The handshake reuse message is sent on the connection, its parent thread is
+// the invitation (RFC 0434).
+func (s *Service) ReuseConnection(connectionID, invitationID string) error {
+	connRecord, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("reuse connection: %w", err)
+	}
+
+	if connRecord.State != stateNameCompleted {
+		return fmt.Errorf("reuse connection: connection is not completed: state=%s", connRecord.State)
+	}
+
+	msgID := generateRandomID()
+
+	reuse := &HandshakeReuse{
+		Type:   HandshakeReuseMsgType,
+		ID:     msgID,
+		Thread: &decorator.Thread{ID: msgID, PID: invitationID},
+	}
+
+	if err = s.ctx.outboundDispatcher.SendToDID(reuse, connRecord.MyDID, connRecord.TheirDID); err != nil {
+		return fmt.Errorf("reuse connection: send handshake reuse message: %w", err)
+	}
+
+	return nil
+}
+
+// handleInboundReuse accepts the reuse of the existing connection the handshake reuse message was received on.
+func (s *Service) handleInboundReuse(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	reuse := &HandshakeReuse{}
+
+	if err := msg.Decode(reuse); err != nil {
+		return "", fmt.Errorf("handle inbound handshake reuse: %w", err)
+	}
+
+	if reuse.Thread == nil || reuse.Thread.ID == "" {
+		return "", errors.New("handle inbound handshake reuse: thread ID is missing")
+	}
+
+	connRecord, err := s.completedConnectionByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("handle inbound handshake reuse: %w", err)
+	}
+
+	accepted := &HandshakeReuseAccepted{
+		Type:   HandshakeReuseAcceptedMsgType,
+		ID:     generateRandomID(),
+		Thread: &decorator.Thread{ID: reuse.Thread.ID, PID: reuse.Thread.PID},
+	}
+
+	if err = s.ctx.outboundDispatcher.SendToDID(accepted, connRecord.MyDID, connRecord.TheirDID); err != nil {
+		return "", fmt.Errorf("handle inbound handshake reuse: send handshake reuse accepted message: %w", err)
+	}
+
+	return connRecord.ConnectionID, nil
+}
+
+// handleInboundReuseAccepted returns the connection the reuse of which was accepted by the inviter.
+func (s *Service) handleInboundReuseAccepted(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	accepted := &HandshakeReuseAccepted{}
+
+	if err := msg.Decode(accepted); err != nil {
+		return "", fmt.Errorf("handle inbound handshake reuse accepted: %w", err)
+	}
+
+	connRecord, err := s.completedConnectionByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("handle inbound handshake reuse accepted: %w", err)
+	}
+
+	return connRecord.ConnectionID, nil
+}
+
+// completedConnectionByDIDs returns the completed connection between myDID and theirDID.
+func (s *Service) completedConnectionByDIDs(myDID, theirDID string) (*connection.Record, error) {
+	connRecord, err := s.connectionStore.GetConnectionRecordByDIDs(myDID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("connection of %s and %s: %w", myDID, theirDID, err)
+	}
+
+	if connRecord.State != stateNameCompleted {
+		return nil, fmt.Errorf("connection is not completed: state=%s", connRecord.State)
+	}
+
+	return connRecord, nil
+}
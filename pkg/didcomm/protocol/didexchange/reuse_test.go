@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+)
+
+const (
+	reuseInvitationID = "invitation-id"
+	reuseError        = "reuse error"
+)
+
+func TestService_ReuseConnection(t *testing.T) {
+	t.Run("reuse connection", func(t *testing.T) {
+		var sent *HandshakeReuse
+
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDIDArg, theirDIDArg string) error {
+				require.Equal(t, myDID, myDIDArg)
+				require.Equal(t, theirDID, theirDIDArg)
+
+				sent = msg.(*HandshakeReuse)
+
+				return nil
+			},
+		}, stateNameCompleted)
+
+		require.NoError(t, svc.ReuseConnection(connID, reuseInvitationID))
+
+		require.NotNil(t, sent)
+		require.Equal(t, HandshakeReuseMsgType, sent.Type)
+		require.NotEmpty(t, sent.ID)
+		require.Equal(t, sent.ID, sent.Thread.ID)
+		require.Equal(t, reuseInvitationID, sent.Thread.PID)
+	})
+
+	t.Run("connection is not found", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		err := svc.ReuseConnection("unknown", reuseInvitationID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "reuse connection")
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameRequested)
+
+		err := svc.ReuseConnection(connID, reuseInvitationID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection is not completed: state=requested")
+	})
+
+	t.Run("send handshake reuse message error", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{SendErr: errors.New(reuseError)},
+			stateNameCompleted)
+
+		err := svc.ReuseConnection(connID, reuseInvitationID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send handshake reuse message: "+reuseError)
+	})
+}
+
+func TestService_HandleInboundReuse(t *testing.T) {
+	reuseMsg := func(thID string) service.DIDCommMsg {
+		return service.NewDIDCommMsgMap(&HandshakeReuse{
+			Type:   HandshakeReuseMsgType,
+			ID:     thID,
+			Thread: &decorator.Thread{ID: thID, PID: reuseInvitationID},
+		})
+	}
+
+	t.Run("accept reuse", func(t *testing.T) {
+		var sent *HandshakeReuseAccepted
+
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDIDArg, theirDIDArg string) error {
+				require.Equal(t, myDID, myDIDArg)
+				require.Equal(t, theirDID, theirDIDArg)
+
+				sent = msg.(*HandshakeReuseAccepted)
+
+				return nil
+			},
+		}, stateNameCompleted)
+		require.True(t, svc.Accept(HandshakeReuseMsgType))
+
+		id, err := svc.HandleInbound(reuseMsg("reuse-thread-id"), myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, connID, id)
+
+		require.NotNil(t, sent)
+		require.Equal(t, HandshakeReuseAcceptedMsgType, sent.Type)
+		require.Equal(t, "reuse-thread-id", sent.Thread.ID)
+		require.Equal(t, reuseInvitationID, sent.Thread.PID)
+	})
+
+	t.Run("thread ID is missing", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(reuseMsg(""), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "thread ID is missing")
+	})
+
+	t.Run("connection is not found", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(reuseMsg("reuse-thread-id"), myDID, "did:example:unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection of did:example:my-did and did:example:unknown")
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameRequested)
+
+		_, err := svc.HandleInbound(reuseMsg("reuse-thread-id"), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection is not completed: state=requested")
+	})
+
+	t.Run("send handshake reuse accepted message error", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{SendErr: errors.New(reuseError)},
+			stateNameCompleted)
+
+		_, err := svc.HandleInbound(reuseMsg("reuse-thread-id"), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send handshake reuse accepted message: "+reuseError)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		msg := service.DIDCommMsgMap{"@type": HandshakeReuseMsgType, "~thread": "invalid"}
+
+		_, err := svc.HandleInbound(msg, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handle inbound handshake reuse")
+	})
+}
+
+func TestService_HandleInboundReuseAccepted(t *testing.T) {
+	acceptedMsg := service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+		Type:   HandshakeReuseAcceptedMsgType,
+		ID:     generateRandomID(),
+		Thread: &decorator.Thread{ID: "reuse-thread-id", PID: reuseInvitationID},
+	})
+
+	t.Run("reuse accepted", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+		require.True(t, svc.Accept(HandshakeReuseAcceptedMsgType))
+
+		id, err := svc.HandleInbound(acceptedMsg, myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, connID, id)
+	})
+
+	t.Run("connection is not found", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(acceptedMsg, "did:example:unknown", theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handle inbound handshake reuse accepted")
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		msg := service.DIDCommMsgMap{"@type": HandshakeReuseAcceptedMsgType, "~thread": "invalid"}
+
+		_, err := svc.HandleInbound(msg, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "handle inbound handshake reuse accepted")
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// RotateDID rotates my DID of the completed connection to the new one. The other party is notified
+// by the rotate message sent on the connection thread from the current DID, then the connection record
+// is updated. The new DID must be resolvable.
+func (s *Service) RotateDID(connectionID, newDID string) error {
+	connRecord, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("rotate DID: %w", err)
+	}
+
+	if connRecord.State != stateNameCompleted {
+		return fmt.Errorf("rotate DID: connection is not completed: state=%s", connRecord.State)
+	}
+
+	if _, err = s.ctx.vdriRegistry.Resolve(newDID); err != nil {
+		return fmt.Errorf("rotate DID: resolve new DID[%s]: %w", newDID, err)
+	}
+
+	rotate := &Rotate{
+		Type:   RotateMsgType,
+		ID:     generateRandomID(),
+		ToDID:  newDID,
+		Thread: &decorator.Thread{ID: connRecord.ThreadID},
+	}
+
+	if err = s.ctx.outboundDispatcher.SendToDID(rotate, connRecord.MyDID, connRecord.TheirDID); err != nil {
+		return fmt.Errorf("rotate DID: send rotate message: %w", err)
+	}
+
+	oldMyDID, oldTheirDID := connRecord.MyDID, connRecord.TheirDID
+	connRecord.MyDID = newDID
+
+	if err = s.saveRotation(oldMyDID, oldTheirDID, newDID, connRecord); err != nil {
+		return fmt.Errorf("rotate DID: %w", err)
+	}
+
+	return nil
+}
+
+// handleInboundRotate updates their DID of the connection the rotate message was received on.
+// The message must be sent by the other party of the connection, i.e. by its current DID to my current DID.
+func (s *Service) handleInboundRotate(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	rotate := &Rotate{}
+
+	if err := msg.Decode(rotate); err != nil {
+		return "", fmt.Errorf("handle inbound rotate: %w", err)
+	}
+
+	if rotate.Thread == nil || rotate.Thread.ID == "" {
+		return "", errors.New("handle inbound rotate: thread ID is missing")
+	}
+
+	connRecord, err := s.connectionRecordByThreadID(rotate.Thread.ID)
+	if err != nil {
+		return "", fmt.Errorf("handle inbound rotate: %w", err)
+	}
+
+	if connRecord.State != stateNameCompleted {
+		return "", fmt.Errorf("handle inbound rotate: connection is not completed: state=%s", connRecord.State)
+	}
+
+	if theirDID == "" || theirDID != connRecord.TheirDID {
+		return "", fmt.Errorf("handle inbound rotate: sender DID[%s] is not their DID of the connection", theirDID)
+	}
+
+	if myDID != connRecord.MyDID {
+		return "", fmt.Errorf("handle inbound rotate: recipient DID[%s] is not my DID of the connection", myDID)
+	}
+
+	if _, err = s.ctx.vdriRegistry.Resolve(rotate.ToDID); err != nil {
+		return "", fmt.Errorf("handle inbound rotate: resolve new DID[%s]: %w", rotate.ToDID, err)
+	}
+
+	connRecord.TheirDID = rotate.ToDID
+
+	if err = s.saveRotation(myDID, theirDID, rotate.ToDID, connRecord); err != nil {
+		return "", fmt.Errorf("handle inbound rotate: %w", err)
+	}
+
+	return connRecord.ConnectionID, nil
+}
+
+// connectionRecordByThreadID returns the connection record of the given thread, whatever the role of this agent is.
+func (s *Service) connectionRecordByThreadID(thID string) (*connection.Record, error) {
+	for _, prefix := range []string{myNSPrefix, theirNSPrefix} {
+		nsThID, err := connection.CreateNamespaceKey(prefix, thID)
+		if err != nil {
+			return nil, err
+		}
+
+		connRecord, err := s.connectionStore.GetConnectionRecordByNSThreadID(nsThID)
+		if err == nil {
+			return connRecord, nil
+		}
+
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("connection of thread %s: %w", thID, storage.ErrDataNotFound)
+}
+
+// saveRotation saves the connection record with the rotated DID and the rotation of the connection itself,
+// so the messages of the connection received before the rotation are replied using the new DID.
+func (s *Service) saveRotation(oldMyDID, oldTheirDID, newDID string, connRecord *connection.Record) error {
+	if err := s.connectionStore.saveConnectionRecord(connRecord); err != nil {
+		return err
+	}
+
+	if err := s.connectionStore.SaveDIDByResolving(newDID); err != nil {
+		return fmt.Errorf("save DID by resolving: %w", err)
+	}
+
+	if err := s.connectionStore.SaveDIDRotation(connRecord.ConnectionID, oldMyDID, oldTheirDID); err != nil {
+		return fmt.Errorf("save DID rotation: %w", err)
+	}
+
+	return nil
+}
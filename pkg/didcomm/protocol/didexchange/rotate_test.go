@@ -0,0 +1,228 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	myDID       = "did:example:my-did"
+	theirDID    = "did:example:their-did"
+	rotatedDID  = "did:example:rotated-did"
+	rotateThID  = "rotate-thread-id"
+	rotateError = "rotate error"
+)
+
+func newRotateService(t *testing.T, outbound *mockdispatcher.MockOutbound, state string) (*Service, string) {
+	svc, err := New(&protocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+		CustomVDRI:     &mockvdri.MockVDRIRegistry{ResolveValue: createDIDDoc()},
+		CustomOutbound: outbound,
+	})
+	require.NoError(t, err)
+
+	connRecord := &connection.Record{
+		ConnectionID: generateRandomID(),
+		ThreadID:     rotateThID,
+		State:        state,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+		Namespace:    theirNSPrefix,
+	}
+
+	require.NoError(t, svc.connectionStore.saveConnectionRecordWithMapping(connRecord))
+
+	return svc, connRecord.ConnectionID
+}
+
+func TestService_RotateDID(t *testing.T) {
+	t.Run("rotate my DID", func(t *testing.T) {
+		var sent *Rotate
+
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDIDArg, theirDIDArg string) error {
+				require.Equal(t, myDID, myDIDArg)
+				require.Equal(t, theirDID, theirDIDArg)
+
+				sent = msg.(*Rotate)
+
+				return nil
+			},
+		}, stateNameCompleted)
+
+		err := svc.RotateDID(connID, rotatedDID)
+		require.NoError(t, err)
+
+		require.NotNil(t, sent)
+		require.Equal(t, RotateMsgType, sent.Type)
+		require.NotEmpty(t, sent.ID)
+		require.Equal(t, rotatedDID, sent.ToDID)
+		require.Equal(t, rotateThID, sent.Thread.ID)
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, rotatedDID, connRecord.MyDID)
+		require.Equal(t, theirDID, connRecord.TheirDID)
+
+		currentMyDID, currentTheirDID, err := svc.connectionStore.GetCurrentDIDs(myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, rotatedDID, currentMyDID)
+		require.Equal(t, theirDID, currentTheirDID)
+	})
+
+	t.Run("connection is not found", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		err := svc.RotateDID("unknown", rotatedDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rotate DID")
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameRequested)
+
+		err := svc.RotateDID(connID, rotatedDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection is not completed: state=requested")
+	})
+
+	t.Run("new DID is not resolvable", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+		svc.ctx.vdriRegistry = &mockvdri.MockVDRIRegistry{ResolveErr: errors.New(rotateError)}
+
+		err := svc.RotateDID(connID, rotatedDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve new DID[did:example:rotated-did]: "+rotateError)
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, myDID, connRecord.MyDID)
+	})
+
+	t.Run("send rotate message error", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{SendErr: errors.New(rotateError)},
+			stateNameCompleted)
+
+		err := svc.RotateDID(connID, rotatedDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send rotate message: "+rotateError)
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, myDID, connRecord.MyDID)
+	})
+}
+
+func TestService_HandleInboundRotate(t *testing.T) {
+	rotateMsg := func(thID string) service.DIDCommMsg {
+		return service.NewDIDCommMsgMap(&Rotate{
+			Type:   RotateMsgType,
+			ID:     generateRandomID(),
+			ToDID:  rotatedDID,
+			Thread: &decorator.Thread{ID: thID},
+		})
+	}
+
+	t.Run("rotate their DID", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+		require.True(t, svc.Accept(RotateMsgType))
+
+		id, err := svc.HandleInbound(rotateMsg(rotateThID), myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, connID, id)
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, myDID, connRecord.MyDID)
+		require.Equal(t, rotatedDID, connRecord.TheirDID)
+
+		currentMyDID, currentTheirDID, err := svc.connectionStore.GetCurrentDIDs(myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, myDID, currentMyDID)
+		require.Equal(t, rotatedDID, currentTheirDID)
+	})
+
+	t.Run("connection of the thread is not found", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(rotateMsg("unknown"), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection of thread unknown: data not found")
+	})
+
+	t.Run("thread ID is missing", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(rotateMsg(""), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "thread ID is missing")
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameResponded)
+
+		_, err := svc.HandleInbound(rotateMsg(rotateThID), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection is not completed: state=responded")
+	})
+
+	t.Run("sender is not the other party of the connection", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(rotateMsg(rotateThID), myDID, "did:example:other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sender DID[did:example:other] is not their DID of the connection")
+	})
+
+	t.Run("sender is not authenticated", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(rotateMsg(rotateThID), myDID, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sender DID[] is not their DID of the connection")
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, theirDID, connRecord.TheirDID)
+	})
+
+	t.Run("recipient is not this party of the connection", func(t *testing.T) {
+		svc, _ := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+
+		_, err := svc.HandleInbound(rotateMsg(rotateThID), "did:example:other", theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recipient DID[did:example:other] is not my DID of the connection")
+	})
+
+	t.Run("new DID is not resolvable", func(t *testing.T) {
+		svc, connID := newRotateService(t, &mockdispatcher.MockOutbound{}, stateNameCompleted)
+		svc.ctx.vdriRegistry = &mockvdri.MockVDRIRegistry{ResolveErr: errors.New(rotateError)}
+
+		_, err := svc.HandleInbound(rotateMsg(rotateThID), myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve new DID[did:example:rotated-did]: "+rotateError)
+
+		connRecord, err := svc.connectionStore.GetConnectionRecord(connID)
+		require.NoError(t, err)
+		require.Equal(t, theirDID, connRecord.TheirDID)
+	})
+}
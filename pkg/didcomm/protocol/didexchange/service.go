@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
@@ -26,6 +27,9 @@ import (
 
 var logger = log.New("aries-framework/did-exchange/service")
 
+// ErrInvitationExpired is returned when the invitation has expired (see Invitation Timing)
+var ErrInvitationExpired = errors.New("invitation expired")
+
 const (
 	// DIDExchange did exchange protocol
 	DIDExchange = "didexchange"
@@ -39,6 +43,18 @@ const (
 	ResponseMsgType = DIDExchangeSpec + "response"
 	// AckMsgType defines the did-exchange ack message type.
 	AckMsgType = DIDExchangeSpec + "ack"
+	// OOBSpec defines the out-of-band spec
+	OOBSpec = "https://didcomm.org/out-of-band/1.0/"
+	// OOBInvitationMsgType defines the out-of-band invitation message type.
+	OOBInvitationMsgType = OOBSpec + "invitation"
+	// HandshakeReuseMsgType defines the out-of-band handshake reuse message type.
+	HandshakeReuseMsgType = OOBSpec + "handshake-reuse"
+	// HandshakeReuseAcceptedMsgType defines the out-of-band handshake reuse accepted message type.
+	HandshakeReuseAcceptedMsgType = OOBSpec + "handshake-reuse-accepted"
+	// DIDRotateSpec defines the DID rotate spec
+	DIDRotateSpec = "https://didcomm.org/did-rotate/1.0/"
+	// RotateMsgType defines the DID rotate message type.
+	RotateMsgType = DIDRotateSpec + "rotate"
 )
 
 // message type to store data for eventing. This is retrieved during callback.
@@ -75,9 +91,29 @@ type stateMachineMsg struct {
 type Service struct {
 	service.Action
 	service.Message
-	ctx             *context
-	callbackChannel chan *message
-	connectionStore *connectionStore
+	ctx               *context
+	callbackChannel   chan *message
+	connectionStore   *connectionStore
+	connectionHistory bool
+	clock             clock.Clock
+}
+
+// Opt is the DID exchange service option
+type Opt func(s *Service)
+
+// WithClock sets the clock of the service which is used to check the expiry of the invitations.
+func WithClock(c clock.Clock) Opt {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithConnectionHistory enables persisting of each state transition of the connections
+// (from-state, to-state, message type and time). The history is available via connection store lookup.
+func WithConnectionHistory() Opt {
+	return func(s *Service) {
+		s.connectionHistory = true
+	}
 }
 
 type context struct {
@@ -98,7 +134,7 @@ type opts interface {
 }
 
 // New return didexchange service
-func New(prov provider) (*Service, error) {
+func New(prov provider, opts ...Opt) (*Service, error) {
 	connRecorder, err := newConnectionStore(prov)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize connection store : %w", err)
@@ -127,6 +163,11 @@ func New(prov provider) (*Service, error) {
 		// TODO channel size - https://github.com/hyperledger/aries-framework-go/issues/246
 		callbackChannel: make(chan *message, callbackChannelSize),
 		connectionStore: connRecorder,
+		clock:           clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(svc)
 	}
 
 	// start the listener
@@ -139,6 +180,32 @@ func New(prov provider) (*Service, error) {
 func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	logger.Debugf("receive inbound message : %s", msg)
 
+	switch msg.Type() {
+	case RotateMsgType:
+		return s.handleInboundRotate(msg, myDID, theirDID)
+	case HandshakeReuseMsgType:
+		return s.handleInboundReuse(msg, myDID, theirDID)
+	case HandshakeReuseAcceptedMsgType:
+		return s.handleInboundReuseAccepted(msg, myDID, theirDID)
+	}
+
+	return s.handleInboundExchange(msg, nil)
+}
+
+// HandleInvitation handles the received invitation, the connection created for the invitation is assigned
+// the given metadata (if any). Unlike the metadata of the invitations created by the agent (see Recorder
+// SaveInvitationMetadata), the metadata is saved with the connection record only.
+func (s *Service) HandleInvitation(msg service.DIDCommMsg, metadata map[string]interface{}) (string, error) {
+	if msg.Type() != InvitationMsgType {
+		return "", fmt.Errorf("handle invitation : invalid message type %s", msg.Type())
+	}
+
+	return s.handleInboundExchange(msg, metadata)
+}
+
+// handleInboundExchange handles the messages of the exchange, metadata is assigned to the connection created
+// for the received invitation.
+func (s *Service) handleInboundExchange(msg service.DIDCommMsg, metadata map[string]interface{}) (string, error) {
 	// fetch the thread id
 	thID, err := threadID(msg)
 	if err != nil {
@@ -152,7 +219,7 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 	}
 
 	// connection record
-	connRecord, err := s.connectionRecord(msg)
+	connRecord, err := s.connectionRecord(msg, metadata)
 	if err != nil {
 		return "", err
 	}
@@ -193,7 +260,10 @@ func (s *Service) Accept(msgType string) bool {
 	return msgType == InvitationMsgType ||
 		msgType == RequestMsgType ||
 		msgType == ResponseMsgType ||
-		msgType == AckMsgType
+		msgType == AckMsgType ||
+		msgType == RotateMsgType ||
+		msgType == HandshakeReuseMsgType ||
+		msgType == HandshakeReuseAcceptedMsgType
 }
 
 // HandleOutbound handles outbound didexchange messages.
@@ -512,6 +582,12 @@ func (s *Service) currentState(nsThID string) (state, error) {
 }
 
 func (s *Service) update(msgType string, connectionRecord *connection.Record) error {
+	if s.connectionHistory {
+		if err := s.saveStateTransition(msgType, connectionRecord); err != nil {
+			return err
+		}
+	}
+
 	if (msgType == RequestMsgType && connectionRecord.State == stateNameRequested) ||
 		(msgType == InvitationMsgType && connectionRecord.State == stateNameInvited) {
 		return s.connectionStore.saveConnectionRecordWithMapping(connectionRecord)
@@ -520,10 +596,37 @@ func (s *Service) update(msgType string, connectionRecord *connection.Record) er
 	return s.connectionStore.saveConnectionRecord(connectionRecord)
 }
 
-func (s *Service) connectionRecord(msg service.DIDCommMsg) (*connection.Record, error) {
+// saveStateTransition persists the transition of the connection from the stored state to the state of the record.
+func (s *Service) saveStateTransition(msgType string, connectionRecord *connection.Record) error {
+	var fromState string
+
+	prev, err := s.connectionStore.GetConnectionRecord(connectionRecord.ConnectionID)
+
+	switch {
+	case err == nil:
+		fromState = prev.State
+	case !errors.Is(err, storage.ErrDataNotFound):
+		return fmt.Errorf("save state transition: %w", err)
+	}
+
+	err = s.connectionStore.SaveStateTransition(connectionRecord.ConnectionID, connection.StateTransition{
+		FromState: fromState,
+		ToState:   connectionRecord.State,
+		MsgType:   msgType,
+		Time:      s.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("save state transition: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) connectionRecord(msg service.DIDCommMsg,
+	metadata map[string]interface{}) (*connection.Record, error) {
 	switch msg.Type() {
 	case InvitationMsgType:
-		return s.invitationMsgRecord(msg)
+		return s.invitationMsgRecord(msg, metadata)
 	case RequestMsgType:
 		return s.requestMsgRecord(msg)
 	case ResponseMsgType:
@@ -535,7 +638,11 @@ func (s *Service) connectionRecord(msg service.DIDCommMsg) (*connection.Record,
 	return nil, errors.New("invalid message type")
 }
 
-func (s *Service) invitationMsgRecord(msg service.DIDCommMsg) (*connection.Record, error) {
+// invitationMsgRecord creates the connection record of the received invitation with the given metadata.
+// The metadata saved for the invitations created by the agent is not looked up, as the ID of the received
+// invitation is chosen by the inviter.
+func (s *Service) invitationMsgRecord(msg service.DIDCommMsg,
+	metadata map[string]interface{}) (*connection.Record, error) {
 	thID, msgErr := msg.ThreadID()
 	if msgErr != nil {
 		return nil, msgErr
@@ -561,8 +668,10 @@ func (s *Service) invitationMsgRecord(msg service.DIDCommMsg) (*connection.Recor
 		InvitationDID:   invitation.DID,
 		ServiceEndPoint: invitation.ServiceEndpoint,
 		RecipientKeys:   []string{recKey},
+		RoutingKeys:     invitation.RoutingKeys,
 		TheirLabel:      invitation.Label,
 		Namespace:       findNamespace(msg.Type()),
+		Metadata:        metadata,
 	}
 
 	if err := s.connectionStore.saveConnectionRecord(connRecord); err != nil {
@@ -588,6 +697,17 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 		Namespace:    theirNSPrefix,
 	}
 
+	if request.Thread != nil {
+		if err = s.checkInvitationExpiry(request.Thread.PID); err != nil {
+			return nil, err
+		}
+
+		connRecord.Metadata, err = s.invitationMetadata(request.Thread.PID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.connectionStore.saveConnectionRecord(connRecord); err != nil {
 		return nil, err
 	}
@@ -595,6 +715,49 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 	return connRecord, nil
 }
 
+// checkInvitationExpiry rejects the request to the invitation which has expired (if the invitation is stored)
+func (s *Service) checkInvitationExpiry(invitationID string) error {
+	if invitationID == "" || isDID(invitationID) {
+		return nil
+	}
+
+	var invitation Invitation
+
+	err := s.connectionStore.GetInvitation(invitationID, &invitation)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get invitation: %w", err)
+	}
+
+	if invitation.Timing != nil && !invitation.Timing.ExpiresTime.IsZero() &&
+		s.clock.Now().After(invitation.Timing.ExpiresTime) {
+		return fmt.Errorf("request to invitation %s: %w", invitationID, ErrInvitationExpired)
+	}
+
+	return nil
+}
+
+// invitationMetadata returns the metadata to be assigned to the connections of the invitation (if any)
+func (s *Service) invitationMetadata(invitationID string) (map[string]interface{}, error) {
+	if invitationID == "" {
+		return nil, nil
+	}
+
+	metadata, err := s.connectionStore.GetInvitationMetadata(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get invitation metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
 func (s *Service) responseMsgRecord(payload service.DIDCommMsg) (*connection.Record, error) {
 	return s.fetchConnectionRecord(myNSPrefix, payload)
 }
@@ -647,7 +810,7 @@ func (s *Service) CreateImplicitInvitation(inviterLabel, inviterDID, inviteeLabe
 		return "", fmt.Errorf("resolve public did[%s]: %w", inviterDID, err)
 	}
 
-	dest, err := service.CreateDestination(didDoc)
+	dest, err := service.NewDestinationFromDID(didDoc)
 	if err != nil {
 		return "", err
 	}
@@ -661,6 +824,7 @@ func (s *Service) CreateImplicitInvitation(inviterLabel, inviterDID, inviteeLabe
 		Implicit:        true,
 		ServiceEndPoint: dest.ServiceEndpoint,
 		RecipientKeys:   dest.RecipientKeys,
+		RoutingKeys:     dest.RoutingKeys,
 		TheirLabel:      inviterLabel,
 		Namespace:       findNamespace(InvitationMsgType),
 	}
@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -390,6 +391,20 @@ func TestService_Handle_EdgeCases(t *testing.T) {
 			"null -> responded")
 	})
 
+	t.Run("handle invitation - not an invitation", func(t *testing.T) {
+		svc, err := New(&protocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				route.Coordination: &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		didMsg := generateRequestMsgPayload(t, &protocol.MockProvider{}, randomString(), "")
+
+		_, err = svc.HandleInvitation(didMsg, map[string]interface{}{"user": "alice"})
+		require.EqualError(t, err, "handle invitation : invalid message type "+RequestMsgType)
+	})
+
 	t.Run("handleInbound - threadID error", func(t *testing.T) {
 		svc, err := New(&protocol.MockProvider{
 			ServiceMap: map[string]interface{}{
@@ -693,6 +708,92 @@ func TestEventsSuccess(t *testing.T) {
 	}
 }
 
+func TestConnectionHistory(t *testing.T) {
+	now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	svc, err := New(&protocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	}, WithConnectionHistory(), WithClock(clock.Fixed(now)))
+	require.NoError(t, err)
+	require.True(t, svc.connectionHistory)
+
+	actionCh := make(chan service.DIDCommAction, 10)
+	require.NoError(t, svc.RegisterActionEvent(actionCh))
+
+	go func() { service.AutoExecuteActionEvent(actionCh) }()
+
+	statusCh := make(chan service.StateMsg, 10)
+	require.NoError(t, svc.RegisterMsgEvent(statusCh))
+
+	done := make(chan string)
+
+	go func() {
+		for e := range statusCh {
+			if e.Type == service.PostState && e.StateID == stateNameRequested {
+				done <- e.Properties.(*didExchangeEvent).ConnectionID()
+			}
+		}
+	}()
+
+	pubKey, _ := generateKeyPair()
+	invite, err := json.Marshal(
+		&Invitation{
+			Type:          InvitationMsgType,
+			ID:            randomString(),
+			Label:         "test",
+			RecipientKeys: []string{pubKey},
+		},
+	)
+	require.NoError(t, err)
+
+	didMsg, err := service.ParseDIDCommMsgMap(invite)
+	require.NoError(t, err)
+
+	_, err = svc.HandleInbound(didMsg, "", "")
+	require.NoError(t, err)
+
+	var connectionID string
+
+	select {
+	case connectionID = <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "tests are not validated")
+	}
+
+	history, err := svc.connectionStore.GetConnectionHistory(connectionID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	require.Equal(t, stateNameNull, history[0].FromState)
+	require.Equal(t, stateNameInvited, history[0].ToState)
+	require.Equal(t, InvitationMsgType, history[0].MsgType)
+
+	require.Equal(t, stateNameInvited, history[1].FromState)
+	require.Equal(t, stateNameRequested, history[1].ToState)
+	require.Equal(t, InvitationMsgType, history[1].MsgType)
+	require.True(t, now.Equal(history[0].Time))
+	require.True(t, now.Equal(history[1].Time))
+}
+
+func TestService_saveStateTransitionError(t *testing.T) {
+	connectionStore, err := newConnectionStore(&protocol.MockProvider{
+		StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:  make(map[string][]byte),
+			ErrGet: errors.New("get error"),
+		}),
+	})
+	require.NoError(t, err)
+
+	svc := &Service{connectionStore: connectionStore, connectionHistory: true}
+
+	err = svc.update(RequestMsgType, &connection.Record{ThreadID: "123", ConnectionID: "123456",
+		State: stateNameRequested, Namespace: findNamespace(RequestMsgType)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "save state transition: get error")
+}
+
 func TestContinueWithPublicDID(t *testing.T) {
 	didDoc := mockdiddoc.GetMockDIDDoc()
 	svc, err := New(&protocol.MockProvider{
@@ -952,7 +1053,7 @@ func TestConnectionRecord(t *testing.T) {
 	require.NoError(t, err)
 
 	conn, err := svc.connectionRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
-		randomString(), ""))
+		randomString(), ""), nil)
 	require.NoError(t, err)
 	require.NotNil(t, conn)
 
@@ -964,7 +1065,7 @@ func TestConnectionRecord(t *testing.T) {
 	msg, err := service.ParseDIDCommMsgMap(requestBytes)
 	require.NoError(t, err)
 
-	_, err = svc.connectionRecord(msg)
+	_, err = svc.connectionRecord(msg, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid message type")
 }
@@ -988,10 +1089,23 @@ func TestInvitationRecord(t *testing.T) {
 	msg, err := service.ParseDIDCommMsgMap(invitationBytes)
 	require.NoError(t, err)
 
-	conn, err := svc.invitationMsgRecord(msg)
+	conn, err := svc.invitationMsgRecord(msg, nil)
 	require.NoError(t, err)
 	require.NotNil(t, conn)
 
+	// the metadata of the invitation created by the agent with the same ID is not assigned
+	require.NoError(t, svc.connectionStore.SaveInvitationMetadata("id", map[string]interface{}{"user": "bob"}))
+
+	conn, err = svc.invitationMsgRecord(msg, nil)
+	require.NoError(t, err)
+	require.Empty(t, conn.Metadata)
+
+	metadata := map[string]interface{}{"user": "alice"}
+
+	conn, err = svc.invitationMsgRecord(msg, metadata)
+	require.NoError(t, err)
+	require.Equal(t, metadata, conn.Metadata)
+
 	// invalid thread id
 	invitationBytes, err = json.Marshal(&Invitation{
 		Type: "invalid-type",
@@ -1000,7 +1114,7 @@ func TestInvitationRecord(t *testing.T) {
 	msg, err = service.ParseDIDCommMsgMap(invitationBytes)
 	require.NoError(t, err)
 
-	_, err = svc.invitationMsgRecord(msg)
+	_, err = svc.invitationMsgRecord(msg, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "threadID not found")
 
@@ -1026,7 +1140,7 @@ func TestInvitationRecord(t *testing.T) {
 	msg, err = service.ParseDIDCommMsgMap(invitationBytes)
 	require.NoError(t, err)
 
-	_, err = svc.invitationMsgRecord(msg)
+	_, err = svc.invitationMsgRecord(msg, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "save connection record")
 }
@@ -1060,6 +1174,49 @@ func TestRequestRecord(t *testing.T) {
 		randomString(), ""))
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "save connection record")
+
+	// metadata of the invitation
+	svc, err = New(&protocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	invitationID := randomString()
+	metadata := map[string]interface{}{"user": "alice"}
+	require.NoError(t, svc.connectionStore.SaveInvitationMetadata(invitationID, metadata))
+
+	conn, err = svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+		randomString(), invitationID))
+	require.NoError(t, err)
+	require.Equal(t, metadata, conn.Metadata)
+
+	// the invitation has expired
+	now := time.Now()
+
+	svc, err = New(&protocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			route.Coordination: &mockroute.MockRouteSvc{},
+		},
+	}, WithClock(clock.Fixed(now)))
+	require.NoError(t, err)
+
+	invitationID = randomString()
+	require.NoError(t, svc.connectionStore.SaveInvitation(invitationID, &Invitation{
+		ID:     invitationID,
+		Timing: &decorator.Timing{ExpiresTime: now.Add(time.Minute)},
+	}))
+
+	_, err = svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+		randomString(), invitationID))
+	require.NoError(t, err)
+
+	svc.clock = clock.Fixed(now.Add(2 * time.Minute))
+
+	_, err = svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+		randomString(), invitationID))
+	require.True(t, errors.Is(err, ErrInvitationExpired))
 }
 
 func TestAcceptExchangeRequest(t *testing.T) {
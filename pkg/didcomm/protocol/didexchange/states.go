@@ -355,7 +355,7 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 	connRec.MyDID = connection.DID
 	connRec.TheirLabel = request.Label
 
-	destination, err := service.CreateDestination(requestDidDoc)
+	destination, err := service.NewDestinationFromDID(requestDidDoc)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -550,7 +550,7 @@ func (ctx *context) handleInboundResponse(response *Response) (stateAction, *con
 		return nil, nil, fmt.Errorf("resolve did doc from exchange response connection: %w", err)
 	}
 
-	destination, err := service.CreateDestination(responseDidDoc)
+	destination, err := service.NewDestinationFromDID(responseDidDoc)
 	if err != nil {
 		return nil, nil, fmt.Errorf("prepare destination from response did doc: %w", err)
 	}
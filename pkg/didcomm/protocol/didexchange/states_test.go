@@ -1081,6 +1081,10 @@ func (s *mockSigner) SignMessage(message []byte, fromVerKey string) ([]byte, err
 	return nil, s.err
 }
 
+func (s *mockSigner) VerifyMessage(message, signature []byte, fromVerKey string) error {
+	return s.err
+}
+
 func createDIDDoc() *diddoc.Doc {
 	pubKey, _ := generateKeyPair()
 	return createDIDDocWithKey(pubKey)
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package introduce_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -527,8 +528,8 @@ func newMessenger(f *flow) service.Messenger {
 	require.NoError(f.t, err)
 
 	dispatcherOutbound.EXPECT().
-		SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
-		Do(func(msg service.DIDCommMsg, myid string, dest string) error {
+		SendToDIDWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, msg service.DIDCommMsg, myid string, dest string) error {
 			require.NoError(f.t, msgr.HandleInbound(msg.(service.DIDCommMsgMap), dest, myid))
 			f.transport[dest] <- msg
 			return nil
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+// StatusRequest message pickup status request message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#status-request
+type StatusRequest struct {
+	Type         string `json:"@type,omitempty"`
+	ID           string `json:"@id,omitempty"`
+	RecipientKey string `json:"recipient_key,omitempty"`
+}
+
+// Status message pickup status message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#status
+type Status struct {
+	Type         string `json:"@type,omitempty"`
+	ID           string `json:"@id,omitempty"`
+	RecipientKey string `json:"recipient_key,omitempty"`
+	MessageCount int    `json:"message_count"`
+}
+
+// DeliveryRequest message pickup delivery request message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#delivery-request
+type DeliveryRequest struct {
+	Type         string `json:"@type,omitempty"`
+	ID           string `json:"@id,omitempty"`
+	RecipientKey string `json:"recipient_key,omitempty"`
+	Limit        int    `json:"limit"`
+}
+
+// MessagesReceived message pickup messages received message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#messages-received
+type MessagesReceived struct {
+	Type          string   `json:"@type,omitempty"`
+	ID            string   `json:"@id,omitempty"`
+	MessageIDList []string `json:"message_id_list,omitempty"`
+}
@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// forwardPrefix is the prefix of the forwarded messages of the connection, forward|theirDID|
+	forwardPrefix = "forward|%s|"
+	// forwardKey is the key of the forwarded message, forward|theirDID|recipientKey|timestamp|msgID
+	forwardKey = "%s%s|%020d|%s"
+	// limitPattern is a limit of the key range for the given prefix
+	limitPattern = "%s~"
+)
+
+// storageProvider opens the store of the queue
+type storageProvider interface {
+	StorageProvider() storage.Provider
+}
+
+// Queue keeps the forwarded messages which the mediator could not deliver to the recipients, until
+// the recipients pick them up (see Service). The messages are queued per recipient key of the connection
+// with the recipient (e.g. by the route service when the recipient is not reachable).
+type Queue struct {
+	store storage.Store
+}
+
+// NewQueue returns the queue of the forwarded messages.
+func NewQueue(prov storageProvider) (*Queue, error) {
+	store, err := prov.StorageProvider().OpenStore(MessagePickup)
+	if err != nil {
+		return nil, fmt.Errorf("open message pickup store : %w", err)
+	}
+
+	return &Queue{store: store}, nil
+}
+
+// Add queues the forwarded message (i.e. the packed message) for the recipient key of the connection
+// with theirDID.
+func (q *Queue) Add(theirDID, recipientKey string, msg interface{}) error {
+	src, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("queue forwarded message : %w", err)
+	}
+
+	key := fmt.Sprintf(forwardKey, fmt.Sprintf(forwardPrefix, theirDID), recipientKey, time.Now().UnixNano(),
+		uuid.New().String())
+
+	if err = q.store.Put(key, src); err != nil {
+		return fmt.Errorf("queue forwarded message : %w", err)
+	}
+
+	return nil
+}
+
+type queuedMessage struct {
+	key       string
+	timestamp string
+	id        string
+	value     []byte
+}
+
+// messages returns up to limit messages queued for the recipient key of the connection with theirDID
+// (for all recipient keys if recipientKey is empty) in the order they were queued
+func (q *Queue) messages(theirDID, recipientKey string, limit int) ([]queuedMessage, error) {
+	prefix := fmt.Sprintf(forwardPrefix, theirDID)
+
+	itr := q.store.Iterator(prefix, fmt.Sprintf(limitPattern, prefix))
+	defer itr.Release()
+
+	var msgs []queuedMessage
+
+	for itr.Next() {
+		// the rest of the key is recipientKey|timestamp|msgID
+		parts := strings.Split(strings.TrimPrefix(string(itr.Key()), prefix), "|")
+		if len(parts) != 3 || (recipientKey != "" && parts[0] != recipientKey) {
+			continue
+		}
+
+		msgs = append(msgs, queuedMessage{
+			key:       string(itr.Key()),
+			timestamp: parts[1],
+			id:        parts[2],
+			value:     append([]byte(nil), itr.Value()...),
+		})
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("iterate forwarded messages : %w", err)
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		if msgs[i].timestamp != msgs[j].timestamp {
+			return msgs[i].timestamp < msgs[j].timestamp
+		}
+
+		return msgs[i].id < msgs[j].id
+	})
+
+	if limit > 0 && len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+
+	return msgs, nil
+}
+
+// attachments returns the forwarded messages as the attachments of the delivery message
+func attachments(msgs []queuedMessage) ([]decorator.Attachment, error) {
+	result := make([]decorator.Attachment, 0, len(msgs))
+
+	for _, m := range msgs {
+		var data interface{}
+
+		if err := json.Unmarshal(m.value, &data); err != nil {
+			return nil, fmt.Errorf("unmarshal forwarded message %s : %w", m.id, err)
+		}
+
+		result = append(result, decorator.Attachment{
+			ID:   m.id,
+			Data: decorator.AttachmentData{JSON: data},
+		})
+	}
+
+	return result, nil
+}
+
+// remove removes the messages with the given IDs queued for the connection with theirDID
+func (q *Queue) remove(theirDID string, msgIDs ...string) error {
+	msgs, err := q.messages(theirDID, "", 0)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]bool, len(msgIDs))
+	for _, id := range msgIDs {
+		ids[id] = true
+	}
+
+	for _, m := range msgs {
+		if !ids[m.id] {
+			continue
+		}
+
+		if err = q.store.Delete(m.key); err != nil {
+			return fmt.Errorf("delete forwarded message %s : %w", m.id, err)
+		}
+	}
+
+	return nil
+}
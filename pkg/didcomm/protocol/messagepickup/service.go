@@ -0,0 +1,360 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// constants for message pickup spec types
+const (
+	// MessagePickup message pickup protocol
+	MessagePickup = "messagepickup"
+
+	// PickupSpec defines the message pickup spec
+	PickupSpec = "https://didcomm.org/messagepickup/2.0/"
+
+	// StatusRequestMsgType defines the message pickup status request message type.
+	StatusRequestMsgType = PickupSpec + "status-request"
+
+	// StatusMsgType defines the message pickup status message type.
+	StatusMsgType = PickupSpec + "status"
+
+	// DeliveryRequestMsgType defines the message pickup delivery request message type.
+	DeliveryRequestMsgType = PickupSpec + "delivery-request"
+
+	// DeliveryMsgType defines the message pickup delivery message type.
+	DeliveryMsgType = PickupSpec + "delivery"
+
+	// MessagesReceivedMsgType defines the message pickup messages received message type.
+	MessagesReceivedMsgType = PickupSpec + "messages-received"
+)
+
+// ErrUnsupportedMessenger is returned when the messenger of the provider doesn't wait for the replies.
+var ErrUnsupportedMessenger = errors.New("messenger doesn't support waiting for replies")
+
+// ErrUnexpectedReply is returned when the mediator replies with the message of unexpected type.
+var ErrUnexpectedReply = errors.New("unexpected reply")
+
+// provider contains dependencies for the message pickup protocol and is typically created by using aries.Context()
+type provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+}
+
+// pendingQueue is the messenger which keeps the queue of the outbound messages of the connections
+// (e.g. *messenger.Messenger created WithRetryQueue).
+type pendingQueue interface {
+	PendingCount(myDID, theirDID string) (int, error)
+	PendingMessages(myDID, theirDID string, limit int) ([]service.DIDCommMsgMap, error)
+	RemovePending(myDID, theirDID string, msgIDs ...string) error
+}
+
+// replyWaiter is the messenger which waits for the replies (e.g. *messenger.Messenger).
+type replyWaiter interface {
+	NewReplyWaiter(threadID, myDID, theirDID string) *messenger.ReplyWaiter
+}
+
+// Service for Message Pickup protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2
+//
+// The mediator delivers the messages which were not delivered because the recipient was not reachable:
+// the forwarded messages queued for the recipient keys of the connection (see Queue) and, unless the recipient
+// key is requested, the messages of the connection queued by the messenger (see messenger.WithRetryQueue).
+// The recipient requests the status of the queue and the delivery of the messages (see StatusRequest and
+// DeliveryRequest) and acknowledges the receipt of them, so the mediator removes them from the queue
+// (see MessagesReceived).
+type Service struct {
+	messenger service.Messenger
+	queue     *Queue
+}
+
+// New returns message pickup service.
+func New(prov provider) (*Service, error) {
+	queue, err := NewQueue(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new message pickup service: %w", err)
+	}
+
+	return &Service{messenger: prov.Messenger(), queue: queue}, nil
+}
+
+// HandleInbound handles inbound message pickup messages.
+// The replies of the mediator (status and delivery) are delivered to the requests which are waiting for them.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	var err error
+
+	switch msg.Type() {
+	case StatusRequestMsgType:
+		err = s.handleStatusRequest(msg, myDID, theirDID)
+	case DeliveryRequestMsgType:
+		err = s.handleDeliveryRequest(msg, myDID, theirDID)
+	case MessagesReceivedMsgType:
+		err = s.handleMessagesReceived(msg, myDID, theirDID)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound handles outbound message pickup messages.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	return errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case StatusRequestMsgType, StatusMsgType, DeliveryRequestMsgType, DeliveryMsgType, MessagesReceivedMsgType:
+		return true
+	}
+
+	return false
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return MessagePickup
+}
+
+func (s *Service) handleStatusRequest(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &StatusRequest{}
+
+	if err := msg.Decode(request); err != nil {
+		return fmt.Errorf("status request message unmarshal : %w", err)
+	}
+
+	return s.replyStatus(msg.ID(), request.RecipientKey, myDID, theirDID)
+}
+
+func (s *Service) replyStatus(msgID, recipientKey, myDID, theirDID string) error {
+	forwarded, err := s.queue.messages(theirDID, recipientKey, 0)
+	if err != nil {
+		return fmt.Errorf("status : %w", err)
+	}
+
+	count := len(forwarded)
+
+	if pending, ok := s.messenger.(pendingQueue); ok && recipientKey == "" {
+		n, err := pending.PendingCount(myDID, theirDID)
+		if err != nil {
+			return fmt.Errorf("status : %w", err)
+		}
+
+		count += n
+	}
+
+	return s.messenger.ReplyTo(msgID, service.NewDIDCommMsgMap(&Status{
+		Type:         StatusMsgType,
+		ID:           uuid.New().String(),
+		RecipientKey: recipientKey,
+		MessageCount: count,
+	}))
+}
+
+func (s *Service) handleDeliveryRequest(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &DeliveryRequest{}
+
+	if err := msg.Decode(request); err != nil {
+		return fmt.Errorf("delivery request message unmarshal : %w", err)
+	}
+
+	msgs, err := s.deliveredMessages(request, myDID, theirDID)
+	if err != nil {
+		return fmt.Errorf("delivery : %w", err)
+	}
+
+	// the status is sent if there are no messages to deliver
+	if len(msgs) == 0 {
+		return s.replyStatus(msg.ID(), request.RecipientKey, myDID, theirDID)
+	}
+
+	delivery := service.DIDCommMsgMap{
+		"@type": DeliveryMsgType,
+		"@id":   uuid.New().String(),
+	}
+
+	if request.RecipientKey != "" {
+		delivery["recipient_key"] = request.RecipientKey
+	}
+
+	for _, m := range msgs {
+		delivery.AddAttachment(m)
+	}
+
+	return s.messenger.ReplyTo(msg.ID(), delivery)
+}
+
+// deliveredMessages returns up to limit queued messages, the messages queued by the messenger go first
+func (s *Service) deliveredMessages(request *DeliveryRequest, myDID, theirDID string) ([]decorator.Attachment, error) {
+	var result []decorator.Attachment
+
+	if pending, ok := s.messenger.(pendingQueue); ok && request.RecipientKey == "" {
+		msgs, err := pending.PendingMessages(myDID, theirDID, request.Limit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range msgs {
+			result = append(result, decorator.Attachment{ID: m.ID(), Data: decorator.AttachmentData{JSON: m}})
+		}
+	}
+
+	limit := request.Limit
+	if limit > 0 {
+		limit -= len(result)
+
+		if limit == 0 {
+			return result, nil
+		}
+	}
+
+	forwarded, err := s.queue.messages(theirDID, request.RecipientKey, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := attachments(forwarded)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, msgs...), nil
+}
+
+func (s *Service) handleMessagesReceived(msg service.DIDCommMsg, myDID, theirDID string) error {
+	received := &MessagesReceived{}
+
+	if err := msg.Decode(received); err != nil {
+		return fmt.Errorf("messages received message unmarshal : %w", err)
+	}
+
+	if pending, ok := s.messenger.(pendingQueue); ok {
+		if err := pending.RemovePending(myDID, theirDID, received.MessageIDList...); err != nil {
+			return fmt.Errorf("messages received : %w", err)
+		}
+	}
+
+	if err := s.queue.remove(theirDID, received.MessageIDList...); err != nil {
+		return fmt.Errorf("messages received : %w", err)
+	}
+
+	return nil
+}
+
+// StatusRequest requests the status of the message queue of the mediator on the other end of the connection.
+// It blocks until the status is received or ctx is done.
+func (s *Service) StatusRequest(ctx context.Context, myDID, theirDID string) (*Status, error) {
+	reply, err := s.request(ctx, service.NewDIDCommMsgMap(&StatusRequest{
+		Type: StatusRequestMsgType,
+		ID:   uuid.New().String(),
+	}), myDID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("status request : %w", err)
+	}
+
+	if reply.Type() != StatusMsgType {
+		return nil, fmt.Errorf("status request : %w %s", ErrUnexpectedReply, reply.Type())
+	}
+
+	status := &Status{}
+
+	if err = reply.Decode(status); err != nil {
+		return nil, fmt.Errorf("status message unmarshal : %w", err)
+	}
+
+	return status, nil
+}
+
+// DeliveryRequest requests the delivery of up to limit queued messages from the mediator on the other end of
+// the connection and returns them as the attachments of the delivery. The data of the attachment is the message
+// of the connection or the forwarded (packed) message. No messages are returned if the queue is empty.
+// It blocks until the messages are received or ctx is done. The receipt of the messages (i.e. the IDs of
+// the attachments) should be acknowledged by MessagesReceived, otherwise they stay queued and are delivered again.
+func (s *Service) DeliveryRequest(ctx context.Context, myDID, theirDID string,
+	limit int) ([]decorator.Attachment, error) {
+	reply, err := s.request(ctx, service.NewDIDCommMsgMap(&DeliveryRequest{
+		Type:  DeliveryRequestMsgType,
+		ID:    uuid.New().String(),
+		Limit: limit,
+	}), myDID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("delivery request : %w", err)
+	}
+
+	switch reply.Type() {
+	case StatusMsgType:
+		// the queue is empty
+		return nil, nil
+	case DeliveryMsgType:
+	default:
+		return nil, fmt.Errorf("delivery request : %w %s", ErrUnexpectedReply, reply.Type())
+	}
+
+	attachments, err := reply.Attachments()
+	if err != nil {
+		return nil, fmt.Errorf("delivery message : %w", err)
+	}
+
+	for _, a := range attachments {
+		if a.Data.JSON == nil {
+			return nil, fmt.Errorf("delivery message : attachment %s has no message", a.ID)
+		}
+	}
+
+	return attachments, nil
+}
+
+// MessagesReceived acknowledges the receipt of the messages delivered by the mediator on the other end of
+// the connection, so the mediator removes them from the queue.
+func (s *Service) MessagesReceived(myDID, theirDID string, msgIDs []string) error {
+	err := s.messenger.Send(service.NewDIDCommMsgMap(&MessagesReceived{
+		Type:          MessagesReceivedMsgType,
+		ID:            uuid.New().String(),
+		MessageIDList: msgIDs,
+	}), myDID, theirDID)
+	if err != nil {
+		return fmt.Errorf("messages received : %w", err)
+	}
+
+	return nil
+}
+
+// request sends the message and waits for the reply on its thread of the connection
+func (s *Service) request(ctx context.Context, msg service.DIDCommMsgMap,
+	myDID, theirDID string) (service.DIDCommMsgMap, error) {
+	msgr, ok := s.messenger.(replyWaiter)
+	if !ok {
+		return nil, ErrUnsupportedMessenger
+	}
+
+	waiter := msgr.NewReplyWaiter(msg.ID(), myDID, theirDID)
+
+	if err := s.messenger.Send(msg, myDID, theirDID); err != nil {
+		// releases the waiter
+		done, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, _ = waiter.Wait(done)
+
+		return nil, err
+	}
+
+	return waiter.Wait(ctx)
+}
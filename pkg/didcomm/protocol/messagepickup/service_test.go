@@ -0,0 +1,342 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	mediatorDID  = "did:example:mediator"
+	recipientDID = "did:example:recipient"
+	errMsg       = "test error"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, err := New(newAgent(t).provider())
+		require.NoError(t, err)
+		require.Equal(t, MessagePickup, svc.Name())
+		require.EqualError(t, svc.HandleOutbound(nil, "", ""), "not implemented")
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := New(&mockProvider{store: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New(errMsg)}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open message pickup store : "+errMsg)
+	})
+
+	t.Run("unsupported messenger", func(t *testing.T) {
+		svc, err := New(&mockProvider{store: mem.NewProvider()})
+		require.NoError(t, err)
+
+		_, err = svc.StatusRequest(context.Background(), recipientDID, mediatorDID)
+		require.True(t, errors.Is(err, ErrUnsupportedMessenger))
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	svc, err := New(newAgent(t).provider())
+	require.NoError(t, err)
+
+	for _, msgType := range []string{
+		StatusRequestMsgType, StatusMsgType, DeliveryRequestMsgType, DeliveryMsgType, MessagesReceivedMsgType,
+	} {
+		require.True(t, svc.Accept(msgType))
+	}
+
+	require.False(t, svc.Accept("unsupported"))
+}
+
+func TestService_Pickup(t *testing.T) {
+	mediator, recipient := newAgent(t), newAgent(t)
+	mediator.connect(recipient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the recipient is offline, the messages are queued by the mediator
+	recipient.offline = true
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.Error(t, mediator.messenger.Send(service.DIDCommMsgMap{
+			"@id":   id,
+			"@type": "https://didcomm.org/basicmessage/1.0/message",
+		}, mediatorDID, recipientDID))
+	}
+
+	recipient.offline = false
+
+	status, err := recipient.svc.StatusRequest(ctx, recipientDID, mediatorDID)
+	require.NoError(t, err)
+	require.Equal(t, 3, status.MessageCount)
+
+	msgs, err := recipient.svc.DeliveryRequest(ctx, recipientDID, mediatorDID, 2)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.Equal(t, "1", msgs[0].ID)
+	require.Equal(t, "2", msgs[1].ID)
+	require.Equal(t, "https://didcomm.org/basicmessage/1.0/message", msgs[0].Data.JSON.(map[string]interface{})["@type"])
+
+	require.NoError(t, recipient.svc.MessagesReceived(recipientDID, mediatorDID, []string{"1", "2"}))
+
+	status, err = recipient.svc.StatusRequest(ctx, recipientDID, mediatorDID)
+	require.NoError(t, err)
+	require.Equal(t, 1, status.MessageCount)
+
+	msgs, err = recipient.svc.DeliveryRequest(ctx, recipientDID, mediatorDID, 0)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "3", msgs[0].ID)
+
+	require.NoError(t, recipient.svc.MessagesReceived(recipientDID, mediatorDID, []string{"3"}))
+
+	// the status is delivered if the queue is empty
+	msgs, err = recipient.svc.DeliveryRequest(ctx, recipientDID, mediatorDID, 0)
+	require.NoError(t, err)
+	require.Empty(t, msgs)
+}
+
+func TestService_PickupForwarded(t *testing.T) {
+	mediator, recipient := newAgent(t), newAgent(t)
+	mediator.connect(recipient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the forwarded messages for the keys of the recipient, the message of another connection
+	queue, err := NewQueue(mediator)
+	require.NoError(t, err)
+
+	require.NoError(t, queue.Add(recipientDID, "key1", map[string]interface{}{"protected": "1"}))
+	require.NoError(t, queue.Add(recipientDID, "key2", map[string]interface{}{"protected": "2"}))
+	require.NoError(t, queue.Add(recipientDID, "key1", map[string]interface{}{"protected": "3"}))
+	require.NoError(t, queue.Add("did:example:other", "key1", map[string]interface{}{"protected": "4"}))
+
+	// the message of the connection queued by the messenger
+	recipient.offline = true
+	require.Error(t, mediator.messenger.Send(service.DIDCommMsgMap{
+		"@id":   "msg",
+		"@type": "https://didcomm.org/basicmessage/1.0/message",
+	}, mediatorDID, recipientDID))
+	recipient.offline = false
+
+	status, err := recipient.svc.StatusRequest(ctx, recipientDID, mediatorDID)
+	require.NoError(t, err)
+	require.Equal(t, 4, status.MessageCount)
+
+	msgs, err := recipient.svc.DeliveryRequest(ctx, recipientDID, mediatorDID, 3)
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+	require.Equal(t, "msg", msgs[0].ID)
+	require.Equal(t, map[string]interface{}{"protected": "1"}, msgs[1].Data.JSON)
+	require.Equal(t, map[string]interface{}{"protected": "2"}, msgs[2].Data.JSON)
+
+	require.NoError(t, recipient.svc.MessagesReceived(recipientDID, mediatorDID, []string{msgs[0].ID, msgs[1].ID}))
+
+	msgs, err = recipient.svc.DeliveryRequest(ctx, recipientDID, mediatorDID, 0)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+
+	// the messages of the recipient key
+	request := service.NewDIDCommMsgMap(&DeliveryRequest{
+		Type:         DeliveryRequestMsgType,
+		ID:           "delivery",
+		RecipientKey: "key1",
+	})
+
+	waiter := recipient.messenger.NewReplyWaiter(request.ID(), recipientDID, mediatorDID)
+	require.NoError(t, recipient.messenger.Send(request, recipientDID, mediatorDID))
+
+	reply, err := waiter.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "key1", reply["recipient_key"])
+
+	msgs, err = reply.Attachments()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, map[string]interface{}{"protected": "3"}, msgs[0].Data.JSON)
+
+	require.NoError(t, recipient.svc.MessagesReceived(recipientDID, mediatorDID, []string{msgs[0].ID}))
+
+	status, err = recipient.svc.StatusRequest(ctx, recipientDID, mediatorDID)
+	require.NoError(t, err)
+	require.Equal(t, 1, status.MessageCount)
+}
+
+func TestService_Request(t *testing.T) {
+	t.Run("mediator is not reachable", func(t *testing.T) {
+		mediator, recipient := newAgent(t), newAgent(t)
+		mediator.connect(recipient)
+
+		mediator.offline = true
+
+		_, err := recipient.svc.StatusRequest(context.Background(), recipientDID, mediatorDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status request")
+
+		_, err = recipient.svc.DeliveryRequest(context.Background(), recipientDID, mediatorDID, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delivery request")
+
+		err = recipient.svc.MessagesReceived(recipientDID, mediatorDID, []string{"1"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "messages received")
+	})
+
+	t.Run("no reply", func(t *testing.T) {
+		// the peer doesn't support message pickup
+		mediator, recipient := newAgent(t), newAgent(t)
+		mediator.connect(recipient)
+		mediator.svc = nil
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := recipient.svc.StatusRequest(ctx, recipientDID, mediatorDID)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("unexpected reply", func(t *testing.T) {
+		mediator, recipient := newAgent(t), newAgent(t)
+		mediator.connect(recipient)
+		mediator.svc = nil
+		mediator.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			return service.DIDCommMsgMap{"@type": "unexpected"}
+		}
+
+		_, err := recipient.svc.StatusRequest(context.Background(), recipientDID, mediatorDID)
+		require.True(t, errors.Is(err, ErrUnexpectedReply))
+
+		_, err = recipient.svc.DeliveryRequest(context.Background(), recipientDID, mediatorDID, 1)
+		require.True(t, errors.Is(err, ErrUnexpectedReply))
+	})
+
+	t.Run("invalid delivery", func(t *testing.T) {
+		mediator, recipient := newAgent(t), newAgent(t)
+		mediator.connect(recipient)
+		mediator.svc = nil
+		mediator.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			return service.DIDCommMsgMap{
+				"@type":   DeliveryMsgType,
+				"~attach": []interface{}{map[string]interface{}{"@id": "1", "data": map[string]interface{}{}}},
+			}
+		}
+
+		_, err := recipient.svc.DeliveryRequest(context.Background(), recipientDID, mediatorDID, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "attachment 1 has no message")
+	})
+}
+
+// agent is the party of the connection which delivers the messages to its peer synchronously
+type agent struct {
+	t         *testing.T
+	store     storage.Provider
+	messenger *messenger.Messenger
+	svc       *Service
+	peer      *agent
+	offline   bool
+	reply     func(msg service.DIDCommMsgMap) service.DIDCommMsgMap
+}
+
+func newAgent(t *testing.T) *agent {
+	a := &agent{t: t, store: mem.NewProvider()}
+
+	msgr, err := messenger.NewMessenger(a, messenger.WithRetryQueue())
+	require.NoError(t, err)
+
+	a.messenger = msgr
+
+	svc, err := New(a.provider())
+	require.NoError(t, err)
+
+	a.svc = svc
+
+	return a
+}
+
+func (a *agent) connect(peer *agent) {
+	a.peer, peer.peer = peer, a
+}
+
+func (a *agent) provider() *mockProvider {
+	return &mockProvider{messenger: a.messenger, store: a.store}
+}
+
+func (a *agent) OutboundDispatcher() dispatcher.Outbound {
+	return &outbound{agent: a}
+}
+
+func (a *agent) StorageProvider() storage.Provider {
+	return a.store
+}
+
+// receive handles the inbound message the way the framework does
+func (a *agent) receive(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if a.offline {
+		return errors.New("agent is offline")
+	}
+
+	if err := a.messenger.HandleInbound(msg, myDID, theirDID); err != nil {
+		return err
+	}
+
+	if a.reply != nil {
+		return a.messenger.ReplyTo(msg.ID(), a.reply(msg))
+	}
+
+	if a.svc == nil {
+		return nil
+	}
+
+	_, err := a.svc.HandleInbound(msg, myDID, theirDID)
+
+	return err
+}
+
+// outbound delivers the messages of the agent to its peer
+type outbound struct {
+	dispatcher.Outbound
+	agent *agent
+}
+
+func (o *outbound) SendToDIDWithContext(_ context.Context, msg interface{}, myDID, theirDID string) error {
+	raw, err := json.Marshal(msg)
+	require.NoError(o.agent.t, err)
+
+	inbound, err := service.ParseDIDCommMsgMap(raw)
+	require.NoError(o.agent.t, err)
+
+	return o.agent.peer.receive(inbound, theirDID, myDID)
+}
+
+type mockProvider struct {
+	messenger service.Messenger
+	store     storage.Provider
+}
+
+func (p *mockProvider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+func (p *mockProvider) StorageProvider() storage.Provider {
+	return p.store
+}
@@ -13,4 +13,7 @@ type ProtocolService interface {
 
 	// Config gives back the router configuration
 	Config() (*Config, error)
+
+	// ConnectionConfig gives back the configuration of the router on the other end of the connection
+	ConnectionConfig(connectionID string) (*Config, error)
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -107,6 +108,7 @@ type Service struct {
 	routeRegistrationMapLock sync.RWMutex
 	keylistUpdateMap         map[string]chan *KeylistUpdateResponse
 	keylistUpdateMapLock     sync.RWMutex
+	pickupQueue              *messagepickup.Queue
 }
 
 // New return route coordination service.
@@ -121,6 +123,12 @@ func New(prov provider) (*Service, error) {
 		return nil, err
 	}
 
+	// the forwarded messages which were not delivered are picked up by the recipients
+	pickupQueue, err := messagepickup.NewQueue(prov)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
 		routeStore:           store,
 		outbound:             prov.OutboundDispatcher(),
@@ -130,6 +138,7 @@ func New(prov provider) (*Service, error) {
 		connectionLookup:     connectionLookup,
 		routeRegistrationMap: make(map[string]chan Grant),
 		keylistUpdateMap:     make(map[string]chan *KeylistUpdateResponse),
+		pickupQueue:          pickupQueue,
 	}, nil
 }
 
@@ -326,7 +335,16 @@ func (s *Service) handleForward(msg service.DIDCommMsg) error {
 		return fmt.Errorf("get destination : %w", err)
 	}
 
-	return s.outbound.Forward(forward.Msg, dest)
+	if err = s.outbound.Forward(forward.Msg, dest); err != nil {
+		// the recipient is not reachable, the message is kept until the recipient picks it up
+		logger.Debugf("queue forwarded message for %s : %v", forward.To, err)
+
+		if qErr := s.pickupQueue.Add(string(theirDID), forward.To, forward.Msg); qErr != nil {
+			return fmt.Errorf("forward message : %w (queue for pickup : %v)", err, qErr)
+		}
+	}
+
+	return nil
 }
 
 // Register registers the agent with the router on the other end of the connection identified by
@@ -483,6 +501,22 @@ func (s *Service) Config() (*Config, error) {
 	return s.getRouterConfig()
 }
 
+// ConnectionConfig fetches the config (endpoint and routingKeys) of the router on the other end of the connection.
+// ErrRouterNotRegistered is returned if the agent is not registered with the router of the connection.
+// TODO https://github.com/hyperledger/aries-framework-go/issues/1076 Support for multiple routers
+func (s *Service) ConnectionConfig(connectionID string) (*Config, error) {
+	routerConnID, err := s.getRouterConnectionID()
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("fetch router connection id : %w", err)
+	}
+
+	if routerConnID == "" || routerConnID != connectionID {
+		return nil, ErrRouterNotRegistered
+	}
+
+	return s.getRouterConfig()
+}
+
 func processKeylistUpdateResp(recKey string, keyUpdateResp *KeylistUpdateResponse) error {
 	for _, result := range keyUpdateResp.Updated {
 		if result.RecipientKey == recKey && result.Action == add && result.Result != success {
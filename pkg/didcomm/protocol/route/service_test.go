@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -413,6 +414,46 @@ func TestServiceForwardMsg(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "get destination")
 	})
+
+	t.Run("test service handle forward msg - message is queued for pickup", func(t *testing.T) {
+		to := randomID()
+		store := mockstore.NewMockStoreProvider()
+
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          store,
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateForward: func(msg interface{}, des *service.Destination) error {
+					return errors.New("recipient is not reachable")
+				},
+			},
+			VDRIRegistryValue: &mockvdri.MockVDRIRegistry{ResolveValue: mockdiddoc.GetMockDIDDoc()},
+		})
+		require.NoError(t, err)
+
+		err = svc.routeStore.Put(dataKey(to), []byte("did:example:123"))
+		require.NoError(t, err)
+
+		err = svc.handleForward(generateForwardMsgPayload(t, randomID(), to, &model.Envelope{CipherText: "abc"}))
+		require.NoError(t, err)
+
+		queued := 0
+
+		for k := range store.Store.Store {
+			if strings.HasPrefix(k, "forward|did:example:123|"+to+"|") {
+				queued++
+			}
+		}
+
+		require.Equal(t, 1, queued)
+
+		store.Store.ErrPut = errors.New("put error")
+
+		err = svc.handleForward(generateForwardMsgPayload(t, randomID(), to, &model.Envelope{CipherText: "abc"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recipient is not reachable (queue for pickup : queue forwarded message")
+	})
 }
 
 func TestRegister(t *testing.T) {
@@ -843,6 +884,59 @@ func TestConfig(t *testing.T) {
 	})
 }
 
+func TestConnectionConfig(t *testing.T) {
+	var routingKeys = []string{"abc", "xyz"}
+
+	newService := func(t *testing.T, store *mockstore.MockStore) *Service {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: store},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	t.Run("test connection config - success", func(t *testing.T) {
+		svc := newService(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		require.NoError(t, svc.saveRouterConnectionID("connID-123"))
+		require.NoError(t, svc.saveRouterConfig(&config{
+			RouterEndpoint: ENDPOINT,
+			RoutingKeys:    routingKeys,
+		}))
+
+		conf, err := svc.ConnectionConfig("connID-123")
+		require.NoError(t, err)
+		require.Equal(t, ENDPOINT, conf.Endpoint())
+		require.Equal(t, routingKeys, conf.Keys())
+	})
+
+	t.Run("test connection config - router of the connection is not registered", func(t *testing.T) {
+		svc := newService(t, &mockstore.MockStore{Store: make(map[string][]byte)})
+
+		conf, err := svc.ConnectionConfig("connID-123")
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
+		require.Nil(t, conf)
+
+		require.NoError(t, svc.saveRouterConnectionID("connID-456"))
+
+		conf, err = svc.ConnectionConfig("connID-123")
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
+		require.Nil(t, conf)
+	})
+
+	t.Run("test connection config - router connectionID fetch error", func(t *testing.T) {
+		svc := newService(t, &mockstore.MockStore{Store: make(map[string][]byte), ErrGet: errors.New("get error")})
+
+		conf, err := svc.ConnectionConfig("connID-123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch router connection id")
+		require.Nil(t, conf)
+	})
+}
+
 func generateRequestMsgPayload(t *testing.T, id string) service.DIDCommMsg {
 	requestBytes, err := json.Marshal(&Request{
 		Type: RequestMsgType,
@@ -96,3 +96,8 @@ func (m *mockRouteSvc) Config() (*Config, error) {
 
 	return NewConfig(m.RouterEndpoint, m.RoutingKeys), nil
 }
+
+// ConnectionConfig gives back the router configuration of the connection
+func (m *mockRouteSvc) ConnectionConfig(connectionID string) (*Config, error) {
+	return m.Config()
+}
@@ -8,6 +8,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -92,7 +93,20 @@ func (cs *OutboundHTTPClient) Start(prov transport.Provider) error {
 
 // Send sends a2a exchange data via HTTP (client side)
 func (cs *OutboundHTTPClient) Send(data []byte, destination *service.Destination) (string, error) {
-	resp, err := cs.client.Post(destination.ServiceEndpoint, commContentType, bytes.NewBuffer(data))
+	return cs.SendWithContext(context.Background(), data, destination)
+}
+
+// SendWithContext sends a2a exchange data via HTTP (client side), the request is canceled once ctx is done
+func (cs *OutboundHTTPClient) SendWithContext(ctx context.Context, data []byte,
+	destination *service.Destination) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, destination.ServiceEndpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("creating HTTP request failed: %w", err)
+	}
+
+	req.Header.Set("Content-Type", commContentType)
+
+	resp, err := cs.client.Do(req)
 	if err != nil {
 		logger.Errorf("posting DID envelope to agent failed [%s, %v]", destination.ServiceEndpoint, err)
 		return "", err
@@ -7,8 +7,10 @@
 package http
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -93,6 +95,20 @@ func TestOutboundHTTPTransport(t *testing.T) {
 	require.NoError(t, e)
 	require.NotEmpty(t, r)
 
+	// the sending is aborted with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r, e = ot.SendWithContext(ctx, []byte("Hello World"), prepareDestination(serverURL))
+	require.True(t, errors.Is(e, context.Canceled))
+	require.Empty(t, r)
+
+	// invalid url can't be used to create a request
+	r, e = ot.SendWithContext(context.Background(), []byte("Hello World"), prepareDestination("%%"))
+	require.Error(t, e)
+	require.Contains(t, e.Error(), "creating HTTP request failed")
+	require.Empty(t, r)
+
 	require.True(t, ot.Accept("http://example.com"))
 	require.False(t, ot.Accept("123:22"))
 }
@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+// defaultQueueSize is the number of messages the inbound transport queues by default
+const defaultQueueSize = 100
+
+// InboundOpt is an inbound loopback transport option
+type InboundOpt func(i *Inbound)
+
+// WithQueueSize sets the number of messages the inbound transport queues before the senders are blocked.
+func WithQueueSize(size int) InboundOpt {
+	return func(i *Inbound) {
+		i.queueSize = size
+	}
+}
+
+// Inbound is the loopback inbound transport, it receives the messages sent through the Network.
+// The messages are handled one by one in the order they were sent.
+type Inbound struct {
+	network   *Network
+	endpoint  string
+	queueSize int
+	messages  chan []byte
+	done      chan struct{}
+	started   bool
+	lock      sync.Mutex
+	wg        sync.WaitGroup
+}
+
+// NewInbound creates a new loopback inbound transport listening on the network at the given address
+// (e.g. the DID of the agent).
+func NewInbound(network *Network, address string, opts ...InboundOpt) (*Inbound, error) {
+	if network == nil {
+		return nil, errors.New("loopback network is mandatory")
+	}
+
+	if address == "" {
+		return nil, errors.New("loopback address is mandatory")
+	}
+
+	i := &Inbound{network: network, endpoint: Scheme + address, queueSize: defaultQueueSize}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i, nil
+}
+
+// Start starts receiving the messages sent to the endpoint of the inbound transport.
+func (i *Inbound) Start(prov transport.Provider) error {
+	if prov == nil || prov.InboundMessageHandler() == nil {
+		return errors.New("loopback inbound start failed: message handler function is nil")
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.started {
+		return errors.New("loopback inbound start failed: already started")
+	}
+
+	i.messages = make(chan []byte, i.queueSize)
+	i.done = make(chan struct{})
+
+	if err := i.network.register(i); err != nil {
+		return fmt.Errorf("loopback inbound start failed: %w", err)
+	}
+
+	i.started = true
+
+	i.wg.Add(1)
+
+	go i.listen(prov)
+
+	return nil
+}
+
+// Stop stops receiving the messages, the messages which are queued but not handled yet are dropped.
+func (i *Inbound) Stop() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if !i.started {
+		return errors.New("loopback inbound stop failed: not started")
+	}
+
+	i.network.unregister(i)
+	close(i.done)
+	i.wg.Wait()
+
+	i.started = false
+
+	return nil
+}
+
+// Endpoint returns the endpoint of the inbound transport, i.e. Scheme followed by its address.
+func (i *Inbound) Endpoint() string {
+	return i.endpoint
+}
+
+func (i *Inbound) deliver(ctx context.Context, data []byte) error {
+	select {
+	case i.messages <- data:
+		return nil
+	case <-i.done:
+		return fmt.Errorf("inbound transport at %s is stopped", i.endpoint)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (i *Inbound) listen(prov transport.Provider) {
+	defer i.wg.Done()
+
+	for {
+		select {
+		case data := <-i.messages:
+			i.handle(prov, data)
+		case <-i.done:
+			return
+		}
+	}
+}
+
+func (i *Inbound) handle(prov transport.Provider, data []byte) {
+	unpackMsg, err := prov.Packager().UnpackMessage(data)
+	if err != nil {
+		logger.Errorf("failed to unpack msg at %s: %s", i.endpoint, err)
+
+		return
+	}
+
+	err = prov.InboundMessageHandler()(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+	if err != nil {
+		logger.Errorf("incoming msg processing failed at %s: %s", i.endpoint, err)
+	}
+}
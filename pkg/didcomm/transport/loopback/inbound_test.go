@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+func TestNewInbound(t *testing.T) {
+	t.Run("test inbound transport - with address", func(t *testing.T) {
+		inbound, err := NewInbound(NewNetwork(), "did:example:alice")
+		require.NoError(t, err)
+		require.Equal(t, "loopback://did:example:alice", inbound.Endpoint())
+	})
+
+	t.Run("test inbound transport - without network", func(t *testing.T) {
+		_, err := NewInbound(nil, "did:example:alice")
+		require.EqualError(t, err, "loopback network is mandatory")
+	})
+
+	t.Run("test inbound transport - without address", func(t *testing.T) {
+		_, err := NewInbound(NewNetwork(), "")
+		require.EqualError(t, err, "loopback address is mandatory")
+	})
+}
+
+func TestInbound_Start(t *testing.T) {
+	t.Run("test inbound transport - start and stop", func(t *testing.T) {
+		network := NewNetwork()
+
+		inbound, err := NewInbound(network, "did:example:alice")
+		require.NoError(t, err)
+
+		prov := &mockProvider{packagerValue: &echoPackager{}, received: make(chan []byte)}
+
+		require.NoError(t, inbound.Start(prov))
+
+		err = inbound.Start(prov)
+		require.EqualError(t, err, "loopback inbound start failed: already started")
+
+		other, err := NewInbound(network, "did:example:alice")
+		require.NoError(t, err)
+
+		err = other.Start(prov)
+		require.EqualError(t, err,
+			"loopback inbound start failed: endpoint loopback://did:example:alice is already in use")
+
+		require.NoError(t, inbound.Stop())
+
+		err = inbound.Stop()
+		require.EqualError(t, err, "loopback inbound stop failed: not started")
+
+		// the endpoint is released
+		require.NoError(t, other.Start(prov))
+		require.NoError(t, other.Stop())
+	})
+
+	t.Run("test inbound transport - nil context", func(t *testing.T) {
+		inbound, err := NewInbound(NewNetwork(), "did:example:alice")
+		require.NoError(t, err)
+
+		err = inbound.Start(nil)
+		require.EqualError(t, err, "loopback inbound start failed: message handler function is nil")
+	})
+}
+
+func TestInbound_Handle(t *testing.T) {
+	network := NewNetwork()
+
+	inbound, err := NewInbound(network, "did:example:alice")
+	require.NoError(t, err)
+
+	prov := &mockProvider{packagerValue: &echoPackager{}, received: make(chan []byte, 3)}
+
+	require.NoError(t, inbound.Start(prov))
+
+	defer func() { require.NoError(t, inbound.Stop()) }()
+
+	outbound := NewOutbound(network)
+	destination := &service.Destination{ServiceEndpoint: inbound.Endpoint()}
+
+	// the messages which failed to be unpacked or handled don't stop the transport
+	for _, data := range []string{"bad", "invalid-data", "data"} {
+		_, err = outbound.Send([]byte(data), destination)
+		require.NoError(t, err)
+	}
+
+	for _, expected := range []string{"invalid-data", "data"} {
+		select {
+		case received := <-prov.received:
+			require.Equal(t, expected, string(received))
+		case <-time.After(time.Second):
+			require.Fail(t, "message was not received")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/loopback"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestLoopback_DIDExchange(t *testing.T) {
+	network := loopback.NewNetwork()
+
+	alice, aliceCompleted, closeAlice := newAgent(t, network, "alice")
+	defer closeAlice()
+
+	bob, bobCompleted, closeBob := newAgent(t, network, "bob")
+	defer closeBob()
+
+	invitation, err := bob.CreateInvitation("bob")
+	require.NoError(t, err)
+
+	connectionID, err := alice.HandleInvitation(invitation)
+	require.NoError(t, err)
+
+	for _, completed := range []chan string{aliceCompleted, bobCompleted} {
+		select {
+		case <-completed:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "did exchange was not completed")
+		}
+	}
+
+	connection, err := alice.GetConnection(connectionID)
+	require.NoError(t, err)
+	require.Equal(t, "completed", connection.State)
+	require.Equal(t, "bob", connection.TheirLabel)
+}
+
+func newAgent(t *testing.T, network *loopback.Network, name string) (*didexchange.Client, chan string, func()) {
+	inbound, err := loopback.NewInbound(network, name)
+	require.NoError(t, err)
+
+	framework, err := aries.New(
+		aries.WithStoreProvider(mem.NewProvider()),
+		aries.WithTransientStoreProvider(mem.NewProvider()),
+		aries.WithInboundTransport(inbound),
+		aries.WithOutboundTransports(loopback.NewOutbound(network)),
+	)
+	require.NoError(t, err)
+
+	ctx, err := framework.Context()
+	require.NoError(t, err)
+
+	client, err := didexchange.New(ctx)
+	require.NoError(t, err)
+
+	actions := make(chan service.DIDCommAction)
+	require.NoError(t, client.RegisterActionEvent(actions))
+
+	go service.AutoExecuteActionEvent(actions)
+
+	states := make(chan service.StateMsg)
+	require.NoError(t, client.RegisterMsgEvent(states))
+
+	completed := make(chan string, 1)
+
+	go func() {
+		for msg := range states {
+			if msg.Type == service.PostState && msg.StateID == "completed" {
+				completed <- msg.StateID
+			}
+		}
+	}()
+
+	return client, completed, func() { require.NoError(t, framework.Close()) }
+}
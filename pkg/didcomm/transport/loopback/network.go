@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+)
+
+// Scheme is the scheme of the endpoints of the loopback transport, e.g. "loopback://did:example:alice"
+const Scheme = "loopback://"
+
+var logger = log.New("aries-framework/transport/loopback")
+
+// Network is the in-memory network connecting the loopback transports of the agents running in the same process.
+// The inbound transports are addressed by the endpoints (i.e. Scheme followed by the address of the agent,
+// e.g. its DID). The network is intended for the tests which exchange DIDComm messages without sockets.
+type Network struct {
+	inbounds map[string]*Inbound
+	lock     sync.RWMutex
+}
+
+// NewNetwork creates a new in-memory network.
+func NewNetwork() *Network {
+	return &Network{inbounds: make(map[string]*Inbound)}
+}
+
+func (n *Network) register(inbound *Inbound) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if _, ok := n.inbounds[inbound.Endpoint()]; ok {
+		return fmt.Errorf("endpoint %s is already in use", inbound.Endpoint())
+	}
+
+	n.inbounds[inbound.Endpoint()] = inbound
+
+	return nil
+}
+
+func (n *Network) unregister(inbound *Inbound) {
+	n.lock.Lock()
+	delete(n.inbounds, inbound.Endpoint())
+	n.lock.Unlock()
+}
+
+func (n *Network) inbound(endpoint string) (*Inbound, error) {
+	if !strings.HasPrefix(endpoint, Scheme) {
+		return nil, fmt.Errorf("not a loopback endpoint: %s", endpoint)
+	}
+
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	inbound, ok := n.inbounds[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("no inbound transport at %s", endpoint)
+	}
+
+	return inbound, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+// Outbound is the loopback outbound transport, it sends the messages to the inbound transports of the Network.
+type Outbound struct {
+	network *Network
+}
+
+// NewOutbound creates a new loopback outbound transport sending the messages through the network.
+func NewOutbound(network *Network) *Outbound {
+	return &Outbound{network: network}
+}
+
+// Start starts the outbound transport.
+func (o *Outbound) Start(prov transport.Provider) error {
+	return nil
+}
+
+// Send sends a2a exchange data to the loopback inbound transport of the destination.
+func (o *Outbound) Send(data []byte, destination *service.Destination) (string, error) {
+	return o.SendWithContext(context.Background(), data, destination)
+}
+
+// SendWithContext sends a2a exchange data to the loopback inbound transport of the destination.
+// The data is queued by the inbound transport, the sending is aborted once ctx is done if the queue is full.
+func (o *Outbound) SendWithContext(ctx context.Context, data []byte, destination *service.Destination) (string, error) {
+	inbound, err := o.network.inbound(destination.ServiceEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("loopback send: %w", err)
+	}
+
+	// the data is owned by the receiver
+	if err = inbound.deliver(ctx, append([]byte(nil), data...)); err != nil {
+		return "", fmt.Errorf("loopback send: %w", err)
+	}
+
+	return "", nil
+}
+
+// AcceptRecipient checks if there is a connection for the list of recipient keys.
+func (o *Outbound) AcceptRecipient([]string) bool {
+	return false
+}
+
+// Accept checks if the url is a loopback endpoint.
+func (o *Outbound) Accept(url string) bool {
+	return strings.HasPrefix(url, Scheme)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+func TestOutbound_Accept(t *testing.T) {
+	outbound := NewOutbound(NewNetwork())
+	require.NoError(t, outbound.Start(nil))
+
+	require.True(t, outbound.Accept("loopback://did:example:alice"))
+	require.False(t, outbound.Accept("http://example.com"))
+	require.False(t, outbound.AcceptRecipient([]string{"key"}))
+}
+
+func TestOutbound_Send(t *testing.T) {
+	network := NewNetwork()
+	outbound := NewOutbound(network)
+
+	t.Run("test send - unknown endpoint", func(t *testing.T) {
+		_, err := outbound.Send([]byte("data"), &service.Destination{ServiceEndpoint: "loopback://did:example:bob"})
+		require.EqualError(t, err, "loopback send: no inbound transport at loopback://did:example:bob")
+
+		_, err = outbound.Send([]byte("data"), &service.Destination{ServiceEndpoint: "http://example.com"})
+		require.EqualError(t, err, "loopback send: not a loopback endpoint: http://example.com")
+	})
+
+	t.Run("test send - data is copied", func(t *testing.T) {
+		inbound, err := NewInbound(network, "did:example:alice")
+		require.NoError(t, err)
+
+		prov := &mockProvider{packagerValue: &echoPackager{}, received: make(chan []byte, 1)}
+		require.NoError(t, inbound.Start(prov))
+
+		defer func() { require.NoError(t, inbound.Stop()) }()
+
+		data := []byte("data")
+
+		_, err = outbound.Send(data, &service.Destination{ServiceEndpoint: inbound.Endpoint()})
+		require.NoError(t, err)
+
+		data[0] = 'x'
+
+		select {
+		case received := <-prov.received:
+			require.Equal(t, "data", string(received))
+		case <-time.After(time.Second):
+			require.Fail(t, "message was not received")
+		}
+	})
+
+	t.Run("test send - queue is full", func(t *testing.T) {
+		inbound, err := NewInbound(network, "did:example:alice", WithQueueSize(0))
+		require.NoError(t, err)
+
+		// the handler is blocked on the first message
+		prov := &mockProvider{packagerValue: &echoPackager{}, received: make(chan []byte)}
+		require.NoError(t, inbound.Start(prov))
+
+		destination := &service.Destination{ServiceEndpoint: inbound.Endpoint()}
+
+		_, err = outbound.Send([]byte("data"), destination)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = outbound.SendWithContext(ctx, []byte("data"), destination)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		<-prov.received
+		require.NoError(t, inbound.Stop())
+	})
+
+	t.Run("test send - inbound is stopped", func(t *testing.T) {
+		inbound, err := NewInbound(network, "did:example:alice", WithQueueSize(0))
+		require.NoError(t, err)
+
+		prov := &mockProvider{packagerValue: &echoPackager{}, received: make(chan []byte)}
+		require.NoError(t, inbound.Start(prov))
+		require.NoError(t, inbound.Stop())
+
+		err = inbound.deliver(context.Background(), []byte("data"))
+		require.EqualError(t, err, "inbound transport at loopback://did:example:alice is stopped")
+	})
+}
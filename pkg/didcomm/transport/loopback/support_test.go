@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package loopback
+
+import (
+	"errors"
+
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+type mockProvider struct {
+	packagerValue commontransport.Packager
+	received      chan []byte
+}
+
+func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
+	return func(message []byte, myDID, theirDID string) error {
+		p.received <- message
+
+		if string(message) == "invalid-data" {
+			return errors.New("error")
+		}
+
+		return nil
+	}
+}
+
+func (p *mockProvider) Packager() commontransport.Packager {
+	return p.packagerValue
+}
+
+func (p *mockProvider) AriesFrameworkID() string {
+	return "loopback-test"
+}
+
+// echoPackager unpacks the message as is
+type echoPackager struct{}
+
+func (p *echoPackager) PackMessage(e *commontransport.Envelope) ([]byte, error) {
+	return e.Message, nil
+}
+
+func (p *echoPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelope, error) {
+	if string(encMessage) == "bad" {
+		return nil, errors.New("unpack error")
+	}
+
+	return &commontransport.Envelope{Message: encMessage}, nil
+}
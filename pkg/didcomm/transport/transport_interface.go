@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package transport
 
 import (
+	"context"
+
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 )
@@ -20,6 +22,9 @@ type OutboundTransport interface {
 	// Send send a2a exchange data
 	Send(data []byte, destination *service.Destination) (string, error)
 
+	// SendWithContext send a2a exchange data, the sending is aborted once ctx is done
+	SendWithContext(ctx context.Context, data []byte, destination *service.Destination) (string, error)
+
 	// AcceptRecipient checks if there is a connection for the list of recipient keys. The framework executes this
 	// function before Accept() in outbound message dispatcher.
 	AcceptRecipient([]string) bool
@@ -41,14 +41,20 @@ func (cs *OutboundClient) Start(prov transport.Provider) error {
 
 // Send sends a2a data via WS.
 func (cs *OutboundClient) Send(data []byte, destination *service.Destination) (string, error) {
-	conn, cleanup, err := cs.getConnection(destination)
+	return cs.SendWithContext(context.Background(), data, destination)
+}
+
+// SendWithContext sends a2a data via WS, the sending is aborted once ctx is done.
+func (cs *OutboundClient) SendWithContext(ctx context.Context, data []byte,
+	destination *service.Destination) (string, error) {
+	conn, cleanup, err := cs.getConnection(ctx, destination)
 	defer cleanup()
 
 	if err != nil {
 		return "", fmt.Errorf("get websocket connection : %w", err)
 	}
 
-	err = conn.Write(context.Background(), websocket.MessageText, data)
+	err = conn.Write(ctx, websocket.MessageText, data)
 	if err != nil {
 		return "", fmt.Errorf("websocket write message : %w", err)
 	}
@@ -66,7 +72,8 @@ func (cs *OutboundClient) AcceptRecipient(keys []string) bool {
 	return acceptRecipient(cs.pool, keys)
 }
 
-func (cs *OutboundClient) getConnection(destination *service.Destination) (*websocket.Conn, func(), error) {
+func (cs *OutboundClient) getConnection(ctx context.Context,
+	destination *service.Destination) (*websocket.Conn, func(), error) {
 	var conn *websocket.Conn
 
 	// get the connection for the routing or recipient keys
@@ -88,7 +95,7 @@ func (cs *OutboundClient) getConnection(destination *service.Destination) (*webs
 	if conn == nil {
 		var err error
 
-		conn, _, err = websocket.Dial(context.Background(), destination.ServiceEndpoint, nil)
+		conn, _, err = websocket.Dial(ctx, destination.ServiceEndpoint, nil)
 		if err != nil {
 			return nil, cleanup, fmt.Errorf("websocket client : %w", err)
 		}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package ws
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -47,6 +49,19 @@ func TestClient(t *testing.T) {
 		require.Equal(t, "", resp)
 	})
 
+	t.Run("test outbound transport - canceled context", func(t *testing.T) {
+		outbound := NewOutbound()
+		require.NotNil(t, outbound)
+		addr := startWebSocketServer(t, echo)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := outbound.SendWithContext(ctx, []byte("hello"), prepareDestination("ws://"+addr))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
 	t.Run("test outbound transport - not a websocket server", func(t *testing.T) {
 		outbound := NewOutbound()
 		require.NotNil(t, outbound)
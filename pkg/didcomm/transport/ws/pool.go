@@ -72,7 +72,9 @@ func (d *connPool) remove(verKey string) {
 func (d *connPool) listener(conn *websocket.Conn, outbound bool) {
 	verKeys := []string{}
 
-	defer d.close(conn, verKeys)
+	defer func() {
+		d.close(conn, verKeys)
+	}()
 
 	go keepConnAlive(conn, outbound, pingFrequency)
 
@@ -101,8 +103,16 @@ func (d *connPool) listener(conn *websocket.Conn, outbound bool) {
 			logger.Errorf("unmarshal transport decorator : %v", err)
 		}
 
+		// This is how the outbound dispatcher learns of the inbound transport: the sender asked for the responses
+		// to come back over this connection, so it is kept under the sender key. The outbound client accepts
+		// the recipient (AcceptRecipient) and writes the reply to the connection instead of dialing a new one.
+		// The connection is removed from the pool once it is closed.
 		if trans != nil && trans.ReturnRoute != nil && trans.ReturnRoute.Value == decorator.TransportReturnRouteAll {
-			d.add(base58.Encode(unpackMsg.FromVerKey), conn)
+			verKey := base58.Encode(unpackMsg.FromVerKey)
+
+			d.add(verKey, conn)
+
+			verKeys = append(verKeys, verKey)
 		}
 
 		messageHandler := d.msgHandler
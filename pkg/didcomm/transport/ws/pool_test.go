@@ -81,6 +81,51 @@ func TestConnectionStore(t *testing.T) {
 		require.Equal(t, response, string(message))
 	})
 
+	t.Run("test transport pool - inbound connection is removed once closed", func(t *testing.T) {
+		request := createTransportDecRequest(t, decorator.TransportReturnRouteAll)
+
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+		inbound, err := NewInbound(port, "")
+		require.NoError(t, err)
+
+		outbound := NewOutbound()
+
+		verKey := "EFGH"
+		done := make(chan struct{})
+
+		transportProvider := &mockTransportProvider{
+			packagerValue: &mockpackager.Packager{
+				UnpackValue: &commontransport.Envelope{Message: request, FromVerKey: base58.Decode(verKey)},
+			},
+			frameworkID: uuid.New().String(),
+			executeInbound: func(message []byte, myDID, theirDID string) error {
+				done <- struct{}{}
+				return nil
+			},
+		}
+
+		require.NoError(t, inbound.Start(transportProvider))
+		require.NoError(t, outbound.Start(transportProvider))
+
+		client, cleanup := websocketClient(t, port)
+
+		require.NoError(t, client.Write(context.Background(), websocket.MessageText, request))
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "tests are not validated due to timeout")
+		}
+
+		require.NotNil(t, outbound.pool.fetch(verKey))
+
+		cleanup()
+
+		require.Eventually(t, func() bool {
+			return outbound.pool.fetch(verKey) == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
 	t.Run("test transport pool - agent without inbound (client)", func(t *testing.T) {
 		// request to be sent to the framework (with route option)
 		request := createTransportDecRequest(t, decorator.TransportReturnRouteAll)
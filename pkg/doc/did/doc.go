@@ -82,6 +82,19 @@ const (
         ]
       }
     },
+    "assertionMethod": {
+      "type": "array",
+      "items": {
+        "oneOf": [
+          {
+            "$ref": "#/definitions/publicKey"
+          },
+          {
+            "type": "string"
+          }
+        ]
+      }
+    },
     "service": {
       "type": "array",
       "items": {
@@ -177,14 +190,15 @@ var schemaLoader = gojsonschema.NewStringLoader(schema) //nolint:gochecknoglobal
 
 // Doc DID Document definition
 type Doc struct {
-	Context        []string
-	ID             string
-	PublicKey      []PublicKey
-	Service        []Service
-	Authentication []VerificationMethod
-	Created        *time.Time
-	Updated        *time.Time
-	Proof          []Proof
+	Context         []string
+	ID              string
+	PublicKey       []PublicKey
+	Service         []Service
+	Authentication  []VerificationMethod
+	AssertionMethod []VerificationMethod `json:",omitempty"` // keeps JSON of the docs without it (e.g. peer DIDs)
+	Created         *time.Time
+	Updated         *time.Time
+	Proof           []Proof
 }
 
 // PublicKey DID doc public key
@@ -212,14 +226,15 @@ type VerificationMethod struct {
 }
 
 type rawDoc struct {
-	Context        []string                 `json:"@context,omitempty"`
-	ID             string                   `json:"id,omitempty"`
-	PublicKey      []map[string]interface{} `json:"publicKey,omitempty"`
-	Service        []map[string]interface{} `json:"service,omitempty"`
-	Authentication []interface{}            `json:"authentication,omitempty"`
-	Created        *time.Time               `json:"created,omitempty"`
-	Updated        *time.Time               `json:"updated,omitempty"`
-	Proof          []interface{}            `json:"proof,omitempty"`
+	Context         []string                 `json:"@context,omitempty"`
+	ID              string                   `json:"id,omitempty"`
+	PublicKey       []map[string]interface{} `json:"publicKey,omitempty"`
+	Service         []map[string]interface{} `json:"service,omitempty"`
+	Authentication  []interface{}            `json:"authentication,omitempty"`
+	AssertionMethod []interface{}            `json:"assertionMethod,omitempty"`
+	Created         *time.Time               `json:"created,omitempty"`
+	Updated         *time.Time               `json:"updated,omitempty"`
+	Proof           []interface{}            `json:"proof,omitempty"`
 }
 
 // Proof is cryptographic proof of the integrity of the DID Document
@@ -251,24 +266,30 @@ func ParseDocument(data []byte) (*Doc, error) {
 		return nil, fmt.Errorf("populate public keys failed: %w", err)
 	}
 
-	authPKs, err := populateAuthentications(raw.Authentication, publicKeys)
+	authPKs, err := populateVerificationMethods("authentication", raw.Authentication, publicKeys)
 	if err != nil {
 		return nil, fmt.Errorf("populate authentications failed: %w", err)
 	}
 
+	assertionPKs, err := populateVerificationMethods("assertion method", raw.AssertionMethod, publicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("populate assertion methods failed: %w", err)
+	}
+
 	proofs, err := populateProofs(raw.Proof)
 	if err != nil {
 		return nil, fmt.Errorf("populate proofs failed: %w", err)
 	}
 
 	return &Doc{Context: raw.Context,
-		ID:             raw.ID,
-		PublicKey:      publicKeys,
-		Service:        populateServices(raw.Service),
-		Authentication: authPKs,
-		Created:        raw.Created,
-		Updated:        raw.Updated,
-		Proof:          proofs,
+		ID:              raw.ID,
+		PublicKey:       publicKeys,
+		Service:         populateServices(raw.Service),
+		Authentication:  authPKs,
+		AssertionMethod: assertionPKs,
+		Created:         raw.Created,
+		Updated:         raw.Updated,
+		Proof:           proofs,
 	}, nil
 }
 
@@ -338,7 +359,10 @@ func populateServices(rawServices []map[string]interface{}) []Service {
 	return services
 }
 
-func populateAuthentications(rawAuthentications []interface{}, pks []PublicKey) ([]VerificationMethod, error) {
+// populateVerificationMethods populates the verification methods of the verification relationship
+// (e.g. authentication), the method is either embedded public key or a reference to the public key of DID doc.
+func populateVerificationMethods(relationship string, rawAuthentications []interface{},
+	pks []PublicKey) ([]VerificationMethod, error) {
 	var vms []VerificationMethod
 
 	for _, rawAuthentication := range rawAuthentications {
@@ -356,7 +380,7 @@ func populateAuthentications(rawAuthentications []interface{}, pks []PublicKey)
 			}
 
 			if !keyExist {
-				return nil, fmt.Errorf("authentication key %s not exist in did doc public key", valueString)
+				return nil, fmt.Errorf("%s key %s not exist in did doc public key", relationship, valueString)
 			}
 
 			continue
@@ -485,14 +509,15 @@ func stringArray(entry interface{}) []string {
 // JSONBytes converts document to json bytes
 func (doc *Doc) JSONBytes() ([]byte, error) {
 	raw := &rawDoc{
-		Context:        doc.Context,
-		ID:             doc.ID,
-		PublicKey:      populateRawPublicKeys(doc.PublicKey),
-		Authentication: populateRawAuthentications(doc.Authentication),
-		Service:        populateRawServices(doc.Service),
-		Created:        doc.Created,
-		Proof:          populateRawProofs(doc.Proof),
-		Updated:        doc.Updated,
+		Context:         doc.Context,
+		ID:              doc.ID,
+		PublicKey:       populateRawPublicKeys(doc.PublicKey),
+		Authentication:  populateRawVerificationMethods(doc.Authentication),
+		AssertionMethod: populateRawVerificationMethods(doc.AssertionMethod),
+		Service:         populateRawServices(doc.Service),
+		Created:         doc.Created,
+		Proof:           populateRawProofs(doc.Proof),
+		Updated:         doc.Updated,
 	}
 
 	byteDoc, err := json.Marshal(raw)
@@ -601,7 +626,7 @@ func populateRawPublicKey(pk PublicKey) map[string]interface{} {
 	return rawPK
 }
 
-func populateRawAuthentications(vms []VerificationMethod) []interface{} {
+func populateRawVerificationMethods(vms []VerificationMethod) []interface{} {
 	var rawAuthentications []interface{}
 
 	for _, vm := range vms {
@@ -644,6 +669,13 @@ func WithAuthentication(auth []VerificationMethod) DocOption {
 	}
 }
 
+// WithAssertionMethod DID doc AssertionMethod.
+func WithAssertionMethod(assertionMethod []VerificationMethod) DocOption {
+	return func(opts *Doc) {
+		opts.AssertionMethod = assertionMethod
+	}
+}
+
 // WithService DID doc services.
 func WithService(svc []Service) DocOption {
 	return func(opts *Doc) {
@@ -13,7 +13,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
-	"errors"
 	"testing"
 	"time"
 
@@ -22,6 +21,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 const pemPK = `-----BEGIN PUBLIC KEY-----
@@ -225,6 +225,42 @@ func TestPopulateAuthentications(t *testing.T) {
 	})
 }
 
+func TestAssertionMethod(t *testing.T) {
+	raw := &rawDoc{}
+	require.NoError(t, json.Unmarshal([]byte(validDoc), &raw))
+
+	raw.AssertionMethod = []interface{}{raw.PublicKey[0]["id"]}
+
+	docBytes, err := json.Marshal(raw)
+	require.NoError(t, err)
+
+	t.Run("test parse and convert to JSON", func(t *testing.T) {
+		doc, err := ParseDocument(docBytes)
+		require.NoError(t, err)
+		require.Len(t, doc.AssertionMethod, 1)
+		require.Equal(t, doc.PublicKey[0], doc.AssertionMethod[0].PublicKey)
+
+		byteDoc, err := doc.JSONBytes()
+		require.NoError(t, err)
+
+		doc2, err := ParseDocument(byteDoc)
+		require.NoError(t, err)
+		require.Equal(t, doc, doc2)
+	})
+
+	t.Run("test key not exist", func(t *testing.T) {
+		raw.AssertionMethod = []interface{}{"did:example:123456789abcdefghs#key4"}
+
+		invalidBytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		_, err = ParseDocument(invalidBytes)
+		require.Error(t, err)
+		require.Contains(t, err.Error(),
+			"assertion method key did:example:123456789abcdefghs#key4 not exist in did doc public key")
+	})
+}
+
 func TestPublicKeys(t *testing.T) {
 	t.Run("test failed to decode PEM block", func(t *testing.T) {
 		raw := &rawDoc{}
@@ -713,7 +749,7 @@ func createSignedDidDocument(privKey, pubKey []byte) []byte {
 		SignatureType: signatureType}
 
 	s := signer.New(ed25519signature2018.New(
-		ed25519signature2018.WithSigner(getSigner(privKey))))
+		ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))))
 
 	signedDoc, err := s.Sign(context, jsonDoc)
 	if err != nil {
@@ -723,22 +759,6 @@ func createSignedDidDocument(privKey, pubKey []byte) []byte {
 	return signedDoc
 }
 
-func getSigner(privKey []byte) *testSigner {
-	return &testSigner{privateKey: privKey}
-}
-
-type testSigner struct {
-	privateKey []byte
-}
-
-func (s *testSigner) Sign(doc []byte) ([]byte, error) {
-	if l := len(s.privateKey); l != ed25519.PrivateKeySize {
-		return nil, errors.New("ed25519: bad private key length")
-	}
-
-	return ed25519.Sign(s.privateKey, doc), nil
-}
-
 const validDocWithProof = `{
 	"@context": ["https://w3id.org/did/v1"],
 	"created": "2019-09-23T14:16:59.261024-04:00",
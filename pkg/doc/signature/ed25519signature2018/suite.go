@@ -17,11 +17,13 @@ import (
 	"errors"
 
 	"github.com/piprate/json-gold/ld"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature"
 )
 
 // SignatureSuite implements ed25519 signature suite
 type SignatureSuite struct {
-	signer signer
+	signer signature.Signer
 }
 
 const (
@@ -29,16 +31,11 @@ const (
 	format        = "application/n-quads"
 )
 
-type signer interface {
-	// Sign will sign document and return signature
-	Sign(data []byte) ([]byte, error)
-}
-
 // SuiteOpt is the SignatureSuite option.
 type SuiteOpt func(opts *SignatureSuite)
 
 // WithSigner defines a signer for the Signature Suite.
-func WithSigner(s signer) SuiteOpt {
+func WithSigner(s signature.Signer) SuiteOpt {
 	return func(opts *SignatureSuite) {
 		opts.signer = s
 	}
@@ -6,12 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 package ed25519signature2018
 
 import (
+	"crypto"
 	"crypto/ed25519"
 	"crypto/rand"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature"
 )
 
 func TestSignatureSuite_Sign(t *testing.T) {
@@ -32,6 +35,14 @@ func TestSignatureSuite_Sign(t *testing.T) {
 	require.EqualError(t, err, "signature error")
 	require.Empty(t, bytes)
 
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ss = New(WithSigner(signature.NewCryptoSigner(privKey, "did:example:123#key-1", nil)))
+	bytes, err = ss.Sign(doc)
+	require.NoError(t, err)
+	require.NoError(t, ss.Verify(pubKey, doc, bytes))
+
 	ss = New()
 	bytes, err = ss.Sign(doc)
 	require.Error(t, err)
@@ -47,16 +58,16 @@ func TestSignatureSuite_Verify(t *testing.T) {
 
 	doc := []byte("hello world")
 
-	signature := ed25519.Sign(privKey, doc)
-	require.NotEmpty(t, signature)
+	sig := ed25519.Sign(privKey, doc)
+	require.NotEmpty(t, sig)
 
 	ss := New()
 
-	err = ss.Verify(pubKey, doc, signature)
+	err = ss.Verify(pubKey, doc, sig)
 	require.Nil(t, err)
 
 	// test different message
-	err = ss.Verify(pubKey, []byte("different doc"), signature)
+	err = ss.Verify(pubKey, []byte("different doc"), sig)
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "signature doesn't match")
 
@@ -66,7 +77,7 @@ func TestSignatureSuite_Verify(t *testing.T) {
 	require.Contains(t, err.Error(), "signature doesn't match")
 
 	// test wrong public key size
-	err = ss.Verify([]byte("key"), doc, signature)
+	err = ss.Verify([]byte("key"), doc, sig)
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "ed25519: bad public key length")
 }
@@ -168,6 +179,14 @@ func (s *mockSigner) Sign(_ []byte) ([]byte, error) {
 	return s.signature, nil
 }
 
+func (s *mockSigner) PublicKey() crypto.PublicKey {
+	return nil
+}
+
+func (s *mockSigner) KeyID() string {
+	return ""
+}
+
 // taken from test 28 report https://json-ld.org/test-suite/reports/#test_30bc80ba056257df8a196e8f65c097fc
 
 // nolint
@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ed25519signature2020 implements the Ed25519Signature2020 signature suite
+// for the Linked Data Signatures [LD-SIGNATURES] specification.
+// The suite differs from Ed25519Signature2018 by the representation of the signature only:
+// it is kept in "proofValue" as multibase base58-btc string (see proof.SignatureProofValue).
+// It uses the RDF Dataset Normalization Algorithm [RDF-DATASET-NORMALIZATION]
+// to transform the input document into its canonical form.
+// It uses SHA-256 [RFC6234] as the message digest algorithm and
+// Ed25519 [ED25519] as the signature algorithm.
+package ed25519signature2020
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+)
+
+const signatureType = "Ed25519Signature2020"
+
+// SignatureSuite implements ed25519 signature suite of 2020
+type SignatureSuite struct {
+	*ed25519signature2018.SignatureSuite
+}
+
+// SuiteOpt is the SignatureSuite option.
+type SuiteOpt func(opts *suiteOpts)
+
+type suiteOpts struct {
+	signer signature.Signer
+}
+
+// WithSigner defines a signer for the Signature Suite.
+func WithSigner(s signature.Signer) SuiteOpt {
+	return func(opts *suiteOpts) {
+		opts.signer = s
+	}
+}
+
+// New an instance of ed25519 signature suite of 2020
+func New(opts ...SuiteOpt) *SignatureSuite {
+	suiteOpts := &suiteOpts{}
+
+	for _, opt := range opts {
+		opt(suiteOpts)
+	}
+
+	var suite *ed25519signature2018.SignatureSuite
+
+	if suiteOpts.signer != nil {
+		suite = ed25519signature2018.New(ed25519signature2018.WithSigner(suiteOpts.signer))
+	} else {
+		suite = ed25519signature2018.New()
+	}
+
+	return &SignatureSuite{SignatureSuite: suite}
+}
+
+// Accept will accept only ed25519 signature type of 2020
+func (s *SignatureSuite) Accept(t string) bool {
+	return t == signatureType
+}
+
+// ErrSignerNotDefined is returned when Sign() is called but signer option is not defined.
+var ErrSignerNotDefined = ed25519signature2018.ErrSignerNotDefined
@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ed25519signature2020
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature"
+)
+
+func TestSignatureSuite_Sign(t *testing.T) {
+	doc := []byte("test doc")
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ss := New(WithSigner(signature.NewCryptoSigner(privKey, "did:example:123#key-1", nil)))
+	bytes, err := ss.Sign(doc)
+	require.NoError(t, err)
+	require.NoError(t, ss.Verify(pubKey, doc, bytes))
+
+	err = ss.Verify(pubKey, []byte("different doc"), bytes)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature doesn't match")
+
+	ss = New()
+	bytes, err = ss.Sign(doc)
+	require.Equal(t, ErrSignerNotDefined, err)
+	require.Empty(t, bytes)
+}
+
+func TestSignatureSuite_GetDigest(t *testing.T) {
+	digest := New().GetDigest([]byte("test doc"))
+	require.Len(t, digest, 32)
+}
+
+func TestSignatureSuite_Accept(t *testing.T) {
+	ss := New()
+	require.True(t, ss.Accept("Ed25519Signature2020"))
+	require.False(t, ss.Accept("Ed25519Signature2018"))
+}
@@ -37,12 +37,29 @@ const (
 // It depends on the signature value holder type.
 // In case of "proofValue", the standard Create Verify Hash algorithm is used.
 // In case of "jws", verify data is built as JSON Web Signature (JWS) with detached payload.
+// The document is signed without its proofs unless the proof is chained (i.e. it has previous proof).
+// The chained proof signs the document including its proofs, so jsonldDoc must contain the proofs
+// preceding the chained one only (see GetCopyWithPrecedingProofs).
 func CreateVerifyData(suite signatureSuite, jsonldDoc map[string]interface{}, proof *Proof) ([]byte, error) {
+	doc := GetCopyWithoutProof(jsonldDoc)
+
+	if proof.PreviousProof != "" {
+		proofs, err := getProofEntries(jsonldDoc)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err = GetCopyWithPrecedingProofs(jsonldDoc, len(proofs))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	switch proof.SignatureRepresentation {
 	case SignatureProofValue:
-		return CreateVerifyHash(suite, jsonldDoc, proof.JSONLdObject())
+		return createVerifyHash(suite, doc, proof.JSONLdObject())
 	case SignatureJWS:
-		return createVerifyJWS(suite, jsonldDoc, proof)
+		return createVerifyJWS(suite, doc, proof)
 	}
 
 	return nil, fmt.Errorf("unsupported signature representation: %v", proof.SignatureRepresentation)
@@ -51,6 +68,12 @@ func CreateVerifyData(suite signatureSuite, jsonldDoc map[string]interface{}, pr
 // CreateVerifyHash returns data that is used to generate or verify a digital signature
 // Algorithm steps are described here https://w3c-dvcg.github.io/ld-signatures/#create-verify-hash-algorithm
 func CreateVerifyHash(suite signatureSuite, jsonldDoc, proofOptions map[string]interface{}) ([]byte, error) {
+	return createVerifyHash(suite, GetCopyWithoutProof(jsonldDoc), proofOptions)
+}
+
+// createVerifyHash is CreateVerifyHash for the document prepared for signing (i.e. without proofs
+// or with the preceding proofs in case of proof chain)
+func createVerifyHash(suite signatureSuite, jsonldDoc, proofOptions map[string]interface{}) ([]byte, error) {
 	// in  order to generate canonical form we need context
 	// if context is not passed, use document's context
 	// spec doesn't mention anything about context
@@ -96,11 +119,8 @@ func prepareCanonicalProofOptions(suite signatureSuite, proofOptions map[string]
 }
 
 func prepareCanonicalDocument(suite signatureSuite, jsonldObject map[string]interface{}) ([]byte, error) {
-	// copy document object without proof
-	docCopy := GetCopyWithoutProof(jsonldObject)
-
 	// build canonical document
-	return suite.GetCanonicalDocument(docCopy)
+	return suite.GetCanonicalDocument(jsonldObject)
 }
 
 // excludedKey defines keys that are excluded for proof options
@@ -106,10 +106,7 @@ func prepareJWSProof(suite signatureSuite, proofOptions map[string]interface{})
 }
 
 func prepareDocumentForJWS(suite signatureSuite, jsonldObject map[string]interface{}) ([]byte, error) {
-	// copy document object without proof
-	docCopy := GetCopyWithoutProof(jsonldObject)
-
-	docCompacted, err := getCompactedWithSecuritySchema(docCopy)
+	docCompacted, err := getCompactedWithSecuritySchema(jsonldObject)
 	if err != nil {
 		return nil, err
 	}
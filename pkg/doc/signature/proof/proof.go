@@ -8,7 +8,11 @@ package proof
 import (
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/btcsuite/btcutil/base58"
 )
 
 const (
@@ -20,6 +24,8 @@ const (
 	jsonldCreated = "created"
 	// jsonldDomain is key for domain name
 	jsonldDomain = "domain"
+	// jsonldChallenge is key for challenge
+	jsonldChallenge = "challenge"
 	// jsonldNonce is key for nonce
 	jsonldNonce = "nonce"
 	// jsonldProofValue is key for proof value
@@ -28,19 +34,36 @@ const (
 	jsonldProofPurpose = "proofPurpose"
 	// jsonldJWSProof is key for JWS proof
 	jsonldJWS = "jws"
+	// jsonldVerificationMethod is a key for verification method
+	jsonldVerificationMethod = "verificationMethod"
+	// jsonldID is key for proof ID
+	jsonldID = "id"
+	// jsonldPreviousProof is key for ID of the proof preceding this one in a proof chain
+	jsonldPreviousProof = "previousProof"
+
+	// ed25519Signature2020 is a proof type which keeps "proofValue" as multibase base58-btc string
+	ed25519Signature2020 = "Ed25519Signature2020"
+	// multibaseBase58BTC is a multibase prefix of base58-btc encoding
+	multibaseBase58BTC = "z"
 )
 
 // Proof is cryptographic proof of the integrity of the DID Document
 type Proof struct {
+	ID                      string
 	Type                    string
 	Created                 *time.Time
 	Creator                 string
 	ProofValue              []byte
 	JWS                     string
 	ProofPurpose            string
+	VerificationMethod      string
 	Domain                  string
+	Challenge               string
 	Nonce                   []byte
 	SignatureRepresentation SignatureRepresentation
+	// PreviousProof is the ID of the preceding proof in a proof chain. The chained proof signs the document
+	// including the proofs which precede it.
+	PreviousProof string
 }
 
 // NewProof creates new proof
@@ -59,7 +82,7 @@ func NewProof(emap map[string]interface{}) (*Proof, error) {
 	)
 
 	if generalProof, ok := emap[jsonldProofValue]; ok {
-		proofValue, err = base64.RawURLEncoding.DecodeString(stringEntry(generalProof))
+		proofValue, err = decodeProofValue(stringEntry(emap[jsonldType]), stringEntry(generalProof))
 		if err != nil {
 			return nil, err
 		}
@@ -80,6 +103,7 @@ func NewProof(emap map[string]interface{}) (*Proof, error) {
 	}
 
 	return &Proof{
+		ID:                      stringEntry(emap[jsonldID]),
 		Type:                    stringEntry(emap[jsonldType]),
 		Created:                 &timeValue,
 		Creator:                 stringEntry(emap[jsonldCreator]),
@@ -87,8 +111,11 @@ func NewProof(emap map[string]interface{}) (*Proof, error) {
 		SignatureRepresentation: proofHolder,
 		JWS:                     jws,
 		ProofPurpose:            stringEntry(emap[jsonldProofPurpose]),
+		VerificationMethod:      stringEntry(emap[jsonldVerificationMethod]),
 		Domain:                  stringEntry(emap[jsonldDomain]),
+		Challenge:               stringEntry(emap[jsonldChallenge]),
 		Nonce:                   nonce,
+		PreviousProof:           stringEntry(emap[jsonldPreviousProof]),
 	}, nil
 }
 
@@ -101,11 +128,43 @@ func stringEntry(entry interface{}) string {
 	return entry.(string)
 }
 
+// decodeProofValue decodes "proofValue" according to the proof type.
+// Ed25519Signature2020 proofs use multibase base58-btc encoding, others use base64url.
+func decodeProofValue(proofType, value string) ([]byte, error) {
+	if proofType != ed25519Signature2020 {
+		return base64.RawURLEncoding.DecodeString(value)
+	}
+
+	if !strings.HasPrefix(value, multibaseBase58BTC) {
+		return nil, fmt.Errorf("unsupported multibase encoding of proof value: %s", value)
+	}
+
+	decoded := base58.Decode(strings.TrimPrefix(value, multibaseBase58BTC))
+	if len(decoded) == 0 {
+		return nil, errors.New("illegal base58 data of proof value")
+	}
+
+	return decoded, nil
+}
+
+// encodeProofValue encodes "proofValue" according to the proof type (see decodeProofValue).
+func encodeProofValue(proofType string, value []byte) string {
+	if proofType == ed25519Signature2020 {
+		return multibaseBase58BTC + base58.Encode(value)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
 // JSONLdObject returns map that represents JSON LD Object
 func (p *Proof) JSONLdObject() map[string]interface{} {
 	emap := make(map[string]interface{})
 	emap[jsonldType] = p.Type
 
+	if p.ID != "" {
+		emap[jsonldID] = p.ID
+	}
+
 	if p.Creator != "" {
 		emap[jsonldCreator] = p.Creator
 	}
@@ -115,7 +174,7 @@ func (p *Proof) JSONLdObject() map[string]interface{} {
 	}
 
 	if len(p.ProofValue) > 0 {
-		emap[jsonldProofValue] = base64.RawURLEncoding.EncodeToString(p.ProofValue)
+		emap[jsonldProofValue] = encodeProofValue(p.Type, p.ProofValue)
 	}
 
 	if len(p.JWS) > 0 {
@@ -126,6 +185,10 @@ func (p *Proof) JSONLdObject() map[string]interface{} {
 		emap[jsonldDomain] = p.Domain
 	}
 
+	if p.Challenge != "" {
+		emap[jsonldChallenge] = p.Challenge
+	}
+
 	if len(p.Nonce) > 0 {
 		emap[jsonldNonce] = base64.RawURLEncoding.EncodeToString(p.Nonce)
 	}
@@ -134,5 +197,13 @@ func (p *Proof) JSONLdObject() map[string]interface{} {
 		emap[jsonldProofPurpose] = p.ProofPurpose
 	}
 
+	if p.VerificationMethod != "" {
+		emap[jsonldVerificationMethod] = p.VerificationMethod
+	}
+
+	if p.PreviousProof != "" {
+		emap[jsonldPreviousProof] = p.PreviousProof
+	}
+
 	return emap
 }
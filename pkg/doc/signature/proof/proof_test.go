@@ -7,9 +7,11 @@ package proof
 
 import (
 	"encoding/base64"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,12 +19,14 @@ const proofValueBase64 = "6mdES87erjP5r1qCSRW__otj-A_Rj0YgRO7XU_0Amhwdfa7AAmtGUS
 
 func TestProof(t *testing.T) {
 	p, err := NewProof(map[string]interface{}{
-		"type":       "type",
-		"creator":    "didID",
-		"created":    "2018-03-15T00:00:00Z",
-		"domain":     "abc.com",
-		"nonce":      "",
-		"proofValue": proofValueBase64,
+		"type":               "type",
+		"creator":            "didID",
+		"verificationMethod": "did:example:123456#key1",
+		"created":            "2018-03-15T00:00:00Z",
+		"domain":             "abc.com",
+		"challenge":          "3fa85f64",
+		"nonce":              "",
+		"proofValue":         proofValueBase64,
 	})
 	require.NoError(t, err)
 
@@ -35,8 +39,10 @@ func TestProof(t *testing.T) {
 
 	require.Equal(t, "type", p.Type)
 	require.Equal(t, "didID", p.Creator)
+	require.Equal(t, "did:example:123456#key1", p.VerificationMethod)
 	require.Equal(t, &created, p.Created)
 	require.Equal(t, "abc.com", p.Domain)
+	require.Equal(t, "3fa85f64", p.Challenge)
 	require.Equal(t, []byte(""), p.Nonce)
 	require.Equal(t, proofValueBytes, p.ProofValue)
 }
@@ -91,14 +97,18 @@ func TestProof_JSONLdObject(t *testing.T) {
 	r.NoError(err)
 
 	p := &Proof{
-		Type:         "Ed25519Signature2018",
-		Created:      &created,
-		Creator:      "creator",
-		ProofValue:   proofValueBytes,
-		JWS:          "test.jws.value",
-		ProofPurpose: "assertionMethod",
-		Domain:       "internal",
-		Nonce:        nonceBase64,
+		ID:                 "urn:uuid:proof-2",
+		Type:               "Ed25519Signature2018",
+		Created:            &created,
+		Creator:            "creator",
+		ProofValue:         proofValueBytes,
+		JWS:                "test.jws.value",
+		ProofPurpose:       "assertionMethod",
+		VerificationMethod: "did:example:123456#key1",
+		Domain:             "internal",
+		Challenge:          "3fa85f64",
+		Nonce:              nonceBase64,
+		PreviousProof:      "urn:uuid:proof-1",
 	}
 
 	pJSONLd := p.JSONLdObject()
@@ -108,6 +118,65 @@ func TestProof_JSONLdObject(t *testing.T) {
 	r.Equal(proofValueBase64, pJSONLd["proofValue"])
 	r.Equal("test.jws.value", pJSONLd["jws"])
 	r.Equal("assertionMethod", pJSONLd["proofPurpose"])
+	r.Equal("did:example:123456#key1", pJSONLd["verificationMethod"])
 	r.Equal("internal", pJSONLd["domain"])
+	r.Equal("3fa85f64", pJSONLd["challenge"])
 	r.Equal("abc", pJSONLd["nonce"])
+	r.Equal("urn:uuid:proof-2", pJSONLd["id"])
+	r.Equal("urn:uuid:proof-1", pJSONLd["previousProof"])
+
+	parsedProof, err := NewProof(pJSONLd)
+	r.NoError(err)
+	r.Equal("urn:uuid:proof-2", parsedProof.ID)
+	r.Equal("urn:uuid:proof-1", parsedProof.PreviousProof)
+}
+
+func TestProof_Ed25519Signature2020ProofValue(t *testing.T) {
+	proofValueBytes, err := base64.RawURLEncoding.DecodeString(proofValueBase64)
+	require.NoError(t, err)
+
+	t.Run("round trip of multibase base58-btc proof value", func(t *testing.T) {
+		created, err := time.Parse(time.RFC3339, "2018-03-15T00:00:00Z")
+		require.NoError(t, err)
+
+		p := &Proof{
+			Type:       "Ed25519Signature2020",
+			Created:    &created,
+			ProofValue: proofValueBytes,
+		}
+
+		pJSONLd := p.JSONLdObject()
+
+		proofValue, ok := pJSONLd["proofValue"].(string)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(proofValue, "z"))
+		require.Equal(t, "z"+base58.Encode(proofValueBytes), proofValue)
+
+		parsed, err := NewProof(pJSONLd)
+		require.NoError(t, err)
+		require.Equal(t, proofValueBytes, parsed.ProofValue)
+		require.Equal(t, SignatureProofValue, parsed.SignatureRepresentation)
+	})
+
+	t.Run("unsupported multibase encoding", func(t *testing.T) {
+		p, err := NewProof(map[string]interface{}{
+			"type":       "Ed25519Signature2020",
+			"created":    "2011-09-23T20:21:34Z",
+			"proofValue": proofValueBase64,
+		})
+		require.Error(t, err)
+		require.Nil(t, p)
+		require.Contains(t, err.Error(), "unsupported multibase encoding of proof value")
+	})
+
+	t.Run("illegal base58 data", func(t *testing.T) {
+		p, err := NewProof(map[string]interface{}{
+			"type":       "Ed25519Signature2020",
+			"created":    "2011-09-23T20:21:34Z",
+			"proofValue": "z0OIl",
+		})
+		require.Error(t, err)
+		require.Nil(t, p)
+		require.Contains(t, err.Error(), "illegal base58 data of proof value")
+	})
 }
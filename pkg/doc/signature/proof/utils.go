@@ -7,6 +7,7 @@ package proof
 
 import (
 	"errors"
+	"fmt"
 )
 
 const (
@@ -15,19 +16,13 @@ const (
 
 // GetProofs gets proof(s) from LD Object
 func GetProofs(jsonLdObject map[string]interface{}) ([]*Proof, error) {
-	entry, ok := jsonLdObject[jsonldProof]
-	if !ok {
+	if _, ok := jsonLdObject[jsonldProof]; !ok {
 		return nil, ErrProofNotFound
 	}
 
-	var typedEntry []interface{}
-	switch te := entry.(type) {
-	case []interface{}:
-		typedEntry = te
-	case map[string]interface{}:
-		typedEntry = []interface{}{te}
-	default:
-		return nil, errors.New("expecting []interface{} or map[string]interface{}, got something else")
+	typedEntry, err := getProofEntries(jsonLdObject)
+	if err != nil {
+		return nil, err
 	}
 
 	var result []*Proof
@@ -49,6 +44,23 @@ func GetProofs(jsonLdObject map[string]interface{}) ([]*Proof, error) {
 	return result, nil
 }
 
+// getProofEntries returns proof(s) of LD Object as a list
+func getProofEntries(jsonLdObject map[string]interface{}) ([]interface{}, error) {
+	entry, ok := jsonLdObject[jsonldProof]
+	if !ok || entry == nil {
+		return nil, nil
+	}
+
+	switch te := entry.(type) {
+	case []interface{}:
+		return te, nil
+	case map[string]interface{}:
+		return []interface{}{te}, nil
+	default:
+		return nil, errors.New("expecting []interface{} or map[string]interface{}, got something else")
+	}
+}
+
 // AddProof adds a proof to LD Object
 func AddProof(jsonLdObject map[string]interface{}, proof *Proof) error {
 	var proofs []interface{}
@@ -87,5 +99,30 @@ func GetCopyWithoutProof(jsonLdObject map[string]interface{}) map[string]interfa
 	return dest
 }
 
+// GetCopyWithPrecedingProofs gets copy of JSON LD Object with its first n proofs only, i.e. the document
+// as it is signed by the next proof of a proof chain. A single proof is kept as an object.
+func GetCopyWithPrecedingProofs(jsonLdObject map[string]interface{}, n int) (map[string]interface{}, error) {
+	proofs, err := getProofEntries(jsonLdObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if n > len(proofs) {
+		return nil, fmt.Errorf("document has %d proofs, expected at least %d", len(proofs), n)
+	}
+
+	dest := GetCopyWithoutProof(jsonLdObject)
+
+	switch n {
+	case 0:
+	case 1:
+		dest[jsonldProof] = proofs[0]
+	default:
+		dest[jsonldProof] = proofs[:n]
+	}
+
+	return dest, nil
+}
+
 // ErrProofNotFound is returned when proof is not found
 var ErrProofNotFound = errors.New("proof not found")
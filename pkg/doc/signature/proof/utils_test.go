@@ -61,6 +61,43 @@ func TestGetCopyWithoutProof(t *testing.T) {
 	require.True(t, reflect.DeepEqual(docCopy, getDefaultDoc()))
 }
 
+func TestGetCopyWithPrecedingProofs(t *testing.T) {
+	doc := getDefaultDoc()
+
+	docCopy, err := GetCopyWithPrecedingProofs(doc, 0)
+	require.NoError(t, err)
+	require.Equal(t, getDefaultDoc(), docCopy)
+
+	proof1 := map[string]interface{}{"id": "proof-1", "type": "Ed25519Signature2018"}
+	proof2 := map[string]interface{}{"id": "proof-2", "type": "Ed25519Signature2018"}
+	proof3 := map[string]interface{}{"id": "proof-3", "type": "Ed25519Signature2018"}
+	doc["proof"] = []interface{}{proof1, proof2, proof3}
+
+	// a single proof is kept as an object
+	docCopy, err = GetCopyWithPrecedingProofs(doc, 1)
+	require.NoError(t, err)
+	require.Equal(t, proof1, docCopy["proof"])
+
+	docCopy, err = GetCopyWithPrecedingProofs(doc, 2)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{proof1, proof2}, docCopy["proof"])
+
+	docCopy, err = GetCopyWithPrecedingProofs(doc, 0)
+	require.NoError(t, err)
+	require.NotContains(t, docCopy, "proof")
+
+	// the original document is left untouched
+	require.Len(t, doc["proof"], 3)
+
+	_, err = GetCopyWithPrecedingProofs(doc, 4)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "document has 3 proofs, expected at least 4")
+
+	doc["proof"] = "invalid proof"
+	_, err = GetCopyWithPrecedingProofs(doc, 1)
+	require.Error(t, err)
+}
+
 func TestAddSingleProof(t *testing.T) {
 	doc := map[string]interface{}{
 		"test": "test",
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package signature provides the abstractions shared by the signature suites
+// of the Linked Data Signatures [LD-SIGNATURES] specification.
+package signature
+
+import (
+	"crypto"
+	"crypto/rand"
+	"fmt"
+)
+
+// Signer signs the data with a private key which may be kept outside of the process
+// (e.g. in a cloud KMS or a hardware token). It is accepted by all signature suites.
+type Signer interface {
+	// Sign will sign data and return signature
+	Sign(data []byte) ([]byte, error)
+
+	// PublicKey returns the public key corresponding to the signing key
+	PublicKey() crypto.PublicKey
+
+	// KeyID returns ID of the signing key (e.g. DID URL of the verification method)
+	KeyID() string
+}
+
+// CryptoSigner adapts Go's crypto.Signer to the Signer.
+type CryptoSigner struct {
+	signer crypto.Signer
+	keyID  string
+	opts   crypto.SignerOpts
+}
+
+// NewCryptoSigner returns new instance of CryptoSigner. If opts defines a hash function, the data
+// is hashed before signing; if opts is nil, the data is signed as is (e.g. for ed25519.PrivateKey).
+func NewCryptoSigner(signer crypto.Signer, keyID string, opts crypto.SignerOpts) *CryptoSigner {
+	if opts == nil {
+		opts = crypto.Hash(0)
+	}
+
+	return &CryptoSigner{
+		signer: signer,
+		keyID:  keyID,
+		opts:   opts,
+	}
+}
+
+// Sign will sign data and return signature.
+func (s *CryptoSigner) Sign(data []byte) ([]byte, error) {
+	digest := data
+
+	if hash := s.opts.HashFunc(); hash != 0 {
+		if !hash.Available() {
+			return nil, fmt.Errorf("sign: hash function %v is not available", hash)
+		}
+
+		h := hash.New()
+		h.Write(data)
+
+		digest = h.Sum(nil)
+	}
+
+	signature, err := s.signer.Sign(rand.Reader, digest, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return signature, nil
+}
+
+// PublicKey returns the public key corresponding to the signing key.
+func (s *CryptoSigner) PublicKey() crypto.PublicKey {
+	return s.signer.Public()
+}
+
+// KeyID returns ID of the signing key.
+func (s *CryptoSigner) KeyID() string {
+	return s.keyID
+}
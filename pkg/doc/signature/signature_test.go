@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoSigner(t *testing.T) {
+	data := []byte("test data")
+
+	t.Run("test ed25519 signer", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		s := NewCryptoSigner(privKey, "did:example:123#key-1", nil)
+		require.Equal(t, "did:example:123#key-1", s.KeyID())
+		require.Equal(t, pubKey, s.PublicKey())
+
+		sig, err := s.Sign(data)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, data, sig))
+	})
+
+	t.Run("test rsa signer hashes the data", func(t *testing.T) {
+		privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		s := NewCryptoSigner(privKey, "did:example:123#key-2", crypto.SHA256)
+		require.Equal(t, &privKey.PublicKey, s.PublicKey())
+
+		sig, err := s.Sign(data)
+		require.NoError(t, err)
+
+		digest := sha256.Sum256(data)
+		require.NoError(t, rsa.VerifyPKCS1v15(&privKey.PublicKey, crypto.SHA256, digest[:], sig))
+	})
+
+	t.Run("test hash function is not available", func(t *testing.T) {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		sig, err := NewCryptoSigner(privKey, "", crypto.MD4).Sign(data)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not available")
+		require.Nil(t, sig)
+	})
+
+	t.Run("test error from crypto signer", func(t *testing.T) {
+		sig, err := NewCryptoSigner(&failingCryptoSigner{}, "", nil).Sign(data)
+		require.Error(t, err)
+		require.EqualError(t, err, "sign: sign error")
+		require.Nil(t, sig)
+	})
+}
+
+type failingCryptoSigner struct{}
+
+func (s *failingCryptoSigner) Public() crypto.PublicKey {
+	return nil
+}
+
+func (s *failingCryptoSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, errors.New("sign error")
+}
@@ -43,7 +43,14 @@ type Context struct {
 	SignatureRepresentation proof.SignatureRepresentation // optional
 	Created                 *time.Time                    // optional
 	Domain                  string                        // optional
+	Challenge               string                        // optional
 	Nonce                   []byte                        // optional
+	ProofPurpose            string                        // optional
+	VerificationMethod      string                        // optional
+	ID                      string                        // optional
+	// PreviousProof is ID of the last proof of the document, the new proof is chained to it
+	// i.e. it signs the document including the existing proofs (optional)
+	PreviousProof string
 }
 
 // New returns new instance of document verifier
@@ -84,6 +91,12 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 		return err
 	}
 
+	if context.PreviousProof != "" {
+		if err = checkPreviousProof(jsonLdObject, context.PreviousProof); err != nil {
+			return err
+		}
+	}
+
 	created := context.Created
 	if created == nil {
 		now := time.Now()
@@ -92,11 +105,16 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 
 	p := &proof.Proof{
 		Type:                    context.SignatureType,
+		ID:                      context.ID,
 		SignatureRepresentation: context.SignatureRepresentation,
 		Creator:                 context.Creator,
 		Created:                 created,
 		Domain:                  context.Domain,
+		Challenge:               context.Challenge,
 		Nonce:                   context.Nonce,
+		ProofPurpose:            context.ProofPurpose,
+		VerificationMethod:      context.VerificationMethod,
+		PreviousProof:           context.PreviousProof,
 	}
 
 	if context.SignatureRepresentation == proof.SignatureJWS {
@@ -118,6 +136,20 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 	return proof.AddProof(jsonLdObject, p)
 }
 
+// checkPreviousProof checks that the proof a new proof is chained to is the last proof of the document
+func checkPreviousProof(jsonLdObject map[string]interface{}, previousProof string) error {
+	proofs, err := proof.GetProofs(jsonLdObject)
+	if err != nil {
+		return fmt.Errorf("previous proof: %w", err)
+	}
+
+	if last := proofs[len(proofs)-1]; last.ID != previousProof {
+		return fmt.Errorf("previous proof %s is not the last proof of the document", previousProof)
+	}
+
+	return nil
+}
+
 func (signer *DocumentSigner) applySignatureValue(context *Context, p *proof.Proof, s []byte) {
 	switch context.SignatureRepresentation {
 	case proof.SignatureProofValue:
@@ -9,13 +9,13 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/json"
-	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 const signatureType = "Ed25519Signature2018"
@@ -25,7 +25,7 @@ func TestDocumentSigner_Sign(t *testing.T) {
 
 	s := New(ed25519signature2018.New(
 		ed25519signature2018.WithSigner(
-			getSigner(generatePrivateKey()))))
+			mocksignature.NewEd25519Signer(generatePrivateKey()))))
 	signedDoc, err := s.Sign(context, []byte(validDoc))
 	require.NoError(t, err)
 	require.NotNil(t, signedDoc)
@@ -34,13 +34,32 @@ func TestDocumentSigner_Sign(t *testing.T) {
 	signedJWSDoc, err := s.Sign(context, []byte(validDoc))
 	require.NoError(t, err)
 	require.NotNil(t, signedJWSDoc)
+
+	context.ProofPurpose = "assertionMethod"
+	context.VerificationMethod = "did:example:123456#key1"
+	signedDoc, err = s.Sign(context, []byte(validDoc))
+	require.NoError(t, err)
+
+	var signedDocMap map[string]interface{}
+
+	err = json.Unmarshal(signedDoc, &signedDocMap)
+	require.NoError(t, err)
+
+	proofs, ok := signedDocMap["proof"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, proofs, 1)
+
+	proofMap, ok := proofs[0].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "assertionMethod", proofMap["proofPurpose"])
+	require.Equal(t, "did:example:123456#key1", proofMap["verificationMethod"])
 }
 
 func TestDocumentSigner_SignErrors(t *testing.T) {
 	context := getSignatureContext()
 	s := New(ed25519signature2018.New(
 		ed25519signature2018.WithSigner(
-			getSigner(generatePrivateKey()))))
+			mocksignature.NewEd25519Signer(generatePrivateKey()))))
 
 	// test invalid json
 	signedDoc, err := s.Sign(context, []byte("not json"))
@@ -72,11 +91,35 @@ func TestDocumentSigner_SignErrors(t *testing.T) {
 	require.Nil(t, signedDoc)
 	require.Contains(t, err.Error(), "invalid context")
 
+	// test previous proof is not the last proof of the document
+	context = getSignatureContext()
+	context.PreviousProof = "proof-1"
+	signedDoc, err = s.Sign(context, []byte(validDoc))
+	require.Error(t, err)
+	require.Nil(t, signedDoc)
+	require.Contains(t, err.Error(), "previous proof: proof not found")
+
+	validDocMap = make(map[string]interface{})
+	err = json.Unmarshal([]byte(validDoc), &validDocMap)
+	require.NoError(t, err)
+
+	validDocMap["proof"] = []interface{}{
+		map[string]interface{}{"id": "proof-1", "created": "2020-01-21T12:59:31Z", "proofValue": "c2lnbmF0dXJl"},
+		map[string]interface{}{"id": "proof-2", "created": "2020-01-21T12:59:31Z", "proofValue": "c2lnbmF0dXJl"},
+	}
+	docWithProofsBytes, err := json.Marshal(validDocMap)
+	require.NoError(t, err)
+
+	signedDoc, err = s.Sign(context, docWithProofsBytes)
+	require.Error(t, err)
+	require.Nil(t, signedDoc)
+	require.Contains(t, err.Error(), "previous proof proof-1 is not the last proof of the document")
+
 	// test signing error
 	context = getSignatureContext()
 	s = New(ed25519signature2018.New(
 		ed25519signature2018.WithSigner(
-			getSigner([]byte("invalid")))))
+			mocksignature.NewEd25519Signer([]byte("invalid")))))
 	signedDoc, err = s.Sign(context, []byte(validDoc))
 	require.NotNil(t, err)
 	require.Nil(t, signedDoc)
@@ -108,22 +151,6 @@ func generatePrivateKey() []byte {
 	return privKey
 }
 
-func getSigner(privKey []byte) *testSigner {
-	return &testSigner{privateKey: privKey}
-}
-
-type testSigner struct {
-	privateKey []byte
-}
-
-func (s *testSigner) Sign(doc []byte) ([]byte, error) {
-	if l := len(s.privateKey); l != ed25519.PrivateKeySize {
-		return nil, errors.New("ed25519: bad private key length")
-	}
-
-	return ed25519.Sign(s.privateKey, doc), nil
-}
-
 //nolint:lll
 const validDoc = `{
   "@context": ["https://w3id.org/did/v1"],
@@ -7,14 +7,18 @@ package verifier
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
 )
 
-// signatureSuite encapsulates signature suite methods required for signature verification
-type signatureSuite interface {
+// ErrProofChainOrder is returned when a chained proof does not directly follow its previous proof
+var ErrProofChainOrder = errors.New("invalid proof chain order")
+
+// SignatureSuite encapsulates signature suite methods required for signature verification
+type SignatureSuite interface {
 
 	// GetCanonicalDocument will return normalized/canonical version of the document
 	GetCanonicalDocument(doc map[string]interface{}) ([]byte, error)
@@ -36,16 +40,32 @@ type keyResolver interface {
 	Resolve(id string) ([]byte, error)
 }
 
+// CandidateKey is the public key which may verify the proof.
+type CandidateKey struct {
+	ID    string
+	Value []byte
+}
+
+// candidateKeysResolver is implemented by the key resolvers which resolve several keys for the proof,
+// e.g. all keys published by the issuer DID during the key rotation window.
+type candidateKeysResolver interface {
+
+	// ResolveCandidates will return the keys which may verify the proof, the keys are tried in order
+	ResolveCandidates(id string) ([]*CandidateKey, error)
+}
+
 // DocumentVerifier implements JSON LD document proof verification
 type DocumentVerifier struct {
-	signatureSuites []signatureSuite
+	signatureSuites []SignatureSuite
 	pkResolver      keyResolver
 }
 
-// New returns new instance of document verifier
-func New(resolver keyResolver, signatureSuites ...signatureSuite) *DocumentVerifier {
+// New returns new instance of document verifier. If the resolver resolves several candidate keys
+// (i.e. it has ResolveCandidates(id string) ([]*CandidateKey, error) method), the proof is verified
+// if any of the candidate keys verifies it.
+func New(resolver keyResolver, signatureSuites ...SignatureSuite) *DocumentVerifier {
 	if len(signatureSuites) == 0 {
-		signatureSuites = []signatureSuite{ed25519signature2018.New()}
+		signatureSuites = []SignatureSuite{ed25519signature2018.New()}
 	}
 
 	return &DocumentVerifier{signatureSuites: signatureSuites, pkResolver: resolver}
@@ -53,55 +73,130 @@ func New(resolver keyResolver, signatureSuites ...signatureSuite) *DocumentVerif
 
 // Verify will verify document proofs
 func (dv *DocumentVerifier) Verify(jsonLdDoc []byte) error {
+	_, err := dv.VerifyKeys(jsonLdDoc)
+
+	return err
+}
+
+// VerifyKeys will verify document proofs and return the IDs of the keys which verified them, in order of the proofs.
+func (dv *DocumentVerifier) VerifyKeys(jsonLdDoc []byte) ([]string, error) {
 	var jsonLdObject map[string]interface{}
 
 	err := json.Unmarshal(jsonLdDoc, &jsonLdObject)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal json ld document: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal json ld document: %w", err)
 	}
 
-	return dv.verifyObject(jsonLdObject)
+	return dv.verifyObjectKeys(jsonLdObject)
 }
 
 // verifyObject will verify document proofs for JSON LD object
 func (dv *DocumentVerifier) verifyObject(jsonLdObject map[string]interface{}) error {
+	_, err := dv.verifyObjectKeys(jsonLdObject)
+
+	return err
+}
+
+// verifyObjectKeys will verify document proofs for JSON LD object and return the IDs of the keys which verified them
+func (dv *DocumentVerifier) verifyObjectKeys(jsonLdObject map[string]interface{}) ([]string, error) {
 	proofs, err := proof.GetProofs(jsonLdObject)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, p := range proofs {
-		publicKey, err := dv.pkResolver.Resolve(p.Creator)
+	keyIDs := make([]string, len(proofs))
+
+	for i, p := range proofs {
+		keys, err := dv.resolveKeys(getPublicKeyID(p))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		suite, err := dv.getSignatureSuite(p.Type)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		signedDoc, err := getSignedDocument(jsonLdObject, proofs, i)
+		if err != nil {
+			return nil, err
 		}
 
-		message, err := proof.CreateVerifyData(suite, jsonLdObject, p)
+		message, err := proof.CreateVerifyData(suite, signedDoc, p)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		signature, err := getProofVerifyValue(p)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		err = suite.Verify(publicKey, message, signature)
+		keyIDs[i], err = verifySignature(suite, keys, message, signature)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keyIDs, nil
+}
+
+// resolveKeys resolves the candidate keys if supported by the resolver or the single key otherwise.
+func (dv *DocumentVerifier) resolveKeys(id string) ([]*CandidateKey, error) {
+	if resolver, ok := dv.pkResolver.(candidateKeysResolver); ok {
+		keys, err := resolver.ResolveCandidates(id)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("no candidate keys are resolved for %s", id)
+		}
+
+		return keys, nil
+	}
+
+	publicKey, err := dv.pkResolver.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*CandidateKey{{ID: id, Value: publicKey}}, nil
+}
+
+// verifySignature verifies the signature by the keys in order and returns the ID of the first key
+// which verifies it, the error of the last key is returned if none does.
+func verifySignature(suite SignatureSuite, keys []*CandidateKey, message, signature []byte) (string, error) {
+	var err error
+
+	for _, key := range keys {
+		if err = suite.Verify(key.Value, message, signature); err == nil {
+			return key.ID, nil
 		}
 	}
 
-	return nil
+	return "", err
+}
+
+// getSignedDocument returns the document as it was signed by the i-th proof. A chained proof signs
+// the document including the preceding proofs, the last of which must be its previous proof.
+func getSignedDocument(jsonLdObject map[string]interface{}, proofs []*proof.Proof,
+	i int) (map[string]interface{}, error) {
+	p := proofs[i]
+	if p.PreviousProof == "" {
+		return jsonLdObject, nil
+	}
+
+	if i == 0 || proofs[i-1].ID != p.PreviousProof {
+		return nil, fmt.Errorf("%w: previous proof %s does not precede proof %s",
+			ErrProofChainOrder, p.PreviousProof, p.ID)
+	}
+
+	return proof.GetCopyWithPrecedingProofs(jsonLdObject, i)
 }
 
 // getSignatureSuite returns signature suite based on signature type
-func (dv *DocumentVerifier) getSignatureSuite(signatureType string) (signatureSuite, error) {
+func (dv *DocumentVerifier) getSignatureSuite(signatureType string) (SignatureSuite, error) {
 	for _, s := range dv.signatureSuites {
 		if s.Accept(signatureType) {
 			return s, nil
@@ -111,6 +206,16 @@ func (dv *DocumentVerifier) getSignatureSuite(signatureType string) (signatureSu
 	return nil, fmt.Errorf("signature type %s not supported", signatureType)
 }
 
+// getPublicKeyID returns the ID of the public key used to verify the proof.
+// Verification method takes precedence over the deprecated creator.
+func getPublicKeyID(p *proof.Proof) string {
+	if p.VerificationMethod != "" {
+		return p.VerificationMethod
+	}
+
+	return p.Creator
+}
+
 func getProofVerifyValue(p *proof.Proof) ([]byte, error) {
 	switch p.SignatureRepresentation {
 	case proof.SignatureProofValue:
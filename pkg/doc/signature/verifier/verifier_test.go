@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 func TestNew(t *testing.T) {
@@ -131,6 +132,83 @@ func TestVerifyJWSObject(t *testing.T) {
 	require.Contains(t, err.Error(), "invalid JWT")
 }
 
+func TestVerifyProofChain(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signedDocBytes, tkr := getDefaultSignedDoc(proof.SignatureProofValue, privKey, pubKey)
+
+	s := signer.New(ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))))
+
+	var doc map[string]interface{}
+
+	err = json.Unmarshal(signedDocBytes, &doc)
+	require.NoError(t, err)
+
+	doc["proof"].(map[string]interface{})["id"] = "proof-1"
+
+	docBytes, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	for _, p := range []struct{ id, previous string }{{"proof-2", "proof-1"}, {"proof-3", "proof-2"}} {
+		docBytes, err = s.Sign(&signer.Context{
+			Creator:       "key-1",
+			SignatureType: "Ed25519Signature2018",
+			ID:            p.id,
+			PreviousProof: p.previous,
+		}, docBytes)
+		require.NoError(t, err)
+	}
+
+	v := New(tkr)
+	err = v.Verify(docBytes)
+	require.NoError(t, err)
+
+	// proofs out of chain order
+	err = json.Unmarshal(docBytes, &doc)
+	require.NoError(t, err)
+
+	proofs := doc["proof"].([]interface{})
+	proofs[1], proofs[2] = proofs[2], proofs[1]
+
+	err = v.verifyObject(doc)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrProofChainOrder))
+}
+
+func Test_getSignedDocument(t *testing.T) {
+	doc := getDefaultDoc()
+	doc["proof"] = []interface{}{
+		map[string]interface{}{"id": "proof-1"},
+		map[string]interface{}{"id": "proof-2", "previousProof": "proof-1"},
+		map[string]interface{}{"id": "proof-3", "previousProof": "proof-1"},
+	}
+
+	proofs := []*proof.Proof{
+		{ID: "proof-1"},
+		{ID: "proof-2", PreviousProof: "proof-1"},
+		{ID: "proof-3", PreviousProof: "proof-1"},
+	}
+
+	// not chained proof signs the whole document
+	signedDoc, err := getSignedDocument(doc, proofs, 0)
+	require.NoError(t, err)
+	require.Equal(t, doc, signedDoc)
+
+	signedDoc, err = getSignedDocument(doc, proofs, 1)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"id": "proof-1"}, signedDoc["proof"])
+
+	_, err = getSignedDocument(doc, proofs, 2)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrProofChainOrder))
+	require.Contains(t, err.Error(), "previous proof proof-1 does not precede proof proof-3")
+
+	_, err = getSignedDocument(doc, proofs[1:], 0)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrProofChainOrder))
+}
+
 func Test_getProofVerifyValue(t *testing.T) {
 	jwsSignature := base64.RawURLEncoding.EncodeToString([]byte("signature"))
 
@@ -158,6 +236,14 @@ func Test_getProofVerifyValue(t *testing.T) {
 	require.Nil(t, proofVerifyValue)
 }
 
+func Test_getPublicKeyID(t *testing.T) {
+	p := &proof.Proof{Creator: "creator"}
+	require.Equal(t, "creator", getPublicKeyID(p))
+
+	p.VerificationMethod = "did:example:123456#key1"
+	require.Equal(t, "did:example:123456#key1", getPublicKeyID(p))
+}
+
 func getDefaultSignedDoc(signatureRepr proof.SignatureRepresentation, privKey, pubKey []byte) ([]byte, keyResolver) {
 	const creator = "key-1"
 
@@ -177,7 +263,7 @@ func getDefaultSignedDoc(signatureRepr proof.SignatureRepresentation, privKey, p
 
 	s := signer.New(ed25519signature2018.New(
 		ed25519signature2018.WithSigner(
-			getSigner(privKey))))
+			mocksignature.NewEd25519Signer(privKey))))
 
 	signedDocBytes, err := s.Sign(&context, docBytes)
 	if err != nil {
@@ -212,22 +298,6 @@ func getDefaultDoc() map[string]interface{} {
 	return doc
 }
 
-func getSigner(privKey []byte) *testSigner {
-	return &testSigner{privateKey: privKey}
-}
-
-type testSigner struct {
-	privateKey []byte
-}
-
-func (s *testSigner) Sign(doc []byte) ([]byte, error) {
-	if l := len(s.privateKey); l != ed25519.PrivateKeySize {
-		return nil, errors.New("ed25519: bad private key length")
-	}
-
-	return ed25519.Sign(s.privateKey, doc), nil
-}
-
 type testKeyResolver struct {
 	Keys map[string][]byte
 }
@@ -255,3 +325,92 @@ const validDoc = `{
   ],
   "created": "2002-10-10T17:00:00Z"
 }`
+
+// testSuite accepts the signature equal to the public key, the document is canonicalized as JSON
+type testSuite struct{}
+
+func (testSuite) GetCanonicalDocument(doc map[string]interface{}) ([]byte, error) {
+	return json.Marshal(doc)
+}
+
+func (testSuite) GetDigest(doc []byte) []byte {
+	return doc
+}
+
+func (testSuite) Verify(pubKey, _, signature []byte) error {
+	if string(pubKey) != string(signature) {
+		return errors.New("signature doesn't match")
+	}
+
+	return nil
+}
+
+func (testSuite) Accept(string) bool {
+	return true
+}
+
+type testCandidateKeysResolver struct {
+	testKeyResolver
+	candidates []*CandidateKey
+	err        error
+}
+
+func (r *testCandidateKeysResolver) ResolveCandidates(string) ([]*CandidateKey, error) {
+	return r.candidates, r.err
+}
+
+func TestDocumentVerifier_VerifyKeys(t *testing.T) {
+	doc := func(signature string) []byte {
+		return []byte(`{
+  "id": "did:example:123",
+  "proof": {
+    "type": "TestSignature",
+    "created": "2020-01-01T00:00:00Z",
+    "verificationMethod": "did:example:issuer#key",
+    "proofValue": "` + base64.RawURLEncoding.EncodeToString([]byte(signature)) + `"
+  }
+}`)
+	}
+
+	t.Run("single key", func(t *testing.T) {
+		v := New(&testKeyResolver{Keys: map[string][]byte{"did:example:issuer#key": []byte("new key")}}, testSuite{})
+
+		keyIDs, err := v.VerifyKeys(doc("new key"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:issuer#key"}, keyIDs)
+	})
+
+	t.Run("candidate keys", func(t *testing.T) {
+		resolver := &testCandidateKeysResolver{candidates: []*CandidateKey{
+			{ID: "did:example:issuer#old", Value: []byte("old key")},
+			{ID: "did:example:issuer#new", Value: []byte("new key")},
+		}}
+
+		v := New(resolver, testSuite{})
+
+		keyIDs, err := v.VerifyKeys(doc("new key"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:issuer#new"}, keyIDs)
+
+		keyIDs, err = v.VerifyKeys(doc("old key"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:issuer#old"}, keyIDs)
+
+		require.NoError(t, v.Verify(doc("old key")))
+
+		_, err = v.VerifyKeys(doc("unknown key"))
+		require.EqualError(t, err, "signature doesn't match")
+	})
+
+	t.Run("candidate keys resolution error", func(t *testing.T) {
+		v := New(&testCandidateKeysResolver{err: errors.New("resolve error")}, testSuite{})
+
+		_, err := v.VerifyKeys(doc("new key"))
+		require.EqualError(t, err, "resolve error")
+
+		v = New(&testCandidateKeysResolver{}, testSuite{})
+
+		_, err = v.VerifyKeys(doc("new key"))
+		require.EqualError(t, err, "no candidate keys are resolved for did:example:issuer#key")
+	})
+}
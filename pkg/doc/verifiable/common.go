@@ -14,6 +14,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -225,6 +226,29 @@ func decodeContext(c interface{}) ([]string, []interface{}, error) {
 	}
 }
 
+// decodeContexts decodes raw context(s) of the credential keeping their order.
+//
+// context can be defined as a single string or object value or as an array
+// of string and object values (objects express inline context information).
+func decodeContexts(c interface{}) (Contexts, error) {
+	switch rContext := c.(type) {
+	case string, map[string]interface{}:
+		return Contexts{rContext}, nil
+	case []interface{}:
+		for i := range rContext {
+			switch rContext[i].(type) {
+			case string, map[string]interface{}:
+			default:
+				return nil, fmt.Errorf("credential context entry %d of unknown type", i)
+			}
+		}
+
+		return Contexts(rContext), nil
+	default:
+		return nil, errors.New("credential context of unknown type")
+	}
+}
+
 func safeStringValue(v interface{}) string {
 	if v == nil {
 		return ""
@@ -233,17 +257,25 @@ func safeStringValue(v interface{}) string {
 	return v.(string)
 }
 
-func proofsToRaw(proofs []Proof) ([]byte, error) {
-	switch len(proofs) {
-	case 0:
+// proofsToRaw serializes the proofs, a single proof is serialized as object unless proofSet is true.
+func proofsToRaw(proofs []Proof, proofSet bool) ([]byte, error) {
+	switch {
+	case len(proofs) == 0:
 		return nil, nil
-	case 1:
+	case len(proofs) == 1 && !proofSet:
 		return json.Marshal(proofs[0])
 	default:
 		return json.Marshal(proofs)
 	}
 }
 
+// isProofSet checks if the proof is defined as an array (proof set) rather than a single object.
+func isProofSet(proofBytes json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(proofBytes)
+
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
 func decodeProof(proofBytes json.RawMessage) ([]Proof, error) {
 	if len(proofBytes) == 0 {
 		return nil, nil
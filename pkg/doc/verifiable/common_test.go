@@ -212,7 +212,7 @@ func Test_proofsToRaw(t *testing.T) {
 		"proofValue": "eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..67TTULBvibJaJ2oZf3tGYhxZqxYS89qGQykL5hfCoh-MF0vrwQqzciZhjNrAGTAgHtDZsnSQVwJ8bO_7Sc0ECw", //nolint:lll
 	}}
 
-	singleProofBytes, err := proofsToRaw(singleProof)
+	singleProofBytes, err := proofsToRaw(singleProof, false)
 	require.NoError(t, err)
 
 	var singleProofMap map[string]interface{}
@@ -224,12 +224,20 @@ func Test_proofsToRaw(t *testing.T) {
 		singleProof[0],
 		{"proofValue": "if8ooA+32YZc4SQBvIDDY9tgTatPoq4IZ8Kr+We1t38LR2RuURmaVu9D4shbi4VvND87PUqq5/0vsNFEGIIEDA=="},
 	}
-	severalProofsBytes, err := proofsToRaw(severalProofs)
+	severalProofsBytes, err := proofsToRaw(severalProofs, false)
 	require.NoError(t, err)
 
 	var severalProofsMap []map[string]interface{}
 	err = json.Unmarshal(severalProofsBytes, &severalProofsMap)
 	require.NoError(t, err)
+
+	singleProofSetBytes, err := proofsToRaw(singleProof, true)
+	require.NoError(t, err)
+
+	var singleProofSetMap []map[string]interface{}
+	err = json.Unmarshal(singleProofSetBytes, &singleProofSetMap)
+	require.NoError(t, err)
+	require.Len(t, singleProofSetMap, 1)
 }
 
 func TestNewDIDKeyResolver(t *testing.T) {
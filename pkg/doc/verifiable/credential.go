@@ -17,7 +17,9 @@ import (
 	"github.com/piprate/json-gold/ld"
 	"github.com/xeipuuv/gojsonschema"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
 
 //go:generate testdata/scripts/openssl_env.sh testdata/scripts/generate_test_keys.sh
@@ -29,22 +31,39 @@ const defaultSchema = `{
     "@context",
     "type",
     "credentialSubject",
-    "issuer",
-    "issuanceDate"
+    "issuer"
+  ],
+  "anyOf": [
+    {
+      "required": [
+        "issuanceDate"
+      ]
+    },
+    {
+      "required": [
+        "validFrom"
+      ]
+    }
   ],
   "properties": {
     "@context": {
       "oneOf": [
         {
           "type": "string",
-          "const": "https://www.w3.org/2018/credentials/v1"
+          "enum": [
+            "https://www.w3.org/2018/credentials/v1",
+            "https://www.w3.org/ns/credentials/v2"
+          ]
         },
         {
           "type": "array",
           "items": [
             {
               "type": "string",
-              "const": "https://www.w3.org/2018/credentials/v1"
+              "enum": [
+                "https://www.w3.org/2018/credentials/v1",
+                "https://www.w3.org/ns/credentials/v2"
+              ]
             }
           ],
           "uniqueItems": true,
@@ -145,6 +164,17 @@ const defaultSchema = `{
       ],
       "format": "date-time"
     },
+    "validFrom": {
+      "type": "string",
+      "format": "date-time"
+    },
+    "validUntil": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "date-time"
+    },
     "credentialStatus": {
       "$ref": "#/definitions/typedID"
     },
@@ -230,6 +260,9 @@ const (
 	// https://www.w3.org/TR/vc-data-model/#base-context
 	baseContext = "https://www.w3.org/2018/credentials/v1"
 
+	// https://www.w3.org/TR/vc-data-model-2.0/#base-context
+	baseContextV2 = "https://www.w3.org/ns/credentials/v2"
+
 	// https://www.w3.org/TR/vc-data-model/#types
 	vcType = "VerifiableCredential"
 
@@ -292,6 +325,7 @@ type cache interface {
 type ExpirableSchemaCache struct {
 	cache      cache
 	expiration time.Duration
+	clock      clock.Clock
 }
 
 // CredentialSchemaLoader defines expirable cache.
@@ -349,7 +383,7 @@ func (b *CredentialSchemaLoaderBuilder) Build() *CredentialSchemaLoader {
 
 // Put element to the cache. It also adds a mark of when the element will expire.
 func (sc *ExpirableSchemaCache) Put(k string, v []byte) {
-	expires := time.Now().Add(sc.expiration).Unix()
+	expires := sc.now().Add(sc.expiration).Unix()
 
 	const numBytesTime = 8
 
@@ -363,6 +397,15 @@ func (sc *ExpirableSchemaCache) Put(k string, v []byte) {
 	sc.cache.Set([]byte(k), ve)
 }
 
+// now returns the time the elements expire by, the real-time clock is used if not defined.
+func (sc *ExpirableSchemaCache) now() time.Time {
+	if sc.clock == nil {
+		return clock.Real().Now()
+	}
+
+	return sc.clock.Now()
+}
+
 // Get element from the cache. If element is present, it checks if the element is expired.
 // If yes, it clears the element from the cache and indicates that the key is not found.
 func (sc *ExpirableSchemaCache) Get(k string) ([]byte, bool) {
@@ -374,7 +417,7 @@ func (sc *ExpirableSchemaCache) Get(k string) ([]byte, bool) {
 	const numBytesTime = 8
 
 	expires := int64(binary.LittleEndian.Uint64(b[:numBytesTime]))
-	if expires < time.Now().Unix() {
+	if expires < sc.now().Unix() {
 		// cache expires
 		sc.cache.Del([]byte(k))
 		return nil, false
@@ -384,21 +427,66 @@ func (sc *ExpirableSchemaCache) Get(k string) ([]byte, bool) {
 }
 
 // Evidence defines evidence of Verifiable Credential
-type Evidence interface{}
+type Evidence map[string]interface{}
 
-// Issuer of the Verifiable Credential
+// Issuer of the Verifiable Credential.
+// It is marshalled into plain string (ID) if neither Name nor custom fields are defined,
+// otherwise it is marshalled into JSON object.
 type Issuer struct {
-	ID   string
-	Name string
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	CustomFields `json:"-"`
+}
+
+// MarshalJSON defines custom marshalling of Issuer to JSON.
+func (i Issuer) MarshalJSON() ([]byte, error) {
+	if i.Name == "" && len(i.CustomFields) == 0 {
+		return json.Marshal(i.ID)
+	}
+
+	type Alias Issuer
+
+	alias := Alias(i)
+
+	data, err := marshalWithCustomFields(alias, i.CustomFields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal Issuer: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON defines custom unmarshalling of Issuer from JSON.
+// Issuer could be defined either as a plain string (ID) or as an object with mandatory "id" field.
+func (i *Issuer) UnmarshalJSON(data []byte) error {
+	var v interface{}
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return fmt.Errorf("unmarshal Issuer: %w", err)
+	}
+
+	issuer, err := decodeIssuer(v)
+	if err != nil {
+		return fmt.Errorf("unmarshal Issuer: %w", err)
+	}
+
+	*i = issuer
+
+	return nil
 }
 
 // Subject of the Verifiable Credential
 type Subject interface{}
 
+// Contexts are the JSON-LD contexts of the Verifiable Credential in the order they are defined.
+// An entry is either the URI of the context (string) or the inline context definition (map[string]interface{}).
+type Contexts []interface{}
+
 // Credential Verifiable Credential definition
 type Credential struct {
-	Context        []string
-	CustomContext  []interface{}
+	Context        Contexts
 	ID             string
 	Types          []string
 	Subject        Subject
@@ -408,11 +496,34 @@ type Credential struct {
 	Proofs         []Proof
 	Status         *TypedID
 	Schemas        []TypedID
-	Evidence       *Evidence
+	Evidence       []Evidence
 	TermsOfUse     []TypedID
 	RefreshService []TypedID
 
 	CustomFields CustomFields
+
+	// original string representations of issuanceDate and expirationDate, or validFrom and validUntil
+	// of VC Data Model 2.0 (see WithPreserveRaw)
+	issuedRaw  string
+	expiredRaw string
+
+	// names of the validity period fields VC was decoded with
+	validityFields validityFields
+
+	// proof is decoded from an array (proof set), so it is serialized back as array even if it has single element
+	proofSet bool
+
+	// "cnf" claim of JWT credential bound to the holder key and the hash of the credential (see VerifyHolderBinding)
+	holderConfirmation   *Confirmation
+	holderCredentialHash string
+
+	// time source of the holder proof checks, the one the credential was decoded with (see WithClock),
+	// the real-time clock is used if not defined
+	clock clock.Clock
+
+	// JOSE header and claims of JWT the credential was decoded from (see JWTHeader and DecodedJWTClaims)
+	jwtHeader json.RawMessage
+	jwtClaims json.RawMessage
 }
 
 // rawCredential is a basic verifiable credential
@@ -421,13 +532,15 @@ type rawCredential struct {
 	ID             string          `json:"id,omitempty"`
 	Type           interface{}     `json:"type,omitempty"`
 	Subject        Subject         `json:"credentialSubject,omitempty"`
-	Issued         *time.Time      `json:"issuanceDate,omitempty"`
-	Expired        *time.Time      `json:"expirationDate,omitempty"`
+	Issued         *rawTime        `json:"issuanceDate,omitempty"`
+	Expired        *rawTime        `json:"expirationDate,omitempty"`
+	ValidFrom      *rawTime        `json:"validFrom,omitempty"`
+	ValidUntil     *rawTime        `json:"validUntil,omitempty"`
 	Proof          json.RawMessage `json:"proof,omitempty"`
 	Status         *TypedID        `json:"credentialStatus,omitempty"`
 	Issuer         interface{}     `json:"issuer,omitempty"`
 	Schema         interface{}     `json:"credentialSchema,omitempty"`
-	Evidence       *Evidence       `json:"evidence,omitempty"`
+	Evidence       json.RawMessage `json:"evidence,omitempty"`
 	TermsOfUse     json.RawMessage `json:"termsOfUse,omitempty"`
 	RefreshService json.RawMessage `json:"refreshService,omitempty"`
 
@@ -459,11 +572,6 @@ func (rc *rawCredential) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-type compositeIssuer struct {
-	ID   string `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
-}
-
 // CredentialDecoder makes a custom decoding of Verifiable Credential in JSON form to existent
 // instance of Credential.
 type CredentialDecoder func(dataJSON []byte, vc *Credential) error
@@ -482,7 +590,30 @@ type credentialOpts struct {
 	disabledProofCheck    bool
 	jsonldDocumentLoader  ld.DocumentLoader
 	strictValidation      bool
-	ldpSuite              verifierSignatureSuite
+	strictContext         bool
+	ldpSuites             []SignatureSuite
+	proofChallenge        string
+	proofDomain           string
+	preserveRaw           bool
+	issuerKeyBindingVDRI  vdri.Registry
+	allowedJWSAlgorithms  []JWSAlgorithm
+	clock                 clock.Clock
+	clockSet              bool
+	validityPeriodCheck   bool
+	failFast              bool
+	verificationCache     Cache
+	customValidators      []func(*Credential) error
+	proofDateConsistency  bool
+	remoteFetchPolicy     *remoteFetchPolicy
+	types                 typeOpts
+	requireID             bool
+	limits                limitOpts
+	allowDuplicateKeys    bool
+	proofThreshold        int
+	proofSigners          []string
+	subjectIDValidator    SubjectIDValidator
+	strictBaseContext     bool
+	candidateKeysFetcher  CandidateKeysFetcher
 }
 
 // CredentialOpt is the Verifiable Credential decoding option
@@ -570,10 +701,129 @@ func WithStrictValidation() CredentialOpt {
 	}
 }
 
-// WithEmbeddedSignatureSuites defines the suite which is used to check embedded linked data proof of VC.
-func WithEmbeddedSignatureSuites(suite verifierSignatureSuite) CredentialOpt {
+// WithStrictContextValidation defines whether the terms not defined in the JSON-LD contexts of VC are allowed
+// during JSON-LD validation. The option is independent of WithStrictValidation(): the terms are rejected
+// if any of them enables strict validation, i.e. WithStrictContextValidation(false) does not relax
+// the validation enabled by WithStrictValidation().
+//
+// In strict mode, the decoding fails if any field (root one or inside credentialSubject) is not defined
+// in any JSON-LD context.
+//
+// In lenient mode (the default one), such terms are tolerated: undefined root fields are put into
+// Credential.CustomFields (as any other field unknown to the data model) and undefined credentialSubject
+// fields are kept as is in Credential.Subject.
+func WithStrictContextValidation(strict bool) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.strictContext = strict
+	}
+}
+
+// WithPreserveRaw option keeps the original string representation of issuanceDate and expirationDate
+// of the decoded VC, so they are marshalled as is (e.g. with the original time zone offset).
+// Otherwise, the dates are normalized to UTC on marshalling. The original representation is dropped
+// once the date of VC is changed.
+func WithPreserveRaw() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.preserveRaw = true
+	}
+}
+
+// WithEmbeddedSignatureSuites defines the suites which are used to check embedded linked data proof of VC.
+// If no suites are defined, Ed25519Signature2018 suite is used.
+func WithEmbeddedSignatureSuites(suites ...SignatureSuite) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.ldpSuites = suites
+	}
+}
+
+// WithProofChallenge defines the expected challenge of the embedded proof of VC.
+// The check fails with ErrProofChallengeMismatch if the challenge of the proof is different.
+func WithProofChallenge(challenge string) CredentialOpt {
 	return func(opts *credentialOpts) {
-		opts.ldpSuite = suite
+		opts.proofChallenge = challenge
+	}
+}
+
+// WithProofDomain defines the expected domain of the embedded proof of VC.
+// The check fails with ErrProofDomainMismatch if the domain of the proof is different.
+func WithProofDomain(domain string) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.proofDomain = domain
+	}
+}
+
+// WithIssuerKeyBindingCheck enables the check that the key used for the proof of VC (JWS "kid" header or
+// "verificationMethod" of the embedded proof) belongs to the issuer, i.e. it is an assertion method key
+// of the issuer DID document resolved using the given VDRI registry. The check fails with ErrIssuerKeyMismatch
+// otherwise, or if VC has no proof.
+func WithIssuerKeyBindingCheck(vdriRegistry vdri.Registry) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.issuerKeyBindingVDRI = vdriRegistry
+	}
+}
+
+// WithAllowedJWSAlgorithms restricts the algorithms accepted for JWS of VC. Decoding of JWS signed
+// with any other algorithm fails with ErrDisallowedAlgorithm before the public key is fetched.
+// JWS with "none" algorithm is always rejected.
+func WithAllowedJWSAlgorithms(algs ...JWSAlgorithm) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.allowedJWSAlgorithms = algs
+	}
+}
+
+// WithClock sets the time source for the time-dependent checks of VC decoding (see WithValidityPeriodCheck)
+// and of the holder proof of the decoded VC (see VerifyHolderBinding). The real-time clock is used by default.
+func WithClock(c clock.Clock) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.clock = c
+		opts.clockSet = c != nil
+	}
+}
+
+// WithFailFast option makes decoding of VC fail on the first validation problem found.
+// By default, all structural problems are reported at once by *CredentialValidationError.
+func WithFailFast() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.failFast = true
+	}
+}
+
+// WithCustomValidator option adds the validator of domain rules (e.g. the format of the subject attributes)
+// which is run after the structural validation of VC. It can be used several times, all the validators are run
+// and their errors are reported together with the structural problems by *CredentialValidationError
+// (see WithFailFast to stop on the first problem).
+func WithCustomValidator(validator func(*Credential) error) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.customValidators = append(opts.customValidators, validator)
+	}
+}
+
+// WithVerificationCache option defines the cache of the credential proof checks. The credential which proof
+// was successfully checked is not checked again while it is present in the cache. The cache is keyed by
+// the hash of the canonical credential, of the key fetchers and signature suites used to check the proof
+// and of the proof threshold. Only the result of the proof check is cached, the credential status
+// (e.g. revocation) is not. The fetchers are identified by their function, so the closures of the same
+// function literal which fetch the keys of different trust domains should not share the cache.
+func WithVerificationCache(cache Cache) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.verificationCache = cache
+	}
+}
+
+// WithProofDateConsistency option enables the check that the embedded proofs of VC were created within its
+// validity window, i.e. "created" of the proof is not before issuanceDate and not after expirationDate.
+// ErrProofDateInconsistent is returned otherwise.
+func WithProofDateConsistency() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.proofDateConsistency = true
+	}
+}
+
+// WithRequireID option makes "id" of VC mandatory. ErrMissingID is returned if VC doesn't have it.
+// "id" is optional by default as defined by the VC data model.
+func WithRequireID() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.requireID = true
 	}
 }
 
@@ -583,7 +833,7 @@ func WithEmbeddedSignatureSuites(suite verifierSignatureSuite) CredentialOpt {
 //
 // - a string which is ID of the issuer;
 //
-// - object with mandatory "id" field and optional "name" field.
+// - object with mandatory "id" field and optional "name" field; other fields are kept in CustomFields.
 func decodeIssuer(issuer interface{}) (Issuer, error) {
 	getStringEntry := func(m map[string]interface{}, k string) (string, error) {
 		v, exists := m[k]
@@ -617,9 +867,24 @@ func decodeIssuer(issuer interface{}) (Issuer, error) {
 			return Issuer{}, err
 		}
 
+		var customFields CustomFields
+
+		for k, v := range iss {
+			if k == "id" || k == "name" {
+				continue
+			}
+
+			if customFields == nil {
+				customFields = make(CustomFields)
+			}
+
+			customFields[k] = v
+		}
+
 		return Issuer{
-			ID:   id,
-			Name: name,
+			ID:           id,
+			Name:         name,
+			CustomFields: customFields,
 		}, nil
 	default:
 		return Issuer{}, errors.New("unsupported format of issuer")
@@ -663,40 +928,162 @@ func decodeCredentialSchemas(data *rawCredential) ([]TypedID, error) {
 // It returns decoded Credential and its marshalled JSON.
 // For JSON bytes input, the output marshalled JSON is the same value.
 // For serialized JWT input, the output is the result of decoding `vc` claim from JWT.
+// The input could also be wrapped into data URI (e.g. "data:application/vc+jwt;base64,...") or base64 encoding.
+// The input exceeding the size or nesting depth limits (see WithMaxSize and WithMaxDepth) is rejected before parsing.
+// The input with duplicate JSON keys is rejected unless WithDuplicateKeys option is passed.
 // The output Credential and marshalled JSON can be used for extensions of the base data model
 // by checking CustomFields of Credential and/or unmarshalling the JSON to custom date structure.
 func NewCredential(vcData []byte, opts ...CredentialOpt) (*Credential, []byte, error) {
 	// Apply options.
 	vcOpts := parseCredentialOpts(opts)
 
-	// Decode credential (e.g. from JWT).
-	vcDataDecoded, err := decodeRaw(vcData, vcOpts)
+	vcData, vcDataDecoded, err := decodeNewCredentialData(vcData, vcOpts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("decode new credential: %w", err)
 	}
 
-	// Unmarshal raw credential from JSON.
-	var raw rawCredential
-	err = json.Unmarshal(vcDataDecoded, &raw)
-
+	vc, err := decodeCredential(vcDataDecoded)
 	if err != nil {
-		return nil, nil, fmt.Errorf("unmarshal new credential: %w", err)
+		return nil, nil, withStructureErrors(err, vcDataDecoded, vcOpts)
 	}
 
-	// Create credential from raw.
-	vc, err := newCredential(&raw)
+	if err = checkDecodedCredential(vc, vcOpts); err != nil {
+		return nil, nil, fmt.Errorf("decode new credential: %w", err)
+	}
+
+	if !vcOpts.preserveRaw {
+		vc.issuedRaw, vc.expiredRaw = "", ""
+	}
+
+	err = joinCustomValidationErrors(vc, validateCredential(vc, vcDataDecoded, vcOpts), vcOpts)
 	if err != nil {
-		return nil, nil, fmt.Errorf("build new credential: %w", err)
+		return nil, nil, err
 	}
 
-	err = validateCredential(vc, vcDataDecoded, vcOpts)
+	err = checkIssuerKeyBinding(vc, vcData, vcOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	err = checkProofDateConsistency(vc, vcOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode new credential: %w", err)
+	}
+
+	err = decodeHolderBinding(vc, vcData, vcOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode new credential: holder binding: %w", err)
+	}
+
+	if vcOpts.clockSet {
+		vc.clock = vcOpts.clock
+	}
+
+	vc.jwtHeader, vc.jwtClaims, err = decodeJWTSource(vcData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode new credential: %w", err)
+	}
+
 	return vc, vcDataDecoded, nil
 }
 
+// checkDecodedCredential checks the constraints of VC which are not covered by the validation of its model.
+func checkDecodedCredential(vc *Credential, vcOpts *credentialOpts) error {
+	if vcOpts.requireID && vc.ID == "" {
+		return ErrMissingID
+	}
+
+	if vcOpts.strictBaseContext {
+		if err := checkBaseContext(vc.Context); err != nil {
+			return err
+		}
+	}
+
+	if vcOpts.validityPeriodCheck {
+		if err := checkValidityPeriod(vc, vcOpts.clock.Now()); err != nil {
+			return err
+		}
+	}
+
+	return validateSubjectIDs(vc.Subject, vcOpts.subjectIDValidator)
+}
+
+// decodeNewCredentialData unwraps the credential data and decodes it (e.g. from JWT) checking its limits.
+// Both unwrapped and decoded data are returned.
+func decodeNewCredentialData(vcData []byte, vcOpts *credentialOpts) ([]byte, []byte, error) {
+	if err := vcOpts.limits.checkSize(vcData); err != nil {
+		return nil, nil, err
+	}
+
+	// Unwrap credential delivered as data URI or base64.
+	vcData, err := unwrapData(vcData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = vcOpts.limits.checkDepth(vcData); err != nil {
+		return nil, nil, err
+	}
+
+	if err = vcOpts.limits.checkJWTDepth(vcData); err != nil {
+		return nil, nil, err
+	}
+
+	if !vcOpts.allowDuplicateKeys {
+		if err = checkDuplicateKeys(vcData); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Decode credential (e.g. from JWT).
+	vcDataDecoded, err := decodeRaw(vcData, vcOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = vcOpts.limits.checkDepth(vcDataDecoded); err != nil {
+		return nil, nil, err
+	}
+
+	vcDataDecoded, err = processTypes(vcDataDecoded, vcType, vcOpts.types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vcData, vcDataDecoded, nil
+}
+
+// decodeCredential unmarshals raw credential from JSON and creates credential from it.
+func decodeCredential(vcBytes []byte) (*Credential, error) {
+	var raw rawCredential
+
+	if err := json.Unmarshal(vcBytes, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal new credential: %w", err)
+	}
+
+	vc, err := newCredential(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("build new credential: %w", err)
+	}
+
+	return vc, nil
+}
+
+// withStructureErrors complements the decoding error with the problems found by validation against
+// the default JSON schema, so that all structural problems of the credential are reported at once.
+func withStructureErrors(decodeErr error, vcBytes []byte, vcOpts *credentialOpts) error {
+	if vcOpts.failFast || vcOpts.modelValidationMode == jsonldValidation {
+		return decodeErr
+	}
+
+	schemaErr := validateCredentialUsingJSONSchema(vcBytes, nil, &credentialOpts{disabledCustomSchema: true})
+	if schemaErr == nil {
+		return decodeErr
+	}
+
+	return joinValidationErrors(decodeErr, schemaErr)
+}
+
 func validateCredential(vc *Credential, vcBytes []byte, vcOpts *credentialOpts) error {
 	// Credential and type constraint.
 	switch vcOpts.modelValidationMode {
@@ -710,7 +1097,7 @@ func validateCredential(vc *Credential, vcBytes []byte, vcOpts *credentialOpts)
 			return err
 		}
 
-		if len(vc.Context) > 1 {
+		if len(vc.ContextURIs()) > 1 {
 			return vc.validateJSONLD(vcOpts)
 		}
 
@@ -730,32 +1117,66 @@ func validateCredential(vc *Credential, vcBytes []byte, vcOpts *credentialOpts)
 	}
 }
 
+// joinCustomValidationErrors runs the custom validators (see WithCustomValidator) and joins their errors
+// with the error of the structural validation (if any).
+func joinCustomValidationErrors(vc *Credential, validationErr error, vcOpts *credentialOpts) error {
+	if validationErr != nil && vcOpts.failFast {
+		return validationErr
+	}
+
+	errs := []error{validationErr}
+
+	for _, validate := range vcOpts.customValidators {
+		if err := validate(vc); err != nil {
+			if vcOpts.failFast {
+				return fmt.Errorf("custom validation: %w", err)
+			}
+
+			errs = append(errs, fmt.Errorf("custom validation: %w", err))
+		}
+	}
+
+	return joinValidationErrors(errs...)
+}
+
 func validateBaseContext(vc *Credential, vcBytes []byte, vcOpts *credentialOpts) error {
+	var errs []error
+
 	if len(vc.Types) > 1 || vc.Types[0] != vcType {
-		return errors.New("violated type constraint: not base only type defined")
+		errs = append(errs, errors.New("violated type constraint: not base only type defined"))
+	}
+
+	if len(vc.Context) > 1 || !isBaseContext(vc.Context[0]) {
+		errs = append(errs, errors.New("violated @context constraint: not base only @context defined"))
 	}
 
-	if len(vc.Context) > 1 || vc.Context[0] != baseContext {
-		return errors.New("violated @context constraint: not base only @context defined")
+	if len(errs) > 0 && vcOpts.failFast {
+		return errs[0]
 	}
 
-	return vc.validateJSONSchema(vcBytes, vcOpts)
+	return joinValidationErrors(append(errs, vc.validateJSONSchema(vcBytes, vcOpts))...)
 }
 
 func validateBaseContextWithExtendedValidation(vc *Credential, vcOpts *credentialOpts, vcBytes []byte) error {
-	for _, vcContext := range vc.Context {
-		if _, ok := vcOpts.allowedCustomContexts[vcContext]; !ok {
-			return fmt.Errorf("not allowed @context: %s", vcContext)
+	var errs []error
+
+	for _, vcContext := range vc.ContextURIs() {
+		if _, ok := vcOpts.allowedCustomContexts[vcContext]; !ok && vcContext != baseContextV2 {
+			errs = append(errs, fmt.Errorf("not allowed @context: %s", vcContext))
 		}
 	}
 
 	for _, vcType := range vc.Types {
 		if _, ok := vcOpts.allowedCustomTypes[vcType]; !ok {
-			return fmt.Errorf("not allowed type: %s", vcType)
+			errs = append(errs, fmt.Errorf("not allowed type: %s", vcType))
 		}
 	}
 
-	return vc.validateJSONSchema(vcBytes, vcOpts)
+	if len(errs) > 0 && vcOpts.failFast {
+		return errs[0]
+	}
+
+	return joinValidationErrors(append(errs, vc.validateJSONSchema(vcBytes, vcOpts))...)
 }
 
 func (vc *Credential) validateJSONLD(vcOpts *credentialOpts) error {
@@ -764,7 +1185,15 @@ func (vc *Credential) validateJSONLD(vcOpts *credentialOpts) error {
 		return err
 	}
 
-	return compactJSONLD(string(vcJSON), vcOpts.jsonldDocumentLoader, vcOpts.strictValidation)
+	if vcOpts.remoteFetchPolicy != nil {
+		// JSON-LD processor does not expose the errors of the document loader, so the hosts are checked in advance
+		if err = vcOpts.remoteFetchPolicy.checkContexts(vc.Context, vcOpts.jsonldDocumentLoader); err != nil {
+			return err
+		}
+	}
+
+	return compactJSONLD(string(vcJSON), vcOpts.jsonldDocumentLoader,
+		vcOpts.strictValidation || vcOpts.strictContext)
 }
 
 // CustomCredentialProducer is a factory for Credentials with extended data model.
@@ -828,7 +1257,7 @@ func newCredential(raw *rawCredential) (*Credential, error) {
 		return nil, fmt.Errorf("fill credential issuer from raw: %w", err)
 	}
 
-	context, customContext, err := decodeContext(raw.Context)
+	context, err := decodeContexts(raw.Context)
 	if err != nil {
 		return nil, fmt.Errorf("fill credential context from raw: %w", err)
 	}
@@ -843,27 +1272,40 @@ func newCredential(raw *rawCredential) (*Credential, error) {
 		return nil, fmt.Errorf("fill credential refresh service from raw: %w", err)
 	}
 
+	evidence, err := decodeEvidence(raw.Evidence)
+	if err != nil {
+		return nil, fmt.Errorf("fill credential evidence from raw: %w", err)
+	}
+
 	proofs, err := decodeProof(raw.Proof)
 	if err != nil {
 		return nil, fmt.Errorf("fill credential proof from raw: %w", err)
 	}
 
+	issued, expired, fields, err := raw.validityPeriod()
+	if err != nil {
+		return nil, fmt.Errorf("fill credential validity period from raw: %w", err)
+	}
+
 	return &Credential{
 		Context:        context,
-		CustomContext:  customContext,
 		ID:             raw.ID,
 		Types:          types,
 		Subject:        raw.Subject,
 		Issuer:         issuer,
-		Issued:         raw.Issued,
-		Expired:        raw.Expired,
+		Issued:         issued.timePtr(),
+		Expired:        expired.timePtr(),
 		Proofs:         proofs,
 		Status:         raw.Status,
 		Schemas:        schemas,
-		Evidence:       raw.Evidence,
+		Evidence:       evidence,
 		TermsOfUse:     termsOfUse,
 		RefreshService: refreshService,
 		CustomFields:   raw.CustomFields,
+		issuedRaw:      issued.rawString(),
+		expiredRaw:     expired.rawString(),
+		validityFields: fields,
+		proofSet:       isProofSet(raw.Proof),
 	}, nil
 }
 
@@ -889,13 +1331,43 @@ func decodeTypedID(bytes json.RawMessage) ([]TypedID, error) {
 	return nil, err
 }
 
+func decodeEvidence(evidenceBytes json.RawMessage) ([]Evidence, error) {
+	if len(evidenceBytes) == 0 {
+		return nil, nil
+	}
+
+	var singleEvidence Evidence
+
+	err := json.Unmarshal(evidenceBytes, &singleEvidence)
+	if err == nil {
+		if singleEvidence == nil { // evidence is null
+			return nil, nil
+		}
+
+		return []Evidence{singleEvidence}, nil
+	}
+
+	var composedEvidence []Evidence
+
+	err = json.Unmarshal(evidenceBytes, &composedEvidence)
+	if err == nil {
+		return composedEvidence, nil
+	}
+
+	return nil, err
+}
+
 func decodeRaw(vcData []byte, vcOpts *credentialOpts) ([]byte, error) {
 	if isJWS(vcData) { // External proof, is checked by JWS.
+		if err := checkJWSAlgorithm(vcData, vcOpts.allowedJWSAlgorithms); err != nil {
+			return nil, fmt.Errorf("JWS decoding: %w", err)
+		}
+
 		if vcOpts.publicKeyFetcher == nil {
 			return nil, errors.New("public key fetcher is not defined")
 		}
 
-		vcDecodedBytes, err := decodeCredJWS(vcData, !vcOpts.disabledProofCheck, vcOpts.publicKeyFetcher)
+		vcDecodedBytes, err := decodeCachedCredJWS(vcData, vcOpts)
 		if err != nil {
 			return nil, fmt.Errorf("JWS decoding: %w", err)
 		}
@@ -904,6 +1376,10 @@ func decodeRaw(vcData []byte, vcOpts *credentialOpts) ([]byte, error) {
 	}
 
 	if isJWTUnsecured(vcData) { // Embedded proof.
+		if err := checkUnsecuredJWTAlgorithm(vcOpts.allowedJWSAlgorithms); err != nil {
+			return nil, fmt.Errorf("unsecured JWT decoding: %w", err)
+		}
+
 		vcDecodedBytes, err := decodeCredJWTUnsecured(vcData)
 		if err != nil {
 			return nil, fmt.Errorf("unsecured JWT decoding: %w", err)
@@ -916,9 +1392,37 @@ func decodeRaw(vcData []byte, vcOpts *credentialOpts) ([]byte, error) {
 	return checkEmbeddedProof(vcData, vcOpts)
 }
 
+// decodeCachedCredJWS decodes JWS credential, its signature is not checked again if the credential
+// is present in the verification cache.
+func decodeCachedCredJWS(vcData []byte, vcOpts *credentialOpts) ([]byte, error) {
+	if vcOpts.disabledProofCheck {
+		return decodeCredJWS(vcData, false, vcOpts.publicKeyFetcher)
+	}
+
+	var vcDecodedBytes []byte
+
+	err := checkCachedProof(vcData, vcOpts, func(opts *credentialOpts) error {
+		var decodeErr error
+
+		vcDecodedBytes, decodeErr = decodeCredJWS(vcData, true, opts.publicKeyFetcher)
+
+		return decodeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if vcDecodedBytes == nil { // the signature was checked before
+		return decodeCredJWS(vcData, false, vcOpts.publicKeyFetcher)
+	}
+
+	return vcDecodedBytes, nil
+}
+
 func parseCredentialOpts(opts []CredentialOpt) *credentialOpts {
 	crOpts := &credentialOpts{
 		modelValidationMode: combinedValidation,
+		limits:              limitOpts{maxSize: defaultMaxSize, maxDepth: defaultMaxDepth},
 	}
 
 	for _, opt := range opts {
@@ -930,7 +1434,15 @@ func parseCredentialOpts(opts []CredentialOpt) *credentialOpts {
 	}
 
 	if crOpts.jsonldDocumentLoader == nil {
-		crOpts.jsonldDocumentLoader = CachingJSONLDLoader()
+		if crOpts.remoteFetchPolicy != nil {
+			crOpts.jsonldDocumentLoader = newCachingJSONLDLoader(crOpts.remoteFetchPolicy)
+		} else {
+			crOpts.jsonldDocumentLoader = CachingJSONLDLoader()
+		}
+	}
+
+	if crOpts.clock == nil {
+		crOpts.clock = clock.Real()
 	}
 
 	return crOpts
@@ -944,8 +1456,8 @@ func newDefaultSchemaLoader() *CredentialSchemaLoader {
 }
 
 func issuerToRaw(issuer Issuer) interface{} {
-	if issuer.Name != "" {
-		return &compositeIssuer{ID: issuer.ID, Name: issuer.Name}
+	if issuer.Name != "" || len(issuer.CustomFields) > 0 {
+		return issuer
 	}
 
 	return issuer.ID
@@ -971,8 +1483,13 @@ func validateCredentialUsingJSONSchema(data []byte, schemas []TypedID, opts *cre
 	}
 
 	if !result.Valid() {
-		errMsg := describeSchemaValidationError(result, "verifiable credential")
-		return errors.New(errMsg)
+		errs := make([]error, len(result.Errors()))
+
+		for i, desc := range result.Errors() {
+			errs[i] = errors.New(desc.String())
+		}
+
+		return &CredentialValidationError{errs: errs}
 	}
 
 	return nil
@@ -1009,16 +1526,24 @@ func getJSONSchema(url string, opts *credentialOpts) ([]byte, error) {
 	loader := opts.schemaLoader
 	cache := loader.cache
 
-	if cache == nil {
+	load := func(url string) ([]byte, error) {
+		if opts.remoteFetchPolicy != nil {
+			return opts.remoteFetchPolicy.fetch(url)
+		}
+
 		return loadJSONSchema(url, loader.schemaDownloadClient)
 	}
 
+	if cache == nil {
+		return load(url)
+	}
+
 	// Check the cache first.
 	if cachedBytes, ok := cache.Get(url); ok {
 		return cachedBytes, nil
 	}
 
-	schemaBytes, err := loadJSONSchema(url, loader.schemaDownloadClient)
+	schemaBytes, err := load(url)
 	if err != nil {
 		return nil, err
 	}
@@ -1057,9 +1582,9 @@ func loadJSONSchema(url string, client *http.Client) ([]byte, error) {
 }
 
 // JWTClaims converts Verifiable Credential into JWT Credential claims, which can be than serialized
-// e.g. into JWS.
-func (vc *Credential) JWTClaims(minimizeVC bool) (*JWTCredClaims, error) {
-	return newJWTCredClaims(vc, minimizeVC)
+// e.g. into JWS. The credential can be bound to the holder key (see WithHolderKey).
+func (vc *Credential) JWTClaims(minimizeVC bool, opts ...JWTClaimsOpt) (*JWTCredClaims, error) {
+	return newJWTCredClaims(vc, minimizeVC, opts...)
 }
 
 // subjectID gets ID of single subject if present or
@@ -1117,27 +1642,34 @@ func (vc *Credential) raw() (*rawCredential, error) {
 		return nil, err
 	}
 
-	proof, err := proofsToRaw(vc.Proofs)
+	rawEvidence, err := evidenceToRaw(vc.Evidence)
 	if err != nil {
 		return nil, err
 	}
 
-	return &rawCredential{
-		Context:        contextToRaw(vc.Context, vc.CustomContext),
+	proof, err := proofsToRaw(vc.Proofs, vc.proofSet)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &rawCredential{
+		Context:        vc.Context,
 		ID:             vc.ID,
 		Type:           typesToRaw(vc.Types),
 		Subject:        vc.Subject,
-		Issued:         vc.Issued,
-		Expired:        vc.Expired,
 		Proof:          proof,
 		Status:         vc.Status,
 		Issuer:         issuerToRaw(vc.Issuer),
 		Schema:         vc.Schemas,
-		Evidence:       vc.Evidence,
+		Evidence:       rawEvidence,
 		RefreshService: rawRefreshService,
 		TermsOfUse:     rawTermsOfUse,
 		CustomFields:   vc.CustomFields,
-	}, nil
+	}
+
+	raw.setValidityPeriod(vc)
+
+	return raw, nil
 }
 
 func typesToRaw(types []string) interface{} {
@@ -1149,22 +1681,6 @@ func typesToRaw(types []string) interface{} {
 	return types
 }
 
-func contextToRaw(context []string, cContext []interface{}) interface{} {
-	if len(cContext) > 0 {
-		// return as array
-		sContext := make([]interface{}, len(context), len(context)+len(cContext))
-		for i := range context {
-			sContext[i] = context[i]
-		}
-
-		sContext = append(sContext, cContext...)
-
-		return sContext
-	}
-
-	return context
-}
-
 func typedIDsToRaw(typedIDs []TypedID) ([]byte, error) {
 	switch len(typedIDs) {
 	case 0:
@@ -1176,6 +1692,17 @@ func typedIDsToRaw(typedIDs []TypedID) ([]byte, error) {
 	}
 }
 
+func evidenceToRaw(evidence []Evidence) ([]byte, error) {
+	switch len(evidence) {
+	case 0:
+		return nil, nil
+	case 1:
+		return json.Marshal(evidence[0])
+	default:
+		return json.Marshal(evidence)
+	}
+}
+
 // MarshalJSON converts Verifiable Credential to JSON bytes
 func (vc *Credential) MarshalJSON() ([]byte, error) {
 	raw, err := vc.raw()
@@ -1191,13 +1718,181 @@ func (vc *Credential) MarshalJSON() ([]byte, error) {
 	return byteCred, nil
 }
 
+// MarshalJSONCanonical converts Verifiable Credential to JSON bytes with lexicographically sorted keys
+// at every level (including custom fields and credential subject). The output is stable, so it can be
+// used for hashing of the credential (e.g. for deduplication or content addressing).
+func (vc *Credential) MarshalJSONCanonical() ([]byte, error) {
+	byteCred, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	canonicalCred, err := canonicalJSON(byteCred)
+	if err != nil {
+		return nil, fmt.Errorf("canonical JSON marshalling of verifiable credential: %w", err)
+	}
+
+	return canonicalCred, nil
+}
+
+// WithUpdates applies the mutation fn to a copy of the credential, re-validates the updated copy
+// and returns it. The original credential is left untouched.
+// The options are applied to validation of the updated credential (e.g. WithNoCustomSchemaCheck);
+// embedded proofs are not checked.
+func (vc *Credential) WithUpdates(fn func(*Credential) error, opts ...CredentialOpt) (*Credential, error) {
+	vcCopy, err := vc.copy()
+	if err != nil {
+		return nil, fmt.Errorf("update credential: %w", err)
+	}
+
+	if err = fn(vcCopy); err != nil {
+		return nil, fmt.Errorf("update credential: %w", err)
+	}
+
+	vcBytes, err := vcCopy.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("update credential: %w", err)
+	}
+
+	if err = validateCredential(vcCopy, vcBytes, parseCredentialOpts(opts)); err != nil {
+		return nil, fmt.Errorf("update credential: %w", err)
+	}
+
+	return vcCopy, nil
+}
+
+// copy makes a deep copy of the credential.
+func (vc *Credential) copy() (*Credential, error) {
+	vcBytes, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw rawCredential
+
+	if err = json.Unmarshal(vcBytes, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal credential copy: %w", err)
+	}
+
+	return newCredential(&raw)
+}
+
+// ErrSubjectNotCredential is returned when the credential subject is not a verifiable credential.
+var ErrSubjectNotCredential = errors.New("credential subject is not a verifiable credential")
+
+// SubjectCredential parses the credential subject as a verifiable credential. It is used for chained
+// credentials, i.e. when the issuer of the credential attests to another (inner) credential.
+// The subject can be either embedded JSON credential or JWT one.
+// The options are applied to decoding of the inner credential (e.g. public key fetcher to check its proof).
+// ErrSubjectNotCredential is returned if the subject is not a verifiable credential.
+func (vc *Credential) SubjectCredential(opts ...CredentialOpt) (*Credential, error) {
+	subject, err := singleSubject(vc.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("subject credential: %w", err)
+	}
+
+	var vcBytes []byte
+
+	switch s := subject.(type) {
+	case string:
+		if !isJWS([]byte(s)) && !isJWTUnsecured([]byte(s)) {
+			return nil, ErrSubjectNotCredential
+		}
+
+		vcBytes = []byte(s)
+
+	default:
+		sMap, err := toMap(s)
+		if err != nil || !isCredentialMap(sMap) {
+			return nil, ErrSubjectNotCredential
+		}
+
+		vcBytes, err = json.Marshal(sMap)
+		if err != nil {
+			return nil, fmt.Errorf("subject credential: %w", err)
+		}
+	}
+
+	subjectVC, _, err := NewCredential(vcBytes, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("subject credential: %w", err)
+	}
+
+	return subjectVC, nil
+}
+
+// singleSubject returns the only subject of the credential or error if there are several subjects or none.
+func singleSubject(subject Subject) (interface{}, error) {
+	switch s := subject.(type) {
+	case nil:
+		return nil, errors.New("no subject is defined")
+
+	case []interface{}:
+		if len(s) != 1 {
+			return nil, errors.New("exactly one subject must be defined")
+		}
+
+		return s[0], nil
+
+	case []map[string]interface{}:
+		if len(s) != 1 {
+			return nil, errors.New("exactly one subject must be defined")
+		}
+
+		return s[0], nil
+
+	default:
+		return s, nil
+	}
+}
+
+// isCredentialMap checks whether the JSON object looks like a verifiable credential.
+func isCredentialMap(m map[string]interface{}) bool {
+	if _, ok := m["credentialSubject"]; !ok {
+		return false
+	}
+
+	types, err := decodeType(m["type"])
+	if err != nil {
+		return false
+	}
+
+	for _, t := range types {
+		if t == vcType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContextURIs returns the URIs of the JSON-LD contexts of the credential, i.e. the inline contexts are skipped.
+func (vc *Credential) ContextURIs() []string {
+	uris := make([]string, 0, len(vc.Context))
+
+	for _, c := range vc.Context {
+		if uri, ok := c.(string); ok {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
+
 // Presentation encloses credential into presentation.
 func (vc *Credential) Presentation() (*Presentation, error) {
 	vp := Presentation{
-		Context: vc.Context,
+		Context: vc.ContextURIs(),
 		Type:    []string{vpType},
 	}
 
+	// the inline contexts follow the URIs of the contexts in the presentation
+	for _, c := range vc.Context {
+		if _, ok := c.(string); !ok {
+			vp.CustomContext = append(vp.CustomContext, c)
+		}
+	}
+
 	err := vp.SetCredentials(vc)
 	if err != nil {
 		return nil, fmt.Errorf("build presentation from credential: %w", err)
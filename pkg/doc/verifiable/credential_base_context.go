@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBaseContextNotFirst is returned when the first "@context" entry of VC is not the base VC context
+// as required by WithStrictBaseContext option.
+var ErrBaseContextNotFirst = errors.New("base context is not the first context of credential")
+
+// WithStrictBaseContext option requires the first "@context" entry of VC to be the base VC context
+// (https://www.w3.org/2018/credentials/v1 or https://www.w3.org/ns/credentials/v2 of VC Data Model 2.0)
+// as defined by the VC data model, ErrBaseContextNotFirst is returned otherwise. The base context is accepted
+// in any position by default.
+func WithStrictBaseContext() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.strictBaseContext = true
+	}
+}
+
+// EnsureBaseContext places the base VC context first in the contexts of VC, e.g. before the issuer signs it.
+// The base context (of VC Data Model 1.1 unless VC has the one of 2.0) is added if VC doesn't have it,
+// the order of the other contexts is kept.
+func (vc *Credential) EnsureBaseContext() {
+	base := baseContext
+	if hasV2Context(vc.Context) {
+		base = baseContextV2
+	}
+
+	context := Contexts{base}
+
+	for _, c := range vc.Context {
+		if c != base {
+			context = append(context, c)
+		}
+	}
+
+	vc.Context = context
+}
+
+// checkBaseContext checks that the base VC context is the first one.
+func checkBaseContext(context Contexts) error {
+	if len(context) == 0 || !isBaseContext(context[0]) {
+		var first interface{}
+		if len(context) > 0 {
+			first = context[0]
+		}
+
+		return fmt.Errorf("%w: first context is %v", ErrBaseContextNotFirst, first)
+	}
+
+	return nil
+}
+
+// isBaseContext checks whether the context is the base VC context of VC Data Model 1.1 or 2.0.
+func isBaseContext(context interface{}) bool {
+	return context == baseContext || context == baseContextV2
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStrictBaseContext(t *testing.T) {
+	vcWithContext := func(t *testing.T, context interface{}) []byte {
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+		vcMap["@context"] = context
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	t.Run("base context is first", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential), WithStrictBaseContext())
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcWithContext(t, baseContext), WithStrictBaseContext())
+		require.NoError(t, err)
+	})
+
+	t.Run("base context is not first", func(t *testing.T) {
+		vcBytes := vcWithContext(t, []interface{}{"https://www.w3.org/2018/credentials/examples/v1", baseContext})
+
+		_, _, err := NewCredential(vcBytes, WithStrictBaseContext())
+		require.True(t, errors.Is(err, ErrBaseContextNotFirst))
+		require.Contains(t, err.Error(), "first context is https://www.w3.org/2018/credentials/examples/v1")
+
+		// the order is not checked by default
+		vcBytes = vcWithContext(t, []interface{}{map[string]interface{}{"name": "http://schema.org/name"}, baseContext})
+
+		_, _, err = NewCredential(vcBytes, WithJSONLDValidation(), WithJSONLDDocumentLoader(CachingJSONLDLoader()))
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithStrictBaseContext())
+		require.True(t, errors.Is(err, ErrBaseContextNotFirst))
+	})
+
+	t.Run("base context is absent", func(t *testing.T) {
+		vcBytes := vcWithContext(t, []interface{}{map[string]interface{}{"name": "http://schema.org/name"}})
+
+		_, _, err := NewCredential(vcBytes, WithStrictBaseContext())
+		require.True(t, errors.Is(err, ErrBaseContextNotFirst))
+	})
+}
+
+func TestCredential_EnsureBaseContext(t *testing.T) {
+	customContext := map[string]interface{}{"name": "http://schema.org/name"}
+
+	vc := &Credential{Context: Contexts{"https://www.w3.org/2018/credentials/examples/v1", baseContext, customContext}}
+	vc.EnsureBaseContext()
+	require.Equal(t, Contexts{baseContext, "https://www.w3.org/2018/credentials/examples/v1", customContext},
+		vc.Context)
+	require.NoError(t, checkBaseContext(vc.Context))
+
+	vc = &Credential{}
+	vc.EnsureBaseContext()
+	require.Equal(t, Contexts{baseContext}, vc.Context)
+
+	require.True(t, errors.Is(checkBaseContext(nil), ErrBaseContextNotFirst))
+}
@@ -0,0 +1,381 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/piprate/json-gold/ld"
+)
+
+const (
+	// compactCBORFormat identifies the compact CBOR format, the document is encoded as CBOR array
+	// of the format identifier and the compressed credential. The format is specific to aries-framework-go,
+	// it is neither CBOR-LD nor tagged by the CBOR tags registered for CBOR-LD.
+	compactCBORFormat = "aries-framework-go/compact-vc/v1"
+
+	// compactCBORFirstTermID is the ID of the first term defined by the contexts, the lower IDs are reserved
+	// for JSON-LD keywords
+	compactCBORFirstTermID = 100
+
+	jsonldContext = "@context"
+	jsonldType    = "@type"
+)
+
+// ErrNotCompactCBOR is returned when the data decoded as compact CBOR credential is not compact CBOR document.
+var ErrNotCompactCBOR = errors.New("data is not compressed compact CBOR document")
+
+// IDs of JSON-LD keywords in compact CBOR document
+// nolint:gochecknoglobals
+var compactCBORKeywords = map[string]uint64{
+	"@context":   0,
+	"@type":      2,
+	"@id":        4,
+	"@value":     6,
+	"@direction": 8,
+	"@graph":     10,
+	"@included":  12,
+	"@index":     14,
+	"@json":      16,
+	"@language":  18,
+	"@list":      20,
+	"@nest":      22,
+	"@reverse":   24,
+}
+
+// IDs of the well-known contexts in compact CBOR document, the other contexts are kept as is
+// nolint:gochecknoglobals
+var compactCBORContexts = map[string]uint64{
+	"https://www.w3.org/2018/credentials/v1":           0x11,
+	"https://www.w3.org/ns/did/v1":                     0x12,
+	"https://w3id.org/security/suites/ed25519-2018/v1": 0x13,
+	"https://w3id.org/security/suites/ed25519-2020/v1": 0x14,
+}
+
+// MarshalCompactCBOR serializes the credential into compact CBOR for the constrained transports (e.g. QR code).
+// The terms defined by the contexts of VC and the well-known contexts are replaced by their integer IDs,
+// the contexts are loaded by JSON-LD document loader (see WithJSONLDDocumentLoader).
+// The other options are ignored. Use NewCredentialFromCompactCBOR to decode the credential.
+// Note that the format is specific to aries-framework-go and is not interoperable with CBOR-LD.
+func (vc *Credential) MarshalCompactCBOR(opts ...CredentialOpt) ([]byte, error) {
+	vcBytes, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal compact CBOR credential: %w", err)
+	}
+
+	var vcMap map[string]interface{}
+
+	if err = json.Unmarshal(vcBytes, &vcMap); err != nil {
+		return nil, fmt.Errorf("marshal compact CBOR credential: %w", err)
+	}
+
+	codec, err := newCompactCBORCodec(vcMap[jsonldContext], parseCredentialOpts(opts).jsonldDocumentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("marshal compact CBOR credential: %w", err)
+	}
+
+	encMode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, fmt.Errorf("marshal compact CBOR credential: %w", err)
+	}
+
+	compact, err := encMode.Marshal([]interface{}{compactCBORFormat, codec.compress("", vcMap)})
+	if err != nil {
+		return nil, fmt.Errorf("marshal compact CBOR credential: %w", err)
+	}
+
+	return compact, nil
+}
+
+// NewCredentialFromCompactCBOR decodes the credential serialized into compact CBOR (see MarshalCompactCBOR).
+// The contexts of VC are loaded by JSON-LD document loader (see WithJSONLDDocumentLoader) to expand the terms,
+// then VC is decoded from its JSON form with the given options (see NewCredential).
+func NewCredentialFromCompactCBOR(data []byte, opts ...CredentialOpt) (*Credential, []byte, error) {
+	var decoded interface{}
+
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", err)
+	}
+
+	envelope, ok := decoded.([]interface{})
+	if !ok || len(envelope) != 2 || envelope[0] != compactCBORFormat {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", ErrNotCompactCBOR)
+	}
+
+	content, ok := envelope[1].(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", ErrNotCompactCBOR)
+	}
+
+	contexts, err := (&compactCBORCodec{}).decompress(jsonldContext, content[compactCBORKeywords[jsonldContext]])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", err)
+	}
+
+	codec, err := newCompactCBORCodec(contexts, parseCredentialOpts(opts).jsonldDocumentLoader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", err)
+	}
+
+	vcMap, err := codec.decompress("", content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", err)
+	}
+
+	vcBytes, err := json.Marshal(vcMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode compact CBOR credential: %w", err)
+	}
+
+	return NewCredential(vcBytes, opts...)
+}
+
+// compactCBORCodec replaces the terms of JSON-LD document by their IDs and vice versa.
+type compactCBORCodec struct {
+	termIDs map[string]uint64
+	terms   map[uint64]string
+}
+
+// newCompactCBORCodec creates the codec of the terms defined by the contexts. The terms are sorted
+// lexicographically and get even IDs starting from compactCBORFirstTermID.
+func newCompactCBORCodec(contexts interface{}, loader ld.DocumentLoader) (*compactCBORCodec, error) {
+	defined := make(map[string]bool)
+
+	if err := collectTerms(contexts, loader, defined, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+
+	termList := make([]string, 0, len(defined))
+	for term := range defined {
+		termList = append(termList, term)
+	}
+
+	sort.Strings(termList)
+
+	codec := &compactCBORCodec{termIDs: make(map[string]uint64), terms: make(map[uint64]string)}
+
+	for keyword, id := range compactCBORKeywords {
+		codec.termIDs[keyword], codec.terms[id] = id, keyword
+	}
+
+	for i, term := range termList {
+		id := uint64(compactCBORFirstTermID + 2*i)
+		codec.termIDs[term], codec.terms[id] = id, term
+	}
+
+	return codec, nil
+}
+
+// collectTerms collects the terms defined by the contexts including the scoped ones.
+func collectTerms(contexts interface{}, loader ld.DocumentLoader, terms, loaded map[string]bool) error {
+	switch c := contexts.(type) {
+	case nil:
+		return nil
+
+	case string:
+		if loaded[c] {
+			return nil
+		}
+
+		loaded[c] = true
+
+		doc, err := loader.LoadDocument(c)
+		if err != nil {
+			return fmt.Errorf("load context %s: %w", c, err)
+		}
+
+		docMap, ok := doc.Document.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("context %s is not JSON object", c)
+		}
+
+		return collectTerms(docMap[jsonldContext], loader, terms, loaded)
+
+	case []interface{}:
+		for _, context := range c {
+			if err := collectTerms(context, loader, terms, loaded); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case map[string]interface{}:
+		for term, definition := range c {
+			if !strings.HasPrefix(term, "@") {
+				terms[term] = true
+			}
+
+			if defMap, ok := definition.(map[string]interface{}); ok {
+				if err := collectTerms(defMap[jsonldContext], loader, terms, loaded); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("context of unknown type: %T", contexts)
+	}
+}
+
+// compress replaces the terms of the value of the key by their IDs.
+func (c *compactCBORCodec) compress(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		compressed := make(map[interface{}]interface{}, len(v))
+
+		for k, kValue := range v {
+			if k == jsonldContext {
+				compressed[c.termIDs[k]] = compressContext(kValue)
+				continue
+			}
+
+			compressed[compressString(c.termIDs, k)] = c.compress(k, kValue)
+		}
+
+		return compressed
+
+	case []interface{}:
+		compressed := make([]interface{}, len(v))
+
+		for i := range v {
+			compressed[i] = c.compress(key, v[i])
+		}
+
+		return compressed
+
+	case string:
+		if isTypeKey(key) {
+			return compressString(c.termIDs, v)
+		}
+
+		return v
+
+	default:
+		return v
+	}
+}
+
+func compressString(ids map[string]uint64, s string) interface{} {
+	if id, ok := ids[s]; ok {
+		return id
+	}
+
+	return s
+}
+
+// compressContext replaces the well-known contexts by their IDs, the inline contexts are kept as is
+// as the terms are defined by them.
+func compressContext(context interface{}) interface{} {
+	switch c := context.(type) {
+	case string:
+		if id, ok := compactCBORContexts[c]; ok {
+			return id
+		}
+
+		return c
+
+	case []interface{}:
+		compressed := make([]interface{}, len(c))
+
+		for i := range c {
+			compressed[i] = compressContext(c[i])
+		}
+
+		return compressed
+
+	default:
+		return c
+	}
+}
+
+// decompress restores the terms of the value of the key from their IDs.
+func (c *compactCBORCodec) decompress(key string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		decompressed := make(map[string]interface{}, len(v))
+
+		for k, kValue := range v {
+			term, err := c.decompressID(k)
+			if err != nil {
+				return nil, err
+			}
+
+			if decompressed[term], err = c.decompress(term, kValue); err != nil {
+				return nil, err
+			}
+		}
+
+		return decompressed, nil
+
+	case []interface{}:
+		decompressed := make([]interface{}, len(v))
+
+		for i := range v {
+			var err error
+
+			if decompressed[i], err = c.decompress(key, v[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		return decompressed, nil
+
+	case uint64:
+		switch {
+		case key == jsonldContext:
+			return decompressContext(v)
+		case isTypeKey(key):
+			return c.decompressID(v)
+		default:
+			return v, nil
+		}
+
+	default:
+		return v, nil
+	}
+}
+
+func (c *compactCBORCodec) decompressID(id interface{}) (string, error) {
+	switch i := id.(type) {
+	case string:
+		return i, nil
+
+	case uint64:
+		term, ok := c.terms[i]
+		if !ok {
+			return "", fmt.Errorf("unknown compact CBOR term ID %d", i)
+		}
+
+		return term, nil
+
+	default:
+		return "", fmt.Errorf("compact CBOR term of unknown type: %T", id)
+	}
+}
+
+func decompressContext(id uint64) (string, error) {
+	for context, contextID := range compactCBORContexts {
+		if contextID == id {
+			return context, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown compact CBOR context ID %d", id)
+}
+
+// isTypeKey checks whether the values of the key are types, i.e. the terms defined by the contexts.
+func isTypeKey(key string) bool {
+	return key == jsonldType || key == "type"
+}
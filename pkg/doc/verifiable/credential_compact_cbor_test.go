@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const compactCBORCredential = `{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    {"name": "http://schema.org/name", "age": "http://schema.org/age"}
+  ],
+  "id": "http://example.edu/credentials/1872",
+  "type": ["VerifiableCredential", "UniversityDegreeCredential"],
+  "credentialSubject": {
+    "id": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+    "name": "Jayden Doe",
+    "age": 21.5
+  },
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z",
+  "notDefinedTerm": [true, null]
+}`
+
+func TestCredential_MarshalCompactCBOR(t *testing.T) {
+	vc, _, err := NewCredential([]byte(compactCBORCredential))
+	require.NoError(t, err)
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		compact, err := vc.MarshalCompactCBOR(WithJSONLDDocumentLoader(CachingJSONLDLoader()))
+		require.NoError(t, err)
+		require.Less(t, len(compact), len(vcBytes))
+
+		decodedVC, decodedBytes, err := NewCredentialFromCompactCBOR(compact,
+			WithJSONLDDocumentLoader(CachingJSONLDLoader()))
+		require.NoError(t, err)
+		require.Equal(t, vc, decodedVC)
+		require.JSONEq(t, string(vcBytes), string(decodedBytes))
+	})
+
+	t.Run("terms and contexts are compressed", func(t *testing.T) {
+		compact, err := vc.MarshalCompactCBOR()
+		require.NoError(t, err)
+
+		var envelope []interface{}
+		require.NoError(t, cbor.Unmarshal(compact, &envelope))
+		require.Len(t, envelope, 2)
+		require.Equal(t, compactCBORFormat, envelope[0])
+
+		content, ok := envelope[1].(map[interface{}]interface{})
+		require.True(t, ok)
+
+		contexts, ok := content[uint64(0)].([]interface{})
+		require.True(t, ok)
+		require.Equal(t, uint64(0x11), contexts[0])
+
+		// the terms which are not defined by the contexts are kept as is
+		require.Contains(t, content, "notDefinedTerm")
+		require.NotContains(t, content, "type")
+	})
+
+	t.Run("context loading error", func(t *testing.T) {
+		_, err := vc.MarshalCompactCBOR(WithJSONLDDocumentLoader(failingDocumentLoader{}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "load context https://www.w3.org/2018/credentials/v1: document is not available")
+
+		compact, err := vc.MarshalCompactCBOR()
+		require.NoError(t, err)
+
+		_, _, err = NewCredentialFromCompactCBOR(compact, WithJSONLDDocumentLoader(failingDocumentLoader{}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "document is not available")
+	})
+}
+
+func TestNewCredentialFromCompactCBOR(t *testing.T) {
+	marshal := func(t *testing.T, v interface{}) []byte {
+		data, err := cbor.Marshal(v)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	envelope := func(t *testing.T, content interface{}) []byte {
+		return marshal(t, []interface{}{compactCBORFormat, content})
+	}
+
+	t.Run("not compact CBOR", func(t *testing.T) {
+		_, _, err := NewCredentialFromCompactCBOR([]byte(compactCBORCredential))
+		require.Error(t, err)
+
+		_, _, err = NewCredentialFromCompactCBOR(marshal(t, map[string]interface{}{"id": "1"}))
+		require.True(t, errors.Is(err, ErrNotCompactCBOR))
+
+		_, _, err = NewCredentialFromCompactCBOR(marshal(t, cbor.Tag{Number: 0x0501, Content: map[string]interface{}{}}))
+		require.True(t, errors.Is(err, ErrNotCompactCBOR))
+
+		_, _, err = NewCredentialFromCompactCBOR(marshal(t, []interface{}{"other/format", map[string]interface{}{}}))
+		require.True(t, errors.Is(err, ErrNotCompactCBOR))
+
+		_, _, err = NewCredentialFromCompactCBOR(envelope(t, "not a map"))
+		require.True(t, errors.Is(err, ErrNotCompactCBOR))
+	})
+
+	t.Run("unknown IDs", func(t *testing.T) {
+		_, _, err := NewCredentialFromCompactCBOR(envelope(t, map[uint64]interface{}{
+			0: uint64(1),
+		}))
+		require.EqualError(t, err, "decode compact CBOR credential: unknown compact CBOR context ID 1")
+
+		_, _, err = NewCredentialFromCompactCBOR(envelope(t, map[uint64]interface{}{
+			0:    uint64(0x11),
+			9999: "value",
+		}))
+		require.EqualError(t, err, "decode compact CBOR credential: unknown compact CBOR term ID 9999")
+
+		_, _, err = NewCredentialFromCompactCBOR(envelope(t, map[interface{}]interface{}{
+			0:    uint64(0x11),
+			true: "value",
+		}))
+		require.EqualError(t, err, "decode compact CBOR credential: compact CBOR term of unknown type: bool")
+
+		_, _, err = NewCredentialFromCompactCBOR(envelope(t, map[uint64]interface{}{
+			0: []interface{}{uint64(0x11), uint64(1)},
+		}))
+		require.EqualError(t, err, "decode compact CBOR credential: unknown compact CBOR context ID 1")
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		_, _, err := NewCredentialFromCompactCBOR(envelope(t, map[uint64]interface{}{
+			0: true,
+		}))
+		require.EqualError(t, err, "decode compact CBOR credential: context of unknown type: bool")
+	})
+}
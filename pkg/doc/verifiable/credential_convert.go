@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"fmt"
+)
+
+// ToLinkedDataProof converts the credential (e.g. decoded from JWT) to the credential with linked data proof.
+// The original signature can't be carried over to the other representation, so the credential is re-signed
+// with the proof defined by context and the original proofs are not kept. The credential itself is not modified.
+func (vc *Credential) ToLinkedDataProof(context *LinkedDataProofContext) (*Credential, error) {
+	vcCopy, err := vc.unsignedCopy()
+	if err != nil {
+		return nil, fmt.Errorf("convert VC to linked data proof form: %w", err)
+	}
+
+	if err = vcCopy.AddLinkedDataProof(context); err != nil {
+		return nil, fmt.Errorf("convert VC to linked data proof form: %w", err)
+	}
+
+	return vcCopy, nil
+}
+
+// ToJWT converts the credential (e.g. decoded from JSON-LD with linked data proof) to JWS.
+// The original signature can't be carried over to the other representation, so the credential is re-signed
+// with privateKey and the embedded proofs are not kept. See JWTCredClaims.MarshalJWS for the signing details.
+func (vc *Credential) ToJWT(signatureAlg JWSAlgorithm, privateKey interface{}, keyID string) (string, error) {
+	vcCopy, err := vc.unsignedCopy()
+	if err != nil {
+		return "", fmt.Errorf("convert VC to JWT form: %w", err)
+	}
+
+	claims, err := vcCopy.JWTClaims(false)
+	if err != nil {
+		return "", fmt.Errorf("convert VC to JWT form: %w", err)
+	}
+
+	jws, err := claims.MarshalJWS(signatureAlg, privateKey, keyID)
+	if err != nil {
+		return "", fmt.Errorf("convert VC to JWT form: %w", err)
+	}
+
+	return jws, nil
+}
+
+// unsignedCopy returns the copy of the credential without proofs
+func (vc *Credential) unsignedCopy() (*Credential, error) {
+	vcCopy, err := vc.copy()
+	if err != nil {
+		return nil, err
+	}
+
+	vcCopy.Proofs = nil
+
+	return vcCopy, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
+)
+
+func TestCredential_ToJWT(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	vc.Proofs = []Proof{{"type": "Ed25519Signature2018", "jws": "eyJ..."}}
+
+	t.Run("credential is re-signed as JWT", func(t *testing.T) {
+		vcJWT, err := vc.ToJWT(EdDSA, privKey, "did:example:76e12ec712ebc6f1c221ebfeb1f#key1")
+		require.NoError(t, err)
+
+		// the credential is not modified
+		require.Len(t, vc.Proofs, 1)
+
+		vcFromJWT, _, err := NewCredential([]byte(vcJWT), WithPublicKeyFetcher(SingleKey(pubKey)))
+		require.NoError(t, err)
+		require.Empty(t, vcFromJWT.Proofs)
+		require.Equal(t, vc.ID, vcFromJWT.ID)
+		require.Equal(t, vc.Issuer, vcFromJWT.Issuer)
+		require.Equal(t, vc.Subject, vcFromJWT.Subject)
+	})
+
+	t.Run("invalid signature algorithm", func(t *testing.T) {
+		vcJWT, err := vc.ToJWT(JWSAlgorithm(-1), privKey, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert VC to JWT form")
+		require.Empty(t, vcJWT)
+	})
+
+	t.Run("JWT claims error", func(t *testing.T) {
+		vcCopy := *vc
+		vcCopy.Subject = []string{"did:example:1", "did:example:2"}
+
+		vcJWT, err := vcCopy.ToJWT(EdDSA, privKey, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert VC to JWT form")
+		require.Empty(t, vcJWT)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		invalidVC := &Credential{CustomFields: CustomFields{"invalid": make(chan int)}}
+
+		vcJWT, err := invalidVC.ToJWT(EdDSA, privKey, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert VC to JWT form")
+		require.Empty(t, vcJWT)
+	})
+}
+
+func TestCredential_ToLinkedDataProof(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	vcJWT, err := vc.ToJWT(EdDSA, privKey, "")
+	require.NoError(t, err)
+
+	vcFromJWT, _, err := NewCredential([]byte(vcJWT), WithPublicKeyFetcher(SingleKey(pubKey)))
+	require.NoError(t, err)
+
+	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey)))
+
+	t.Run("JWT credential is re-signed with linked data proof", func(t *testing.T) {
+		vcWithLdp, err := vcFromJWT.ToLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureJWS,
+			Suite:                   suite,
+		})
+		require.NoError(t, err)
+		require.Len(t, vcWithLdp.Proofs, 1)
+		require.Equal(t, "Ed25519Signature2018", vcWithLdp.Proofs[0]["type"])
+
+		// the credential is not modified
+		require.Empty(t, vcFromJWT.Proofs)
+
+		vcBytes, err := vcWithLdp.MarshalJSON()
+		require.NoError(t, err)
+		require.NoError(t, CheckLinkedDataProof(vcBytes, nil, SingleKey([]byte(pubKey))))
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		invalidVC := &Credential{CustomFields: CustomFields{"invalid": make(chan int)}}
+
+		vcWithLdp, err := invalidVC.ToLinkedDataProof(&LinkedDataProofContext{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert VC to linked data proof form")
+		require.Nil(t, vcWithLdp)
+	})
+
+	t.Run("linked data proof error", func(t *testing.T) {
+		vcWithLdp, err := vc.ToLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "UnknownSignature",
+			SignatureRepresentation: SignatureJWS,
+			Suite:                   suite,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert VC to linked data proof form")
+		require.Nil(t, vcWithLdp)
+	})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType is the type of the change of the credential field
+type ChangeType string
+
+const (
+	// FieldAdded means that the field is absent in the first credential and present in the second one
+	FieldAdded ChangeType = "added"
+	// FieldRemoved means that the field is present in the first credential and absent in the second one
+	FieldRemoved ChangeType = "removed"
+	// FieldChanged means that the field has different values in the credentials
+	FieldChanged ChangeType = "changed"
+)
+
+// FieldChange is the change of the credential field
+type FieldChange struct {
+	// Path is the path of the field in JSON form of the credential (e.g. "credentialSubject.degree.name")
+	Path string
+	// Type is the type of the change
+	Type ChangeType
+	// Old is the value of the field in the first credential (nil for the added field)
+	Old interface{}
+	// New is the value of the field in the second credential (nil for the removed field)
+	New interface{}
+}
+
+// diffOpts holds options of the credentials comparison
+type diffOpts struct {
+	ignoredFields []string
+}
+
+// DiffOpt is the option of the credentials comparison
+type DiffOpt func(opts *diffOpts)
+
+// WithDiffIgnoredFields defines the top-level fields of the credential which are not compared.
+// The option replaces the default ones, i.e. "proof" and "issuanceDate".
+func WithDiffIgnoredFields(fields ...string) DiffOpt {
+	return func(opts *diffOpts) {
+		opts.ignoredFields = fields
+	}
+}
+
+// Diff compares the credentials and returns the changes of their fields (sorted by path).
+// The credentials are compared in JSON form, so both standard and custom fields are compared;
+// the objects (e.g. credential subject) are compared field by field and the arrays as a whole.
+// The proof and issuance date are not compared by default (see WithDiffIgnoredFields).
+func Diff(a, b *Credential, opts ...DiffOpt) ([]FieldChange, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("credential is not defined")
+	}
+
+	options := &diffOpts{ignoredFields: []string{"proof", "issuanceDate"}}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	aMap, err := credentialToMap(a)
+	if err != nil {
+		return nil, fmt.Errorf("diff credentials: %w", err)
+	}
+
+	bMap, err := credentialToMap(b)
+	if err != nil {
+		return nil, fmt.Errorf("diff credentials: %w", err)
+	}
+
+	for _, field := range options.ignoredFields {
+		delete(aMap, field)
+		delete(bMap, field)
+	}
+
+	changes := diffObjects("", aMap, bMap, nil)
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	return changes, nil
+}
+
+func credentialToMap(vc *Credential) (map[string]interface{}, error) {
+	vcBytes, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal credential: %w", err)
+	}
+
+	var vcMap map[string]interface{}
+
+	if err = json.Unmarshal(vcBytes, &vcMap); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	return vcMap, nil
+}
+
+func diffObjects(prefix string, a, b map[string]interface{}, changes []FieldChange) []FieldChange {
+	for key, aValue := range a {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		bValue, ok := b[key]
+		if !ok {
+			changes = append(changes, FieldChange{Path: path, Type: FieldRemoved, Old: aValue})
+
+			continue
+		}
+
+		aObj, aIsObj := aValue.(map[string]interface{})
+		bObj, bIsObj := bValue.(map[string]interface{})
+
+		switch {
+		case aIsObj && bIsObj:
+			changes = diffObjects(path, aObj, bObj, changes)
+		case !reflect.DeepEqual(aValue, bValue):
+			changes = append(changes, FieldChange{Path: path, Type: FieldChanged, Old: aValue, New: bValue})
+		}
+	}
+
+	for key, bValue := range b {
+		if _, ok := a[key]; ok {
+			continue
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		changes = append(changes, FieldChange{Path: path, Type: FieldAdded, New: bValue})
+	}
+
+	return changes
+}
@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	newVC := func(t *testing.T) *Credential {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.Subject = map[string]interface{}{
+			"id":   "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"name": "Jayden Doe",
+			"degree": map[string]interface{}{
+				"type": "BachelorDegree",
+				"name": "Bachelor of Science and Arts",
+			},
+		}
+
+		return vc
+	}
+
+	t.Run("same credentials", func(t *testing.T) {
+		changes, err := Diff(newVC(t), newVC(t))
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		a := newVC(t)
+		b := newVC(t)
+
+		b.ID = "http://example.edu/credentials/1873"
+		b.Subject = map[string]interface{}{
+			"id": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": map[string]interface{}{
+				"type":  "BachelorDegree",
+				"name":  "Bachelor of Science",
+				"level": "undergraduate",
+			},
+		}
+		b.CustomFields = CustomFields{"referenceNumber": 83294847.0}
+		b.Types = []string{"VerifiableCredential", "UniversityDegreeCredential"}
+
+		changes, err := Diff(a, b)
+		require.NoError(t, err)
+		require.Equal(t, []FieldChange{
+			{
+				Path: "credentialSubject.degree.level",
+				Type: FieldAdded,
+				New:  "undergraduate",
+			},
+			{
+				Path: "credentialSubject.degree.name",
+				Type: FieldChanged,
+				Old:  "Bachelor of Science and Arts",
+				New:  "Bachelor of Science",
+			},
+			{
+				Path: "credentialSubject.name",
+				Type: FieldRemoved,
+				Old:  "Jayden Doe",
+			},
+			{
+				Path: "id",
+				Type: FieldChanged,
+				Old:  "http://example.edu/credentials/1872",
+				New:  "http://example.edu/credentials/1873",
+			},
+			{
+				Path: "referenceNumber",
+				Type: FieldAdded,
+				New:  83294847.0,
+			},
+			{
+				Path: "type",
+				Type: FieldChanged,
+				Old:  "VerifiableCredential",
+				New:  []interface{}{"VerifiableCredential", "UniversityDegreeCredential"},
+			},
+		}, changes)
+	})
+
+	t.Run("proof and issuance date are ignored by default", func(t *testing.T) {
+		a := newVC(t)
+		b := newVC(t)
+
+		issued := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		b.Issued = &issued
+		b.Proofs = []Proof{{"type": "Ed25519Signature2018"}}
+
+		changes, err := Diff(a, b)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+
+		changes, err = Diff(a, b, WithDiffIgnoredFields("proof"))
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "issuanceDate", changes[0].Path)
+		require.Equal(t, FieldChanged, changes[0].Type)
+
+		changes, err = Diff(a, b, WithDiffIgnoredFields())
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, FieldChange{
+			Path: "proof",
+			Type: FieldAdded,
+			New:  map[string]interface{}{"type": "Ed25519Signature2018"},
+		}, changes[1])
+	})
+
+	t.Run("credential is not defined", func(t *testing.T) {
+		_, err := Diff(newVC(t), nil)
+		require.EqualError(t, err, "credential is not defined")
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		b := newVC(t)
+		b.CustomFields = CustomFields{"invalid": make(chan int)}
+
+		_, err := Diff(newVC(t), b)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "diff credentials: marshal credential")
+
+		_, err = Diff(b, newVC(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "diff credentials: marshal credential")
+	})
+}
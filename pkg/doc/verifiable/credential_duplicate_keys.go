@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDuplicateKey is returned when JSON object of the credential has the same key defined more than once.
+// encoding/json takes the last value of such key, so the credential could be interpreted differently
+// by the verifier of its signature and by the application. As encoding/json matches the keys to the fields
+// case-insensitively, the keys which differ in case only (e.g. "issuer" and "ISSUER") are duplicates too.
+var ErrDuplicateKey = errors.New("duplicate JSON key")
+
+// WithDuplicateKeys option allows JSON objects of the credential to have duplicate keys (the last value is taken),
+// e.g. for legacy credentials. The credential with duplicate keys is rejected with ErrDuplicateKey by default.
+func WithDuplicateKeys() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.allowDuplicateKeys = true
+	}
+}
+
+// checkDuplicateKeys checks that JSON objects of the credential (or the header and claims of JWT credential)
+// don't have duplicate keys. The data which is not valid JSON is not checked, it is rejected when parsed.
+func checkDuplicateKeys(vcData []byte) error {
+	if !isJWS(vcData) && !isJWTUnsecured(vcData) {
+		return findDuplicateKey(vcData)
+	}
+
+	for _, part := range strings.Split(string(vcData), ".")[:2] {
+		decoded, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return fmt.Errorf("decode JWT: %w", err)
+		}
+
+		if err = findDuplicateKey(decoded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findDuplicateKey(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := checkValueKeys(decoder, "$")
+	if errors.Is(err, ErrDuplicateKey) {
+		return err
+	}
+
+	return nil
+}
+
+// checkValueKeys reads the next JSON value and checks that its objects don't have duplicate keys
+func checkValueKeys(decoder *json.Decoder, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	if delim == '{' {
+		err = checkObjectKeys(decoder, path)
+	} else {
+		for i := 0; decoder.More() && err == nil; i++ {
+			err = checkValueKeys(decoder, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// closing delimiter
+	_, err = decoder.Token()
+
+	return err
+}
+
+func checkObjectKeys(decoder *json.Decoder, path string) error {
+	keys := make(map[string]struct{})
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("invalid key at %s", path)
+		}
+
+		folded := foldKey(key)
+		if _, ok := keys[folded]; ok {
+			return fmt.Errorf("%w %q at %s", ErrDuplicateKey, key, path)
+		}
+
+		keys[folded] = struct{}{}
+
+		if err = checkValueKeys(decoder, path+"."+key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// foldKey returns the case-folded form of the key, the keys which encoding/json matches case-insensitively
+// (e.g. "issuer" and "ISSUER", or "kid" and "\u212Aid" with Kelvin sign) have the same folded form.
+func foldKey(key string) string {
+	return strings.ToLower(strings.ToUpper(key))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredential_DuplicateKeys(t *testing.T) {
+	// the issuer is replaced by the duplicate key which is taken by encoding/json
+	vcWithDuplicateKey := strings.Replace(validCredential, `"issuer":`,
+		`"issuer": "did:example:attacker", "issuer":`, 1)
+
+	t.Run("duplicate key is rejected", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(vcWithDuplicateKey))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"issuer" at $`)
+	})
+
+	t.Run("duplicate key differing in case is rejected", func(t *testing.T) {
+		// encoding/json matches "ISSUER" to the issuer field while JSON-LD drops the undefined term
+		vcBytes := strings.Replace(validCredential, `"issuer":`, `"ISSUER": "did:example:attacker", "issuer":`, 1)
+
+		_, _, err := NewCredential([]byte(vcBytes))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"issuer" at $`)
+
+		vcBytes = strings.Replace(validCredential, `"issuer":`, `"issuer": "did:example:attacker", "ISSUER":`, 1)
+
+		_, _, err = NewCredential([]byte(vcBytes))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"ISSUER" at $`)
+	})
+
+	t.Run("duplicate keys are allowed", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(vcWithDuplicateKey), WithDuplicateKeys())
+		require.NoError(t, err)
+	})
+
+	t.Run("no duplicate keys", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+	})
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	t.Run("nested objects", func(t *testing.T) {
+		err := checkDuplicateKeys([]byte(`{"a": [{"b": 1}, {"c": {"d": 1, "d": 2}}], "b": 1}`))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"d" at $.a[1].c`)
+
+		// the same key in different objects
+		require.NoError(t, checkDuplicateKeys([]byte(`{"a": {"b": 1}, "b": [{"b": 1}, {"b": 2}]}`)))
+	})
+
+	t.Run("JWT claims", func(t *testing.T) {
+		encode := func(s string) string {
+			return base64.RawURLEncoding.EncodeToString([]byte(s))
+		}
+
+		jwt := encode(`{"alg":"none"}`) + "." + encode(`{"iss":"did:example:a","vc":{},"iss":"did:example:b"}`) + "."
+
+		err := checkDuplicateKeys([]byte(jwt))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"iss"`)
+
+		jwt = encode(`{"alg":"none","alg":"none"}`) + "." + encode(`{"vc":{}}`) + "."
+
+		err = checkDuplicateKeys([]byte(jwt))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+		require.Contains(t, err.Error(), `"alg"`)
+	})
+
+	t.Run("keys folded like encoding/json does", func(t *testing.T) {
+		err := checkDuplicateKeys([]byte("{\"kid\": 1, \"\u212Aid\": 2}"))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+
+		err = checkDuplicateKeys([]byte("{\"status\": 1, \"\u017Ftatus\": 2}"))
+		require.True(t, errors.Is(err, ErrDuplicateKey))
+	})
+
+	t.Run("large object is checked in linear time", func(t *testing.T) {
+		var sb strings.Builder
+
+		sb.WriteString("{")
+
+		for i := 0; i < 100000; i++ {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+
+			fmt.Fprintf(&sb, `"key%d":%d`, i, i)
+		}
+
+		sb.WriteString("}")
+
+		start := time.Now()
+
+		require.NoError(t, checkDuplicateKeys([]byte(sb.String())))
+		require.True(t, time.Since(start) < 10*time.Second, "duplicate keys check took %s", time.Since(start))
+	})
+
+	t.Run("invalid JSON is not checked", func(t *testing.T) {
+		require.NoError(t, checkDuplicateKeys([]byte(`{"a": 1,, "a": 2}`)))
+		require.NoError(t, checkDuplicateKeys([]byte(`not JSON`)))
+	})
+}
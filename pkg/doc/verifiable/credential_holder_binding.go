@@ -0,0 +1,182 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/square/go-jose/v3"
+	"github.com/square/go-jose/v3/jwt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+)
+
+// ErrNoHolderBinding is returned when the holder binding is verified for the credential without "cnf" claim.
+var ErrNoHolderBinding = errors.New("credential is not bound to holder key")
+
+// ErrHolderProofNonceMismatch is returned when the nonce of the holder proof does not match the expected one.
+var ErrHolderProofNonceMismatch = errors.New("holder proof nonce mismatch")
+
+// ErrHolderProofCredentialMismatch is returned when the holder proof is not bound to the verified credential.
+var ErrHolderProofCredentialMismatch = errors.New("holder proof credential hash mismatch")
+
+// Confirmation is the "cnf" (confirmation) claim of JWT credential which binds it to the holder key (RFC 7800).
+type Confirmation struct {
+	JWK *jose.JSONWebKey `json:"jwk,omitempty"`
+}
+
+// jwtClaimsOpts holds options for the JWT claims of VC
+type jwtClaimsOpts struct {
+	holderKey interface{}
+}
+
+// JWTClaimsOpt is the option of JWT claims of Verifiable Credential
+type JWTClaimsOpt func(opts *jwtClaimsOpts)
+
+// WithHolderKey option binds JWT credential to the holder public key, i.e. "cnf" claim is set
+// to the JWK of the key. The holder proves possession of the key by holder proof (see VerifyHolderBinding).
+func WithHolderKey(publicKey interface{}) JWTClaimsOpt {
+	return func(opts *jwtClaimsOpts) {
+		opts.holderKey = publicKey
+	}
+}
+
+// newConfirmation creates "cnf" claim of the holder public key.
+func newConfirmation(publicKey interface{}) (*Confirmation, error) {
+	jwk := &jose.JSONWebKey{Key: publicKey}
+
+	if !jwk.Valid() || !jwk.IsPublic() {
+		return nil, fmt.Errorf("unsupported holder public key type %T", publicKey)
+	}
+
+	return &Confirmation{JWK: jwk}, nil
+}
+
+// HolderProofClaims is JWT Claims of the holder proof of possession of the key the credential is bound to.
+type HolderProofClaims struct {
+	*jwt.Claims
+
+	// Nonce is the nonce generated by verifier, so the proof can't be replayed
+	Nonce string `json:"nonce,omitempty"`
+	// CredentialHash is the hash of the credential the proof is made for (see CredentialHash)
+	CredentialHash string `json:"vc_hash,omitempty"`
+}
+
+// MarshalJWS serializes the holder proof claims into signed form (JWS).
+func (hpc *HolderProofClaims) MarshalJWS(signatureAlg JWSAlgorithm, privateKey interface{}, keyID string,
+	opts ...JWSOpt) (string, error) {
+	return marshalJWS(hpc, signatureAlg, privateKey, keyID, opts...)
+}
+
+// CredentialHash returns the hash of JWT credential (JWS) the holder proof is made for, i.e. base64url encoded
+// SHA-256 digest of the JWS. In case of SD-JWT credential, it is the hash of the issuer JWS (without disclosures).
+func CredentialHash(vcJWS string) string {
+	digest := sha256.Sum256([]byte(vcJWS))
+
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// VerifyHolderBinding checks holder proof of possession of the key the JWT credential is bound to by "cnf" claim.
+// The "cnf" claim is taken into account only if the issuer JWS of the credential was verified on decoding,
+// i.e. unsecured JWT or JWS decoded with disabled proof check are not bound to the holder key.
+//
+// The holder proof is JWS (see HolderProofClaims) signed with the holder private key. It must have the given nonce
+// (the one generated by verifier) and the hash of the credential (see CredentialHash), so the proof can't
+// be replayed or reused for other credential. Its "exp" and "nbf" claims are checked if present by the clock
+// the credential was decoded with (see WithClock).
+func (vc *Credential) VerifyHolderBinding(holderProof []byte, nonce string) error {
+	if vc.holderConfirmation == nil || vc.holderConfirmation.JWK == nil {
+		return fmt.Errorf("verify holder binding: %w", ErrNoHolderBinding)
+	}
+
+	if nonce == "" {
+		return errors.New("verify holder binding: nonce is required")
+	}
+
+	parsedJWT, err := jwt.ParseSigned(string(holderProof))
+	if err != nil {
+		return fmt.Errorf("verify holder binding: parse holder proof: %w", err)
+	}
+
+	var claims HolderProofClaims
+
+	if err = parsedJWT.Claims(vc.holderConfirmation.JWK.Key, &claims); err != nil {
+		return fmt.Errorf("verify holder binding: verify holder proof signature: %w", err)
+	}
+
+	if claims.Claims != nil {
+		if err = claims.Validate(jwt.Expected{Time: vc.now()}); err != nil {
+			return fmt.Errorf("verify holder binding: %w", err)
+		}
+	}
+
+	if claims.Nonce != nonce {
+		return fmt.Errorf("verify holder binding: %w", ErrHolderProofNonceMismatch)
+	}
+
+	if claims.CredentialHash != vc.holderCredentialHash {
+		return fmt.Errorf("verify holder binding: %w", ErrHolderProofCredentialMismatch)
+	}
+
+	return nil
+}
+
+// now returns the time of the holder proof checks.
+func (vc *Credential) now() time.Time {
+	if vc.clock == nil {
+		return clock.Real().Now()
+	}
+
+	return vc.clock.Now()
+}
+
+// decodeHolderBinding sets "cnf" claim of JWT credential (if any) and the hash of the credential the holder proof
+// is bound to. The claim is taken only from JWS verified on decoding (i.e. the proof check is not disabled).
+func decodeHolderBinding(vc *Credential, vcJWS []byte, vcOpts *credentialOpts) error {
+	if vcOpts.disabledProofCheck || !isJWS(vcJWS) {
+		return nil
+	}
+
+	confirmation, err := decodeHolderConfirmation(vcJWS)
+	if err != nil {
+		return err
+	}
+
+	if confirmation != nil {
+		vc.holderConfirmation = confirmation
+		vc.holderCredentialHash = CredentialHash(string(vcJWS))
+	}
+
+	return nil
+}
+
+// decodeHolderConfirmation returns "cnf" claim of JWT credential (if any).
+func decodeHolderConfirmation(vcData []byte) (*Confirmation, error) {
+	if !isJWS(vcData) && !isJWTUnsecured(vcData) {
+		return nil, nil
+	}
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(strings.Split(string(vcData), ".")[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWT claims: %w", err)
+	}
+
+	var claims struct {
+		Confirmation *Confirmation `json:"cnf,omitempty"`
+	}
+
+	if err = json.Unmarshal(claimsBytes, &claims); err != nil {
+		return nil, fmt.Errorf("unmarshal cnf claim: %w", err)
+	}
+
+	return claims.Confirmation, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/square/go-jose/v3/jwt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+)
+
+func TestCredential_VerifyHolderBinding(t *testing.T) {
+	issuerPubKey, issuerPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	holderPubKey, holderPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	newHolderBoundJWS := func(t *testing.T) string {
+		jwtClaims, err := vc.JWTClaims(true, WithHolderKey(holderPubKey))
+		require.NoError(t, err)
+		require.NotNil(t, jwtClaims.Confirmation)
+
+		jws, err := jwtClaims.MarshalJWS(EdDSA, issuerPrivKey, "")
+		require.NoError(t, err)
+
+		return jws
+	}
+
+	vcJWS := newHolderBoundJWS(t)
+	vcHash := CredentialHash(vcJWS)
+
+	newHolderBoundVC := func(t *testing.T) *Credential {
+		boundVC, _, err := NewCredential([]byte(vcJWS), WithPublicKeyFetcher(SingleKey(issuerPubKey)))
+		require.NoError(t, err)
+
+		return boundVC
+	}
+
+	newHolderProof := func(t *testing.T, claims *HolderProofClaims, privKey ed25519.PrivateKey) []byte {
+		jws, err := claims.MarshalJWS(EdDSA, privKey, "")
+		require.NoError(t, err)
+
+		return []byte(jws)
+	}
+
+	t.Run("holder proves possession of the key", func(t *testing.T) {
+		boundVC := newHolderBoundVC(t)
+
+		holderProof := newHolderProof(t, &HolderProofClaims{
+			Claims:         &jwt.Claims{IssuedAt: jwt.NewNumericDate(time.Now())},
+			Nonce:          "nonce-1",
+			CredentialHash: vcHash,
+		}, holderPrivKey)
+
+		require.NoError(t, boundVC.VerifyHolderBinding(holderProof, "nonce-1"))
+	})
+
+	t.Run("holder binding is kept in JWT claims of decoded credential", func(t *testing.T) {
+		jwtClaims, err := newHolderBoundVC(t).JWTClaims(true)
+		require.NoError(t, err)
+		require.NotNil(t, jwtClaims.Confirmation)
+		require.Equal(t, holderPubKey, jwtClaims.Confirmation.JWK.Key)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{Nonce: "nonce-1", CredentialHash: vcHash}, holderPrivKey)
+
+		err := newHolderBoundVC(t).VerifyHolderBinding(holderProof, "nonce-2")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHolderProofNonceMismatch))
+	})
+
+	t.Run("nonce is required", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{CredentialHash: vcHash}, holderPrivKey)
+
+		err := newHolderBoundVC(t).VerifyHolderBinding(holderProof, "")
+		require.EqualError(t, err, "verify holder binding: nonce is required")
+	})
+
+	t.Run("holder proof is made for other credential", func(t *testing.T) {
+		otherHash := CredentialHash(newHolderBoundJWS(t) + "other")
+
+		holderProof := newHolderProof(t, &HolderProofClaims{Nonce: "nonce-1", CredentialHash: otherHash},
+			holderPrivKey)
+
+		err := newHolderBoundVC(t).VerifyHolderBinding(holderProof, "nonce-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHolderProofCredentialMismatch))
+
+		holderProof = newHolderProof(t, &HolderProofClaims{Nonce: "nonce-1"}, holderPrivKey)
+
+		err = newHolderBoundVC(t).VerifyHolderBinding(holderProof, "nonce-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHolderProofCredentialMismatch))
+	})
+
+	t.Run("holder proof is signed by other key", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{Nonce: "nonce-1", CredentialHash: vcHash}, issuerPrivKey)
+
+		err := newHolderBoundVC(t).VerifyHolderBinding(holderProof, "nonce-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify holder proof signature")
+	})
+
+	t.Run("holder proof is expired", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{
+			Claims:         &jwt.Claims{Expiry: jwt.NewNumericDate(time.Now().Add(-time.Hour))},
+			Nonce:          "nonce-1",
+			CredentialHash: vcHash,
+		}, holderPrivKey)
+
+		err := newHolderBoundVC(t).VerifyHolderBinding(holderProof, "nonce-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, jwt.ErrExpired))
+	})
+
+	t.Run("holder proof is checked by the clock of decoding", func(t *testing.T) {
+		now := time.Now()
+
+		holderProof := newHolderProof(t, &HolderProofClaims{
+			Claims:         &jwt.Claims{Expiry: jwt.NewNumericDate(now.Add(time.Hour))},
+			Nonce:          "nonce-1",
+			CredentialHash: vcHash,
+		}, holderPrivKey)
+
+		boundVC := newHolderBoundVC(t)
+		require.NoError(t, boundVC.VerifyHolderBinding(holderProof, "nonce-1"))
+
+		boundVC, _, err = NewCredential([]byte(vcJWS), WithPublicKeyFetcher(SingleKey(issuerPubKey)),
+			WithClock(clock.Fixed(now.Add(2*time.Hour))))
+		require.NoError(t, err)
+
+		err = boundVC.VerifyHolderBinding(holderProof, "nonce-1")
+		require.True(t, errors.Is(err, jwt.ErrExpired))
+	})
+
+	t.Run("cnf claim of not verified credential is ignored", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{Nonce: "nonce-1", CredentialHash: vcHash}, holderPrivKey)
+
+		unverifiedVC, _, err := NewCredential([]byte(vcJWS), WithPublicKeyFetcher(SingleKey(issuerPubKey)),
+			func(opts *credentialOpts) { opts.disabledProofCheck = true })
+		require.NoError(t, err)
+
+		err = unverifiedVC.VerifyHolderBinding(holderProof, "nonce-1")
+		require.True(t, errors.Is(err, ErrNoHolderBinding))
+
+		jwtClaims, err := vc.JWTClaims(true, WithHolderKey(holderPubKey))
+		require.NoError(t, err)
+
+		unsecuredJWT, err := jwtClaims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		unsecuredVC, _, err := NewCredential([]byte(unsecuredJWT))
+		require.NoError(t, err)
+
+		err = unsecuredVC.VerifyHolderBinding(holderProof, "nonce-1")
+		require.True(t, errors.Is(err, ErrNoHolderBinding))
+	})
+
+	t.Run("invalid holder proof", func(t *testing.T) {
+		err := newHolderBoundVC(t).VerifyHolderBinding([]byte("not a JWS"), "nonce-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse holder proof")
+	})
+
+	t.Run("credential is not holder-bound", func(t *testing.T) {
+		holderProof := newHolderProof(t, &HolderProofClaims{}, holderPrivKey)
+
+		err := vc.VerifyHolderBinding(holderProof, "nonce-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrNoHolderBinding))
+	})
+
+	t.Run("unsupported holder key", func(t *testing.T) {
+		_, err := vc.JWTClaims(true, WithHolderKey("not a key"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported holder public key type string")
+
+		_, err = vc.JWTClaims(true, WithHolderKey(holderPrivKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported holder public key type")
+	})
+}
+
+func Test_decodeHolderConfirmation(t *testing.T) {
+	t.Run("not JWT", func(t *testing.T) {
+		cnf, err := decodeHolderConfirmation([]byte(validCredential))
+		require.NoError(t, err)
+		require.Nil(t, cnf)
+	})
+
+	t.Run("invalid cnf claim", func(t *testing.T) {
+		jwtClaims := &struct {
+			Confirmation string `json:"cnf"`
+		}{Confirmation: "invalid"}
+
+		jws, err := marshalUnsecuredJWT(map[string]string{"alg": "none"}, jwtClaims)
+		require.NoError(t, err)
+
+		_, err = decodeHolderConfirmation([]byte(jws))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal cnf claim")
+	})
+}
@@ -0,0 +1,22 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import "errors"
+
+// ErrMissingID is returned when VC doesn't have "id" required by WithRequireID option.
+var ErrMissingID = errors.New("credential id is missing")
+
+// EnsureID assigns the id generated by fn to VC if it doesn't have one, e.g. before the issuer signs it.
+// It returns the id of VC.
+func (vc *Credential) EnsureID(fn func() string) string {
+	if vc.ID == "" {
+		vc.ID = fn()
+	}
+
+	return vc.ID
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequireID(t *testing.T) {
+	var vcMap map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+	delete(vcMap, "id")
+
+	vcWithoutID, err := json.Marshal(vcMap)
+	require.NoError(t, err)
+
+	t.Run("id is optional by default", func(t *testing.T) {
+		vc, _, err := NewCredential(vcWithoutID)
+		require.NoError(t, err)
+		require.Empty(t, vc.ID)
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		_, _, err := NewCredential(vcWithoutID, WithRequireID())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMissingID))
+	})
+
+	t.Run("id is defined", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential), WithRequireID())
+		require.NoError(t, err)
+		require.Equal(t, "http://example.edu/credentials/1872", vc.ID)
+	})
+}
+
+func TestCredential_EnsureID(t *testing.T) {
+	generate := func() string {
+		return "urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5"
+	}
+
+	t.Run("id is generated", func(t *testing.T) {
+		vc := &Credential{}
+
+		require.Equal(t, generate(), vc.EnsureID(generate))
+		require.Equal(t, generate(), vc.ID)
+	})
+
+	t.Run("existing id is kept", func(t *testing.T) {
+		vc := &Credential{ID: "http://example.edu/credentials/1872"}
+
+		require.Equal(t, "http://example.edu/credentials/1872", vc.EnsureID(generate))
+	})
+}
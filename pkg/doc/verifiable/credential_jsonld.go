@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+// JSONLDForm is the form of JSON-LD document
+type JSONLDForm int
+
+const (
+	// JSONLDCompact is the compacted form of JSON-LD document which uses the terms of the document contexts.
+	JSONLDCompact JSONLDForm = iota
+	// JSONLDExpanded is the expanded form of JSON-LD document with the contexts applied and removed,
+	// i.e. all terms are expanded to IRIs.
+	JSONLDExpanded
+)
+
+// jsonldMarshalOpts holds options of the JSON-LD serialization of the credential
+type jsonldMarshalOpts struct {
+	documentLoader ld.DocumentLoader
+}
+
+// JSONLDMarshalOpt is the option of the JSON-LD serialization of the credential
+type JSONLDMarshalOpt func(opts *jsonldMarshalOpts)
+
+// WithJSONLDMarshalDocumentLoader defines the document loader used to load the contexts of the credential.
+// If not defined, a new document loader is created using CachingJSONLDLoader().
+func WithJSONLDMarshalDocumentLoader(documentLoader ld.DocumentLoader) JSONLDMarshalOpt {
+	return func(opts *jsonldMarshalOpts) {
+		opts.documentLoader = documentLoader
+	}
+}
+
+// MarshalJSONLD serializes the credential to JSON-LD of the given form. Unlike MarshalJSON the credential is
+// processed by JSON-LD processor, so e.g. the fields which are not defined by the contexts are dropped.
+// The expanded form is handy for canonicalization and debugging of the context terms resolution.
+func (vc *Credential) MarshalJSONLD(form JSONLDForm, opts ...JSONLDMarshalOpt) ([]byte, error) {
+	marshalOpts := &jsonldMarshalOpts{}
+
+	for _, opt := range opts {
+		opt(marshalOpts)
+	}
+
+	if marshalOpts.documentLoader == nil {
+		marshalOpts.documentLoader = CachingJSONLDLoader()
+	}
+
+	vcBytes, err := vc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("JSON-LD marshalling of verifiable credential: %w", err)
+	}
+
+	docMap, err := toMap(vcBytes)
+	if err != nil {
+		return nil, fmt.Errorf("JSON-LD marshalling of verifiable credential: %w", err)
+	}
+
+	proc := ld.NewJsonLdProcessor()
+	options := ld.NewJsonLdOptions("")
+	options.ProcessingMode = ld.JsonLd_1_1
+	options.DocumentLoader = marshalOpts.documentLoader
+
+	var doc interface{}
+
+	switch form {
+	case JSONLDCompact:
+		contextMap, e := extractContext(docMap)
+		if e != nil {
+			return nil, fmt.Errorf("JSON-LD marshalling of verifiable credential: %w", e)
+		}
+
+		doc, err = proc.Compact(docMap, contextMap, options)
+	case JSONLDExpanded:
+		doc, err = proc.Expand(docMap, options)
+	default:
+		return nil, fmt.Errorf("JSON-LD marshalling of verifiable credential: unsupported form %d", form)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("JSON-LD marshalling of verifiable credential: %w", err)
+	}
+
+	return json.Marshal(doc)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+)
+
+type failingDocumentLoader struct{}
+
+func (l failingDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	return nil, errors.New("document is not available")
+}
+
+func TestCredential_MarshalJSONLD(t *testing.T) {
+	loader := newCachingJSONLDLoader(failingDocumentLoader{})
+
+	extContext, err := ld.DocumentFromReader(strings.NewReader(`{
+  "@context": {
+    "referenceNumber": "https://example.com/vocab#referenceNumber"
+  }
+}`))
+	require.NoError(t, err)
+
+	loader.AddDocument("https://contexts.example.com/v1", extContext)
+
+	vc, _, err := NewCredential(credentialWithContext("https://contexts.example.com/v1"),
+		WithJSONLDDocumentLoader(loader))
+	require.NoError(t, err)
+
+	vc.CustomFields["undefinedTerm"] = "some value"
+
+	t.Run("compact form", func(t *testing.T) {
+		vcBytes, err := vc.MarshalJSONLD(JSONLDCompact, WithJSONLDMarshalDocumentLoader(loader))
+		require.NoError(t, err)
+
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(vcBytes, &vcMap))
+		require.Equal(t, 83294847.0, vcMap["referenceNumber"])
+		require.Equal(t, "http://example.com/credentials/4643", vcMap["id"])
+		require.NotContains(t, vcMap, "undefinedTerm")
+	})
+
+	t.Run("expanded form", func(t *testing.T) {
+		vcBytes, err := vc.MarshalJSONLD(JSONLDExpanded, WithJSONLDMarshalDocumentLoader(loader))
+		require.NoError(t, err)
+
+		var expanded []map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(vcBytes, &expanded))
+		require.Len(t, expanded, 1)
+		require.Equal(t, "http://example.com/credentials/4643", expanded[0]["@id"])
+		require.Contains(t, expanded[0], "https://example.com/vocab#referenceNumber")
+		require.Contains(t, expanded[0], "https://www.w3.org/2018/credentials#issuanceDate")
+		require.NotContains(t, expanded[0], "@context")
+	})
+
+	t.Run("default document loader", func(t *testing.T) {
+		baseVC, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vcBytes, err := baseVC.MarshalJSONLD(JSONLDExpanded)
+		require.NoError(t, err)
+		require.Contains(t, string(vcBytes), "https://www.w3.org/2018/credentials#issuer")
+	})
+
+	t.Run("context is not available", func(t *testing.T) {
+		vcBytes, err := vc.MarshalJSONLD(JSONLDExpanded,
+			WithJSONLDMarshalDocumentLoader(newCachingJSONLDLoader(failingDocumentLoader{})))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD marshalling of verifiable credential")
+		require.Nil(t, vcBytes)
+	})
+
+	t.Run("unsupported form", func(t *testing.T) {
+		vcBytes, err := vc.MarshalJSONLD(JSONLDForm(-1), WithJSONLDMarshalDocumentLoader(loader))
+		require.EqualError(t, err, "JSON-LD marshalling of verifiable credential: unsupported form -1")
+		require.Nil(t, vcBytes)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		invalidVC := &Credential{CustomFields: CustomFields{"invalid": make(chan int)}}
+
+		vcBytes, err := invalidVC.MarshalJSONLD(JSONLDCompact, WithJSONLDMarshalDocumentLoader(loader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD marshalling of verifiable credential")
+		require.Nil(t, vcBytes)
+	})
+}
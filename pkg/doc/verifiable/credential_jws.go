@@ -11,10 +11,12 @@ import (
 	"github.com/square/go-jose/v3/jwt"
 )
 
-// MarshalJWS serializes JWT into signed form (JWS)
+// MarshalJWS serializes JWT into signed form (JWS).
+// If keyID is empty, JWK thumbprint (RFC 7638) of the public key is used as "kid" (see WithNoDefaultKeyID).
 // todo refactor, do not pass privateKey (https://github.com/hyperledger/aries-framework-go/issues/339)
-func (jcc *JWTCredClaims) MarshalJWS(signatureAlg JWSAlgorithm, privateKey interface{}, keyID string) (string, error) { //nolint:lll
-	return marshalJWS(jcc, signatureAlg, privateKey, keyID)
+func (jcc *JWTCredClaims) MarshalJWS(signatureAlg JWSAlgorithm, privateKey interface{}, keyID string,
+	opts ...JWSOpt) (string, error) {
+	return marshalJWS(jcc, signatureAlg, privateKey, keyID, opts...)
 }
 
 func unmarshalJWSClaims(rawJwt []byte, checkProof bool, fetcher PublicKeyFetcher) (*JWTCredClaims, error) {
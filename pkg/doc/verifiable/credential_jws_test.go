@@ -6,8 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/square/go-jose/v3"
@@ -131,3 +134,78 @@ func TestCredJWSDecoderUnmarshal(t *testing.T) {
 		require.Nil(t, jws)
 	})
 }
+
+func TestWithAllowedJWSAlgorithms(t *testing.T) {
+	privateKey, err := readPrivateKey(filepath.Join(certPrefix, "issuer_private.pem"))
+	require.NoError(t, err)
+
+	publicKey, err := readPublicKey(filepath.Join(certPrefix, "issuer_public.pem"))
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	jwtClaims, err := vc.JWTClaims(true)
+	require.NoError(t, err)
+
+	jws, err := jwtClaims.MarshalJWS(RS256, privateKey, "any")
+	require.NoError(t, err)
+
+	noFetchExpected := func(issuerID, keyID string) (interface{}, error) {
+		require.Fail(t, "public key must not be fetched")
+
+		return nil, errors.New("unexpected fetch")
+	}
+
+	t.Run("no restriction by default", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(jws), WithPublicKeyFetcher(SingleKey(publicKey)))
+		require.NoError(t, err)
+	})
+
+	t.Run("algorithm is allowed", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(jws),
+			WithPublicKeyFetcher(SingleKey(publicKey)),
+			WithAllowedJWSAlgorithms(EdDSA, RS256))
+		require.NoError(t, err)
+	})
+
+	t.Run("algorithm is not allowed", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(jws),
+			WithPublicKeyFetcher(noFetchExpected),
+			WithAllowedJWSAlgorithms(EdDSA))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+		require.Contains(t, err.Error(), "disallowed JWS algorithm: RS256")
+	})
+
+	t.Run("unsecured JWT is rejected if algorithms are restricted", func(t *testing.T) {
+		parts := strings.Split(jws, ".")
+
+		for _, header := range []string{`{"alg":"none"}`, `{"alg":"RS256"}`} {
+			unsecuredJWT := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + parts[1] + "."
+
+			_, _, err := NewCredential([]byte(unsecuredJWT), WithAllowedJWSAlgorithms(RS256))
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrDisallowedAlgorithm), header)
+			require.Contains(t, err.Error(), "unsecured JWT decoding")
+		}
+	})
+
+	t.Run("none algorithm is always rejected", func(t *testing.T) {
+		parts := strings.Split(jws, ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		noneJWS := strings.Join(parts, ".")
+
+		_, _, err := NewCredential([]byte(noneJWS), WithPublicKeyFetcher(noFetchExpected))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+
+		_, _, err = NewCredential([]byte(noneJWS),
+			WithPublicKeyFetcher(noFetchExpected),
+			func(opts *credentialOpts) {
+				opts.disabledProofCheck = true
+			})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+	})
+}
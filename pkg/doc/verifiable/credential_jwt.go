@@ -27,16 +27,36 @@ type JWTCredClaims struct {
 	*jwt.Claims
 
 	VC map[string]interface{} `json:"vc,omitempty"`
+
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+
+	// SDAlg is the hash algorithm of the digests of selectively disclosable claims (see MarshalSDJWT)
+	SDAlg string `json:"_sd_alg,omitempty"`
 }
 
 // newJWTCredClaims creates JWT Claims of VC with an option to minimize certain fields of VC
 // which is put into "vc" claim.
-func newJWTCredClaims(vc *Credential, minimizeVC bool) (*JWTCredClaims, error) {
+func newJWTCredClaims(vc *Credential, minimizeVC bool, opts ...JWTClaimsOpt) (*JWTCredClaims, error) {
 	subjectID, err := subjectID(vc.Subject)
 	if err != nil {
 		return nil, fmt.Errorf("get VC subject id: %w", err)
 	}
 
+	options := &jwtClaimsOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	confirmation := vc.holderConfirmation
+
+	if options.holderKey != nil {
+		confirmation, err = newConfirmation(options.holderKey)
+		if err != nil {
+			return nil, fmt.Errorf("holder key binding: %w", err)
+		}
+	}
+
 	// currently jwt encoding supports only single subject (by the spec)
 	jwtClaims := &jwt.Claims{
 		Issuer:    vc.Issuer.ID,                   // iss
@@ -73,8 +93,9 @@ func newJWTCredClaims(vc *Credential, minimizeVC bool) (*JWTCredClaims, error) {
 	}
 
 	credClaims := &JWTCredClaims{
-		Claims: jwtClaims,
-		VC:     vcMap,
+		Claims:       jwtClaims,
+		VC:           vcMap,
+		Confirmation: confirmation,
 	}
 
 	return credClaims, nil
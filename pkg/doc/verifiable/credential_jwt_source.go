@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JWTHeader returns the JOSE header of JWT the credential was decoded from, e.g. to check "kid" or "typ".
+// nil is returned if the credential was not decoded from JWT. The returned map is a copy,
+// so its modification does not affect the credential.
+func (vc *Credential) JWTHeader() map[string]interface{} {
+	return unmarshalJWTSourcePart(vc.jwtHeader)
+}
+
+// DecodedJWTClaims returns the claims of JWT the credential was decoded from, including the registered
+// claims which are not mapped to the credential (e.g. "aud", "nonce" or "iat" to be checked against replay).
+// nil is returned if the credential was not decoded from JWT. The returned map is a copy,
+// so its modification does not affect the credential.
+// Use JWTClaims to create JWT claims from the credential.
+func (vc *Credential) DecodedJWTClaims() map[string]interface{} {
+	return unmarshalJWTSourcePart(vc.jwtClaims)
+}
+
+// decodeJWTSource decodes JOSE header and claims of JWT credential (either JWS or unsecured JWT).
+// nil is returned if the credential data is not JWT.
+func decodeJWTSource(vcData []byte) (json.RawMessage, json.RawMessage, error) {
+	if !isJWS(vcData) && !isJWTUnsecured(vcData) {
+		return nil, nil, nil
+	}
+
+	parts := strings.Split(string(vcData), ".")
+
+	header, err := decodeJWTSourcePart(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode JWT header: %w", err)
+	}
+
+	claims, err := decodeJWTSourcePart(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode JWT claims: %w", err)
+	}
+
+	return header, claims, nil
+}
+
+func decodeJWTSourcePart(part string) (json.RawMessage, error) {
+	partBytes, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return nil, err
+	}
+
+	var partMap map[string]interface{}
+
+	if err = json.Unmarshal(partBytes, &partMap); err != nil {
+		return nil, err
+	}
+
+	return partBytes, nil
+}
+
+func unmarshalJWTSourcePart(part json.RawMessage) map[string]interface{} {
+	if part == nil {
+		return nil
+	}
+
+	var partMap map[string]interface{}
+
+	// the part is checked to be JSON object when the credential is decoded
+	if err := json.Unmarshal(part, &partMap); err != nil {
+		return nil
+	}
+
+	return partMap
+}
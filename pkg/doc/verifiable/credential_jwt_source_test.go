@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/square/go-jose/v3/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredential_JWTSource(t *testing.T) {
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	jwtClaims, err := vc.JWTClaims(true)
+	require.NoError(t, err)
+
+	jwtClaims.Audience = jwt.Audience{"did:example:verifier"}
+
+	t.Run("JWS credential", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jws, err := jwtClaims.MarshalJWS(EdDSA, privKey, "did:example:issuer#key-1")
+		require.NoError(t, err)
+
+		jwsVC, _, err := NewCredential([]byte(jws), WithPublicKeyFetcher(SingleKey(pubKey)))
+		require.NoError(t, err)
+
+		header := jwsVC.JWTHeader()
+		require.Equal(t, "EdDSA", header["alg"])
+		require.Equal(t, "did:example:issuer#key-1", header["kid"])
+
+		claims := jwsVC.DecodedJWTClaims()
+		require.Equal(t, "did:example:verifier", claims["aud"])
+		require.Equal(t, vc.ID, claims["jti"])
+		require.Contains(t, claims, "vc")
+
+		// the claims are read-only
+		claims["aud"] = "did:example:attacker"
+		require.Equal(t, "did:example:verifier", jwsVC.DecodedJWTClaims()["aud"])
+	})
+
+	t.Run("unsecured JWT credential with custom claims", func(t *testing.T) {
+		claimsBytes, err := json.Marshal(jwtClaims)
+		require.NoError(t, err)
+
+		var claims map[string]interface{}
+		require.NoError(t, json.Unmarshal(claimsBytes, &claims))
+
+		claims["nonce"] = "n-0S6_WzA2Mj"
+
+		unsecuredJWT, err := marshalUnsecuredJWT(map[string]string{"alg": "none"}, claims)
+		require.NoError(t, err)
+
+		jwtVC, _, err := NewCredential([]byte(unsecuredJWT))
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"alg": "none"}, jwtVC.JWTHeader())
+		require.Equal(t, "n-0S6_WzA2Mj", jwtVC.DecodedJWTClaims()["nonce"])
+	})
+
+	t.Run("JSON credential", func(t *testing.T) {
+		require.Nil(t, vc.JWTHeader())
+		require.Nil(t, vc.DecodedJWTClaims())
+	})
+}
+
+func Test_decodeJWTSourcePart(t *testing.T) {
+	_, err := decodeJWTSourcePart("not base64!")
+	require.Error(t, err)
+
+	_, err = decodeJWTSourcePart(base64.RawURLEncoding.EncodeToString([]byte(`"not object"`)))
+	require.Error(t, err)
+
+	part, err := decodeJWTSourcePart(base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"alg": "none"}, unmarshalJWTSourcePart(part))
+
+	require.Nil(t, unmarshalJWTSourcePart(json.RawMessage("invalid")))
+}
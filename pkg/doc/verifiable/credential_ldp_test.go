@@ -9,11 +9,16 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 func TestNewCredentialFromLinkedDataProof(t *testing.T) {
@@ -22,7 +27,7 @@ func TestNewCredentialFromLinkedDataProof(t *testing.T) {
 	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
 	r.NoError(err)
 
-	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey)))
+	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey)))
 
 	ldpContext := &LinkedDataProofContext{
 		SignatureType:           "Ed25519Signature2018",
@@ -54,6 +59,46 @@ func TestNewCredentialFromLinkedDataProof(t *testing.T) {
 	r.Equal(vcMap, vcWithLdpMap)
 }
 
+func TestNewCredentialFromLinkedDataProof_Ed25519Signature2020(t *testing.T) {
+	r := require.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+
+	suite := ed25519signature2020.New(ed25519signature2020.WithSigner(mocksignature.NewEd25519Signer(privKey)))
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	r.NoError(err)
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2020",
+		SignatureRepresentation: SignatureProofValue,
+		Suite:                   suite,
+	})
+	r.NoError(err)
+
+	vcMap := addDummyCreatorToProof(vc, r)
+
+	proofValue, ok := vcMap["proof"].(map[string]interface{})["proofValue"].(string)
+	r.True(ok)
+	r.True(strings.HasPrefix(proofValue, "z"))
+
+	vcBytes, err := json.Marshal(vcMap)
+	r.NoError(err)
+
+	_, _, err = NewCredential(vcBytes,
+		WithEmbeddedSignatureSuites(suite),
+		WithPublicKeyFetcher(SingleKey([]byte(pubKey))))
+	r.NoError(err)
+
+	// the suite of 2018 does not accept the proof of 2020
+	_, _, err = NewCredential(vcBytes,
+		WithEmbeddedSignatureSuites(ed25519signature2018.New()),
+		WithPublicKeyFetcher(SingleKey([]byte(pubKey))))
+	r.Error(err)
+	r.True(errors.Is(err, ErrUnsupportedProofType))
+}
+
 func addDummyCreatorToProof(vc *Credential, r *require.Assertions) map[string]interface{} {
 	vcMap, err := toMap(vc)
 	r.NoError(err)
@@ -82,7 +127,8 @@ func TestCredential_AddLinkedDataProof(t *testing.T) {
 		err = vc.AddLinkedDataProof(&LinkedDataProofContext{
 			SignatureType:           "Ed25519Signature2018",
 			SignatureRepresentation: SignatureJWS,
-			Suite:                   ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 		})
 		r.NoError(err)
 
@@ -96,12 +142,145 @@ func TestCredential_AddLinkedDataProof(t *testing.T) {
 		r.Contains(vcProofMap, "created")
 		r.Contains(vcProofMap, "jws")
 		r.Equal("Ed25519Signature2018", vcProofMap["type"])
+		r.Equal("assertionMethod", vcProofMap["proofPurpose"])
+		r.NotContains(vcProofMap, "verificationMethod")
 
 		// check that only "proof" element was added as a result of AddLinkedDataProof().
 		delete(vcMap, "proof")
 		r.Equal(originalVCMap, vcMap)
 	})
 
+	t.Run("Add Linked Data proof with proof purpose and verification method to VC", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		r.NoError(err)
+
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+
+		err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
+			ProofPurpose:       "authentication",
+			VerificationMethod: "did:example:76e12ec712ebc6f1c221ebfeb1f#key1",
+		})
+		r.NoError(err)
+
+		r.Len(vc.Proofs, 1)
+		r.Equal("authentication", vc.Proofs[0]["proofPurpose"])
+		r.Equal("did:example:76e12ec712ebc6f1c221ebfeb1f#key1", vc.Proofs[0]["verificationMethod"])
+
+		vcBytes, err := vc.MarshalJSON()
+		r.NoError(err)
+
+		// verification method is used to resolve the public key
+		_, _, err = NewCredential(vcBytes,
+			WithEmbeddedSignatureSuites(ed25519signature2018.New()),
+			WithPublicKeyFetcher(SingleKey(pubKey)))
+		r.NoError(err)
+	})
+
+	t.Run("Add Linked Data proof with challenge and domain to VC", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		r.NoError(err)
+
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+
+		err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
+			Challenge: "3fa85f64",
+			Domain:    "example.com",
+		})
+		r.NoError(err)
+
+		r.Len(vc.Proofs, 1)
+		r.Equal("3fa85f64", vc.Proofs[0]["challenge"])
+		r.Equal("example.com", vc.Proofs[0]["domain"])
+
+		vcBytes, err := vc.MarshalJSON()
+		r.NoError(err)
+
+		_, _, err = NewCredential(vcBytes,
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithProofChallenge("3fa85f64"),
+			WithProofDomain("example.com"))
+		r.NoError(err)
+
+		_, _, err = NewCredential(vcBytes,
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithProofChallenge("other challenge"))
+		r.Error(err)
+		r.True(errors.Is(err, ErrProofChallengeMismatch))
+	})
+
+	t.Run("Add chained Linked Data proofs to VC", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		r.NoError(err)
+
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+
+		ldpContext := &LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
+			ID:         "urn:uuid:issuer-proof",
+			ProofChain: true,
+		}
+
+		err = vc.AddLinkedDataProof(ldpContext)
+		r.NoError(err)
+
+		ldpContext.ID = ""
+		err = vc.AddLinkedDataProof(ldpContext)
+		r.NoError(err)
+
+		r.Len(vc.Proofs, 2)
+		r.Equal("urn:uuid:issuer-proof", vc.Proofs[0]["id"])
+		r.NotContains(vc.Proofs[0], "previousProof")
+		r.Contains(vc.Proofs[1]["id"], "urn:uuid:")
+		r.Equal("urn:uuid:issuer-proof", vc.Proofs[1]["previousProof"])
+
+		vcBytes, err := vc.MarshalJSON()
+		r.NoError(err)
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(SingleKey(pubKey)))
+		r.NoError(err)
+
+		// the chained proofs are verified in order
+		vc.Proofs[0], vc.Proofs[1] = vc.Proofs[1], vc.Proofs[0]
+
+		vcBytes, err = vc.MarshalJSON()
+		r.NoError(err)
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(SingleKey(pubKey)))
+		r.Error(err)
+		r.True(errors.Is(err, verifier.ErrProofChainOrder))
+	})
+
+	t.Run("Add chained Linked Data proof to VC with proof without id", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+
+		vc.Proofs = []Proof{{"type": "Ed25519Signature2018"}}
+
+		err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
+			ProofChain: true,
+		})
+		r.Error(err)
+		r.Contains(err.Error(), "the last proof of the document has no id")
+	})
+
 	t.Run("Add invalid Linked Data proof to VC", func(t *testing.T) {
 		vc, _, err := NewCredential([]byte(validCredential))
 		require.NoError(t, err)
@@ -113,13 +292,15 @@ func TestCredential_AddLinkedDataProof(t *testing.T) {
 		err = vc.AddLinkedDataProof(&LinkedDataProofContext{
 			SignatureType:           "Ed25519Signature2018",
 			SignatureRepresentation: SignatureProofValue,
-			Suite:                   ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 		})
 		r.Error(err)
 
 		vc.CustomFields = nil
 		ldpContextWithMissingSignatureType := &LinkedDataProofContext{
-			Suite:                   ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+			Suite: ed25519signature2018.New(
+				ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 			SignatureRepresentation: SignatureProofValue,
 		}
 
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// defaultMaxSize is the default maximum size of the encoded credential (10 MiB)
+	defaultMaxSize = 10 << 20
+	// defaultMaxDepth is the default maximum nesting depth of JSON objects and arrays of the credential
+	defaultMaxDepth = 64
+)
+
+// ErrCredentialTooLarge is returned when the encoded credential exceeds the maximum size (see WithMaxSize).
+var ErrCredentialTooLarge = errors.New("credential is too large")
+
+// ErrCredentialTooDeep is returned when JSON objects and arrays of the credential are nested deeper than
+// the maximum depth (see WithMaxDepth).
+var ErrCredentialTooDeep = errors.New("credential is too deep")
+
+// limitOpts are the limits of the decoded credential
+type limitOpts struct {
+	maxSize  int
+	maxDepth int
+}
+
+// WithMaxSize sets the maximum size (in bytes) of the encoded credential, the larger credential is rejected
+// with ErrCredentialTooLarge before it is parsed. The limit is 10 MiB by default; it is not checked
+// if the size is not positive.
+func WithMaxSize(bytes int) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.limits.maxSize = bytes
+	}
+}
+
+// WithMaxDepth sets the maximum nesting depth of JSON objects and arrays of the credential, the credential
+// with deeper nesting is rejected with ErrCredentialTooDeep before it is parsed. The limit is 64 by default;
+// it is not checked if the depth is not positive.
+func WithMaxDepth(n int) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.limits.maxDepth = n
+	}
+}
+
+// NewCredentialFromReader reads the credential from r and decodes it (see NewCredential).
+// At most the maximum size of the credential (see WithMaxSize) is read, so the credential
+// of unlimited size doesn't exhaust the memory.
+func NewCredentialFromReader(r io.Reader, opts ...CredentialOpt) (*Credential, []byte, error) {
+	limits := parseCredentialOpts(opts).limits
+
+	if limits.maxSize > 0 {
+		r = io.LimitReader(r, int64(limits.maxSize)+1)
+	}
+
+	vcData, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read new credential: %w", err)
+	}
+
+	return NewCredential(vcData, opts...)
+}
+
+// checkSize checks that the encoded credential doesn't exceed the maximum size
+func (l limitOpts) checkSize(data []byte) error {
+	if l.maxSize > 0 && len(data) > l.maxSize {
+		return fmt.Errorf("%w: size exceeds %d bytes", ErrCredentialTooLarge, l.maxSize)
+	}
+
+	return nil
+}
+
+// checkDepth checks that JSON objects and arrays are not nested deeper than the maximum depth.
+// The data is scanned without parsing, the data which is not JSON (e.g. JWT) has zero depth.
+func (l limitOpts) checkDepth(data []byte) error {
+	if l.maxDepth <= 0 {
+		return nil
+	}
+
+	depth := 0
+	inString, escaped := false, false
+
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			escaped = c == '\\'
+			inString = c != '"'
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+
+			if depth > l.maxDepth {
+				return fmt.Errorf("%w: nesting exceeds depth %d", ErrCredentialTooDeep, l.maxDepth)
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// checkJWTDepth checks the nesting depth of the header and payload of the credential encoded as JWT,
+// they are checked once decoded from base64 but before they are unmarshalled. The data which is not JWT
+// is not checked.
+func (l limitOpts) checkJWTDepth(data []byte) error {
+	parts := strings.Split(string(data), ".")
+	if l.maxDepth <= 0 || len(parts) != 3 {
+		return nil
+	}
+
+	for _, part := range parts[:2] {
+		decoded, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil
+		}
+
+		if err = l.checkDepth(decoded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxSize(t *testing.T) {
+	t.Run("credential is too large", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential), WithMaxSize(len(validCredential)-1))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrCredentialTooLarge))
+
+		_, _, err = NewCredential(append([]byte(validCredential), make([]byte, defaultMaxSize)...))
+		require.True(t, errors.Is(err, ErrCredentialTooLarge))
+	})
+
+	t.Run("size is within the limit", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential), WithMaxSize(len(validCredential)))
+		require.NoError(t, err)
+	})
+
+	t.Run("limit is disabled", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential), WithMaxSize(0))
+		require.NoError(t, err)
+	})
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	vcWithSubject := func(t *testing.T, subject interface{}) []byte {
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+		vcMap["credentialSubject"] = subject
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	// the credential has depth 6 (the credential subject is the deepest part)
+	nested := vcWithSubject(t, map[string]interface{}{
+		"id": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+		"degree": map[string]interface{}{"names": []interface{}{
+			map[string]interface{}{"values": []interface{}{"Bachelor of Science {[", `"]}`}},
+		}},
+	})
+
+	t.Run("credential is too deep", func(t *testing.T) {
+		_, _, err := NewCredential(nested, WithMaxDepth(5))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrCredentialTooDeep))
+
+		deep := strings.Repeat("[", defaultMaxDepth+1) + strings.Repeat("]", defaultMaxDepth+1)
+		_, _, err = NewCredential([]byte(`{"credentialSubject":` + deep + `}`))
+		require.True(t, errors.Is(err, ErrCredentialTooDeep))
+	})
+
+	t.Run("JWT payload is too deep", func(t *testing.T) {
+		deep := strings.Repeat("[", defaultMaxDepth+1) + strings.Repeat("]", defaultMaxDepth+1)
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"vc":{"credentialSubject":` + deep + `}}`))
+
+		_, _, err := NewCredential([]byte(header + "." + payload + "."))
+		require.True(t, errors.Is(err, ErrCredentialTooDeep))
+
+		_, _, err = NewCredential([]byte(header+"."+payload+".signature"),
+			WithPublicKeyFetcher(SingleKey([]byte("key"))))
+		require.True(t, errors.Is(err, ErrCredentialTooDeep))
+	})
+
+	t.Run("depth is within the limit", func(t *testing.T) {
+		_, _, err := NewCredential(nested, WithMaxDepth(6))
+		require.NoError(t, err)
+	})
+
+	t.Run("limit is disabled", func(t *testing.T) {
+		_, _, err := NewCredential(nested, WithMaxDepth(-1))
+		require.NoError(t, err)
+	})
+}
+
+func TestNewCredentialFromReader(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		vc, _, err := NewCredentialFromReader(strings.NewReader(validCredential))
+		require.NoError(t, err)
+		require.Equal(t, "http://example.edu/credentials/1872", vc.ID)
+	})
+
+	t.Run("credential is too large", func(t *testing.T) {
+		r := bytes.NewReader(append([]byte(validCredential), make([]byte, 100)...))
+
+		_, _, err := NewCredentialFromReader(r, WithMaxSize(len(validCredential)))
+		require.True(t, errors.Is(err, ErrCredentialTooLarge))
+		require.Equal(t, 100-1, r.Len())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, _, err := NewCredentialFromReader(&failingReader{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read new credential")
+	})
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
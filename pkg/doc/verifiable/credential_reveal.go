@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	revealPathSeparator = "."
+	subjectIDField      = "id"
+)
+
+// DeriveReveal returns a copy of the credential which retains only the listed attribute paths of
+// credentialSubject. A path is a dot-separated list of JSON keys relative to the subject (e.g. "degree.type");
+// the subject "id" is always kept. Of the top-level fields, only @context, id, type, issuer, issuanceDate and
+// expirationDate are retained. The derived credential is not crypto-bound to the original one,
+// its proofs are dropped. An error is returned if any of the paths is absent in the subject.
+func (vc *Credential) DeriveReveal(paths []string) (*Credential, error) {
+	vcCopy, err := vc.copy()
+	if err != nil {
+		return nil, fmt.Errorf("derive reveal: %w", err)
+	}
+
+	subject, err := revealSubject(vcCopy.Subject, paths)
+	if err != nil {
+		return nil, fmt.Errorf("derive reveal: %w", err)
+	}
+
+	return &Credential{
+		Context:        vcCopy.Context,
+		ID:             vcCopy.ID,
+		Types:          vcCopy.Types,
+		Subject:        subject,
+		Issuer:         vcCopy.Issuer,
+		Issued:         vcCopy.Issued,
+		Expired:        vcCopy.Expired,
+		Schemas:        make([]TypedID, 0),
+		issuedRaw:      vcCopy.issuedRaw,
+		expiredRaw:     vcCopy.expiredRaw,
+		validityFields: vcCopy.validityFields,
+	}, nil
+}
+
+// revealSubject projects the subject (or each of several subjects) onto the paths.
+func revealSubject(subject Subject, paths []string) (Subject, error) {
+	var subjects []map[string]interface{}
+
+	switch s := subject.(type) {
+	case map[string]interface{}:
+		subjects = []map[string]interface{}{s}
+
+	case []interface{}:
+		for _, item := range s {
+			sMap, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("subject is not a JSON object")
+			}
+
+			subjects = append(subjects, sMap)
+		}
+
+	default:
+		return nil, errors.New("subject is not a JSON object")
+	}
+
+	revealed := make([]map[string]interface{}, len(subjects))
+
+	for i, s := range subjects {
+		revealed[i] = make(map[string]interface{})
+
+		if id, ok := s[subjectIDField]; ok {
+			revealed[i][subjectIDField] = id
+		}
+	}
+
+	for _, path := range paths {
+		found := false
+
+		for i, s := range subjects {
+			if revealPath(s, revealed[i], strings.Split(path, revealPathSeparator)) {
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("path %q is absent in subject", path)
+		}
+	}
+
+	if _, ok := subject.(map[string]interface{}); ok {
+		return revealed[0], nil
+	}
+
+	result := make([]interface{}, len(revealed))
+	for i := range revealed {
+		result[i] = revealed[i]
+	}
+
+	return result, nil
+}
+
+// revealPath copies the value at keys from src to dst, creating intermediate objects in dst as needed.
+// It returns false if the path is absent in src.
+func revealPath(src, dst map[string]interface{}, keys []string) bool {
+	value, ok := src[keys[0]]
+	if !ok {
+		return false
+	}
+
+	if len(keys) == 1 {
+		dst[keys[0]] = value
+
+		return true
+	}
+
+	srcNext, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	dstNext, ok := dst[keys[0]].(map[string]interface{})
+	if !ok {
+		dstNext = make(map[string]interface{})
+	}
+
+	if !revealPath(srcNext, dstNext, keys[1:]) {
+		return false
+	}
+
+	dst[keys[0]] = dstNext
+
+	return true
+}
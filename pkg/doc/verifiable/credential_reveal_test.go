@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredential_DeriveReveal(t *testing.T) {
+	newVC := func(subject Subject) *Credential {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.Subject = subject
+		vc.Proofs = []Proof{{"type": "Ed25519Signature2018"}}
+
+		return vc
+	}
+
+	subject := map[string]interface{}{
+		"id":   "did:example:ebfeb1f712ebc6f1c276e12ec21",
+		"name": "Jayden Doe",
+		"degree": map[string]interface{}{
+			"type": "BachelorDegree",
+			"name": "Bachelor of Science and Arts",
+		},
+	}
+
+	t.Run("reveal subject attributes", func(t *testing.T) {
+		vc := newVC(subject)
+
+		derived, err := vc.DeriveReveal([]string{"degree.type"})
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{
+			"id": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": map[string]interface{}{
+				"type": "BachelorDegree",
+			},
+		}, derived.Subject)
+
+		require.Equal(t, vc.Context, derived.Context)
+		require.Equal(t, vc.Types, derived.Types)
+		require.Equal(t, vc.ID, derived.ID)
+		require.Equal(t, vc.Issuer, derived.Issuer)
+		require.Equal(t, vc.Issued, derived.Issued)
+		require.Empty(t, derived.Proofs)
+		require.Nil(t, derived.Status)
+		require.Empty(t, derived.Evidence)
+
+		// the original credential is left untouched
+		require.Equal(t, subject, vc.Subject)
+		require.Len(t, vc.Proofs, 1)
+
+		// the derived credential is still valid
+		vcBytes, err := derived.MarshalJSON()
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes)
+		require.NoError(t, err)
+	})
+
+	t.Run("reveal attributes of several subjects", func(t *testing.T) {
+		vc := newVC([]map[string]interface{}{
+			subject,
+			{"id": "did:example:c276e12ec21ebfeb1f712ebc6f1", "name": "Morgan Doe"},
+		})
+
+		derived, err := vc.DeriveReveal([]string{"name", "degree.name"})
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{
+			map[string]interface{}{
+				"id":   "did:example:ebfeb1f712ebc6f1c276e12ec21",
+				"name": "Jayden Doe",
+				"degree": map[string]interface{}{
+					"name": "Bachelor of Science and Arts",
+				},
+			},
+			map[string]interface{}{
+				"id":   "did:example:c276e12ec21ebfeb1f712ebc6f1",
+				"name": "Morgan Doe",
+			},
+		}, derived.Subject)
+	})
+
+	t.Run("path is absent", func(t *testing.T) {
+		vc := newVC(subject)
+
+		_, err := vc.DeriveReveal([]string{"name", "degree.level"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `path "degree.level" is absent in subject`)
+
+		_, err = vc.DeriveReveal([]string{"name.first"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `path "name.first" is absent in subject`)
+	})
+
+	t.Run("subject is not a JSON object", func(t *testing.T) {
+		_, err := newVC("did:example:ebfeb1f712ebc6f1c276e12ec21").DeriveReveal([]string{"name"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "subject is not a JSON object")
+
+		_, err = newVC([]interface{}{"did:example:ebfeb1f712ebc6f1c276e12ec21"}).DeriveReveal([]string{"name"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "subject is not a JSON object")
+	})
+}
@@ -0,0 +1,481 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	sdJWTSeparator = "~"
+	sdDigestsField = "_sd"
+	sdAlgField     = "_sd_alg"
+	sdAlgSHA256    = "sha-256"
+	sdSaltSize     = 16
+	sdSubjectField = "credentialSubject"
+
+	// disclosure is JSON array of salt, claim name and claim value
+	disclosureLength = 3
+)
+
+// disclosure is the selectively disclosable claim of SD-JWT.
+type disclosure struct {
+	encoded string
+	name    string
+	value   interface{}
+}
+
+// MarshalSDJWT serializes the credential into Selective Disclosure JWT (SD-JWT), i.e. JWS signed by the issuer
+// followed by "~" separated disclosures. The disclosure frame lists the attribute paths of credentialSubject
+// which are selectively disclosable, a path is a dot-separated list of JSON keys relative to the subject
+// (e.g. "degree.type"). The digests of the disclosures are put into "_sd" arrays of the subject.
+// Only a single subject is supported (as for JWT credential).
+func (vc *Credential) MarshalSDJWT(signatureAlg JWSAlgorithm, privateKey interface{}, keyID string,
+	disclosureFrame []string) (string, error) {
+	jwtClaims, err := vc.JWTClaims(false)
+	if err != nil {
+		return "", fmt.Errorf("marshal SD-JWT: %w", err)
+	}
+
+	subject, ok := jwtClaims.VC[sdSubjectField].(map[string]interface{})
+	if !ok {
+		return "", errors.New("marshal SD-JWT: subject is not a JSON object")
+	}
+
+	// the deepest paths go first, so the disclosure of an object includes the digests of its nested disclosures
+	paths := append([]string(nil), disclosureFrame...)
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], revealPathSeparator) > strings.Count(paths[j], revealPathSeparator)
+	})
+
+	disclosures := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		encoded, discloseErr := discloseClaim(subject, strings.Split(path, revealPathSeparator))
+		if discloseErr != nil {
+			return "", fmt.Errorf("marshal SD-JWT: path %q: %w", path, discloseErr)
+		}
+
+		disclosures = append(disclosures, encoded)
+	}
+
+	jwtClaims.SDAlg = sdAlgSHA256
+
+	jws, err := jwtClaims.MarshalJWS(signatureAlg, privateKey, keyID)
+	if err != nil {
+		return "", fmt.Errorf("marshal SD-JWT: %w", err)
+	}
+
+	return strings.Join(append([]string{jws}, disclosures...), sdJWTSeparator) + sdJWTSeparator, nil
+}
+
+// discloseClaim replaces the claim at keys with the digest of its disclosure, returns the encoded disclosure.
+func discloseClaim(obj map[string]interface{}, keys []string) (string, error) {
+	value, ok := obj[keys[0]]
+	if !ok {
+		return "", errors.New("path is absent in subject")
+	}
+
+	if len(keys) > 1 {
+		next, isObj := value.(map[string]interface{})
+		if !isObj {
+			return "", errors.New("path is absent in subject")
+		}
+
+		return discloseClaim(next, keys[1:])
+	}
+
+	salt := make([]byte, sdSaltSize)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	disclosureBytes, err := json.Marshal([]interface{}{base64.RawURLEncoding.EncodeToString(salt), keys[0], value})
+	if err != nil {
+		return "", fmt.Errorf("marshal disclosure: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(disclosureBytes)
+
+	digests, _ := obj[sdDigestsField].([]interface{}) //nolint:errcheck
+	digests = append(digests, disclosureDigest(encoded))
+
+	// the digests are sorted to hide the original order of the claims
+	sort.Slice(digests, func(i, j int) bool {
+		return fmt.Sprint(digests[i]) < fmt.Sprint(digests[j])
+	})
+
+	delete(obj, keys[0])
+	obj[sdDigestsField] = digests
+
+	return encoded, nil
+}
+
+func disclosureDigest(encoded string) string {
+	digest := sha256.Sum256([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// NewCredentialFromSDJWT decodes the credential from Selective Disclosure JWT (see MarshalSDJWT).
+// The issuer signature of JWT is checked using the public key fetcher (see WithPublicKeyFetcher)
+// and the disclosed claims are put back into the subject. Each disclosure must have its digest in "_sd" array
+// of the signed claims. The proof options (e.g. WithProofThreshold and WithIssuerKeyBindingCheck) apply
+// to the issuer JWS, the challenge and domain of the embedded proofs are not checked as for JWS credential.
+func NewCredentialFromSDJWT(sdJWT []byte, opts ...CredentialOpt) (*Credential, error) {
+	vcOpts := parseCredentialOpts(opts)
+
+	if err := checkSDJWTLimits(sdJWT, vcOpts.limits); err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	jws, disclosures, err := parseSDJWT(string(sdJWT))
+	if err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	vcData, err := decodeSDJWTClaims(jws, disclosures, vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	// the disclosed claims have no proof of their own, the issuer JWS is checked instead
+	vc, _, err := NewCredential(vcData, append(opts, withoutProofCheck())...)
+	if err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	if err = checkSDJWTProof(vc, []byte(jws), vcOpts); err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	err = decodeHolderBinding(vc, []byte(jws), vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: holder binding: %w", err)
+	}
+
+	vc.jwtHeader, vc.jwtClaims, err = decodeJWTSource([]byte(jws))
+	if err != nil {
+		return nil, fmt.Errorf("decode SD-JWT credential: %w", err)
+	}
+
+	return vc, nil
+}
+
+// checkSDJWTLimits checks the size of SD-JWT and the nesting depth of its JWS and disclosures
+// before they are unmarshalled (see WithMaxSize and WithMaxDepth).
+func checkSDJWTLimits(sdJWT []byte, limits limitOpts) error {
+	if err := limits.checkSize(sdJWT); err != nil {
+		return err
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(sdJWT)), sdJWTSeparator)
+
+	if err := limits.checkJWTDepth([]byte(parts[0])); err != nil {
+		return err
+	}
+
+	for _, encoded := range parts[1:] {
+		disclosureBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			continue // rejected when parsed
+		}
+
+		if err = limits.checkDepth(disclosureBytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeSDJWTClaims checks the issuer JWS and returns the credential with the disclosed claims put back.
+func decodeSDJWTClaims(jws string, disclosures map[string]*disclosure, vcOpts *credentialOpts) ([]byte, error) {
+	if err := checkJWSAlgorithm([]byte(jws), vcOpts.allowedJWSAlgorithms); err != nil {
+		return nil, err
+	}
+
+	if vcOpts.publicKeyFetcher == nil {
+		return nil, errors.New("public key fetcher is not defined")
+	}
+
+	return decodeCredJWT([]byte(jws), func(vcJWTBytes []byte) (*JWTCredClaims, error) {
+		credClaims, claimsErr := unmarshalJWSClaims(vcJWTBytes, !vcOpts.disabledProofCheck, vcOpts.publicKeyFetcher)
+		if claimsErr != nil {
+			return nil, claimsErr
+		}
+
+		if credClaims.SDAlg != sdAlgSHA256 {
+			return nil, fmt.Errorf("unsupported %s: %s", sdAlgField, credClaims.SDAlg)
+		}
+
+		return credClaims, applyDisclosures(credClaims.VC, disclosures)
+	})
+}
+
+// withoutProofCheck disables the checks of the embedded proofs and of the options which refer to them.
+func withoutProofCheck() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.disabledProofCheck = true
+		opts.proofThreshold, opts.proofSigners = 0, nil
+		opts.proofChallenge, opts.proofDomain = "", ""
+		opts.issuerKeyBindingVDRI = nil
+		opts.verificationCache = nil
+	}
+}
+
+// checkSDJWTProof checks the proof options against the issuer JWS of SD-JWT, the key of JWS is the only signer.
+func checkSDJWTProof(vc *Credential, jws []byte, vcOpts *credentialOpts) error {
+	if vcOpts.disabledProofCheck {
+		return nil
+	}
+
+	keyIDs, err := proofKeyIDs(vc, jws)
+	if err != nil {
+		return err
+	}
+
+	signers := make([]string, len(keyIDs))
+	for i, keyID := range keyIDs {
+		signers[i] = absoluteKeyID(vc.Issuer.ID, keyID)
+	}
+
+	if err = checkProofThreshold(signers, vcOpts); err != nil {
+		return err
+	}
+
+	return checkIssuerKeyBinding(vc, jws, vcOpts)
+}
+
+// parseSDJWT splits SD-JWT into JWS and disclosures.
+func parseSDJWT(sdJWT string) (string, map[string]*disclosure, error) {
+	parts := strings.Split(strings.TrimSpace(sdJWT), sdJWTSeparator)
+
+	if !isJWS([]byte(parts[0])) {
+		return "", nil, errors.New("SD-JWT does not start with JWS")
+	}
+
+	disclosures := make(map[string]*disclosure)
+
+	for _, encoded := range parts[1:] {
+		if encoded == "" {
+			continue
+		}
+
+		d, err := parseDisclosure(encoded)
+		if err != nil {
+			return "", nil, err
+		}
+
+		disclosures[disclosureDigest(encoded)] = d
+	}
+
+	return parts[0], disclosures, nil
+}
+
+func parseDisclosure(encoded string) (*disclosure, error) {
+	disclosureBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode disclosure: %w", err)
+	}
+
+	var values []interface{}
+
+	if err = json.Unmarshal(disclosureBytes, &values); err != nil {
+		return nil, fmt.Errorf("unmarshal disclosure: %w", err)
+	}
+
+	if len(values) != disclosureLength {
+		return nil, fmt.Errorf("disclosure has %d elements, expected %d", len(values), disclosureLength)
+	}
+
+	name, ok := values[1].(string)
+	if !ok || name == "" || name == sdDigestsField {
+		return nil, errors.New("invalid disclosure claim name")
+	}
+
+	return &disclosure{encoded: encoded, name: name, value: values[2]}, nil
+}
+
+// applyDisclosures puts the disclosed claims into the objects which have their digests.
+// All the digests are removed. An error is returned if any disclosure is not referenced by a digest.
+func applyDisclosures(claims map[string]interface{}, disclosures map[string]*disclosure) error {
+	applied := make(map[string]bool)
+
+	err := walkSDObjects(claims, "", func(obj map[string]interface{}, _ string) error {
+		digests, _ := obj[sdDigestsField].([]interface{}) //nolint:errcheck
+		delete(obj, sdDigestsField)
+
+		for _, digest := range digests {
+			d, ok := disclosures[fmt.Sprint(digest)]
+			if !ok {
+				// not disclosed (or decoy digest)
+				continue
+			}
+
+			if _, exists := obj[d.name]; exists || applied[d.encoded] {
+				return fmt.Errorf("disclosed claim %q is duplicated", d.name)
+			}
+
+			obj[d.name] = d.value
+			applied[d.encoded] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	delete(claims, sdAlgField)
+
+	for _, d := range disclosures {
+		if !applied[d.encoded] {
+			return fmt.Errorf("digest of disclosure of claim %q is not found", d.name)
+		}
+	}
+
+	return nil
+}
+
+// walkSDObjects calls the function for the object and then walks its nested objects (including the claims
+// disclosed by the function). The path of the nested objects is a dot-separated list of the keys.
+func walkSDObjects(obj map[string]interface{}, path string, fn func(map[string]interface{}, string) error) error {
+	if err := fn(obj, path); err != nil {
+		return err
+	}
+
+	for k, v := range obj {
+		var nested []map[string]interface{}
+
+		switch value := v.(type) {
+		case map[string]interface{}:
+			nested = append(nested, value)
+		case []interface{}:
+			for _, item := range value {
+				if itemObj, ok := item.(map[string]interface{}); ok {
+					nested = append(nested, itemObj)
+				}
+			}
+		}
+
+		for _, n := range nested {
+			if err := walkSDObjects(n, joinPath(path, k), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + revealPathSeparator + key
+}
+
+// PresentSDJWT is used by the holder to create the presentation of SD-JWT credential which discloses
+// only the listed attribute paths of credentialSubject (see MarshalSDJWT). The disclosures of the objects
+// containing the paths are kept as well. An error is returned if a path is not selectively disclosable.
+func PresentSDJWT(sdJWT string, paths []string) (string, error) {
+	jws, disclosures, err := parseSDJWT(sdJWT)
+	if err != nil {
+		return "", fmt.Errorf("present SD-JWT: %w", err)
+	}
+
+	disclosurePaths, err := subjectDisclosurePaths(jws, disclosures)
+	if err != nil {
+		return "", fmt.Errorf("present SD-JWT: %w", err)
+	}
+
+	var selected []string
+
+	for _, path := range paths {
+		found := false
+
+		for encoded, disclosurePath := range disclosurePaths {
+			if disclosurePath == path || strings.HasPrefix(path, disclosurePath+revealPathSeparator) {
+				selected = append(selected, encoded)
+			}
+
+			if disclosurePath == path {
+				found = true
+			}
+		}
+
+		if !found {
+			return "", fmt.Errorf("present SD-JWT: path %q is not selectively disclosable", path)
+		}
+	}
+
+	return strings.Join(append([]string{jws}, dedupStrings(selected)...), sdJWTSeparator) + sdJWTSeparator, nil
+}
+
+// subjectDisclosurePaths returns the paths of the disclosures relative to credentialSubject.
+func subjectDisclosurePaths(jws string, disclosures map[string]*disclosure) (map[string]string, error) {
+	var claims struct {
+		VC map[string]interface{} `json:"vc"`
+	}
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(strings.Split(jws, ".")[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWT claims: %w", err)
+	}
+
+	if err = json.Unmarshal(claimsBytes, &claims); err != nil {
+		return nil, fmt.Errorf("unmarshal JWT claims: %w", err)
+	}
+
+	subject, ok := claims.VC[sdSubjectField].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("subject is not a JSON object")
+	}
+
+	paths := make(map[string]string)
+
+	err = walkSDObjects(subject, "", func(obj map[string]interface{}, path string) error {
+		digests, _ := obj[sdDigestsField].([]interface{}) //nolint:errcheck
+
+		for _, digest := range digests {
+			if d, found := disclosures[fmt.Sprint(digest)]; found {
+				paths[d.encoded] = joinPath(path, d.name)
+				obj[d.name] = d.value
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+func dedupStrings(values []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+)
+
+func TestCredential_SDJWT(t *testing.T) {
+	issuerPubKey, issuerPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	vc.Subject = map[string]interface{}{
+		"id":   "did:example:ebfeb1f712ebc6f1c276e12ec21",
+		"name": "Jayden Doe",
+		"degree": map[string]interface{}{
+			"type": "BachelorDegree",
+			"name": "Bachelor of Science and Arts",
+		},
+	}
+
+	sdJWT, err := vc.MarshalSDJWT(EdDSA, issuerPrivKey, "", []string{"name", "degree", "degree.name"})
+	require.NoError(t, err)
+
+	parts := strings.Split(sdJWT, sdJWTSeparator)
+	require.Len(t, parts, 5)
+	require.Empty(t, parts[4])
+
+	// the disclosed claims are not present in the signed JWT
+	require.NotContains(t, parts[0], "Jayden")
+
+	fetcher := WithPublicKeyFetcher(SingleKey(issuerPubKey))
+
+	t.Run("all the claims are disclosed", func(t *testing.T) {
+		sdVC, err := NewCredentialFromSDJWT([]byte(sdJWT), fetcher)
+		require.NoError(t, err)
+		require.Equal(t, vc.Subject, sdVC.Subject)
+		require.Equal(t, vc.ID, sdVC.ID)
+		require.Equal(t, vc.Issuer.ID, sdVC.Issuer.ID)
+	})
+
+	t.Run("holder presents subset of the claims", func(t *testing.T) {
+		presentation, err := PresentSDJWT(sdJWT, []string{"degree.name"})
+		require.NoError(t, err)
+		require.Len(t, strings.Split(presentation, sdJWTSeparator), 4)
+
+		sdVC, err := NewCredentialFromSDJWT([]byte(presentation), fetcher)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"id": "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": map[string]interface{}{
+				"type": "BachelorDegree",
+				"name": "Bachelor of Science and Arts",
+			},
+		}, sdVC.Subject)
+
+		presentation, err = PresentSDJWT(sdJWT, nil)
+		require.NoError(t, err)
+
+		sdVC, err = NewCredentialFromSDJWT([]byte(presentation), fetcher)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"}, sdVC.Subject)
+	})
+
+	t.Run("path is not selectively disclosable", func(t *testing.T) {
+		_, err := PresentSDJWT(sdJWT, []string{"degree.type"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `path "degree.type" is not selectively disclosable`)
+	})
+
+	t.Run("disclosure is not referenced by digest", func(t *testing.T) {
+		otherSDJWT, err := vc.MarshalSDJWT(EdDSA, issuerPrivKey, "", []string{"name"})
+		require.NoError(t, err)
+
+		otherDisclosure := strings.Split(otherSDJWT, sdJWTSeparator)[1]
+
+		_, err = NewCredentialFromSDJWT([]byte(parts[0]+sdJWTSeparator+otherDisclosure), fetcher)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `digest of disclosure of claim "name" is not found`)
+	})
+
+	t.Run("invalid issuer signature", func(t *testing.T) {
+		otherPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = NewCredentialFromSDJWT([]byte(sdJWT), WithPublicKeyFetcher(SingleKey(otherPubKey)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "VC JWT signature verification")
+	})
+
+	t.Run("public key fetcher is not defined", func(t *testing.T) {
+		_, err := NewCredentialFromSDJWT([]byte(sdJWT))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key fetcher is not defined")
+	})
+
+	t.Run("not SD-JWT", func(t *testing.T) {
+		_, err := NewCredentialFromSDJWT([]byte(validCredential), fetcher)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SD-JWT does not start with JWS")
+
+		_, err = PresentSDJWT(validCredential, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SD-JWT does not start with JWS")
+	})
+
+	t.Run("JWT without selective disclosures", func(t *testing.T) {
+		jwtClaims, err := vc.JWTClaims(false)
+		require.NoError(t, err)
+
+		jws, err := jwtClaims.MarshalJWS(EdDSA, issuerPrivKey, "")
+		require.NoError(t, err)
+
+		_, err = NewCredentialFromSDJWT([]byte(jws+sdJWTSeparator), fetcher)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported _sd_alg")
+	})
+
+	t.Run("proof options are checked against issuer JWS", func(t *testing.T) {
+		keyID := vc.Issuer.ID + "#keys-1"
+
+		issuerKey := did.PublicKey{ID: keyID, Type: "Ed25519VerificationKey2018", Value: issuerPubKey}
+		issuerDoc := &did.Doc{
+			ID:              vc.Issuer.ID,
+			PublicKey:       []did.PublicKey{issuerKey},
+			AssertionMethod: []did.VerificationMethod{{PublicKey: issuerKey}},
+		}
+
+		keyIDSDJWT, err := vc.MarshalSDJWT(EdDSA, issuerPrivKey, keyID, []string{"name"})
+		require.NoError(t, err)
+
+		sdVC, err := NewCredentialFromSDJWT([]byte(keyIDSDJWT), fetcher,
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveValue: issuerDoc}))
+		require.NoError(t, err)
+		require.Equal(t, vc.Subject, sdVC.Subject)
+
+		_, err = NewCredentialFromSDJWT([]byte(keyIDSDJWT), fetcher,
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveValue: &did.Doc{ID: vc.Issuer.ID}}))
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+
+		_, err = NewCredentialFromSDJWT([]byte(keyIDSDJWT), fetcher, WithProofThreshold(1, []string{keyID}))
+		require.NoError(t, err)
+
+		_, err = NewCredentialFromSDJWT([]byte(keyIDSDJWT), fetcher,
+			WithProofThreshold(1, []string{vc.Issuer.ID + "#keys-2"}))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+
+		_, err = NewCredentialFromSDJWT([]byte(keyIDSDJWT), fetcher, WithProofChallenge("challenge"))
+		require.NoError(t, err)
+	})
+
+	t.Run("limits are checked before SD-JWT is parsed", func(t *testing.T) {
+		_, err := NewCredentialFromSDJWT([]byte(sdJWT), fetcher, WithMaxSize(len(sdJWT)-1))
+		require.True(t, errors.Is(err, ErrCredentialTooLarge))
+
+		deepDisclosure := base64.RawURLEncoding.EncodeToString([]byte(`["salt","name",[[[[[[1]]]]]]]`))
+
+		_, err = NewCredentialFromSDJWT([]byte(parts[0]+sdJWTSeparator+deepDisclosure+sdJWTSeparator), fetcher,
+			WithMaxDepth(5))
+		require.True(t, errors.Is(err, ErrCredentialTooDeep))
+	})
+
+	t.Run("path of disclosure frame is absent", func(t *testing.T) {
+		_, err := vc.MarshalSDJWT(EdDSA, issuerPrivKey, "", []string{"degree.level"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `path "degree.level": path is absent in subject`)
+
+		_, err = vc.MarshalSDJWT(EdDSA, issuerPrivKey, "", []string{"name.first"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `path "name.first": path is absent in subject`)
+	})
+}
+
+func Test_parseDisclosure(t *testing.T) {
+	t.Run("invalid disclosures", func(t *testing.T) {
+		_, err := parseDisclosure("!")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode disclosure")
+
+		_, err = parseDisclosure("e30") // {}
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal disclosure")
+
+		_, err = parseDisclosure("WyJzYWx0IiwgIm5hbWUiXQ") // ["salt", "name"]
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disclosure has 2 elements, expected 3")
+
+		_, err = parseDisclosure("WyJzYWx0IiwgMSwgInZhbHVlIl0") // ["salt", 1, "value"]
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid disclosure claim name")
+	})
+
+	t.Run("valid disclosure", func(t *testing.T) {
+		d, err := parseDisclosure("WyJzYWx0IiwgIm5hbWUiLCAidmFsdWUiXQ") // ["salt", "name", "value"]
+		require.NoError(t, err)
+		require.Equal(t, "name", d.name)
+		require.Equal(t, "value", d.value)
+	})
+}
@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+)
+
+// Bitstring Status List (https://www.w3.org/TR/vc-bitstring-status-list/) types.
+const (
+	// StatusListCredentialType is the type of the status list credential.
+	StatusListCredentialType = "BitstringStatusListCredential"
+
+	// StatusListType is the type of the subject of the status list credential.
+	StatusListType = "BitstringStatusList"
+
+	// StatusListEntryType is the type of the credentialStatus which refers to the status list credential.
+	StatusListEntryType = "BitstringStatusListEntry"
+
+	// DefaultStatusListSize is the minimal size (in bits) of the status list required for the herd privacy.
+	DefaultStatusListSize = 131072
+
+	// maxStatusListLength is the maximal length (in bytes) of the decompressed status list, it keeps
+	// the small encodedList from being decompressed into a huge bitstring
+	maxStatusListLength = 16 << 20
+
+	// multibase prefix of base64url encoding without padding
+	base64URLMultibase = "u"
+)
+
+// ErrStatusListIndex is returned when the index is out of range of the status list.
+var ErrStatusListIndex = errors.New("status list index is out of range")
+
+// NewStatusListCredential creates the status list credential of the given purpose (e.g. "revocation" or
+// "suspension") with all the statuses of size (in bits) cleared. DefaultStatusListSize is used if size is
+// not positive. The credential is issued now (see WithClock, the other options are ignored). The subject
+// of the credential (encodedList) is gzip compressed bitstring encoded as base64url multibase value.
+// The issuer signs the credential after setting the statuses (see SetStatusListBit).
+//
+// The base context only is set, the context which defines the status list terms is to be added
+// by the issuer (e.g. for JSON-LD processing).
+func NewStatusListCredential(id string, issuer Issuer, purpose string, size int,
+	opts ...CredentialOpt) (*Credential, error) {
+	vcOpts := &credentialOpts{clock: clock.Real()}
+
+	for _, opt := range opts {
+		opt(vcOpts)
+	}
+
+	if size <= 0 {
+		size = DefaultStatusListSize
+	}
+
+	if size > maxStatusListLength*8 {
+		return nil, fmt.Errorf("new status list credential: size is greater than %d", maxStatusListLength*8)
+	}
+
+	encodedList, err := encodeStatusList(make([]byte, (size+7)/8))
+	if err != nil {
+		return nil, fmt.Errorf("new status list credential: %w", err)
+	}
+
+	issued := vcOpts.clock.Now().UTC()
+
+	return &Credential{
+		Context: Contexts{baseContext},
+		ID:      id,
+		Types:   []string{vcType, StatusListCredentialType},
+		Issuer:  issuer,
+		Issued:  &issued,
+		Subject: map[string]interface{}{
+			"id":            id + "#list",
+			"type":          StatusListType,
+			"statusPurpose": purpose,
+			"encodedList":   encodedList,
+		},
+	}, nil
+}
+
+// StatusListBit returns the status at the given index of the status list credential.
+// The index of the first status is 0 (the left-most bit of the bitstring).
+func (vc *Credential) StatusListBit(index int) (bool, error) {
+	_, list, err := vc.statusList()
+	if err != nil {
+		return false, fmt.Errorf("status list bit: %w", err)
+	}
+
+	if index < 0 || index >= len(list)*8 {
+		return false, fmt.Errorf("status list bit %d: %w", index, ErrStatusListIndex)
+	}
+
+	return list[index/8]&(1<<(7-uint(index%8))) != 0, nil
+}
+
+// SetStatusListBit sets (e.g. revokes the credential) or clears the status at the given index of
+// the status list credential. The proofs of the credential are not valid after the change, so the issuer
+// signs the credential again.
+func (vc *Credential) SetStatusListBit(index int, set bool) error {
+	subject, list, err := vc.statusList()
+	if err != nil {
+		return fmt.Errorf("set status list bit: %w", err)
+	}
+
+	if index < 0 || index >= len(list)*8 {
+		return fmt.Errorf("set status list bit %d: %w", index, ErrStatusListIndex)
+	}
+
+	mask := byte(1 << (7 - uint(index%8)))
+
+	if set {
+		list[index/8] |= mask
+	} else {
+		list[index/8] &^= mask
+	}
+
+	subject["encodedList"], err = encodeStatusList(list)
+	if err != nil {
+		return fmt.Errorf("set status list bit: %w", err)
+	}
+
+	vc.Subject = subject
+
+	return nil
+}
+
+// statusList returns the subject of the status list credential and its decoded bitstring.
+func (vc *Credential) statusList() (map[string]interface{}, []byte, error) {
+	if !vc.hasType(StatusListCredentialType) {
+		return nil, nil, fmt.Errorf("not a %s", StatusListCredentialType)
+	}
+
+	s, err := singleSubject(vc.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subject, err := toMap(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("subject of unknown structure: %w", err)
+	}
+
+	encodedList, ok := subject["encodedList"].(string)
+	if !ok {
+		return nil, nil, errors.New("encodedList is not defined")
+	}
+
+	list, err := decodeStatusList(encodedList)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subject, list, nil
+}
+
+func (vc *Credential) hasType(t string) bool {
+	for _, vcT := range vc.Types {
+		if vcT == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeStatusList(list []byte) (string, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write(list); err != nil {
+		return "", fmt.Errorf("compress status list: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("compress status list: %w", err)
+	}
+
+	return base64URLMultibase + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decodeStatusList(encodedList string) ([]byte, error) {
+	if !strings.HasPrefix(encodedList, base64URLMultibase) {
+		return nil, errors.New("encodedList is not base64url multibase value")
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(encodedList[len(base64URLMultibase):])
+	if err != nil {
+		return nil, fmt.Errorf("decode status list: %w", err)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("decompress status list: %w", err)
+	}
+
+	list, err := ioutil.ReadAll(io.LimitReader(r, maxStatusListLength+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompress status list: %w", err)
+	}
+
+	if len(list) > maxStatusListLength {
+		return nil, fmt.Errorf("decompress status list: status list is longer than %d bytes", maxStatusListLength)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+)
+
+func TestNewStatusListCredential(t *testing.T) {
+	t.Run("default size", func(t *testing.T) {
+		vc, err := NewStatusListCredential("https://example.com/status/1", Issuer{ID: "did:example:issuer"},
+			"revocation", 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{vcType, StatusListCredentialType}, vc.Types)
+
+		subject, ok := vc.Subject.(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "https://example.com/status/1#list", subject["id"])
+		require.Equal(t, StatusListType, subject["type"])
+		require.Equal(t, "revocation", subject["statusPurpose"])
+		require.True(t, strings.HasPrefix(subject["encodedList"].(string), "u"))
+
+		set, err := vc.StatusListBit(DefaultStatusListSize - 1)
+		require.NoError(t, err)
+		require.False(t, set)
+
+		_, err = vc.StatusListBit(DefaultStatusListSize)
+		require.True(t, errors.Is(err, ErrStatusListIndex))
+	})
+
+	t.Run("set and clear bits", func(t *testing.T) {
+		vc, err := NewStatusListCredential("https://example.com/status/1", Issuer{ID: "did:example:issuer"},
+			"revocation", 16)
+		require.NoError(t, err)
+
+		require.NoError(t, vc.SetStatusListBit(0, true))
+		require.NoError(t, vc.SetStatusListBit(9, true))
+
+		for index := 0; index < 16; index++ {
+			set, err := vc.StatusListBit(index)
+			require.NoError(t, err)
+			require.Equal(t, index == 0 || index == 9, set, index)
+		}
+
+		require.NoError(t, vc.SetStatusListBit(0, false))
+
+		set, err := vc.StatusListBit(0)
+		require.NoError(t, err)
+		require.False(t, set)
+
+		err = vc.SetStatusListBit(16, true)
+		require.True(t, errors.Is(err, ErrStatusListIndex))
+
+		err = vc.SetStatusListBit(-1, true)
+		require.True(t, errors.Is(err, ErrStatusListIndex))
+	})
+
+	t.Run("decoded status list credential", func(t *testing.T) {
+		vc, err := NewStatusListCredential("https://example.com/status/1", Issuer{ID: "did:example:issuer"},
+			"suspension", 16)
+		require.NoError(t, err)
+		require.NoError(t, vc.SetStatusListBit(3, true))
+
+		vcBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		decoded, _, err := NewCredential(vcBytes, WithNoCustomSchemaCheck())
+		require.NoError(t, err)
+
+		set, err := decoded.StatusListBit(3)
+		require.NoError(t, err)
+		require.True(t, set)
+
+		require.NoError(t, decoded.SetStatusListBit(3, false))
+
+		set, err = decoded.StatusListBit(3)
+		require.NoError(t, err)
+		require.False(t, set)
+	})
+
+	t.Run("issued by the clock", func(t *testing.T) {
+		issued := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		vc, err := NewStatusListCredential("https://example.com/status/1", Issuer{ID: "did:example:issuer"},
+			"revocation", 16, WithClock(clock.Fixed(issued)))
+		require.NoError(t, err)
+		require.Equal(t, issued, *vc.Issued)
+	})
+
+	t.Run("too big size", func(t *testing.T) {
+		_, err := NewStatusListCredential("https://example.com/status/1", Issuer{ID: "did:example:issuer"},
+			"revocation", maxStatusListLength*8+1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "size is greater than")
+	})
+}
+
+func TestCredential_StatusListErrors(t *testing.T) {
+	t.Run("not a status list credential", func(t *testing.T) {
+		vc := &Credential{Types: []string{vcType}}
+
+		_, err := vc.StatusListBit(0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a BitstringStatusListCredential")
+
+		err = vc.SetStatusListBit(0, true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a BitstringStatusListCredential")
+	})
+
+	t.Run("invalid subject", func(t *testing.T) {
+		for _, test := range []struct {
+			subject Subject
+			err     string
+		}{
+			{subject: nil, err: "no subject is defined"},
+			{subject: map[string]interface{}{}, err: "encodedList is not defined"},
+			{subject: map[string]interface{}{"encodedList": "H4sI"}, err: "not base64url multibase value"},
+			{subject: map[string]interface{}{"encodedList": "u!"}, err: "decode status list"},
+			{subject: map[string]interface{}{"encodedList": "uAAAA"}, err: "decompress status list"},
+		} {
+			vc := &Credential{Types: []string{vcType, StatusListCredentialType}, Subject: test.subject}
+
+			_, err := vc.StatusListBit(0)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		}
+	})
+
+	t.Run("too long decompressed status list", func(t *testing.T) {
+		encodedList, err := encodeStatusList(make([]byte, maxStatusListLength+1))
+		require.NoError(t, err)
+
+		vc := &Credential{
+			Types:   []string{vcType, StatusListCredentialType},
+			Subject: map[string]interface{}{"encodedList": encodedList},
+		}
+
+		_, err = vc.StatusListBit(0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status list is longer than")
+	})
+}
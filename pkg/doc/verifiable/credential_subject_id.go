@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import "fmt"
+
+// SubjectIDValidator validates "id" of the credential subject, e.g. it requires the subject to be identified by DID.
+type SubjectIDValidator func(id string) error
+
+// WithSubjectIDValidator option validates "id" of each subject of VC by the given validator,
+// the error of the validator is wrapped into the error of the decoding. The subjects without "id"
+// are not validated. The subject IDs are not validated by default.
+func WithSubjectIDValidator(validator SubjectIDValidator) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.subjectIDValidator = validator
+	}
+}
+
+// validateSubjectIDs validates "id" of each subject of VC.
+func validateSubjectIDs(subject Subject, validator SubjectIDValidator) error {
+	if validator == nil {
+		return nil
+	}
+
+	subjects, err := subjectMaps(subject)
+	if err != nil {
+		return fmt.Errorf("validate subject id: %w", err)
+	}
+
+	for _, s := range subjects {
+		id, ok := s["id"].(string)
+		if !ok {
+			continue
+		}
+
+		if err = validator(id); err != nil {
+			return fmt.Errorf("invalid subject id %q: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// subjectMaps returns the subjects of VC as JSON objects.
+func subjectMaps(subject Subject) ([]map[string]interface{}, error) {
+	switch s := subject.(type) {
+	case nil:
+		return nil, nil
+
+	case map[string]interface{}:
+		return []map[string]interface{}{s}, nil
+
+	case []map[string]interface{}:
+		return s, nil
+
+	case []interface{}:
+		subjects := make([]map[string]interface{}, 0, len(s))
+
+		for _, item := range s {
+			sMap, err := toMap(item)
+			if err != nil {
+				return nil, fmt.Errorf("subject of unknown structure: %w", err)
+			}
+
+			subjects = append(subjects, sMap)
+		}
+
+		return subjects, nil
+
+	case string:
+		// the subject is identified by the string (e.g. JWT credential with "sub" claim only)
+		return []map[string]interface{}{{"id": s}}, nil
+
+	default:
+		sMap, err := toMap(s)
+		if err != nil {
+			return nil, fmt.Errorf("subject of unknown structure: %w", err)
+		}
+
+		return []map[string]interface{}{sMap}, nil
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errNotDID = errors.New("subject id is not DID")
+
+func requireDID(id string) error {
+	if !strings.HasPrefix(id, "did:") {
+		return errNotDID
+	}
+
+	return nil
+}
+
+func TestWithSubjectIDValidator(t *testing.T) {
+	vcWithSubject := func(t *testing.T, subject interface{}) []byte {
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+		vcMap["credentialSubject"] = subject
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	t.Run("valid subject id", func(t *testing.T) {
+		var validated []string
+
+		_, _, err := NewCredential([]byte(validCredential), WithSubjectIDValidator(func(id string) error {
+			validated = append(validated, id)
+
+			return requireDID(id)
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:ebfeb1f712ebc6f1c276e12ec21"}, validated)
+	})
+
+	t.Run("invalid subject id", func(t *testing.T) {
+		vcBytes := vcWithSubject(t, map[string]interface{}{"id": "https://example.com/subject"})
+
+		_, _, err := NewCredential(vcBytes, WithSubjectIDValidator(requireDID))
+		require.True(t, errors.Is(err, errNotDID))
+		require.Contains(t, err.Error(), `invalid subject id "https://example.com/subject"`)
+
+		// not validated by default
+		_, _, err = NewCredential(vcBytes)
+		require.NoError(t, err)
+	})
+
+	t.Run("multiple subjects", func(t *testing.T) {
+		vcBytes := vcWithSubject(t, []interface{}{
+			map[string]interface{}{"id": "did:example:1"},
+			map[string]interface{}{"name": "subject without id"},
+			map[string]interface{}{"id": "urn:uuid:2"},
+		})
+
+		var validated []string
+
+		_, _, err := NewCredential(vcBytes, WithSubjectIDValidator(func(id string) error {
+			validated = append(validated, id)
+
+			return requireDID(id)
+		}))
+		require.True(t, errors.Is(err, errNotDID))
+		require.Equal(t, []string{"did:example:1", "urn:uuid:2"}, validated)
+	})
+}
+
+func TestSubjectMaps(t *testing.T) {
+	subjects, err := subjectMaps(nil)
+	require.NoError(t, err)
+	require.Empty(t, subjects)
+
+	subjects, err = subjectMaps("did:example:1")
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{{"id": "did:example:1"}}, subjects)
+
+	subjects, err = subjectMaps([]map[string]interface{}{{"id": "did:example:1"}})
+	require.NoError(t, err)
+	require.Len(t, subjects, 1)
+
+	subjects, err = subjectMaps(struct {
+		ID string `json:"id"`
+	}{ID: "did:example:1"})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{{"id": "did:example:1"}}, subjects)
+
+	_, err = subjectMaps([]interface{}{"not a subject"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "subject of unknown structure")
+
+	err = validateSubjectIDs(make(chan int), requireDID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validate subject id")
+}
@@ -7,11 +7,14 @@ package verifiable
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 
 	"github.com/piprate/json-gold/ld"
@@ -57,7 +60,7 @@ func TestNewCredential(t *testing.T) {
 		require.NotEmpty(t, vcData)
 
 		// validate @context
-		require.Equal(t, []string{"https://www.w3.org/2018/credentials/v1"}, vc.Context)
+		require.Equal(t, Contexts{"https://www.w3.org/2018/credentials/v1"}, vc.Context)
 
 		// validate id
 		require.Equal(t, "http://example.edu/credentials/1872", vc.ID)
@@ -91,7 +94,11 @@ func TestNewCredential(t *testing.T) {
 		require.Equal(t, "https://example.edu/refresh/3732", vc.RefreshService[0].ID)
 		require.Equal(t, "ManualRefreshService2018", vc.RefreshService[0].Type)
 
-		require.NotNil(t, vc.Evidence)
+		// check evidence
+		require.Len(t, vc.Evidence, 2)
+		require.Equal(t, "https://example.edu/evidence/f2aeec97-fc0d-42bf-8ca7-0548192d4231", vc.Evidence[0]["id"])
+		require.Equal(t, []interface{}{"DocumentVerification"}, vc.Evidence[0]["type"])
+		require.Equal(t, "DriversLicense", vc.Evidence[0]["evidenceDocument"])
 
 		require.NotNil(t, vc.TermsOfUse)
 		require.Len(t, vc.TermsOfUse, 1)
@@ -136,7 +143,8 @@ func TestValidateVerCredContext(t *testing.T) {
 		require.NoError(t, err)
 		err = validateCredentialUsingJSONSchema(bytes, nil, &credentialOpts{})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "@context: @context does not match: \"https://www.w3.org/2018/credentials/v1\"")
+		require.Contains(t, err.Error(),
+			"@context: @context must be one of the following: \"https://www.w3.org/2018/credentials/v1\"")
 	})
 
 	t.Run("test verifiable credential with empty context", func(t *testing.T) {
@@ -175,7 +183,8 @@ func TestValidateVerCredContext(t *testing.T) {
 		require.NoError(t, err)
 		err = validateCredentialUsingJSONSchema(bytes, nil, &credentialOpts{})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "@context.0: @context.0 does not match: \"https://www.w3.org/2018/credentials/v1\"")
+		require.Contains(t, err.Error(),
+			"@context.0: @context.0 must be one of the following: \"https://www.w3.org/2018/credentials/v1\"")
 	})
 
 	t.Run("test verifiable credential with object context", func(t *testing.T) {
@@ -191,7 +200,8 @@ func TestValidateVerCredContext(t *testing.T) {
 		require.NoError(t, err)
 		err = validateCredentialUsingJSONSchema(bytes, nil, &credentialOpts{})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "@context.0: @context.0 does not match: \"https://www.w3.org/2018/credentials/v1\"")
+		require.Contains(t, err.Error(),
+			"@context.0: @context.0 must be one of the following: \"https://www.w3.org/2018/credentials/v1\"")
 	})
 }
 
@@ -677,6 +687,63 @@ func TestCredential_MarshalJSON(t *testing.T) {
 	})
 }
 
+func TestCredential_MarshalJSONCanonical(t *testing.T) {
+	issued := time.Date(2010, time.January, 1, 19, 23, 24, 0, time.UTC)
+
+	newCredential := func() *Credential {
+		return &Credential{
+			Context: Contexts{"https://www.w3.org/2018/credentials/v1"},
+			ID:      "http://example.edu/credentials/1872",
+			Types:   []string{"VerifiableCredential"},
+			Subject: map[string]interface{}{
+				"id":     "did:example:ebfeb1f712ebc6f1c276e12ec21",
+				"name":   "Jayden Doe",
+				"degree": map[string]interface{}{"type": "BachelorDegree", "name": "Bachelor of Science"},
+			},
+			Issuer: Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+			Issued: &issued,
+			CustomFields: map[string]interface{}{
+				"zField": map[string]interface{}{"b": 2, "a": 1},
+				"aField": "value",
+			},
+		}
+	}
+
+	t.Run("keys are sorted at every level", func(t *testing.T) {
+		byteCred, err := newCredential().MarshalJSONCanonical()
+		require.NoError(t, err)
+
+		require.Equal(t, `{"@context":["https://www.w3.org/2018/credentials/v1"],"aField":"value","credentialSchema":null,`+
+			`"credentialSubject":{"degree":{"name":"Bachelor of Science","type":"BachelorDegree"},`+
+			`"id":"did:example:ebfeb1f712ebc6f1c276e12ec21","name":"Jayden Doe"},`+
+			`"id":"http://example.edu/credentials/1872","issuanceDate":"2010-01-01T19:23:24Z",`+
+			`"issuer":"did:example:76e12ec712ebc6f1c221ebfeb1f","type":"VerifiableCredential",`+
+			`"zField":{"a":1,"b":2}}`, string(byteCred))
+	})
+
+	t.Run("output is stable", func(t *testing.T) {
+		expected, err := newCredential().MarshalJSONCanonical()
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			actual, err := newCredential().MarshalJSONCanonical()
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("failure in VC marshalling", func(t *testing.T) {
+		vc := newCredential()
+		vc.CustomFields = map[string]interface{}{
+			"invalid field": make(chan int),
+		}
+
+		bytes, err := vc.MarshalJSONCanonical()
+		require.Error(t, err)
+		require.Nil(t, bytes)
+	})
+}
+
 func TestWithPublicKeyFetcher(t *testing.T) {
 	credentialOpt := WithPublicKeyFetcher(SingleKey("test pubKey"))
 	require.NotNil(t, credentialOpt)
@@ -783,6 +850,18 @@ func TestWithJSONLDDocumentLoader(t *testing.T) {
 	require.Equal(t, documentLoader, opts.jsonldDocumentLoader)
 }
 
+func TestWithClock(t *testing.T) {
+	now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	opts := parseCredentialOpts([]CredentialOpt{WithClock(clock.Fixed(now))})
+	require.Equal(t, now, opts.clock.Now())
+
+	// real-time clock is used by default
+	opts = parseCredentialOpts(nil)
+	require.NotNil(t, opts.clock)
+	require.WithinDuration(t, time.Now(), opts.clock.Now(), time.Minute)
+}
+
 func TestWithStrictValidation(t *testing.T) {
 	credentialOpt := WithStrictValidation()
 	require.NotNil(t, credentialOpt)
@@ -792,6 +871,148 @@ func TestWithStrictValidation(t *testing.T) {
 	require.True(t, opts.strictValidation)
 }
 
+func TestWithStrictContextValidation(t *testing.T) {
+	opts := &credentialOpts{}
+	WithStrictContextValidation(true)(opts)
+	require.True(t, opts.strictContext)
+	require.False(t, opts.strictValidation)
+
+	WithStrictContextValidation(false)(opts)
+	require.False(t, opts.strictContext)
+
+	jsonldContext := `
+{
+  "@context": {
+    "referenceNumber": "https://example.com/vocab#referenceNumber"
+  }
+}
+`
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(jsonldContext))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	vcJSON := fmt.Sprintf(`
+{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    "%s"
+  ],
+  "id": "http://example.com/credentials/4643",
+  "type": ["VerifiableCredential", "CustomExt12"],
+  "issuer": "https://example.com/issuers/14",
+  "issuanceDate": "2018-02-24T05:28:04Z",
+  "referenceNumber": 83294847,
+  "undefinedTerm": "some value",
+  "credentialSubject": {
+    "id": "did:example:abcdef1234567",
+    "undefinedSubjectTerm": "Jane Doe"
+  }
+}
+`, testServer.URL)
+
+	loader := CachingJSONLDLoader()
+
+	t.Run("strict mode rejects undefined terms", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(vcJSON),
+			WithJSONLDValidation(), WithJSONLDDocumentLoader(loader), WithStrictContextValidation(true))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD doc has different structure after compaction")
+		require.Nil(t, vc)
+	})
+
+	t.Run("lenient mode keeps undefined terms", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(vcJSON),
+			WithJSONLDValidation(), WithJSONLDDocumentLoader(loader), WithStrictContextValidation(false))
+		require.NoError(t, err)
+		require.Equal(t, "some value", vc.CustomFields["undefinedTerm"])
+		require.Equal(t, "Jane Doe", vc.Subject.(map[string]interface{})["undefinedSubjectTerm"])
+	})
+
+	t.Run("lenient mode does not relax strict validation", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(vcJSON), WithJSONLDValidation(), WithJSONLDDocumentLoader(loader),
+			WithStrictValidation(), WithStrictContextValidation(false))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD doc has different structure after compaction")
+
+		_, _, err = NewCredential([]byte(vcJSON), WithJSONLDValidation(), WithJSONLDDocumentLoader(loader),
+			WithStrictContextValidation(false), WithStrictValidation())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD doc has different structure after compaction")
+	})
+
+	t.Run("strict mode without strict validation", func(t *testing.T) {
+		opts := parseCredentialOpts([]CredentialOpt{WithStrictContextValidation(true)})
+		require.False(t, opts.strictValidation)
+
+		_, _, err := NewCredential([]byte(vcJSON), WithJSONLDValidation(), WithJSONLDDocumentLoader(loader),
+			WithStrictContextValidation(true))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JSON-LD doc has different structure after compaction")
+	})
+}
+
+func TestWithPreserveRaw(t *testing.T) {
+	opts := &credentialOpts{}
+	WithPreserveRaw()(opts)
+	require.True(t, opts.preserveRaw)
+
+	vcMap, err := toMap(validCredential)
+	require.NoError(t, err)
+
+	vcMap["issuanceDate"] = "2010-01-01T19:23:24.123+02:00"
+	vcMap["expirationDate"] = "2020-01-01T19:23:24+00:00"
+
+	vcBytes, err := json.Marshal(vcMap)
+	require.NoError(t, err)
+
+	expectedIssued := time.Date(2010, 1, 1, 17, 23, 24, 123000000, time.UTC)
+
+	t.Run("dates are normalized to UTC by default", func(t *testing.T) {
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+		require.True(t, expectedIssued.Equal(*vc.Issued))
+
+		vcJSON, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vcJSONMap, err := toMap(vcJSON)
+		require.NoError(t, err)
+		require.Equal(t, "2010-01-01T17:23:24.123Z", vcJSONMap["issuanceDate"])
+		require.Equal(t, "2020-01-01T19:23:24Z", vcJSONMap["expirationDate"])
+	})
+
+	t.Run("original dates are preserved", func(t *testing.T) {
+		vc, _, err := NewCredential(vcBytes, WithPreserveRaw())
+		require.NoError(t, err)
+		require.True(t, expectedIssued.Equal(*vc.Issued))
+
+		vcJSON, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vcJSONMap, err := toMap(vcJSON)
+		require.NoError(t, err)
+		require.Equal(t, "2010-01-01T19:23:24.123+02:00", vcJSONMap["issuanceDate"])
+		require.Equal(t, "2020-01-01T19:23:24+00:00", vcJSONMap["expirationDate"])
+
+		// changed date is normalized to UTC
+		issued := vc.Issued.Add(time.Hour)
+		vc.Issued = &issued
+
+		vcJSON, err = vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vcJSONMap, err = toMap(vcJSON)
+		require.NoError(t, err)
+		require.Equal(t, "2010-01-01T18:23:24.123Z", vcJSONMap["issuanceDate"])
+		require.Equal(t, "2020-01-01T19:23:24+00:00", vcJSONMap["expirationDate"])
+	})
+}
+
 func TestWithEmbeddedSignatureSuites(t *testing.T) {
 	suite := ed25519signature2018.New()
 
@@ -800,7 +1021,34 @@ func TestWithEmbeddedSignatureSuites(t *testing.T) {
 
 	opts := &credentialOpts{}
 	credentialOpt(opts)
-	require.Equal(t, suite, opts.ldpSuite)
+	require.Equal(t, []SignatureSuite{suite}, opts.ldpSuites)
+}
+
+func TestWithProofChallenge(t *testing.T) {
+	credentialOpt := WithProofChallenge("3fa85f64")
+	require.NotNil(t, credentialOpt)
+
+	opts := &credentialOpts{}
+	credentialOpt(opts)
+	require.Equal(t, "3fa85f64", opts.proofChallenge)
+}
+
+func TestWithProofDomain(t *testing.T) {
+	credentialOpt := WithProofDomain("example.com")
+	require.NotNil(t, credentialOpt)
+
+	opts := &credentialOpts{}
+	credentialOpt(opts)
+	require.Equal(t, "example.com", opts.proofDomain)
+}
+
+func TestWithProofDateConsistency(t *testing.T) {
+	credentialOpt := WithProofDateConsistency()
+	require.NotNil(t, credentialOpt)
+
+	opts := &credentialOpts{}
+	credentialOpt(opts)
+	require.True(t, opts.proofDateConsistency)
 }
 
 func TestCustomCredentialJsonSchemaValidator2018(t *testing.T) {
@@ -1227,6 +1475,78 @@ func TestDecodeIssuer(t *testing.T) {
 	})
 }
 
+func TestIssuer_JSON(t *testing.T) {
+	t.Run("Round trip of Issuer defined by ID only", func(t *testing.T) {
+		var issuer Issuer
+
+		err := json.Unmarshal([]byte(`"did:example:123"`), &issuer)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", issuer.ID)
+		require.Empty(t, issuer.Name)
+		require.Empty(t, issuer.CustomFields)
+
+		issuerBytes, err := json.Marshal(issuer)
+		require.NoError(t, err)
+		require.Equal(t, `"did:example:123"`, string(issuerBytes))
+	})
+
+	t.Run("Round trip of Issuer defined as an object", func(t *testing.T) {
+		issuerJSON := `{"id":"did:example:123","image":"data:image/png;base64,iVBOR","name":"Example University"}`
+
+		var issuer Issuer
+
+		err := json.Unmarshal([]byte(issuerJSON), &issuer)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", issuer.ID)
+		require.Equal(t, "Example University", issuer.Name)
+		require.Equal(t, CustomFields{"image": "data:image/png;base64,iVBOR"}, issuer.CustomFields)
+
+		issuerBytes, err := json.Marshal(issuer)
+		require.NoError(t, err)
+		require.JSONEq(t, issuerJSON, string(issuerBytes))
+	})
+
+	t.Run("Issuer object without ID", func(t *testing.T) {
+		var issuer Issuer
+
+		err := json.Unmarshal([]byte(`{"name":"Example University"}`), &issuer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issuer ID is not defined")
+	})
+
+	t.Run("Issuer of invalid format", func(t *testing.T) {
+		var issuer Issuer
+
+		err := json.Unmarshal([]byte(`55`), &issuer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported format of issuer")
+
+		err = json.Unmarshal([]byte(`{`), &issuer)
+		require.Error(t, err)
+	})
+
+	t.Run("Credential with string issuer", func(t *testing.T) {
+		vcMap, err := toMap(validCredential)
+		require.NoError(t, err)
+
+		vcMap["issuer"] = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+		require.Equal(t, Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"}, vc.Issuer)
+
+		vcBytes, err = vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vcMap, err = toMap(vcBytes)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f", vcMap["issuer"])
+	})
+}
+
 func TestTypesToSerialize(t *testing.T) {
 	// single type
 	require.Equal(t, "VerifiableCredential", typesToRaw([]string{"VerifiableCredential"}))
@@ -1237,31 +1557,97 @@ func TestTypesToSerialize(t *testing.T) {
 		typesToRaw([]string{"VerifiableCredential", "UniversityDegreeCredential"}))
 }
 
-func TestContextToSerialize(t *testing.T) {
-	// single context without custom objects
-	require.Equal(t,
-		[]string{"https://www.w3.org/2018/credentials/v1"},
-		contextToRaw([]string{"https://www.w3.org/2018/credentials/v1"}, []interface{}{}))
+func TestCredential_Context(t *testing.T) {
+	inlineContext := map[string]interface{}{
+		"image": map[string]interface{}{"@id": "schema:image", "@type": "@id"},
+	}
+
+	var vcMap map[string]interface{}
 
-	// several contexts without custom objects
-	require.Equal(t, []string{
+	require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+	vcMap["@context"] = []interface{}{
 		"https://www.w3.org/2018/credentials/v1",
-		"https://www.w3.org/2018/credentials/examples/v1"},
-		contextToRaw([]string{
+		map[string]interface{}{"@vocab": "https://example.com/vocab#"},
+		inlineContext,
+	}
+
+	vcBytes, err := json.Marshal(vcMap)
+	require.NoError(t, err)
+
+	t.Run("inline contexts are decoded in order", func(t *testing.T) {
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+		require.Equal(t, Contexts{
 			"https://www.w3.org/2018/credentials/v1",
-			"https://www.w3.org/2018/credentials/examples/v1"},
-			[]interface{}{}))
+			map[string]interface{}{"@vocab": "https://example.com/vocab#"},
+			inlineContext,
+		}, vc.Context)
+		require.Equal(t, []string{"https://www.w3.org/2018/credentials/v1"}, vc.ContextURIs())
 
-	// context with custom objects
-	customContext := map[string]interface{}{
-		"image": map[string]interface{}{"@id": "schema:image", "@type": "@id"},
-	}
-	require.Equal(t,
-		[]interface{}{"https://www.w3.org/2018/credentials/v1", customContext},
-		contextToRaw([]string{"https://www.w3.org/2018/credentials/v1"},
-			[]interface{}{
-				customContext,
-			}))
+		context, err := decodeContexts([]interface{}{
+			"https://www.w3.org/2018/credentials/v1",
+			inlineContext,
+			"https://www.w3.org/2018/credentials/examples/v1",
+		})
+		require.NoError(t, err)
+		require.Equal(t, Contexts{
+			"https://www.w3.org/2018/credentials/v1",
+			inlineContext,
+			"https://www.w3.org/2018/credentials/examples/v1",
+		}, context)
+
+		vc.Context = context
+		require.Equal(t, []string{
+			"https://www.w3.org/2018/credentials/v1",
+			"https://www.w3.org/2018/credentials/examples/v1",
+		}, vc.ContextURIs())
+	})
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+
+		vcBytesCopy, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vcCopy, _, err := NewCredential(vcBytesCopy)
+		require.NoError(t, err)
+		require.Equal(t, vc.Context, vcCopy.Context)
+	})
+
+	t.Run("JWT round trip", func(t *testing.T) {
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+
+		jwtClaims, err := vc.JWTClaims(true)
+		require.NoError(t, err)
+
+		unsecuredJWT, err := jwtClaims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		vcCopy, _, err := NewCredential([]byte(unsecuredJWT))
+		require.NoError(t, err)
+		require.Equal(t, vc.Context, vcCopy.Context)
+	})
+
+	t.Run("single context", func(t *testing.T) {
+		context, err := decodeContexts("https://www.w3.org/2018/credentials/v1")
+		require.NoError(t, err)
+		require.Equal(t, Contexts{"https://www.w3.org/2018/credentials/v1"}, context)
+
+		context, err = decodeContexts(inlineContext)
+		require.NoError(t, err)
+		require.Equal(t, Contexts{inlineContext}, context)
+	})
+
+	t.Run("context of unknown type", func(t *testing.T) {
+		_, err := decodeContexts(55)
+		require.EqualError(t, err, "credential context of unknown type")
+
+		_, err = decodeContexts([]interface{}{"https://www.w3.org/2018/credentials/v1", 55})
+		require.EqualError(t, err, "credential context entry 1 of unknown type")
+	})
 }
 
 func TestNewCredentialFromRaw(t *testing.T) {
@@ -1342,7 +1728,30 @@ func TestCredential_CreatePresentation(t *testing.T) {
 
 	require.Equal(t, []interface{}{vc}, vp.Credentials())
 	require.Equal(t, []string{"VerifiablePresentation"}, vp.Type)
-	require.Equal(t, vc.Context, vp.Context)
+	require.Equal(t, vc.ContextURIs(), vp.Context)
+	require.Empty(t, vp.CustomContext)
+
+	t.Run("inline contexts are copied", func(t *testing.T) {
+		inlineContext := map[string]interface{}{"@vocab": "https://example.com/vocab#"}
+
+		vc := &Credential{
+			Context: Contexts{"https://www.w3.org/2018/credentials/v1", inlineContext},
+			Types:   []string{"VerifiableCredential"},
+		}
+
+		vp, err := vc.Presentation()
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://www.w3.org/2018/credentials/v1"}, vp.Context)
+		require.Equal(t, []interface{}{inlineContext}, vp.CustomContext)
+
+		vpBytes, err := vp.MarshalJSON()
+		require.NoError(t, err)
+
+		var raw map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(vpBytes, &raw))
+		require.Equal(t, []interface{}{"https://www.w3.org/2018/credentials/v1", inlineContext}, raw["@context"])
+	})
 }
 
 func TestCredential_validateCredential(t *testing.T) {
@@ -1374,13 +1783,13 @@ func TestCredential_validateCredential(t *testing.T) {
 		require.NoError(t, err)
 
 		vc.Types = []string{"VerifiableCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1"}
 		r.NoError(validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{modelValidationMode: baseContextValidation}))
 
 		vc.Types = []string{"VerifiableCredential", "UniversityDegreeCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{modelValidationMode: baseContextValidation})
@@ -1388,7 +1797,7 @@ func TestCredential_validateCredential(t *testing.T) {
 		r.EqualError(err, "violated type constraint: not base only type defined")
 
 		vc.Types = []string{"UniversityDegreeCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{modelValidationMode: baseContextValidation})
@@ -1396,7 +1805,7 @@ func TestCredential_validateCredential(t *testing.T) {
 		r.EqualError(err, "violated type constraint: not base only type defined")
 
 		vc.Types = []string{"VerifiableCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/udc/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/udc/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{modelValidationMode: baseContextValidation})
@@ -1404,7 +1813,7 @@ func TestCredential_validateCredential(t *testing.T) {
 		r.EqualError(err, "violated @context constraint: not base only @context defined")
 
 		vc.Types = []string{"VerifiableCredential"}
-		vc.Context = []string{"https://www.exaple.org/udc/v1"}
+		vc.Context = Contexts{"https://www.exaple.org/udc/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{modelValidationMode: baseContextValidation})
@@ -1417,7 +1826,7 @@ func TestCredential_validateCredential(t *testing.T) {
 		require.NoError(t, err)
 
 		vc.Types = []string{"VerifiableCredential", "AlumniCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/alumni/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/alumni/v1"}
 		r.NoError(validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{
@@ -1431,7 +1840,7 @@ func TestCredential_validateCredential(t *testing.T) {
 			}))
 
 		vc.Types = []string{"VerifiableCredential", "UniversityDegreeCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/alumni/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/alumni/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{
@@ -1447,7 +1856,7 @@ func TestCredential_validateCredential(t *testing.T) {
 		r.EqualError(err, "not allowed type: UniversityDegreeCredential")
 
 		vc.Types = []string{"VerifiableCredential", "AlumniCredential"}
-		vc.Context = []string{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/udc/v1"}
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/v1", "https://www.exaple.org/udc/v1"}
 		err = validateCredential(
 			vc, vc.byteJSON(t),
 			&credentialOpts{
@@ -1502,6 +1911,78 @@ func TestDecodeWithNullValues(t *testing.T) {
 	vc, _, err := NewCredential([]byte(vcJSON))
 	require.NoError(t, err)
 	require.NotNil(t, vc)
+	require.Nil(t, vc.Evidence)
+}
+
+func TestCredential_Evidence(t *testing.T) {
+	r := require.New(t)
+
+	t.Run("single evidence", func(t *testing.T) {
+		vcMap, err := toMap(validCredential)
+		r.NoError(err)
+
+		evidence := map[string]interface{}{
+			"id":               "https://example.edu/evidence/f2aeec97-fc0d-42bf-8ca7-0548192d4231",
+			"type":             []interface{}{"DocumentVerification"},
+			"verifier":         "https://example.edu/issuers/14",
+			"evidenceDocument": "DriversLicense",
+		}
+		vcMap["evidence"] = evidence
+
+		vcBytes, err := json.Marshal(vcMap)
+		r.NoError(err)
+
+		vc, _, err := NewCredential(vcBytes)
+		r.NoError(err)
+		r.Equal([]Evidence{evidence}, vc.Evidence)
+
+		// single evidence is serialized as an object
+		vcMapFromVC, err := toMap(vc)
+		r.NoError(err)
+		r.Equal(evidence, vcMapFromVC["evidence"])
+	})
+
+	t.Run("multiple evidence", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+		r.Len(vc.Evidence, 2)
+
+		vcMap, err := toMap(validCredential)
+		r.NoError(err)
+
+		// multiple evidence is serialized as an array
+		vcMapFromVC, err := toMap(vc)
+		r.NoError(err)
+		r.Equal(vcMap["evidence"], vcMapFromVC["evidence"])
+	})
+
+	t.Run("evidence is preserved through JWT", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		r.NoError(err)
+
+		jwtClaims, err := vc.JWTClaims(true)
+		r.NoError(err)
+
+		unsecuredJWT, err := jwtClaims.MarshalUnsecuredJWT()
+		r.NoError(err)
+
+		vcFromJWT, _, err := NewCredential([]byte(unsecuredJWT))
+		r.NoError(err)
+		r.Equal(vc.Evidence, vcFromJWT.Evidence)
+	})
+
+	t.Run("invalid evidence", func(t *testing.T) {
+		vcMap, err := toMap(validCredential)
+		r.NoError(err)
+
+		vcMap["evidence"] = "not an object"
+
+		vcBytes, err := json.Marshal(vcMap)
+		r.NoError(err)
+
+		_, _, err = NewCredential(vcBytes)
+		r.Error(err)
+	})
 }
 
 func TestCredential_raw(t *testing.T) {
@@ -1530,6 +2011,19 @@ func TestCredential_raw(t *testing.T) {
 		require.Nil(t, vcRaw)
 	})
 
+	t.Run("Serialize with invalid evidence", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.Evidence = []Evidence{{
+			"invalidField": make(chan int),
+		}}
+
+		vcRaw, err := vc.raw()
+		require.Error(t, err)
+		require.Nil(t, vcRaw)
+	})
+
 	t.Run("Serialize with invalid proof", func(t *testing.T) {
 		vc, _, err := NewCredential([]byte(validCredential))
 		require.NoError(t, err)
@@ -1543,3 +2037,243 @@ func TestCredential_raw(t *testing.T) {
 		require.Nil(t, vcRaw)
 	})
 }
+
+func TestCredential_ProofSet(t *testing.T) {
+	proof1 := Proof{
+		"type":               "Ed25519Signature2018",
+		"created":            "2018-06-18T21:19:10Z",
+		"proofPurpose":       "assertionMethod",
+		"verificationMethod": "https://example.com/jdoe/keys/1",
+		"jws":                "eyJhbGciOiJQUzI1N..Dw_mmMCjs9qxg0zcZzqEJw",
+	}
+	proof2 := Proof{
+		"type":               "Ed25519Signature2018",
+		"created":            "2018-06-18T21:19:10Z",
+		"proofPurpose":       "assertionMethod",
+		"verificationMethod": "https://example.com/jdoe/keys/2",
+		"jws":                "eyJhbGciOiJQUzI1N..KkEsPEi8Ff8cRSyw5rIvrQ",
+	}
+
+	newVCBytes := func(t *testing.T, proof interface{}) []byte {
+		var raw map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &raw))
+		raw["proof"] = proof
+
+		vcBytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	decodeProof := func(t *testing.T, vc *Credential) interface{} {
+		vcBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		var raw map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(vcBytes, &raw))
+
+		return raw["proof"]
+	}
+
+	noProofCheck := func(opts *credentialOpts) {
+		opts.disabledProofCheck = true
+	}
+
+	t.Run("several proofs", func(t *testing.T) {
+		vc, _, err := NewCredential(newVCBytes(t, []Proof{proof1, proof2}), noProofCheck)
+		require.NoError(t, err)
+		require.Equal(t, []Proof{proof1, proof2}, vc.Proofs)
+
+		require.Equal(t, []interface{}{map[string]interface{}(proof1), map[string]interface{}(proof2)},
+			decodeProof(t, vc))
+	})
+
+	t.Run("proof set with single proof", func(t *testing.T) {
+		vc, _, err := NewCredential(newVCBytes(t, []Proof{proof1}), noProofCheck)
+		require.NoError(t, err)
+		require.Equal(t, []Proof{proof1}, vc.Proofs)
+
+		require.Equal(t, []interface{}{map[string]interface{}(proof1)}, decodeProof(t, vc))
+	})
+
+	t.Run("single proof", func(t *testing.T) {
+		vc, _, err := NewCredential(newVCBytes(t, proof1), noProofCheck)
+		require.NoError(t, err)
+		require.Equal(t, []Proof{proof1}, vc.Proofs)
+
+		require.Equal(t, map[string]interface{}(proof1), decodeProof(t, vc))
+
+		// the proof is serialized as array when another proof is added
+		vc.Proofs = append(vc.Proofs, proof2)
+		require.Len(t, decodeProof(t, vc), 2)
+	})
+}
+
+func TestCredential_SubjectCredential(t *testing.T) {
+	innerVC, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	innerVCBytes, err := innerVC.MarshalJSON()
+	require.NoError(t, err)
+
+	var innerVCMap map[string]interface{}
+	require.NoError(t, json.Unmarshal(innerVCBytes, &innerVCMap))
+
+	newOuterVC := func(t *testing.T, subject interface{}) *Credential {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.ID = "http://example.edu/credentials/endorsement"
+		vc.Subject = subject
+
+		vcBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		vc, _, err = NewCredential(vcBytes)
+		require.NoError(t, err)
+
+		return vc
+	}
+
+	t.Run("embedded credential", func(t *testing.T) {
+		vc := newOuterVC(t, innerVCMap)
+
+		subjectVC, err := vc.SubjectCredential()
+		require.NoError(t, err)
+		require.Equal(t, innerVC.ID, subjectVC.ID)
+		require.Equal(t, innerVC.Issuer, subjectVC.Issuer)
+	})
+
+	t.Run("embedded credential in subjects array", func(t *testing.T) {
+		vc := newOuterVC(t, []interface{}{innerVCMap})
+
+		subjectVC, err := vc.SubjectCredential()
+		require.NoError(t, err)
+		require.Equal(t, innerVC.ID, subjectVC.ID)
+	})
+
+	t.Run("JWT credential", func(t *testing.T) {
+		jwtClaims, err := innerVC.JWTClaims(true)
+		require.NoError(t, err)
+
+		vcJWT, err := jwtClaims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		vc := newOuterVC(t, vcJWT)
+
+		subjectVC, err := vc.SubjectCredential()
+		require.NoError(t, err)
+		require.Equal(t, innerVC.ID, subjectVC.ID)
+	})
+
+	t.Run("subject is not a credential", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		subjectVC, err := vc.SubjectCredential()
+		require.True(t, errors.Is(err, ErrSubjectNotCredential))
+		require.Nil(t, subjectVC)
+
+		vc.Subject = "did:example:ebfeb1f712ebc6f1c276e12ec21"
+		_, err = vc.SubjectCredential()
+		require.True(t, errors.Is(err, ErrSubjectNotCredential))
+
+		vc.Subject = map[string]interface{}{"type": "VerifiableCredential"}
+		_, err = vc.SubjectCredential()
+		require.True(t, errors.Is(err, ErrSubjectNotCredential))
+
+		vc.Subject = map[string]interface{}{"type": 1, "credentialSubject": "did:example:123"}
+		_, err = vc.SubjectCredential()
+		require.True(t, errors.Is(err, ErrSubjectNotCredential))
+
+		vc.Subject = map[string]interface{}{"type": []interface{}{"Other"}, "credentialSubject": "did:example:123"}
+		_, err = vc.SubjectCredential()
+		require.True(t, errors.Is(err, ErrSubjectNotCredential))
+	})
+
+	t.Run("no single subject", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.Subject = nil
+		_, err = vc.SubjectCredential()
+		require.EqualError(t, err, "subject credential: no subject is defined")
+
+		vc.Subject = []interface{}{innerVCMap, innerVCMap}
+		_, err = vc.SubjectCredential()
+		require.EqualError(t, err, "subject credential: exactly one subject must be defined")
+
+		vc.Subject = []map[string]interface{}{}
+		_, err = vc.SubjectCredential()
+		require.EqualError(t, err, "subject credential: exactly one subject must be defined")
+
+		vc.Subject = []map[string]interface{}{innerVCMap}
+		_, err = vc.SubjectCredential()
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid inner credential", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vc.Subject = map[string]interface{}{
+			"type":              "VerifiableCredential",
+			"credentialSubject": "did:example:123",
+		}
+
+		_, err = vc.SubjectCredential()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "subject credential:")
+	})
+}
+
+func TestCredential_WithUpdates(t *testing.T) {
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	t.Run("update is applied to the copy", func(t *testing.T) {
+		updatedVC, err := vc.WithUpdates(func(c *Credential) error {
+			c.Status = &TypedID{ID: "https://example.edu/status/25", Type: "CredentialStatusList2017"}
+			c.Evidence = append(c.Evidence, Evidence{"id": "https://example.edu/evidence/1", "type": "Evidence"})
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://example.edu/status/25", updatedVC.Status.ID)
+		require.Len(t, updatedVC.Evidence, 3)
+
+		// the original credential is untouched
+		require.Equal(t, "https://example.edu/status/24", vc.Status.ID)
+		require.Len(t, vc.Evidence, 2)
+
+		originalBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+		require.Equal(t, vcBytes, originalBytes)
+	})
+
+	t.Run("mutation error", func(t *testing.T) {
+		updatedVC, err := vc.WithUpdates(func(c *Credential) error {
+			return errors.New("mutation error")
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "mutation error")
+		require.Nil(t, updatedVC)
+	})
+
+	t.Run("updated credential is invalid", func(t *testing.T) {
+		updatedVC, err := vc.WithUpdates(func(c *Credential) error {
+			c.Subject = nil
+
+			return nil
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update credential")
+		require.Nil(t, updatedVC)
+		require.NotNil(t, vc.Subject)
+	})
+}
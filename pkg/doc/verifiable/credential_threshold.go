@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrProofThresholdNotMet is returned when the credential doesn't have enough proofs of distinct signers
+// (see AggregateProofs and WithProofThreshold).
+var ErrProofThresholdNotMet = errors.New("proof threshold is not met")
+
+// AggregatedProof is M-of-N multi-signature proof of the credential, i.e. the proofs of at least
+// Threshold distinct signers (verification methods) out of N ones.
+//
+// It is represented as the proof set: each proof is a regular (not chained) linked data proof
+// (e.g. Ed25519Signature2018) created by one of the signers over the credential without proofs
+// (see CreatePartialProof), so each proof is verified independently. The proofs don't define the threshold
+// as it is not covered by the signatures: Threshold is not kept in the credential once the proof is added,
+// the verifier supplies M and the N authorized signers by WithProofThreshold option.
+type AggregatedProof struct {
+	Threshold int
+	Proofs    []Proof
+}
+
+// CreatePartialProof creates the linked data proof of the credential without adding it to the credential,
+// i.e. the partial signature of one of the signers of M-of-N multi-signature proof (see AggregateProofs).
+// The existing proofs of the credential are not signed, the proof can't be chained.
+// The signer's key can be kept by legacy KMS (see legacykms.NewProofSigner).
+func (vc *Credential) CreatePartialProof(context *LinkedDataProofContext) (Proof, error) {
+	if context.ProofChain || context.AggregatedProof != nil {
+		return nil, errors.New("create partial proof: the proof can't be chained or aggregated")
+	}
+
+	unsigned := *vc
+	unsigned.Proofs = nil
+
+	vcBytes, err := unsigned.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("create partial proof: %w", err)
+	}
+
+	proofs, err := addLinkedDataProof(context, vcBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create partial proof: %w", err)
+	}
+
+	return proofs[0], nil
+}
+
+// AggregateProofs combines the partial proofs of the signers (see CreatePartialProof) into M-of-N
+// multi-signature proof, the proofs must be created by at least threshold distinct verification methods.
+// The proof is added to the credential by AddLinkedDataProof (see LinkedDataProofContext AggregatedProof).
+// The signatures of the proofs are not checked, they are verified when the credential is decoded.
+func AggregateProofs(threshold int, partials ...Proof) (*AggregatedProof, error) {
+	if threshold < 1 {
+		return nil, fmt.Errorf("aggregate proofs: invalid threshold %d", threshold)
+	}
+
+	signers := make(map[string]bool)
+
+	for i, p := range partials {
+		if p["previousProof"] != nil {
+			return nil, fmt.Errorf("aggregate proofs: proof %d is chained", i)
+		}
+
+		signer := proofSigner(p)
+		if signer == "" {
+			return nil, fmt.Errorf("aggregate proofs: proof %d has no verification method", i)
+		}
+
+		if signers[signer] {
+			return nil, fmt.Errorf("aggregate proofs: duplicate proof of %s", signer)
+		}
+
+		signers[signer] = true
+	}
+
+	if len(signers) < threshold {
+		return nil, fmt.Errorf("aggregate proofs: %w: %d of %d proofs", ErrProofThresholdNotMet,
+			len(signers), threshold)
+	}
+
+	return &AggregatedProof{Threshold: threshold, Proofs: partials}, nil
+}
+
+// WithProofThreshold option requires the embedded proofs of VC to be verified by at least m distinct keys
+// out of the authorized signers, i.e. M-of-N multi-signature proof (see AggregateProofs). The signers are
+// the IDs of the N keys as they are resolved for the proofs: the verification method of the proof
+// (e.g. "did:example:123#key-1") if PublicKeyFetcher is used, or the ID of the candidate key
+// if CandidateKeysFetcher is used. The threshold and the signers are not signed by the proofs,
+// so the verifier must supply them. All the proofs are verified, but only the proofs verified by the keys of
+// the signers are counted. ErrProofThresholdNotMet is returned if there are less distinct keys.
+func WithProofThreshold(m int, signers []string) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.proofThreshold = m
+		opts.proofSigners = signers
+	}
+}
+
+// checkProofThreshold checks that the proofs are verified by enough distinct keys of the authorized signers.
+func checkProofThreshold(keyIDs []string, vcOpts *credentialOpts) error {
+	if vcOpts.proofThreshold == 0 {
+		return nil
+	}
+
+	if len(vcOpts.proofSigners) < vcOpts.proofThreshold {
+		return fmt.Errorf("%w: %d authorized signers are less than threshold %d", ErrProofThresholdNotMet,
+			len(vcOpts.proofSigners), vcOpts.proofThreshold)
+	}
+
+	authorized := make(map[string]bool)
+
+	for _, signer := range vcOpts.proofSigners {
+		authorized[signer] = true
+	}
+
+	signers := make(map[string]bool)
+
+	for _, keyID := range keyIDs {
+		if authorized[keyID] {
+			signers[keyID] = true
+		}
+	}
+
+	if len(signers) < vcOpts.proofThreshold {
+		return fmt.Errorf("%w: %d of %d signers", ErrProofThresholdNotMet, len(signers), vcOpts.proofThreshold)
+	}
+
+	return nil
+}
+
+// proofSigner returns the verification method of the proof
+func proofSigner(p map[string]interface{}) string {
+	if vm := safeStringValue(p["verificationMethod"]); vm != "" {
+		return vm
+	}
+
+	return safeStringValue(p["creator"])
+}
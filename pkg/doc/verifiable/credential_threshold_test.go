@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
+)
+
+func TestCredential_ThresholdProof(t *testing.T) {
+	const signersNum = 3
+
+	pubKeys := make(map[string][]byte)
+	privKeys := make([]ed25519.PrivateKey, signersNum)
+	partials := make([]Proof, signersNum)
+	signers := make([]string, signersNum)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	for i := 0; i < signersNum; i++ {
+		pubKey, privKey, e := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, e)
+
+		keyID := fmt.Sprintf("#key-%d", i)
+		pubKeys[keyID] = pubKey
+		privKeys[i] = privKey
+		signers[i] = "did:example:signers" + keyID
+
+		partials[i], err = vc.CreatePartialProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite:                   newJSONSignatureSuite(mocksignature.NewEd25519Signer(privKey)),
+			VerificationMethod:      "did:example:signers" + keyID,
+		})
+		require.NoError(t, err)
+	}
+
+	require.Empty(t, vc.Proofs)
+
+	verifierSuite := newJSONSignatureSuite(nil)
+
+	pubKeyFetcher := func(_, keyID string) (interface{}, error) {
+		pubKey, ok := pubKeys[keyID]
+		if !ok {
+			return nil, errors.New("public key not found")
+		}
+
+		return pubKey, nil
+	}
+
+	t.Run("2 of 3 proofs", func(t *testing.T) {
+		aggregated, err := AggregateProofs(2, partials[0], partials[2])
+		require.NoError(t, err)
+
+		signedVC := *vc
+		require.NoError(t, signedVC.AddLinkedDataProof(&LinkedDataProofContext{AggregatedProof: aggregated}))
+		require.Len(t, signedVC.Proofs, 2)
+
+		vcBytes, err := signedVC.MarshalJSON()
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(2, signers))
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(3, signers))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+
+		// the proof of the signer which is not authorized is not counted
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(2, signers[:2]))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+		require.Contains(t, err.Error(), "1 of 2 signers")
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(2, signers[:1]))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+		require.Contains(t, err.Error(), "1 authorized signers are less than threshold 2")
+	})
+
+	t.Run("proofs of the same key", func(t *testing.T) {
+		// the proof refers to another signer, but it is made by the key of the first signer
+		forged, err := vc.CreatePartialProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite:                   newJSONSignatureSuite(mocksignature.NewEd25519Signer(privKeys[0])),
+			VerificationMethod:      signers[1],
+		})
+		require.NoError(t, err)
+
+		aggregated, err := AggregateProofs(2, partials[0], forged)
+		require.NoError(t, err)
+
+		signedVC := *vc
+		require.NoError(t, signedVC.AddLinkedDataProof(&LinkedDataProofContext{AggregatedProof: aggregated}))
+
+		vcBytes, err := signedVC.MarshalJSON()
+		require.NoError(t, err)
+
+		candidateKeysFetcher := func(_, _ string) ([]*verifier.CandidateKey, error) {
+			keys := make([]*verifier.CandidateKey, signersNum)
+			for i := range keys {
+				keys[i] = &verifier.CandidateKey{ID: signers[i], Value: pubKeys[fmt.Sprintf("#key-%d", i)]}
+			}
+
+			return keys, nil
+		}
+
+		_, _, err = NewCredential(vcBytes, WithCandidateKeysFetcher(candidateKeysFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(2, signers))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+		require.Contains(t, err.Error(), "1 of 2 signers")
+	})
+
+	t.Run("tampered proof", func(t *testing.T) {
+		aggregated, err := AggregateProofs(2, partials[0], partials[1])
+		require.NoError(t, err)
+
+		signedVC := *vc
+		signedVC.ID = "http://example.edu/credentials/tampered"
+		require.NoError(t, signedVC.AddLinkedDataProof(&LinkedDataProofContext{AggregatedProof: aggregated}))
+
+		vcBytes, err := signedVC.MarshalJSON()
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithEmbeddedSignatureSuites(verifierSuite), WithProofThreshold(2, signers))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check linked data proof")
+	})
+
+	t.Run("credential without proofs", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(validCredential), WithProofThreshold(1, signers))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+	})
+
+	t.Run("chained partial proof", func(t *testing.T) {
+		_, err := vc.CreatePartialProof(&LinkedDataProofContext{ProofChain: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't be chained")
+	})
+}
+
+func TestAggregateProofs(t *testing.T) {
+	proof := func(verificationMethod string) Proof {
+		return Proof{"type": "Ed25519Signature2018", "verificationMethod": verificationMethod}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		aggregated, err := AggregateProofs(2, proof("did:example:a#key"), proof("did:example:b#key"))
+		require.NoError(t, err)
+		require.Equal(t, 2, aggregated.Threshold)
+		require.Len(t, aggregated.Proofs, 2)
+
+		aggregated, err = AggregateProofs(1, Proof{"creator": "did:example:a#key"})
+		require.NoError(t, err)
+		require.Len(t, aggregated.Proofs, 1)
+	})
+
+	t.Run("threshold is not met", func(t *testing.T) {
+		_, err := AggregateProofs(3, proof("did:example:a#key"), proof("did:example:b#key"))
+		require.True(t, errors.Is(err, ErrProofThresholdNotMet))
+	})
+
+	t.Run("invalid proofs", func(t *testing.T) {
+		_, err := AggregateProofs(0)
+		require.EqualError(t, err, "aggregate proofs: invalid threshold 0")
+
+		_, err = AggregateProofs(2, proof("did:example:a#key"), proof("did:example:a#key"))
+		require.EqualError(t, err, "aggregate proofs: duplicate proof of did:example:a#key")
+
+		_, err = AggregateProofs(1, Proof{"type": "Ed25519Signature2018"})
+		require.EqualError(t, err, "aggregate proofs: proof 0 has no verification method")
+
+		chained := proof("did:example:a#key")
+		chained["previousProof"] = "urn:uuid:1"
+
+		_, err = AggregateProofs(1, chained)
+		require.EqualError(t, err, "aggregate proofs: proof 0 is chained")
+	})
+}
+
+// jsonSignatureSuite is Ed25519Signature2018 suite which canonicalizes the document as JSON,
+// so the documents are signed without loading JSON-LD contexts
+type jsonSignatureSuite struct {
+	*ed25519signature2018.SignatureSuite
+}
+
+func newJSONSignatureSuite(s *mocksignature.Ed25519Signer) *jsonSignatureSuite {
+	if s == nil {
+		return &jsonSignatureSuite{ed25519signature2018.New()}
+	}
+
+	return &jsonSignatureSuite{ed25519signature2018.New(ed25519signature2018.WithSigner(s))}
+}
+
+func (s *jsonSignatureSuite) GetCanonicalDocument(doc map[string]interface{}) ([]byte, error) {
+	return json.Marshal(doc)
+}
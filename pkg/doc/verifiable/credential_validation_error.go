@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CredentialValidationError is returned when the Verifiable Credential is not valid.
+// It accumulates all the structural problems found (see WithFailFast to stop on the first one).
+type CredentialValidationError struct {
+	errs []error
+}
+
+// Error returns the description of all the problems found.
+func (e *CredentialValidationError) Error() string {
+	errMsg := "verifiable credential is not valid:\n"
+	for _, err := range e.errs {
+		errMsg += fmt.Sprintf("- %s\n", err)
+	}
+
+	return errMsg
+}
+
+// Errors returns the problems found.
+func (e *CredentialValidationError) Errors() []error {
+	errs := make([]error, len(e.errs))
+	copy(errs, e.errs)
+
+	return errs
+}
+
+// joinValidationErrors combines the errors (nil ones are skipped) into a single one.
+// The problems of *CredentialValidationError are merged. The only error is returned as is.
+func joinValidationErrors(errs ...error) error {
+	var nonNil []error
+
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+
+	joined := &CredentialValidationError{}
+
+	for _, err := range nonNil {
+		var validationErr *CredentialValidationError
+		if errors.As(err, &validationErr) {
+			joined.errs = append(joined.errs, validationErr.errs...)
+		} else {
+			joined.errs = append(joined.errs, err)
+		}
+	}
+
+	return joined
+}
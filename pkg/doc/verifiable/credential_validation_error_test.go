@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialValidationError(t *testing.T) {
+	const invalidCredential = `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "type": 1,
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "yesterday"
+}`
+
+	t.Run("all structural problems are reported", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(invalidCredential))
+		require.Error(t, err)
+
+		var validationErr *CredentialValidationError
+		require.True(t, errors.As(err, &validationErr))
+
+		errs := validationErr.Errors()
+		require.Len(t, errs, 5)
+		require.Contains(t, errs[0].Error(), "unmarshal new credential")
+
+		// JSON schema errors are reported in no particular order
+		schemaErrs := make([]string, 0, len(errs)-1)
+		for _, e := range errs[1:] {
+			schemaErrs = append(schemaErrs, e.Error())
+		}
+
+		requireContainsError(t, schemaErrs, "credentialSubject is required")
+		requireContainsError(t, schemaErrs, "type: Must validate one and only one schema")
+		requireContainsError(t, schemaErrs, "type: Invalid type")
+		requireContainsError(t, schemaErrs, "issuanceDate: Does not match format 'date-time'")
+
+		require.Contains(t, err.Error(), "verifiable credential is not valid:\n- unmarshal new credential")
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		_, _, err := NewCredential([]byte(invalidCredential), WithFailFast())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal new credential")
+
+		var validationErr *CredentialValidationError
+		require.False(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("base context constraints", func(t *testing.T) {
+		vcMap, err := toMap(validCredential)
+		require.NoError(t, err)
+
+		vcMap["@context"] = []interface{}{baseContext, "https://www.w3.org/2018/credentials/examples/v1"}
+		vcMap["type"] = []interface{}{vcType, "UniversityDegreeCredential"}
+		delete(vcMap, "credentialSubject")
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextValidation())
+		require.Error(t, err)
+
+		var validationErr *CredentialValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Errors(), 3)
+		require.EqualError(t, validationErr.Errors()[0], "violated type constraint: not base only type defined")
+		require.EqualError(t, validationErr.Errors()[1],
+			"violated @context constraint: not base only @context defined")
+		require.Contains(t, validationErr.Errors()[2].Error(), "credentialSubject is required")
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextValidation(), WithFailFast())
+		require.EqualError(t, err, "violated type constraint: not base only type defined")
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextExtendedValidation(nil, nil))
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Errors(), 3)
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextExtendedValidation(nil, nil), WithFailFast())
+		require.EqualError(t, err, "not allowed @context: https://www.w3.org/2018/credentials/examples/v1")
+	})
+
+	t.Run("custom validators", func(t *testing.T) {
+		subjectValidator := func(vc *Credential) error {
+			if _, ok := vc.Subject.(map[string]interface{})["nationalID"]; !ok {
+				return errors.New("subject national ID is missing")
+			}
+
+			return nil
+		}
+
+		issuerValidator := func(vc *Credential) error {
+			return fmt.Errorf("issuer %s is not trusted", vc.Issuer.ID)
+		}
+
+		var calls int
+
+		passingValidator := func(vc *Credential) error {
+			calls++
+
+			return nil
+		}
+
+		_, _, err := NewCredential([]byte(validCredential), WithCustomValidator(passingValidator))
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+
+		_, _, err = NewCredential([]byte(validCredential), WithCustomValidator(subjectValidator))
+		require.EqualError(t, err, "custom validation: subject national ID is missing")
+
+		_, _, err = NewCredential([]byte(validCredential),
+			WithCustomValidator(subjectValidator), WithCustomValidator(passingValidator),
+			WithCustomValidator(issuerValidator))
+		require.Error(t, err)
+
+		var validationErr *CredentialValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Errors(), 2)
+		require.EqualError(t, validationErr.Errors()[0], "custom validation: subject national ID is missing")
+		require.EqualError(t, validationErr.Errors()[1],
+			"custom validation: issuer did:example:76e12ec712ebc6f1c221ebfeb1f is not trusted")
+
+		_, _, err = NewCredential([]byte(validCredential),
+			WithCustomValidator(subjectValidator), WithCustomValidator(issuerValidator), WithFailFast())
+		require.EqualError(t, err, "custom validation: subject national ID is missing")
+
+		// the errors of the custom validators are reported together with the structural problems
+		vcMap, err := toMap(validCredential)
+		require.NoError(t, err)
+
+		vcMap["type"] = []interface{}{vcType, "UniversityDegreeCredential"}
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextValidation(), WithCustomValidator(issuerValidator))
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Errors(), 2)
+		require.EqualError(t, validationErr.Errors()[0], "violated type constraint: not base only type defined")
+
+		_, _, err = NewCredential(vcBytes, WithBaseContextValidation(), WithCustomValidator(issuerValidator),
+			WithFailFast())
+		require.EqualError(t, err, "violated type constraint: not base only type defined")
+	})
+}
+
+func Test_joinValidationErrors(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+
+	require.NoError(t, joinValidationErrors())
+	require.NoError(t, joinValidationErrors(nil, nil))
+	require.Equal(t, err1, joinValidationErrors(nil, err1))
+
+	err := joinValidationErrors(err1, &CredentialValidationError{errs: []error{err2, err3}})
+
+	var validationErr *CredentialValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []error{err1, err2, err3}, validationErr.Errors())
+	require.Equal(t, "verifiable credential is not valid:\n- error 1\n- error 2\n- error 3\n", err.Error())
+}
+
+// requireContainsError checks that one of the error messages contains the given substring.
+func requireContainsError(t *testing.T, errMsgs []string, substr string) {
+	for _, msg := range errMsgs {
+		if strings.Contains(msg, substr) {
+			return
+		}
+	}
+
+	require.Fail(t, "error is not reported", "%q is not found in %q", substr, errMsgs)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCredentialNotValid is returned if VC is not valid yet or has expired (see WithValidityPeriodCheck).
+var ErrCredentialNotValid = errors.New("credential is not valid at this time")
+
+// WithValidityPeriodCheck option enables the check that VC is valid at the time of decoding, i.e. the time
+// (see WithClock) is not before issuanceDate (validFrom) and not after expirationDate (validUntil).
+// ErrCredentialNotValid is returned otherwise.
+func WithValidityPeriodCheck() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.validityPeriodCheck = true
+	}
+}
+
+// checkValidityPeriod checks that VC is valid at the given time.
+func checkValidityPeriod(vc *Credential, now time.Time) error {
+	if vc.Issued != nil && now.Before(*vc.Issued) {
+		return fmt.Errorf("valid from %s: %w", vc.Issued.Format(time.RFC3339), ErrCredentialNotValid)
+	}
+
+	if vc.Expired != nil && now.After(*vc.Expired) {
+		return fmt.Errorf("expired at %s: %w", vc.Expired.Format(time.RFC3339), ErrCredentialNotValid)
+	}
+
+	return nil
+}
+
+// validityFields keeps the names of the fields VC was decoded with, so VC is serialized with the same
+// names (e.g. the signed VC of VC Data Model 1.1 with validFrom is not changed by the round trip).
+type validityFields struct {
+	issuanceDate   bool
+	validFrom      bool
+	expirationDate bool
+	validUntil     bool
+}
+
+// validityPeriod returns the dates VC is valid from and until. VC Data Model 2.0 renames issuanceDate
+// and expirationDate to validFrom and validUntil, either of them is accepted regardless of the context.
+// Both names of the date may be present only if they have the same value.
+func (rc *rawCredential) validityPeriod() (*rawTime, *rawTime, validityFields, error) {
+	fields := validityFields{
+		issuanceDate:   rc.Issued != nil,
+		validFrom:      rc.ValidFrom != nil,
+		expirationDate: rc.Expired != nil,
+		validUntil:     rc.ValidUntil != nil,
+	}
+
+	issued, err := oneOfDates(rc.Issued, rc.ValidFrom, "issuanceDate", "validFrom")
+	if err != nil {
+		return nil, nil, validityFields{}, err
+	}
+
+	expired, err := oneOfDates(rc.Expired, rc.ValidUntil, "expirationDate", "validUntil")
+	if err != nil {
+		return nil, nil, validityFields{}, err
+	}
+
+	return issued, expired, fields, nil
+}
+
+// oneOfDates returns the date defined by either of the names, it fails if the dates are different.
+func oneOfDates(v1, v2 *rawTime, v1Name, v2Name string) (*rawTime, error) {
+	if v1 == nil {
+		return v2, nil
+	}
+
+	if v2 != nil && v1.raw != v2.raw {
+		return nil, fmt.Errorf("%s and %s are different", v1Name, v2Name)
+	}
+
+	return v1, nil
+}
+
+// setValidityPeriod sets the dates VC is valid from and until with the names VC was decoded with.
+// validFrom and validUntil are used for the new dates if VC has the context of VC Data Model 2.0,
+// issuanceDate and expirationDate otherwise.
+func (rc *rawCredential) setValidityPeriod(vc *Credential) {
+	issued, expired := newRawTime(vc.Issued, vc.issuedRaw), newRawTime(vc.Expired, vc.expiredRaw)
+	fields, v2 := vc.validityFields, hasV2Context(vc.Context)
+
+	if !fields.issuanceDate && !fields.validFrom {
+		fields.issuanceDate, fields.validFrom = !v2, v2
+	}
+
+	if !fields.expirationDate && !fields.validUntil {
+		fields.expirationDate, fields.validUntil = !v2, v2
+	}
+
+	if fields.issuanceDate {
+		rc.Issued = issued
+	}
+
+	if fields.validFrom {
+		rc.ValidFrom = issued
+	}
+
+	if fields.expirationDate {
+		rc.Expired = expired
+	}
+
+	if fields.validUntil {
+		rc.ValidUntil = expired
+	}
+}
+
+// hasV2Context checks whether VC has the base context of VC Data Model 2.0.
+func hasV2Context(context Contexts) bool {
+	for _, c := range context {
+		if c == baseContextV2 {
+			return true
+		}
+	}
+
+	return false
+}
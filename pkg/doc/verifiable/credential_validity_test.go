@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+)
+
+func TestCredential_ValidityPeriod(t *testing.T) {
+	vcWithDates := func(t *testing.T, context interface{}, dates map[string]interface{}) []byte {
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+		vcMap["@context"] = context
+		delete(vcMap, "issuanceDate")
+		delete(vcMap, "expirationDate")
+
+		for k, v := range dates {
+			vcMap[k] = v
+		}
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	marshalMap := func(t *testing.T, vc *Credential) map[string]interface{} {
+		vcBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(vcBytes, &vcMap))
+
+		return vcMap
+	}
+
+	validFrom := time.Date(2020, 1, 1, 19, 23, 24, 0, time.UTC)
+	validUntil := time.Date(2030, 1, 1, 19, 23, 24, 0, time.UTC)
+
+	t.Run("VC Data Model 2.0", func(t *testing.T) {
+		vc, _, err := NewCredential(vcWithDates(t, baseContextV2, map[string]interface{}{
+			"validFrom":  "2020-01-01T19:23:24Z",
+			"validUntil": "2030-01-01T19:23:24Z",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, validFrom, *vc.Issued)
+		require.Equal(t, validUntil, *vc.Expired)
+		require.Empty(t, vc.CustomFields)
+
+		vcMap := marshalMap(t, vc)
+		require.Equal(t, "2020-01-01T19:23:24Z", vcMap["validFrom"])
+		require.Equal(t, "2030-01-01T19:23:24Z", vcMap["validUntil"])
+		require.NotContains(t, vcMap, "issuanceDate")
+		require.NotContains(t, vcMap, "expirationDate")
+	})
+
+	t.Run("VC Data Model 1.1", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vcMap := marshalMap(t, vc)
+		require.Contains(t, vcMap, "issuanceDate")
+		require.Contains(t, vcMap, "expirationDate")
+		require.NotContains(t, vcMap, "validFrom")
+		require.NotContains(t, vcMap, "validUntil")
+	})
+
+	t.Run("either set of names is accepted", func(t *testing.T) {
+		vc, _, err := NewCredential(vcWithDates(t, baseContext, map[string]interface{}{
+			"validFrom": "2020-01-01T19:23:24Z",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, validFrom, *vc.Issued)
+		require.Nil(t, vc.Expired)
+		require.Contains(t, marshalMap(t, vc), "validFrom")
+		require.NotContains(t, marshalMap(t, vc), "issuanceDate")
+
+		vc, _, err = NewCredential(vcWithDates(t, []interface{}{baseContextV2}, map[string]interface{}{
+			"issuanceDate":   "2020-01-01T19:23:24Z",
+			"expirationDate": "2030-01-01T19:23:24Z",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, validFrom, *vc.Issued)
+		require.Equal(t, validUntil, *vc.Expired)
+		require.Contains(t, marshalMap(t, vc), "issuanceDate")
+		require.NotContains(t, marshalMap(t, vc), "validFrom")
+	})
+
+	t.Run("decoded names are kept", func(t *testing.T) {
+		vcBytes := vcWithDates(t, baseContext, map[string]interface{}{
+			"issuanceDate": "2020-01-01T19:23:24Z",
+			"validFrom":    "2020-01-01T19:23:24Z",
+			"validUntil":   "2030-01-01T19:23:24Z",
+		})
+
+		vc, _, err := NewCredential(vcBytes)
+		require.NoError(t, err)
+
+		vcMap := marshalMap(t, vc)
+		require.Equal(t, "2020-01-01T19:23:24Z", vcMap["issuanceDate"])
+		require.Equal(t, "2020-01-01T19:23:24Z", vcMap["validFrom"])
+		require.Equal(t, "2030-01-01T19:23:24Z", vcMap["validUntil"])
+		require.NotContains(t, vcMap, "expirationDate")
+
+		// the dates set after decoding are serialized with the decoded names
+		vc.Expired = &validFrom
+		require.Equal(t, "2020-01-01T19:23:24Z", marshalMap(t, vc)["validUntil"])
+
+		// the new dates are serialized with the names of the context
+		vc, _, err = NewCredential(vcWithDates(t, baseContextV2, map[string]interface{}{
+			"issuanceDate": "2020-01-01T19:23:24Z",
+		}))
+		require.NoError(t, err)
+
+		vc.Expired = &validUntil
+
+		vcMap = marshalMap(t, vc)
+		require.Contains(t, vcMap, "issuanceDate")
+		require.Contains(t, vcMap, "validUntil")
+	})
+
+	t.Run("conflicting dates", func(t *testing.T) {
+		_, _, err := NewCredential(vcWithDates(t, baseContextV2, map[string]interface{}{
+			"issuanceDate": "2020-01-01T19:23:24Z",
+			"validFrom":    "2021-01-01T19:23:24Z",
+		}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issuanceDate and validFrom are different")
+
+		_, _, err = NewCredential(vcWithDates(t, baseContextV2, map[string]interface{}{
+			"validFrom":      "2020-01-01T19:23:24Z",
+			"expirationDate": "2030-01-01T19:23:24Z",
+			"validUntil":     "2031-01-01T19:23:24Z",
+		}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expirationDate and validUntil are different")
+	})
+
+	t.Run("issued date is required", func(t *testing.T) {
+		_, _, err := NewCredential(vcWithDates(t, baseContextV2, nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issuanceDate is required")
+
+		_, _, err = NewCredential(vcWithDates(t, baseContextV2, map[string]interface{}{"validFrom": "not a date"}))
+		require.Error(t, err)
+	})
+
+	t.Run("validity period check", func(t *testing.T) {
+		vcBytes := vcWithDates(t, baseContextV2, map[string]interface{}{
+			"validFrom":  "2020-01-01T19:23:24Z",
+			"validUntil": "2030-01-01T19:23:24Z",
+		})
+
+		_, _, err := NewCredential(vcBytes, WithValidityPeriodCheck(),
+			WithClock(clock.Fixed(validFrom.Add(time.Hour))))
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, WithValidityPeriodCheck(),
+			WithClock(clock.Fixed(validFrom.Add(-time.Hour))))
+		require.True(t, errors.Is(err, ErrCredentialNotValid))
+		require.Contains(t, err.Error(), "valid from 2020-01-01T19:23:24Z")
+
+		_, _, err = NewCredential(vcBytes, WithValidityPeriodCheck(),
+			WithClock(clock.Fixed(validUntil.Add(time.Hour))))
+		require.True(t, errors.Is(err, ErrCredentialNotValid))
+		require.Contains(t, err.Error(), "expired at 2030-01-01T19:23:24Z")
+
+		// the period is not checked by default
+		_, _, err = NewCredential(vcBytes, WithClock(clock.Fixed(validUntil.Add(time.Hour))))
+		require.NoError(t, err)
+	})
+
+	t.Run("base context of VC Data Model 2.0", func(t *testing.T) {
+		vcBytes := vcWithDates(t, []interface{}{baseContextV2, map[string]interface{}{"name": "http://schema.org/name"}},
+			map[string]interface{}{"validFrom": "2020-01-01T19:23:24Z"})
+
+		vc, _, err := NewCredential(vcBytes, WithStrictBaseContext())
+		require.NoError(t, err)
+
+		vc.Context = Contexts{"https://www.w3.org/2018/credentials/examples/v1", baseContextV2}
+		vc.EnsureBaseContext()
+		require.Equal(t, Contexts{baseContextV2, "https://www.w3.org/2018/credentials/examples/v1"}, vc.Context)
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const dataURIScheme = "data:"
+
+// nolint:gochecknoglobals
+var supportedDataURIMediaTypes = map[string]bool{
+	"":                       true,
+	"application/vc+jwt":     true,
+	"application/vc+ld+json": true,
+	"application/ld+json":    true,
+	"application/json":       true,
+	"application/jwt":        true,
+	"text/plain":             true,
+}
+
+// unwrapData decodes the credential (or presentation) delivered as a data URI
+// (e.g. "data:application/vc+jwt;base64,...") or as base64 encoded JSON or JWT.
+// Other data is returned as is.
+func unwrapData(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte(dataURIScheme)) {
+		return decodeDataURI(string(trimmed))
+	}
+
+	if len(trimmed) == 0 || trimmed[0] == '{' || isJWS(trimmed) || isJWTUnsecured(trimmed) {
+		return data, nil
+	}
+
+	if decoded, err := decodeBase64(string(trimmed)); err == nil && (json.Valid(decoded) ||
+		isJWS(decoded) || isJWTUnsecured(decoded)) {
+		return decoded, nil
+	}
+
+	return data, nil
+}
+
+// decodeDataURI decodes data URI of the form "data:[<media type>][;base64],<data>" (RFC 2397).
+func decodeDataURI(uri string) ([]byte, error) {
+	commaIndex := strings.Index(uri, ",")
+	if commaIndex < 0 {
+		return nil, errors.New("malformed data URI: missing comma")
+	}
+
+	header, payload := uri[len(dataURIScheme):commaIndex], uri[commaIndex+1:]
+
+	params := strings.Split(header, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+	if !supportedDataURIMediaTypes[mediaType] {
+		return nil, fmt.Errorf("unsupported data URI media type: %s", mediaType)
+	}
+
+	isBase64 := false
+
+	for _, param := range params[1:] {
+		if param == "base64" {
+			isBase64 = true
+		}
+	}
+
+	if isBase64 {
+		decoded, err := decodeBase64(payload)
+		if err != nil {
+			return nil, fmt.Errorf("malformed data URI: base64 decoding: %w", err)
+		}
+
+		return decoded, nil
+	}
+
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("malformed data URI: percent decoding: %w", err)
+	}
+
+	return []byte(decoded), nil
+}
+
+// decodeBase64 decodes standard or URL-safe base64 with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	encodings := []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	}
+
+	var err error
+
+	for _, encoding := range encodings {
+		var decoded []byte
+
+		decoded, err = encoding.DecodeString(s)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, err
+}
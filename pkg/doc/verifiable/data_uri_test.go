@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_unwrapData(t *testing.T) {
+	r := require.New(t)
+
+	const jsonData = `{"id":"http://example.edu/credentials/1872"}`
+
+	unsecuredJWT := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(jsonData)) + "."
+
+	t.Run("data is returned as is", func(t *testing.T) {
+		for _, data := range []string{jsonData, unsecuredJWT, "", "not a credential"} {
+			unwrapped, err := unwrapData([]byte(data))
+			r.NoError(err)
+			r.Equal(data, string(unwrapped))
+		}
+	})
+
+	t.Run("base64 data URI", func(t *testing.T) {
+		unwrapped, err := unwrapData([]byte("data:application/vc+jwt;base64," +
+			base64.StdEncoding.EncodeToString([]byte(unsecuredJWT))))
+		r.NoError(err)
+		r.Equal(unsecuredJWT, string(unwrapped))
+
+		unwrapped, err = unwrapData([]byte("data:application/vc+ld+json;charset=utf-8;base64," +
+			base64.RawURLEncoding.EncodeToString([]byte(jsonData))))
+		r.NoError(err)
+		r.Equal(jsonData, string(unwrapped))
+	})
+
+	t.Run("percent encoded data URI", func(t *testing.T) {
+		unwrapped, err := unwrapData([]byte("data:application/json," + url.PathEscape(jsonData)))
+		r.NoError(err)
+		r.Equal(jsonData, string(unwrapped))
+
+		unwrapped, err = unwrapData([]byte("data:," + jsonData))
+		r.NoError(err)
+		r.Equal(jsonData, string(unwrapped))
+	})
+
+	t.Run("base64 encoded data", func(t *testing.T) {
+		unwrapped, err := unwrapData([]byte(base64.RawURLEncoding.EncodeToString([]byte(unsecuredJWT))))
+		r.NoError(err)
+		r.Equal(unsecuredJWT, string(unwrapped))
+
+		unwrapped, err = unwrapData([]byte(base64.StdEncoding.EncodeToString([]byte(jsonData))))
+		r.NoError(err)
+		r.Equal(jsonData, string(unwrapped))
+	})
+
+	t.Run("malformed data URI", func(t *testing.T) {
+		unwrapped, err := unwrapData([]byte("data:application/vc+jwt;base64"))
+		r.Error(err)
+		r.EqualError(err, "malformed data URI: missing comma")
+		r.Nil(unwrapped)
+
+		unwrapped, err = unwrapData([]byte("data:application/vc+jwt;base64,!not base64!"))
+		r.Error(err)
+		r.Contains(err.Error(), "malformed data URI: base64 decoding")
+		r.Nil(unwrapped)
+
+		unwrapped, err = unwrapData([]byte("data:application/json,%zz"))
+		r.Error(err)
+		r.Contains(err.Error(), "malformed data URI: percent decoding")
+		r.Nil(unwrapped)
+	})
+
+	t.Run("unsupported data URI media type", func(t *testing.T) {
+		unwrapped, err := unwrapData([]byte("data:image/png;base64,iVBORw0KGgo="))
+		r.Error(err)
+		r.EqualError(err, "unsupported data URI media type: image/png")
+		r.Nil(unwrapped)
+	})
+}
+
+func TestNewCredentialFromDataURI(t *testing.T) {
+	r := require.New(t)
+
+	vc, vcBytes, err := NewCredential([]byte("data:application/vc+ld+json;base64,"+
+		base64.StdEncoding.EncodeToString([]byte(validCredential))), WithNoCustomSchemaCheck())
+	r.NoError(err)
+	r.NotNil(vc)
+	r.JSONEq(validCredential, string(vcBytes))
+
+	vc, _, err = NewCredential([]byte("data:text/html,<p>credential</p>"))
+	r.Error(err)
+	r.Contains(err.Error(), "unsupported data URI media type: text/html")
+	r.Nil(vc)
+}
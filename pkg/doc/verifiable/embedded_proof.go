@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type embeddedProofType int
@@ -17,23 +18,41 @@ const (
 	linkedDataProof embeddedProofType = iota
 )
 
+// ErrUnsupportedProofType is returned when an embedded proof has a type which is not supported
+// by the signature suites defined for the proof check.
+var ErrUnsupportedProofType = errors.New("unsupported proof type")
+
+// ErrProofChallengeMismatch is returned when the challenge of an embedded proof does not match the expected one
+// or there is no proof to carry the expected challenge.
+var ErrProofChallengeMismatch = errors.New("proof challenge mismatch")
+
+// ErrProofDomainMismatch is returned when the domain of an embedded proof does not match the expected one.
+var ErrProofDomainMismatch = errors.New("proof domain mismatch")
+
+// ErrProofDateInconsistent is returned when the proof of VC was created outside of its validity window,
+// i.e. before the issuance date or after the expiration date (see WithProofDateConsistency).
+var ErrProofDateInconsistent = errors.New("proof date is inconsistent with credential validity")
+
 // nolint:gochecknoglobals
 var proofTypesMapping = map[string]embeddedProofType{
-	"Ed25519Signature2018": linkedDataProof,
+	ed25519Signature2018: linkedDataProof,
+	ed25519Signature2020: linkedDataProof,
 }
 
-func parseEmbeddedProof(proofMap map[string]interface{}) (embeddedProofType, error) {
+func parseEmbeddedProof(proofMap map[string]interface{}) (embeddedProofType, string, error) {
 	proofType, ok := proofMap["type"]
 	if !ok {
-		return -1, errors.New("proof type is missing")
+		return -1, "", errors.New("proof type is missing")
 	}
 
-	embeddedProofType, ok := proofTypesMapping[safeStringValue(proofType)]
+	proofTypeStr := safeStringValue(proofType)
+
+	embeddedProofType, ok := proofTypesMapping[proofTypeStr]
 	if !ok {
-		return -1, fmt.Errorf("unsupported proof type: %s", proofType)
+		return -1, "", fmt.Errorf("%w: %s", ErrUnsupportedProofType, proofTypeStr)
 	}
 
-	return embeddedProofType, nil
+	return embeddedProofType, proofTypeStr, nil
 }
 
 func checkEmbeddedProof(docBytes []byte, vcOpts *credentialOpts) ([]byte, error) {
@@ -50,30 +69,185 @@ func checkEmbeddedProof(docBytes []byte, vcOpts *credentialOpts) ([]byte, error)
 
 	proofElement, ok := jsonldDoc["proof"]
 	if !ok || proofElement == nil {
+		if err = checkProofThreshold(nil, vcOpts); err != nil {
+			return nil, fmt.Errorf("check embedded proof: %w", err)
+		}
+
+		if err = checkProofsChallengeAndDomain(nil, vcOpts.proofChallenge, vcOpts.proofDomain); err != nil {
+			return nil, fmt.Errorf("check embedded proof: %w", err)
+		}
+
 		// do not make a check if there is no proof defined as proof presence is not mandatory
 		return docBytes, nil
 	}
 
-	proofMap, ok := proofElement.(map[string]interface{})
-	if !ok {
-		return nil, errors.New("check embedded proof: expecting [string]interface{}")
+	proofMaps, err := getProofMaps(proofElement)
+	if err != nil {
+		return nil, fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	if err = checkProofMaps(proofMaps, vcOpts); err != nil {
+		return nil, fmt.Errorf("check embedded proof: %w", err)
 	}
 
-	proofType, err := parseEmbeddedProof(proofMap)
+	// all the proofs are verified at once, including the order of the chained ones
+	err = checkCachedProof(docBytes, vcOpts, func(opts *credentialOpts) error {
+		keyIDs, verifyErr := verifyEmbeddedProofs(docBytes, opts)
+		if verifyErr != nil {
+			return verifyErr
+		}
+
+		// the threshold counts the keys which verified the proofs, not the keys the proofs refer to
+		return checkProofThreshold(keyIDs, opts)
+	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	return docBytes, nil
+}
+
+// verifyEmbeddedProofs verifies the embedded linked data proofs and returns the IDs of the keys which verified them.
+func verifyEmbeddedProofs(docBytes []byte, vcOpts *credentialOpts) ([]string, error) {
+	if vcOpts.candidateKeysFetcher != nil {
+		return checkLinkedDataProofWithCandidateKeys(docBytes, vcOpts.ldpSuites, vcOpts.candidateKeysFetcher)
+	}
+
+	if vcOpts.publicKeyFetcher == nil {
+		return nil, errors.New("check linked data proof: public key fetcher is not defined")
+	}
+
+	return verifyLinkedDataProof(docBytes, vcOpts.ldpSuites, &keyResolverAdapter{vcOpts.publicKeyFetcher})
+}
+
+// checkProofMaps checks the embedded proofs before their signatures are verified.
+func checkProofMaps(proofMaps []map[string]interface{}, vcOpts *credentialOpts) error {
+	for _, proofMap := range proofMaps {
+		if err := checkProofType(proofMap, vcOpts); err != nil {
+			return err
+		}
+	}
+
+	return checkProofsChallengeAndDomain(proofMaps, vcOpts.proofChallenge, vcOpts.proofDomain)
+}
+
+// getProofMaps returns the embedded proof(s) as a list of JSON objects.
+func getProofMaps(proofElement interface{}) ([]map[string]interface{}, error) {
+	switch p := proofElement.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{p}, nil
+
+	case []interface{}:
+		if len(p) == 0 {
+			return nil, errors.New("expecting at least one proof")
+		}
+
+		proofMaps := make([]map[string]interface{}, len(p))
+
+		for i := range p {
+			proofMap, ok := p[i].(map[string]interface{})
+			if !ok {
+				return nil, errors.New("expecting [string]interface{}")
+			}
+
+			proofMaps[i] = proofMap
+		}
+
+		return proofMaps, nil
+
+	default:
+		return nil, errors.New("expecting [string]interface{}")
+	}
+}
+
+// checkProofType checks that the proof is of a type supported by the signature suites.
+func checkProofType(proofMap map[string]interface{}, vcOpts *credentialOpts) error {
+	proofType, proofTypeStr, err := parseEmbeddedProof(proofMap)
+	if err != nil {
+		return err
 	}
 
 	switch proofType {
 	case linkedDataProof:
-		err = checkLinkedDataProof(docBytes, vcOpts.ldpSuite, vcOpts.publicKeyFetcher)
+		if !acceptsProofType(vcOpts.ldpSuites, proofTypeStr) {
+			return fmt.Errorf("%w: %s", ErrUnsupportedProofType, proofTypeStr)
+		}
 	default:
-		err = fmt.Errorf("unsupported proof type: %v", proofType)
+		return fmt.Errorf("%w: %v", ErrUnsupportedProofType, proofType)
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("check embedded proof: %w", err)
+	return nil
+}
+
+// checkProofsChallengeAndDomain checks that challenge and domain of each proof match the expected ones
+// (if defined). The expected challenge or domain can't be matched if there are no proofs.
+func checkProofsChallengeAndDomain(proofMaps []map[string]interface{}, challenge, domain string) error {
+	if challenge == "" && domain == "" {
+		return nil
 	}
 
-	return docBytes, nil
+	if len(proofMaps) == 0 {
+		if challenge != "" {
+			return fmt.Errorf("%w: expected %q, got no proof", ErrProofChallengeMismatch, challenge)
+		}
+
+		return fmt.Errorf("%w: expected %q, got no proof", ErrProofDomainMismatch, domain)
+	}
+
+	for _, proofMap := range proofMaps {
+		if err := checkProofChallengeAndDomain(proofMap, challenge, domain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkProofChallengeAndDomain checks that challenge and domain of the proof match the expected ones (if defined).
+func checkProofChallengeAndDomain(proofMap map[string]interface{}, expectedChallenge, expectedDomain string) error {
+	if expectedChallenge != "" {
+		if challenge := safeStringValue(proofMap["challenge"]); challenge != expectedChallenge {
+			return fmt.Errorf("%w: expected %q, got %q", ErrProofChallengeMismatch, expectedChallenge, challenge)
+		}
+	}
+
+	if expectedDomain != "" {
+		if domain := safeStringValue(proofMap["domain"]); domain != expectedDomain {
+			return fmt.Errorf("%w: expected %q, got %q", ErrProofDomainMismatch, expectedDomain, domain)
+		}
+	}
+
+	return nil
+}
+
+// checkProofDateConsistency checks that the proofs of VC were created within its validity window (if enabled).
+// The proofs without "created" are not checked.
+func checkProofDateConsistency(vc *Credential, vcOpts *credentialOpts) error {
+	if !vcOpts.proofDateConsistency {
+		return nil
+	}
+
+	for _, proof := range vc.Proofs {
+		createdRaw, ok := proof["created"].(string)
+		if !ok {
+			continue
+		}
+
+		created, err := parseTime(createdRaw)
+		if err != nil {
+			return fmt.Errorf("parse proof created: %w", err)
+		}
+
+		if vc.Issued != nil && created.Before(*vc.Issued) {
+			return fmt.Errorf("%w: proof created %s before issuance date %s",
+				ErrProofDateInconsistent, createdRaw, vc.Issued.Format(time.RFC3339))
+		}
+
+		if vc.Expired != nil && created.After(*vc.Expired) {
+			return fmt.Errorf("%w: proof created %s after expiration date %s",
+				ErrProofDateInconsistent, createdRaw, vc.Expired.Format(time.RFC3339))
+		}
+	}
+
+	return nil
 }
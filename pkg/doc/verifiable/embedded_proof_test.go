@@ -6,31 +6,46 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
 func Test_parseEmbeddedProof(t *testing.T) {
 	t.Run("parse linked data proof with \"Ed25519Signature2018\" proof type", func(t *testing.T) {
-		proofType, err := parseEmbeddedProof(map[string]interface{}{
+		proofType, proofTypeStr, err := parseEmbeddedProof(map[string]interface{}{
 			"type": "Ed25519Signature2018",
 		})
 		require.NoError(t, err)
 		require.Equal(t, linkedDataProof, proofType)
+		require.Equal(t, "Ed25519Signature2018", proofTypeStr)
+	})
+
+	t.Run("parse linked data proof with \"Ed25519Signature2020\" proof type", func(t *testing.T) {
+		proofType, proofTypeStr, err := parseEmbeddedProof(map[string]interface{}{
+			"type": "Ed25519Signature2020",
+		})
+		require.NoError(t, err)
+		require.Equal(t, linkedDataProof, proofType)
+		require.Equal(t, "Ed25519Signature2020", proofTypeStr)
 	})
 
 	t.Run("parse embedded proof without \"type\" element", func(t *testing.T) {
-		_, err := parseEmbeddedProof(map[string]interface{}{})
+		_, _, err := parseEmbeddedProof(map[string]interface{}{})
 		require.Error(t, err)
 		require.EqualError(t, err, "proof type is missing")
 	})
 
 	t.Run("parse embedded proof with unsupported type", func(t *testing.T) {
-		_, err := parseEmbeddedProof(map[string]interface{}{
+		_, _, err := parseEmbeddedProof(map[string]interface{}{
 			"type": "SomethingUnsupported",
 		})
 		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnsupportedProofType))
 		require.EqualError(t, err, "unsupported proof type: SomethingUnsupported")
 	})
 }
@@ -73,6 +88,65 @@ func Test_checkEmbeddedProof(t *testing.T) {
 		r.Nil(docBytes)
 	})
 
+	t.Run("error on not map element of \"proof\" list", func(t *testing.T) {
+		docWithNotMapProof := `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": [{"type": "Ed25519Signature2018"}, "some string proof"]
+}`
+		docBytes, err := checkEmbeddedProof([]byte(docWithNotMapProof), defaultVCOpts)
+		r.Error(err)
+		r.EqualError(err, "check embedded proof: expecting [string]interface{}")
+		r.Nil(docBytes)
+	})
+
+	t.Run("error on empty \"proof\" list", func(t *testing.T) {
+		docBytes, err := checkEmbeddedProof([]byte(`{"proof": []}`), defaultVCOpts)
+		r.Error(err)
+		r.EqualError(err, "check embedded proof: expecting at least one proof")
+		r.Nil(docBytes)
+	})
+
+	t.Run("error on not supported type of one of several embedded proofs", func(t *testing.T) {
+		docWithNotSupportedProof := `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": [{"type": "Ed25519Signature2018"}, {"type": "SomethingUnsupported"}]
+}`
+		docBytes, err := checkEmbeddedProof([]byte(docWithNotSupportedProof), defaultVCOpts)
+		r.Error(err)
+		r.True(errors.Is(err, ErrUnsupportedProofType))
+		r.EqualError(err, "check embedded proof: unsupported proof type: SomethingUnsupported")
+		r.Nil(docBytes)
+	})
+
+	t.Run("challenge is checked against each of several embedded proofs", func(t *testing.T) {
+		for _, challenges := range [][2]string{{"3fa85f64", "other"}, {"other", "3fa85f64"}} {
+			docWithProofs := fmt.Sprintf(`{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": [
+    {"type": "Ed25519Signature2018", "challenge": %q},
+    {"type": "Ed25519Signature2018", "challenge": %q}
+  ]
+}`, challenges[0], challenges[1])
+
+			docBytes, err := checkEmbeddedProof([]byte(docWithProofs), &credentialOpts{proofChallenge: "3fa85f64"})
+			r.Error(err)
+			r.True(errors.Is(err, ErrProofChallengeMismatch))
+			r.Nil(docBytes)
+		}
+	})
+
+	t.Run("expected challenge or domain without proof", func(t *testing.T) {
+		docWithoutProof := `{"@context": "https://www.w3.org/2018/credentials/v1"}`
+
+		docBytes, err := checkEmbeddedProof([]byte(docWithoutProof), &credentialOpts{proofChallenge: "3fa85f64"})
+		r.True(errors.Is(err, ErrProofChallengeMismatch))
+		r.EqualError(err, `check embedded proof: proof challenge mismatch: expected "3fa85f64", got no proof`)
+		r.Nil(docBytes)
+
+		_, err = checkEmbeddedProof([]byte(docWithoutProof), &credentialOpts{proofDomain: "example.com"})
+		r.True(errors.Is(err, ErrProofDomainMismatch))
+	})
+
 	t.Run("error on not supported type of embedded proof", func(t *testing.T) {
 		docWithNotSupportedProof := `{
   "@context": "https://www.w3.org/2018/credentials/v1",
@@ -82,7 +156,24 @@ func Test_checkEmbeddedProof(t *testing.T) {
 }`
 		docBytes, err := checkEmbeddedProof([]byte(docWithNotSupportedProof), defaultVCOpts)
 		r.Error(err)
-		r.EqualError(err, "unsupported proof type: SomethingUnsupported")
+		r.True(errors.Is(err, ErrUnsupportedProofType))
+		r.EqualError(err, "check embedded proof: unsupported proof type: SomethingUnsupported")
+		r.Nil(docBytes)
+	})
+
+	t.Run("error on proof type not accepted by the defined signature suites", func(t *testing.T) {
+		docWithEd25519Proof := `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": {
+	"type": "Ed25519Signature2018"
+  }
+}`
+		docBytes, err := checkEmbeddedProof([]byte(docWithEd25519Proof), &credentialOpts{
+			ldpSuites: []SignatureSuite{&rejectingSignatureSuite{}},
+		})
+		r.Error(err)
+		r.True(errors.Is(err, ErrUnsupportedProofType))
+		r.EqualError(err, "check embedded proof: unsupported proof type: Ed25519Signature2018")
 		r.Nil(docBytes)
 	})
 
@@ -102,3 +193,133 @@ func Test_checkEmbeddedProof(t *testing.T) {
 		r.Nil(docBytes)
 	})
 }
+
+type rejectingSignatureSuite struct {
+	SignatureSuite
+}
+
+func (s *rejectingSignatureSuite) Accept(string) bool {
+	return false
+}
+
+func Test_checkProofChallengeAndDomain(t *testing.T) {
+	r := require.New(t)
+
+	proofMap := map[string]interface{}{
+		"type":      "Ed25519Signature2018",
+		"challenge": "3fa85f64",
+		"domain":    "example.com",
+	}
+
+	t.Run("no challenge and domain expected", func(t *testing.T) {
+		r.NoError(checkProofChallengeAndDomain(proofMap, "", ""))
+		r.NoError(checkProofChallengeAndDomain(map[string]interface{}{}, "", ""))
+	})
+
+	t.Run("challenge and domain match", func(t *testing.T) {
+		r.NoError(checkProofChallengeAndDomain(proofMap, "3fa85f64", "example.com"))
+	})
+
+	t.Run("challenge mismatch", func(t *testing.T) {
+		err := checkProofChallengeAndDomain(proofMap, "other", "")
+		r.Error(err)
+		r.True(errors.Is(err, ErrProofChallengeMismatch))
+		r.EqualError(err, `proof challenge mismatch: expected "other", got "3fa85f64"`)
+
+		err = checkProofChallengeAndDomain(map[string]interface{}{}, "3fa85f64", "")
+		r.True(errors.Is(err, ErrProofChallengeMismatch))
+	})
+
+	t.Run("domain mismatch", func(t *testing.T) {
+		err := checkProofChallengeAndDomain(proofMap, "3fa85f64", "other.com")
+		r.Error(err)
+		r.True(errors.Is(err, ErrProofDomainMismatch))
+		r.EqualError(err, `proof domain mismatch: expected "other.com", got "example.com"`)
+	})
+
+	t.Run("check embedded proof fails on challenge mismatch", func(t *testing.T) {
+		docWithProof := `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": {
+	"type": "Ed25519Signature2018",
+	"challenge": "3fa85f64"
+  }
+}`
+		docBytes, err := checkEmbeddedProof([]byte(docWithProof), &credentialOpts{proofChallenge: "other"})
+		r.Error(err)
+		r.True(errors.Is(err, ErrProofChallengeMismatch))
+		r.Nil(docBytes)
+	})
+}
+
+func Test_checkProofDateConsistency(t *testing.T) {
+	issued := time.Date(2010, 1, 1, 19, 23, 24, 0, time.UTC)
+	expired := time.Date(2020, 1, 1, 19, 23, 24, 0, time.UTC)
+
+	newVC := func(created interface{}) *Credential {
+		return &Credential{
+			Issued:  &issued,
+			Expired: &expired,
+			Proofs:  []Proof{{"type": "Ed25519Signature2018", "created": created}},
+		}
+	}
+
+	opts := &credentialOpts{proofDateConsistency: true}
+
+	t.Run("proof created within validity window", func(t *testing.T) {
+		require.NoError(t, checkProofDateConsistency(newVC("2018-06-18T21:19:10Z"), opts))
+		require.NoError(t, checkProofDateConsistency(newVC("2010-01-01T19:23:24Z"), opts))
+
+		// proof without created date is not checked
+		require.NoError(t, checkProofDateConsistency(newVC(nil), opts))
+
+		// the credential without validity dates
+		require.NoError(t, checkProofDateConsistency(&Credential{
+			Proofs: []Proof{{"created": "2009-06-18T21:19:10Z"}},
+		}, opts))
+	})
+
+	t.Run("proof created before issuance date", func(t *testing.T) {
+		err := checkProofDateConsistency(newVC("2009-06-18T21:19:10Z"), opts)
+		require.True(t, errors.Is(err, ErrProofDateInconsistent))
+		require.Contains(t, err.Error(), "proof created 2009-06-18T21:19:10Z before issuance date 2010-01-01T19:23:24Z")
+
+		// the check is opt-in
+		require.NoError(t, checkProofDateConsistency(newVC("2009-06-18T21:19:10Z"), &credentialOpts{}))
+	})
+
+	t.Run("proof created after expiration date", func(t *testing.T) {
+		err := checkProofDateConsistency(newVC("2021-06-18T21:19:10Z"), opts)
+		require.True(t, errors.Is(err, ErrProofDateInconsistent))
+		require.Contains(t, err.Error(), "proof created 2021-06-18T21:19:10Z after expiration date 2020-01-01T19:23:24Z")
+	})
+
+	t.Run("invalid proof created date", func(t *testing.T) {
+		err := checkProofDateConsistency(newVC("18 June 2018"), opts)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse proof created")
+	})
+
+	t.Run("decode credential with proof created before issuance date", func(t *testing.T) {
+		var raw map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &raw))
+		raw["proof"] = map[string]interface{}{
+			"type":    "Ed25519Signature2018",
+			"created": "2009-06-18T21:19:10Z",
+		}
+
+		vcBytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		noProofCheck := func(opts *credentialOpts) {
+			opts.disabledProofCheck = true
+		}
+
+		_, _, err = NewCredential(vcBytes, noProofCheck)
+		require.NoError(t, err)
+
+		_, _, err = NewCredential(vcBytes, noProofCheck, WithProofDateConsistency())
+		require.True(t, errors.Is(err, ErrProofDateInconsistent))
+	})
+}
@@ -190,7 +190,7 @@ func ExamplePresentation_SetCredentials() {
 
 	// The first VC is created on fly (or just decoded using NewCredential).
 	vc := &verifiable.Credential{
-		Context: []string{
+		Context: verifiable.Contexts{
 			"https://www.w3.org/2018/credentials/v1",
 			"https://www.w3.org/2018/credentials/examples/v1"},
 		ID: "http://example.edu/credentials/1872",
@@ -239,7 +239,7 @@ func ExamplePresentation_MarshalJSON() {
 	}
 
 	vc := &verifiable.Credential{
-		Context: []string{
+		Context: verifiable.Contexts{
 			"https://www.w3.org/2018/credentials/v1",
 			"https://www.w3.org/2018/credentials/examples/v1"},
 		ID: "http://example.edu/credentials/1872",
@@ -333,7 +333,7 @@ func ExamplePresentation_MarshalledCredentials() {
 	}
 
 	vc := verifiable.Credential{
-		Context: []string{
+		Context: verifiable.Contexts{
 			"https://www.w3.org/2018/credentials/v1",
 			"https://www.w3.org/2018/credentials/examples/v1"},
 		ID: "http://example.edu/credentials/1872",
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/square/go-jose/v3/jwt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+)
+
+// ErrIssuerKeyMismatch is returned when the key used for the proof of VC does not belong to the issuer.
+var ErrIssuerKeyMismatch = errors.New("issuer key mismatch")
+
+// checkIssuerKeyBinding checks that the keys used for the proofs of VC are the assertion method keys
+// of the issuer DID (see WithIssuerKeyBindingCheck). VC must have a proof.
+func checkIssuerKeyBinding(vc *Credential, vcData []byte, vcOpts *credentialOpts) error {
+	if vcOpts.issuerKeyBindingVDRI == nil || vcOpts.disabledProofCheck {
+		return nil
+	}
+
+	keyIDs, err := proofKeyIDs(vc, vcData)
+	if err != nil {
+		return fmt.Errorf("check issuer key binding: %w", err)
+	}
+
+	if len(keyIDs) == 0 {
+		return fmt.Errorf("check issuer key binding: %w: credential has no proof", ErrIssuerKeyMismatch)
+	}
+
+	doc, err := vcOpts.issuerKeyBindingVDRI.Resolve(vc.Issuer.ID)
+	if err != nil {
+		return fmt.Errorf("check issuer key binding: resolve DID %s: %w", vc.Issuer.ID, err)
+	}
+
+	for _, keyID := range keyIDs {
+		if !isAssertionMethod(doc, keyID) {
+			return fmt.Errorf("check issuer key binding: %w: key '%s' is not an assertion method of DID %s",
+				ErrIssuerKeyMismatch, keyID, vc.Issuer.ID)
+		}
+	}
+
+	return nil
+}
+
+// proofKeyIDs returns IDs of the keys used for the proofs of VC, i.e. "kid" header of JWS
+// or "verificationMethod" (or "creator") of each embedded proof.
+func proofKeyIDs(vc *Credential, vcData []byte) ([]string, error) {
+	if isJWS(vcData) {
+		parsedJWT, err := jwt.ParseSigned(string(vcData))
+		if err != nil {
+			return nil, fmt.Errorf("parse JWS: %w", err)
+		}
+
+		var keyID string
+
+		for _, h := range parsedJWT.Headers {
+			if h.KeyID != "" {
+				keyID = h.KeyID
+				break
+			}
+		}
+
+		return []string{keyID}, nil
+	}
+
+	keyIDs := make([]string, len(vc.Proofs))
+
+	for i, p := range vc.Proofs {
+		keyIDs[i] = safeStringValue(p["verificationMethod"])
+		if keyIDs[i] == "" {
+			keyIDs[i] = safeStringValue(p["creator"])
+		}
+	}
+
+	return keyIDs, nil
+}
+
+// isAssertionMethod checks whether the key with the given ID is an assertion method of the DID document,
+// i.e. the key is authorized to issue credentials. Both absolute ("did:example:123#key-1") and relative ("#key-1")
+// key IDs are supported.
+func isAssertionMethod(doc *did.Doc, keyID string) bool {
+	if keyID == "" {
+		return false
+	}
+
+	keyID = absoluteKeyID(doc.ID, keyID)
+
+	for _, vm := range doc.AssertionMethod {
+		if absoluteKeyID(doc.ID, vm.PublicKey.ID) == keyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func absoluteKeyID(didID, keyID string) string {
+	if strings.HasPrefix(keyID, "#") {
+		return didID + keyID
+	}
+
+	return keyID
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+)
+
+func TestWithIssuerKeyBindingCheck(t *testing.T) {
+	const issuerDID = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	issuerKey := did.PublicKey{
+		ID:         "#keys-1",
+		Type:       "Ed25519VerificationKey2018",
+		Controller: issuerDID,
+		Value:      pubKey,
+	}
+
+	issuerDoc := &did.Doc{
+		ID:              issuerDID,
+		PublicKey:       []did.PublicKey{issuerKey},
+		AssertionMethod: []did.VerificationMethod{{PublicKey: issuerKey}},
+	}
+
+	vdriRegistry := &mockvdri.MockVDRIRegistry{ResolveValue: issuerDoc}
+
+	t.Run("JWS signed by issuer key", func(t *testing.T) {
+		vc, _, err := NewCredential(createEdDSAJWS(t, []byte(validCredential), privKey, false),
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithIssuerKeyBindingCheck(vdriRegistry))
+		require.NoError(t, err)
+		require.NotNil(t, vc)
+	})
+
+	t.Run("JWS signed by key of other DID", func(t *testing.T) {
+		otherKey := did.PublicKey{ID: "did:example:other#keys-1"}
+		otherDoc := &did.Doc{
+			ID:              issuerDID,
+			PublicKey:       []did.PublicKey{otherKey},
+			AssertionMethod: []did.VerificationMethod{{PublicKey: otherKey}},
+		}
+
+		vc, _, err := NewCredential(createEdDSAJWS(t, []byte(validCredential), privKey, false),
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveValue: otherDoc}))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+		require.Nil(t, vc)
+	})
+
+	t.Run("issuer DID resolution error", func(t *testing.T) {
+		vc, _, err := NewCredential(createEdDSAJWS(t, []byte(validCredential), privKey, false),
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveErr: errors.New("resolve error")}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve error")
+		require.Nil(t, vc)
+	})
+
+	t.Run("check is skipped if proof check is disabled", func(t *testing.T) {
+		vc, _, err := NewCredential(createEdDSAJWS(t, []byte(validCredential), privKey, false),
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			func(opts *credentialOpts) { opts.disabledProofCheck = true },
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveErr: errors.New("resolve error")}))
+		require.NoError(t, err)
+		require.NotNil(t, vc)
+	})
+
+	t.Run("credential without proof", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential), WithIssuerKeyBindingCheck(vdriRegistry))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+		require.Contains(t, err.Error(), "credential has no proof")
+		require.Nil(t, vc)
+	})
+
+	t.Run("embedded proofs", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		vcOpts := &credentialOpts{issuerKeyBindingVDRI: vdriRegistry}
+
+		vc.Proofs = []Proof{
+			{"type": "Ed25519Signature2018", "verificationMethod": issuerDID + "#keys-1"},
+			{"type": "Ed25519Signature2018", "creator": "#keys-1"},
+		}
+		require.NoError(t, checkIssuerKeyBinding(vc, []byte(validCredential), vcOpts))
+
+		vc.Proofs = append(vc.Proofs, Proof{"type": "Ed25519Signature2018", "verificationMethod": "did:example:other#keys-1"})
+		err = checkIssuerKeyBinding(vc, []byte(validCredential), vcOpts)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+
+		vc.Proofs = []Proof{{"type": "Ed25519Signature2018"}}
+		err = checkIssuerKeyBinding(vc, []byte(validCredential), vcOpts)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+	})
+
+	t.Run("key which is not an assertion method", func(t *testing.T) {
+		doc := &did.Doc{
+			ID:              issuerDID,
+			PublicKey:       []did.PublicKey{{ID: "#keys-1"}},
+			Authentication:  []did.VerificationMethod{{PublicKey: did.PublicKey{ID: issuerDID + "#auth-1"}}},
+			AssertionMethod: []did.VerificationMethod{{PublicKey: did.PublicKey{ID: issuerDID + "#assert-1"}}},
+		}
+		require.True(t, isAssertionMethod(doc, "#assert-1"))
+		require.True(t, isAssertionMethod(doc, issuerDID+"#assert-1"))
+		require.False(t, isAssertionMethod(doc, "#auth-1"))
+		require.False(t, isAssertionMethod(doc, "#keys-1"))
+		require.False(t, isAssertionMethod(doc, ""))
+
+		vc, _, err := NewCredential(createEdDSAJWS(t, []byte(validCredential), privKey, false),
+			WithPublicKeyFetcher(SingleKey(pubKey)),
+			WithIssuerKeyBindingCheck(&mockvdri.MockVDRIRegistry{ResolveValue: doc}))
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+		require.Nil(t, vc)
+	})
+}
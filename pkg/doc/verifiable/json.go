@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
@@ -104,3 +105,30 @@ func toMap(v interface{}) (map[string]interface{}, error) {
 
 	return m, nil
 }
+
+// canonicalJSON re-encodes JSON with lexicographically sorted keys at every level of nesting.
+// Numbers are kept in their original representation and HTML characters are not escaped.
+func canonicalJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+
+	err := decoder.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Maps are encoded with sorted keys by encoding/json.
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	err = encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -98,3 +98,17 @@ func Test_unmarshalJSON(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func Test_canonicalJSON(t *testing.T) {
+	t.Run("Keys are sorted at every level", func(t *testing.T) {
+		actual, err := canonicalJSON([]byte(`{"z":{"b":1,"a":[{"y":"<&>","x":1.50}]},"a":null}`))
+		require.NoError(t, err)
+		require.Equal(t, `{"a":null,"z":{"a":[{"x":1.50,"y":"<&>"}],"b":1}}`, string(actual))
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		actual, err := canonicalJSON([]byte("not JSON"))
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+}
@@ -256,7 +256,11 @@ const vcJSONLD = `
 
 // CachingJSONLDLoader creates JSON_LD CachingDocumentLoader with preloaded base JSON-LD document.
 func CachingJSONLDLoader() *ld.CachingDocumentLoader {
-	loader := ld.NewCachingDocumentLoader(ld.NewRFC7324CachingDocumentLoader(&http.Client{}))
+	return newCachingJSONLDLoader(ld.NewRFC7324CachingDocumentLoader(&http.Client{}))
+}
+
+func newCachingJSONLDLoader(nextLoader ld.DocumentLoader) *ld.CachingDocumentLoader {
+	loader := ld.NewCachingDocumentLoader(nextLoader)
 
 	reader, err := ld.DocumentFromReader(strings.NewReader(vcJSONLD))
 	if err != nil {
@@ -6,8 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -15,14 +19,44 @@ import (
 	"github.com/square/go-jose/v3/jwt"
 )
 
+// jwsOpts holds options for the JWS serialization
+type jwsOpts struct {
+	noDefaultKeyID bool
+}
+
+// JWSOpt is the JWS serialization option
+type JWSOpt func(opts *jwsOpts)
+
+// WithNoDefaultKeyID disables defaulting of empty key ID to JWK thumbprint of the public key,
+// i.e. the empty "kid" header is kept.
+func WithNoDefaultKeyID() JWSOpt {
+	return func(opts *jwsOpts) {
+		opts.noDefaultKeyID = true
+	}
+}
+
 // MarshalJWS serializes JWT presentation claims into signed form (JWS)
 // todo refactor, do not pass privateKey (https://github.com/hyperledger/aries-framework-go/issues/339)
-func marshalJWS(jwtClaims interface{}, signatureAlg JWSAlgorithm, privateKey interface{}, keyID string) (string, error) { //nolint:lll
+func marshalJWS(jwtClaims interface{}, signatureAlg JWSAlgorithm, privateKey interface{}, keyID string,
+	opts ...JWSOpt) (string, error) {
 	joseAlg, err := signatureAlg.jose()
 	if err != nil {
 		return "", err
 	}
 
+	options := &jwsOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if keyID == "" && !options.noDefaultKeyID {
+		keyID, err = jwkThumbprint(privateKey)
+		if err != nil {
+			return "", fmt.Errorf("default key ID: %w", err)
+		}
+	}
+
 	key := jose.SigningKey{Algorithm: joseAlg, Key: privateKey}
 
 	var signerOpts = &jose.SignerOptions{}
@@ -47,6 +81,31 @@ func marshalJWS(jwtClaims interface{}, signatureAlg JWSAlgorithm, privateKey int
 	return jws, nil
 }
 
+// jwkThumbprint computes RFC 7638 JWK thumbprint (SHA-256, base64url encoded) of the public key
+// which corresponds to the given private key.
+func jwkThumbprint(privateKey interface{}) (string, error) {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return "", fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+
+	if edPubKey, ok := signer.Public().(ed25519.PublicKey); ok {
+		// go-jose produces malformed thumbprint input for OKP keys, so follow RFC 8037 explicitly
+		input := fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`,
+			base64.RawURLEncoding.EncodeToString(edPubKey))
+		thumbprint := sha256.Sum256([]byte(input))
+
+		return base64.RawURLEncoding.EncodeToString(thumbprint[:]), nil
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: signer.Public()}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("compute JWK thumbprint: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
 func verifyJWTSignature(token *jwt.JSONWebToken, fetcher PublicKeyFetcher, issuer string, jwtClaims interface{}) error {
 	var keyID string
 
@@ -69,6 +128,55 @@ func verifyJWTSignature(token *jwt.JSONWebToken, fetcher PublicKeyFetcher, issue
 	return nil
 }
 
+// ErrDisallowedAlgorithm is returned when JWS is signed with the algorithm which is not allowed.
+var ErrDisallowedAlgorithm = errors.New("disallowed JWS algorithm")
+
+// checkJWSAlgorithm checks "alg" header of JWS against allowed algorithms (all supported ones if not defined).
+// "none" algorithm is rejected unconditionally.
+func checkJWSAlgorithm(data []byte, allowed []JWSAlgorithm) error {
+	parts := strings.Split(string(data), ".")
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("decode JWS header: %w", err)
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return fmt.Errorf("unmarshal JWS header: %w", err)
+	}
+
+	if strings.EqualFold(header.Algorithm, "none") {
+		return fmt.Errorf("%w: %s", ErrDisallowedAlgorithm, header.Algorithm)
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, alg := range allowed {
+		joseAlg, algErr := alg.jose()
+		if algErr == nil && string(joseAlg) == header.Algorithm {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrDisallowedAlgorithm, header.Algorithm)
+}
+
+// checkUnsecuredJWTAlgorithm rejects unsecured JWT if the algorithms are restricted, JWT without signature
+// is not secured by any of them whatever its "alg" header is.
+func checkUnsecuredJWTAlgorithm(allowed []JWSAlgorithm) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: none", ErrDisallowedAlgorithm)
+}
+
 func isJWS(data []byte) bool {
 	parts := strings.Split(string(data), ".")
 
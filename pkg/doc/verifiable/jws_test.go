@@ -6,11 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/square/go-jose/v3/jwt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -76,3 +78,45 @@ func Test_isJWS(t *testing.T) {
 		})
 	}
 }
+
+func Test_marshalJWS_keyID(t *testing.T) {
+	// Ed25519 key and its JWK thumbprint from RFC 8037, Appendix A
+	seed, err := base64.RawURLEncoding.DecodeString("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")
+	require.NoError(t, err)
+
+	privKey := ed25519.NewKeyFromSeed(seed)
+
+	claims := &JWTCredClaims{Claims: &jwt.Claims{Issuer: "did:example:76e12ec712ebc6f1c221ebfeb1f"}}
+
+	keyIDOf := func(t *testing.T, jws string) string {
+		token, err := jwt.ParseSigned(jws)
+		require.NoError(t, err)
+		require.Len(t, token.Headers, 1)
+
+		return token.Headers[0].KeyID
+	}
+
+	t.Run("key ID defaults to JWK thumbprint", func(t *testing.T) {
+		jws, err := claims.MarshalJWS(EdDSA, privKey, "")
+		require.NoError(t, err)
+		require.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", keyIDOf(t, jws))
+	})
+
+	t.Run("explicit key ID", func(t *testing.T) {
+		jws, err := claims.MarshalJWS(EdDSA, privKey, "did:example:76e12ec712ebc6f1c221ebfeb1f#keys-1")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f#keys-1", keyIDOf(t, jws))
+	})
+
+	t.Run("empty key ID is kept", func(t *testing.T) {
+		jws, err := claims.MarshalJWS(EdDSA, privKey, "", WithNoDefaultKeyID())
+		require.NoError(t, err)
+		require.Empty(t, keyIDOf(t, jws))
+	})
+
+	t.Run("unsupported private key", func(t *testing.T) {
+		_, err := claims.MarshalJWS(EdDSA, []byte(privKey), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "default key ID: unsupported private key type")
+	})
+}
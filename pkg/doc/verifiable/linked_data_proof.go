@@ -9,9 +9,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
@@ -19,6 +20,12 @@ import (
 
 const (
 	resolveIDParts = 2
+
+	ed25519Signature2018 = "Ed25519Signature2018"
+	ed25519Signature2020 = "Ed25519Signature2020"
+
+	// defaultProofPurpose is used as proof purpose of Linked Data Proof if not defined explicitly.
+	defaultProofPurpose = "assertionMethod"
 )
 
 // signatureSuite encapsulates signature suite methods required for signing documents
@@ -34,7 +41,8 @@ type signatureSuite interface {
 	Accept(signatureType string) bool
 }
 
-type verifierSignatureSuite interface {
+// SignatureSuite encapsulates signature suite methods required for linked data proof verification.
+type SignatureSuite interface {
 	signatureSuite
 
 	// Verify will verify signature against public key
@@ -54,13 +62,12 @@ type keyResolverAdapter struct {
 
 func (k *keyResolverAdapter) Resolve(id string) ([]byte, error) {
 	// id will contain didID#keyID
-	idSplit := strings.Split(id, "#")
-	if len(idSplit) != resolveIDParts {
-		return nil, fmt.Errorf("wrong id %s to resolve", idSplit)
+	issuerID, keyID, err := splitKeyID(id)
+	if err != nil {
+		return nil, err
 	}
-	// idSplit[0] is didID
-	// idSplit[1] is keyID
-	fetcher, err := k.pubKeyFetcher(idSplit[0], fmt.Sprintf("#%s", idSplit[1]))
+
+	fetcher, err := k.pubKeyFetcher(issuerID, keyID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,19 +97,78 @@ type LinkedDataProofContext struct {
 	Suite                   signerSignatureSuite    // required
 	SignatureRepresentation SignatureRepresentation // required
 	Created                 *time.Time              // optional
+	ProofPurpose            string                  // optional, "assertionMethod" is used by default
+	VerificationMethod      string                  // optional
+	Challenge               string                  // optional
+	Domain                  string                  // optional
+	ID                      string                  // optional, generated for a chained proof if not defined
+	// ProofChain makes the new proof chained to the last proof of the document (optional). The chained proof
+	// references the previous proof by its ID and signs the document including the existing proofs,
+	// so the proofs are verified in order. The last proof of the document must have ID.
+	ProofChain bool
+	// AggregatedProof is M-of-N multi-signature proof (see AggregateProofs) to be added instead of signing
+	// the document, the other options are not used then (optional).
+	AggregatedProof *AggregatedProof
+}
+
+// CheckLinkedDataProof checks linked data proof(s) of JSON-LD document (e.g. VC or VP) without decoding
+// and validating the document against the VC data model. If suite is not defined,
+// Ed25519Signature2018 suite is used.
+func CheckLinkedDataProof(docBytes []byte, suite SignatureSuite, pubKeyFetcher PublicKeyFetcher) error {
+	var suites []SignatureSuite
+	if suite != nil {
+		suites = append(suites, suite)
+	}
+
+	return checkLinkedDataProof(docBytes, suites, pubKeyFetcher)
+}
+
+func checkLinkedDataProof(jsonldBytes []byte, suites []SignatureSuite, pubKeyFetcher PublicKeyFetcher) error {
+	if pubKeyFetcher == nil {
+		return errors.New("check linked data proof: public key fetcher is not defined")
+	}
+
+	_, err := verifyLinkedDataProof(jsonldBytes, suites, &keyResolverAdapter{pubKeyFetcher})
+
+	return err
+}
+
+// ldpKeyResolver resolves the public key of the linked data proof (see verifier.New).
+type ldpKeyResolver interface {
+	Resolve(id string) ([]byte, error)
 }
 
-func checkLinkedDataProof(jsonldBytes []byte, suite verifierSignatureSuite, pubKeyFetcher PublicKeyFetcher) error {
-	documentVerifier := verifier.New(
-		&keyResolverAdapter{pubKeyFetcher},
-		suite)
+// verifyLinkedDataProof verifies linked data proof(s) and returns the IDs of the keys which verified them.
+func verifyLinkedDataProof(jsonldBytes []byte, suites []SignatureSuite, resolver ldpKeyResolver) ([]string, error) {
+	verifierSuites := make([]verifier.SignatureSuite, len(suites))
+	for i := range suites {
+		verifierSuites[i] = suites[i]
+	}
+
+	documentVerifier := verifier.New(resolver, verifierSuites...)
 
-	err := documentVerifier.Verify(jsonldBytes)
+	keyIDs, err := documentVerifier.VerifyKeys(jsonldBytes)
 	if err != nil {
-		return fmt.Errorf("check linked data proof: %w", err)
+		return nil, fmt.Errorf("check linked data proof: %w", err)
 	}
 
-	return nil
+	return keyIDs, nil
+}
+
+// acceptsProofType checks whether any of the suites accepts the given proof type.
+// If no suites are defined, only Ed25519Signature2018 is accepted.
+func acceptsProofType(suites []SignatureSuite, proofType string) bool {
+	if len(suites) == 0 {
+		return proofType == ed25519Signature2018
+	}
+
+	for _, suite := range suites {
+		if suite.Accept(proofType) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type rawProof struct {
@@ -112,9 +178,21 @@ type rawProof struct {
 // addLinkedDataProof adds a new proof to the JSON-LD document (VC or VP). It returns a slice
 // of the proofs which were already present appended with a newly created proof.
 func addLinkedDataProof(context *LinkedDataProofContext, jsonldBytes []byte) ([]Proof, error) {
+	if context.AggregatedProof != nil {
+		return addAggregatedProof(context.AggregatedProof, jsonldBytes)
+	}
+
 	documentSigner := signer.New(context.Suite)
 
-	vcWithNewProofBytes, err := documentSigner.Sign(mapContext(context), jsonldBytes)
+	signerContext := mapContext(context)
+
+	if context.ProofChain {
+		if err := chainProof(signerContext, jsonldBytes); err != nil {
+			return nil, fmt.Errorf("add linked data proof: %w", err)
+		}
+	}
+
+	vcWithNewProofBytes, err := documentSigner.Sign(signerContext, jsonldBytes)
 	if err != nil {
 		return nil, fmt.Errorf("add linked data proof: %w", err)
 	}
@@ -135,10 +213,71 @@ func addLinkedDataProof(context *LinkedDataProofContext, jsonldBytes []byte) ([]
 	return proofs, nil
 }
 
+// addAggregatedProof returns a slice of the proofs which were already present in the JSON-LD document
+// appended with the proofs of M-of-N multi-signature proof.
+func addAggregatedProof(aggregated *AggregatedProof, jsonldBytes []byte) ([]Proof, error) {
+	var rProof rawProof
+
+	err := json.Unmarshal(jsonldBytes, &rProof)
+	if err != nil {
+		return nil, fmt.Errorf("add aggregated proof: %w", err)
+	}
+
+	proofs, err := decodeProof(rProof.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("add aggregated proof: %w", err)
+	}
+
+	return append(proofs, aggregated.Proofs...), nil
+}
+
+// chainProof sets the new proof ID (if not defined) and makes it chained to the last proof of the document
+// (if any).
+func chainProof(signerContext *signer.Context, jsonldBytes []byte) error {
+	if signerContext.ID == "" {
+		signerContext.ID = "urn:uuid:" + uuid.New().String()
+	}
+
+	var rProof rawProof
+
+	err := json.Unmarshal(jsonldBytes, &rProof)
+	if err != nil {
+		return err
+	}
+
+	proofs, err := decodeProof(rProof.Proof)
+	if err != nil {
+		return err
+	}
+
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	previousProof := safeStringValue(proofs[len(proofs)-1]["id"])
+	if previousProof == "" {
+		return errors.New("proof chain: the last proof of the document has no id")
+	}
+
+	signerContext.PreviousProof = previousProof
+
+	return nil
+}
+
 func mapContext(context *LinkedDataProofContext) *signer.Context {
+	proofPurpose := context.ProofPurpose
+	if proofPurpose == "" {
+		proofPurpose = defaultProofPurpose
+	}
+
 	return &signer.Context{
 		SignatureType:           context.SignatureType,
 		SignatureRepresentation: proof.SignatureRepresentation(context.SignatureRepresentation),
 		Created:                 context.Created,
+		ProofPurpose:            proofPurpose,
+		VerificationMethod:      context.VerificationMethod,
+		Challenge:               context.Challenge,
+		Domain:                  context.Domain,
+		ID:                      context.ID,
 	}
 }
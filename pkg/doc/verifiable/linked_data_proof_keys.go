@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+)
+
+// CandidateKeysFetcher fetches the public keys which may verify the linked data proof of the issuer,
+// e.g. when the issuer DID publishes both old and new keys during the key rotation window. The keys are tried
+// in order until one of them verifies the proof. The candidates must include the key referenced by
+// the verification method of the proof (by either absolute or relative ID), the proof is rejected otherwise.
+type CandidateKeysFetcher func(issuerID, keyID string) ([]*verifier.CandidateKey, error)
+
+// WithCandidateKeysFetcher option verifies the embedded linked data proofs of VC by any of the candidate keys
+// fetched by the given fetcher (e.g. DIDKeyResolver CandidateKeysFetcher) instead of the single key fetched by
+// PublicKeyFetcher. Use CheckLinkedDataProofWithCandidateKeys to find out which key verified the proof.
+// The IDs of the keys which verified the proofs are the signers counted by WithProofThreshold.
+func WithCandidateKeysFetcher(fetcher CandidateKeysFetcher) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.candidateKeysFetcher = fetcher
+	}
+}
+
+// CheckLinkedDataProofWithCandidateKeys checks linked data proof(s) of JSON-LD document (e.g. VC or VP)
+// by the candidate keys like CheckLinkedDataProof does by the single key. The IDs of the keys which verified
+// the proofs are returned in order of the proofs. If suite is not defined, Ed25519Signature2018 suite is used.
+func CheckLinkedDataProofWithCandidateKeys(docBytes []byte, suite SignatureSuite,
+	fetcher CandidateKeysFetcher) ([]string, error) {
+	var suites []SignatureSuite
+	if suite != nil {
+		suites = append(suites, suite)
+	}
+
+	return checkLinkedDataProofWithCandidateKeys(docBytes, suites, fetcher)
+}
+
+func checkLinkedDataProofWithCandidateKeys(docBytes []byte, suites []SignatureSuite,
+	fetcher CandidateKeysFetcher) ([]string, error) {
+	if fetcher == nil {
+		return nil, errors.New("check linked data proof: candidate keys fetcher is not defined")
+	}
+
+	return verifyLinkedDataProof(docBytes, suites, &candidateKeysAdapter{fetcher: fetcher})
+}
+
+// candidateKeysAdapter resolves the candidate keys of the proof for verifier.DocumentVerifier.
+type candidateKeysAdapter struct {
+	fetcher CandidateKeysFetcher
+}
+
+// Resolve resolves the first candidate key.
+func (c *candidateKeysAdapter) Resolve(id string) ([]byte, error) {
+	keys, err := c.ResolveCandidates(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no candidate keys are fetched for %s", id)
+	}
+
+	return keys[0].Value, nil
+}
+
+// ResolveCandidates resolves the candidate keys of the verification method (didID#keyID) of the proof.
+// The verification method must be one of the candidates.
+func (c *candidateKeysAdapter) ResolveCandidates(id string) ([]*verifier.CandidateKey, error) {
+	issuerID, keyID, err := splitKeyID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := c.fetcher(issuerID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.ID == id || key.ID == keyID {
+			return keys, nil
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no candidate keys are fetched for %s", id)
+	}
+
+	return nil, fmt.Errorf("%w: verification method %s is not one of the candidate keys", ErrIssuerKeyMismatch, id)
+}
+
+// splitKeyID splits the verification method of the proof (didID#keyID) into DID and key ID (#keyID).
+func splitKeyID(id string) (string, string, error) {
+	idSplit := strings.Split(id, "#")
+	if len(idSplit) != resolveIDParts {
+		return "", "", fmt.Errorf("wrong id %s to resolve", idSplit)
+	}
+
+	return idSplit[0], "#" + idSplit[1], nil
+}
+
+// resolveCandidateKeys returns the assertion method keys of the issuer DID with absolute IDs
+// (e.g. did:example:123#key-1), the key with the given ID is the first.
+func (r *DIDKeyResolver) resolveCandidateKeys(issuerDID, keyID string) ([]*verifier.CandidateKey, error) {
+	doc, err := r.vdriRegistry.Resolve(issuerDID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", issuerDID, err)
+	}
+
+	keyID = absoluteKeyID(issuerDID, keyID)
+	keys := make([]*verifier.CandidateKey, 0, len(doc.AssertionMethod))
+
+	for _, vm := range doc.AssertionMethod {
+		candidate := &verifier.CandidateKey{ID: absoluteKeyID(doc.ID, vm.PublicKey.ID), Value: vm.PublicKey.Value}
+
+		if candidate.ID == keyID {
+			keys = append([]*verifier.CandidateKey{candidate}, keys...)
+			continue
+		}
+
+		keys = append(keys, candidate)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("assertion method keys are not found for DID %s", issuerDID)
+	}
+
+	return keys, nil
+}
+
+// CandidateKeysFetcher returns Candidate Keys Fetcher via DID resolution mechanism, the assertion method keys
+// of the DID (i.e. the keys authorized to issue credentials) are the candidates. The key referenced by the proof
+// must be one of them, it is tried first. The IDs of the keys are absolute (e.g. did:example:123#key-1).
+func (r *DIDKeyResolver) CandidateKeysFetcher() CandidateKeysFetcher {
+	return r.resolveCandidateKeys
+}
@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+)
+
+func TestCheckLinkedDataProofWithCandidateKeys(t *testing.T) {
+	const issuerDID = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+
+	oldPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	newPubKey, newPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// the issuer signs by the new key but references the old one during the key rotation window
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	require.NoError(t, vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		SignatureRepresentation: SignatureProofValue,
+		Suite:                   newJSONSignatureSuite(mocksignature.NewEd25519Signer(newPrivKey)),
+		VerificationMethod:      issuerDID + "#key-1",
+	}))
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	authPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	registry := &mockvdri.MockVDRIRegistry{ResolveValue: &did.Doc{
+		ID: issuerDID,
+		PublicKey: []did.PublicKey{
+			{ID: "#key-2", Value: newPubKey},
+			{ID: "#key-1", Value: oldPubKey},
+			{ID: "#key-3", Value: authPubKey},
+		},
+		Authentication: []did.VerificationMethod{{PublicKey: did.PublicKey{ID: "#key-3", Value: authPubKey}}},
+		AssertionMethod: []did.VerificationMethod{
+			{PublicKey: did.PublicKey{ID: "#key-2", Value: newPubKey}},
+			{PublicKey: did.PublicKey{ID: issuerDID + "#key-1", Value: oldPubKey}},
+		},
+	}}
+
+	resolver := NewDIDKeyResolver(registry)
+	verifierSuite := newJSONSignatureSuite(nil)
+
+	t.Run("any of candidate keys verifies the proof", func(t *testing.T) {
+		keyIDs, err := CheckLinkedDataProofWithCandidateKeys(vcBytes, verifierSuite, resolver.CandidateKeysFetcher())
+		require.NoError(t, err)
+		require.Equal(t, []string{issuerDID + "#key-2"}, keyIDs)
+
+		_, _, err = NewCredential(vcBytes, WithEmbeddedSignatureSuites(verifierSuite),
+			WithCandidateKeysFetcher(resolver.CandidateKeysFetcher()))
+		require.NoError(t, err)
+
+		// the referenced key only is checked by the public key fetcher
+		_, _, err = NewCredential(vcBytes, WithEmbeddedSignatureSuites(verifierSuite),
+			WithPublicKeyFetcher(resolver.PublicKeyFetcher()))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check linked data proof")
+	})
+
+	t.Run("referenced key is tried first", func(t *testing.T) {
+		keys, err := resolver.CandidateKeysFetcher()(issuerDID, "#key-1")
+		require.NoError(t, err)
+		require.Equal(t, []*verifier.CandidateKey{
+			{ID: issuerDID + "#key-1", Value: oldPubKey},
+			{ID: issuerDID + "#key-2", Value: newPubKey},
+		}, keys)
+	})
+
+	t.Run("proof of the key which is not an assertion method", func(t *testing.T) {
+		fetcher := resolver.CandidateKeysFetcher()
+
+		// the key is not a candidate, so the proof is rejected even if it is signed by a candidate key
+		_, err := (&candidateKeysAdapter{fetcher: fetcher}).ResolveCandidates(issuerDID + "#key-3")
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+
+		keys, err := fetcher(issuerDID, "#key-3")
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		for _, key := range keys {
+			require.NotEqual(t, issuerDID+"#key-3", key.ID)
+		}
+
+		authProofVC, _, err := NewCredential([]byte(validCredential))
+		require.NoError(t, err)
+
+		require.NoError(t, authProofVC.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureProofValue,
+			Suite:                   newJSONSignatureSuite(mocksignature.NewEd25519Signer(newPrivKey)),
+			VerificationMethod:      issuerDID + "#key-3",
+		}))
+
+		authProofBytes, err := authProofVC.MarshalJSON()
+		require.NoError(t, err)
+
+		_, err = CheckLinkedDataProofWithCandidateKeys(authProofBytes, verifierSuite, fetcher)
+		require.True(t, errors.Is(err, ErrIssuerKeyMismatch))
+	})
+
+	t.Run("none of candidate keys verifies the proof", func(t *testing.T) {
+		fetcher := func(_, _ string) ([]*verifier.CandidateKey, error) {
+			return []*verifier.CandidateKey{{ID: "#key-1", Value: oldPubKey}}, nil
+		}
+
+		_, err := CheckLinkedDataProofWithCandidateKeys(vcBytes, verifierSuite, fetcher)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check linked data proof")
+
+		_, _, err = NewCredential(vcBytes, WithEmbeddedSignatureSuites(verifierSuite),
+			WithCandidateKeysFetcher(fetcher))
+		require.Error(t, err)
+	})
+
+	t.Run("candidate keys are not fetched", func(t *testing.T) {
+		_, err := CheckLinkedDataProofWithCandidateKeys(vcBytes, verifierSuite, nil)
+		require.EqualError(t, err, "check linked data proof: candidate keys fetcher is not defined")
+
+		_, err = CheckLinkedDataProofWithCandidateKeys(vcBytes, verifierSuite,
+			func(_, _ string) ([]*verifier.CandidateKey, error) {
+				return nil, errors.New("fetch error")
+			})
+		require.EqualError(t, err, "check linked data proof: fetch error")
+
+		_, err = CheckLinkedDataProofWithCandidateKeys(vcBytes, nil,
+			NewDIDKeyResolver(&mockvdri.MockVDRIRegistry{ResolveValue: &did.Doc{ID: issuerDID}}).CandidateKeysFetcher())
+		require.EqualError(t, err, "check linked data proof: assertion method keys are not found for DID "+issuerDID)
+
+		_, err = NewDIDKeyResolver(&mockvdri.MockVDRIRegistry{ResolveErr: errors.New("resolve error")}).
+			CandidateKeysFetcher()(issuerDID, "#key-1")
+		require.EqualError(t, err, "resolve DID "+issuerDID+": resolve error")
+	})
+
+	t.Run("candidate keys adapter", func(t *testing.T) {
+		adapter := &candidateKeysAdapter{fetcher: resolver.CandidateKeysFetcher()}
+
+		key, err := adapter.Resolve(issuerDID + "#key-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte(oldPubKey), key)
+
+		_, err = adapter.Resolve("any")
+		require.EqualError(t, err, "wrong id [any] to resolve")
+
+		adapter = &candidateKeysAdapter{fetcher: func(_, _ string) ([]*verifier.CandidateKey, error) {
+			return nil, nil
+		}}
+
+		_, err = adapter.Resolve(issuerDID + "#key-1")
+		require.EqualError(t, err, "no candidate keys are fetched for "+issuerDID+"#key-1")
+	})
+}
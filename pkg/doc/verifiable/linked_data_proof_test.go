@@ -9,7 +9,9 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 func Test_keyResolverAdapter_Resolve(t *testing.T) {
@@ -142,8 +145,9 @@ func TestLinkedDataProofSigner(t *testing.T) {
 	require.NoError(t, err)
 
 	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
-		SignatureType:           "Ed25519Signature2018",
-		Suite:                   ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+		SignatureType: "Ed25519Signature2018",
+		Suite: ed25519signature2018.New(
+			ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 		SignatureRepresentation: SignatureJWS,
 		Created:                 &created,
 	})
@@ -155,5 +159,96 @@ func TestLinkedDataProofSigner(t *testing.T) {
 
 	require.Equal(t, "Ed25519Signature2018", p["type"])
 	require.Equal(t, "2018-03-15T00:00:00Z", p["created"])
-	require.Equal(t, "eyJhbGciOiJFZDI1NTE5U2lnbmF0dXJlMjAxOCIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..jKb9yKsse4ufBRVs8Ffq0wA3U9LEfKql2CAtRNGIeA8KN5BkhPgaMYUAe4drVkFCgyvW754YBnZw0wDNIklLDQ", p["jws"]) //nolint:lll
+	require.Equal(t, "assertionMethod", p["proofPurpose"])
+	require.True(t, strings.HasPrefix(p["jws"].(string),
+		"eyJhbGciOiJFZDI1NTE5U2lnbmF0dXJlMjAxOCIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.."))
+
+	// the proof options (including the proof purpose) are signed
+	vcMap := addDummyCreatorToProof(vc, require.New(t))
+
+	vcBytes, err := json.Marshal(vcMap)
+	require.NoError(t, err)
+
+	documentVerifier := verifier.New(dummyKeyResolver(privKey.Public().(ed25519.PublicKey)))
+	require.NoError(t, documentVerifier.Verify(vcBytes))
+
+	vcMap["proof"].(map[string]interface{})["proofPurpose"] = "authentication"
+
+	vcBytes, err = json.Marshal(vcMap)
+	require.NoError(t, err)
+	require.Error(t, documentVerifier.Verify(vcBytes))
+}
+
+func TestCheckLinkedDataProof(t *testing.T) {
+	r := require.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+
+	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey)))
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	r.NoError(err)
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		SignatureRepresentation: SignatureProofValue,
+		Suite:                   suite,
+	})
+	r.NoError(err)
+
+	vcMap := addDummyCreatorToProof(vc, r)
+
+	vcBytes, err := json.Marshal(vcMap)
+	r.NoError(err)
+
+	t.Run("valid linked data proof", func(t *testing.T) {
+		err = CheckLinkedDataProof(vcBytes, suite, SingleKey(pubKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("valid linked data proof with default suite", func(t *testing.T) {
+		err = CheckLinkedDataProof(vcBytes, nil, SingleKey(pubKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("linked data proof of tampered document", func(t *testing.T) {
+		tamperedMap, err := toMap(vcMap)
+		require.NoError(t, err)
+
+		tamperedMap["id"] = "http://example.edu/credentials/0000"
+
+		tamperedBytes, err := json.Marshal(tamperedMap)
+		require.NoError(t, err)
+
+		err = CheckLinkedDataProof(tamperedBytes, suite, SingleKey(pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check linked data proof")
+	})
+
+	t.Run("undefined public key fetcher", func(t *testing.T) {
+		err = CheckLinkedDataProof(vcBytes, suite, nil)
+		require.Error(t, err)
+		require.EqualError(t, err, "check linked data proof: public key fetcher is not defined")
+	})
+
+	t.Run("document without linked data proof", func(t *testing.T) {
+		err = CheckLinkedDataProof([]byte(validCredential), suite, SingleKey(pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check linked data proof")
+	})
+}
+
+func Test_acceptsProofType(t *testing.T) {
+	t.Run("default suite accepts Ed25519Signature2018 only", func(t *testing.T) {
+		require.True(t, acceptsProofType(nil, "Ed25519Signature2018"))
+		require.False(t, acceptsProofType(nil, "SomethingUnsupported"))
+	})
+
+	t.Run("defined suites", func(t *testing.T) {
+		suites := []SignatureSuite{&rejectingSignatureSuite{}, ed25519signature2018.New()}
+
+		require.True(t, acceptsProofType(suites, "Ed25519Signature2018"))
+		require.False(t, acceptsProofType(suites[:1], "Ed25519Signature2018"))
+	})
 }
@@ -161,6 +161,8 @@ type Presentation struct {
 	Holder         string
 	Proofs         []Proof
 	RefreshService *TypedID
+
+	PresentationSubmission *PresentationSubmission
 }
 
 // MarshalJSON converts Verifiable Presentation to JSON bytes.
@@ -232,22 +234,39 @@ func (vp *Presentation) MarshalledCredentials() ([]MarshalledCredential, error)
 }
 
 func (vp *Presentation) raw() (*rawPresentation, error) {
-	proof, err := proofsToRaw(vp.Proofs)
+	proof, err := proofsToRaw(vp.Proofs, false)
 	if err != nil {
 		return nil, err
 	}
 
 	return &rawPresentation{
-		Context:        vp.Context,
+		Context:        vp.rawContext(),
 		ID:             vp.ID,
 		Type:           vp.Type,
 		Credential:     vp.credentials,
 		Holder:         vp.Holder,
 		Proof:          proof,
 		RefreshService: vp.RefreshService,
+
+		PresentationSubmission: vp.PresentationSubmission,
 	}, nil
 }
 
+// rawContext returns the contexts of the presentation, the custom contexts follow the URIs of the contexts.
+func (vp *Presentation) rawContext() interface{} {
+	if len(vp.CustomContext) == 0 {
+		return vp.Context
+	}
+
+	context := make([]interface{}, 0, len(vp.Context)+len(vp.CustomContext))
+
+	for _, c := range vp.Context {
+		context = append(context, c)
+	}
+
+	return append(context, vp.CustomContext...)
+}
+
 // rawPresentation is a basic verifiable credential
 type rawPresentation struct {
 	Context        interface{}     `json:"@context,omitempty"`
@@ -257,13 +276,29 @@ type rawPresentation struct {
 	Holder         string          `json:"holder,omitempty"`
 	Proof          json.RawMessage `json:"proof,omitempty"`
 	RefreshService *TypedID        `json:"refreshService,omitempty"`
+
+	PresentationSubmission *PresentationSubmission `json:"presentation_submission,omitempty"`
+
+	// jwtBinding binds VP decoded from JWT to the verifier's request, it is not a part of VP itself
+	jwtBinding *presJWTBinding
+}
+
+// presJWTBinding holds JWT claims of VP which play the role of challenge and domain of the embedded proof.
+type presJWTBinding struct {
+	nonce    string
+	audience []string
 }
 
 // presentationOpts holds options for the Verifiable Presentation decoding
 type presentationOpts struct {
 	publicKeyFetcher   PublicKeyFetcher
 	disabledProofCheck bool
-	ldpSuite           verifierSignatureSuite
+	ldpSuites          []SignatureSuite
+	proofChallenge     string
+	proofDomain        string
+	types              typeOpts
+
+	allowedJWSAlgorithms []JWSAlgorithm
 }
 
 // PresentationOpt is the Verifiable Presentation decoding option
@@ -277,10 +312,38 @@ func WithPresPublicKeyFetcher(fetcher PublicKeyFetcher) PresentationOpt {
 	}
 }
 
-// WithPresEmbeddedSignatureSuites defines the suite which is used to check embedded linked data proof of VP.
-func WithPresEmbeddedSignatureSuites(suite verifierSignatureSuite) PresentationOpt {
+// WithPresEmbeddedSignatureSuites defines the suites which are used to check embedded linked data proof of VP.
+// If no suites are defined, Ed25519Signature2018 suite is used.
+func WithPresEmbeddedSignatureSuites(suites ...SignatureSuite) PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.ldpSuites = suites
+	}
+}
+
+// WithPresProofChallenge defines the expected challenge of the embedded proofs of VP, or the expected
+// "nonce" claim of VP decoded from JWT. The check fails with ErrProofChallengeMismatch if the challenge
+// is different or VP has no proof. The embedded credentials are not checked against the challenge.
+func WithPresProofChallenge(challenge string) PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.proofChallenge = challenge
+	}
+}
+
+// WithPresProofDomain defines the expected domain of the embedded proofs of VP, or the expected
+// audience ("aud" claim) of VP decoded from JWT. The check fails with ErrProofDomainMismatch if the domain
+// is different or VP has no proof.
+func WithPresProofDomain(domain string) PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.proofDomain = domain
+	}
+}
+
+// WithPresAllowedJWSAlgorithms restricts the algorithms accepted for JWS of VP and of the credentials
+// embedded into VP (see WithAllowedJWSAlgorithms). Unsecured JWT is rejected with ErrDisallowedAlgorithm
+// if the algorithms are restricted.
+func WithPresAllowedJWSAlgorithms(algs ...JWSAlgorithm) PresentationOpt {
 	return func(opts *presentationOpts) {
-		opts.ldpSuite = suite
+		opts.allowedJWSAlgorithms = algs
 	}
 }
 
@@ -299,6 +362,15 @@ func NewPresentation(vpData []byte, opts ...PresentationOpt) (*Presentation, err
 		return nil, err
 	}
 
+	if err = checkPresChallengeAndDomain(vpRaw, vpOpts); err != nil {
+		return nil, err
+	}
+
+	vpDataDecoded, err = processPresentationTypes(vpDataDecoded, vpRaw, vpOpts.types)
+	if err != nil {
+		return nil, err
+	}
+
 	err = validatePresentation(vpDataDecoded)
 	if err != nil {
 		return nil, err
@@ -333,6 +405,8 @@ func NewPresentation(vpData []byte, opts ...PresentationOpt) (*Presentation, err
 		Holder:         vpRaw.Holder,
 		Proofs:         proofs,
 		RefreshService: vpRaw.RefreshService,
+
+		PresentationSubmission: vpRaw.PresentationSubmission,
 	}
 
 	return vp, nil
@@ -393,10 +467,67 @@ func mapOpts(vpOpts *presentationOpts) *credentialOpts {
 	return &credentialOpts{
 		publicKeyFetcher:   vpOpts.publicKeyFetcher,
 		disabledProofCheck: vpOpts.disabledProofCheck,
-		ldpSuite:           vpOpts.ldpSuite,
+		ldpSuites:          vpOpts.ldpSuites,
+
+		allowedJWSAlgorithms: vpOpts.allowedJWSAlgorithms,
 	}
 }
 
+// checkPresChallengeAndDomain checks the expected challenge and domain against the embedded proofs of VP,
+// or against the nonce and audience of VP decoded from JWT.
+func checkPresChallengeAndDomain(vpRaw *rawPresentation, vpOpts *presentationOpts) error {
+	if vpOpts.proofChallenge == "" && vpOpts.proofDomain == "" {
+		return nil
+	}
+
+	if vpRaw.jwtBinding != nil {
+		return checkPresJWTBinding(vpRaw.jwtBinding, vpOpts)
+	}
+
+	var proofMaps []map[string]interface{}
+
+	if len(vpRaw.Proof) > 0 {
+		var proofElement interface{}
+
+		if err := json.Unmarshal(vpRaw.Proof, &proofElement); err != nil {
+			return fmt.Errorf("check presentation proof: %w", err)
+		}
+
+		var err error
+
+		if proofMaps, err = getProofMaps(proofElement); err != nil {
+			return fmt.Errorf("check presentation proof: %w", err)
+		}
+	}
+
+	if err := checkProofsChallengeAndDomain(proofMaps, vpOpts.proofChallenge, vpOpts.proofDomain); err != nil {
+		return fmt.Errorf("check presentation proof: %w", err)
+	}
+
+	return nil
+}
+
+// checkPresJWTBinding checks that the nonce and audience of VP decoded from JWT are the expected ones.
+func checkPresJWTBinding(binding *presJWTBinding, vpOpts *presentationOpts) error {
+	if vpOpts.proofChallenge != "" && binding.nonce != vpOpts.proofChallenge {
+		return fmt.Errorf("check presentation JWT: %w: expected %q, got %q",
+			ErrProofChallengeMismatch, vpOpts.proofChallenge, binding.nonce)
+	}
+
+	if vpOpts.proofDomain == "" {
+		return nil
+	}
+
+	for _, aud := range binding.audience {
+		if aud == vpOpts.proofDomain {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("check presentation JWT: %w: expected %q, got %q",
+		ErrProofDomainMismatch, vpOpts.proofDomain, binding.audience)
+}
+
 func validatePresentation(data []byte) error {
 	loader := gojsonschema.NewStringLoader(string(data))
 
@@ -415,6 +546,10 @@ func validatePresentation(data []byte) error {
 
 func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *rawPresentation, error) {
 	if isJWS(vpData) {
+		if err := checkJWSAlgorithm(vpData, vpOpts.allowedJWSAlgorithms); err != nil {
+			return nil, nil, fmt.Errorf("decoding of Verifiable Presentation from JWS: %w", err)
+		}
+
 		if vpOpts.publicKeyFetcher == nil {
 			return nil, nil, errors.New("public key fetcher is not defined")
 		}
@@ -428,6 +563,10 @@ func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *ra
 	}
 
 	if isJWTUnsecured(vpData) {
+		if err := checkUnsecuredJWTAlgorithm(vpOpts.allowedJWSAlgorithms); err != nil {
+			return nil, nil, fmt.Errorf("decoding of Verifiable Presentation from unsecured JWT: %w", err)
+		}
+
 		rawBytes, rawCred, err := decodeVPFromUnsecuredJWT(vpData)
 		if err != nil {
 			return nil, nil, fmt.Errorf("decoding of Verifiable Presentation from unsecured JWT: %w", err)
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -26,6 +27,70 @@ func TestJWTPresClaims_MarshalJWS(t *testing.T) {
 	require.Equal(t, vp.stringJSON(t), rawVC.stringJSON(t))
 }
 
+func TestJWTPresClaims_MarshalJWSWithNonceAndAudience(t *testing.T) {
+	vp, err := NewPresentation([]byte(validPresentation))
+	require.NoError(t, err)
+
+	privateKey, err := readPrivateKey(filepath.Join(certPrefix, "holder_private.pem"))
+	require.NoError(t, err)
+
+	jwtClaims, err := vp.JWTClaims([]string{"did:example:verifier"}, true)
+	require.NoError(t, err)
+
+	jwtClaims.Nonce = "343s$FSFDa-"
+
+	jws, err := jwtClaims.MarshalJWS(RS256, privateKey, vp.Holder+"#keys-"+keyID)
+	require.NoError(t, err)
+
+	claims, err := unmarshalPresJWSClaims([]byte(jws), true, holderPublicKeyFetcher(t))
+	require.NoError(t, err)
+	require.Equal(t, "343s$FSFDa-", claims.Nonce)
+	require.Equal(t, jwt.Audience{"did:example:verifier"}, claims.Audience)
+	require.Equal(t, vp.Holder, claims.Issuer)
+
+	vpFromJWS, err := NewPresentation([]byte(jws), WithPresPublicKeyFetcher(holderPublicKeyFetcher(t)))
+	require.NoError(t, err)
+	require.Equal(t, vp, vpFromJWS)
+}
+
+func TestNewPresentation_JWTNonceAndAudience(t *testing.T) {
+	vp, err := NewPresentation([]byte(validPresentation))
+	require.NoError(t, err)
+
+	privateKey, err := readPrivateKey(filepath.Join(certPrefix, "holder_private.pem"))
+	require.NoError(t, err)
+
+	jwtClaims, err := vp.JWTClaims([]string{"did:example:verifier"}, true)
+	require.NoError(t, err)
+
+	jwtClaims.Nonce = "343s$FSFDa-"
+
+	jws, err := jwtClaims.MarshalJWS(RS256, privateKey, vp.Holder+"#keys-"+keyID)
+	require.NoError(t, err)
+
+	t.Run("nonce and audience match", func(t *testing.T) {
+		_, err := NewPresentation([]byte(jws),
+			WithPresPublicKeyFetcher(holderPublicKeyFetcher(t)),
+			WithPresProofChallenge("343s$FSFDa-"),
+			WithPresProofDomain("did:example:verifier"))
+		require.NoError(t, err)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		_, err := NewPresentation([]byte(jws),
+			WithPresPublicKeyFetcher(holderPublicKeyFetcher(t)),
+			WithPresProofChallenge("stale"))
+		require.True(t, errors.Is(err, ErrProofChallengeMismatch))
+	})
+
+	t.Run("audience mismatch", func(t *testing.T) {
+		_, err := NewPresentation([]byte(jws),
+			WithPresPublicKeyFetcher(holderPublicKeyFetcher(t)),
+			WithPresProofDomain("did:example:other"))
+		require.True(t, errors.Is(err, ErrProofDomainMismatch))
+	})
+}
+
 type invalidPresClaims struct {
 	*jwt.Claims
 
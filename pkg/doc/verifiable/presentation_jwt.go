@@ -13,9 +13,11 @@ import (
 )
 
 // JWTPresClaims is JWT Claims extension by Verifiable Presentation (with custom "vp" claim).
+// Nonce can be set to bind the signed presentation to the verifier's request (together with the audience).
 type JWTPresClaims struct {
 	*jwt.Claims
 
+	Nonce        string           `json:"nonce,omitempty"`
 	Presentation *rawPresentation `json:"vp,omitempty"`
 }
 
@@ -29,6 +31,8 @@ func (jpc *JWTPresClaims) refineFromJWTClaims() {
 	if jpc.ID != "" {
 		raw.ID = jpc.ID
 	}
+
+	raw.jwtBinding = &presJWTBinding{nonce: jpc.Nonce, audience: jpc.Audience}
 }
 
 // newJWTPresClaims creates JWT Claims of VP with an option to minimize certain fields put into "vp" claim.
@@ -8,8 +8,10 @@ package verifiable
 import (
 	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +76,18 @@ func TestNewPresentationFromJWS(t *testing.T) {
 		require.Nil(t, vp)
 	})
 
+	t.Run("algorithm is not allowed", func(t *testing.T) {
+		vp, err := NewPresentation(createPresJWS(t, vpBytes, true),
+			WithPresPublicKeyFetcher(keyFetcher), WithPresAllowedJWSAlgorithms(EdDSA))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+		require.Nil(t, vp)
+
+		_, err = NewPresentation(createPresJWS(t, vpBytes, true),
+			WithPresPublicKeyFetcher(keyFetcher), WithPresAllowedJWSAlgorithms(RS256))
+		require.NoError(t, err)
+	})
+
 	t.Run("Not defined public key fetcher", func(t *testing.T) {
 		vp, err := NewPresentation(createPresJWS(t, vpBytes, true))
 
@@ -133,6 +147,22 @@ func TestNewPresentationFromUnsecuredJWT(t *testing.T) {
 
 		require.Equal(t, vp, vpFromJWT)
 	})
+
+	t.Run("unsecured JWT is rejected if algorithms are restricted", func(t *testing.T) {
+		unsecuredJWT := createPresUnsecuredJWT(t, vpBytes, false)
+
+		_, err := NewPresentation(unsecuredJWT, WithPresAllowedJWSAlgorithms(RS256))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+
+		// the header claims the allowed algorithm but JWT has no signature
+		parts := strings.Split(string(unsecuredJWT), ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`))
+
+		_, err = NewPresentation([]byte(strings.Join(parts, ".")), WithPresAllowedJWSAlgorithms(RS256))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDisallowedAlgorithm))
+	})
 }
 
 func TestNewPresentationWithVCJWT(t *testing.T) {
@@ -143,7 +173,7 @@ func TestNewPresentationWithVCJWT(t *testing.T) {
 	expired := time.Date(2020, time.January, 1, 19, 23, 24, 0, time.UTC)
 
 	vc := &Credential{
-		Context: []string{
+		Context: Contexts{
 			"https://www.w3.org/2018/credentials/v1",
 			"https://www.w3.org/2018/credentials/examples/v1"},
 		ID: "http://example.edu/credentials/1872",
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	mocksignature "github.com/hyperledger/aries-framework-go/pkg/internal/mock/signature"
 )
 
 func TestNewPresentationFromLinkedDataProof(t *testing.T) {
@@ -22,7 +23,7 @@ func TestNewPresentationFromLinkedDataProof(t *testing.T) {
 	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
 	r.NoError(err)
 
-	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey)))
+	suite := ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey)))
 
 	ldpContext := &LinkedDataProofContext{
 		SignatureType:           "Ed25519Signature2018",
@@ -57,7 +58,8 @@ func TestPresentation_AddLinkedDataProof(t *testing.T) {
 	ldpContext := &LinkedDataProofContext{
 		SignatureType:           "Ed25519Signature2018",
 		SignatureRepresentation: SignatureProofValue,
-		Suite:                   ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+		Suite: ed25519signature2018.New(
+			ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 	}
 
 	t.Run("Add a valid Linked Data proof to VC", func(t *testing.T) {
@@ -100,7 +102,7 @@ func TestPresentation_AddLinkedDataProof(t *testing.T) {
 
 		vp.RefreshService = nil
 		ldpContextWithMissingSignatureType := &LinkedDataProofContext{
-			Suite: ed25519signature2018.New(ed25519signature2018.WithSigner(getSigner(privKey))),
+			Suite: ed25519signature2018.New(ed25519signature2018.WithSigner(mocksignature.NewEd25519Signer(privKey))),
 		}
 
 		err = vp.AddLinkedDataProof(ldpContextWithMissingSignatureType)
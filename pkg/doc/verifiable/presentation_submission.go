@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// credentialPathPattern is a JSONPath pattern of the credential of presentation
+const credentialPathPattern = "$.verifiableCredential[%d]"
+
+// credentialPathRegex matches JSONPath of the credential of presentation,
+// e.g. "$.verifiableCredential[0]" or "$.verifiableCredential.[0]".
+var credentialPathRegex = regexp.MustCompile(`^\$\.verifiableCredential\.?\[(\d+)]$`)
+
+// ErrSubmissionNotDefined is returned when presentation submission is validated but not defined.
+var ErrSubmissionNotDefined = errors.New("presentation submission is not defined")
+
+// PresentationSubmission links the credentials of a presentation to the input descriptors
+// of a presentation definition (DIF Presentation Exchange).
+type PresentationSubmission struct {
+	ID            string                   `json:"id,omitempty"`
+	DefinitionID  string                   `json:"definition_id,omitempty"`
+	DescriptorMap []InputDescriptorMapping `json:"descriptor_map"`
+}
+
+// InputDescriptorMapping maps an input descriptor to the credential of presentation which satisfies it.
+// Path is JSONPath of the credential, e.g. "$.verifiableCredential[0]" (see CredentialPath).
+type InputDescriptorMapping struct {
+	ID     string `json:"id"`
+	Format string `json:"format,omitempty"`
+	Path   string `json:"path"`
+}
+
+// CredentialPath returns JSONPath of the credential of presentation with the given index.
+func CredentialPath(index int) string {
+	return fmt.Sprintf(credentialPathPattern, index)
+}
+
+// ValidateSubmission checks that the presentation submission is defined and each mapped path
+// resolves to a credential present in the presentation.
+func (vp *Presentation) ValidateSubmission() error {
+	if vp.PresentationSubmission == nil {
+		return ErrSubmissionNotDefined
+	}
+
+	for _, mapping := range vp.PresentationSubmission.DescriptorMap {
+		if mapping.ID == "" {
+			return errors.New("validate presentation submission: input descriptor id is not defined")
+		}
+
+		if _, err := vp.submissionCredential(mapping.Path); err != nil {
+			return fmt.Errorf("validate presentation submission: input descriptor %s: %w", mapping.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// SubmissionCredentials returns the credentials of presentation mapped to the given input descriptor.
+func (vp *Presentation) SubmissionCredentials(descriptorID string) ([]interface{}, error) {
+	if vp.PresentationSubmission == nil {
+		return nil, ErrSubmissionNotDefined
+	}
+
+	var creds []interface{}
+
+	for _, mapping := range vp.PresentationSubmission.DescriptorMap {
+		if mapping.ID != descriptorID {
+			continue
+		}
+
+		cred, err := vp.submissionCredential(mapping.Path)
+		if err != nil {
+			return nil, fmt.Errorf("input descriptor %s: %w", mapping.ID, err)
+		}
+
+		creds = append(creds, cred)
+	}
+
+	return creds, nil
+}
+
+func (vp *Presentation) submissionCredential(path string) (interface{}, error) {
+	match := credentialPathRegex.FindStringSubmatch(path)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported credential path '%s'", path)
+	}
+
+	index, err := strconv.Atoi(match[1])
+	if err != nil || index >= len(vp.credentials) {
+		return nil, fmt.Errorf("credential is not found by path '%s'", path)
+	}
+
+	return vp.credentials[index], nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresentation_ValidateSubmission(t *testing.T) {
+	vp, err := NewPresentation([]byte(validPresentation))
+	require.NoError(t, err)
+
+	require.True(t, errors.Is(vp.ValidateSubmission(), ErrSubmissionNotDefined))
+
+	vp.PresentationSubmission = &PresentationSubmission{
+		ID:           "a30e3b91-fb77-4d22-95fa-871689c322e2",
+		DefinitionID: "32f54163-7166-48f1-93d8-ff217bdb0653",
+		DescriptorMap: []InputDescriptorMapping{
+			{ID: "alumni_input", Format: "ldp_vc", Path: CredentialPath(0)},
+			{ID: "alumni_input_legacy", Path: "$.verifiableCredential.[0]"},
+		},
+	}
+
+	t.Run("submission is kept in JSON", func(t *testing.T) {
+		vpBytes, err := json.Marshal(vp)
+		require.NoError(t, err)
+
+		var vpMap map[string]interface{}
+		require.NoError(t, json.Unmarshal(vpBytes, &vpMap))
+		require.Contains(t, vpMap, "presentation_submission")
+
+		vpDecoded, err := NewPresentation(vpBytes)
+		require.NoError(t, err)
+		require.Equal(t, vp.PresentationSubmission, vpDecoded.PresentationSubmission)
+		require.NoError(t, vpDecoded.ValidateSubmission())
+	})
+
+	t.Run("submission credentials", func(t *testing.T) {
+		creds, err := vp.SubmissionCredentials("alumni_input")
+		require.NoError(t, err)
+		require.Len(t, creds, 1)
+		require.Equal(t, vp.Credentials()[0], creds[0])
+
+		creds, err = vp.SubmissionCredentials("unknown")
+		require.NoError(t, err)
+		require.Empty(t, creds)
+
+		_, err = (&Presentation{}).SubmissionCredentials("alumni_input")
+		require.True(t, errors.Is(err, ErrSubmissionNotDefined))
+	})
+
+	t.Run("invalid submission", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			mapping InputDescriptorMapping
+			err     string
+		}{
+			{"missing id", InputDescriptorMapping{Path: CredentialPath(0)}, "input descriptor id is not defined"},
+			{"unsupported path", InputDescriptorMapping{ID: "x", Path: "$.credential"}, "unsupported credential path"},
+			{"no credential", InputDescriptorMapping{ID: "x", Path: CredentialPath(1)}, "credential is not found"},
+		}
+
+		for _, tc := range tests {
+			invalidVP := *vp
+			invalidVP.PresentationSubmission = &PresentationSubmission{DescriptorMap: []InputDescriptorMapping{tc.mapping}}
+
+			err := invalidVP.ValidateSubmission()
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.err, tc.name)
+
+			if tc.mapping.ID != "" {
+				_, err = invalidVP.SubmissionCredentials(tc.mapping.ID)
+				require.Error(t, err, tc.name)
+			}
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
@@ -113,6 +114,56 @@ func TestNewPresentation(t *testing.T) {
 	})
 }
 
+func TestNewPresentation_ProofChallengeAndDomain(t *testing.T) {
+	var raw map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(validPresentation), &raw))
+
+	proof, ok := raw["proof"].(map[string]interface{})
+	require.True(t, ok)
+
+	proof["challenge"] = "3fa85f64"
+	proof["domain"] = "example.com"
+
+	vpBytes, err := json.Marshal(raw)
+	require.NoError(t, err)
+
+	t.Run("challenge and domain match", func(t *testing.T) {
+		_, err := NewPresentation(vpBytes, WithPresProofChallenge("3fa85f64"), WithPresProofDomain("example.com"))
+		require.NoError(t, err)
+	})
+
+	t.Run("challenge mismatch", func(t *testing.T) {
+		_, err := NewPresentation(vpBytes, WithPresProofChallenge("stale"))
+		require.True(t, errors.Is(err, ErrProofChallengeMismatch))
+	})
+
+	t.Run("domain mismatch", func(t *testing.T) {
+		_, err := NewPresentation(vpBytes, WithPresProofDomain("example.org"))
+		require.True(t, errors.Is(err, ErrProofDomainMismatch))
+	})
+
+	t.Run("presentation without proof", func(t *testing.T) {
+		delete(raw, "proof")
+
+		unsignedBytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		disableProofCheck := func(opts *presentationOpts) {
+			opts.disabledProofCheck = true
+		}
+
+		_, err = NewPresentation(unsignedBytes, disableProofCheck, WithPresProofChallenge("3fa85f64"))
+		require.True(t, errors.Is(err, ErrProofChallengeMismatch))
+
+		_, err = NewPresentation(unsignedBytes, disableProofCheck, WithPresProofDomain("example.com"))
+		require.True(t, errors.Is(err, ErrProofDomainMismatch))
+
+		_, err = NewPresentation(unsignedBytes, disableProofCheck)
+		require.NoError(t, err)
+	})
+}
+
 func TestValidateVP_Context(t *testing.T) {
 	t.Run("rejects verifiable presentation with empty context", func(t *testing.T) {
 		raw := &rawPresentation{}
@@ -396,5 +447,18 @@ func TestWithPresEmbeddedSignatureSuites(t *testing.T) {
 
 	opts := &presentationOpts{}
 	vpOpt(opts)
-	require.Equal(t, suite, opts.ldpSuite)
+	require.Equal(t, []SignatureSuite{suite}, opts.ldpSuites)
+}
+
+func TestWithPresProofChallengeAndDomain(t *testing.T) {
+	opts := &presentationOpts{}
+	WithPresProofChallenge("3fa85f64")(opts)
+	WithPresProofDomain("example.com")(opts)
+	require.Equal(t, "3fa85f64", opts.proofChallenge)
+	require.Equal(t, "example.com", opts.proofDomain)
+
+	// the challenge and domain bind the presentation, not the embedded credentials
+	vcOpts := mapOpts(opts)
+	require.Empty(t, vcOpts.proofChallenge)
+	require.Empty(t, vcOpts.proofDomain)
 }
@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+// ErrHostNotAllowed is returned when the remote JSON-LD context or credential schema is hosted
+// outside of the hosts allowed by WithRemoteFetcher.
+var ErrHostNotAllowed = errors.New("host is not allowed")
+
+// ErrRemoteDocumentTooLarge is returned by HTTPRemoteFetcher when the remote document exceeds the maximum size
+// (10 MiB, the default maximum size of the credential).
+var ErrRemoteDocumentTooLarge = errors.New("remote document is too large")
+
+// maxRedirects is the number of the redirects of the remote document which are followed
+const maxRedirects = 10
+
+// RemoteFetcher fetches the document (JSON-LD context or JSON schema) by its URL.
+type RemoteFetcher func(url string) ([]byte, error)
+
+// redirectError is returned by HTTPRemoteFetcher when the document is redirected to the location
+type redirectError struct {
+	location string
+}
+
+func (e *redirectError) Error() string {
+	return fmt.Sprintf("fetch remote document: redirected to %s", e.location)
+}
+
+// HTTPRemoteFetcher creates RemoteFetcher which downloads the documents using the given HTTP client.
+// At most 10 MiB of the document is read, the larger document is rejected with ErrRemoteDocumentTooLarge.
+// The fetcher does not follow the redirects itself, they are followed by WithRemoteFetcher to the allowed hosts only.
+func HTTPRemoteFetcher(client *http.Client) RemoteFetcher {
+	noRedirectClient := *client
+	noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return func(url string) ([]byte, error) {
+		resp, err := noRedirectClient.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("fetch remote document: %w", err)
+		}
+
+		defer func() {
+			e := resp.Body.Close()
+			if e != nil {
+				logger.Errorf("closing response body failed [%v]", e)
+			}
+		}()
+
+		if location, e := resp.Location(); e == nil && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+			return nil, &redirectError{location: location.String()}
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch remote document: HTTP failure [%v]", resp.StatusCode)
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, defaultMaxSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("fetch remote document: read response body: %w", err)
+		}
+
+		if len(body) > defaultMaxSize {
+			return nil, fmt.Errorf("fetch remote document: %w: size exceeds %d bytes",
+				ErrRemoteDocumentTooLarge, defaultMaxSize)
+		}
+
+		return body, nil
+	}
+}
+
+// WithRemoteFetcher option defines the policy of fetching the remote JSON-LD contexts and credential schemas.
+// The documents are fetched by fetcher from the allowHosts only (host name, e.g. "www.w3.org"),
+// ErrHostNotAllowed is returned for the contexts and schemas of the other hosts (base VC context excepted),
+// as well as for the redirects of HTTPRemoteFetcher to them and the contexts imported or scoped by the contexts.
+// The option takes precedence over the HTTP client of the credential schema loader and the JSON-LD document
+// loader is created using the fetcher unless defined by WithJSONLDDocumentLoader.
+func WithRemoteFetcher(fetcher RemoteFetcher, allowHosts []string) CredentialOpt {
+	return func(opts *credentialOpts) {
+		policy := &remoteFetchPolicy{
+			fetcher:    fetcher,
+			allowHosts: make(map[string]bool, len(allowHosts)),
+		}
+
+		for _, host := range allowHosts {
+			policy.allowHosts[strings.ToLower(host)] = true
+		}
+
+		opts.remoteFetchPolicy = policy
+	}
+}
+
+// remoteFetchPolicy fetches the remote documents from the allowed hosts only
+type remoteFetchPolicy struct {
+	fetcher    RemoteFetcher
+	allowHosts map[string]bool
+}
+
+func (p *remoteFetchPolicy) fetch(rawURL string) ([]byte, error) {
+	for redirects := 0; ; redirects++ {
+		if err := p.checkURL(rawURL); err != nil {
+			return nil, err
+		}
+
+		doc, err := p.fetcher(rawURL)
+
+		var redirect *redirectError
+		if !errors.As(err, &redirect) {
+			return doc, err
+		}
+
+		if redirects == maxRedirects {
+			return nil, fmt.Errorf("fetch %s: stopped after %d redirects", rawURL, maxRedirects)
+		}
+
+		rawURL = redirect.location
+	}
+}
+
+func (p *remoteFetchPolicy) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse remote document URL: %w", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || !p.allowHosts[strings.ToLower(u.Hostname())] {
+		return fmt.Errorf("fetch %s: %w", rawURL, ErrHostNotAllowed)
+	}
+
+	return nil
+}
+
+// checkContexts checks that JSON-LD contexts are hosted by the allowed hosts, as well as the contexts which
+// are imported or scoped by them (the remote contexts are loaded by loader). Base context is preloaded
+// and it is not checked.
+func (p *remoteFetchPolicy) checkContexts(contexts []interface{}, loader ld.DocumentLoader) error {
+	checked := make(map[string]bool)
+
+	for _, context := range contexts {
+		if err := p.checkContext(context, loader, checked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *remoteFetchPolicy) checkContext(context interface{}, loader ld.DocumentLoader, checked map[string]bool) error {
+	switch c := context.(type) {
+	case string:
+		return p.checkRemoteContext(c, loader, checked)
+	case []interface{}:
+		for _, v := range c {
+			if err := p.checkContext(v, loader, checked); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if imported, ok := c["@import"]; ok {
+			if err := p.checkContext(imported, loader, checked); err != nil {
+				return err
+			}
+		}
+
+		// the term definitions may define scoped contexts
+		for _, v := range c {
+			definition, ok := v.(map[string]interface{})
+			if !ok || definition["@context"] == nil {
+				continue
+			}
+
+			if err := p.checkContext(definition["@context"], loader, checked); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *remoteFetchPolicy) checkRemoteContext(contextURL string, loader ld.DocumentLoader,
+	checked map[string]bool) error {
+	if contextURL == baseContext || checked[contextURL] {
+		return nil
+	}
+
+	checked[contextURL] = true
+
+	if err := p.checkURL(contextURL); err != nil {
+		return err
+	}
+
+	doc, err := loader.LoadDocument(contextURL)
+	if err != nil {
+		return fmt.Errorf("load JSON-LD context %s: %w", contextURL, err)
+	}
+
+	if docMap, ok := doc.Document.(map[string]interface{}); ok {
+		return p.checkContext(docMap["@context"], loader, checked)
+	}
+
+	return nil
+}
+
+// LoadDocument loads JSON-LD document, it implements ld.DocumentLoader.
+func (p *remoteFetchPolicy) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	docBytes, err := p.fetch(u)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ld.DocumentFromReader(bytes.NewReader(docBytes))
+	if err != nil {
+		return nil, fmt.Errorf("parse remote JSON-LD document: %w", err)
+	}
+
+	return &ld.RemoteDocument{DocumentURL: u, Document: doc}, nil
+}
@@ -0,0 +1,286 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRemoteFetcher(t *testing.T) {
+	documents := map[string]string{
+		"https://schemas.example.com/degree.json": defaultSchema,
+		"https://contexts.example.com/v1": `{
+  "@context": {
+    "referenceNumber": "https://example.com/vocab#referenceNumber"
+  }
+}`,
+		"https://contexts.example.com/imports": `{
+  "@context": {
+    "@version": 1.1,
+    "@import": "https://other.example.com/v1"
+  }
+}`,
+		"https://contexts.example.com/scoped": `{
+  "@context": {
+    "@version": 1.1,
+    "referenceNumber": {
+      "@id": "https://example.com/vocab#referenceNumber",
+      "@context": ["https://contexts.example.com/v1", "https://other.example.com/v1"]
+    }
+  }
+}`,
+	}
+
+	var fetched []string
+
+	fetcher := func(url string) ([]byte, error) {
+		fetched = append(fetched, url)
+
+		doc, ok := documents[url]
+		if !ok {
+			return nil, errors.New("document not found")
+		}
+
+		return []byte(doc), nil
+	}
+
+	t.Run("credential schema is fetched from allowed host", func(t *testing.T) {
+		fetched = nil
+
+		vcBytes := credentialWithSchema(t, "https://schemas.example.com/degree.json")
+
+		vc, _, err := NewCredential(vcBytes, WithRemoteFetcher(fetcher, []string{"Schemas.Example.com"}))
+		require.NoError(t, err)
+		require.NotNil(t, vc)
+		require.Equal(t, []string{"https://schemas.example.com/degree.json"}, fetched)
+	})
+
+	t.Run("credential schema of not allowed host", func(t *testing.T) {
+		fetched = nil
+
+		for _, schemaURL := range []string{
+			"https://schemas.example.com/degree.json",
+			"http://169.254.169.254/latest/meta-data",
+			"file://schemas.example.com/etc/passwd",
+		} {
+			vcBytes := credentialWithSchema(t, schemaURL)
+
+			_, _, err := NewCredential(vcBytes, WithRemoteFetcher(fetcher, []string{"contexts.example.com"}))
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrHostNotAllowed))
+		}
+
+		require.Empty(t, fetched)
+	})
+
+	t.Run("invalid credential schema URL", func(t *testing.T) {
+		vcBytes := credentialWithSchema(t, "https://schemas.example.com/%zz")
+
+		_, _, err := NewCredential(vcBytes, WithRemoteFetcher(fetcher, []string{"schemas.example.com"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse remote document URL")
+	})
+
+	t.Run("JSON-LD context is fetched from allowed host", func(t *testing.T) {
+		fetched = nil
+
+		vc, _, err := NewCredential(credentialWithContext("https://contexts.example.com/v1"),
+			WithJSONLDValidation(), WithRemoteFetcher(fetcher, []string{"contexts.example.com"}))
+		require.NoError(t, err)
+		require.NotNil(t, vc)
+		require.Equal(t, []string{"https://contexts.example.com/v1"}, fetched)
+	})
+
+	t.Run("JSON-LD context of not allowed host", func(t *testing.T) {
+		fetched = nil
+
+		_, _, err := NewCredential(credentialWithContext("https://contexts.example.com/v1"),
+			WithJSONLDValidation(), WithRemoteFetcher(fetcher, []string{"schemas.example.com"}))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHostNotAllowed))
+		require.Empty(t, fetched)
+	})
+
+	t.Run("JSON-LD contexts imported or scoped by the contexts are checked", func(t *testing.T) {
+		for _, contextURL := range []string{
+			"https://contexts.example.com/imports",
+			"https://contexts.example.com/scoped",
+		} {
+			fetched = nil
+
+			_, _, err := NewCredential(credentialWithContext(contextURL),
+				WithJSONLDValidation(), WithRemoteFetcher(fetcher, []string{"contexts.example.com"}))
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrHostNotAllowed))
+			require.NotContains(t, fetched, "https://other.example.com/v1")
+		}
+
+		// inline context
+		vcBytes := []byte(strings.Replace(string(credentialWithContext("https://contexts.example.com/v1")),
+			`"https://contexts.example.com/v1"`,
+			`{"@version": 1.1, "degree": {"@id": "https://example.com/vocab#degree",
+				"@context": "https://other.example.com/v1"}}`, 1))
+
+		_, _, err := NewCredential(vcBytes,
+			WithJSONLDValidation(), WithRemoteFetcher(fetcher, []string{"contexts.example.com"}))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrHostNotAllowed))
+
+		// the context can't be loaded
+		_, _, err = NewCredential(credentialWithContext("https://contexts.example.com/unknown"),
+			WithJSONLDValidation(), WithRemoteFetcher(fetcher, []string{"contexts.example.com"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "load JSON-LD context https://contexts.example.com/unknown")
+	})
+
+	t.Run("invalid JSON-LD context", func(t *testing.T) {
+		invalidFetcher := func(url string) ([]byte, error) {
+			return []byte("not JSON"), nil
+		}
+
+		_, _, err := NewCredential(credentialWithContext("https://contexts.example.com/v1"),
+			WithJSONLDValidation(), WithRemoteFetcher(invalidFetcher, []string{"contexts.example.com"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse remote JSON-LD document")
+
+		opts := &credentialOpts{}
+		WithRemoteFetcher(invalidFetcher, []string{"contexts.example.com"})(opts)
+
+		doc, err := opts.remoteFetchPolicy.LoadDocument("https://contexts.example.com/v1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse remote JSON-LD document")
+		require.Nil(t, doc)
+	})
+}
+
+func TestHTTPRemoteFetcher(t *testing.T) {
+	var testServer *httptest.Server
+
+	testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/redirect":
+			// the same server under another host name
+			http.Redirect(res, req, strings.Replace(testServer.URL, "127.0.0.1", "localhost", 1)+"/schema.json",
+				http.StatusFound)
+
+			return
+		case "/loop":
+			http.Redirect(res, req, "/loop", http.StatusFound)
+
+			return
+		case "/large.json":
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write(bytes.Repeat([]byte(" "), defaultMaxSize+1))
+			require.NoError(t, err)
+
+			return
+		}
+
+		if req.URL.Path != "/schema.json" {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(defaultSchema))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	fetcher := HTTPRemoteFetcher(&http.Client{})
+
+	t.Run("success", func(t *testing.T) {
+		doc, err := fetcher(testServer.URL + "/schema.json")
+		require.NoError(t, err)
+		require.Equal(t, defaultSchema, string(doc))
+	})
+
+	t.Run("HTTP failure", func(t *testing.T) {
+		doc, err := fetcher(testServer.URL + "/unknown.json")
+		require.EqualError(t, err, "fetch remote document: HTTP failure [404]")
+		require.Nil(t, doc)
+	})
+
+	t.Run("document is too large", func(t *testing.T) {
+		doc, err := fetcher(testServer.URL + "/large.json")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRemoteDocumentTooLarge))
+		require.Nil(t, doc)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		doc, err := fetcher("invalid URL")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch remote document")
+		require.Nil(t, doc)
+	})
+
+	t.Run("redirect is not followed by fetcher", func(t *testing.T) {
+		doc, err := fetcher(testServer.URL + "/redirect")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch remote document: redirected to http://localhost")
+		require.Nil(t, doc)
+	})
+
+	t.Run("redirect is followed to allowed hosts only", func(t *testing.T) {
+		opts := &credentialOpts{}
+		WithRemoteFetcher(fetcher, []string{"127.0.0.1"})(opts)
+
+		_, err := opts.remoteFetchPolicy.fetch(testServer.URL + "/redirect")
+		require.True(t, errors.Is(err, ErrHostNotAllowed))
+
+		WithRemoteFetcher(fetcher, []string{"127.0.0.1", "localhost"})(opts)
+
+		doc, err := opts.remoteFetchPolicy.fetch(testServer.URL + "/redirect")
+		require.NoError(t, err)
+		require.Equal(t, defaultSchema, string(doc))
+
+		_, err = opts.remoteFetchPolicy.fetch(testServer.URL + "/loop")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stopped after 10 redirects")
+	})
+}
+
+func credentialWithSchema(t *testing.T, schemaURL string) []byte {
+	var raw rawCredential
+	require.NoError(t, json.Unmarshal([]byte(validCredential), &raw))
+
+	raw.Schema = &TypedID{ID: schemaURL, Type: "JsonSchemaValidator2018"}
+
+	vcBytes, err := json.Marshal(raw)
+	require.NoError(t, err)
+
+	return vcBytes
+}
+
+func credentialWithContext(contextURL string) []byte {
+	return []byte(fmt.Sprintf(`
+{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    "%s"
+  ],
+  "id": "http://example.com/credentials/4643",
+  "type": "VerifiableCredential",
+  "issuer": "https://example.com/issuers/14",
+  "issuanceDate": "2018-02-24T05:28:04Z",
+  "referenceNumber": 83294847,
+  "credentialSubject": {
+    "id": "did:example:abcdef1234567"
+  }
+}
+`, contextURL))
+}
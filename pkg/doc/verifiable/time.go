@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// rawTime is a date of the Verifiable Credential (e.g. issuanceDate) which keeps its original
+// string representation. The date is marshalled in UTC unless the original representation is kept.
+type rawTime struct {
+	time time.Time
+	raw  string
+}
+
+// newRawTime creates rawTime from the given time. The original string representation is kept
+// only if it represents the same time instant (i.e. the time was not changed after decoding).
+func newRawTime(t *time.Time, raw string) *rawTime {
+	if t == nil {
+		return nil
+	}
+
+	if raw != "" {
+		if rawParsed, err := parseTime(raw); err != nil || !rawParsed.Equal(*t) {
+			raw = ""
+		}
+	}
+
+	return &rawTime{time: *t, raw: raw}
+}
+
+// MarshalJSON marshals the date using the original string representation if defined or RFC3339 in UTC otherwise.
+func (rt rawTime) MarshalJSON() ([]byte, error) {
+	if rt.raw != "" {
+		return json.Marshal(rt.raw)
+	}
+
+	return json.Marshal(rt.time.UTC().Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON parses RFC3339 date with optional fractional seconds and any valid time zone offset.
+func (rt *rawTime) UnmarshalJSON(data []byte) error {
+	var raw string
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("date must be a string: %w", err)
+	}
+
+	t, err := parseTime(raw)
+	if err != nil {
+		return err
+	}
+
+	rt.time = t
+	rt.raw = raw
+
+	return nil
+}
+
+// timePtr returns the parsed time or nil.
+func (rt *rawTime) timePtr() *time.Time {
+	if rt == nil {
+		return nil
+	}
+
+	t := rt.time
+
+	return &t
+}
+
+// rawString returns the original string representation of the time.
+func (rt *rawTime) rawString() string {
+	if rt == nil {
+		return ""
+	}
+
+	return rt.raw
+}
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date: %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawTime(t *testing.T) {
+	t.Run("unmarshal RFC3339 with fractional seconds and offset", func(t *testing.T) {
+		var rt rawTime
+		require.NoError(t, json.Unmarshal([]byte(`"2010-01-01T19:23:24.123+02:00"`), &rt))
+
+		expected := time.Date(2010, 1, 1, 17, 23, 24, 123000000, time.UTC)
+		require.True(t, expected.Equal(*rt.timePtr()))
+		require.Equal(t, "2010-01-01T19:23:24.123+02:00", rt.rawString())
+	})
+
+	t.Run("unmarshal invalid date", func(t *testing.T) {
+		var rt rawTime
+		err := json.Unmarshal([]byte(`"2010-01-01 19:23:24"`), &rt)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse date")
+
+		err = json.Unmarshal([]byte(`1262373804`), &rt)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "date must be a string")
+	})
+
+	t.Run("marshal in UTC", func(t *testing.T) {
+		issued := time.Date(2010, 1, 1, 19, 23, 24, 123000000, time.FixedZone("", 2*60*60))
+
+		bytes, err := json.Marshal(newRawTime(&issued, ""))
+		require.NoError(t, err)
+		require.Equal(t, `"2010-01-01T17:23:24.123Z"`, string(bytes))
+	})
+
+	t.Run("marshal original string", func(t *testing.T) {
+		raw := "2010-01-01T19:23:24.123+02:00"
+		issued, err := parseTime(raw)
+		require.NoError(t, err)
+
+		bytes, err := json.Marshal(newRawTime(&issued, raw))
+		require.NoError(t, err)
+		require.Equal(t, `"`+raw+`"`, string(bytes))
+
+		// original string is dropped if time was changed
+		changed := issued.Add(time.Second)
+		bytes, err = json.Marshal(newRawTime(&changed, raw))
+		require.NoError(t, err)
+		require.Equal(t, `"2010-01-01T17:23:25.123Z"`, string(bytes))
+
+		// invalid original string is dropped
+		bytes, err = json.Marshal(newRawTime(&issued, "invalid"))
+		require.NoError(t, err)
+		require.Equal(t, `"2010-01-01T17:23:24.123Z"`, string(bytes))
+	})
+
+	t.Run("nil time", func(t *testing.T) {
+		require.Nil(t, newRawTime(nil, "2010-01-01T19:23:24Z"))
+
+		var rt *rawTime
+		require.Nil(t, rt.timePtr())
+		require.Empty(t, rt.rawString())
+	})
+}
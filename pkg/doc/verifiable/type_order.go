@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// typeOpts defines how the "type" of the decoded credential or presentation is processed
+type typeOpts struct {
+	normalizeOrder  bool
+	requireBaseType bool
+}
+
+// WithTypeOrderNormalization moves VerifiableCredential to the first position of the credential types
+// keeping the order of the other types, so it's emitted first when the credential is marshalled.
+// The credential is validated after the normalization. The types are kept in the order they are defined by default.
+func WithTypeOrderNormalization() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.types.normalizeOrder = true
+	}
+}
+
+// WithBaseTypeRequired rejects the credential which doesn't have VerifiableCredential type.
+func WithBaseTypeRequired() CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.types.requireBaseType = true
+	}
+}
+
+// WithPresTypeOrderNormalization moves VerifiablePresentation to the first position of the presentation types
+// keeping the order of the other types, so it's emitted first when the presentation is marshalled.
+func WithPresTypeOrderNormalization() PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.types.normalizeOrder = true
+	}
+}
+
+// WithPresBaseTypeRequired rejects the presentation which doesn't have VerifiablePresentation type.
+func WithPresBaseTypeRequired() PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.types.requireBaseType = true
+	}
+}
+
+// processTypes normalizes the order of the types of JSON document and checks that the base type is present
+// (if requested by opts).
+func processTypes(data []byte, baseType string, opts typeOpts) ([]byte, error) {
+	if !opts.normalizeOrder && !opts.requireBaseType {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unmarshal types: %w", err)
+	}
+
+	if opts.requireBaseType && !hasRawType(doc["type"], baseType) {
+		return nil, fmt.Errorf("type %s is missing", baseType)
+	}
+
+	if !opts.normalizeOrder {
+		return data, nil
+	}
+
+	doc["type"] = normalizeTypeOrder(doc["type"], baseType)
+
+	return json.Marshal(doc)
+}
+
+// processPresentationTypes processes the types of both JSON and raw presentation.
+func processPresentationTypes(vpData []byte, vpRaw *rawPresentation, opts typeOpts) ([]byte, error) {
+	vpData, err := processTypes(vpData, vpType, opts)
+	if err != nil {
+		return nil, fmt.Errorf("decode new presentation: %w", err)
+	}
+
+	if opts.normalizeOrder {
+		vpRaw.Type = normalizeTypeOrder(vpRaw.Type, vpType)
+	}
+
+	return vpData, nil
+}
+
+func hasRawType(rawTypes interface{}, baseType string) bool {
+	switch types := rawTypes.(type) {
+	case string:
+		return types == baseType
+	case []interface{}:
+		for _, t := range types {
+			if t == baseType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// normalizeTypeOrder moves the base type to the first position of the raw types.
+func normalizeTypeOrder(rawTypes interface{}, baseType string) interface{} {
+	types, ok := rawTypes.([]interface{})
+	if !ok || !hasRawType(types, baseType) {
+		return rawTypes
+	}
+
+	normalized := make([]interface{}, 1, len(types))
+	normalized[0] = baseType
+
+	for _, t := range types {
+		if t != baseType {
+			normalized = append(normalized, t)
+		}
+	}
+
+	return normalized
+}
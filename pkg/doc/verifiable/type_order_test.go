@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTypeOrderNormalization(t *testing.T) {
+	vcWithTypes := func(t *testing.T, types ...interface{}) []byte {
+		var vcMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validCredential), &vcMap))
+
+		vcMap["type"] = types
+		vcMap["referenceNumber"] = json.Number("12345678901234567890")
+
+		vcBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		return vcBytes
+	}
+
+	t.Run("base type is moved to the first position", func(t *testing.T) {
+		vc, vcBytes, err := NewCredential(
+			vcWithTypes(t, "UniversityDegreeCredential", "VerifiableCredential", "AlumniCredential"),
+			WithTypeOrderNormalization())
+		require.NoError(t, err)
+		require.Equal(t, []string{"VerifiableCredential", "UniversityDegreeCredential", "AlumniCredential"},
+			vc.Types)
+		require.Contains(t, string(vcBytes), `"referenceNumber":12345678901234567890`)
+
+		vcJSON, err := vc.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(vcJSON),
+			`"type":["VerifiableCredential","UniversityDegreeCredential","AlumniCredential"]`)
+	})
+
+	t.Run("order is preserved by default", func(t *testing.T) {
+		vc, _, err := NewCredential(vcWithTypes(t, "VerifiableCredential", "UniversityDegreeCredential"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"VerifiableCredential", "UniversityDegreeCredential"}, vc.Types)
+
+		// the base JSON schema requires VerifiableCredential to be the first type
+		_, _, err = NewCredential(vcWithTypes(t, "UniversityDegreeCredential", "VerifiableCredential"))
+		require.Error(t, err)
+	})
+
+	t.Run("single type is kept", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(validCredential), WithTypeOrderNormalization())
+		require.NoError(t, err)
+		require.Equal(t, []string{"VerifiableCredential"}, vc.Types)
+	})
+
+	t.Run("base type is missing", func(t *testing.T) {
+		_, _, err := NewCredential(vcWithTypes(t, "UniversityDegreeCredential"),
+			WithTypeOrderNormalization(), WithBaseTypeRequired())
+		require.EqualError(t, err, "decode new credential: type VerifiableCredential is missing")
+
+		_, _, err = NewCredential(vcWithTypes(t, "VerifiableCredential", "UniversityDegreeCredential"),
+			WithBaseTypeRequired())
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		_, err := processTypes([]byte("{"), vcType, typeOpts{normalizeOrder: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal types")
+	})
+}
+
+func TestWithPresTypeOrderNormalization(t *testing.T) {
+	vpWithTypes := func(t *testing.T, types ...interface{}) []byte {
+		var vpMap map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(validPresentation), &vpMap))
+
+		vpMap["type"] = types
+
+		vpBytes, err := json.Marshal(vpMap)
+		require.NoError(t, err)
+
+		return vpBytes
+	}
+
+	t.Run("base type is moved to the first position", func(t *testing.T) {
+		vp, err := NewPresentation(vpWithTypes(t, "CredentialManagerPresentation", "VerifiablePresentation"),
+			WithPresTypeOrderNormalization())
+		require.NoError(t, err)
+		require.Equal(t, []string{"VerifiablePresentation", "CredentialManagerPresentation"}, vp.Type)
+	})
+
+	t.Run("base type is missing", func(t *testing.T) {
+		_, err := NewPresentation(vpWithTypes(t, "CredentialManagerPresentation"), WithPresBaseTypeRequired())
+		require.EqualError(t, err, "decode new presentation: type VerifiablePresentation is missing")
+	})
+}
@@ -0,0 +1,293 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+)
+
+const verificationCacheKeyPrefix = "vc-proof:"
+
+// Cache defines a cache of the results of the Verifiable Credential proof checks.
+// The cache is responsible for expiring its elements (see NewExpirableVerificationCache).
+type Cache interface {
+
+	// Get element from the cache, returns false at second return value if element is not present.
+	Get(k string) ([]byte, bool)
+
+	// Set element to the cache.
+	Set(k string, v []byte)
+}
+
+// ExpirableVerificationCache is an implementation of Cache with expirable elements.
+type ExpirableVerificationCache struct {
+	schemaCache *ExpirableSchemaCache
+}
+
+// VerificationCacheOpt is the option of ExpirableVerificationCache.
+type VerificationCacheOpt func(cache *ExpirableVerificationCache)
+
+// WithVerificationCacheClock sets the time source the elements of the cache expire by.
+// The real-time clock is used by default.
+func WithVerificationCacheClock(c clock.Clock) VerificationCacheOpt {
+	return func(cache *ExpirableVerificationCache) {
+		cache.schemaCache.clock = c
+	}
+}
+
+// NewExpirableVerificationCache creates new instance of ExpirableVerificationCache.
+// The verified credential is re-verified once the expiration has passed.
+func NewExpirableVerificationCache(size int, expiration time.Duration,
+	opts ...VerificationCacheOpt) *ExpirableVerificationCache {
+	cache := &ExpirableVerificationCache{schemaCache: NewExpirableSchemaCache(size, expiration)}
+
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	return cache
+}
+
+// Get element from the cache. Expired element is not present.
+func (c *ExpirableVerificationCache) Get(k string) ([]byte, bool) {
+	return c.schemaCache.Get(k)
+}
+
+// Set element to the cache. It also adds a mark of when the element will expire.
+func (c *ExpirableVerificationCache) Set(k string, v []byte) {
+	c.schemaCache.Put(k, v)
+}
+
+// verificationCacheKey is the hash of the canonical form of the credential and of the way its proof is verified
+// (see verificationScope). JSON credential is canonicalized, so the same credential is found in the cache
+// regardless of the order of its fields and whitespaces. JWS is taken as is.
+func verificationCacheKey(vcBytes []byte, vcOpts *credentialOpts) (string, error) {
+	canonicalBytes := vcBytes
+
+	if !isJWS(vcBytes) {
+		var vcJSON interface{}
+
+		if err := json.Unmarshal(vcBytes, &vcJSON); err != nil {
+			return "", fmt.Errorf("unmarshal credential: %w", err)
+		}
+
+		var err error
+
+		// the keys of JSON objects are sorted by json.Marshal
+		canonicalBytes, err = json.Marshal(vcJSON)
+		if err != nil {
+			return "", fmt.Errorf("marshal canonical credential: %w", err)
+		}
+	}
+
+	scope, err := verificationScope(vcOpts)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(canonicalBytes)
+	hash.Write([]byte{0})
+	hash.Write(scope)
+
+	return verificationCacheKeyPrefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verificationScope describes how the proof is verified, i.e. the signature suites (by their identity)
+// and the options which change the result of the proof check. The key fetchers are not part of the scope
+// as they can't be identified, the keys they fetched are kept in the cache instead (see checkCachedProof).
+func verificationScope(vcOpts *credentialOpts) ([]byte, error) {
+	suites := make([]string, len(vcOpts.ldpSuites))
+	for i, suite := range vcOpts.ldpSuites {
+		suites[i] = identity(suite)
+	}
+
+	scope, err := json.Marshal(&struct {
+		CandidateKeys  bool     `json:"candidateKeys"`
+		Suites         []string `json:"suites"`
+		ProofThreshold int      `json:"proofThreshold"`
+		ProofSigners   []string `json:"proofSigners"`
+	}{
+		CandidateKeys:  vcOpts.candidateKeysFetcher != nil,
+		Suites:         suites,
+		ProofThreshold: vcOpts.proofThreshold,
+		ProofSigners:   vcOpts.proofSigners,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal verification scope: %w", err)
+	}
+
+	return scope, nil
+}
+
+// identity identifies the signature suite by its instance.
+func identity(v interface{}) string {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Slice, reflect.UnsafePointer:
+		return fmt.Sprintf("%T@%x", v, rv.Pointer())
+	default:
+		return fmt.Sprintf("%T:%v", v, v)
+	}
+}
+
+// verifiedKey is the key which verified the proof of the cached credential, it is identified by
+// the fingerprint of its value.
+type verifiedKey struct {
+	IssuerID    string `json:"issuerID"`
+	KeyID       string `json:"keyID"`
+	Candidates  bool   `json:"candidates,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// keyRecorder records the keys fetched by the key fetchers while the proof is checked.
+type keyRecorder struct {
+	keys []verifiedKey
+	err  error
+}
+
+// wrap returns the copy of the options with the key fetchers recording the fetched keys.
+func (r *keyRecorder) wrap(vcOpts *credentialOpts) *credentialOpts {
+	recOpts := *vcOpts
+
+	if fetcher := vcOpts.publicKeyFetcher; fetcher != nil {
+		recOpts.publicKeyFetcher = func(issuerID, keyID string) (interface{}, error) {
+			key, err := fetcher(issuerID, keyID)
+			if err == nil {
+				r.record(issuerID, keyID, false, key)
+			}
+
+			return key, err
+		}
+	}
+
+	if fetcher := vcOpts.candidateKeysFetcher; fetcher != nil {
+		recOpts.candidateKeysFetcher = func(issuerID, keyID string) ([]*verifier.CandidateKey, error) {
+			keys, err := fetcher(issuerID, keyID)
+			if err == nil {
+				r.record(issuerID, keyID, true, keys)
+			}
+
+			return keys, err
+		}
+	}
+
+	return &recOpts
+}
+
+func (r *keyRecorder) record(issuerID, keyID string, candidates bool, key interface{}) {
+	fingerprint, err := keyFingerprint(key)
+	if err != nil {
+		r.err = err
+		return
+	}
+
+	r.keys = append(r.keys, verifiedKey{
+		IssuerID:    issuerID,
+		KeyID:       keyID,
+		Candidates:  candidates,
+		Fingerprint: fingerprint,
+	})
+}
+
+// keyFingerprint is the hash of the type and of JSON form of the fetched key(s).
+func keyFingerprint(key interface{}) (string, error) {
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("marshal fetched key: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(fmt.Sprintf("%T", key)))
+	hash.Write([]byte{0})
+	hash.Write(keyBytes)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// keysUnchanged checks whether the key fetchers of the options still fetch the keys which verified
+// the proof of the cached credential.
+func keysUnchanged(cached []byte, vcOpts *credentialOpts) bool {
+	var keys []verifiedKey
+
+	if err := json.Unmarshal(cached, &keys); err != nil {
+		return false
+	}
+
+	for _, k := range keys {
+		var (
+			key interface{}
+			err error
+		)
+
+		switch {
+		case k.Candidates && vcOpts.candidateKeysFetcher != nil:
+			key, err = vcOpts.candidateKeysFetcher(k.IssuerID, k.KeyID)
+		case !k.Candidates && vcOpts.publicKeyFetcher != nil:
+			key, err = vcOpts.publicKeyFetcher(k.IssuerID, k.KeyID)
+		default:
+			return false
+		}
+
+		if err != nil {
+			return false
+		}
+
+		fingerprint, err := keyFingerprint(key)
+		if err != nil || fingerprint != k.Fingerprint {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkCachedProof runs the check of the credential proof unless the credential was already verified
+// and is still present in the verification cache (if defined). Only the successful checks are cached
+// together with the keys which verified the proof, the cached credential is verified again if the key fetchers
+// of the options fetch other keys now (e.g. the closures of SingleKey with different keys).
+func checkCachedProof(vcBytes []byte, vcOpts *credentialOpts, check func(opts *credentialOpts) error) error {
+	if vcOpts.verificationCache == nil {
+		return check(vcOpts)
+	}
+
+	key, err := verificationCacheKey(vcBytes, vcOpts)
+	if err != nil {
+		return fmt.Errorf("verification cache key: %w", err)
+	}
+
+	if cached, ok := vcOpts.verificationCache.Get(key); ok && keysUnchanged(cached, vcOpts) {
+		return nil
+	}
+
+	recorder := &keyRecorder{}
+
+	if err = check(recorder.wrap(vcOpts)); err != nil {
+		return err
+	}
+
+	if recorder.err != nil { // the keys can't be identified, so the check is not cached
+		return nil
+	}
+
+	verifiedKeys, err := json.Marshal(recorder.keys)
+	if err != nil {
+		return fmt.Errorf("marshal verified keys: %w", err)
+	}
+
+	vcOpts.verificationCache.Set(key, verifiedKeys)
+
+	return nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/clock"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+)
+
+const docWithInvalidProof = `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "proof": {
+    "type": "Ed25519Signature2018",
+    "created": "2020-01-21T12:59:31+02:00",
+    "creator": "John",
+    "proofValue": "invalid value"
+  }
+}`
+
+type mapCache map[string][]byte
+
+func (c mapCache) Get(k string) ([]byte, bool) {
+	v, ok := c[k]
+	return v, ok
+}
+
+func (c mapCache) Set(k string, v []byte) {
+	c[k] = v
+}
+
+func TestWithVerificationCache(t *testing.T) {
+	privateKey, err := readPrivateKey(filepath.Join(certPrefix, "issuer_private.pem"))
+	require.NoError(t, err)
+
+	publicKey, err := readPublicKey(filepath.Join(certPrefix, "issuer_public.pem"))
+	require.NoError(t, err)
+
+	vc, _, err := NewCredential([]byte(validCredential))
+	require.NoError(t, err)
+
+	jwtClaims, err := vc.JWTClaims(true)
+	require.NoError(t, err)
+
+	jws, err := jwtClaims.MarshalJWS(RS256, privateKey, "any")
+	require.NoError(t, err)
+
+	fetches := 0
+	countingFetcher := func(issuerID, keyID string) (interface{}, error) {
+		fetches++
+
+		return publicKey, nil
+	}
+
+	t.Run("JWS is verified once", func(t *testing.T) {
+		fetches = 0
+		cache := mapCache{}
+
+		for i := 0; i < 3; i++ {
+			_, _, err = NewCredential([]byte(jws),
+				WithPublicKeyFetcher(countingFetcher), WithVerificationCache(cache))
+			require.NoError(t, err)
+		}
+
+		// the key is fetched each time to make sure it is the key which verified the cached JWS
+		require.Equal(t, 3, fetches)
+		require.Len(t, cache, 1)
+	})
+
+	t.Run("cached JWS is verified again by other key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		cache := mapCache{}
+
+		_, _, err = NewCredential([]byte(jws), WithPublicKeyFetcher(SingleKey(publicKey)), WithVerificationCache(cache))
+		require.NoError(t, err)
+		require.Len(t, cache, 1)
+
+		_, _, err = NewCredential([]byte(jws),
+			WithPublicKeyFetcher(SingleKey(&otherKey.PublicKey)), WithVerificationCache(cache))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "JWS decoding")
+
+		_, _, err = NewCredential([]byte(jws), WithPublicKeyFetcher(SingleKey(publicKey)), WithVerificationCache(cache))
+		require.NoError(t, err)
+	})
+
+	t.Run("key which can't be identified is not cached", func(t *testing.T) {
+		cache := mapCache{}
+		vcOpts := &credentialOpts{
+			publicKeyFetcher:  SingleKey(func() {}),
+			verificationCache: cache,
+		}
+
+		err := checkCachedProof([]byte(jws), vcOpts, func(opts *credentialOpts) error {
+			_, fetchErr := opts.publicKeyFetcher("issuer", "key")
+			return fetchErr
+		})
+		require.NoError(t, err)
+		require.Empty(t, cache)
+	})
+
+	t.Run("invalid JWS is not cached", func(t *testing.T) {
+		cache := mapCache{}
+
+		_, _, err = NewCredential([]byte(jws),
+			WithPublicKeyFetcher(func(issuerID, keyID string) (interface{}, error) {
+				return nil, errors.New("fetch error")
+			}),
+			WithVerificationCache(cache))
+		require.Error(t, err)
+		require.Empty(t, cache)
+	})
+
+	t.Run("embedded proof check is skipped for cached credential", func(t *testing.T) {
+		cache := mapCache{}
+		vcOpts := &credentialOpts{verificationCache: cache}
+
+		_, err = checkEmbeddedProof([]byte(docWithInvalidProof), vcOpts)
+		require.Error(t, err)
+		require.Empty(t, cache)
+
+		key, err := verificationCacheKey([]byte(docWithInvalidProof), &credentialOpts{})
+		require.NoError(t, err)
+
+		cache.Set(key, []byte("[]"))
+
+		docBytes, err := checkEmbeddedProof([]byte(docWithInvalidProof), vcOpts)
+		require.NoError(t, err)
+		require.Equal(t, docWithInvalidProof, string(docBytes))
+	})
+
+	t.Run("proof challenge is checked for cached credential", func(t *testing.T) {
+		key, err := verificationCacheKey([]byte(docWithInvalidProof), &credentialOpts{})
+		require.NoError(t, err)
+
+		_, err = checkEmbeddedProof([]byte(docWithInvalidProof), &credentialOpts{
+			proofChallenge:    "other",
+			verificationCache: mapCache{key: []byte("[]")},
+		})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrProofChallengeMismatch))
+	})
+}
+
+func Test_verificationCacheKey(t *testing.T) {
+	t.Run("key does not depend on fields order and whitespaces", func(t *testing.T) {
+		key1, err := verificationCacheKey([]byte(`{"id": "http://example.edu/credentials/1872", "type": "VC"}`),
+			&credentialOpts{})
+		require.NoError(t, err)
+
+		key2, err := verificationCacheKey([]byte(`{"type":"VC","id":"http://example.edu/credentials/1872"}`),
+			&credentialOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, key1, key2)
+
+		key3, err := verificationCacheKey([]byte(`{"type":"VC","id":"http://example.edu/credentials/1873"}`),
+			&credentialOpts{})
+		require.NoError(t, err)
+
+		require.NotEqual(t, key1, key3)
+	})
+
+	t.Run("key depends on the way the proof is verified", func(t *testing.T) {
+		vcBytes := []byte(`{"id":"http://example.edu/credentials/1872"}`)
+
+		fetcher := func(issuerID, keyID string) (interface{}, error) {
+			return nil, errors.New("not found")
+		}
+
+		candidatesFetcher := func(issuerID, keyID string) ([]*verifier.CandidateKey, error) {
+			return nil, errors.New("not found")
+		}
+
+		suite := ed25519signature2018.New()
+
+		optsList := []*credentialOpts{
+			{publicKeyFetcher: fetcher},
+			{candidateKeysFetcher: candidatesFetcher},
+			{publicKeyFetcher: fetcher, ldpSuites: []SignatureSuite{suite}},
+			{publicKeyFetcher: fetcher, ldpSuites: []SignatureSuite{ed25519signature2018.New()}},
+			{publicKeyFetcher: fetcher, proofThreshold: 1, proofSigners: []string{"did:example:a#key"}},
+			{publicKeyFetcher: fetcher, proofThreshold: 1, proofSigners: []string{"did:example:b#key"}},
+		}
+
+		keys := make(map[string]bool)
+
+		for _, vcOpts := range optsList {
+			key, err := verificationCacheKey(vcBytes, vcOpts)
+			require.NoError(t, err)
+
+			keys[key] = true
+		}
+
+		require.Len(t, keys, len(optsList))
+
+		key1, err := verificationCacheKey(vcBytes, &credentialOpts{publicKeyFetcher: fetcher,
+			ldpSuites: []SignatureSuite{suite}})
+		require.NoError(t, err)
+
+		key2, err := verificationCacheKey(vcBytes, &credentialOpts{publicKeyFetcher: fetcher,
+			ldpSuites: []SignatureSuite{suite}})
+		require.NoError(t, err)
+
+		require.Equal(t, key1, key2)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := verificationCacheKey([]byte("{"), &credentialOpts{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal credential")
+	})
+}
+
+func TestExpirableVerificationCache(t *testing.T) {
+	cache := NewExpirableVerificationCache(32*1024*1024, time.Hour)
+
+	_, ok := cache.Get("k")
+	require.False(t, ok)
+
+	cache.Set("k", []byte{1})
+
+	v, ok := cache.Get("k")
+	require.True(t, ok)
+	require.Equal(t, []byte{1}, v)
+
+	expiredCache := NewExpirableVerificationCache(32*1024*1024, -time.Hour)
+	expiredCache.Set("k", []byte{1})
+
+	_, ok = expiredCache.Get("k")
+	require.False(t, ok)
+
+	now := time.Now()
+	c := clock.Fixed(now)
+
+	clockCache := NewExpirableVerificationCache(32*1024*1024, time.Hour,
+		WithVerificationCacheClock(clock.Func(func() time.Time { return c.Now() })))
+	clockCache.Set("k", []byte{1})
+
+	_, ok = clockCache.Get("k")
+	require.True(t, ok)
+
+	c = clock.Fixed(now.Add(2 * time.Hour))
+
+	_, ok = clockCache.Get("k")
+	require.False(t, ok)
+}
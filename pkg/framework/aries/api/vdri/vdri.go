@@ -88,11 +88,12 @@ func WithNoCache(noCache bool) ResolveOpts {
 
 // CreateDIDOpts holds the options for creating DID
 type CreateDIDOpts struct {
-	ServiceType     string
-	KeyType         string
-	ServiceEndpoint string
-	RoutingKeys     []string
-	RequestBuilder  func([]byte) (io.Reader, error)
+	ServiceType      string
+	KeyType          string
+	ServiceEndpoint  string
+	RoutingKeys      []string
+	KeyAgreementKeys []string
+	RequestBuilder   func([]byte) (io.Reader, error)
 }
 
 // DocOpts is a create DID option
@@ -126,6 +127,13 @@ func WithRoutingKeys(routingKeys []string) DocOpts {
 	}
 }
 
+// WithKeyAgreementKeys allows for setting key agreement keys (base58 encoded).
+func WithKeyAgreementKeys(keys []string) DocOpts {
+	return func(opts *CreateDIDOpts) {
+		opts.KeyAgreementKeys = keys
+	}
+}
+
 // WithRequestBuilder allows to supply request builder
 // which can be used to add headers to request stream to be sent to HTTP binding URL
 func WithRequestBuilder(builder func(payload []byte) (io.Reader, error)) DocOpts {
@@ -12,11 +12,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 )
 
-// CloseableKMS interface
+// CloseableKMS interface, the key custody can be provided by an external KMS or HSM (see legacykms.KeyCustodian)
 type CloseableKMS interface {
 	io.Closer
-	legacykms.KeyManager
-	legacykms.Signer
+	legacykms.KeyCustodian
 }
 
 // KMSCreator method to create new key management service
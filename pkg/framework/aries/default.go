@@ -11,6 +11,7 @@ import (
 	"net/http"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
@@ -19,6 +20,7 @@ import (
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
@@ -48,14 +50,20 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 
 	// order is important as DIDExchange service depends on Route service and Introduce depends on DIDExchange
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
-		newRouteSvc(), newExchangeSvc(), newIntroduceSvc())
+		newRouteSvc(), newExchangeSvc(frameworkOpts.connectionHistory), newIntroduceSvc(), newMessagePickupSvc())
 
 	return setAdditionalDefaultOpts(frameworkOpts)
 }
 
-func newExchangeSvc() api.ProtocolSvcCreator {
+func newExchangeSvc(connectionHistory bool) api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		return didexchange.New(prv)
+		var opts []didexchange.Opt
+
+		if connectionHistory {
+			opts = append(opts, didexchange.WithConnectionHistory())
+		}
+
+		return didexchange.New(prv, opts...)
 	}
 }
 
@@ -65,6 +73,12 @@ func newIntroduceSvc() api.ProtocolSvcCreator {
 	}
 }
 
+func newMessagePickupSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return messagepickup.New(prv)
+	}
+}
+
 func newRouteSvc() api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
 		return route.New(prv)
@@ -122,6 +136,10 @@ func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 		frameworkOpts.msgSvcProvider = &noOpMessageServiceProvider{}
 	}
 
+	if frameworkOpts.msgHandlerRegistry == nil {
+		frameworkOpts.msgHandlerRegistry = service.NewRegistry()
+	}
+
 	// TODO add SecretLock creation here.. #1150
 
 	return nil
@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
@@ -45,8 +46,10 @@ type Aries struct {
 	protocolSvcCreators    []api.ProtocolSvcCreator
 	services               []dispatcher.ProtocolService
 	msgSvcProvider         api.MessageServiceProvider
+	msgHandlerRegistry     *service.Registry
 	outboundDispatcher     dispatcher.Outbound
 	messenger              service.MessengerHandler
+	inboundMiddlewares     []service.Middleware
 	outboundTransports     []transport.OutboundTransport
 	inboundTransports      []transport.InboundTransport
 	kmsCreator             api.KMSCreator
@@ -62,6 +65,7 @@ type Aries struct {
 	vdriRegistry           vdriapi.Registry
 	vdri                   []vdriapi.VDRI
 	transportReturnRoute   string
+	connectionHistory      bool
 	id                     string
 }
 
@@ -148,6 +152,15 @@ func WithMessengerHandler(mh service.MessengerHandler) Option {
 	}
 }
 
+// WithInboundMiddleware wraps the handling of the inbound messages by the messenger handler with the middlewares
+// (e.g. logging, authorization, metrics), the first middleware is the first to see the message.
+func WithInboundMiddleware(middlewares ...service.Middleware) Option {
+	return func(opts *Aries) error {
+		opts.inboundMiddlewares = append(opts.inboundMiddlewares, middlewares...)
+		return nil
+	}
+}
+
 // WithOutboundTransports injects an outbound transports to the Aries framework.
 func WithOutboundTransports(outboundTransports ...transport.OutboundTransport) Option {
 	return func(opts *Aries) error {
@@ -181,6 +194,15 @@ func WithTransportReturnRoute(transportReturnRoute string) Option {
 	}
 }
 
+// WithConnectionHistory enables persisting of the state transitions of DID exchange connections
+// (see didexchange.WithConnectionHistory), the history is available via didexchange client ConnectionHistory.
+func WithConnectionHistory() Option {
+	return func(opts *Aries) error {
+		opts.connectionHistory = true
+		return nil
+	}
+}
+
 // WithStoreProvider injects a storage provider to the Aries framework.
 func WithStoreProvider(prov storage.Provider) Option {
 	return func(opts *Aries) error {
@@ -247,6 +269,16 @@ func WithMessageServiceProvider(msv api.MessageServiceProvider) Option {
 	}
 }
 
+// WithMessageHandlerRegistry injects a message handler registry to the Aries framework.
+// Inbound messages which are not accepted by any protocol service are dispatched to the handler
+// registered for their message type namespace, and messages of unrecognized types to the default handler.
+func WithMessageHandlerRegistry(r *service.Registry) Option {
+	return func(opts *Aries) error {
+		opts.msgHandlerRegistry = r
+		return nil
+	}
+}
+
 // WithPacker injects at least one Packer service into the Aries framework,
 // with the primary Packer being used for inbound/outbound communication
 // and the additional packers being available for unpacking inbound messages.
@@ -279,6 +311,7 @@ func (a *Aries) Context() (*context.Provider, error) {
 		context.WithTransportReturnRoute(a.transportReturnRoute),
 		context.WithAriesFrameworkID(a.id),
 		context.WithMessageServiceProvider(a.msgSvcProvider),
+		context.WithMessageHandlerRegistry(a.msgHandlerRegistry),
 	)
 }
 
@@ -380,22 +413,66 @@ func createVDRI(frameworkOpts *Aries) error {
 
 func createMessengerHandler(frameworkOpts *Aries) error {
 	if frameworkOpts.messenger != nil {
+		if len(frameworkOpts.inboundMiddlewares) > 0 {
+			frameworkOpts.messenger = &middlewareMessenger{
+				MessengerHandler: frameworkOpts.messenger,
+				inbound:          service.Chain(frameworkOpts.messenger.HandleInbound, frameworkOpts.inboundMiddlewares...),
+			}
+		}
+
 		return nil
 	}
 
 	ctx, err := context.New(
 		context.WithOutboundDispatcher(frameworkOpts.outboundDispatcher),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
+		context.WithTransientStorageProvider(frameworkOpts.transientStoreProvider),
 	)
 	if err != nil {
 		return fmt.Errorf("context creation failed: %w", err)
 	}
 
-	frameworkOpts.messenger, err = messenger.NewMessenger(ctx)
+	// replies follow the DID rotations of the connections
+	connectionLookup, err := connection.NewLookup(ctx)
+	if err != nil {
+		return fmt.Errorf("create connection lookup: %w", err)
+	}
+
+	frameworkOpts.messenger, err = messenger.NewMessenger(ctx, messenger.WithDIDLookup(connectionLookup),
+		messenger.WithEncryptionPolicy(connectionLookup, encryptedOutbound(frameworkOpts.primaryPacker)),
+		messenger.WithMiddleware(frameworkOpts.inboundMiddlewares...))
 
 	return err
 }
 
+// encryptedOutbound reports that the outbound messages are encrypted if the outbound dispatcher packs them
+// by the primary packer which is one of the encrypted envelopes of the framework.
+func encryptedOutbound(primary packer.Packer) messenger.EncryptionCheck {
+	encrypted := false
+
+	if primary != nil {
+		switch primary.EncodingType() {
+		case commontransport.MediaTypeV1Envelope, commontransport.MediaTypeV2Envelope:
+			encrypted = true
+		}
+	}
+
+	return func(myDID, theirDID string) bool {
+		return encrypted
+	}
+}
+
+// middlewareMessenger passes the inbound messages through the middlewares before the injected messenger handler.
+type middlewareMessenger struct {
+	service.MessengerHandler
+	inbound service.InboundFunc
+}
+
+// HandleInbound handles the inbound message by the middlewares and the messenger handler.
+func (m *middlewareMessenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.inbound(msg, myDID, theirDID)
+}
+
 func createOutboundDispatcher(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithLegacyKMS(frameworkOpts.kms),
@@ -422,6 +499,7 @@ func startTransports(frameworkOpts *Aries) error {
 		context.WithProtocolServices(frameworkOpts.services...),
 		context.WithAriesFrameworkID(frameworkOpts.id),
 		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
+		context.WithMessageHandlerRegistry(frameworkOpts.msgHandlerRegistry),
 		context.WithMessengerHandler(frameworkOpts.messenger),
 	)
 	if err != nil {
@@ -19,16 +19,20 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/stretchr/testify/require"
 
+	didexchangeclient "github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
@@ -203,10 +207,39 @@ func TestFramework(t *testing.T) {
 
 		_, err = ctx.Service(didexchange.DIDExchange)
 		require.NoError(t, err)
+		_, err = ctx.Service(messagepickup.MessagePickup)
+		require.NoError(t, err)
 		err = aries.Close()
 		require.NoError(t, err)
 	})
 
+	t.Run("test protocol svc - with connection history", func(t *testing.T) {
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}), WithConnectionHistory())
+		require.NoError(t, err)
+		require.True(t, aries.connectionHistory)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		client, err := didexchangeclient.New(ctx)
+		require.NoError(t, err)
+
+		invitation, err := client.CreateInvitation("agent")
+		require.NoError(t, err)
+
+		connectionID, err := client.HandleInvitation(invitation)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			history, err := client.ConnectionHistory(connectionID)
+			require.NoError(t, err)
+
+			return len(history) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test protocol svc - with user provided protocol", func(t *testing.T) {
 		newMockSvc := func(prv api.Provider) (dispatcher.ProtocolService, error) {
 			return &mockdidexchange.MockDIDExchangeSvc{
@@ -495,6 +528,36 @@ func TestFramework(t *testing.T) {
 		require.Equal(t, messengerHandler, aries.Messenger())
 	})
 
+	t.Run("test new with inbound middleware", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var handled []string
+
+		middleware := func(next service.InboundFunc) service.InboundFunc {
+			return func(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+				handled = append(handled, msg.ID())
+
+				return next(msg, myDID, theirDID)
+			}
+		}
+
+		messengerHandler := mocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().HandleInbound(gomock.Any(), "myDID", "theirDID").Return(nil)
+
+		aries, err := New(WithMessengerHandler(messengerHandler), WithInboundMiddleware(middleware))
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "ID"}
+		require.NoError(t, aries.messenger.HandleInbound(msg, "myDID", "theirDID"))
+		require.Equal(t, []string{"ID"}, handled)
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test new with transport return route", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
@@ -580,6 +643,15 @@ func Test_Packager(t *testing.T) {
 	})
 }
 
+func Test_encryptedOutbound(t *testing.T) {
+	for _, typ := range []string{commontransport.MediaTypeV1Envelope, commontransport.MediaTypeV2Envelope} {
+		require.True(t, encryptedOutbound(&didcomm.MockAuthCrypt{Type: typ})("myDID", "theirDID"))
+	}
+
+	require.False(t, encryptedOutbound(&didcomm.MockAuthCrypt{Type: "NOOP"})("myDID", "theirDID"))
+	require.False(t, encryptedOutbound(nil)("myDID", "theirDID"))
+}
+
 func generateTempDir(t testing.TB) (string, func()) {
 	path, err := ioutil.TempDir("", "db")
 	if err != nil {
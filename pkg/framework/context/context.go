@@ -26,6 +26,7 @@ import (
 type Provider struct {
 	services               []dispatcher.ProtocolService
 	msgSvcProvider         api.MessageServiceProvider
+	msgHandlerRegistry     *service.Registry
 	storeProvider          storage.Provider
 	transientStoreProvider storage.Provider
 	kms                    legacykms.KMS
@@ -104,6 +105,11 @@ func (p *Provider) Messenger() service.Messenger {
 	return p.messenger
 }
 
+// MessageHandlerRegistry returns a registry of message handlers keyed by message type namespace.
+func (p *Provider) MessageHandlerRegistry() *service.Registry {
+	return p.msgHandlerRegistry
+}
+
 // Packers returns a list of enabled packers.
 func (p *Provider) Packers() []packer.Packer {
 	return p.packers
@@ -158,6 +164,13 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 			}
 		}
 
+		// then find the message handler registered for the message type namespace
+		if p.msgHandlerRegistry != nil {
+			if h, ok := p.msgHandlerRegistry.Handler(msg.Type()); ok {
+				return p.tryToHandle(h, msg, myDID, theirDID)
+			}
+		}
+
 		// in case of no services are registered for given message type,
 		// find generic inbound services registered for given message header
 		for _, svc := range p.msgSvcProvider.Services() {
@@ -175,6 +188,13 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 			}
 		}
 
+		// fall through to the default message handler for unrecognized message types
+		if p.msgHandlerRegistry != nil {
+			if h, ok := p.msgHandlerRegistry.DefaultHandler(); ok {
+				return p.tryToHandle(h, msg, myDID, theirDID)
+			}
+		}
+
 		return fmt.Errorf("no message handlers found for the message type: %s", msg.Type())
 	}
 }
@@ -348,3 +368,11 @@ func WithMessageServiceProvider(msv api.MessageServiceProvider) ProviderOption {
 		return nil
 	}
 }
+
+// WithMessageHandlerRegistry injects a message handler registry into the context
+func WithMessageHandlerRegistry(r *service.Registry) ProviderOption {
+	return func(opts *Provider) error {
+		opts.msgHandlerRegistry = r
+		return nil
+	}
+}
@@ -214,6 +214,59 @@ func TestNewProvider(t *testing.T) {
 		}
 	})
 
+	t.Run("test new with message handler registry", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessengerHandler(ctrl)
+		messenger.EXPECT().
+			HandleInbound(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil).
+			AnyTimes()
+
+		var handled []string
+
+		newHandler := func(name string) service.MessageHandler {
+			return &generic.MockMessageSvc{
+				HandleFunc: func(*service.DIDCommMsg) (string, error) {
+					handled = append(handled, name)
+					return "", nil
+				},
+			}
+		}
+
+		registry := service.NewRegistry()
+		require.NoError(t, registry.Register("https://didcomm.org/basicmessage/", newHandler("basic")))
+
+		prov, err := New(
+			WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+				AcceptFunc: func(msgType string) bool { return msgType == "valid-message-type" },
+			}),
+			WithMessageHandlerRegistry(registry),
+			WithMessageServiceProvider(msghandler.NewMockMsgServiceProvider()),
+			WithMessengerHandler(messenger),
+		)
+		require.NoError(t, err)
+
+		inboundHandler := prov.InboundMessageHandler()
+
+		err = inboundHandler([]byte(`{"@type": "https://didcomm.org/basicmessage/1.0/message"}`), "did1", "did2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"basic"}, handled)
+
+		// protocol services take precedence
+		err = inboundHandler([]byte(`{"@type": "valid-message-type"}`), "did1", "did2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"basic"}, handled)
+
+		err = inboundHandler([]byte(`{"@type": "https://didcomm.org/trust_ping/1.0/ping"}`), "did1", "did2")
+		require.EqualError(t, err,
+			"no message handlers found for the message type: https://didcomm.org/trust_ping/1.0/ping")
+
+		registry.SetDefault(newHandler("default"))
+
+		err = inboundHandler([]byte(`{"@type": "https://didcomm.org/trust_ping/1.0/ping"}`), "did1", "did2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"basic", "default"}, handled)
+	})
+
 	t.Run("test new with kms and packager service", func(t *testing.T) {
 		prov, err := New(
 			WithLegacyKMS(&mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}),
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	service "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	reflect "reflect"
@@ -74,3 +75,31 @@ func (mr *MockOutboundMockRecorder) SendToDID(arg0, arg1, arg2 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToDID", reflect.TypeOf((*MockOutbound)(nil).SendToDID), arg0, arg1, arg2)
 }
+
+// SendToDIDWithContext mocks base method
+func (m *MockOutbound) SendToDIDWithContext(arg0 context.Context, arg1 interface{}, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendToDIDWithContext", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendToDIDWithContext indicates an expected call of SendToDIDWithContext
+func (mr *MockOutboundMockRecorder) SendToDIDWithContext(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToDIDWithContext", reflect.TypeOf((*MockOutbound)(nil).SendToDIDWithContext), arg0, arg1, arg2, arg3)
+}
+
+// SendWithContext mocks base method
+func (m *MockOutbound) SendWithContext(arg0 context.Context, arg1 interface{}, arg2 string, arg3 *service.Destination) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendWithContext", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendWithContext indicates an expected call of SendWithContext
+func (mr *MockOutboundMockRecorder) SendWithContext(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendWithContext", reflect.TypeOf((*MockOutbound)(nil).SendWithContext), arg0, arg1, arg2, arg3)
+}
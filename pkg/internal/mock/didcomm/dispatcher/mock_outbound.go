@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"context"
+
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 )
 
@@ -26,6 +28,12 @@ func (m *MockOutbound) Send(msg interface{}, senderVerKey string, des *service.D
 	return m.SendErr
 }
 
+// SendWithContext msg
+func (m *MockOutbound) SendWithContext(_ context.Context, msg interface{}, senderVerKey string,
+	des *service.Destination) error {
+	return m.Send(msg, senderVerKey, des)
+}
+
 // SendToDID msg
 func (m *MockOutbound) SendToDID(msg interface{}, myDID, theirDID string) error {
 	if m.ValidateSendToDID != nil {
@@ -35,6 +43,11 @@ func (m *MockOutbound) SendToDID(msg interface{}, myDID, theirDID string) error
 	return m.SendErr
 }
 
+// SendToDIDWithContext msg
+func (m *MockOutbound) SendToDIDWithContext(_ context.Context, msg interface{}, myDID, theirDID string) error {
+	return m.SendToDID(msg, myDID, theirDID)
+}
+
 // Forward msg
 func (m *MockOutbound) Forward(msg interface{}, des *service.Destination) error {
 	if m.ValidateForward != nil {
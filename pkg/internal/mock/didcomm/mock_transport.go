@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package didcomm
 
 import (
+	"context"
+
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 )
@@ -32,6 +34,12 @@ func (o *MockOutboundTransport) Send(data []byte, destination *service.Destinati
 	return o.ExpectedResponse, o.SendErr
 }
 
+// SendWithContext implementation of MockOutboundTransport.SendWithContext api
+func (o *MockOutboundTransport) SendWithContext(ctx context.Context, data []byte,
+	destination *service.Destination) (string, error) {
+	return o.Send(data, destination)
+}
+
 // AcceptRecipient checks if there is a connection for the list of recipient keys
 func (o *MockOutboundTransport) AcceptRecipient([]string) bool {
 	return false
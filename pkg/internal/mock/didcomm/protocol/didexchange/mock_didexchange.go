@@ -27,6 +27,7 @@ import (
 type MockDIDExchangeSvc struct {
 	ProtocolName             string
 	HandleFunc               func(service.DIDCommMsg) (string, error)
+	HandleInvitationFunc     func(msg service.DIDCommMsg, metadata map[string]interface{}) (string, error)
 	HandleOutboundFunc       func(msg service.DIDCommMsg, myDID, theirDID string) error
 	AcceptFunc               func(string) bool
 	RegisterActionEventErr   error
@@ -35,6 +36,9 @@ type MockDIDExchangeSvc struct {
 	UnregisterMsgEventErr    error
 	AcceptError              error
 	ImplicitInvitationErr    error
+	ImplicitInvitationFunc   func(inviterLabel, inviterDID, inviteeLabel, inviteeDID string) (string, error)
+	RotateDIDFunc            func(connectionID, newDID string) error
+	ReuseConnectionFunc      func(connectionID, invitationID string) error
 }
 
 // HandleInbound msg
@@ -46,6 +50,15 @@ func (m *MockDIDExchangeSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirD
 	return uuid.New().String(), nil
 }
 
+// HandleInvitation msg
+func (m *MockDIDExchangeSvc) HandleInvitation(msg service.DIDCommMsg, metadata map[string]interface{}) (string, error) {
+	if m.HandleInvitationFunc != nil {
+		return m.HandleInvitationFunc(msg, metadata)
+	}
+
+	return m.HandleInbound(msg, "", "")
+}
+
 // HandleOutbound msg
 func (m *MockDIDExchangeSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
 	if m.HandleOutboundFunc != nil {
@@ -133,9 +146,31 @@ func (m *MockDIDExchangeSvc) CreateImplicitInvitation(inviterLabel, inviterDID,
 		return "", m.ImplicitInvitationErr
 	}
 
+	if m.ImplicitInvitationFunc != nil {
+		return m.ImplicitInvitationFunc(inviterLabel, inviterDID, inviteeLabel, inviteeDID)
+	}
+
 	return "connection-id", nil
 }
 
+// RotateDID rotates my DID of the connection
+func (m *MockDIDExchangeSvc) RotateDID(connectionID, newDID string) error {
+	if m.RotateDIDFunc != nil {
+		return m.RotateDIDFunc(connectionID, newDID)
+	}
+
+	return nil
+}
+
+// ReuseConnection notifies the inviter of the reuse of the connection
+func (m *MockDIDExchangeSvc) ReuseConnection(connectionID, invitationID string) error {
+	if m.ReuseConnectionFunc != nil {
+		return m.ReuseConnectionFunc(connectionID, invitationID)
+	}
+
+	return nil
+}
+
 // MockProvider is provider for DIDExchange Service
 type MockProvider struct {
 	StoreProvider          *mockstore.MockStoreProvider
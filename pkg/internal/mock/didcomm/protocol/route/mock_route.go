@@ -22,6 +22,7 @@ type MockRouteSvc struct {
 	RegisterFunc       func(connectionID string) error
 	RouterEndpoint     string
 	RoutingKeys        []string
+	RouterConnectionID string
 	ConfigErr          error
 	AddKeyErr          error
 	UnregisterErr      error
@@ -95,3 +96,12 @@ func (m *MockRouteSvc) Config() (*route.Config, error) {
 
 	return route.NewConfig(m.RouterEndpoint, m.RoutingKeys), nil
 }
+
+// ConnectionConfig gives back the configuration of the router registered for the connection (RouterConnectionID)
+func (m *MockRouteSvc) ConnectionConfig(connectionID string) (*route.Config, error) {
+	if m.ConfigErr == nil && m.RouterConnectionID != connectionID {
+		return nil, route.ErrRouterNotRegistered
+	}
+
+	return m.Config()
+}
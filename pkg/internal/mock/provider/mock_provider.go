@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
@@ -26,6 +27,8 @@ type Provider struct {
 	PackerValue                   packer.Packer
 	OutboundDispatcherValue       dispatcher.Outbound
 	VDRIRegistryValue             vdriapi.Registry
+	MessengerValue                service.Messenger
+	MessageHandlerRegistryValue   *service.Registry
 }
 
 // Service return service
@@ -85,3 +88,13 @@ func (p *Provider) OutboundDispatcher() dispatcher.Outbound {
 func (p *Provider) VDRIRegistry() vdriapi.Registry {
 	return p.VDRIRegistryValue
 }
+
+// Messenger return messenger
+func (p *Provider) Messenger() service.Messenger {
+	return p.MessengerValue
+}
+
+// MessageHandlerRegistry return message handler registry
+func (p *Provider) MessageHandlerRegistry() *service.Registry {
+	return p.MessageHandlerRegistryValue
+}
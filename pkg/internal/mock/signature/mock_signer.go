@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+)
+
+// Ed25519Signer mocks signer of the signature suites using ed25519 private key.
+type Ed25519Signer struct {
+	privateKey []byte
+}
+
+// NewEd25519Signer returns new instance of Ed25519Signer, the key of invalid length is accepted
+// to test signing failures.
+func NewEd25519Signer(privKey []byte) *Ed25519Signer {
+	return &Ed25519Signer{privateKey: privKey}
+}
+
+// Sign will sign data and return signature.
+func (s *Ed25519Signer) Sign(doc []byte) ([]byte, error) {
+	if l := len(s.privateKey); l != ed25519.PrivateKeySize {
+		return nil, errors.New("ed25519: bad private key length")
+	}
+
+	return ed25519.Sign(s.privateKey, doc), nil
+}
+
+// PublicKey returns the public key corresponding to the signing key.
+func (s *Ed25519Signer) PublicKey() crypto.PublicKey {
+	if l := len(s.privateKey); l != ed25519.PrivateKeySize {
+		return nil
+	}
+
+	return ed25519.PrivateKey(s.privateKey).Public()
+}
+
+// KeyID returns ID of the signing key.
+func (s *Ed25519Signer) KeyID() string {
+	return ""
+}
@@ -15,6 +15,23 @@ type KMS interface {
 	Signer
 }
 
+// KeyCustodian keeps the private keys and performs the operations which require them, so the crypto operations
+// (e.g. CryptoBox) never read the private keys and the key custody can be swapped for an external KMS or HSM.
+// BaseKMS is the default implementation backed by the storage provider.
+type KeyCustodian interface {
+	KeyManager
+	Signer
+
+	// DeriveECDH computes X25519 shared secret of the private key corresponding to myPub and theirPub.
+	//
+	// Returns:
+	//
+	// []byte: raw 32 bytes shared secret
+	//
+	// error: ErrKeyNotFound if myPub is not managed by the LegacyKMS, other error in case of errors
+	DeriveECDH(myPub, theirPub []byte) ([]byte, error)
+}
+
 // KeyManager interface provides key management operations (create, find, get, derive, etc.)
 type KeyManager interface {
 	KeyConverter
@@ -27,6 +44,14 @@ type KeyManager interface {
 	// error: error
 	CreateKeySet() (string, string, error)
 
+	// CreateKeyAgreementKey creates a new X25519 key agreement keypair used for packing and unpacking messages.
+	//
+	// Returns:
+	// string: key id - base58 encoded X25519 public key
+	// []byte: raw X25519 public key
+	// error: error
+	CreateKeyAgreementKey() (string, []byte, error)
+
 	// DeriveKEK will derive an ephemeral symmetric key (kek) using a private from key fetched from
 	// from the LegacyKMS corresponding to fromPubKey and derived with toPubKey.
 	//
@@ -46,6 +71,15 @@ type KeyManager interface {
 	//		in case of error, the index will be -1
 	FindVerKey(candidateKeys []string) (int, error)
 
+	// FindEncKey will search the LegacyKMS to find stored encryption keys (including key agreement keys)
+	// 		that match any of candidateKeys and return the index of the first match
+	// returns:
+	// 		int index of candidateKeys that matches the first key found in the LegacyKMS
+	//		error in case of errors (including ErrKeyNotFound)
+	//
+	//		in case of error, the index will be -1
+	FindEncKey(candidateKeys []string) (int, error)
+
 	// GetEncryptionKey will return the public encryption key corresponding to the public verKey argument
 	GetEncryptionKey(verKey []byte) ([]byte, error)
 }
@@ -67,6 +101,21 @@ type Signer interface {
 	//
 	// error: error
 	SignMessage(message []byte, fromVerKey string) ([]byte, error)
+
+	// VerifyMessage verifies a signature of the message using the key pair associated with a given verification key.
+	//
+	// Args:
+	//
+	// message: The signed message
+	//
+	// signature: The signature to verify
+	//
+	// fromVerKey: Verify using this verification key (it must be managed by the LegacyKMS)
+	//
+	// Returns:
+	//
+	// error: ErrInvalidSignature if the signature does not match, other error in case of errors
+	VerifyMessage(message, signature []byte, fromVerKey string) error
 }
 
 // KeyConverter provides methods for converting signing to encryption keys
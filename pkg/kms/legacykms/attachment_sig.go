@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+const (
+	attachmentSigAlg = "EdDSA"
+	okpKeyType       = "OKP"
+	ed25519CurveName = "Ed25519"
+)
+
+// AttachmentSig is the detached JWS of the attachment payload, i.e. "jws" of the attachment data.
+// https://github.com/hyperledger/aries-rfcs/tree/master/concepts/0017-attachments#signing-attachments
+type AttachmentSig struct {
+	Header    map[string]string `json:"header,omitempty"`
+	Protected string            `json:"protected"`
+	Signature string            `json:"signature"`
+}
+
+// attachmentSigHeader is the protected header of the attachment signature
+type attachmentSigHeader struct {
+	Alg string            `json:"alg"`
+	KID string            `json:"kid,omitempty"`
+	JWK *attachmentSigJWK `json:"jwk"`
+}
+
+// attachmentSigJWK is the JWK of the Ed25519 verification key of the attachment signature
+type attachmentSigJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// SignAttachment signs the attachment payload using the private key associated with the given verification key.
+// The detached JWS is created, its protected header keeps the verification key as "kid" (base58) and "jwk".
+func (w *BaseKMS) SignAttachment(data []byte, verKey string) (*AttachmentSig, error) {
+	kpc, err := w.getKeyPairSet(verKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign attachment: failed to get key: %w", err)
+	}
+
+	if kpc.SigKeyPair == nil || kpc.SigKeyPair.Alg != cryptoutil.EdDSA {
+		return nil, fmt.Errorf("sign attachment: %w", cryptoutil.ErrInvalidKey)
+	}
+
+	protected, err := json.Marshal(&attachmentSigHeader{
+		Alg: attachmentSigAlg,
+		KID: verKey,
+		JWK: &attachmentSigJWK{
+			Kty: okpKeyType,
+			Crv: ed25519CurveName,
+			X:   base64.RawURLEncoding.EncodeToString(kpc.SigKeyPair.Pub),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign attachment: marshal protected header: %w", err)
+	}
+
+	sig := &AttachmentSig{
+		Header:    map[string]string{"kid": verKey},
+		Protected: base64.RawURLEncoding.EncodeToString(protected),
+	}
+
+	signature, err := w.SignMessage(attachmentSigningInput(sig.Protected, data), verKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign attachment: %w", err)
+	}
+
+	sig.Signature = base64.RawURLEncoding.EncodeToString(signature)
+
+	return sig, nil
+}
+
+// VerifyAttachment verifies the detached JWS of the attachment payload against the expected verification key
+// (base58) of the signer, e.g. the key of the connection. The key of the protected header must be the expected one,
+// the key does not need to be managed by the LegacyKMS.
+// ErrInvalidSignature is returned if the signature does not match or it is made with another key.
+func (w *BaseKMS) VerifyAttachment(data []byte, sig *AttachmentSig, verKey string) error {
+	if sig == nil {
+		return errors.New("verify attachment: signature is not defined")
+	}
+
+	if verKey == "" {
+		return errors.New("verify attachment: verification key is not defined")
+	}
+
+	header, err := decodeAttachmentSigHeader(sig.Protected)
+	if err != nil {
+		return fmt.Errorf("verify attachment: %w", err)
+	}
+
+	pubKey, err := base64.RawURLEncoding.DecodeString(header.JWK.X)
+	if err != nil {
+		return fmt.Errorf("verify attachment: decode jwk: %w", err)
+	}
+
+	if header.KID != "" && header.KID != base58.Encode(pubKey) {
+		return fmt.Errorf("verify attachment: kid %s does not match jwk", header.KID)
+	}
+
+	if base58.Encode(pubKey) != verKey {
+		return fmt.Errorf("verify attachment: %w: signed with another key", ErrInvalidSignature)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("verify attachment: decode signature: %w", err)
+	}
+
+	return verifyEd25519(pubKey, attachmentSigningInput(sig.Protected, data), signature)
+}
+
+func decodeAttachmentSigHeader(protected string) (*attachmentSigHeader, error) {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return nil, fmt.Errorf("decode protected header: %w", err)
+	}
+
+	var header attachmentSigHeader
+
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("unmarshal protected header: %w", err)
+	}
+
+	if header.Alg != attachmentSigAlg {
+		return nil, fmt.Errorf("unsupported signature algorithm '%s'", header.Alg)
+	}
+
+	if header.JWK == nil || header.JWK.Kty != okpKeyType || header.JWK.Crv != ed25519CurveName {
+		return nil, errors.New("protected header has no Ed25519 jwk")
+	}
+
+	return &header, nil
+}
+
+// attachmentSigningInput returns JWS signing input of the detached payload
+func attachmentSigningInput(protected string, data []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(data))
+}
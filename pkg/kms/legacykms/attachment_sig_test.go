@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestBaseKMS_SignAttachment(t *testing.T) {
+	newKMS := func(t *testing.T) *BaseKMS {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{
+			Store: &mockstorage.MockStore{
+				Store: make(map[string][]byte),
+			}}))
+		require.NoError(t, err)
+
+		return k
+	}
+
+	data := []byte(`{"@type":"https://didcomm.org/issue-credential/1.0/credential"}`)
+
+	t.Run("test success", func(t *testing.T) {
+		signer := newKMS(t)
+		_, verKey, err := signer.CreateKeySet()
+		require.NoError(t, err)
+
+		sig, err := signer.SignAttachment(data, verKey)
+		require.NoError(t, err)
+		require.Equal(t, verKey, sig.Header["kid"])
+
+		header, err := decodeAttachmentSigHeader(sig.Protected)
+		require.NoError(t, err)
+		require.Equal(t, "EdDSA", header.Alg)
+		require.Equal(t, verKey, header.KID)
+
+		// the signature is verified by the party which does not manage the key
+		require.NoError(t, newKMS(t).VerifyAttachment(data, sig, verKey))
+
+		// the signature can be embedded into attachment
+		sigBytes, err := json.Marshal(sig)
+		require.NoError(t, err)
+
+		attachment := decorator.AttachmentData{
+			Base64: base64.StdEncoding.EncodeToString(data),
+			JWS:    sigBytes,
+		}
+
+		attachmentBytes, err := json.Marshal(attachment)
+		require.NoError(t, err)
+
+		var received decorator.AttachmentData
+		require.NoError(t, json.Unmarshal(attachmentBytes, &received))
+
+		var receivedSig AttachmentSig
+		require.NoError(t, json.Unmarshal(received.JWS, &receivedSig))
+
+		receivedData, err := received.Fetch()
+		require.NoError(t, err)
+		require.NoError(t, signer.VerifyAttachment(receivedData, &receivedSig, verKey))
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		_, err := newKMS(t).SignAttachment(data, "unknown")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+
+	t.Run("test invalid signature", func(t *testing.T) {
+		k := newKMS(t)
+		_, verKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		sig, err := k.SignAttachment(data, verKey)
+		require.NoError(t, err)
+
+		err = k.VerifyAttachment([]byte("other data"), sig, verKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		_, otherVerKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		otherSig, err := k.SignAttachment(data, otherVerKey)
+		require.NoError(t, err)
+
+		err = k.VerifyAttachment(data, &AttachmentSig{Protected: sig.Protected, Signature: otherSig.Signature}, verKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		// the signature of another key is valid by itself, but it is not made with the expected key
+		require.NoError(t, k.VerifyAttachment(data, otherSig, otherVerKey))
+
+		err = k.VerifyAttachment(data, otherSig, verKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+		require.Contains(t, err.Error(), "signed with another key")
+
+		err = k.VerifyAttachment(data, &AttachmentSig{Protected: sig.Protected, Signature: "!"}, verKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode signature")
+	})
+
+	t.Run("test invalid protected header", func(t *testing.T) {
+		k := newKMS(t)
+
+		err := k.VerifyAttachment(data, nil, "verKey")
+		require.EqualError(t, err, "verify attachment: signature is not defined")
+
+		err = k.VerifyAttachment(data, &AttachmentSig{}, "")
+		require.EqualError(t, err, "verify attachment: verification key is not defined")
+
+		tests := []struct {
+			name      string
+			protected string
+			errMsg    string
+		}{
+			{
+				name:      "not base64",
+				protected: "!",
+				errMsg:    "decode protected header",
+			},
+			{
+				name:      "not JSON",
+				protected: base64.RawURLEncoding.EncodeToString([]byte("{")),
+				errMsg:    "unmarshal protected header",
+			},
+			{
+				name:      "unsupported algorithm",
+				protected: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256"}`)),
+				errMsg:    "unsupported signature algorithm 'ES256'",
+			},
+			{
+				name:      "no jwk",
+				protected: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA"}`)),
+				errMsg:    "protected header has no Ed25519 jwk",
+			},
+			{
+				name: "invalid jwk",
+				protected: base64.RawURLEncoding.EncodeToString(
+					[]byte(`{"alg":"EdDSA","jwk":{"kty":"OKP","crv":"Ed25519","x":"!"}}`)),
+				errMsg: "decode jwk",
+			},
+			{
+				name: "kid does not match jwk",
+				protected: base64.RawURLEncoding.EncodeToString(
+					[]byte(`{"alg":"EdDSA","kid":"abc","jwk":{"kty":"OKP","crv":"Ed25519","x":"AQID"}}`)),
+				errMsg: "kid abc does not match jwk",
+			},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				err := k.VerifyAttachment(data, &AttachmentSig{Protected: tc.protected}, "verKey")
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+}
@@ -11,31 +11,29 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/btcsuite/btcutil/base58"
 	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/salsa20/salsa"
 
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
 )
 
-// TODO: move CryptoBox out of the LegacyKMS package.
-//   this currently only sits inside LegacyKMS so it can access private keys. See issue #511
-
 // CryptoBox provides an elliptic-curve-based authenticated encryption scheme
 //
 // Payloads are encrypted using symmetric encryption (XChacha20Poly1305)
 // using a shared key derived from a shared secret created by
 //   Curve25519 Elliptic Curve Diffie-Hellman key exchange.
 //
-// CryptoBox is created by a LegacyKMS, and reads secret keys from the LegacyKMS
-//   for encryption/decryption, so clients do not need to see
+// CryptoBox is created by a LegacyKMS, and derives the shared secrets by the key custodian
+//   for encryption/decryption, so neither clients nor CryptoBox see
 //   the secrets themselves.
 type CryptoBox struct {
-	km *BaseKMS
+	km KeyCustodian
 }
 
-// NewCryptoBox creates a CryptoBox which provides crypto box encryption using the given LegacyKMS's keypairs
+// NewCryptoBox creates a CryptoBox which provides crypto box encryption using the given LegacyKMS's keypairs,
+// the LegacyKMS has to be KeyCustodian.
 func NewCryptoBox(w KeyManager) (*CryptoBox, error) {
-	wa, ok := w.(*BaseKMS)
+	wa, ok := w.(KeyCustodian)
 	if !ok {
 		return nil, fmt.Errorf("cannot use parameter as LegacyKMS")
 	}
@@ -46,25 +44,17 @@ func NewCryptoBox(w KeyManager) (*CryptoBox, error) {
 // Easy seals a message with a provided nonce
 // theirPub is used as a public key, while myPub is used to identify the private key that should be used
 func (b *CryptoBox) Easy(payload, nonce, theirPub, myPub []byte) ([]byte, error) {
-	var recPubBytes [cryptoutil.Curve25519KeySize]byte
-
-	copy(recPubBytes[:], theirPub)
-
-	//	 myPub is used to get the sender private key for encryption
-	kp, err := b.km.getKeyPairSet(base58.Encode(myPub))
+	//	 myPub is used to identify the sender private key for encryption
+	sharedKey, err := b.precompute(myPub, theirPub)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		priv       [cryptoutil.Curve25519KeySize]byte
-		nonceBytes [cryptoutil.NonceSize]byte
-	)
+	var nonceBytes [cryptoutil.NonceSize]byte
 
-	copy(priv[:], kp.EncKeyPair.Priv)
 	copy(nonceBytes[:], nonce)
 
-	ret := box.Seal(nil, payload, &nonceBytes, &recPubBytes, &priv)
+	ret := box.SealAfterPrecomputation(nil, payload, &nonceBytes, sharedKey)
 
 	return ret, nil
 }
@@ -72,25 +62,17 @@ func (b *CryptoBox) Easy(payload, nonce, theirPub, myPub []byte) ([]byte, error)
 // EasyOpen unseals a message sealed with Easy, where the nonce is provided
 // theirPub is the public key used to decrypt directly, while myPub is used to identify the private key to be used
 func (b *CryptoBox) EasyOpen(cipherText, nonce, theirPub, myPub []byte) ([]byte, error) {
-	//	 myPub is used to get the recipient private key for decryption
-	var sendPubBytes [cryptoutil.Curve25519KeySize]byte
-
-	copy(sendPubBytes[:], theirPub)
-
-	kp, err := b.km.getKeyPairSet(base58.Encode(myPub))
+	//	 myPub is used to identify the recipient private key for decryption
+	sharedKey, err := b.precompute(myPub, theirPub)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		priv       [cryptoutil.Curve25519KeySize]byte
-		nonceBytes [cryptoutil.NonceSize]byte
-	)
+	var nonceBytes [cryptoutil.NonceSize]byte
 
-	copy(priv[:], kp.EncKeyPair.Priv)
 	copy(nonceBytes[:], nonce)
 
-	out, success := box.Open(nil, cipherText, &nonceBytes, &sendPubBytes, &priv)
+	out, success := box.OpenAfterPrecomputation(nil, cipherText, &nonceBytes, sharedKey)
 	if !success {
 		return nil, errors.New("failed to unpack")
 	}
@@ -133,28 +115,41 @@ func (b *CryptoBox) SealOpen(cipherText, myPub []byte) ([]byte, error) {
 		return nil, errors.New("message too short")
 	}
 
-	kp, err := b.km.getKeyPairSet(base58.Encode(myPub))
+	epk := cipherText[:cryptoutil.Curve25519KeySize]
+
+	sharedKey, err := b.precompute(myPub, epk)
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		epk  [cryptoutil.Curve25519KeySize]byte
-		priv [cryptoutil.Curve25519KeySize]byte
-	)
-
-	copy(epk[:], cipherText[:cryptoutil.Curve25519KeySize])
-	copy(priv[:], kp.EncKeyPair.Priv)
-
-	nonce, err := cryptoutil.Nonce(epk[:], myPub)
+	nonce, err := cryptoutil.Nonce(epk, myPub)
 	if err != nil {
 		return nil, err
 	}
 
-	out, success := box.Open(nil, cipherText[cryptoutil.Curve25519KeySize:], nonce, &epk, &priv)
+	out, success := box.OpenAfterPrecomputation(nil, cipherText[cryptoutil.Curve25519KeySize:], nonce, sharedKey)
 	if !success {
 		return nil, errors.New("failed to unpack")
 	}
 
 	return out, nil
 }
+
+// precompute derives the shared key of the box as box.Precompute does, but the X25519 shared secret
+// is derived by the key custodian with the private key corresponding to myPub.
+func (b *CryptoBox) precompute(myPub, theirPub []byte) (*[cryptoutil.Curve25519KeySize]byte, error) {
+	z, err := b.km.DeriveECDH(myPub, theirPub)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sharedKey [cryptoutil.Curve25519KeySize]byte
+		zeros     [16]byte
+	)
+
+	copy(sharedKey[:], z)
+	salsa.HSalsa20(&sharedKey, &zeros, &sharedKey, &salsa.Sigma)
+
+	return &sharedKey, nil
+}
@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package legacykms
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"errors"
 	"io"
 	"testing"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
 	"golang.org/x/crypto/nacl/box"
 
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
@@ -194,6 +197,102 @@ func TestBoxEasy(t *testing.T) {
 	})
 }
 
+// externalCustodian is KeyCustodian other than BaseKMS, e.g. backed by HSM
+type externalCustodian struct {
+	KeyCustodian
+	derived int
+}
+
+func (c *externalCustodian) DeriveECDH(myPub, theirPub []byte) ([]byte, error) {
+	c.derived++
+
+	return c.KeyCustodian.DeriveECDH(myPub, theirPub)
+}
+
+func TestCryptoBox_KeyCustodian(t *testing.T) {
+	k, _ := newKMS(t)
+
+	_, recipientPub, err := k.CreateKeyAgreementKey()
+	require.NoError(t, err)
+
+	custodian := &externalCustodian{KeyCustodian: k}
+
+	b, err := NewCryptoBox(custodian)
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum dolor sit amet")
+
+	t.Run("sealed message is opened by the custodian", func(t *testing.T) {
+		enc, err := b.Seal(msg, recipientPub, rand.Reader)
+		require.NoError(t, err)
+
+		dec, err := b.SealOpen(enc, recipientPub)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+		require.Equal(t, 1, custodian.derived)
+	})
+
+	t.Run("compatible with nacl box", func(t *testing.T) {
+		senderPub, senderPriv, err := box.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		var (
+			nonce  [cryptoutil.NonceSize]byte
+			recPub [cryptoutil.Curve25519KeySize]byte
+		)
+
+		copy(recPub[:], recipientPub)
+
+		enc := box.Seal(nil, msg, &nonce, &recPub, senderPriv)
+
+		dec, err := b.EasyOpen(enc, nonce[:], senderPub[:], recipientPub)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+	})
+}
+
+func TestBaseKMS_DeriveECDH(t *testing.T) {
+	k, _ := newKMS(t)
+
+	_, myPub, err := k.CreateKeyAgreementKey()
+	require.NoError(t, err)
+
+	theirPub, theirPriv, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		z, err := k.DeriveECDH(myPub, theirPub[:])
+		require.NoError(t, err)
+
+		expected, err := curve25519.X25519(theirPriv[:], myPub)
+		require.NoError(t, err)
+		require.Equal(t, expected, z)
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := k.DeriveECDH(theirPub[:], myPub)
+		require.True(t, errors.Is(err, cryptoutil.ErrKeyNotFound))
+	})
+
+	t.Run("key without encryption key pair", func(t *testing.T) {
+		sigPub, sigPriv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		require.NoError(t, k.persistKeySet(base58.Encode(sigPub), &cryptoutil.MessagingKeys{
+			SigKeyPair: &cryptoutil.SigKeyPair{KeyPair: cryptoutil.KeyPair{Pub: sigPub, Priv: sigPriv}},
+		}))
+
+		_, err = k.DeriveECDH(sigPub, theirPub[:])
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+	})
+
+	t.Run("low order public key", func(t *testing.T) {
+		_, err := k.DeriveECDH(myPub, make([]byte, cryptoutil.Curve25519KeySize))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "derive ECDH")
+	})
+}
+
 func randCurveKeyPair(randReader io.Reader) (*cryptoutil.MessagingKeys, error) {
 	pk, sk, err := box.GenerateKey(randReader)
 	if err != nil {
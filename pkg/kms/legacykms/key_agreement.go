@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+// CreateKeyAgreementKey creates and persists a new X25519 (Curve25519) key agreement keypair,
+// the keypair has no signature key, so it can be used for packing and unpacking messages only.
+// returns:
+// 		string: the key id - base58 encoded 32 bytes X25519 public key (u-coordinate, little-endian as per RFC 7748)
+// 		[]byte: the raw 32 bytes X25519 public key
+//		error: in case of errors
+func (w *BaseKMS) CreateKeyAgreementKey() (string, []byte, error) {
+	priv := make([]byte, cryptoutil.Curve25519KeySize)
+
+	if _, err := rand.Read(priv); err != nil {
+		return "", nil, fmt.Errorf("create key agreement key: %w", err)
+	}
+
+	pub, err := curve25519.X25519(priv, curve25519.Basepoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("create key agreement key: %w", err)
+	}
+
+	kid := base58.Encode(pub)
+	kpCombo := &cryptoutil.MessagingKeys{
+		EncKeyPair: &cryptoutil.EncKeyPair{
+			KeyPair: cryptoutil.KeyPair{Pub: pub, Priv: priv},
+			Alg:     cryptoutil.Curve25519,
+		},
+	}
+
+	if err = w.persistKeySet(kid, kpCombo); err != nil {
+		return "", nil, fmt.Errorf("create key agreement key: %w", err)
+	}
+
+	return kid, pub, nil
+}
+
+// ToX25519PublicKey converts the ed25519 public key to its X25519 (Curve25519) equivalent, e.g. to publish
+// both verification and key agreement keys in DID document. Unlike ConvertToEncryptionKey
+// the key doesn't have to be managed by the LegacyKMS and nothing is persisted.
+func ToX25519PublicKey(ed25519PubKey []byte) ([]byte, error) {
+	pub, err := cryptoutil.PublicEd25519toCurve25519(ed25519PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("convert to X25519 public key: %w", err)
+	}
+
+	return pub, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestBaseKMS_CreateKeyAgreementKey(t *testing.T) {
+	newKMS := func(t *testing.T, errPut error) *BaseKMS {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{
+			Store: &mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrPut: errPut,
+			}}))
+		require.NoError(t, err)
+
+		return k
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		k := newKMS(t, nil)
+
+		kid1, pub1, err := k.CreateKeyAgreementKey()
+		require.NoError(t, err)
+		require.Len(t, pub1, cryptoutil.Curve25519KeySize)
+		require.Equal(t, base58.Encode(pub1), kid1)
+
+		kid2, pub2, err := k.CreateKeyAgreementKey()
+		require.NoError(t, err)
+		require.NotEqual(t, kid1, kid2)
+
+		encKey, err := k.GetEncryptionKey(pub1)
+		require.NoError(t, err)
+		require.Equal(t, pub1, encKey)
+
+		i, err := k.FindEncKey([]string{"unknown", kid2})
+		require.NoError(t, err)
+		require.Equal(t, 1, i)
+
+		// key agreement key is not a verification key
+		i, err = k.FindVerKey([]string{"unknown", kid2})
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+		require.Equal(t, -1, i)
+
+		// both parties derive the same key encryption key
+		kek1, err := k.DeriveKEK([]byte("ECDH-SS+XC20PKW"), nil, pub1, pub2)
+		require.NoError(t, err)
+
+		kek2, err := k.DeriveKEK([]byte("ECDH-SS+XC20PKW"), nil, pub2, pub1)
+		require.NoError(t, err)
+		require.Equal(t, kek1, kek2)
+
+		// key agreement key can't be used for signing
+		_, err = k.SignMessage([]byte("message"), kid1)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+	})
+
+	t.Run("key agreement key is not converted to encryption key", func(t *testing.T) {
+		k := newKMS(t, nil)
+
+		// about half of X25519 public keys are valid ed25519 public keys too
+		for i := 0; i < 20; i++ {
+			_, pub, err := k.CreateKeyAgreementKey()
+			require.NoError(t, err)
+
+			if _, err = cryptoutil.PublicEd25519toCurve25519(pub); err != nil {
+				continue
+			}
+
+			_, err = k.ConvertToEncryptionKey(pub)
+			require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+		}
+	})
+
+	t.Run("test persist error", func(t *testing.T) {
+		kid, pub, err := newKMS(t, errors.New("put error")).CreateKeyAgreementKey()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+		require.Empty(t, kid)
+		require.Nil(t, pub)
+	})
+}
+
+func TestToX25519PublicKey(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{
+			Store: &mockstorage.MockStore{
+				Store: make(map[string][]byte),
+			}}))
+		require.NoError(t, err)
+
+		encKey, verKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		pub, err := ToX25519PublicKey(base58.Decode(verKey))
+		require.NoError(t, err)
+		require.Equal(t, encKey, base58.Encode(pub))
+	})
+
+	t.Run("test invalid key", func(t *testing.T) {
+		pub, err := ToX25519PublicKey([]byte("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert to X25519 public key")
+		require.Nil(t, pub)
+	})
+}
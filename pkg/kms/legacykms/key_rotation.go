@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	keyRotationNamespace = "keyrotation"
+)
+
+// ErrKeyNotFound is returned when the key (or the DID associated with the key) is not managed by the LegacyKMS.
+var ErrKeyNotFound = cryptoutil.ErrKeyNotFound
+
+// keyRotatorProvider contains dependencies for the KeyRotator and is typically created by using aries.Context()
+type keyRotatorProvider interface {
+	LegacyKMS() KeyManager
+	Signer() Signer
+	StorageProvider() storage.Provider
+	VDRIRegistry() vdri.Registry
+}
+
+// KeyRotator rotates signing keys of the DIDs managed by the LegacyKMS.
+//
+// Rotated keys are kept in the LegacyKMS, so messages packed for the old key can still be unpacked.
+type KeyRotator struct {
+	kms    KeyManager
+	signer Signer
+	vdri   vdri.Registry
+	store  storage.Store
+}
+
+// NewKeyRotator returns new instance of KeyRotator.
+func NewKeyRotator(ctx keyRotatorProvider) (*KeyRotator, error) {
+	store, err := ctx.StorageProvider().OpenStore(keyRotationNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to OpenStore for '%s', cause: %w", keyRotationNamespace, err)
+	}
+
+	return &KeyRotator{
+		kms:    ctx.LegacyKMS(),
+		signer: ctx.Signer(),
+		vdri:   ctx.VDRIRegistry(),
+		store:  store,
+	}, nil
+}
+
+// RotateKey creates a new signing key for the DID, replaces the current key with the new one in
+// the DID document and stores the updated document in the VDRI.
+// returns:
+// 		string: base58 encoded verification key of the new key
+//		error: in case of errors (ErrKeyNotFound if the DID is not managed by the LegacyKMS)
+func (r *KeyRotator) RotateKey(didID string) (string, error) {
+	doc, err := r.vdri.Resolve(didID)
+	if err != nil {
+		return "", fmt.Errorf("rotate key: resolve DID: %w", err)
+	}
+
+	oldVerKey, err := r.currentVerKey(doc)
+	if err != nil {
+		return "", fmt.Errorf("rotate key: %w", err)
+	}
+
+	_, newVerKey, err := r.kms.CreateKeySet()
+	if err != nil {
+		return "", fmt.Errorf("rotate key: %w", err)
+	}
+
+	replaceKey(doc, base58.Decode(oldVerKey), base58.Decode(newVerKey))
+
+	err = r.vdri.Store(doc)
+	if err != nil {
+		return "", fmt.Errorf("rotate key: store DID: %w", err)
+	}
+
+	err = r.store.Put(didID, []byte(newVerKey))
+	if err != nil {
+		return "", fmt.Errorf("rotate key: save verification key: %w", err)
+	}
+
+	return newVerKey, nil
+}
+
+// VerKey returns the current base58 encoded verification key of the DID.
+func (r *KeyRotator) VerKey(didID string) (string, error) {
+	verKey, err := r.store.Get(didID)
+	if err == nil {
+		return string(verKey), nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return "", fmt.Errorf("get verification key: %w", err)
+	}
+
+	doc, err := r.vdri.Resolve(didID)
+	if err != nil {
+		return "", fmt.Errorf("get verification key: resolve DID: %w", err)
+	}
+
+	return r.currentVerKey(doc)
+}
+
+// SignMessage signs a message using the current key of the DID.
+func (r *KeyRotator) SignMessage(message []byte, didID string) ([]byte, error) {
+	verKey, err := r.VerKey(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.signer.SignMessage(message, verKey)
+}
+
+// currentVerKey returns the first key of the DID document which is managed by the LegacyKMS.
+func (r *KeyRotator) currentVerKey(doc *did.Doc) (string, error) {
+	candidateKeys := make([]string, len(doc.PublicKey))
+	for i := range doc.PublicKey {
+		candidateKeys[i] = base58.Encode(doc.PublicKey[i].Value)
+	}
+
+	i, err := r.kms.FindVerKey(candidateKeys)
+	if err != nil {
+		return "", err
+	}
+
+	return candidateKeys[i], nil
+}
+
+// replaceKey replaces the old key with the new one in public keys, authentication
+// and recipient keys of the services of the DID document.
+func replaceKey(doc *did.Doc, oldKey, newKey []byte) {
+	for i := range doc.PublicKey {
+		if bytes.Equal(doc.PublicKey[i].Value, oldKey) {
+			doc.PublicKey[i].Value = newKey
+		}
+	}
+
+	for i := range doc.Authentication {
+		if bytes.Equal(doc.Authentication[i].PublicKey.Value, oldKey) {
+			doc.Authentication[i].PublicKey.Value = newKey
+		}
+	}
+
+	oldKeyB58, newKeyB58 := base58.Encode(oldKey), base58.Encode(newKey)
+
+	for i := range doc.Service {
+		for j, recKey := range doc.Service[i].RecipientKeys {
+			if recKey == oldKeyB58 {
+				doc.Service[i].RecipientKeys[j] = newKeyB58
+			}
+		}
+	}
+}
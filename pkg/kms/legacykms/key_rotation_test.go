@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const testDID = "did:example:123456"
+
+func TestNewKeyRotator(t *testing.T) {
+	t.Run("test error from OpenStore for key rotation store", func(t *testing.T) {
+		_, err := NewKeyRotator(&mockKeyRotatorProvider{
+			storage: &mockstorage.MockStoreProvider{FailNamespace: keyRotationNamespace},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), keyRotationNamespace)
+	})
+}
+
+func TestKeyRotator_RotateKey(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		kms, vdri, rotator := newTestKeyRotator(t)
+
+		_, oldVerKey, err := kms.CreateKeySet()
+		require.NoError(t, err)
+
+		vdri.ResolveValue = newTestDIDDoc(oldVerKey)
+
+		newVerKey, err := rotator.RotateKey(testDID)
+		require.NoError(t, err)
+		require.NotEqual(t, oldVerKey, newVerKey)
+
+		// DID document is updated with the new key
+		doc := vdri.MemStore[testDID]
+		require.NotNil(t, doc)
+		require.Equal(t, base58.Decode(newVerKey), doc.PublicKey[0].Value)
+		require.Equal(t, base58.Decode(newVerKey), doc.Authentication[0].PublicKey.Value)
+		require.Equal(t, []string{newVerKey}, doc.Service[0].RecipientKeys)
+
+		// the new key is used for signing by default
+		verKey, err := rotator.VerKey(testDID)
+		require.NoError(t, err)
+		require.Equal(t, newVerKey, verKey)
+
+		msg := []byte("test message")
+
+		signature, err := rotator.SignMessage(msg, testDID)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(base58.Decode(newVerKey), msg, signature))
+
+		// the old key is still available
+		i, err := kms.FindVerKey([]string{oldVerKey})
+		require.NoError(t, err)
+		require.Equal(t, 0, i)
+	})
+
+	t.Run("test DID is not managed by the KMS", func(t *testing.T) {
+		_, vdri, rotator := newTestKeyRotator(t)
+
+		_, otherVerKey, err := newTestKMS(t).CreateKeySet()
+		require.NoError(t, err)
+
+		vdri.ResolveValue = newTestDIDDoc(otherVerKey)
+
+		newVerKey, err := rotator.RotateKey(testDID)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+		require.Empty(t, newVerKey)
+	})
+
+	t.Run("test error from resolve DID", func(t *testing.T) {
+		_, _, rotator := newTestKeyRotator(t)
+
+		newVerKey, err := rotator.RotateKey(testDID)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Empty(t, newVerKey)
+	})
+
+	t.Run("test error from store DID", func(t *testing.T) {
+		kms, vdri, rotator := newTestKeyRotator(t)
+
+		_, oldVerKey, err := kms.CreateKeySet()
+		require.NoError(t, err)
+
+		vdri.ResolveValue = newTestDIDDoc(oldVerKey)
+		vdri.PutErr = fmt.Errorf("put error")
+
+		newVerKey, err := rotator.RotateKey(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+		require.Empty(t, newVerKey)
+	})
+}
+
+func TestKeyRotator_VerKey(t *testing.T) {
+	t.Run("test current key before rotation", func(t *testing.T) {
+		kms, vdri, rotator := newTestKeyRotator(t)
+
+		_, verKey, err := kms.CreateKeySet()
+		require.NoError(t, err)
+
+		vdri.ResolveValue = newTestDIDDoc(verKey)
+
+		currentVerKey, err := rotator.VerKey(testDID)
+		require.NoError(t, err)
+		require.Equal(t, verKey, currentVerKey)
+	})
+
+	t.Run("test error from store", func(t *testing.T) {
+		kms := newTestKMS(t)
+
+		rotator, err := NewKeyRotator(&mockKeyRotatorProvider{
+			kms: kms,
+			storage: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: fmt.Errorf("get error"),
+			}),
+			vdri: &mockvdri.MockVDRIRegistry{},
+		})
+		require.NoError(t, err)
+
+		_, err = rotator.VerKey(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		_, err = rotator.SignMessage([]byte("test message"), testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+	})
+}
+
+func newTestKMS(t *testing.T) *BaseKMS {
+	kms, err := New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+	require.NoError(t, err)
+
+	return kms
+}
+
+func newTestKeyRotator(t *testing.T) (*BaseKMS, *mockvdri.MockVDRIRegistry, *KeyRotator) {
+	kms := newTestKMS(t)
+	vdri := &mockvdri.MockVDRIRegistry{}
+
+	rotator, err := NewKeyRotator(&mockKeyRotatorProvider{
+		kms:     kms,
+		storage: mockstorage.NewMockStoreProvider(),
+		vdri:    vdri,
+	})
+	require.NoError(t, err)
+
+	return kms, vdri, rotator
+}
+
+func newTestDIDDoc(verKey string) *did.Doc {
+	pubKey := did.PublicKey{
+		ID:         testDID + "#key-1",
+		Type:       "Ed25519VerificationKey2018",
+		Controller: testDID,
+		Value:      base58.Decode(verKey),
+	}
+
+	return &did.Doc{
+		Context:        []string{"https://w3id.org/did/v1"},
+		ID:             testDID,
+		PublicKey:      []did.PublicKey{pubKey},
+		Authentication: []did.VerificationMethod{{PublicKey: pubKey}},
+		Service: []did.Service{{
+			ID:              testDID + "#agent",
+			Type:            vdriapi.DIDCommServiceType,
+			RecipientKeys:   []string{verKey},
+			ServiceEndpoint: "https://example.com/agent",
+		}},
+	}
+}
+
+// mockKeyRotatorProvider mocks provider for KeyRotator
+type mockKeyRotatorProvider struct {
+	kms     *BaseKMS
+	storage *mockstorage.MockStoreProvider
+	vdri    vdriapi.Registry
+}
+
+func (m *mockKeyRotatorProvider) LegacyKMS() KeyManager {
+	return m.kms
+}
+
+func (m *mockKeyRotatorProvider) Signer() Signer {
+	return m.kms
+}
+
+func (m *mockKeyRotatorProvider) StorageProvider() storage.Provider {
+	return m.storage
+}
+
+func (m *mockKeyRotatorProvider) VDRIRegistry() vdriapi.Registry {
+	return m.vdri
+}
@@ -7,14 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package legacykms
 
 import (
+	"crypto"
+	"crypto/cipher"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
 	chacha "golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
@@ -25,6 +30,9 @@ const (
 	keyStoreNamespace = "keystore"
 )
 
+// ErrInvalidSignature is returned when the signature does not match the message and verification key
+var ErrInvalidSignature = errors.New("invalid signature")
+
 // provider contains dependencies for the base LegacyKMS and is typically created by using aries.Context()
 type provider interface {
 	StorageProvider() storage.Provider
@@ -32,17 +40,36 @@ type provider interface {
 
 // BaseKMS Base Key Management Service implementation
 type BaseKMS struct {
-	keystore storage.Store
+	keystore  storage.Store
+	seed      []byte
+	masterKey cipher.AEAD
+	// rootStore keeps the master key parameters, it is the key store of the profile's parent
+	rootStore storage.Store
 }
 
 // New return new instance of LegacyKMS implementation
-func New(ctx provider) (*BaseKMS, error) {
+func New(ctx provider, opts ...Option) (*BaseKMS, error) {
+	kmsOpts := &kmsOpts{}
+
+	for _, opt := range opts {
+		opt(kmsOpts)
+	}
+
 	ks, err := ctx.StorageProvider().OpenStore(keyStoreNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to OpenStore for '%s', cause: %w", keyStoreNamespace, err)
 	}
 
-	return &BaseKMS{keystore: ks}, nil
+	w := &BaseKMS{keystore: ks, rootStore: ks}
+
+	if kmsOpts.masterPassphrase != "" {
+		w.masterKey, err = openMasterKey(ks, kmsOpts.masterPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("open master key: %w", err)
+		}
+	}
+
+	return w, nil
 }
 
 // CreateKeySet creates a new public/private encryption and signature keypairs combo.
@@ -51,12 +78,17 @@ func New(ctx provider) (*BaseKMS, error) {
 // 		string: signature key id base58 encoded of the marshaled cryptoutil.KayPairCombo stored in the LegacyKMS store
 //		error: in case of errors
 func (w *BaseKMS) CreateKeySet() (string, string, error) {
-	// TODO - need to encrypt the encPriv and sigPriv before putting them in the store.
 	sigKp, err := createSigKeyPair()
 	if err != nil {
 		return "", "", err
 	}
 
+	return w.storeKeySet(sigKp)
+}
+
+// storeKeySet creates encryption keypair for sigKp and persists the keypair combo in the LegacyKMS store
+// under both encryption and signature public keys.
+func (w *BaseKMS) storeKeySet(sigKp *cryptoutil.SigKeyPair) (string, string, error) {
 	encKp, err := createEncKeyPair(sigKp)
 	if err != nil {
 		return "", "", err
@@ -68,8 +100,7 @@ func (w *BaseKMS) CreateKeySet() (string, string, error) {
 		SigKeyPair: sigKp,
 	}
 
-	// TODO - need to encrypt kpCombo.sigKp.Priv and kpCombo.encKp.Priv before putting them in the store.
-	if er := persist(w.keystore, encBase58Pub, kpCombo); er != nil {
+	if er := w.persistKeySet(encBase58Pub, kpCombo); er != nil {
 		return "", "", er
 	}
 
@@ -77,7 +108,7 @@ func (w *BaseKMS) CreateKeySet() (string, string, error) {
 	//  	the same kpCombo value in the store
 	// for now the keypair combo is stored twice (once for encPubKey and once for sigPubKey)
 	sigBase58Pub := base58.Encode(sigKp.Pub)
-	if er := persist(w.keystore, sigBase58Pub, kpCombo); er != nil {
+	if er := w.persistKeySet(sigBase58Pub, kpCombo); er != nil {
 		return "", "", er
 	}
 
@@ -135,6 +166,11 @@ func (w *BaseKMS) ConvertToEncryptionKey(verKey []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	// key agreement keys have no signature keypair to convert
+	if kpc.SigKeyPair == nil {
+		return nil, fmt.Errorf("convert to encryption key: %w", cryptoutil.ErrInvalidKey)
+	}
+
 	encPriv, err := cryptoutil.SecretEd25519toCurve25519(kpc.SigKeyPair.Priv)
 	if err != nil {
 		return nil, err
@@ -145,13 +181,13 @@ func (w *BaseKMS) ConvertToEncryptionKey(verKey []byte) ([]byte, error) {
 		SigKeyPair: kpc.SigKeyPair,
 	}
 
-	err = persist(w.keystore, encPubB58, kpNew)
+	err = w.persistKeySet(encPubB58, kpNew)
 	if err != nil {
 		return nil, err
 	}
 	// TODO duplicate MessagingKeys in store or use a metadata store to map sig->enc?
 	// 		for now we're duplicating entries as we only have 'keystore' (update when 'metadatastore' is added)
-	err = persist(w.keystore, sigPubB58, kpNew)
+	err = w.persistKeySet(sigPubB58, kpNew)
 	if err != nil {
 		return nil, err
 	}
@@ -166,19 +202,66 @@ func (w *BaseKMS) SignMessage(message []byte, fromVerKey string) ([]byte, error)
 		return nil, fmt.Errorf("failed to get key: %w", err)
 	}
 
+	// key agreement keys have no signature key pair
+	if kpc.SigKeyPair == nil {
+		return nil, fmt.Errorf("sign message: %w", cryptoutil.ErrInvalidKey)
+	}
+
 	signer := &ed25519Signer{kpc: kpc}
 
 	return ed25519signature2018.New(ed25519signature2018.WithSigner(signer)).Sign(message)
 }
 
+// VerifyMessage verifies a signature of the message using the key pair associated with a given verification key.
+// The signature algorithm is the one recorded for the key pair when it was created.
+func (w *BaseKMS) VerifyMessage(message, signature []byte, fromVerKey string) error {
+	kpc, err := w.getKeyPairSet(fromVerKey)
+	if err != nil {
+		return fmt.Errorf("failed to get key: %w", err)
+	}
+
+	if kpc.SigKeyPair == nil {
+		return fmt.Errorf("verify message: %w", cryptoutil.ErrInvalidKey)
+	}
+
+	// the key pair is stored for both signature and encryption keys, make sure a signature key was given
+	if subtle.ConstantTimeCompare(kpc.SigKeyPair.Pub, base58.Decode(fromVerKey)) != 1 {
+		return fmt.Errorf("verify message: %s is not a verification key: %w", fromVerKey, cryptoutil.ErrInvalidKey)
+	}
+
+	switch kpc.SigKeyPair.Alg {
+	case cryptoutil.EdDSA:
+		return verifyEd25519(kpc.SigKeyPair.Pub, message, signature)
+	default:
+		return fmt.Errorf("verify message: unsupported signature algorithm '%s'", kpc.SigKeyPair.Alg)
+	}
+}
+
+func verifyEd25519(pubKey, message, signature []byte) error {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("verify message: %w", cryptoutil.ErrInvalidKey)
+	}
+
+	if len(signature) != ed25519.SignatureSize || !ed25519.Verify(pubKey, message, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
 // Close the LegacyKMS
 func (w *BaseKMS) Close() error {
 	return nil
 }
 
-// getKeyPairSet get encryption & signature key pairs combo
+// getKeyPairSet get encryption & signature key pairs combo. The keys of the profiles (see OpenProfile)
+// are kept in the same key store, they are never looked up through the parent LegacyKMS.
 func (w *BaseKMS) getKeyPairSet(verKey string) (*cryptoutil.MessagingKeys, error) {
-	bytes, err := w.keystore.Get(verKey)
+	if strings.HasPrefix(verKey, profilePrefix) {
+		return nil, cryptoutil.ErrKeyNotFound
+	}
+
+	bytes, err := w.readKeySet(verKey)
 	if err != nil {
 		if errors.Is(storage.ErrDataNotFound, err) {
 			return nil, cryptoutil.ErrKeyNotFound
@@ -222,10 +305,33 @@ func (w *BaseKMS) DeriveKEK(alg, apu, fromPubKey, toPubKey []byte) ([]byte, erro
 	return cryptoutil.Derive25519KEK(alg, apu, fromPrivKey, toKey)
 }
 
-// FindVerKey selects a signing key which is present in candidateKeys that is present in the LegacyKMS
+// DeriveECDH computes X25519 shared secret of the private encryption key corresponding to myPub and theirPub.
+func (w *BaseKMS) DeriveECDH(myPub, theirPub []byte) ([]byte, error) {
+	kpc, err := w.getKeyPairSet(base58.Encode(myPub))
+	if err != nil {
+		return nil, err
+	}
+
+	if kpc.EncKeyPair == nil {
+		return nil, fmt.Errorf("derive ECDH: %w", cryptoutil.ErrInvalidKey)
+	}
+
+	z, err := curve25519.X25519(kpc.EncKeyPair.Priv, theirPub)
+	if err != nil {
+		return nil, fmt.Errorf("derive ECDH: %w", err)
+	}
+
+	return z, nil
+}
+
+// FindVerKey selects a signing key which is present in candidateKeys that is present in the LegacyKMS.
+// The key agreement keys (see CreateKeyAgreementKey) are skipped as they have no signing key,
+// cryptoutil.ErrInvalidKey is returned if only such keys are present.
 func (w *BaseKMS) FindVerKey(candidateKeys []string) (int, error) {
+	notFoundErr := cryptoutil.ErrKeyNotFound
+
 	for i, key := range candidateKeys {
-		_, err := w.getKeyPairSet(key)
+		kpc, err := w.getKeyPairSet(key)
 		if err != nil {
 			if errors.Is(err, cryptoutil.ErrKeyNotFound) {
 				continue
@@ -234,10 +340,38 @@ func (w *BaseKMS) FindVerKey(candidateKeys []string) (int, error) {
 			return -1, fmt.Errorf("failed from getKeyPairSet: %w", err)
 		}
 
+		if kpc.SigKeyPair == nil {
+			notFoundErr = cryptoutil.ErrInvalidKey
+			continue
+		}
+
 		// Currently chooses the first usable key, but could use different logic (eg, priorities)
 		return i, nil
 	}
 
+	return -1, notFoundErr
+}
+
+// FindEncKey selects an encryption key which is present in candidateKeys that is present in the LegacyKMS,
+// unlike FindVerKey the key agreement keys are selected too.
+func (w *BaseKMS) FindEncKey(candidateKeys []string) (int, error) {
+	for i, key := range candidateKeys {
+		kpc, err := w.getKeyPairSet(key)
+		if err != nil {
+			if errors.Is(err, cryptoutil.ErrKeyNotFound) {
+				continue
+			}
+
+			return -1, fmt.Errorf("failed from getKeyPairSet: %w", err)
+		}
+
+		if kpc.EncKeyPair == nil {
+			continue
+		}
+
+		return i, nil
+	}
+
 	return -1, cryptoutil.ErrKeyNotFound
 }
 
@@ -265,6 +399,10 @@ func (w *BaseKMS) GetEncryptionKey(verKey []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if kpCombo.EncKeyPair == nil {
+		return nil, fmt.Errorf("get encryption key: %w", cryptoutil.ErrInvalidKey)
+	}
+
 	return kpCombo.EncKeyPair.Pub, nil
 }
 
@@ -275,3 +413,11 @@ type ed25519Signer struct {
 func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
 	return ed25519.Sign(s.kpc.SigKeyPair.Priv, data), nil
 }
+
+func (s *ed25519Signer) PublicKey() crypto.PublicKey {
+	return ed25519.PublicKey(s.kpc.SigKeyPair.Pub)
+}
+
+func (s *ed25519Signer) KeyID() string {
+	return base58.Encode(s.kpc.SigKeyPair.Pub)
+}
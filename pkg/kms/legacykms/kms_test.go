@@ -9,6 +9,7 @@ package legacykms
 import (
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -138,6 +139,103 @@ func TestBaseKMS_SignMessage(t *testing.T) {
 	})
 }
 
+func TestBaseKMS_VerifyMessage(t *testing.T) {
+	newKMS := func(t *testing.T) *BaseKMS {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{
+			Store: &mockstorage.MockStore{
+				Store: make(map[string][]byte),
+			}}))
+		require.NoError(t, err)
+
+		return k
+	}
+
+	testMsg := []byte("hello")
+
+	t.Run("test success", func(t *testing.T) {
+		k := newKMS(t)
+		_, fromVerKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		signature, err := k.SignMessage(testMsg, fromVerKey)
+		require.NoError(t, err)
+
+		require.NoError(t, k.VerifyMessage(testMsg, signature, fromVerKey))
+	})
+
+	t.Run("test invalid signature", func(t *testing.T) {
+		k := newKMS(t)
+		_, fromVerKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		signature, err := k.SignMessage(testMsg, fromVerKey)
+		require.NoError(t, err)
+
+		err = k.VerifyMessage([]byte("other message"), signature, fromVerKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		err = k.VerifyMessage(testMsg, signature[1:], fromVerKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+
+		err = k.VerifyMessage(testMsg, nil, fromVerKey)
+		require.True(t, errors.Is(err, ErrInvalidSignature))
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		err := newKMS(t).VerifyMessage(testMsg, []byte("signature"), "")
+		require.True(t, errors.Is(err, cryptoutil.ErrKeyNotFound))
+	})
+
+	t.Run("test encryption key is not a verification key", func(t *testing.T) {
+		k := newKMS(t)
+		encKey, sigKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		signature, err := k.SignMessage(testMsg, sigKey)
+		require.NoError(t, err)
+
+		err = k.VerifyMessage(testMsg, signature, encKey)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+		require.Contains(t, err.Error(), "is not a verification key")
+	})
+
+	t.Run("test unsupported signature algorithm", func(t *testing.T) {
+		k := newKMS(t)
+		pub := base58.Encode([]byte("public key"))
+
+		require.NoError(t, persist(k.keystore, pub, &cryptoutil.MessagingKeys{
+			SigKeyPair: &cryptoutil.SigKeyPair{
+				KeyPair: cryptoutil.KeyPair{Pub: []byte("public key")},
+				Alg:     "unknown",
+			},
+		}))
+
+		err := k.VerifyMessage(testMsg, []byte("signature"), pub)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported signature algorithm 'unknown'")
+	})
+
+	t.Run("test missing or invalid signature key pair", func(t *testing.T) {
+		k := newKMS(t)
+		pub := base58.Encode([]byte("public key"))
+
+		require.NoError(t, persist(k.keystore, pub, &cryptoutil.MessagingKeys{}))
+
+		err := k.VerifyMessage(testMsg, []byte("signature"), pub)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+
+		require.NoError(t, persist(k.keystore, pub, &cryptoutil.MessagingKeys{
+			SigKeyPair: &cryptoutil.SigKeyPair{
+				KeyPair: cryptoutil.KeyPair{Pub: []byte("public key")},
+				Alg:     cryptoutil.EdDSA,
+			},
+		}))
+
+		err = k.VerifyMessage(testMsg, []byte("signature"), pub)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+	})
+}
+
 func TestBaseKMS_ConvertToEncryptionKey(t *testing.T) {
 	t.Run("Success: generate and convert a signing key", func(t *testing.T) {
 		k, err := New(newMockKMSProvider(
@@ -269,21 +367,24 @@ func TestBaseKMS_FindVerKey(t *testing.T) {
 	pk1, sk1, err := box.GenerateKey(rand.Reader)
 	require.NoError(t, err)
 
-	kp := cryptoutil.KeyPair{Pub: pk1[:], Priv: sk1[:]}
+	kp := &cryptoutil.MessagingKeys{SigKeyPair: &cryptoutil.SigKeyPair{
+		KeyPair: cryptoutil.KeyPair{Pub: pk1[:], Priv: sk1[:]}, Alg: cryptoutil.EdDSA}}
 	kpm1, err := json.Marshal(kp)
 	require.NoError(t, err)
 
 	pk2, sk2, err := box.GenerateKey(rand.Reader)
 	require.NoError(t, err)
 
-	kp = cryptoutil.KeyPair{Pub: pk2[:], Priv: sk2[:]}
+	kp = &cryptoutil.MessagingKeys{SigKeyPair: &cryptoutil.SigKeyPair{
+		KeyPair: cryptoutil.KeyPair{Pub: pk2[:], Priv: sk2[:]}, Alg: cryptoutil.EdDSA}}
 	kpm2, err := json.Marshal(kp)
 	require.NoError(t, err)
 
 	pk3, sk3, err := box.GenerateKey(rand.Reader)
 	require.NoError(t, err)
 
-	kp = cryptoutil.KeyPair{Pub: pk3[:], Priv: sk3[:]}
+	kp = &cryptoutil.MessagingKeys{SigKeyPair: &cryptoutil.SigKeyPair{
+		KeyPair: cryptoutil.KeyPair{Pub: pk3[:], Priv: sk3[:]}, Alg: cryptoutil.EdDSA}}
 	kpm3, err := json.Marshal(kp)
 	require.NoError(t, err)
 
@@ -0,0 +1,305 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// masterKeyParamsKey is the key store record of the master key parameters. Key pairs are stored
+	// under base58 encoded public keys, so the name can't clash with them.
+	masterKeyParamsKey = "master_key_params"
+	// masterKeyCheck is encrypted with the master key to detect a wrong passphrase
+	masterKeyCheck = "legacykms master key check"
+
+	// scrypt parameters recommended for interactive logins as of 2017
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	masterKeySize = 32
+	saltSize      = 16
+
+	// storeIteratorLimit is past any key of the key store
+	storeIteratorLimit = "~"
+)
+
+// ErrInvalidMasterPassphrase is returned when the master passphrase does not match the one the key store
+// was encrypted with.
+var ErrInvalidMasterPassphrase = errors.New("invalid master passphrase")
+
+// errMasterKeyRequired is returned when an encrypted key store is used without the master key
+var errMasterKeyRequired = errors.New("key store is encrypted, master passphrase is required")
+
+// errUnencryptedKeySet is returned when an unencrypted record is read from the encrypted key store
+var errUnencryptedKeySet = errors.New("key set is not encrypted, call EncryptKeyStore to encrypt it")
+
+// Option configures the LegacyKMS
+type Option func(opts *kmsOpts)
+
+type kmsOpts struct {
+	masterPassphrase string
+}
+
+// WithMasterPassphrase option enables encryption of the key store. The private key material is
+// encrypted (AES-GCM) with the master key derived from the passphrase (scrypt) before it is written
+// to the store and decrypted only when a key is used. Once the key store is encrypted, its unencrypted
+// records are refused. Key pairs of an existing unencrypted key store must be encrypted by EncryptKeyStore
+// before they can be used.
+func WithMasterPassphrase(passphrase string) Option {
+	return func(opts *kmsOpts) {
+		opts.masterPassphrase = passphrase
+	}
+}
+
+// masterKeyParams are the parameters of the master key kept in the key store
+type masterKeyParams struct {
+	Salt  []byte `json:"salt"`
+	Check []byte `json:"check"`
+}
+
+// encryptedRecord is the key store record encrypted with the master key
+type encryptedRecord struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// openMasterKey derives the master key from the passphrase. The key derivation parameters are created
+// on first use, otherwise the passphrase is checked against the ones kept in the store.
+func openMasterKey(store storage.Store, passphrase string) (cipher.AEAD, error) {
+	var params masterKeyParams
+
+	paramsBytes, err := store.Get(masterKeyParamsKey)
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+		return createMasterKey(store, passphrase)
+	case err != nil:
+		return nil, fmt.Errorf("get master key params: %w", err)
+	}
+
+	if err = json.Unmarshal(paramsBytes, &params); err != nil {
+		return nil, fmt.Errorf("unmarshal master key params: %w", err)
+	}
+
+	masterKey, err := deriveMasterKey(passphrase, params.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	check, checkErr := open(masterKey, params.Check, []byte(masterKeyParamsKey))
+	if checkErr != nil || string(check) != masterKeyCheck {
+		return nil, ErrInvalidMasterPassphrase
+	}
+
+	return masterKey, nil
+}
+
+func createMasterKey(store storage.Store, passphrase string) (cipher.AEAD, error) {
+	salt := make([]byte, saltSize)
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate master key salt: %w", err)
+	}
+
+	masterKey, err := deriveMasterKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := seal(masterKey, []byte(masterKeyCheck), []byte(masterKeyParamsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = persist(store, masterKeyParamsKey, &masterKeyParams{Salt: salt, Check: check}); err != nil {
+		return nil, fmt.Errorf("save master key params: %w", err)
+	}
+
+	return masterKey, nil
+}
+
+func deriveMasterKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, masterKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive master key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create master key cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// isEncrypted tells whether the master key parameters are kept in the store, i.e. the key store is encrypted
+func (w *BaseKMS) isEncrypted() (bool, error) {
+	if w.masterKey != nil {
+		return true, nil
+	}
+
+	_, err := w.rootStore.Get(masterKeyParamsKey)
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("get master key params: %w", err)
+	}
+
+	return true, nil
+}
+
+// seal encrypts the plaintext bound to the associated data, the random nonce is prepended to the ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce := ciphertext[:aead.NonceSize()]
+
+	return aead.Open(nil, nonce, ciphertext[aead.NonceSize():], additionalData)
+}
+
+// persistKeySet saves the key pairs combo in the key store, encrypted if the master key is defined.
+// The ciphertext is bound to the key of the record, so it can't be moved under another key.
+func (w *BaseKMS) persistKeySet(key string, value interface{}) error {
+	if w.masterKey == nil {
+		encrypted, err := w.isEncrypted()
+		if err != nil {
+			return err
+		}
+
+		if encrypted {
+			return errMasterKeyRequired
+		}
+
+		return persist(w.keystore, key, value)
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal : %w", err)
+	}
+
+	ciphertext, err := seal(w.masterKey, bytes, []byte(key))
+	if err != nil {
+		return fmt.Errorf("encrypt key set: %w", err)
+	}
+
+	return persist(w.keystore, key, &encryptedRecord{Ciphertext: ciphertext})
+}
+
+// readKeySet returns the key store record decrypted (if encrypted). Unencrypted records are refused
+// once the key store is encrypted.
+func (w *BaseKMS) readKeySet(key string) ([]byte, error) {
+	bytes, record, err := w.getRecord(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Ciphertext == nil {
+		encrypted, err := w.isEncrypted()
+		if err != nil {
+			return nil, err
+		}
+
+		if encrypted {
+			return nil, errUnencryptedKeySet
+		}
+
+		return bytes, nil
+	}
+
+	if w.masterKey == nil {
+		return nil, errMasterKeyRequired
+	}
+
+	plaintext, err := open(w.masterKey, record.Ciphertext, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key set: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (w *BaseKMS) getRecord(key string) ([]byte, *encryptedRecord, error) {
+	bytes, err := w.keystore.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var record encryptedRecord
+
+	if err = json.Unmarshal(bytes, &record); err != nil {
+		return nil, nil, fmt.Errorf("failed unmarshal to key struct: %w", err)
+	}
+
+	return bytes, &record, nil
+}
+
+// EncryptKeyStore encrypts all the key pairs of the key store which were saved unencrypted
+// (i.e. before the master passphrase was configured). The LegacyKMS must be created WithMasterPassphrase.
+// The key pairs of the profiles are encrypted by EncryptKeyStore of each profile.
+func (w *BaseKMS) EncryptKeyStore() error {
+	if w.masterKey == nil {
+		return errors.New("encrypt key store: master passphrase is not defined")
+	}
+
+	var keys []string
+
+	itr := w.keystore.Iterator("", storeIteratorLimit)
+	defer itr.Release()
+
+	for itr.Next() {
+		// the profiles encrypt their own key pairs, see OpenProfile
+		if key := string(itr.Key()); key != masterKeyParamsKey && !strings.HasPrefix(key, profilePrefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return fmt.Errorf("encrypt key store: %w", err)
+	}
+
+	for _, key := range keys {
+		bytes, record, err := w.getRecord(key)
+		if err != nil {
+			return fmt.Errorf("encrypt key store: %w", err)
+		}
+
+		if record.Ciphertext != nil {
+			continue
+		}
+
+		if err = w.persistKeySet(key, json.RawMessage(bytes)); err != nil {
+			return fmt.Errorf("encrypt key store: encrypt unencrypted key set: %w", err)
+		}
+	}
+
+	return nil
+}
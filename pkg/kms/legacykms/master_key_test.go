@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+const masterPassphrase = "correct horse battery staple"
+
+func TestWithMasterPassphrase(t *testing.T) {
+	msg := []byte("test message")
+
+	t.Run("test private keys are encrypted at rest", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store})
+
+		k, err := New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		encKey, verKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		requireEncrypted(t, store, encKey)
+		requireEncrypted(t, store, verKey)
+
+		sig, err := k.SignMessage(msg, verKey)
+		require.NoError(t, err)
+		require.NoError(t, k.VerifyMessage(msg, sig, verKey))
+
+		// the key store is reopened with the same passphrase
+		k, err = New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		sig, err = k.SignMessage(msg, verKey)
+		require.NoError(t, err)
+		require.NoError(t, k.VerifyMessage(msg, sig, verKey))
+
+		// the key store is opened without the passphrase
+		k, err = New(prov)
+		require.NoError(t, err)
+
+		_, err = k.SignMessage(msg, verKey)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errMasterKeyRequired))
+
+		// unencrypted key pairs are neither saved nor read
+		_, _, err = k.CreateKeySet()
+		require.True(t, errors.Is(err, errMasterKeyRequired))
+
+		store.Store["plaintext"] = []byte(`{}`)
+
+		_, err = k.SignMessage(msg, "plaintext")
+		require.True(t, errors.Is(err, errUnencryptedKeySet))
+	})
+
+	t.Run("test ciphertext is bound to the key of the record", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store}),
+			WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		_, verKey1, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		_, verKey2, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		store.Store[verKey1] = store.Store[verKey2]
+
+		_, err = k.SignMessage(msg, verKey1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decrypt key set")
+	})
+
+	t.Run("test invalid passphrase", func(t *testing.T) {
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte),
+		}})
+
+		_, err := New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		_, err = New(prov, WithMasterPassphrase("wrong passphrase"))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidMasterPassphrase))
+	})
+
+	t.Run("test error from master key params", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store}))
+		require.NoError(t, err)
+
+		store.ErrGet = errors.New("get error")
+
+		require.EqualError(t, k.persistKeySet("key", struct{}{}), "get master key params: get error")
+
+		_, err = New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrGet: errors.New("get error"),
+		}}), WithMasterPassphrase(masterPassphrase))
+		require.EqualError(t, err, "open master key: get master key params: get error")
+
+		_, err = New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrPut: errors.New("put error"),
+		}}), WithMasterPassphrase(masterPassphrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "save master key params")
+
+		_, err = New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{masterKeyParamsKey: []byte("{")},
+		}}), WithMasterPassphrase(masterPassphrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal master key params")
+	})
+
+	t.Run("test key set can't be decrypted", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store}),
+			WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		_, verKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		store.Store[verKey] = []byte(`{"ciphertext":"AAAA"}`)
+
+		_, err = k.SignMessage(msg, verKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decrypt key set: ciphertext is too short")
+	})
+}
+
+func TestBaseKMS_EncryptKeyStore(t *testing.T) {
+	msg := []byte("test message")
+
+	t.Run("test unencrypted key store is migrated", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store})
+
+		k, err := New(prov)
+		require.NoError(t, err)
+
+		encKey1, verKey1, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		encKey2, verKey2, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		k, err = New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		// unencrypted key pairs are refused until the key store is encrypted
+		_, err = k.SignMessage(msg, verKey1)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errUnencryptedKeySet))
+
+		require.NoError(t, k.EncryptKeyStore())
+		require.NoError(t, k.EncryptKeyStore())
+
+		for _, key := range []string{encKey1, verKey1, encKey2, verKey2} {
+			requireEncrypted(t, store, key)
+		}
+
+		sig, err := k.SignMessage(msg, verKey2)
+		require.NoError(t, err)
+		require.NoError(t, k.VerifyMessage(msg, sig, verKey2))
+	})
+
+	t.Run("test profiles are encrypted by their own key store", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store})
+
+		k, err := New(prov)
+		require.NoError(t, err)
+
+		p, err := k.OpenProfile("tenant")
+		require.NoError(t, err)
+
+		_, verKey, err := p.CreateKeySet()
+		require.NoError(t, err)
+
+		k, err = New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		require.NoError(t, k.EncryptKeyStore())
+
+		p, err = k.OpenProfile("tenant")
+		require.NoError(t, err)
+
+		_, err = p.SignMessage(msg, verKey)
+		require.True(t, errors.Is(err, errUnencryptedKeySet))
+
+		require.NoError(t, p.EncryptKeyStore())
+		requireEncrypted(t, store, profilePrefix+"tenant"+profileSeparator+verKey)
+
+		sig, err := p.SignMessage(msg, verKey)
+		require.NoError(t, err)
+		require.NoError(t, p.VerifyMessage(msg, sig, verKey))
+	})
+
+	t.Run("test master passphrase is not defined", func(t *testing.T) {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte),
+		}}))
+		require.NoError(t, err)
+
+		require.EqualError(t, k.EncryptKeyStore(), "encrypt key store: master passphrase is not defined")
+	})
+
+	t.Run("test iterator error", func(t *testing.T) {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrItr: errors.New("iterator error"),
+		}}), WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		require.EqualError(t, k.EncryptKeyStore(), "encrypt key store: iterator error")
+	})
+
+	t.Run("test error from put", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store})
+
+		k, err := New(prov)
+		require.NoError(t, err)
+
+		_, _, err = k.CreateKeySet()
+		require.NoError(t, err)
+
+		k, err = New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		store.ErrPut = errors.New("put error")
+
+		err = k.EncryptKeyStore()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "encrypt unencrypted key set")
+
+		store.ErrPut = nil
+		store.Store["key"] = []byte("{")
+
+		err = k.EncryptKeyStore()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed unmarshal to key struct")
+	})
+}
+
+func requireEncrypted(t *testing.T, store *mockstorage.MockStore, key string) {
+	t.Helper()
+
+	var record map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(store.Store[key], &record))
+	require.Len(t, record, 1)
+	require.NotEmpty(t, record["ciphertext"])
+}
@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// profilePrefix prefixes the keys of the profiles in the key store. Key pairs are stored
+	// under base58 encoded public keys, so the prefix can't clash with them.
+	profilePrefix    = "profile|"
+	profileSeparator = "|"
+)
+
+// WalletProfile is the LegacyKMS which can open isolated profiles, e.g. the ones of the tenants
+// of a hosted multi-tenant agent.
+type WalletProfile interface {
+	KeyCustodian
+
+	// DeriveKey derives the key pair of the given path from the seed (see WithSeed)
+	DeriveKey(path string) (string, error)
+
+	// EncryptKeyStore encrypts the key pairs by the master key (see WithMasterPassphrase)
+	EncryptKeyStore() error
+
+	// OpenProfile returns the LegacyKMS of the profile with the given ID
+	OpenProfile(id string) (WalletProfile, error)
+}
+
+// OpenProfile returns the LegacyKMS of the profile with the given ID. The keys of the profile are kept in
+// the key store of w under a separate keyspace, so the keys created in one profile are not visible to the
+// other profiles and to w itself. The profile is protected by the master passphrase of w, its seed (if any)
+// is derived from the seed of w and the profile ID. Profiles can be nested.
+func (w *BaseKMS) OpenProfile(id string) (WalletProfile, error) {
+	if id == "" {
+		return nil, errors.New("open profile: profile ID is empty")
+	}
+
+	if strings.Contains(id, profileSeparator) {
+		return nil, fmt.Errorf("open profile: profile ID must not contain '%s'", profileSeparator)
+	}
+
+	profile := &BaseKMS{
+		keystore:  storage.NewPrefixedStore(w.keystore, profilePrefix+id+profileSeparator),
+		masterKey: w.masterKey,
+		rootStore: w.rootStore,
+	}
+
+	if len(w.seed) != 0 {
+		key, chainCode := hmacSHA512(w.seed, []byte(profilePrefix+id))
+		profile.seed = append(append([]byte(nil), key...), chainCode...)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+var _ WalletProfile = (*BaseKMS)(nil)
+
+func TestBaseKMS_OpenProfile(t *testing.T) {
+	msg := []byte("test message")
+
+	t.Run("test keys of the profiles are isolated", func(t *testing.T) {
+		k := newTestKMS(t)
+
+		profileA, err := k.OpenProfile("a")
+		require.NoError(t, err)
+
+		profileB, err := k.OpenProfile("b")
+		require.NoError(t, err)
+
+		_, verKey, err := profileA.CreateKeySet()
+		require.NoError(t, err)
+
+		_, err = profileA.SignMessage(msg, verKey)
+		require.NoError(t, err)
+
+		_, err = profileB.SignMessage(msg, verKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = k.SignMessage(msg, verKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = profileB.FindVerKey([]string{verKey})
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		// the keys of the base LegacyKMS are not visible to the profiles
+		_, baseVerKey, err := k.CreateKeySet()
+		require.NoError(t, err)
+
+		_, err = profileA.SignMessage(msg, baseVerKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		// the profile is reopened
+		profileA, err = k.OpenProfile("a")
+		require.NoError(t, err)
+
+		i, err := profileA.FindVerKey([]string{baseVerKey, verKey})
+		require.NoError(t, err)
+		require.Equal(t, 1, i)
+
+		// nested profile
+		nested, err := profileA.OpenProfile("b")
+		require.NoError(t, err)
+
+		_, nestedVerKey, err := nested.CreateKeySet()
+		require.NoError(t, err)
+
+		_, err = profileB.SignMessage(msg, nestedVerKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = profileA.SignMessage(msg, nestedVerKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+
+	t.Run("test keys of the profiles are not visible through the parent", func(t *testing.T) {
+		k := newTestKMS(t)
+
+		profile, err := k.OpenProfile("a")
+		require.NoError(t, err)
+
+		_, verKey, err := profile.CreateKeySet()
+		require.NoError(t, err)
+
+		kid := profilePrefix + "a" + profileSeparator + verKey
+
+		_, err = k.SignMessage(msg, kid)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = k.FindVerKey([]string{kid})
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = k.FindEncKey([]string{kid})
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		nested, err := profile.OpenProfile("b")
+		require.NoError(t, err)
+
+		_, nestedVerKey, err := nested.CreateKeySet()
+		require.NoError(t, err)
+
+		_, err = profile.SignMessage(msg, profilePrefix+"b"+profileSeparator+nestedVerKey)
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+
+	t.Run("test profile is protected by the master passphrase", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		prov := newMockKMSProvider(&mockstorage.MockStoreProvider{Store: store})
+
+		k, err := New(prov, WithMasterPassphrase(masterPassphrase))
+		require.NoError(t, err)
+
+		profile, err := k.OpenProfile("tenant")
+		require.NoError(t, err)
+
+		_, verKey, err := profile.CreateKeySet()
+		require.NoError(t, err)
+
+		requireEncrypted(t, store, profilePrefix+"tenant"+profileSeparator+verKey)
+
+		k, err = New(prov)
+		require.NoError(t, err)
+
+		profile, err = k.OpenProfile("tenant")
+		require.NoError(t, err)
+
+		_, err = profile.SignMessage(msg, verKey)
+		require.True(t, errors.Is(err, errMasterKeyRequired))
+	})
+
+	t.Run("test seed of the profile is derived", func(t *testing.T) {
+		k := newSeedKMS(t, make([]byte, minSeedSize))
+
+		profileA, err := k.OpenProfile("a")
+		require.NoError(t, err)
+
+		profileB, err := k.OpenProfile("b")
+		require.NoError(t, err)
+
+		keyA, err := profileA.DeriveKey("m/0'")
+		require.NoError(t, err)
+
+		keyB, err := profileB.DeriveKey("m/0'")
+		require.NoError(t, err)
+		require.NotEqual(t, keyA, keyB)
+
+		profileA, err = newSeedKMS(t, make([]byte, minSeedSize)).OpenProfile("a")
+		require.NoError(t, err)
+
+		key, err := profileA.DeriveKey("m/0'")
+		require.NoError(t, err)
+		require.Equal(t, keyA, key)
+
+		profile, err := newTestKMS(t).OpenProfile("a")
+		require.NoError(t, err)
+
+		_, err = profile.DeriveKey("m/0'")
+		require.True(t, errors.Is(err, ErrSeedNotDefined))
+	})
+
+	t.Run("test invalid profile ID", func(t *testing.T) {
+		k := newTestKMS(t)
+
+		_, err := k.OpenProfile("")
+		require.EqualError(t, err, "open profile: profile ID is empty")
+
+		_, err = k.OpenProfile("a|b")
+		require.EqualError(t, err, "open profile: profile ID must not contain '|'")
+	})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature"
+)
+
+// proofSigner signs the linked data proofs using the key kept by the LegacyKMS
+type proofSigner struct {
+	signer Signer
+	verKey string
+}
+
+// NewProofSigner returns the signer of the linked data proofs (e.g. for ed25519signature2018.WithSigner)
+// which signs using the private key associated with the given verification key (base58), so the credentials
+// can be signed with the keys of the LegacyKMS through verifiable.Credential AddLinkedDataProof.
+// The private key doesn't leave the LegacyKMS.
+func NewProofSigner(s Signer, verKey string) signature.Signer {
+	return &proofSigner{signer: s, verKey: verKey}
+}
+
+// Sign will sign data and return signature.
+func (s *proofSigner) Sign(data []byte) ([]byte, error) {
+	sig, err := s.signer.SignMessage(data, s.verKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign proof: %w", err)
+	}
+
+	return sig, nil
+}
+
+// PublicKey returns the Ed25519 public key corresponding to the signing key.
+func (s *proofSigner) PublicKey() crypto.PublicKey {
+	return ed25519.PublicKey(base58.Decode(s.verKey))
+}
+
+// KeyID returns the verification key (base58).
+func (s *proofSigner) KeyID() string {
+	return s.verKey
+}
@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+)
+
+func TestNewProofSigner(t *testing.T) {
+	kms := newTestKMS(t)
+
+	_, verKey, err := kms.CreateKeySet()
+	require.NoError(t, err)
+
+	t.Run("sign with LegacyKMS key", func(t *testing.T) {
+		signer := NewProofSigner(kms, verKey)
+		require.Equal(t, verKey, signer.KeyID())
+
+		pubKey, ok := signer.PublicKey().(ed25519.PublicKey)
+		require.True(t, ok)
+
+		data := []byte("canonicalized document")
+
+		sig, err := ed25519signature2018.New(ed25519signature2018.WithSigner(signer)).Sign(data)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, data, sig))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := NewProofSigner(kms, "unknown").Sign([]byte("data"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign proof")
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+const (
+	minSeedSize = 16
+	maxSeedSize = 64
+
+	// ed25519Curve is the HMAC key used to derive the master key from a seed (SLIP-0010)
+	ed25519Curve = "ed25519 seed"
+	// hardenedOffset is the first index of hardened child keys
+	hardenedOffset = uint32(0x80000000)
+
+	mnemonicSaltPrefix = "mnemonic"
+	mnemonicIterations = 2048
+)
+
+// ErrSeedNotDefined is returned when a key is derived by the LegacyKMS created without a seed
+var ErrSeedNotDefined = errors.New("seed is not defined")
+
+// NewFromSeed returns new instance of LegacyKMS implementation which is able to derive keys
+// deterministically from the given seed (see DeriveKey). The seed must be 16 to 64 bytes long,
+// e.g. the 64 bytes seed of a BIP39 mnemonic (see SeedFromMnemonic).
+func NewFromSeed(ctx provider, seed []byte, opts ...Option) (*BaseKMS, error) {
+	if len(seed) < minSeedSize || len(seed) > maxSeedSize {
+		return nil, fmt.Errorf("invalid seed length %d, expected %d to %d bytes", len(seed), minSeedSize, maxSeedSize)
+	}
+
+	w, err := New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	w.seed = append([]byte(nil), seed...)
+
+	return w, nil
+}
+
+// SeedFromMnemonic converts a BIP39 mnemonic sentence and an optional passphrase to a 64 bytes seed
+// (PBKDF2 with HMAC-SHA512, 2048 iterations, "mnemonic"+passphrase as salt).
+// The words of the mnemonic are not validated against the BIP39 word list.
+func SeedFromMnemonic(mnemonic, passphrase string) []byte {
+	words := strings.Join(strings.Fields(mnemonic), " ")
+
+	return pbkdf2.Key([]byte(words), []byte(mnemonicSaltPrefix+passphrase), mnemonicIterations, maxSeedSize, sha512.New)
+}
+
+// DeriveKey derives a signature key pair from the seed of the LegacyKMS and stores it together with
+// its encryption key pair, the same way as CreateKeySet does. The derived key can be used like any other
+// key of the LegacyKMS (e.g. for SignMessage or packing of messages).
+//
+// The derivation follows SLIP-0010 for ed25519: path is like "m/44'/0'/0'" and since ed25519 supports
+// hardened derivation only, every index must be hardened (marked with ' or H).
+// The same seed and path always produce the same key.
+//
+// returns:
+// 		string: signature public key (verKey) base58 encoded
+//		error: in case of errors (ErrSeedNotDefined if the LegacyKMS has no seed)
+func (w *BaseKMS) DeriveKey(path string) (string, error) {
+	if len(w.seed) == 0 {
+		return "", ErrSeedNotDefined
+	}
+
+	sigKp, err := deriveSigKeyPair(w.seed, path)
+	if err != nil {
+		return "", fmt.Errorf("derive key: %w", err)
+	}
+
+	_, verKey, err := w.storeKeySet(sigKp)
+	if err != nil {
+		return "", fmt.Errorf("derive key: %w", err)
+	}
+
+	return verKey, nil
+}
+
+func deriveSigKeyPair(seed []byte, path string) (*cryptoutil.SigKeyPair, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := hmacSHA512([]byte(ed25519Curve), seed)
+
+	for _, index := range indexes {
+		data := make([]byte, 1, 1+len(key)+4)
+		data = append(data, key...)
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+		key, chainCode = hmacSHA512(chainCode, data)
+	}
+
+	privKey := ed25519.NewKeyFromSeed(key)
+
+	return &cryptoutil.SigKeyPair{
+		KeyPair: cryptoutil.KeyPair{
+			Pub:  privKey.Public().(ed25519.PublicKey), //nolint:errcheck
+			Priv: privKey,
+		},
+		Alg: cryptoutil.EdDSA,
+	}, nil
+}
+
+// parseDerivationPath parses path like "m/44'/0'/0'" to hardened indexes.
+func parseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path '%s': must start with 'm'", path)
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+
+	for _, segment := range segments[1:] {
+		trimmed := strings.TrimRight(segment, "'H")
+		if trimmed == segment || len(segment)-len(trimmed) != 1 {
+			return nil, fmt.Errorf("invalid derivation path '%s': only hardened indexes are supported", path)
+		}
+
+		index, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path '%s': %w", path, err)
+		}
+
+		indexes = append(indexes, uint32(index)+hardenedOffset)
+	}
+
+	return indexes, nil
+}
+
+func hmacSHA512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data) //nolint:errcheck
+
+	sum := mac.Sum(nil)
+
+	return sum[:32], sum[32:]
+}
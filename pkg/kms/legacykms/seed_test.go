@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+// SLIP-0010 test vector 1 for ed25519
+const testSeed = "000102030405060708090a0b0c0d0e0f"
+
+func newSeedKMS(t *testing.T, seed []byte) *BaseKMS {
+	k, err := NewFromSeed(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+		Store: make(map[string][]byte),
+	}}), seed)
+	require.NoError(t, err)
+
+	return k
+}
+
+func TestNewFromSeed(t *testing.T) {
+	t.Run("test invalid seed length", func(t *testing.T) {
+		_, err := NewFromSeed(newMockKMSProvider(&mockstorage.MockStoreProvider{}), []byte("short"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid seed length")
+	})
+
+	t.Run("test error from OpenStore for keystore", func(t *testing.T) {
+		const errMsg = "error from OpenStore"
+		_, err := NewFromSeed(newMockKMSProvider(
+			&mockstorage.MockStoreProvider{ErrOpenStoreHandle: fmt.Errorf(errMsg)}), make([]byte, minSeedSize))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+}
+
+func TestBaseKMS_DeriveKey(t *testing.T) {
+	seed, err := hex.DecodeString(testSeed)
+	require.NoError(t, err)
+
+	t.Run("test SLIP-0010 vectors", func(t *testing.T) {
+		tests := []struct {
+			path   string
+			pubKey string
+		}{
+			{"m", "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed"},
+			{"m/0'", "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c"},
+			{"m/0H/1H", "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187"},
+			{"m/0'/1'/2'", "ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1"},
+		}
+
+		for _, tc := range tests {
+			k := newSeedKMS(t, seed)
+
+			verKey, err := k.DeriveKey(tc.path)
+			require.NoError(t, err, tc.path)
+			require.Equal(t, tc.pubKey, hex.EncodeToString(base58.Decode(verKey)), tc.path)
+		}
+	})
+
+	t.Run("test derived key is deterministic and usable", func(t *testing.T) {
+		verKey, err := newSeedKMS(t, seed).DeriveKey("m/44'/0'/0'")
+		require.NoError(t, err)
+
+		k := newSeedKMS(t, seed)
+		restoredVerKey, err := k.DeriveKey("m/44'/0'/0'")
+		require.NoError(t, err)
+		require.Equal(t, verKey, restoredVerKey)
+
+		signature, err := k.SignMessage([]byte("hello"), restoredVerKey)
+		require.NoError(t, err)
+		require.NoError(t, k.VerifyMessage([]byte("hello"), signature, restoredVerKey))
+
+		encKey, err := k.GetEncryptionKey(base58.Decode(restoredVerKey))
+		require.NoError(t, err)
+		require.NotEmpty(t, encKey)
+
+		otherVerKey, err := k.DeriveKey("m/44'/0'/1'")
+		require.NoError(t, err)
+		require.NotEqual(t, verKey, otherVerKey)
+	})
+
+	t.Run("test invalid path", func(t *testing.T) {
+		k := newSeedKMS(t, seed)
+
+		for _, path := range []string{"", "0'", "m/0", "m/0''", "m/x'", "m/2147483648'"} {
+			_, err := k.DeriveKey(path)
+			require.Error(t, err, path)
+			require.Contains(t, err.Error(), "invalid derivation path", path)
+		}
+	})
+
+	t.Run("test seed is not defined", func(t *testing.T) {
+		k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte),
+		}}))
+		require.NoError(t, err)
+
+		_, err = k.DeriveKey("m/0'")
+		require.Equal(t, ErrSeedNotDefined, err)
+	})
+
+	t.Run("test error from persistKey", func(t *testing.T) {
+		k, err := NewFromSeed(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrPut: fmt.Errorf("put error"),
+		}}), seed)
+		require.NoError(t, err)
+
+		_, err = k.DeriveKey("m/0'")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+	})
+}
+
+func TestSeedFromMnemonic(t *testing.T) {
+	// BIP39 test vector
+	seed := SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon "+
+		"abandon about", "TREZOR")
+	require.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2"+
+		"cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	// extra white spaces are ignored
+	require.Equal(t, seed, SeedFromMnemonic("  abandon abandon abandon abandon abandon abandon abandon abandon "+
+		"abandon abandon\tabandon about ", "TREZOR"))
+}
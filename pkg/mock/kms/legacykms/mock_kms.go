@@ -20,6 +20,7 @@ type CloseableKMS struct {
 	FindVerKeyErr            error
 	SignMessageValue         []byte
 	SignMessageErr           error
+	VerifyMessageErr         error
 	DecryptMessageValue      []byte
 	DecryptMessageErr        error
 	PackValue                []byte
@@ -29,6 +30,9 @@ type CloseableKMS struct {
 	MockDID                  *did.Doc
 	EncryptionKeyValue       []byte
 	EncryptionKeyErr         error
+	KeyAgreementKeyValue     []byte
+	DeriveECDHValue          []byte
+	DeriveECDHErr            error
 }
 
 // Close previously-opened LegacyKMS, removing it if so configured.
@@ -41,16 +45,31 @@ func (m *CloseableKMS) CreateKeySet() (string, string, error) {
 	return m.CreateEncryptionKeyValue, m.CreateSigningKeyValue, m.CreateKeyErr
 }
 
+// CreateKeyAgreementKey create a new X25519 key agreement keypair.
+func (m *CloseableKMS) CreateKeyAgreementKey() (string, []byte, error) {
+	return m.CreateEncryptionKeyValue, m.KeyAgreementKeyValue, m.CreateKeyErr
+}
+
 // FindVerKey return a verification key from the list of candidates
 func (m *CloseableKMS) FindVerKey(candidateKeys []string) (int, error) {
 	return m.FindVerKeyValue, m.FindVerKeyErr
 }
 
+// FindEncKey return an encryption key from the list of candidates, mocked by FindVerKeyValue and FindVerKeyErr
+func (m *CloseableKMS) FindEncKey(candidateKeys []string) (int, error) {
+	return m.FindVerKeyValue, m.FindVerKeyErr
+}
+
 // SignMessage sign a message using the private key associated with a given verification key.
 func (m *CloseableKMS) SignMessage(message []byte, fromVerKey string) ([]byte, error) {
 	return m.SignMessageValue, m.SignMessageErr
 }
 
+// VerifyMessage verifies a signature of the message using the key pair associated with a given verification key.
+func (m *CloseableKMS) VerifyMessage(message, signature []byte, fromVerKey string) error {
+	return m.VerifyMessageErr
+}
+
 // DeriveKEK derives a key encryption key from two keys
 // mocked to return empty derived KEK
 func (m *CloseableKMS) DeriveKEK(alg, apu, fromKey, toPubKey []byte) ([]byte, error) { // nolint:lll
@@ -66,3 +85,8 @@ func (m *CloseableKMS) GetEncryptionKey(verKey []byte) ([]byte, error) {
 func (m *CloseableKMS) ConvertToEncryptionKey(key []byte) ([]byte, error) {
 	return m.EncryptionKeyValue, m.EncryptionKeyErr
 }
+
+// DeriveECDH computes X25519 shared secret of the private key corresponding to myPub and theirPub
+func (m *CloseableKMS) DeriveECDH(myPub, theirPub []byte) ([]byte, error) {
+	return m.DeriveECDHValue, m.DeriveECDHErr
+}
@@ -0,0 +1,384 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// errStoreClosed is returned when the store is used after it was closed
+var errStoreClosed = errors.New("store is closed")
+
+const (
+	fileExtension = ".json"
+
+	dirPermission  = 0700
+	filePermission = 0600
+)
+
+// Provider file implementation of storage.Provider interface.
+// Each store is kept in a separate JSON file of the directory (keys are entries of the JSON object).
+// It is intended for small single-node agents which need durable storage without a database:
+// each write (Put or Delete) rewrites the whole store file, so its cost grows with the size of the store.
+// Transactions across stores (storage.TxProvider) are not supported.
+type Provider struct {
+	dir  string
+	dbs  map[string]*fileStore
+	lock sync.RWMutex
+}
+
+// NewProvider instantiates Provider which keeps the stores in the given directory
+func NewProvider(dir string) *Provider {
+	return &Provider{dbs: make(map[string]*fileStore), dir: dir}
+}
+
+// OpenStore opens and returns a store for given name space.
+// The data persisted by the store before is loaded.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	store := p.getFileStore(name)
+	if store == nil {
+		return p.newFileStore(name)
+	}
+
+	return store, nil
+}
+
+// getFileStore finds file store with given name
+// returns nil if not found
+func (p *Provider) getFileStore(name string) *fileStore {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.dbs[strings.ToLower(name)]
+}
+
+// newFileStore creates file store for given name space
+func (p *Provider) newFileStore(name string) (*fileStore, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := strings.ToLower(name)
+
+	// the store could be opened while waiting for the lock
+	if store, ok := p.dbs[k]; ok {
+		return store, nil
+	}
+
+	if err := os.MkdirAll(p.dir, dirPermission); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+
+	store := &fileStore{
+		path: filepath.Join(p.dir, url.PathEscape(k)+fileExtension),
+		db:   make(map[string][]byte),
+	}
+
+	if err := store.load(); err != nil {
+		return nil, fmt.Errorf("open store %s: %w", name, err)
+	}
+
+	p.dbs[k] = store
+
+	return store, nil
+}
+
+// Close closes all stores created under this store provider
+func (p *Provider) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, store := range p.dbs {
+		store.close()
+	}
+
+	p.dbs = make(map[string]*fileStore)
+
+	return nil
+}
+
+// CloseStore closes file store of given name
+func (p *Provider) CloseStore(name string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := strings.ToLower(name)
+
+	store, ok := p.dbs[k]
+	if ok {
+		delete(p.dbs, k)
+		store.close()
+	}
+
+	return nil
+}
+
+type fileStore struct {
+	path   string
+	db     map[string][]byte
+	closed bool
+	sync.RWMutex
+}
+
+// close releases the records kept in memory, the store can't be used after that
+func (s *fileStore) close() {
+	s.Lock()
+	s.db = nil
+	s.closed = true
+	s.Unlock()
+}
+
+// load reads the records of the store from its file (if exists)
+func (s *fileStore) load() error {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read store file: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &s.db); err != nil {
+		return fmt.Errorf("unmarshal store file: %w", err)
+	}
+
+	return nil
+}
+
+// persist writes the records of the store to its file.
+// The records are written to a temporary file which is synced and then renamed, so the store file
+// is never left partially written. The directory is synced after the rename, so the new file survives a crash.
+// All the records are written on each change, i.e. the cost of a write is O(n) of the store size.
+func (s *fileStore) persist() error {
+	data, err := json.Marshal(s.db)
+	if err != nil {
+		return fmt.Errorf("marshal store: %w", err)
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary store file: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name()) //nolint:errcheck
+
+	if err = writeAndSync(tmpFile, data); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpFile.Name(), s.path); err != nil {
+		return fmt.Errorf("replace store file: %w", err)
+	}
+
+	return syncDir(filepath.Dir(s.path))
+}
+
+// syncDir syncs the directory, so the renamed file is persisted
+func syncDir(dir string) error {
+	d, err := os.Open(filepath.Clean(dir))
+	if err != nil {
+		return fmt.Errorf("open store directory: %w", err)
+	}
+
+	err = d.Sync()
+
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("sync store directory: %w", err)
+	}
+
+	return nil
+}
+
+func writeAndSync(f *os.File, data []byte) error {
+	_, err := f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("write store file: %w", err)
+	}
+
+	return os.Chmod(f.Name(), filePermission)
+}
+
+// Put stores the key and the record.
+// The whole store is written to its file and synced, so the cost of Put is O(n) of the store size.
+func (s *fileStore) Put(k string, v []byte) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return errStoreClosed
+	}
+
+	prev, existed := s.db[k]
+	s.db[k] = v
+
+	if err := s.persist(); err != nil {
+		// restore the previous state so the store matches the file
+		if existed {
+			s.db[k] = prev
+		} else {
+			delete(s.db, k)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Get fetches the record based on key
+func (s *fileStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	s.RLock()
+	data, ok := s.db[k]
+	closed := s.closed
+	s.RUnlock()
+
+	if closed {
+		return nil, errStoreClosed
+	}
+
+	if !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return data, nil
+}
+
+// Iterator returns iterator for the latest snapshot of the underlying store.
+// The keys are iterated in lexicographical order within [start, limit) range.
+func (s *fileStore) Iterator(start, limit string) storage.StoreIterator {
+	s.RLock()
+	defer s.RUnlock()
+
+	var keys []string
+
+	for k := range s.db {
+		if k >= start && k < limit {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	values := make([][]byte, len(keys))
+	for i, k := range keys {
+		values[i] = s.db[k]
+	}
+
+	return &fileIterator{keys: keys, values: values, currentIndex: -1}
+}
+
+// Delete will delete record with k key
+func (s *fileStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return errStoreClosed
+	}
+
+	prev, ok := s.db[k]
+	if !ok {
+		return nil
+	}
+
+	delete(s.db, k)
+
+	if err := s.persist(); err != nil {
+		s.db[k] = prev
+
+		return err
+	}
+
+	return nil
+}
+
+type fileIterator struct {
+	keys         []string
+	values       [][]byte
+	currentIndex int
+}
+
+// Next moves pointer to next value of iterator.
+// It returns false if the iterator is exhausted.
+func (i *fileIterator) Next() bool {
+	if i.currentIndex+1 >= len(i.keys) {
+		i.currentIndex = len(i.keys)
+
+		return false
+	}
+
+	i.currentIndex++
+
+	return true
+}
+
+// Release releases associated resources.
+func (i *fileIterator) Release() {
+	i.keys = nil
+	i.values = nil
+	i.currentIndex = -1
+}
+
+// Error returns error in iterator.
+func (i *fileIterator) Error() error {
+	return nil
+}
+
+// Key returns the key of the current key/value pair.
+func (i *fileIterator) Key() []byte {
+	if i.currentIndex < 0 || i.currentIndex >= len(i.keys) {
+		return nil
+	}
+
+	return []byte(i.keys[i.currentIndex])
+}
+
+// Value returns the value of the current key/value pair.
+func (i *fileIterator) Value() []byte {
+	if i.currentIndex < 0 || i.currentIndex >= len(i.values) {
+		return nil
+	}
+
+	return i.values[i.currentIndex]
+}
@@ -0,0 +1,280 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func setupFileStore(t testing.TB) (string, func()) {
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		t.Fatalf("Failed to create file store directory: %s", err)
+	}
+
+	return dir, func() {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			t.Fatalf("Failed to clear file store directory: %s", err)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, cleanup := setupFileStore(t)
+	defer cleanup()
+
+	t.Run("Test file store put and get", func(t *testing.T) {
+		prov := NewProvider(dir)
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		const key = "did:example:123"
+		data := []byte("value")
+
+		err = store.Put(key, data)
+		require.NoError(t, err)
+
+		doc, err := store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		_, err = store.Get("did:example:789")
+		require.Equal(t, storage.ErrDataNotFound, err)
+
+		// nil key
+		_, err = store.Get("")
+		require.Error(t, err)
+
+		// nil value
+		err = store.Put(key, nil)
+		require.Error(t, err)
+
+		// nil key
+		err = store.Put("", data)
+		require.Error(t, err)
+
+		err = prov.Close()
+		require.NoError(t, err)
+
+		// try to use the store after provider is closed
+		_, err = store.Get(key)
+		require.Equal(t, errStoreClosed, err)
+		require.Equal(t, errStoreClosed, store.Put(key, data))
+		require.Equal(t, errStoreClosed, store.Delete(key))
+	})
+
+	t.Run("Test file store data survives restart", func(t *testing.T) {
+		prov := NewProvider(dir)
+		store, err := prov.OpenStore("Persistent")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("k1", []byte("v1")))
+		require.NoError(t, store.Put("k2", []byte("v2")))
+		require.NoError(t, store.Delete("k2"))
+		require.NoError(t, prov.Close())
+
+		// new provider (e.g. after agent restart) reads the same directory
+		prov = NewProvider(dir)
+		store, err = prov.OpenStore("persistent")
+		require.NoError(t, err)
+
+		v, err := store.Get("k1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v1"), v)
+
+		_, err = store.Get("k2")
+		require.Equal(t, storage.ErrDataNotFound, err)
+
+		// no temporary files are left
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+
+		for _, f := range files {
+			require.Equal(t, ".json", filepath.Ext(f.Name()))
+		}
+	})
+
+	t.Run("Test file store multi store put and get", func(t *testing.T) {
+		prov := NewProvider(dir)
+		const commonKey = "did:example:1"
+		data := []byte("value1")
+
+		store1, err := prov.OpenStore("store1")
+		require.NoError(t, err)
+
+		store2, err := prov.OpenStore("store2")
+		require.NoError(t, err)
+
+		require.NoError(t, store1.Put(commonKey, data))
+
+		_, err = store2.Get(commonKey)
+		require.Equal(t, storage.ErrDataNotFound, err)
+
+		// open store with same name as store1
+		store3, err := prov.OpenStore("STORE1")
+		require.NoError(t, err)
+
+		doc, err := store3.Get(commonKey)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		require.Len(t, prov.dbs, 2)
+
+		require.NoError(t, prov.CloseStore("Store1"))
+		require.NoError(t, prov.CloseStore("store_x"))
+		require.Len(t, prov.dbs, 1)
+
+		_, err = store1.Get(commonKey)
+		require.Equal(t, errStoreClosed, err)
+	})
+
+	t.Run("Test file store iterator", func(t *testing.T) {
+		prov := NewProvider(dir)
+		store, err := prov.OpenStore("test-iterator")
+		require.NoError(t, err)
+
+		const valPrefix = "val-for-%s"
+		keys := []string{"abc_126", "abc_123", "abc_125", "abc_124", "jkl_123", "mno_123"}
+
+		for _, key := range keys {
+			err = store.Put(key, []byte(fmt.Sprintf(valPrefix, key)))
+			require.NoError(t, err)
+		}
+
+		itr := store.Iterator("abc_", "abc_~")
+		verifyItr(t, itr, []string{"abc_123", "abc_124", "abc_125", "abc_126"})
+
+		itr = store.Iterator("", "")
+		verifyItr(t, itr, nil)
+
+		itr = store.Iterator("abc_", "mno_~")
+		verifyItr(t, itr, []string{"abc_123", "abc_124", "abc_125", "abc_126", "jkl_123", "mno_123"})
+	})
+
+	t.Run("Test file store concurrent access", func(t *testing.T) {
+		prov := NewProvider(dir)
+		store, err := prov.OpenStore("concurrent")
+		require.NoError(t, err)
+
+		const count = 20
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				require.NoError(t, store.Put(fmt.Sprintf("key_%02d", i), []byte("value")))
+			}(i)
+		}
+
+		wg.Wait()
+
+		prov = NewProvider(dir)
+		store, err = prov.OpenStore("concurrent")
+		require.NoError(t, err)
+
+		itr := store.Iterator("key_", "key_~")
+		count2 := 0
+
+		for itr.Next() {
+			count2++
+		}
+
+		require.Equal(t, count, count2)
+	})
+}
+
+func TestFileStoreFailures(t *testing.T) {
+	dir, cleanup := setupFileStore(t)
+	defer cleanup()
+
+	t.Run("Test file instead of directory", func(t *testing.T) {
+		file := filepath.Join(dir, "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("data"), 0600))
+
+		store, err := NewProvider(file).OpenStore("sample")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create store directory")
+		require.Nil(t, store)
+	})
+
+	t.Run("Test corrupted store file", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "corrupted.json"), []byte("{"), 0600))
+
+		store, err := NewProvider(dir).OpenStore("corrupted")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal store file")
+		require.Nil(t, store)
+	})
+
+	t.Run("Test store file can't be written", func(t *testing.T) {
+		storeDir := filepath.Join(dir, "removed")
+
+		store, err := NewProvider(storeDir).OpenStore("sample")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("k1", []byte("v1")))
+		require.NoError(t, os.RemoveAll(storeDir))
+
+		err = store.Put("k2", []byte("v2"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create temporary store file")
+
+		// failed put is reverted
+		_, err = store.Get("k2")
+		require.Equal(t, storage.ErrDataNotFound, err)
+
+		err = store.Delete("k1")
+		require.Error(t, err)
+
+		v, err := store.Get("k1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v1"), v)
+	})
+
+	t.Run("Test store directory can't be synced", func(t *testing.T) {
+		err := syncDir(filepath.Join(dir, "unknown"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open store directory")
+
+		require.NoError(t, syncDir(dir))
+	})
+}
+
+func verifyItr(t *testing.T, itr storage.StoreIterator, keys []string) {
+	var actual []string
+
+	for itr.Next() {
+		require.True(t, strings.HasPrefix(string(itr.Value()), "val-for-"))
+
+		actual = append(actual, string(itr.Key()))
+	}
+
+	require.Equal(t, keys, actual)
+
+	itr.Release()
+	require.False(t, itr.Next())
+	require.Empty(t, itr.Key())
+	require.Empty(t, itr.Value())
+	require.NoError(t, itr.Error())
+}
@@ -23,7 +23,8 @@ import (
 
 const pathPattern = "%s-%s"
 
-// Provider leveldb implementation of storage.Provider interface
+// Provider leveldb implementation of storage.Provider interface.
+// Each store is a separate leveldb database, so transactions across stores (storage.TxProvider) are not supported.
 type Provider struct {
 	dbPath string
 	dbs    map[string]*leveldbStore
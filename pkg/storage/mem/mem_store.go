@@ -17,7 +17,7 @@ import (
 // TODO https://github.com/hyperledger/aries-framework-go/issues/750 - we will need to consider
 //  automatic eviction based on TTL.
 
-// Provider leveldb implementation of storage.Provider interface
+// Provider mem implementation of storage.Provider interface, it also implements storage.TxProvider
 type Provider struct {
 	dbs  map[string]*memStore
 	lock sync.RWMutex
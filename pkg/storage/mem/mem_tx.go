@@ -0,0 +1,228 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// BeginTx starts a new transaction. The writes of the transaction are applied to all its stores atomically
+// on Commit, i.e. the readers see either none or all of them.
+func (p *Provider) BeginTx() (storage.Tx, error) {
+	return &memTx{provider: p, stores: make(map[string]*txStore)}, nil
+}
+
+type memTx struct {
+	provider *Provider
+	stores   map[string]*txStore
+	done     bool
+	lock     sync.Mutex
+}
+
+// Store opens the store with given name space scoped to the transaction.
+func (tx *memTx) Store(name string) (storage.Store, error) {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.done {
+		return nil, storage.ErrTxDone
+	}
+
+	k := strings.ToLower(name)
+
+	if store, ok := tx.stores[k]; ok {
+		return store, nil
+	}
+
+	store := &txStore{tx: tx, name: k, writes: make(map[string][]byte)}
+	tx.stores[k] = store
+
+	return store, nil
+}
+
+// Commit applies the writes of all the stores of the transaction.
+func (tx *memTx) Commit() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.done {
+		return storage.ErrTxDone
+	}
+
+	tx.done = true
+
+	names := make([]string, 0, len(tx.stores))
+	for name := range tx.stores {
+		names = append(names, name)
+	}
+
+	// the stores are locked in the same order by all the transactions to avoid deadlocks
+	sort.Strings(names)
+
+	stores := make([]*memStore, len(names))
+
+	for i, name := range names {
+		store, err := tx.provider.OpenStore(name)
+		if err != nil {
+			return err
+		}
+
+		stores[i] = store.(*memStore) // nolint:errcheck // mem provider always opens *memStore
+	}
+
+	for _, store := range stores {
+		store.Lock()
+	}
+
+	defer func() {
+		for _, store := range stores {
+			store.Unlock()
+		}
+	}()
+
+	for i, name := range names {
+		for k, v := range tx.stores[name].writes {
+			if v == nil {
+				delete(stores[i].db, k)
+			} else {
+				stores[i].db[k] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rollback discards the writes of all the stores of the transaction.
+func (tx *memTx) Rollback() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.done {
+		return storage.ErrTxDone
+	}
+
+	tx.done = true
+	tx.stores = make(map[string]*txStore)
+
+	return nil
+}
+
+// txStore keeps the writes of the store until the transaction is committed, nil value marks the deleted key.
+type txStore struct {
+	tx     *memTx
+	name   string
+	writes map[string][]byte
+}
+
+// committed returns the store the transaction is committed to.
+func (s *txStore) committed() (storage.Store, error) {
+	return s.tx.provider.OpenStore(s.name)
+}
+
+// Put stores the key and the record within the transaction
+func (s *txStore) Put(k string, v []byte) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	s.tx.lock.Lock()
+	defer s.tx.lock.Unlock()
+
+	if s.tx.done {
+		return storage.ErrTxDone
+	}
+
+	s.writes[k] = v
+
+	return nil
+}
+
+// Get fetches the record based on key, the writes of the transaction take precedence
+func (s *txStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	s.tx.lock.Lock()
+	v, ok := s.writes[k]
+	done := s.tx.done
+	s.tx.lock.Unlock()
+
+	switch {
+	case done:
+		return nil, storage.ErrTxDone
+	case ok && v == nil:
+		return nil, storage.ErrDataNotFound
+	case ok:
+		return v, nil
+	}
+
+	store, err := s.committed()
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Get(k)
+}
+
+// Iterator returns iterator for the latest snapshot of the underlying db merged with the writes of the transaction.
+func (s *txStore) Iterator(start, limit string) storage.StoreIterator {
+	s.tx.lock.Lock()
+	defer s.tx.lock.Unlock()
+
+	if s.tx.done {
+		return &memIterator{err: storage.ErrTxDone}
+	}
+
+	store, err := s.committed()
+	if err != nil {
+		return &memIterator{err: err}
+	}
+
+	var batch [][]string
+
+	itr := store.Iterator(start, limit)
+	defer itr.Release()
+
+	for itr.Next() {
+		if _, ok := s.writes[string(itr.Key())]; !ok {
+			batch = append(batch, []string{string(itr.Key()), string(itr.Value())})
+		}
+	}
+
+	for k, v := range s.writes {
+		if v != nil && strings.HasPrefix(k, start) {
+			batch = append(batch, []string{k, string(v)})
+		}
+	}
+
+	return newMemIterator(batch)
+}
+
+// Delete will delete record with k key when the transaction is committed
+func (s *txStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	s.tx.lock.Lock()
+	defer s.tx.lock.Unlock()
+
+	if s.tx.done {
+		return storage.ErrTxDone
+	}
+
+	s.writes[k] = nil
+
+	return nil
+}
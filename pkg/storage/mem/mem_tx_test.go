@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func TestMemTx(t *testing.T) {
+	const (
+		key1 = "did:example:1"
+		key2 = "did:example:2"
+	)
+
+	newTx := func(t *testing.T, prov *Provider) storage.Tx {
+		var txProv storage.TxProvider = prov
+
+		tx, err := txProv.BeginTx()
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	t.Run("commit writes to several stores", func(t *testing.T) {
+		prov := NewProvider()
+
+		store1, err := prov.OpenStore("store1")
+		require.NoError(t, err)
+		require.NoError(t, store1.Put(key2, []byte("to be deleted")))
+
+		tx := newTx(t, prov)
+
+		txStore1, err := tx.Store("store1")
+		require.NoError(t, err)
+		require.NoError(t, txStore1.Put(key1, []byte("value1")))
+		require.NoError(t, txStore1.Delete(key2))
+
+		txStore2, err := tx.Store("Store2")
+		require.NoError(t, err)
+		require.NoError(t, txStore2.Put(key1, []byte("value2")))
+
+		// the transaction sees its writes
+		v, err := txStore1.Get(key1)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), v)
+
+		_, err = txStore1.Get(key2)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// the writes are not visible outside of the transaction
+		_, err = store1.Get(key1)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err = store1.Get(key2)
+		require.NoError(t, err)
+		require.Equal(t, []byte("to be deleted"), v)
+
+		require.NoError(t, tx.Commit())
+
+		v, err = store1.Get(key1)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), v)
+
+		_, err = store1.Get(key2)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		store2, err := prov.OpenStore("store2")
+		require.NoError(t, err)
+
+		v, err = store2.Get(key1)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), v)
+
+		// the transaction is done
+		require.True(t, errors.Is(tx.Rollback(), storage.ErrTxDone))
+		require.True(t, errors.Is(tx.Commit(), storage.ErrTxDone))
+		require.True(t, errors.Is(txStore1.Put(key1, []byte("value")), storage.ErrTxDone))
+		require.True(t, errors.Is(txStore1.Delete(key1), storage.ErrTxDone))
+
+		_, err = txStore1.Get(key1)
+		require.True(t, errors.Is(err, storage.ErrTxDone))
+
+		_, err = tx.Store("store1")
+		require.True(t, errors.Is(err, storage.ErrTxDone))
+
+		itr := txStore1.Iterator("", "")
+		require.False(t, itr.Next())
+		require.True(t, errors.Is(itr.Error(), storage.ErrTxDone))
+	})
+
+	t.Run("rollback discards writes", func(t *testing.T) {
+		prov := NewProvider()
+		tx := newTx(t, prov)
+
+		txStore, err := tx.Store("store")
+		require.NoError(t, err)
+		require.NoError(t, txStore.Put(key1, []byte("value")))
+
+		require.NoError(t, tx.Rollback())
+		require.True(t, errors.Is(tx.Commit(), storage.ErrTxDone))
+
+		store, err := prov.OpenStore("store")
+		require.NoError(t, err)
+
+		_, err = store.Get(key1)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("iterator merges writes of the transaction", func(t *testing.T) {
+		prov := NewProvider()
+
+		store, err := prov.OpenStore("store")
+		require.NoError(t, err)
+		require.NoError(t, store.Put("abc1", []byte("committed1")))
+		require.NoError(t, store.Put("abc2", []byte("committed2")))
+		require.NoError(t, store.Put("abc3", []byte("committed3")))
+
+		tx := newTx(t, prov)
+
+		txStore, err := tx.Store("store")
+		require.NoError(t, err)
+
+		// the same store is returned for the name
+		sameTxStore, err := tx.Store("store")
+		require.NoError(t, err)
+		require.Equal(t, txStore, sameTxStore)
+
+		require.NoError(t, txStore.Put("abc2", []byte("updated2")))
+		require.NoError(t, txStore.Delete("abc3"))
+		require.NoError(t, txStore.Put("abc4", []byte("added4")))
+		require.NoError(t, txStore.Put("xyz", []byte("other")))
+
+		itr := txStore.Iterator("abc", "abc"+"~")
+		defer itr.Release()
+
+		values := make(map[string]string)
+		for itr.Next() {
+			values[string(itr.Key())] = string(itr.Value())
+		}
+
+		require.NoError(t, itr.Error())
+		require.Equal(t, map[string]string{
+			"abc1": "committed1",
+			"abc2": "updated2",
+			"abc4": "added4",
+		}, values)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		txStore, err := newTx(t, NewProvider()).Store("store")
+		require.NoError(t, err)
+
+		require.EqualError(t, txStore.Put("", []byte("value")), "key and value are mandatory")
+		require.EqualError(t, txStore.Put(key1, nil), "key and value are mandatory")
+		require.EqualError(t, txStore.Delete(""), "key is mandatory")
+
+		_, err = txStore.Get("")
+		require.EqualError(t, err, "key is mandatory")
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package storage
+
+import "strings"
+
+// prefixedStore isolates the keyspace of a subsystem within a shared store by prefixing the keys.
+type prefixedStore struct {
+	store  Store
+	prefix string
+}
+
+// NewPrefixedStore returns the store which transparently prefixes keys on Put, Get and Delete
+// and strips the prefix from the keys returned by the iterator.
+func NewPrefixedStore(s Store, prefix string) Store {
+	return &prefixedStore{store: s, prefix: prefix}
+}
+
+// Put stores the key (with the prefix) and the record
+func (s *prefixedStore) Put(k string, v []byte) error {
+	return s.store.Put(s.prefix+k, v)
+}
+
+// Get fetches the record based on key (with the prefix)
+func (s *prefixedStore) Get(k string) ([]byte, error) {
+	return s.store.Get(s.prefix + k)
+}
+
+// Iterator returns an iterator over the prefixed key range. Keys returned by the iterator do not contain the prefix.
+func (s *prefixedStore) Iterator(start, limit string) StoreIterator {
+	return &prefixedIterator{
+		StoreIterator: s.store.Iterator(s.prefix+start, s.prefix+limit),
+		prefix:        s.prefix,
+	}
+}
+
+// Delete will delete a record with k key (with the prefix)
+func (s *prefixedStore) Delete(k string) error {
+	return s.store.Delete(s.prefix + k)
+}
+
+// prefixedIterator strips the prefix from the keys of the underlying iterator.
+type prefixedIterator struct {
+	StoreIterator
+	prefix string
+}
+
+// Key returns the key of the current key/value pair without the prefix, or nil if done.
+func (i *prefixedIterator) Key() []byte {
+	key := i.StoreIterator.Key()
+	if key == nil {
+		return nil
+	}
+
+	return []byte(strings.TrimPrefix(string(key), i.prefix))
+}
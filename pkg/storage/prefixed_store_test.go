@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestPrefixedStore(t *testing.T) {
+	t.Run("test put, get and delete", func(t *testing.T) {
+		shared, err := mem.NewProvider().OpenStore("shared")
+		require.NoError(t, err)
+
+		metadata := storage.NewPrefixedStore(shared, "metadata_")
+		messages := storage.NewPrefixedStore(shared, "message_")
+
+		require.NoError(t, metadata.Put("thread-1", []byte("metadata")))
+		require.NoError(t, messages.Put("thread-1", []byte("message")))
+
+		v, err := metadata.Get("thread-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("metadata"), v)
+
+		v, err = messages.Get("thread-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("message"), v)
+
+		// keys are prefixed in the underlying store
+		v, err = shared.Get("metadata_thread-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("metadata"), v)
+
+		require.NoError(t, metadata.Delete("thread-1"))
+
+		_, err = metadata.Get("thread-1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err = messages.Get("thread-1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("message"), v)
+	})
+
+	t.Run("test iterator strips the prefix", func(t *testing.T) {
+		shared, err := mem.NewProvider().OpenStore("shared")
+		require.NoError(t, err)
+
+		require.NoError(t, shared.Put("conn_1", []byte("other")))
+
+		s := storage.NewPrefixedStore(shared, "record_")
+		require.NoError(t, s.Put("conn_1", []byte("value1")))
+		require.NoError(t, s.Put("conn_2", []byte("value2")))
+
+		itr := s.Iterator("conn_", "conn_~")
+		defer itr.Release()
+
+		values := make(map[string]string)
+		for itr.Next() {
+			values[string(itr.Key())] = string(itr.Value())
+		}
+
+		require.NoError(t, itr.Error())
+		require.Equal(t, map[string]string{"conn_1": "value1", "conn_2": "value2"}, values)
+	})
+}
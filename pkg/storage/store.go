@@ -11,6 +11,9 @@ import "errors"
 // ErrDataNotFound is returned when data not found
 var ErrDataNotFound = errors.New("data not found")
 
+// ErrTxDone is returned when the transaction is used after it has been committed or rolled back
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
 // Provider storage provider interface
 type Provider interface {
 	// OpenStore opens a store with given name space and returns the handle
@@ -23,6 +26,32 @@ type Provider interface {
 	Close() error
 }
 
+// TxProvider is optionally implemented by storage providers which support transactions spanning
+// several stores (e.g. to save a connection record and its keys at once).
+//
+// The writes of the transaction are applied all together on Commit. Providers which can't update several
+// stores atomically (e.g. the ones keeping a separate database per store) may implement it on a best-effort
+// basis: the writes are still applied only on Commit, but a crash or a failure in the middle of Commit
+// may leave some of the stores updated and the others not. Such providers must document it.
+type TxProvider interface {
+	// BeginTx starts a new transaction
+	BeginTx() (Tx, error)
+}
+
+// Tx is the storage transaction
+type Tx interface {
+	// Store opens the store with given name space scoped to the transaction. Its writes are not visible
+	// outside of the transaction until it is committed, reads see the writes of the transaction.
+	Store(name string) (Store, error)
+
+	// Commit applies all the writes of the transaction
+	Commit() error
+
+	// Rollback discards all the writes of the transaction. Returns ErrTxDone if the transaction is already
+	// committed or rolled back, so it is safe to defer it.
+	Rollback() error
+}
+
 // Store is the storage interface
 type Store interface {
 	// Put stores the key and the record
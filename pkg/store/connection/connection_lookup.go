@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -24,7 +25,10 @@ const (
 	connIDKeyPrefix    = "conn"
 	connStateKeyPrefix = "connstate"
 	invKeyPrefix       = "inv"
+	invMetadataPrefix  = "invmetadata"
 	eventDataKeyprefix = "connevent"
+	historyKeyPrefix   = "connhistory"
+	rotationKeyPrefix  = "didrotation"
 	// limitPattern with `~` at the end for lte of given prefix (less than or equal)
 	limitPattern    = "%s~"
 	keySeparator    = "_"
@@ -49,10 +53,23 @@ type Record struct {
 	MyDID           string
 	ServiceEndPoint string
 	RecipientKeys   []string
+	RoutingKeys     []string
 	InvitationID    string
 	InvitationDID   string
 	Implicit        bool
 	Namespace       string
+	// RequireEncryption forbids sending the messages of the connection unencrypted
+	RequireEncryption bool
+	// Metadata is the application data of the connection (e.g. the ID of the user the connection belongs to)
+	Metadata map[string]interface{}
+}
+
+// StateTransition is the transition of did exchange connection from one state to another
+type StateTransition struct {
+	FromState string
+	ToState   string
+	MsgType   string
+	Time      time.Time
 }
 
 // NewLookup returns new connection lookup instance.
@@ -143,6 +160,23 @@ func (c *Lookup) QueryConnectionRecords() ([]*Record, error) {
 	return records, nil
 }
 
+// GetConnectionRecordByDIDs return connection record of the connection between myDID and theirDID,
+// storage.ErrDataNotFound is returned if there is no such connection.
+func (c *Lookup) GetConnectionRecordByDIDs(myDID, theirDID string) (*Record, error) {
+	records, err := c.QueryConnectionRecords()
+	if err != nil {
+		return nil, fmt.Errorf("get connection record by DIDs: %w", err)
+	}
+
+	for _, record := range records {
+		if record.MyDID == myDID && record.TheirDID == theirDID {
+			return record, nil
+		}
+	}
+
+	return nil, storage.ErrDataNotFound
+}
+
 // GetConnectionRecordAtState return connection record based on the connection ID and state.
 func (c *Lookup) GetConnectionRecordAtState(connectionID, stateID string) (*Record, error) {
 	if stateID == "" {
@@ -186,6 +220,58 @@ func (c *Lookup) GetInvitation(id string, target interface{}) error {
 	return getAndUnmarshal(getInvitationKeyPrefix()(id), target, c.store)
 }
 
+// GetInvitationMetadata returns the metadata of the connections created for the invitation with the given ID
+// (see Recorder SaveInvitationMetadata).
+func (c *Lookup) GetInvitationMetadata(invitationID string) (map[string]interface{}, error) {
+	if invitationID == "" {
+		return nil, fmt.Errorf(errMsgInvalidKey)
+	}
+
+	var metadata map[string]interface{}
+
+	if err := getAndUnmarshal(getInvitationMetadataKeyPrefix()(invitationID), &metadata, c.store); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// GetConnectionHistory returns state transitions persisted for given connection ID in chronological order
+func (c *Lookup) GetConnectionHistory(connectionID string) ([]StateTransition, error) {
+	if connectionID == "" {
+		return nil, fmt.Errorf(errMsgInvalidKey)
+	}
+
+	var history []StateTransition
+
+	err := getAndUnmarshal(getHistoryKeyPrefix()(connectionID), &history, c.store)
+	if err != nil {
+		return nil, fmt.Errorf("get connection history: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetCurrentDIDs returns the current DIDs of the connection the given DIDs belonged to before the DID rotations
+// (see SaveDIDRotation), the given DIDs are returned if the connection of them was never rotated.
+func (c *Lookup) GetCurrentDIDs(myDID, theirDID string) (string, string, error) {
+	connectionID, err := c.store.Get(getRotationKeyPrefix()(myDID, theirDID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return myDID, theirDID, nil
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("get DID rotation: %w", err)
+	}
+
+	record, err := c.GetConnectionRecord(string(connectionID))
+	if err != nil {
+		return "", "", fmt.Errorf("get rotated connection: %w", err)
+	}
+
+	return record.MyDID, record.TheirDID, nil
+}
+
 // GetEvent returns persisted event data for given connection ID
 // TODO connection event data shouldn't be transient [Issues #1029]
 func (c *Recorder) GetEvent(connectionID string) ([]byte, error) {
@@ -231,6 +317,20 @@ func getInvitationKeyPrefix() KeyPrefix {
 	}
 }
 
+// getInvitationMetadataKeyPrefix key prefix for saving metadata of the connections of invitations
+func getInvitationMetadataKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, invMetadataPrefix, strings.Join(key, keySeparator))
+	}
+}
+
+// getRotationKeyPrefix key prefix for saving DID rotations
+func getRotationKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, rotationKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
 // getNamespaceKeyPrefix key prefix for saving connections records with mappings
 func getNamespaceKeyPrefix(prefix string) KeyPrefix {
 	return func(key ...string) string {
@@ -245,6 +345,13 @@ func getEventDataKeyPrefix() KeyPrefix {
 	}
 }
 
+// getHistoryKeyPrefix key prefix for saving connection state transitions
+func getHistoryKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, historyKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
 // CreateNamespaceKey creates key prefix for namespace related data
 func CreateNamespaceKey(prefix, thID string) (string, error) {
 	key, err := computeHash([]byte(thID))
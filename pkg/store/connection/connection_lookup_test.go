@@ -11,6 +11,7 @@ package connection
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -289,6 +290,44 @@ func TestConnectionReader_GetConnectionRecordByNSThreadID(t *testing.T) {
 	})
 }
 
+func TestConnectionReader_GetConnectionRecordByDIDs(t *testing.T) {
+	t.Run("get connection record by DIDs", func(t *testing.T) {
+		recorder, err := NewRecorder(&mockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ConnectionID: "id1", MyDID: "myDID", TheirDID: "did1",
+			State: stateNameCompleted}))
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ConnectionID: "id2", MyDID: "myDID", TheirDID: "did2",
+			RequireEncryption: true}))
+
+		record, err := recorder.GetConnectionRecordByDIDs("myDID", "did1")
+		require.NoError(t, err)
+		require.Equal(t, "id1", record.ConnectionID)
+
+		record, err = recorder.GetConnectionRecordByDIDs("myDID", "did2")
+		require.NoError(t, err)
+		require.Equal(t, "id2", record.ConnectionID)
+		require.True(t, record.RequireEncryption)
+
+		record, err = recorder.GetConnectionRecordByDIDs("did1", "myDID")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+		require.Nil(t, record)
+	})
+
+	t.Run("get connection record by DIDs - query error", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		require.NoError(t, store.Put(fmt.Sprintf("%s_abc123", connIDKeyPrefix), []byte("-----")))
+
+		lookup, err := NewLookup(&mockProvider{store: store})
+		require.NoError(t, err)
+
+		record, err := lookup.GetConnectionRecordByDIDs("myDID", "did1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record by DIDs")
+		require.Nil(t, record)
+	})
+}
+
 func TestConnectionRecorder_QueryConnectionRecord(t *testing.T) {
 	t.Run("test query connection record", func(t *testing.T) {
 		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
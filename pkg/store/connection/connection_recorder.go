@@ -54,6 +54,16 @@ func (c *Recorder) SaveInvitation(id string, invitation interface{}) error {
 	return marshalAndSave(getInvitationKeyPrefix()(id), invitation, c.store)
 }
 
+// SaveInvitationMetadata saves the metadata which is assigned to the connections created for the invitation
+// with the given ID (see Record Metadata).
+func (c *Recorder) SaveInvitationMetadata(invitationID string, metadata map[string]interface{}) error {
+	if invitationID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return marshalAndSave(getInvitationMetadataKeyPrefix()(invitationID), metadata, c.store)
+}
+
 // SaveConnectionRecord saves given connection records in underlying store
 func (c *Recorder) SaveConnectionRecord(record *Record) error {
 	if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
@@ -106,6 +116,20 @@ func (c *Recorder) SaveEvent(connectionID string, data []byte) error {
 	return c.transientStore.Put(getEventDataKeyPrefix()(connectionID), data)
 }
 
+// SaveStateTransition appends given state transition to the history of the connection in permanent store
+func (c *Recorder) SaveStateTransition(connectionID string, transition StateTransition) error {
+	if connectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	history, err := c.GetConnectionHistory(connectionID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("save state transition: %w", err)
+	}
+
+	return marshalAndSave(getHistoryKeyPrefix()(connectionID), append(history, transition), c.store)
+}
+
 // SaveNamespaceThreadID saves given namespace, threadID and connection ID mapping in transient store
 func (c *Recorder) SaveNamespaceThreadID(threadID, namespace, connectionID string) error {
 	if namespace != myNSPrefix && namespace != theirNSPrefix {
@@ -125,6 +149,17 @@ func (c *Recorder) SaveNamespaceThreadID(threadID, namespace, connectionID strin
 	return c.transientStore.Put(getNamespaceKeyPrefix(prefix)(key), []byte(connectionID))
 }
 
+// SaveDIDRotation saves in permanent store that the connection between the given DIDs was rotated,
+// the current DIDs of the connection are then taken from its record (see GetCurrentDIDs).
+// The rotation is bound to the connection, so it never affects the other connections of the parties.
+func (c *Recorder) SaveDIDRotation(connectionID, oldMyDID, oldTheirDID string) error {
+	if connectionID == "" || oldMyDID == "" || oldTheirDID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return c.store.Put(getRotationKeyPrefix()(oldMyDID, oldTheirDID), []byte(connectionID))
+}
+
 func marshalAndSave(k string, v interface{}, store storage.Store) error {
 	bytes, err := json.Marshal(v)
 	if err != nil {
@@ -10,8 +10,10 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -170,6 +172,41 @@ func TestConnectionStore_GetInvitation(t *testing.T) {
 	})
 }
 
+func TestConnectionStore_SaveAndGetInvitationMetadata(t *testing.T) {
+	t.Run("test save and get invitation metadata - success", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		metadata := map[string]interface{}{"user": "alice", "tags": []interface{}{"a", "b"}}
+
+		err = recorder.SaveInvitationMetadata("sample-inv-id", metadata)
+		require.NoError(t, err)
+
+		found, err := recorder.GetInvitationMetadata("sample-inv-id")
+		require.NoError(t, err)
+		require.Equal(t, metadata, found)
+	})
+
+	t.Run("test get invitation metadata - not found scenario", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		_, err = recorder.GetInvitationMetadata("sample-inv-id")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test save and get invitation metadata - invalid key scenario", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.SaveInvitationMetadata("", map[string]interface{}{"user": "alice"})
+		require.EqualError(t, err, errMsgInvalidKey)
+
+		_, err = recorder.GetInvitationMetadata("")
+		require.EqualError(t, err, errMsgInvalidKey)
+	})
+}
+
 func TestConnectionStore_SaveAndGetEventData(t *testing.T) {
 	t.Run("test save and get event data - success", func(t *testing.T) {
 		recorder, err := NewRecorder(&protocol.MockProvider{})
@@ -209,6 +246,139 @@ func TestConnectionStore_SaveAndGetEventData(t *testing.T) {
 	})
 }
 
+func TestConnectionRecorder_SaveStateTransition(t *testing.T) {
+	t.Run("save and get connection history - success", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+		transitions := []StateTransition{
+			{ToState: "null", MsgType: "invitation", Time: now},
+			{FromState: "null", ToState: stateNameInvited, MsgType: "invitation", Time: now.Add(time.Second)},
+		}
+
+		for _, transition := range transitions {
+			require.NoError(t, recorder.SaveStateTransition(sampleConnID, transition))
+		}
+
+		history, err := recorder.GetConnectionHistory(sampleConnID)
+		require.NoError(t, err)
+		require.Equal(t, transitions, history)
+
+		// connection records are not affected by the history
+		records, err := recorder.QueryConnectionRecords()
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("get connection history - not found", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		history, err := recorder.GetConnectionHistory(sampleConnID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+		require.Nil(t, history)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.SaveStateTransition("", StateTransition{})
+		require.EqualError(t, err, errMsgInvalidKey)
+
+		_, err = recorder.GetConnectionHistory("")
+		require.EqualError(t, err, errMsgInvalidKey)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		const errMsg = "get error"
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: fmt.Errorf(errMsg),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.SaveStateTransition(sampleConnID, StateTransition{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+}
+
+func TestConnectionRecorder_SaveDIDRotation(t *testing.T) {
+	t.Run("save DID rotations and get current DIDs - success", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		myDID, theirDID, err := recorder.GetCurrentDIDs("did:example:1", "did:example:2")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:1", myDID)
+		require.Equal(t, "did:example:2", theirDID)
+
+		record := &Record{ConnectionID: sampleConnID, State: stateNameCompleted,
+			MyDID: "did:example:3", TheirDID: "did:example:4"}
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		// both parties rotated their DIDs
+		require.NoError(t, recorder.SaveDIDRotation(sampleConnID, "did:example:1", "did:example:2"))
+		require.NoError(t, recorder.SaveDIDRotation(sampleConnID, "did:example:3", "did:example:2"))
+
+		for _, dids := range [][2]string{
+			{"did:example:1", "did:example:2"},
+			{"did:example:3", "did:example:2"},
+			{"did:example:3", "did:example:4"},
+		} {
+			myDID, theirDID, err = recorder.GetCurrentDIDs(dids[0], dids[1])
+			require.NoError(t, err)
+			require.Equal(t, "did:example:3", myDID)
+			require.Equal(t, "did:example:4", theirDID)
+		}
+
+		// the rotation is bound to the connection
+		myDID, theirDID, err = recorder.GetCurrentDIDs("did:example:1", "did:example:5")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:1", myDID)
+		require.Equal(t, "did:example:5", theirDID)
+	})
+
+	t.Run("rotated connection is not found", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveDIDRotation(sampleConnID, "did:example:1", "did:example:2"))
+
+		_, _, err = recorder.GetCurrentDIDs("did:example:1", "did:example:2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get rotated connection")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.EqualError(t, recorder.SaveDIDRotation("", "did:example:1", "did:example:2"), errMsgInvalidKey)
+		require.EqualError(t, recorder.SaveDIDRotation(sampleConnID, "", "did:example:2"), errMsgInvalidKey)
+		require.EqualError(t, recorder.SaveDIDRotation(sampleConnID, "did:example:1", ""), errMsgInvalidKey)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		const errMsg = "get error"
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: fmt.Errorf(errMsg),
+			}),
+		})
+		require.NoError(t, err)
+
+		_, _, err = recorder.GetCurrentDIDs("did:example:1", "did:example:2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+}
+
 func TestConnectionRecordByState(t *testing.T) {
 	recorder, err := NewRecorder(&protocol.MockProvider{})
 	require.NoError(t, err)
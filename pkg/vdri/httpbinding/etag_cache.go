@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotModifiedWithoutCache is returned when the DID resolver replies with 304 Not Modified
+// to the request which is not conditional, i.e. there is no cached DID document to use.
+var ErrNotModifiedWithoutCache = errors.New("DID resolver replied 304 Not Modified without cached DID document")
+
+// etagEntry is the DID document resolved with its ETag
+type etagEntry struct {
+	etag string
+	data []byte
+}
+
+// etagCache keeps the resolved DID documents by request URI, the oldest entries are evicted once the cache is full
+type etagCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*etagEntry
+	uris    []string
+}
+
+func newETagCache(size int) *etagCache {
+	return &etagCache{size: size, entries: make(map[string]*etagEntry)}
+}
+
+func (c *etagCache) get(uri string) (*etagEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[uri]
+
+	return entry, ok
+}
+
+func (c *etagCache) put(uri, etag string, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.entries[uri]; !ok {
+		if len(c.uris) == c.size {
+			delete(c.entries, c.uris[0])
+			c.uris = c.uris[1:]
+		}
+
+		c.uris = append(c.uris, uri)
+	}
+
+	c.entries[uri] = &etagEntry{etag: etag, data: data}
+}
+
+func (c *etagCache) remove(uri string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.entries[uri]; !ok {
+		return
+	}
+
+	delete(c.entries, uri)
+
+	for i, u := range c.uris {
+		if u == uri {
+			c.uris = append(c.uris[:i], c.uris[i+1:]...)
+			break
+		}
+	}
+}
@@ -6,25 +6,101 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
 
-// resolveDID makes DID resolution via HTTP
-func (v *VDRI) resolveDID(uri string) ([]byte, error) {
-	resp, err := v.client.Get(uri)
+// ErrDIDNotFound is returned when the DID resolver doesn't find the DID.
+var ErrDIDNotFound = errors.New("DID does not exist")
+
+// ErrDIDDeactivated is returned when the DID resolver reports that the DID is deactivated.
+var ErrDIDDeactivated = errors.New("DID is deactivated")
+
+// ErrResolverUnavailable is returned when the DID resolver can't be reached or fails to process the request
+// (see ResolverUnavailableError).
+var ErrResolverUnavailable = errors.New("DID resolver is unavailable")
+
+// ResolverUnavailableError is returned when the DID resolver can't be reached (Err is the cause)
+// or fails to process the request (StatusCode is the status of the response).
+// It is ErrResolverUnavailable for errors.Is, the cause is unwrapped.
+type ResolverUnavailableError struct {
+	// URI is the DID resolution request URI
+	URI string
+	// StatusCode is the HTTP status of the response, zero if the resolver is not reached
+	StatusCode int
+	// Err is the cause of the failed request (if any)
+	Err error
+}
+
+// Error satisfies build-in error interface
+func (e *ResolverUnavailableError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: HTTP Get request failed: %s", ErrResolverUnavailable, e.Err)
+	}
+
+	return fmt.Sprintf("%s: status [%v] for request: %s", ErrResolverUnavailable, e.StatusCode, e.URI)
+}
+
+// Is reports whether target is ErrResolverUnavailable
+func (e *ResolverUnavailableError) Is(target error) bool {
+	return target == ErrResolverUnavailable
+}
+
+// Unwrap returns the cause of the failed request
+func (e *ResolverUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// resolutionMetadata is the DID resolution metadata which may be returned by the DID resolver with the error status
+type resolutionMetadata struct {
+	Error string `json:"error,omitempty"`
+}
+
+// resolutionError is the body of the DID resolver error response
+type resolutionError struct {
+	ResolutionMetadata resolutionMetadata `json:"didResolutionMetadata,omitempty"`
+	DocumentMetadata   struct {
+		Deactivated bool `json:"deactivated,omitempty"`
+	} `json:"didDocumentMetadata,omitempty"`
+}
+
+// resolveDID makes DID resolution via HTTP. The request is conditional if the DID document is cached
+// with its ETag (see WithETagCache) unless noCache is set.
+func (v *VDRI) resolveDID(uri string, noCache bool) ([]byte, error) {
+	req, err := v.newResolveRequest(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, conditional := v.cachedDocument(uri, noCache)
+	if conditional {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := v.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+		return nil, &ResolverUnavailableError{URI: uri, Err: err}
 	}
 
 	defer closeResponseBody(resp.Body)
 
+	if resp.StatusCode == http.StatusNotModified {
+		if !conditional {
+			return nil, fmt.Errorf("%w for request: %s", ErrNotModifiedWithoutCache, uri)
+		}
+
+		return cached.data, nil
+	}
+
 	if containsDIDDocument(resp) {
 		var gotBody []byte
 
@@ -33,15 +109,100 @@ func (v *VDRI) resolveDID(uri string) ([]byte, error) {
 			return nil, fmt.Errorf("reading response body failed: %w", err)
 		}
 
+		v.cacheDocument(uri, resp.Header.Get("ETag"), gotBody)
+
 		return gotBody, nil
-	} else if notExistentDID(resp) {
-		return nil, fmt.Errorf("DID does not exist for request: %s", uri)
 	}
 
-	return nil, fmt.Errorf("unsupported response from DID resolver [%v] header [%s]",
+	return nil, responseError(resp, uri)
+}
+
+// newResolveRequest creates DID resolution request authorized with the bearer token (if any)
+func (v *VDRI) newResolveRequest(uri string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP create get request failed: %w", err)
+	}
+
+	if v.authTokenProvider != nil {
+		var token string
+
+		token, err = v.authTokenProvider()
+		if err != nil {
+			return nil, fmt.Errorf("get resolve auth token: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return req, nil
+}
+
+// cachedDocument returns the cached DID document to make the conditional request for
+func (v *VDRI) cachedDocument(uri string, noCache bool) (*etagEntry, bool) {
+	if v.etagCache == nil || noCache {
+		return nil, false
+	}
+
+	return v.etagCache.get(uri)
+}
+
+// cacheDocument caches the resolved DID document with its ETag, the document without ETag is not cached
+func (v *VDRI) cacheDocument(uri, etag string, data []byte) {
+	if v.etagCache == nil {
+		return
+	}
+
+	if etag == "" {
+		v.etagCache.remove(uri)
+
+		return
+	}
+
+	v.etagCache.put(uri, etag, data)
+}
+
+// responseError maps the unsuccessful response of the DID resolver to the error
+// using the status and the resolution metadata (if any).
+func responseError(resp *http.Response, uri string) error {
+	var body resolutionError
+
+	// the response body is optional, so the errors are ignored
+	data, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		_ = json.Unmarshal(data, &body)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusGone, body.ResolutionMetadata.Error == "deactivated",
+		body.DocumentMetadata.Deactivated:
+		return fmt.Errorf("%w for request: %s", ErrDIDDeactivated, uri)
+	case notExistentDID(resp), body.ResolutionMetadata.Error == "notFound":
+		return fmt.Errorf("%w for request: %s", ErrDIDNotFound, uri)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &ResolverUnavailableError{URI: uri, StatusCode: resp.StatusCode}
+	}
+
+	return fmt.Errorf("unsupported response from DID resolver [%v] header [%s]",
 		resp.StatusCode, resp.Header.Get("Content-type"))
 }
 
+// observedResolveDID makes DID resolution via HTTP and notifies the observer (if any) about it
+func (v *VDRI) observedResolveDID(didID, uri string, noCache bool) ([]byte, error) {
+	if v.observer == nil {
+		return v.resolveDID(uri, noCache)
+	}
+
+	v.observer.OnResolveStart(didID)
+
+	start := time.Now()
+	data, err := v.resolveDID(uri, noCache)
+
+	v.observer.OnResolveEnd(didID, time.Since(start), err)
+
+	return data, err
+}
+
 // notExistentDID checks if requested DID is not found on remote DID resolver
 func notExistentDID(resp *http.Response) bool {
 	return resp.StatusCode == http.StatusNotFound
@@ -53,15 +214,25 @@ func containsDIDDocument(resp *http.Response) bool {
 }
 
 // Read implements didresolver.DidMethod.Read interface (https://w3c-ccg.github.io/did-resolution/#resolving-input)
-func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+// The version of DID document (vdriapi.WithVersionID and vdriapi.WithVersionTime options) is requested by
+// "versionId" and "versionTime" query parameters. The cached DID document (see WithETagCache) is revalidated
+// unless vdriapi.WithNoCache option is set.
+func (v *VDRI) Read(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	resolveOpts := &vdriapi.ResolveDIDOpts{}
+
+	for _, opt := range opts {
+		opt(resolveOpts)
+	}
+
 	reqURL, err := url.ParseRequestURI(v.endpointURL)
 	if err != nil {
 		return nil, fmt.Errorf("url parse request uri failed: %w", err)
 	}
 
 	reqURL.Path = path.Join(reqURL.Path, didID)
+	reqURL.RawQuery = versionQuery(reqURL.Query(), resolveOpts).Encode()
 
-	data, err := v.resolveDID(reqURL.String())
+	data, err := v.observedResolveDID(didID, reqURL.String(), resolveOpts.NoCache)
 	if err != nil {
 		return nil, err
 	}
@@ -72,3 +243,62 @@ func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
 
 	return did.ParseDocument(data)
 }
+
+// versionOpts holds options of the DID document version
+type versionOpts struct {
+	versionID   string
+	versionTime *time.Time
+}
+
+// VersionOpt is the option of the DID document version
+type VersionOpt func(opts *versionOpts)
+
+// WithVersionID requests the DID document version with the given ID
+func WithVersionID(versionID string) VersionOpt {
+	return func(opts *versionOpts) {
+		opts.versionID = versionID
+	}
+}
+
+// WithVersionTime requests the DID document version which was current at the given time
+// (e.g. the time the credential was signed)
+func WithVersionTime(versionTime time.Time) VersionOpt {
+	return func(opts *versionOpts) {
+		opts.versionTime = &versionTime
+	}
+}
+
+// ResolveVersion resolves the given version of DID document, e.g. to verify historical proofs
+// against the DID document that was current when they were created.
+func (v *VDRI) ResolveVersion(didID string, opts ...VersionOpt) (*did.Doc, error) {
+	options := &versionOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var resolveOpts []vdriapi.ResolveOpts
+
+	if options.versionID != "" {
+		resolveOpts = append(resolveOpts, vdriapi.WithVersionID(options.versionID))
+	}
+
+	if options.versionTime != nil {
+		resolveOpts = append(resolveOpts, vdriapi.WithVersionTime(*options.versionTime))
+	}
+
+	return v.Read(didID, resolveOpts...)
+}
+
+// versionQuery adds query parameters of the requested DID document version to the query
+func versionQuery(query url.Values, opts *vdriapi.ResolveDIDOpts) url.Values {
+	if opts.VersionID != nil {
+		query.Set("versionId", fmt.Sprint(opts.VersionID))
+	}
+
+	if opts.VersionTime != "" {
+		query.Set("versionTime", opts.VersionTime)
+	}
+
+	return query
+}
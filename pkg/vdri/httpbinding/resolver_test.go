@@ -5,9 +5,18 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -53,6 +62,91 @@ func TestWithOutboundOpts(t *testing.T) {
 	require.Panics(t, func() { opt(clOpts) })
 }
 
+func TestWithClientCert(t *testing.T) {
+	clientCert, clientCACert := newClientCert(t)
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/did+ld+json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(doc))
+		require.NoError(t, err)
+	}))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCACert)
+
+	testServer.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	testServer.StartTLS()
+
+	defer func() { testServer.Close() }()
+
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(testServer.Certificate())
+
+	t.Run("test custom CA and client cert", func(t *testing.T) {
+		resolver, err := New(testServer.URL,
+			WithTLSConfig(&tls.Config{RootCAs: serverCAs}),
+			WithClientCert(clientCert))
+		require.NoError(t, err)
+
+		gotDocument, err := resolver.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+	})
+
+	t.Run("test client cert is kept when TLS config is set afterwards", func(t *testing.T) {
+		tlsConfig := &tls.Config{RootCAs: serverCAs}
+
+		resolver, err := New(testServer.URL,
+			WithClientCert(clientCert),
+			WithTLSConfig(tlsConfig))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.NoError(t, err)
+
+		// given TLS config is not modified
+		require.Empty(t, tlsConfig.Certificates)
+	})
+
+	t.Run("test without client cert", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithTLSConfig(&tls.Config{RootCAs: serverCAs}))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "HTTP Get request failed")
+	})
+}
+
+// newClientCert creates self-signed client certificate
+func newClientCert(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{certBytes}, PrivateKey: privKey, Leaf: cert}, cert
+}
+
 func TestNew(t *testing.T) {
 	t.Run("test new with no options", func(t *testing.T) {
 		var err error
@@ -90,6 +184,125 @@ func TestNew(t *testing.T) {
 	})
 }
 
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/did+ld+json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(doc))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	t.Run("test requests are sent by the given client", func(t *testing.T) {
+		requests := 0
+
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+
+			return http.DefaultTransport.RoundTrip(req)
+		})}
+
+		resolver, err := New(testServer.URL, WithHTTPClient(client))
+		require.NoError(t, err)
+
+		gotDocument, err := resolver.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+		require.Equal(t, 1, requests)
+	})
+
+	t.Run("test timeout is applied to a copy of the client", func(t *testing.T) {
+		client := &http.Client{}
+
+		resolver, err := New(testServer.URL, WithTimeout(time.Minute), WithHTTPClient(client))
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, resolver.client.Timeout)
+		require.Zero(t, client.Timeout)
+	})
+
+	t.Run("test TLS options can't be combined with the client", func(t *testing.T) {
+		_, err := New(testServer.URL, WithHTTPClient(&http.Client{}), WithTLSConfig(&tls.Config{}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "HTTP client can't be combined with TLS config or client certificate options")
+	})
+}
+
+func TestWithProxy(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/did+ld+json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(doc))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	var proxied []string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req.URL.String())
+
+		req.RequestURI = ""
+
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		require.NoError(t, err)
+
+		defer closeResponseBody(resp.Body)
+
+		res.Header().Set("Content-type", resp.Header.Get("Content-type"))
+		res.WriteHeader(resp.StatusCode)
+		_, err = io.Copy(res, resp.Body)
+		require.NoError(t, err)
+	}))
+
+	defer func() { proxy.Close() }()
+
+	t.Run("test resolution through proxy", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithProxy(proxy.URL))
+		require.NoError(t, err)
+
+		gotDocument, err := resolver.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+		require.Equal(t, []string{testServer.URL + "/did:example:334455"}, proxied)
+	})
+
+	t.Run("test proxy is combined with TLS options", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithProxy("socks5://127.0.0.1:1080"),
+			WithTLSConfig(&tls.Config{ServerName: "example.com"}), WithClientCert(tls.Certificate{}))
+		require.NoError(t, err)
+
+		transport, ok := resolver.client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, "example.com", transport.TLSClientConfig.ServerName)
+		require.Len(t, transport.TLSClientConfig.Certificates, 1)
+
+		proxyURL, err := transport.Proxy(httptest.NewRequest(http.MethodGet, testServer.URL, nil))
+		require.NoError(t, err)
+		require.Equal(t, "socks5://127.0.0.1:1080", proxyURL.String())
+	})
+
+	t.Run("test invalid proxy URL", func(t *testing.T) {
+		for _, proxyURL := range []string{"://proxy", "ftp://proxy.example.com", "http://"} {
+			_, err := New(testServer.URL, WithProxy(proxyURL))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "proxy URL invalid")
+		}
+	})
+
+	t.Run("test proxy can't be combined with the client", func(t *testing.T) {
+		_, err := New(testServer.URL, WithHTTPClient(&http.Client{}), WithProxy(proxy.URL))
+		require.EqualError(t, err, "HTTP client can't be combined with proxy option")
+	})
+}
+
 func TestRead_DIDDoc(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
 		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
@@ -129,6 +342,76 @@ func TestRead_DIDDoc(t *testing.T) {
 	})
 }
 
+func TestRead_DIDDocVersion(t *testing.T) {
+	versionTime := time.Date(2020, 4, 10, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		read     func(v *VDRI) (*did.Doc, error)
+		endpoint string
+		query    string
+	}{
+		{
+			name: "version ID",
+			read: func(v *VDRI) (*did.Doc, error) {
+				return v.ResolveVersion("did:example:334455", WithVersionID("2"))
+			},
+			query: "versionId=2",
+		},
+		{
+			name: "version time",
+			read: func(v *VDRI) (*did.Doc, error) {
+				return v.ResolveVersion("did:example:334455", WithVersionTime(versionTime))
+			},
+			query: "versionTime=2020-04-10T12%3A30%3A00Z",
+		},
+		{
+			name: "version ID and time of resolve options",
+			read: func(v *VDRI) (*did.Doc, error) {
+				return v.Read("did:example:334455", vdriapi.WithVersionID(2), vdriapi.WithVersionTime(versionTime))
+			},
+			query: "versionId=2&versionTime=2020-04-10T12%3A30%3A00Z",
+		},
+		{
+			name: "version is not defined",
+			read: func(v *VDRI) (*did.Doc, error) {
+				return v.ResolveVersion("did:example:334455")
+			},
+		},
+		{
+			name: "endpoint with query",
+			read: func(v *VDRI) (*did.Doc, error) {
+				return v.ResolveVersion("did:example:334455", WithVersionID("2"))
+			},
+			endpoint: "?network=test",
+			query:    "network=test&versionId=2",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				require.Equal(t, "/did:example:334455", req.URL.Path)
+				require.Equal(t, tc.query, req.URL.RawQuery)
+				res.Header().Add("Content-type", "application/did+ld+json")
+				res.WriteHeader(http.StatusOK)
+				_, err := res.Write([]byte(doc))
+				require.NoError(t, err)
+			}))
+
+			defer func() { testServer.Close() }()
+
+			resolver, err := New(testServer.URL + "/" + tc.endpoint)
+			require.NoError(t, err)
+
+			gotDocument, err := tc.read(resolver)
+			require.NoError(t, err)
+			require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+		})
+	}
+}
+
 func TestRead_DIDDocWithBasePath(t *testing.T) {
 	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		require.Equal(t, "/document/did:example:334455", req.URL.String())
@@ -184,6 +467,73 @@ func TestRead_DIDDocNotFound(t *testing.T) {
 	_, err = resolver.Read("did:example:334455")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "DID does not exist")
+	require.True(t, errors.Is(err, ErrDIDNotFound))
+}
+
+func TestRead_ResolutionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{name: "gone", status: http.StatusGone, err: ErrDIDDeactivated},
+		{name: "deactivated metadata", status: http.StatusBadRequest,
+			body: `{"didResolutionMetadata":{"error":"deactivated"}}`, err: ErrDIDDeactivated},
+		{name: "deactivated document metadata", status: http.StatusBadRequest,
+			body: `{"didDocumentMetadata":{"deactivated":true}}`, err: ErrDIDDeactivated},
+		{name: "not found metadata", status: http.StatusBadRequest,
+			body: `{"didResolutionMetadata":{"error":"notFound"}}`, err: ErrDIDNotFound},
+		{name: "internal server error", status: http.StatusInternalServerError, err: ErrResolverUnavailable},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, body: "unavailable",
+			err: ErrResolverUnavailable},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(tc.status)
+				_, err := res.Write([]byte(tc.body))
+				require.NoError(t, err)
+			}))
+
+			defer func() { testServer.Close() }()
+
+			resolver, err := New(testServer.URL)
+			require.NoError(t, err)
+			_, err = resolver.Read("did:example:334455")
+			require.Error(t, err)
+			require.True(t, errors.Is(err, tc.err), err.Error())
+
+			var unavailableErr *ResolverUnavailableError
+			if errors.As(err, &unavailableErr) {
+				require.Equal(t, tc.status, unavailableErr.StatusCode)
+				require.Equal(t, testServer.URL+"/did:example:334455", unavailableErr.URI)
+				require.Nil(t, unavailableErr.Unwrap())
+			}
+		})
+	}
+
+	t.Run("resolver is not reachable", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+		testServer.Close()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		_, err = resolver.Read("did:example:334455")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrResolverUnavailable))
+		require.Contains(t, err.Error(), "HTTP Get request failed")
+
+		// the cause of the failed request is kept
+		var unavailableErr *ResolverUnavailableError
+		require.True(t, errors.As(err, &unavailableErr))
+		require.Zero(t, unavailableErr.StatusCode)
+
+		var urlErr *url.Error
+		require.True(t, errors.As(err, &urlErr))
+	})
 }
 
 func TestRead_UnsupportedStatus(t *testing.T) {
@@ -215,6 +565,233 @@ func TestRead_HTTPGetFailed(t *testing.T) {
 	require.Contains(t, err.Error(), "HTTP Get request failed")
 }
 
+func TestRead_WithETagCache(t *testing.T) {
+	const etag = `"v1"`
+
+	newServer := func(t *testing.T, requests *int, etagHeader string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			*requests++
+
+			if etagHeader != "" && req.Header.Get("If-None-Match") == etagHeader {
+				res.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			res.Header().Add("Content-type", "application/did+ld+json")
+
+			if etagHeader != "" {
+				res.Header().Add("ETag", etagHeader)
+			}
+
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(doc))
+			require.NoError(t, err)
+		}))
+	}
+
+	t.Run("test cached document is used if not modified", func(t *testing.T) {
+		var requests int
+
+		testServer := newServer(t, &requests, etag)
+		defer testServer.Close()
+
+		resolver, err := New(testServer.URL, WithETagCache(0))
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			gotDocument, e := resolver.Read("did:example:334455")
+			require.NoError(t, e)
+			require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+		}
+
+		require.Equal(t, 2, requests)
+
+		entry, ok := resolver.etagCache.get(testServer.URL + "/did:example:334455")
+		require.True(t, ok)
+		require.Equal(t, etag, entry.etag)
+
+		// the request is not conditional
+		_, err = resolver.Read("did:example:334455", vdriapi.WithNoCache(true))
+		require.NoError(t, err)
+	})
+
+	t.Run("test document without ETag is not cached", func(t *testing.T) {
+		var requests int
+
+		testServer := newServer(t, &requests, "")
+		defer testServer.Close()
+
+		resolver, err := New(testServer.URL, WithETagCache(1))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.NoError(t, err)
+
+		_, ok := resolver.etagCache.get(testServer.URL + "/did:example:334455")
+		require.False(t, ok)
+	})
+
+	t.Run("test not modified without cached document", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.WriteHeader(http.StatusNotModified)
+		}))
+		defer testServer.Close()
+
+		resolver, err := New(testServer.URL, WithETagCache(1))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.True(t, errors.Is(err, ErrNotModifiedWithoutCache))
+	})
+}
+
+func TestETagCache(t *testing.T) {
+	cache := newETagCache(2)
+
+	cache.put("a", "1", []byte("a1"))
+	cache.put("b", "1", []byte("b1"))
+	cache.put("a", "2", []byte("a2"))
+
+	entry, ok := cache.get("a")
+	require.True(t, ok)
+	require.Equal(t, &etagEntry{etag: "2", data: []byte("a2")}, entry)
+
+	// the oldest entry is evicted
+	cache.put("c", "1", []byte("c1"))
+
+	_, ok = cache.get("a")
+	require.False(t, ok)
+
+	cache.remove("b")
+	cache.remove("unknown")
+
+	_, ok = cache.get("b")
+	require.False(t, ok)
+
+	_, ok = cache.get("c")
+	require.True(t, ok)
+	require.Equal(t, []string{"c"}, cache.uris)
+}
+
+func TestRead_WithObserver(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add("Content-type", "application/did+ld+json")
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(doc))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		obs := &mockObserver{}
+
+		resolver, err := New(testServer.URL, WithObserver(obs))
+		require.NoError(t, err)
+		_, err = resolver.Read("did:example:334455")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"did:example:334455"}, obs.started)
+		require.Equal(t, []string{"did:example:334455"}, obs.ended)
+		require.Len(t, obs.errs, 1)
+		require.NoError(t, obs.errs[0])
+		require.True(t, obs.durations[0] >= 0)
+	})
+	t.Run("test resolve error", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.WriteHeader(http.StatusNotFound)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		obs := &mockObserver{}
+
+		resolver, err := New(testServer.URL, WithObserver(obs))
+		require.NoError(t, err)
+		_, err = resolver.Read("did:example:334455")
+		require.Error(t, err)
+
+		require.Equal(t, []string{"did:example:334455"}, obs.started)
+		require.Equal(t, []string{"did:example:334455"}, obs.ended)
+		require.Len(t, obs.errs, 1)
+		require.Equal(t, err, obs.errs[0])
+	})
+}
+
+func TestRead_WithAuthTokenProvider(t *testing.T) {
+	t.Run("test fresh token is sent with each request", func(t *testing.T) {
+		var authHeaders []string
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			authHeaders = append(authHeaders, req.Header.Get("Authorization"))
+
+			res.Header().Add("Content-type", "application/did+ld+json")
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(doc))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		tokens := []string{"token1", "token2"}
+
+		resolver, err := New(testServer.URL, WithAuthTokenProvider(func() (string, error) {
+			token := tokens[0]
+			tokens = tokens[1:]
+
+			return token, nil
+		}))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"Bearer token1", "Bearer token2"}, authHeaders)
+	})
+
+	t.Run("test token provider error", func(t *testing.T) {
+		requested := false
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			requested = true
+
+			res.WriteHeader(http.StatusOK)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL, WithAuthTokenProvider(func() (string, error) {
+			return "", errors.New("token expired")
+		}))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get resolve auth token: token expired")
+		require.False(t, requested)
+	})
+}
+
+type mockObserver struct {
+	started   []string
+	ended     []string
+	durations []time.Duration
+	errs      []error
+}
+
+func (o *mockObserver) OnResolveStart(didID string) {
+	o.started = append(o.started, didID)
+}
+
+func (o *mockObserver) OnResolveEnd(didID string, d time.Duration, err error) {
+	o.ended = append(o.ended, didID)
+	o.durations = append(o.durations, d)
+	o.errs = append(o.errs, err)
+}
+
 func TestDIDResolver_Accept(t *testing.T) {
 	resolver, err := New("localhost:8080")
 	require.NoError(t, err)
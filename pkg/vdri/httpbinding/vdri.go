@@ -9,6 +9,7 @@ package httpbinding
 import (
 	"bytes"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -29,6 +30,8 @@ const (
 	pubKeyIndex1      = "#key-1"
 	pubKeyController  = "controller"
 	svcEndpointIndex1 = "#endpoint-1"
+
+	defaultETagCacheSize = 100
 )
 
 // VDRI via HTTP(s) endpoint
@@ -36,11 +39,31 @@ type VDRI struct {
 	endpointURL string
 	client      *http.Client
 	accept      Accept
+	observer    Observer
+
+	authTokenProvider AuthTokenProvider
+	etagCache         *etagCache
+
+	// HTTP client set by WithHTTPClient
+	customClient *http.Client
+	// proxy URL set by WithProxy
+	proxyURL string
 }
 
 // Accept is method to accept did method
 type Accept func(method string) bool
 
+// AuthTokenProvider returns the bearer token to authorize a DID resolution request with
+type AuthTokenProvider func() (string, error)
+
+// Observer is notified around each DID resolution HTTP call (e.g. to collect latency and error metrics)
+type Observer interface {
+	// OnResolveStart is called before the resolution request is sent
+	OnResolveStart(did string)
+	// OnResolveEnd is called once the resolution request is completed with its duration and error (if any)
+	OnResolveEnd(did string, d time.Duration, err error)
+}
+
 // New creates new DID Resolver
 func New(endpointURL string, opts ...Option) (*VDRI, error) {
 	vdri := &VDRI{client: &http.Client{}, accept: func(method string) bool { return true }}
@@ -55,11 +78,62 @@ func New(endpointURL string, opts ...Option) (*VDRI, error) {
 		return nil, fmt.Errorf("base URL invalid: %w", err)
 	}
 
+	if vdri.proxyURL != "" {
+		if vdri.customClient != nil {
+			return nil, errors.New("HTTP client can't be combined with proxy option")
+		}
+
+		if err = vdri.configureProxy(); err != nil {
+			return nil, err
+		}
+	}
+
+	if vdri.customClient != nil {
+		if vdri.client.Transport != nil {
+			return nil, errors.New("HTTP client can't be combined with TLS config or client certificate options")
+		}
+
+		client := *vdri.customClient
+		if vdri.client.Timeout != 0 {
+			client.Timeout = vdri.client.Timeout
+		}
+
+		vdri.client = &client
+	}
+
 	vdri.endpointURL = endpointURL
 
 	return vdri, nil
 }
 
+// configureProxy sets the proxy of the transport, TLS config and client certificates are kept
+func (v *VDRI) configureProxy() error {
+	proxyURL, err := url.Parse(v.proxyURL)
+	if err != nil {
+		return fmt.Errorf("proxy URL invalid: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("proxy URL invalid: unsupported scheme '%s'", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return errors.New("proxy URL invalid: host is not defined")
+	}
+
+	t, ok := v.client.Transport.(*http.Transport)
+	if !ok {
+		t = &http.Transport{}
+		v.client.Transport = t
+	}
+
+	t.Proxy = http.ProxyURL(proxyURL)
+
+	return nil
+}
+
 // Accept did method - attempt to resolve any method
 func (v *VDRI) Accept(method string) bool {
 	return v.accept(method)
@@ -178,6 +252,16 @@ func (v *VDRI) Close() error {
 // Option configures the peer vdri
 type Option func(opts *VDRI)
 
+// WithHTTPClient option is for definition of HTTP client used by DID Resolver instead of the internal one
+// (e.g. to share a connection pool or to use a custom round tripper). WithTimeout is applied to a copy
+// of the client, so the given client is not modified. WithTLSConfig and WithClientCert can't be combined with it
+// (New returns an error), the transport of the given client should be configured instead.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *VDRI) {
+		opts.customClient = client
+	}
+}
+
 // WithTimeout option is for definition of HTTP(s) timeout value of DID Resolver
 func WithTimeout(timeout time.Duration) Option {
 	return func(opts *VDRI) {
@@ -185,15 +269,60 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// WithTLSConfig option is for definition of secured HTTP transport using a tls.Config instance
+// WithTLSConfig option is for definition of secured HTTP transport using a tls.Config instance.
+// Client certificates set by WithClientCert are kept. It can't be combined with WithHTTPClient.
 func WithTLSConfig(tlsConfig *tls.Config) Option {
 	return func(opts *VDRI) {
+		cfg := tlsConfig
+
+		if t, ok := opts.client.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+			if certs := t.TLSClientConfig.Certificates; len(certs) > 0 {
+				cfg = withCertificates(cfg, certs...)
+			}
+		}
+
 		opts.client.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
+			TLSClientConfig: cfg,
 		}
 	}
 }
 
+// WithClientCert option is for definition of a client certificate used for mutual TLS authentication.
+// It can be combined with WithTLSConfig in any order, but not with WithHTTPClient.
+func WithClientCert(cert tls.Certificate) Option {
+	return func(opts *VDRI) {
+		t, ok := opts.client.Transport.(*http.Transport)
+		if !ok {
+			t = &http.Transport{}
+			opts.client.Transport = t
+		}
+
+		t.TLSClientConfig = withCertificates(t.TLSClientConfig, cert)
+	}
+}
+
+// withCertificates returns a copy of the given TLS config with the certificates appended
+func withCertificates(cfg *tls.Config, certs ...tls.Certificate) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+
+	cfg.Certificates = append(cfg.Certificates, certs...)
+
+	return cfg
+}
+
+// WithProxy option is for routing DID resolution requests through the proxy (e.g. "http://proxy.example.com:3128"
+// or "socks5://127.0.0.1:1080"). It can be combined with WithTLSConfig and WithClientCert, but not with
+// WithHTTPClient. New returns an error if the proxy URL is invalid.
+func WithProxy(proxyURL string) Option {
+	return func(opts *VDRI) {
+		opts.proxyURL = proxyURL
+	}
+}
+
 // WithAccept option is for accept did method
 func WithAccept(accept Accept) Option {
 	return func(opts *VDRI) {
@@ -201,6 +330,35 @@ func WithAccept(accept Accept) Option {
 	}
 }
 
+// WithObserver option is for notifying the given observer about DID resolution calls
+func WithObserver(obs Observer) Option {
+	return func(opts *VDRI) {
+		opts.observer = obs
+	}
+}
+
+// WithAuthTokenProvider option is for authorizing DID resolution requests with a bearer token.
+// The provider is invoked before each request, so that short-lived tokens can be rotated without
+// recreating the VDRI. The resolution fails without sending the request if the provider returns an error.
+func WithAuthTokenProvider(provider AuthTokenProvider) Option {
+	return func(opts *VDRI) {
+		opts.authTokenProvider = provider
+	}
+}
+
+// WithETagCache option is for caching up to size resolved DID documents (100 if size is not positive) with
+// their ETags. The DID document is requested with If-None-Match header then and the cached one is used
+// if the DID resolver replies with 304 Not Modified. The documents without ETag are not cached.
+func WithETagCache(size int) Option {
+	return func(opts *VDRI) {
+		if size <= 0 {
+			size = defaultETagCacheSize
+		}
+
+		opts.etagCache = newETagCache(size)
+	}
+}
+
 func closeResponseBody(respBody io.Closer) {
 	e := respBody.Close()
 	if e != nil {
@@ -16,7 +16,9 @@ import (
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
 
-// Build builds new DID Document
+// Build builds new DID Document. By default, the document of did:peer:2 DID is built (pubKey is the authentication
+// key, see vdriapi.WithKeyAgreementKeys for the key agreement keys). If the VDRI is created with WithNumalgo1 option,
+// the genesis version of did:peer:1 DID document is built.
 func (v *VDRI) Build(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (*did.Doc, error) {
 	docOpts := &vdriapi.CreateDIDOpts{}
 	// Apply options
@@ -24,7 +26,12 @@ func (v *VDRI) Build(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (*did.Doc,
 		opt(docOpts)
 	}
 
-	didDoc, err := build(pubKey, docOpts)
+	buildDoc := buildNumalgo2
+	if v.numalgo1 {
+		buildDoc = build
+	}
+
+	didDoc, err := buildDoc(pubKey, docOpts)
 	if err != nil {
 		return nil, fmt.Errorf("create peer DID : %w", err)
 	}
@@ -9,6 +9,7 @@ package peer
 import (
 	"crypto/ed25519"
 	"crypto/rand"
+	"strings"
 	"testing"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -18,10 +19,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 )
 
-const (
-	keyType = "key-type"
-)
-
 func TestDIDCreator(t *testing.T) {
 	t.Run("test create without service type", func(t *testing.T) {
 		c, err := New(&storage.MockStoreProvider{})
@@ -34,6 +31,21 @@ func TestDIDCreator(t *testing.T) {
 
 		// verify empty services
 		require.Empty(t, didDoc.Service)
+		require.True(t, strings.HasPrefix(didDoc.ID, "did:peer:2."))
+	})
+
+	t.Run("test create with numalgo 1 option", func(t *testing.T) {
+		c, err := New(&storage.MockStoreProvider{}, WithNumalgo1())
+		require.NoError(t, err)
+		require.NotNil(t, c)
+
+		didDoc, err := c.Build(getSigningKey(), api.WithServiceType(api.DIDCommServiceType))
+		require.NoError(t, err)
+		require.NotNil(t, didDoc)
+		require.NoError(t, validateDID(didDoc))
+
+		require.Len(t, didDoc.Service, 1)
+		require.Equal(t, []string{didDoc.PublicKey[0].ID}, didDoc.Service[0].RecipientKeys)
 	})
 
 	t.Run("test request overrides", func(t *testing.T) {
@@ -77,5 +89,5 @@ func getSigningKey() *api.PubKey {
 		panic(err)
 	}
 
-	return &api.PubKey{Value: base58.Encode(pub[:]), Type: keyType}
+	return &api.PubKey{Value: base58.Encode(pub[:]), Type: ed25519KeyType}
 }
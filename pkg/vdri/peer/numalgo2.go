@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	multibase "github.com/multiformats/go-multibase"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const (
+	// Reference: https://identity.foundation/peer-did-method-spec/#generation-method
+	numAlgo2 = "2"
+
+	numAlgo2Prefix = peerPrefix + numAlgo2
+
+	// purpose codes of the elements of did:peer:2 DID
+	purposeKeyAgreement   = 'E'
+	purposeAuthentication = 'V'
+	purposeService        = 'S'
+
+	// types of the public keys of did:peer:2 DID document
+	ed25519KeyType = "Ed25519VerificationKey2018"
+	x25519KeyType  = "X25519KeyAgreementKey2019"
+
+	// service type abbreviation of did:peer:2 DID
+	didCommMessagingType     = "DIDCommMessaging"
+	didCommMessagingTypeAbbr = "dm"
+
+	publicKeySize = 32
+)
+
+// multicodec prefixes of the public keys (varint encoded)
+// nolint:gochecknoglobals
+var (
+	ed25519Codec = []byte{0xed, 0x01}
+	x25519Codec  = []byte{0xec, 0x01}
+)
+
+// numalgo2Keys are the public keys encoded into did:peer:2 DID
+type numalgo2Keys struct {
+	// authentication are raw Ed25519 public keys
+	authentication [][]byte
+	// keyAgreement are raw X25519 public keys
+	keyAgreement [][]byte
+}
+
+// numalgo2Service is the abbreviated service encoded into did:peer:2 DID
+type numalgo2Service struct {
+	Type            string   `json:"t"`
+	ServiceEndpoint string   `json:"s"`
+	RoutingKeys     []string `json:"r,omitempty"`
+}
+
+// buildNumalgo2 builds the DID document of a new did:peer:2 DID. The DID encodes the authentication key,
+// the key agreement keys and the service (to be included only if service type is provided through opts),
+// so the document can be resolved from the DID itself without a ledger (see Read).
+//
+// The key agreement keys are defined as public keys of X25519KeyAgreementKey2019 type. The recipient keys
+// of DID Communication service are the authentication keys (referenced by the IDs of the public keys).
+func buildNumalgo2(pubKey *vdriapi.PubKey, docOpts *vdriapi.CreateDIDOpts) (*did.Doc, error) {
+	if pubKey.Type != "" && pubKey.Type != ed25519KeyType {
+		return nil, fmt.Errorf("unsupported key type %s", pubKey.Type)
+	}
+
+	keys := numalgo2Keys{authentication: [][]byte{base58.Decode(pubKey.Value)}}
+
+	for _, key := range docOpts.KeyAgreementKeys {
+		keys.keyAgreement = append(keys.keyAgreement, base58.Decode(key))
+	}
+
+	didID, err := computeNumalgo2DID(keys, docOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveNumalgo2(didID)
+}
+
+func computeNumalgo2DID(keys numalgo2Keys, docOpts *vdriapi.CreateDIDOpts) (string, error) {
+	didID := numAlgo2Prefix
+
+	for _, key := range keys.keyAgreement {
+		element, err := encodeNumalgo2Key(purposeKeyAgreement, x25519Codec, key)
+		if err != nil {
+			return "", err
+		}
+
+		didID += element
+	}
+
+	for _, key := range keys.authentication {
+		element, err := encodeNumalgo2Key(purposeAuthentication, ed25519Codec, key)
+		if err != nil {
+			return "", err
+		}
+
+		didID += element
+	}
+
+	if docOpts.ServiceType != "" {
+		serviceType := docOpts.ServiceType
+		if serviceType == didCommMessagingType {
+			serviceType = didCommMessagingTypeAbbr
+		}
+
+		service, err := json.Marshal(numalgo2Service{
+			Type:            serviceType,
+			ServiceEndpoint: docOpts.ServiceEndpoint,
+			RoutingKeys:     docOpts.RoutingKeys,
+		})
+		if err != nil {
+			return "", fmt.Errorf("encode service: %w", err)
+		}
+
+		didID += "." + string(purposeService) + base64.RawURLEncoding.EncodeToString(service)
+	}
+
+	return didID, nil
+}
+
+func encodeNumalgo2Key(purpose byte, codec, key []byte) (string, error) {
+	if len(key) != publicKeySize {
+		return "", fmt.Errorf("invalid public key size %d", len(key))
+	}
+
+	encoded, err := multibase.Encode(transform, append(append([]byte(nil), codec...), key...))
+	if err != nil {
+		return "", fmt.Errorf("encode public key: %w", err)
+	}
+
+	return "." + string(purpose) + encoded, nil
+}
+
+// isNumalgo2 checks if the DID is did:peer:2 DID
+func isNumalgo2(didID string) bool {
+	return strings.HasPrefix(didID, numAlgo2Prefix+".")
+}
+
+// resolveNumalgo2 resolves the DID document from did:peer:2 DID.
+func resolveNumalgo2(didID string) (*did.Doc, error) {
+	if !isNumalgo2(didID) {
+		return nil, fmt.Errorf("resolve %s: not a did:peer:2 DID", didID)
+	}
+
+	var (
+		publicKeys     []did.PublicKey
+		authentication []did.VerificationMethod
+		services       []numalgo2Service
+	)
+
+	for _, element := range strings.Split(strings.TrimPrefix(didID, numAlgo2Prefix+"."), ".") {
+		if element == "" {
+			return nil, fmt.Errorf("resolve %s: empty element", didID)
+		}
+
+		switch element[0] {
+		case purposeKeyAgreement, purposeAuthentication:
+			pk, err := decodeNumalgo2Key(didID, element, len(publicKeys)+1)
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", didID, err)
+			}
+
+			publicKeys = append(publicKeys, pk)
+
+			if element[0] == purposeAuthentication {
+				authentication = append(authentication, did.VerificationMethod{PublicKey: pk})
+			}
+		case purposeService:
+			service, err := decodeNumalgo2Service(element[1:])
+			if err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", didID, err)
+			}
+
+			services = append(services, service)
+		default:
+			return nil, fmt.Errorf("resolve %s: unsupported purpose code '%c'", didID, element[0])
+		}
+	}
+
+	doc := did.BuildDoc(
+		did.WithPublicKey(publicKeys),
+		did.WithAuthentication(authentication),
+		did.WithService(numalgo2Services(services, authentication)),
+	)
+	doc.ID = didID
+
+	return doc, nil
+}
+
+// checkNumalgo2Doc checks that the document is the one encoded into its did:peer:2 DID.
+func checkNumalgo2Doc(doc *did.Doc) error {
+	resolved, err := resolveNumalgo2(doc.ID)
+	if err != nil {
+		return err
+	}
+
+	resolvedBytes, err := resolved.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("JSON marshalling of resolved document failed: %w", err)
+	}
+
+	docBytes, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("JSON marshalling of document failed: %w", err)
+	}
+
+	if !bytes.Equal(resolvedBytes, docBytes) {
+		return fmt.Errorf("document of %s does not match the document encoded into the DID", doc.ID)
+	}
+
+	return nil
+}
+
+func decodeNumalgo2Key(didID, element string, index int) (did.PublicKey, error) {
+	encoding, value, err := multibase.Decode(element[1:])
+	if err != nil {
+		return did.PublicKey{}, fmt.Errorf("decode public key: %w", err)
+	}
+
+	if encoding != transform {
+		return did.PublicKey{}, errors.New("public key is not base58 encoded")
+	}
+
+	keyType, codec := ed25519KeyType, ed25519Codec
+	if element[0] == purposeKeyAgreement {
+		keyType, codec = x25519KeyType, x25519Codec
+	}
+
+	if !bytes.HasPrefix(value, codec) || len(value) != len(codec)+publicKeySize {
+		return did.PublicKey{}, fmt.Errorf("invalid %s public key", keyType)
+	}
+
+	return did.PublicKey{
+		ID:         fmt.Sprintf("#key-%d", index),
+		Type:       keyType,
+		Controller: didID,
+		Value:      value[len(codec):],
+	}, nil
+}
+
+func decodeNumalgo2Service(value string) (numalgo2Service, error) {
+	var service numalgo2Service
+
+	serviceBytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return service, fmt.Errorf("decode service: %w", err)
+	}
+
+	if err = json.Unmarshal(serviceBytes, &service); err != nil {
+		return service, fmt.Errorf("unmarshal service: %w", err)
+	}
+
+	if service.Type == didCommMessagingTypeAbbr {
+		service.Type = didCommMessagingType
+	}
+
+	return service, nil
+}
+
+func numalgo2Services(services []numalgo2Service, authentication []did.VerificationMethod) []did.Service {
+	var result []did.Service
+
+	for i, s := range services {
+		service := did.Service{
+			ID:              "#service",
+			Type:            s.Type,
+			ServiceEndpoint: s.ServiceEndpoint,
+			RoutingKeys:     s.RoutingKeys,
+		}
+
+		if i > 0 {
+			service.ID = fmt.Sprintf("#service-%d", i)
+		}
+
+		if s.Type == vdriapi.DIDCommServiceType {
+			for _, vm := range authentication {
+				service.RecipientKeys = append(service.RecipientKeys, vm.PublicKey.ID)
+			}
+		}
+
+		result = append(result, service)
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestVDRI_BuildNumalgo2(t *testing.T) {
+	authKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	agreementKey := make([]byte, publicKeySize)
+	_, err = rand.Read(agreementKey)
+	require.NoError(t, err)
+
+	pubKey := &vdriapi.PubKey{Value: base58.Encode(authKey), Type: ed25519KeyType}
+	withAgreementKey := vdriapi.WithKeyAgreementKeys([]string{base58.Encode(agreementKey)})
+
+	t.Run("build, store and resolve", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		doc, err := v.Build(pubKey, withAgreementKey,
+			vdriapi.WithServiceType(vdriapi.DIDCommServiceType),
+			vdriapi.WithServiceEndpoint("http://agent.example.com"),
+			vdriapi.WithRoutingKeys([]string{"routingKey"}))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(doc.ID, "did:peer:2.Ez"))
+		require.Contains(t, doc.ID, ".Vz")
+		require.Contains(t, doc.ID, ".S")
+
+		require.Len(t, doc.PublicKey, 2)
+		require.Equal(t, x25519KeyType, doc.PublicKey[0].Type)
+		require.Equal(t, agreementKey, doc.PublicKey[0].Value)
+		require.Equal(t, ed25519KeyType, doc.PublicKey[1].Type)
+		require.Equal(t, []byte(authKey), doc.PublicKey[1].Value)
+		require.Equal(t, doc.ID, doc.PublicKey[1].Controller)
+
+		require.Len(t, doc.Authentication, 1)
+		require.Equal(t, doc.PublicKey[1].ID, doc.Authentication[0].PublicKey.ID)
+
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, vdriapi.DIDCommServiceType, doc.Service[0].Type)
+		require.Equal(t, "http://agent.example.com", doc.Service[0].ServiceEndpoint)
+		require.Equal(t, []string{"routingKey"}, doc.Service[0].RoutingKeys)
+		require.Equal(t, []string{doc.PublicKey[1].ID}, doc.Service[0].RecipientKeys)
+
+		recipientKeys, ok := did.LookupRecipientKeys(doc, vdriapi.DIDCommServiceType, ed25519KeyType)
+		require.True(t, ok)
+		require.Equal(t, []string{base58.Encode(authKey)}, recipientKeys)
+
+		require.NoError(t, v.Store(doc, nil))
+
+		stored, err := v.Read(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, doc.ID, stored.ID)
+		require.Equal(t, doc.PublicKey, stored.PublicKey)
+		require.Len(t, stored.Service, 1)
+		require.Equal(t, doc.Service[0].RecipientKeys, stored.Service[0].RecipientKeys)
+		require.Equal(t, doc.Service[0].ServiceEndpoint, stored.Service[0].ServiceEndpoint)
+	})
+
+	t.Run("resolve without store", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		doc, err := v.Build(pubKey, withAgreementKey)
+		require.NoError(t, err)
+		require.Empty(t, doc.Service)
+
+		resolved, err := v.Read(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, doc, resolved)
+	})
+
+	t.Run("document is always decoded from DID", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		doc, err := v.Build(pubKey, withAgreementKey)
+		require.NoError(t, err)
+
+		// the received document is stored as is, it is parsed from JSON
+		docBytes, err := doc.JSONBytes()
+		require.NoError(t, err)
+
+		received, err := did.ParseDocument(docBytes)
+		require.NoError(t, err)
+		require.NoError(t, v.Store(received, nil))
+
+		// the document which does not match the DID is rejected
+		tampered, err := did.ParseDocument(docBytes)
+		require.NoError(t, err)
+
+		tampered.PublicKey[1].Value = agreementKey
+
+		err = v.Store(tampered, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the document encoded into the DID")
+
+		tampered.ID = doc.ID + ".Vinvalid"
+
+		err = v.Store(tampered, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve "+tampered.ID)
+
+		// the document can't be replaced through the store
+		require.NoError(t, v.store.Put(doc.ID, []byte("invalid")))
+
+		resolved, err := v.Read(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, doc, resolved)
+	})
+
+	t.Run("service type abbreviation", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		doc, err := v.Build(pubKey, withAgreementKey, vdriapi.WithServiceType(didCommMessagingType))
+		require.NoError(t, err)
+		require.Equal(t, didCommMessagingType, doc.Service[0].Type)
+		require.Empty(t, doc.Service[0].RecipientKeys)
+
+		service := doc.ID[strings.Index(doc.ID, ".S")+2:]
+		serviceJSON, err := base64.RawURLEncoding.DecodeString(service)
+		require.NoError(t, err)
+		require.Contains(t, string(serviceJSON), `"t":"dm"`)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		_, err = v.Build(&vdriapi.PubKey{Value: base58.Encode(authKey), Type: "key-type"})
+		require.EqualError(t, err, "create peer DID : unsupported key type key-type")
+
+		_, err = v.Build(&vdriapi.PubKey{})
+		require.EqualError(t, err, "create peer DID : invalid public key size 0")
+
+		_, err = v.Build(&vdriapi.PubKey{Value: base58.Encode([]byte("short"))})
+		require.EqualError(t, err, "create peer DID : invalid public key size 5")
+
+		_, err = v.Build(pubKey, vdriapi.WithKeyAgreementKeys([]string{base58.Encode([]byte("short"))}))
+		require.EqualError(t, err, "create peer DID : invalid public key size 5")
+	})
+}
+
+func TestResolveNumalgo2(t *testing.T) {
+	t.Run("not a did:peer:2 DID", func(t *testing.T) {
+		_, err := resolveNumalgo2(peerDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a did:peer:2 DID")
+
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		_, err = v.Read(peerDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetching data from store failed")
+	})
+
+	t.Run("invalid elements", func(t *testing.T) {
+		for _, test := range []struct {
+			didID string
+			err   string
+		}{
+			{didID: "did:peer:2..Vz", err: "empty element"},
+			{didID: "did:peer:2.Xz123", err: "unsupported purpose code 'X'"},
+			{didID: "did:peer:2.V!", err: "decode public key"},
+			{didID: "did:peer:2.Vf0102", err: "public key is not base58 encoded"},
+			{didID: "did:peer:2.Vz" + base58.Encode(append(x25519Codec, make([]byte, publicKeySize)...)),
+				err: "invalid Ed25519VerificationKey2018 public key"},
+			{didID: "did:peer:2.Ez" + base58.Encode(ed25519Codec), err: "invalid X25519KeyAgreementKey2019 public key"},
+			{didID: "did:peer:2.S!", err: "decode service"},
+			{didID: "did:peer:2.S" + base64.RawURLEncoding.EncodeToString([]byte("{")), err: "unmarshal service"},
+		} {
+			_, err := resolveNumalgo2(test.didID)
+			require.Error(t, err, test.didID)
+			require.Contains(t, err.Error(), test.err, test.didID)
+		}
+	})
+}
@@ -15,6 +15,11 @@ import (
 
 // Read implements didresolver.DidMethod.Read interface (https://w3c-ccg.github.io/did-resolution/#resolving-input)
 func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	// did:peer:2 DID encodes its document, so it is always decoded from the DID (never taken from the store)
+	if isNumalgo2(didID) {
+		return resolveNumalgo2(didID)
+	}
+
 	// get the document from the store
 	doc, err := v.Get(didID)
 	if err != nil {
@@ -29,6 +29,12 @@ func (v *VDRI) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
 		return errors.New("DID and document are mandatory")
 	}
 
+	if isNumalgo2(doc.ID) {
+		if err := checkNumalgo2Doc(doc); err != nil {
+			return err
+		}
+	}
+
 	var deltas []docDelta
 
 	// For now, assume the doc is a genesis document
@@ -19,17 +19,35 @@ const (
 
 // VDRI implements building new peer dids
 type VDRI struct {
-	store storage.Store
+	store    storage.Store
+	numalgo1 bool
 }
 
+// Option configures the peer vdri
+type Option func(opts *VDRI)
+
 // New return new instance of peer vdri
-func New(s storage.Provider) (*VDRI, error) {
+func New(s storage.Provider, opts ...Option) (*VDRI, error) {
 	didDBStore, err := s.OpenStore(StoreNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("open store : %w", err)
 	}
 
-	return &VDRI{store: didDBStore}, nil
+	v := &VDRI{store: didDBStore}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v, nil
+}
+
+// WithNumalgo1 option makes Build create did:peer:1 DIDs (computed from the genesis version of the document)
+// instead of did:peer:2 DIDs.
+func WithNumalgo1() Option {
+	return func(opts *VDRI) {
+		opts.numalgo1 = true
+	}
 }
 
 // Accept did method
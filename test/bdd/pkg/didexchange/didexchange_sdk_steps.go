@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/httpbinding"
 	"github.com/hyperledger/aries-framework-go/test/bdd/pkg/context"
 )
 
@@ -383,7 +384,7 @@ func resolveDID(vdriRegistry vdriapi.Registry, did string, maxRetry int) (*diddo
 	var err error
 	for i := 1; i <= maxRetry; i++ {
 		doc, err = vdriRegistry.Resolve(did)
-		if err == nil || !strings.Contains(err.Error(), "DID does not exist") {
+		if err == nil || !errors.Is(err, httpbinding.ErrDIDNotFound) {
 			return doc, err
 		}
 
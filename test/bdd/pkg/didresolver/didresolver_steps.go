@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -26,6 +27,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/httpbinding"
 	"github.com/hyperledger/aries-framework-go/test/bdd/dockerutil"
 	bddctx "github.com/hyperledger/aries-framework-go/test/bdd/pkg/context"
 )
@@ -220,7 +222,7 @@ func resolveDID(vdriRegistry vdriapi.Registry, did string, maxRetry int) (*diddo
 	var err error
 	for i := 1; i <= maxRetry; i++ {
 		doc, err = vdriRegistry.Resolve(did)
-		if err == nil || !strings.Contains(err.Error(), "DID does not exist") {
+		if err == nil || !errors.Is(err, httpbinding.ErrDIDNotFound) {
 			return doc, err
 		}
 